@@ -269,6 +269,8 @@ func NewDescriptorResolver(descs []catalog.Descriptor) (*DescriptorResolver, err
 			typeToRegister = "table"
 		case catalog.TypeDescriptor:
 			typeToRegister = "type"
+		case catalog.FunctionDescriptor:
+			typeToRegister = "function"
 		}
 		if typeToRegister != "" {
 			if err := registerDesc(desc.GetParentID(), desc, typeToRegister); err != nil {
@@ -551,6 +553,18 @@ func DescriptorsMatchingTargets(
 				}
 			case catalog.TypeDescriptor:
 				maybeAddTypeDesc(desc.GetID())
+			case catalog.FunctionDescriptor:
+				if err := catalog.FilterDescriptorState(
+					desc, tree.CommonLookupFlags{},
+				); err != nil {
+					// Like tables, skip functions that are not public since they
+					// were only part of an expansion.
+					continue
+				}
+				ret.Descs = append(ret.Descs, desc)
+				if err := maybeAddSchemaDesc(desc.GetParentSchemaID(), true /* requirePublic */); err != nil {
+					return err
+				}
 			}
 		}
 		return nil
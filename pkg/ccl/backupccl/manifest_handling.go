@@ -928,6 +928,12 @@ func loadSQLDescsFromBackupsAtTime(
 				continue
 			}
 			isObject = true
+		case catalog.FunctionDescriptor:
+			// Like tables, functions are deleted once they are dropped.
+			if d.Dropped() {
+				continue
+			}
+			isObject = true
 		case catalog.TypeDescriptor, catalog.SchemaDescriptor:
 			isObject = true
 		}
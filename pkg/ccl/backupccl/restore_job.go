@@ -35,6 +35,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/multiregion"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
@@ -112,6 +113,7 @@ func WriteDescriptors(
 	schemas []catalog.SchemaDescriptor,
 	tables []catalog.TableDescriptor,
 	types []catalog.TypeDescriptor,
+	functions []catalog.FunctionDescriptor,
 	descCoverage tree.DescriptorCoverage,
 	extra []roachpb.KeyValue,
 ) error {
@@ -224,6 +226,29 @@ func WriteDescriptors(
 			b.CPut(catalogkeys.EncodeNameKey(codec, typ), typ.GetID(), nil)
 		}
 
+		// Write namespace and descriptor entries for each function.
+		for i := range functions {
+			fn := functions[i]
+			updatedPrivileges, err := getRestoringPrivileges(ctx, codec, txn, fn, user, wroteDBs, descCoverage)
+			if err != nil {
+				return err
+			}
+			if updatedPrivileges != nil {
+				if mut, ok := fn.(*funcdesc.Mutable); ok {
+					mut.Privileges = updatedPrivileges
+				} else {
+					log.Fatalf(ctx, "wrong type for function %d, %T, expected Mutable",
+						fn.GetID(), fn)
+				}
+			}
+			if err := descsCol.WriteDescToBatch(
+				ctx, false /* kvTrace */, fn.(catalog.MutableDescriptor), b,
+			); err != nil {
+				return err
+			}
+			b.CPut(catalogkeys.EncodeNameKey(codec, fn), fn.GetID(), nil)
+		}
+
 		for _, kv := range extra {
 			b.InitPut(kv.Key, &kv.Value, false)
 		}
@@ -795,7 +820,7 @@ func spansForAllRestoreTableIndexes(
 		// entire interval. DROPPED tables should never later become PUBLIC.
 		// TODO(pbardea): Consider and test the interaction between revision_history
 		// backups and OFFLINE tables.
-		rawTbl, _, _, _ := descpb.FromDescriptor(rev.Desc)
+		rawTbl, _, _, _, _ := descpb.FromDescriptor(rev.Desc)
 		if rawTbl != nil && !rawTbl.Dropped() {
 			tbl := tabledesc.NewBuilder(rawTbl).BuildImmutableTable()
 			// We only import spans for physical tables.
@@ -850,6 +875,7 @@ func createImportingDescriptors(
 	var writtenTypes []catalog.TypeDescriptor
 	var schemas []*schemadesc.Mutable
 	var types []*typedesc.Mutable
+	var functions []*funcdesc.Mutable
 	// Store the tables as both the concrete mutable structs and the interface
 	// to deal with the lack of slice covariance in go. We want the slice of
 	// mutable descriptors for rewriting but ultimately want to return the
@@ -888,6 +914,9 @@ func createImportingDescriptors(
 		case catalog.TypeDescriptor:
 			mut := typedesc.NewBuilder(desc.TypeDesc()).BuildCreatedMutableType()
 			types = append(types, mut)
+		case catalog.FunctionDescriptor:
+			mut := funcdesc.NewBuilder(desc.FuncDesc()).BuildCreatedMutableFunction()
+			functions = append(functions, mut)
 		}
 	}
 
@@ -976,6 +1005,15 @@ func createImportingDescriptors(
 		return nil, nil, err
 	}
 
+	// Functions are never remapped to existing ones, so all of them are written.
+	if err := rewriteFunctionDescs(functions, details.DescriptorRewrites); err != nil {
+		return nil, nil, err
+	}
+	writtenFunctions := make([]catalog.FunctionDescriptor, len(functions))
+	for i := range functions {
+		writtenFunctions[i] = functions[i]
+	}
+
 	// Set the new descriptors' states to offline.
 	for _, desc := range mutableTables {
 		desc.SetOffline("restoring")
@@ -986,6 +1024,9 @@ func createImportingDescriptors(
 	for _, desc := range schemasToWrite {
 		desc.SetOffline("restoring")
 	}
+	for _, desc := range functions {
+		desc.SetOffline("restoring")
+	}
 	for _, desc := range mutableDatabases {
 		desc.SetOffline("restoring")
 	}
@@ -1095,7 +1136,7 @@ func createImportingDescriptors(
 			// Write the new descriptors which are set in the OFFLINE state.
 			if err := WriteDescriptors(
 				ctx, p.ExecCfg().Codec, txn, p.User(), descsCol, databases, writtenSchemas, tables, writtenTypes,
-				details.DescriptorCoverage, nil, /* extra */
+				writtenFunctions, details.DescriptorCoverage, nil, /* extra */
 			); err != nil {
 				return errors.Wrapf(err, "restoring %d TableDescriptors from %d databases", len(tables), len(databases))
 			}
@@ -1255,6 +1296,10 @@ func createImportingDescriptors(
 			for i := range schemasToWrite {
 				details.SchemaDescs[i] = schemasToWrite[i].SchemaDesc()
 			}
+			details.FunctionDescs = make([]*descpb.FunctionDescriptor, len(functions))
+			for i := range functions {
+				details.FunctionDescs[i] = functions[i].FuncDesc()
+			}
 
 			// Update the job once all descs have been prepared for ingestion.
 			err := r.job.SetDetails(ctx, txn, details)
@@ -1761,11 +1806,13 @@ func (r *restoreResumer) publishDescriptors(
 	// Write the new descriptors and flip state over to public so they can be
 	// accessed.
 	allMutDescs := make([]catalog.MutableDescriptor, 0,
-		len(details.TableDescs)+len(details.TypeDescs)+len(details.SchemaDescs)+len(details.DatabaseDescs))
+		len(details.TableDescs)+len(details.TypeDescs)+len(details.SchemaDescs)+
+			len(details.FunctionDescs)+len(details.DatabaseDescs))
 	// Create slices of raw descriptors for the restore job details.
 	newTables := make([]*descpb.TableDescriptor, 0, len(details.TableDescs))
 	newTypes := make([]*descpb.TypeDescriptor, 0, len(details.TypeDescs))
 	newSchemas := make([]*descpb.SchemaDescriptor, 0, len(details.SchemaDescs))
+	newFunctions := make([]*descpb.FunctionDescriptor, 0, len(details.FunctionDescs))
 	newDBs := make([]*descpb.DatabaseDescriptor, 0, len(details.DatabaseDescs))
 	checkVersion := func(read catalog.Descriptor, exp descpb.DescriptorVersion) error {
 		if read.GetVersion() == exp {
@@ -1843,6 +1890,18 @@ func (r *restoreResumer) publishDescriptors(
 		allMutDescs = append(allMutDescs, mutSchema)
 		newSchemas = append(newSchemas, mutSchema.SchemaDesc())
 	}
+	for _, fn := range details.FunctionDescs {
+		mutDesc, err := descsCol.GetMutableDescriptorByID(ctx, fn.ID, txn)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(mutDesc, fn.Version); err != nil {
+			return err
+		}
+		mutFunction := mutDesc.(*funcdesc.Mutable)
+		allMutDescs = append(allMutDescs, mutFunction)
+		newFunctions = append(newFunctions, mutFunction.FuncDesc())
+	}
 	for _, dbDesc := range details.DatabaseDescs {
 		// Jobs started before 20.2 upgrade finalization don't put databases in
 		// an offline state.
@@ -1889,6 +1948,7 @@ func (r *restoreResumer) publishDescriptors(
 	details.TableDescs = newTables
 	details.TypeDescs = newTypes
 	details.SchemaDescs = newSchemas
+	details.FunctionDescs = newFunctions
 	details.DatabaseDescs = newDBs
 	if err := r.job.SetDetails(ctx, txn, details); err != nil {
 		return errors.Wrap(err,
@@ -2065,6 +2125,23 @@ func (r *restoreResumer) dropDescriptors(
 		descsCol.AddDeletedDescriptor(mutType)
 	}
 
+	// Drop the function descriptors that this restore created. Like types, they
+	// have no data to GC, so they are deleted right away.
+	for _, fn := range details.FunctionDescs {
+		mutDesc, err := descsCol.GetMutableDescriptorByID(ctx, fn.ID, txn)
+		if err != nil {
+			return err
+		}
+		mutFunction := mutDesc.(*funcdesc.Mutable)
+		b.Del(catalogkeys.EncodeNameKey(codec, fn))
+		mutFunction.SetDropped()
+		if err := descsCol.WriteDescToBatch(ctx, false /* kvTrace */, mutFunction, b); err != nil {
+			return errors.Wrap(err, "writing dropping function to batch")
+		}
+		b.Del(catalogkeys.MakeDescMetadataKey(codec, fn.ID))
+		descsCol.AddDeletedDescriptor(mutFunction)
+	}
+
 	// Queue a GC job.
 	gcDetails := jobspb.SchemaChangeGCDetails{}
 	for _, tableID := range tablesToGC {
@@ -2098,6 +2175,9 @@ func (r *restoreResumer) dropDescriptors(
 	for _, schema := range details.SchemaDescs {
 		ignoredChildDescIDs[schema.ID] = struct{}{}
 	}
+	for _, fn := range details.FunctionDescs {
+		ignoredChildDescIDs[fn.ID] = struct{}{}
+	}
 	allDescs, err := descsCol.GetAllDescriptors(ctx, txn)
 	if err != nil {
 		return err
@@ -2315,7 +2395,7 @@ func getRestoringPrivileges(
 			updatedPrivileges = immutableDefaultPrivileges.CreatePrivilegesFromDefaultPrivileges(
 				parentDB.GetID(), user, tree.Tables, parentDB.GetPrivileges())
 		}
-	case catalog.TypeDescriptor, catalog.DatabaseDescriptor:
+	case catalog.TypeDescriptor, catalog.DatabaseDescriptor, catalog.FunctionDescriptor:
 		if descCoverage == tree.RequestedDescriptors {
 			// If the restore is not a cluster restore we cannot know that the users on
 			// the restoring cluster match the ones that were on the cluster that was
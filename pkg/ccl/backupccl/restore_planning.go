@@ -38,6 +38,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/multiregion"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
@@ -315,6 +316,7 @@ func allocateDescriptorRewrites(
 	schemasByID map[descpb.ID]*schemadesc.Mutable,
 	tablesByID map[descpb.ID]*tabledesc.Mutable,
 	typesByID map[descpb.ID]*typedesc.Mutable,
+	functionsByID map[descpb.ID]*funcdesc.Mutable,
 	restoreDBs []catalog.DatabaseDescriptor,
 	descriptorCoverage tree.DescriptorCoverage,
 	opts tree.RestoreOptions,
@@ -435,6 +437,13 @@ func allocateDescriptorRewrites(
 		}
 	}
 
+	// Include the function descriptors when calculating the max ID.
+	for _, fn := range functionsByID {
+		if int64(fn.ID) > maxDescIDInBackup {
+			maxDescIDInBackup = int64(fn.ID)
+		}
+	}
+
 	needsNewParentIDs := make(map[string][]descpb.ID)
 
 	// Increment the DescIDSequenceKey so that it is higher than the max desc ID
@@ -783,6 +792,56 @@ func allocateDescriptorRewrites(
 			}
 		}
 
+		// Construct a remapping entry for each function.
+		for _, fn := range functionsByID {
+			// If a descriptor has already been assigned a rewrite, then move on.
+			if _, ok := descriptorRewrites[fn.ID]; ok {
+				continue
+			}
+
+			targetDB, err := resolveTargetDB(ctx, txn, p, databasesByID, renaming, overrideDB,
+				descriptorCoverage, fn)
+			if err != nil {
+				return err
+			}
+
+			if _, ok := restoreDBNames[targetDB]; ok {
+				needsNewParentIDs[targetDB] = append(needsNewParentIDs[targetDB], fn.ID)
+			} else if descriptorCoverage == tree.AllDescriptors {
+				descriptorRewrites[fn.ID] = &jobspb.RestoreDetails_DescriptorRewrite{ParentID: fn.ParentID}
+			} else {
+				found, parentID, err := catalogkv.LookupDatabaseID(ctx, txn, p.ExecCfg().Codec, targetDB)
+				if err != nil {
+					return err
+				}
+				if !found {
+					return errors.Errorf("a database named %q needs to exist to restore function %q",
+						targetDB, fn.Name)
+				}
+				// Functions can't be overloaded, so the name must not be in use in
+				// the schema the function is restored into.
+				fnName := tree.NewUnqualifiedTableName(tree.Name(fn.GetName()))
+				scID := maybeRewriteSchemaID(fn.GetParentSchemaID(), descriptorRewrites,
+					false /* isTemporaryDesc */)
+				if err := catalogkv.CheckObjectCollision(
+					ctx, txn, p.ExecCfg().Codec, parentID, scID, fnName,
+				); err != nil {
+					return err
+				}
+
+				// Check privileges on the parent DB.
+				parentDB, err := catalogkv.MustGetDatabaseDescByID(ctx, txn, p.ExecCfg().Codec, parentID)
+				if err != nil {
+					return errors.Wrapf(err,
+						"failed to lookup parent DB %d", errors.Safe(parentID))
+				}
+				if err := p.CheckPrivilege(ctx, parentDB, privilege.CREATE); err != nil {
+					return err
+				}
+				descriptorRewrites[fn.ID] = &jobspb.RestoreDetails_DescriptorRewrite{ParentID: parentID}
+			}
+		}
+
 		return nil
 	}); err != nil {
 		return nil, err
@@ -866,6 +925,16 @@ func allocateDescriptorRewrites(
 		}
 	}
 
+	// Update remapping information for function descriptors.
+	for _, fn := range functionsByID {
+		if descriptorCoverage == tree.AllDescriptors {
+			// The function doesn't need to be remapped.
+			descriptorRewrites[fn.ID].ID = fn.ID
+		} else {
+			descriptorsToRemap = append(descriptorsToRemap, fn)
+		}
+	}
+
 	sort.Sort(catalog.Descriptors(descriptorsToRemap))
 
 	// Generate new IDs for the tables that need to be remapped.
@@ -1025,6 +1094,19 @@ func rewriteDatabaseDescs(databases []*dbdesc.Mutable, descriptorRewrites DescRe
 			newSchemas[schemaName] = descpb.DatabaseDescriptor_SchemaInfo{ID: rewrite.ID}
 		}
 		db.Schemas = newSchemas
+
+		// Rewrite the IDs of the published tables. Tables which aren't being
+		// restored are removed from the publications.
+		for i := range db.Publications {
+			pub := &db.Publications[i]
+			tableIDs := pub.TableIDs[:0]
+			for _, id := range pub.TableIDs {
+				if rewrite, ok := descriptorRewrites[id]; ok {
+					tableIDs = append(tableIDs, rewrite.ID)
+				}
+			}
+			pub.TableIDs = tableIDs
+		}
 	}
 	return nil
 }
@@ -1121,6 +1203,26 @@ func rewriteSchemaDescs(schemas []*schemadesc.Mutable, descriptorRewrites DescRe
 	return nil
 }
 
+// rewriteFunctionDescs rewrites all ID's in the input slice of
+// FunctionDescriptors using the input ID rewrite mapping.
+func rewriteFunctionDescs(functions []*funcdesc.Mutable, descriptorRewrites DescRewriteMap) error {
+	for _, fn := range functions {
+		rewrite, ok := descriptorRewrites[fn.ID]
+		if !ok {
+			return errors.Errorf("missing rewrite for function %d", fn.ID)
+		}
+		// Reset the version and modification time on this new descriptor.
+		fn.Version = 1
+		fn.ModificationTime = hlc.Timestamp{}
+
+		fn.ID = rewrite.ID
+		fn.ParentSchemaID = maybeRewriteSchemaID(fn.ParentSchemaID, descriptorRewrites,
+			false /* isTemporaryDesc */)
+		fn.ParentID = rewrite.ParentID
+	}
+	return nil
+}
+
 func maybeRewriteSchemaID(
 	curSchemaID descpb.ID, descriptorRewrites DescRewriteMap, isTemporaryDesc bool,
 ) descpb.ID {
@@ -1818,7 +1920,7 @@ func doRestorePlan(
 	for _, m := range mainBackupManifests {
 		spans := roachpb.Spans(m.Spans)
 		for i := range m.Descriptors {
-			table, _, _, _ := descpb.FromDescriptor(&m.Descriptors[i])
+			table, _, _, _, _ := descpb.FromDescriptor(&m.Descriptors[i])
 			if table == nil {
 				continue
 			}
@@ -1904,6 +2006,7 @@ func doRestorePlan(
 	schemasByID := make(map[descpb.ID]*schemadesc.Mutable)
 	tablesByID := make(map[descpb.ID]*tabledesc.Mutable)
 	typesByID := make(map[descpb.ID]*typedesc.Mutable)
+	functionsByID := make(map[descpb.ID]*funcdesc.Mutable)
 
 	for _, desc := range sqlDescs {
 		switch desc := desc.(type) {
@@ -1915,9 +2018,20 @@ func doRestorePlan(
 			tablesByID[desc.ID] = desc
 		case *typedesc.Mutable:
 			typesByID[desc.ID] = desc
+		case *funcdesc.Mutable:
+			functionsByID[desc.ID] = desc
 		}
 	}
 
+	// Nodes that don't know about function descriptors can't decode them, so
+	// don't write any until every node can.
+	if len(functionsByID) > 0 &&
+		!p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.UserDefinedFunctions) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to restore user-defined functions",
+			clusterversion.UserDefinedFunctions)
+	}
+
 	if !restoreStmt.Options.SkipLocalitiesCheck {
 		if err := checkClusterRegions(ctx, p, typesByID); err != nil {
 			return err
@@ -1955,6 +2069,7 @@ func doRestorePlan(
 		schemasByID,
 		filteredTablesByID,
 		typesByID,
+		functionsByID,
 		restoreDBs,
 		restoreStmt.DescriptorCoverage,
 		restoreStmt.Options,
@@ -1986,6 +2101,10 @@ func doRestorePlan(
 	for _, desc := range typesByID {
 		types = append(types, desc)
 	}
+	var functions []*funcdesc.Mutable
+	for _, desc := range functionsByID {
+		functions = append(functions, desc)
+	}
 
 	// We attempt to rewrite ID's in the collected type and table descriptors
 	// to catch errors during this process here, rather than in the job itself.
@@ -2001,6 +2120,9 @@ func doRestorePlan(
 	if err := rewriteTypeDescs(types, descriptorRewrites); err != nil {
 		return err
 	}
+	if err := rewriteFunctionDescs(functions, descriptorRewrites); err != nil {
+		return err
+	}
 	for i := range revalidateIndexes {
 		revalidateIndexes[i].TableID = descriptorRewrites[revalidateIndexes[i].TableID].ID
 	}
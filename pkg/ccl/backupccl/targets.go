@@ -127,7 +127,8 @@ func getRelevantDescChanges(
 		}
 		for _, i := range starting {
 			switch desc := i.(type) {
-			case catalog.TableDescriptor, catalog.TypeDescriptor, catalog.SchemaDescriptor:
+			case catalog.TableDescriptor, catalog.TypeDescriptor, catalog.SchemaDescriptor,
+				catalog.FunctionDescriptor:
 				// We need to add to interestingIDs so that if we later see a delete for
 				// this ID we still know it is interesting to us, even though we will not
 				// have a parentID at that point (since the delete is a nil desc).
@@ -160,7 +161,8 @@ func getRelevantDescChanges(
 		} else if change.Desc != nil {
 			desc := catalogkv.NewBuilder(change.Desc).BuildExistingMutable()
 			switch desc := desc.(type) {
-			case catalog.TableDescriptor, catalog.TypeDescriptor, catalog.SchemaDescriptor:
+			case catalog.TableDescriptor, catalog.TypeDescriptor, catalog.SchemaDescriptor,
+				catalog.FunctionDescriptor:
 				if _, ok := interestingParents[desc.GetParentID()]; ok {
 					interestingIDs[desc.GetID()] = struct{}{}
 					interestingChanges = append(interestingChanges, change)
@@ -217,7 +219,7 @@ func getAllDescChanges(
 				// descriptors to use during restore.
 				// Note that the modification time of descriptors on disk is usually 0.
 				// See the comment on MaybeSetDescriptorModificationTime... for more.
-				t, _, _, _ := descpb.FromDescriptorWithMVCCTimestamp(r.Desc, rev.Timestamp)
+				t, _, _, _, _ := descpb.FromDescriptorWithMVCCTimestamp(r.Desc, rev.Timestamp)
 				if priorIDs != nil && t != nil && t.ReplacementOf.ID != descpb.InvalidID {
 					priorIDs[t.ID] = t.ReplacementOf.ID
 				}
@@ -341,6 +343,10 @@ func fullClusterTargets(
 			fullClusterDescs = append(fullClusterDescs, desc)
 		case catalog.TypeDescriptor:
 			fullClusterDescs = append(fullClusterDescs, desc)
+		case catalog.FunctionDescriptor:
+			if !desc.Dropped() {
+				fullClusterDescs = append(fullClusterDescs, desc)
+			}
 		}
 	}
 	return fullClusterDescs, fullClusterDBs, nil
@@ -410,7 +416,7 @@ func checkMissingIntroducedSpans(
 		// Gather the _online_ tables included in the previous backup.
 		prevOnlineTables := make(map[descpb.ID]struct{})
 		for _, desc := range mainBackupManifests[i-1].Descriptors {
-			if table, _, _, _ := descpb.FromDescriptor(&desc); table != nil && table.Public() {
+			if table, _, _, _, _ := descpb.FromDescriptor(&desc); table != nil && table.Public() {
 				prevOnlineTables[table.GetID()] = struct{}{}
 			}
 		}
@@ -468,7 +474,7 @@ that was running an IMPORT at the time of the previous incremental in this chain
 		for _, desc := range mainBackupManifests[i].Descriptors {
 			// Check that all online tables at backup time were either introduced or
 			// in the previous backup.
-			if table, _, _, _ := descpb.FromDescriptor(&desc); table != nil && table.Public() {
+			if table, _, _, _, _ := descpb.FromDescriptor(&desc); table != nil && table.Public() {
 				if err := requiredIntroduction(table); err != nil {
 					return err
 				}
@@ -480,7 +486,7 @@ that was running an IMPORT at the time of the previous incremental in this chain
 		// manifest.Descriptors. If a descriptor switched from offline to online at
 		// any moment during the backup interval, it needs to be reintroduced.
 		for _, desc := range mainBackupManifests[i].DescriptorChanges {
-			if table, _, _, _ := descpb.FromDescriptor(desc.Desc); table != nil && table.Public() {
+			if table, _, _, _, _ := descpb.FromDescriptor(desc.Desc); table != nil && table.Public() {
 				if err := requiredIntroduction(table); err != nil {
 					return err
 				}
@@ -66,6 +66,8 @@ type changeAggregator struct {
 
 	// encoder is the Encoder to use for key and value serialization.
 	encoder Encoder
+	// query, if non-nil, projects and filters rows before they are encoded.
+	query *changefeedQuery
 	// sink is the Sink to write rows to. Resolved timestamps are never written
 	// by changeAggregator.
 	sink Sink
@@ -144,7 +146,7 @@ func newChangeAggregatorProcessor(
 	if err := ca.Init(
 		ca,
 		post,
-		changefeeddist.ChangefeedResultTypes,
+		changefeeddist.ResultTypes(spec.Feed),
 		flowCtx,
 		processorID,
 		output,
@@ -163,6 +165,11 @@ func newChangeAggregatorProcessor(
 	if ca.encoder, err = getEncoder(ca.spec.Feed.Opts, ca.spec.Feed.Targets); err != nil {
 		return nil, err
 	}
+	if ca.spec.Feed.Select != `` {
+		if ca.query, err = newChangefeedQuery(ca.spec.Feed.Select, flowCtx.NewEvalCtx()); err != nil {
+			return nil, err
+		}
+	}
 
 	// If the resolved timestamp frequency is specified, use it as a rough
 	// approximation of how latency-sensitive the changefeed user is. If it's
@@ -250,8 +257,15 @@ func (ca *changeAggregator) Start(ctx context.Context) {
 		return
 	}
 
-	ca.sink, err = getSink(ctx, ca.flowCtx.Cfg, ca.spec.Feed, timestampOracle,
-		ca.spec.User(), ca.spec.JobID, ca.sliMetrics)
+	if isTransactionEnvelope(ca.spec.Feed) {
+		// Only the changeFrontier knows when all the rows committed by a
+		// transaction have been seen, so rows are forwarded to it to be grouped
+		// and emitted, as they are for sinkless changefeeds.
+		ca.sink = &bufferSink{withUpdated: true}
+	} else {
+		ca.sink, err = getSink(ctx, ca.flowCtx.Cfg, ca.spec.Feed, timestampOracle,
+			ca.spec.User(), ca.spec.JobID, ca.sliMetrics)
+	}
 
 	if err != nil {
 		err = changefeedbase.MarkRetryableError(err)
@@ -282,7 +296,7 @@ func (ca *changeAggregator) Start(ctx context.Context) {
 	} else {
 		ca.eventConsumer = newKVEventToRowConsumer(
 			ctx, ca.flowCtx.Cfg, ca.frontier.SpanFrontier(), initialHighWater,
-			ca.sink, ca.encoder, ca.spec.Feed, ca.knobs)
+			ca.sink, ca.encoder, ca.query, ca.spec.Feed, ca.knobs)
 	}
 }
 
@@ -598,12 +612,16 @@ func (ca *changeAggregator) emitResolved(batch jobspb.ResolvedSpans) error {
 		// Enqueue a row to be returned that indicates some span-level resolved
 		// timestamp has advanced. If any rows were queued in `sink`, they must
 		// be emitted first.
-		ca.resolvedSpanBuf.Push(rowenc.EncDatumRow{
+		row := rowenc.EncDatumRow{
 			rowenc.EncDatum{Datum: tree.NewDBytes(tree.DBytes(resolvedBytes))},
 			rowenc.EncDatum{Datum: tree.DNull}, // topic
 			rowenc.EncDatum{Datum: tree.DNull}, // key
 			rowenc.EncDatum{Datum: tree.DNull}, // value
-		})
+		}
+		if isTransactionEnvelope(ca.spec.Feed) {
+			row = append(row, rowenc.EncDatum{Datum: tree.DNull}) // updated
+		}
+		ca.resolvedSpanBuf.Push(row)
 		ca.metrics.ResolvedMessages.Inc(1)
 	}
 	return nil
@@ -623,6 +641,7 @@ type kvEventConsumer interface {
 type kvEventToRowConsumer struct {
 	frontier  *span.Frontier
 	encoder   Encoder
+	query     *changefeedQuery
 	scratch   bufalloc.ByteAllocator
 	sink      Sink
 	cursor    hlc.Timestamp
@@ -630,6 +649,9 @@ type kvEventToRowConsumer struct {
 	rfCache   *rowFetcherCache
 	details   jobspb.ChangefeedDetails
 	kvFetcher row.SpanKVFetcher
+	// emitDatumRows is set when the sink encodes rows itself, in which case
+	// rows are emitted with rowSink.EmitDatumRow rather than encoded.
+	emitDatumRows bool
 }
 
 var _ kvEventConsumer = &kvEventToRowConsumer{}
@@ -641,6 +663,7 @@ func newKVEventToRowConsumer(
 	cursor hlc.Timestamp,
 	sink Sink,
 	encoder Encoder,
+	query *changefeedQuery,
 	details jobspb.ChangefeedDetails,
 	knobs TestingKnobs,
 ) kvEventConsumer {
@@ -655,11 +678,14 @@ func newKVEventToRowConsumer(
 	return &kvEventToRowConsumer{
 		frontier: frontier,
 		encoder:  encoder,
+		query:    query,
 		sink:     sink,
 		cursor:   cursor,
 		rfCache:  rfCache,
 		details:  details,
 		knobs:    knobs,
+		emitDatumRows: changefeedbase.FormatType(details.Opts[changefeedbase.OptFormat]) ==
+			changefeedbase.OptFormatParquet,
 	}
 }
 
@@ -691,6 +717,21 @@ func (c *kvEventToRowConsumer) ConsumeEvent(ctx context.Context, ev kvevent.Even
 			"or equal to the local frontier %s.", r.updated, c.frontier.Frontier())
 		return nil
 	}
+	if c.query != nil && r.tableDesc != nil {
+		projected, matches, err := c.query.eval(ctx, r)
+		if err != nil {
+			return err
+		}
+		if !matches {
+			alloc := ev.DetachAlloc()
+			alloc.Release(ctx)
+			return nil
+		}
+		r = projected
+	}
+	if c.emitDatumRows {
+		return c.emitDatumRow(ctx, r, ev)
+	}
 	var keyCopy, valueCopy []byte
 	encodedKey, err := c.encoder.EncodeKey(ctx, r)
 	if err != nil {
@@ -720,6 +761,22 @@ func (c *kvEventToRowConsumer) ConsumeEvent(ctx context.Context, ev kvevent.Even
 	return nil
 }
 
+// emitDatumRow emits a row to a sink which encodes rows itself.
+func (c *kvEventToRowConsumer) emitDatumRow(
+	ctx context.Context, r encodeRow, ev kvevent.Event,
+) error {
+	rs, ok := c.sink.(rowSink)
+	if !ok {
+		return errors.AssertionFailedf("sink %T does not support emitting rows", c.sink)
+	}
+	if c.knobs.BeforeEmitRow != nil {
+		if err := c.knobs.BeforeEmitRow(ctx); err != nil {
+			return err
+		}
+	}
+	return rs.EmitDatumRow(ctx, tableDescriptorTopic{r.tableDesc}, r, ev.DetachAlloc())
+}
+
 func (c *kvEventToRowConsumer) eventToRow(
 	ctx context.Context, event kvevent.Event,
 ) (encodeRow, error) {
@@ -928,6 +985,10 @@ type changeFrontier struct {
 	// resolved timestamp to be returned. It depends on everything in
 	// `passthroughBuf` being sent, so that one needs to be emptied first.
 	resolvedBuf *encDatumRowBuffer
+	// txnBuf, if non-nil, groups the changed rows forwarded by the
+	// changeAggregators into transactions, which are emitted to the sink as
+	// the frontier advances. It's only used with envelope=transaction.
+	txnBuf *transactionBuffer
 	// metrics are monitoring counters shared between all changefeeds.
 	metrics    *Metrics
 	sliMetrics *sliMetrics
@@ -1086,6 +1147,16 @@ func newChangeFrontierProcessor(
 	if cf.encoder, err = getEncoder(spec.Feed.Opts, spec.Feed.Targets); err != nil {
 		return nil, err
 	}
+	if isTransactionEnvelope(spec.Feed) {
+		txnEncoder, ok := cf.encoder.(transactionEncoder)
+		if !ok {
+			return nil, errors.Errorf(`%s=%s is not supported with %s=%s`,
+				changefeedbase.OptEnvelope, changefeedbase.OptEnvelopeTransaction,
+				changefeedbase.OptFormat, spec.Feed.Opts[changefeedbase.OptFormat])
+		}
+		cf.txnBuf = makeTransactionBuffer(
+			txnEncoder, makeTransactionTopic(spec.Feed.Targets), &flowCtx.Cfg.Settings.SV, &cf.memAcc)
+	}
 
 	return cf, nil
 }
@@ -1109,8 +1180,19 @@ func (cf *changeFrontier) Start(ctx context.Context) {
 	cf.metrics = cf.flowCtx.Cfg.JobRegistry.MetricsStruct().Changefeed.(*Metrics)
 
 	// Pass a nil oracle because this sink is only used to emit resolved timestamps
-	// but the oracle is only used when emitting row updates.
-	var nilOracle timestampLowerBoundOracle
+	// but the oracle is only used when emitting row updates. That is, unless
+	// this sink emits transactions: they're only emitted once the frontier
+	// reaches them and the sink is flushed right after, so the ones emitted
+	// after a flush are past the frontier at the time of the flush. Batches of
+	// the rows of scans are emitted earlier, but at the timestamp of the scan,
+	// which is just past the frontier.
+	var timestampOracle timestampLowerBoundOracle
+	if cf.txnBuf != nil {
+		timestampOracle = &changeAggregatorLowerBoundOracle{
+			sf:                         cf.frontier.SpanFrontier(),
+			initialInclusiveLowerBound: cf.spec.Feed.StatementTime,
+		}
+	}
 	var err error
 	sli, err := cf.metrics.getSLIMetrics(cf.spec.Feed.Opts[changefeedbase.OptMetricsScope])
 	if err != nil {
@@ -1118,7 +1200,7 @@ func (cf *changeFrontier) Start(ctx context.Context) {
 		return
 	}
 	cf.sliMetrics = sli
-	cf.sink, err = getSink(ctx, cf.flowCtx.Cfg, cf.spec.Feed, nilOracle,
+	cf.sink, err = getSink(ctx, cf.flowCtx.Cfg, cf.spec.Feed, timestampOracle,
 		cf.spec.User(), cf.spec.JobID, sli)
 
 	if err != nil {
@@ -1262,7 +1344,15 @@ func (cf *changeFrontier) Next() (rowenc.EncDatumRow, *execinfrapb.ProducerMetad
 			// In changefeeds with a sink, this will never happen. But in the
 			// core changefeed, which returns changed rows directly via pgwire,
 			// a row with a null resolved_span field is a changed row that needs
-			// to be forwarded to the gateway.
+			// to be forwarded to the gateway. With envelope=transaction, it's a
+			// changed row to be emitted along with the rest of its transaction.
+			if cf.txnBuf != nil {
+				if err := cf.txnBuf.add(cf.Ctx, cf.sink, row, cf.frontier.BackfillTS()); err != nil {
+					cf.MoveToDraining(err)
+					break
+				}
+				continue
+			}
 			cf.passthroughBuf.Push(row)
 			continue
 		}
@@ -1313,6 +1403,14 @@ func (cf *changeFrontier) forwardFrontier(resolved jobspb.ResolvedSpan) error {
 
 	cf.maybeLogBehindSpan(frontierChanged)
 
+	// Transactions must be emitted before the job progress is checkpointed
+	// past them, since they would otherwise not be emitted again on restart.
+	if frontierChanged && cf.txnBuf != nil {
+		if err := cf.txnBuf.flush(cf.Ctx, cf.sink, cf.frontier.Frontier()); err != nil {
+			return err
+		}
+	}
+
 	// If frontier changed, we emit resolved timestamp.
 	emitResolved := frontierChanged
 
@@ -1354,8 +1452,10 @@ func (cf *changeFrontier) maybeCheckpointJob(
 	// as we receive spans from the scan request at the Backfill Timestamp
 	inBackfill := !frontierChanged && resolvedSpan.Timestamp.Equal(cf.frontier.BackfillTS())
 
-	// During a backfill we store a checkpoint of completed scans at a throttled rate in the job record
-	updateCheckpoint := inBackfill && cf.js.canCheckpointBackfill()
+	// During a backfill we store a checkpoint of completed scans at a throttled rate in the job record.
+	// With envelope=transaction, the rows of completed scans are only emitted
+	// once the whole backfill completes, so they would be lost on restart.
+	updateCheckpoint := inBackfill && cf.txnBuf == nil && cf.js.canCheckpointBackfill()
 
 	var checkpoint jobspb.ChangefeedProgress_Checkpoint
 	if updateCheckpoint {
@@ -0,0 +1,386 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// changefeedQueryRejectFlags are the kinds of expressions which changefeed
+// queries do not support. Rows are evaluated one at a time, possibly more
+// than once, so expressions must be scalar and deterministic.
+const changefeedQueryRejectFlags = tree.RejectSpecial | tree.RejectSubqueries |
+	tree.RejectVolatileFunctions | tree.RejectUserDefinedFunctions
+
+// changefeedQuery is the projection and the filter of a changefeed query,
+// CREATE CHANGEFEED ... AS SELECT <exprs> FROM <table> WHERE <predicate>. The
+// query is evaluated on the rows of the table before they are encoded: rows
+// which do not match the predicate are not emitted, and the columns of the
+// rows which are emitted are the expressions of the query.
+//
+// Deleted rows only hold their primary key, so the predicate is evaluated on
+// their previous value if it is known, i.e. with the diff option, and they are
+// otherwise always emitted. Likewise, an update of a row which matched the
+// predicate to a value which doesn't match it is not emitted.
+type changefeedQuery struct {
+	sel *tree.SelectClause
+	// from is the name through which columns may be qualified in the query.
+	from    tree.TableName
+	evalCtx *tree.EvalContext
+	alloc   rowenc.DatumAlloc
+
+	// compiled caches the query compiled for each version of the table.
+	compiled map[tableIDAndVersion]*compiledChangefeedQuery
+}
+
+// compiledChangefeedQuery is a changefeedQuery compiled for a version of the
+// table it selects from.
+type compiledChangefeedQuery struct {
+	// desc describes the projection: its columns are the columns of the
+	// query, and it otherwise holds the identity of the table.
+	desc  catalog.TableDescriptor
+	exprs []tree.TypedExpr
+	where tree.TypedExpr
+	ivars *changefeedQueryIVarContainer
+}
+
+// changefeedQueryIVarContainer binds the columns of the table a query selects
+// from to the row the query is evaluated on.
+type changefeedQueryIVarContainer struct {
+	cols []catalog.Column
+	row  tree.Datums
+}
+
+var _ tree.IndexedVarContainer = &changefeedQueryIVarContainer{}
+
+// IndexedVarEval implements the tree.IndexedVarContainer interface.
+func (c *changefeedQueryIVarContainer) IndexedVarEval(
+	idx int, _ *tree.EvalContext,
+) (tree.Datum, error) {
+	return c.row[idx], nil
+}
+
+// IndexedVarResolvedType implements the tree.IndexedVarContainer interface.
+func (c *changefeedQueryIVarContainer) IndexedVarResolvedType(idx int) *types.T {
+	return c.cols[idx].GetType()
+}
+
+// IndexedVarNodeFormatter implements the tree.IndexedVarContainer interface.
+func (c *changefeedQueryIVarContainer) IndexedVarNodeFormatter(idx int) tree.NodeFormatter {
+	n := tree.Name(c.cols[idx].GetName())
+	return &n
+}
+
+// parseChangefeedQuery parses the SELECT clause of a changefeed query, as
+// stored in the details of the changefeed.
+func parseChangefeedQuery(sql string) (*tree.SelectClause, error) {
+	stmt, err := parser.ParseOne(sql)
+	if err != nil {
+		return nil, err
+	}
+	if sel, ok := stmt.AST.(*tree.Select); ok && sel.With == nil && sel.OrderBy == nil &&
+		sel.Limit == nil && len(sel.Locking) == 0 {
+		if clause, ok := sel.Select.(*tree.SelectClause); ok {
+			return clause, nil
+		}
+	}
+	return nil, errors.AssertionFailedf("unexpected changefeed query: %s", sql)
+}
+
+// changefeedQueryTarget validates that a changefeed query only uses the
+// clauses of a SELECT that changefeed queries support, and returns the table
+// it selects from.
+func changefeedQueryTarget(
+	sel *tree.SelectClause,
+) (*tree.AliasedTableExpr, *tree.TableName, error) {
+	unsupported := func(clause string) error {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"%s is not supported in changefeed queries", clause)
+	}
+	switch {
+	case sel.Distinct || sel.DistinctOn != nil:
+		return nil, nil, unsupported("DISTINCT")
+	case sel.GroupBy != nil:
+		return nil, nil, unsupported("GROUP BY")
+	case sel.Having != nil:
+		return nil, nil, unsupported("HAVING")
+	case sel.Window != nil:
+		return nil, nil, unsupported("WINDOW")
+	case sel.From.AsOf.Expr != nil:
+		return nil, nil, unsupported("AS OF SYSTEM TIME")
+	}
+	if len(sel.From.Tables) != 1 {
+		return nil, nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"changefeed queries must select from a single table")
+	}
+	from, ok := sel.From.Tables[0].(*tree.AliasedTableExpr)
+	if !ok {
+		return nil, nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"changefeed queries must select from a single table")
+	}
+	tn, ok := from.Expr.(*tree.TableName)
+	if !ok || from.IndexFlags != nil || from.Ordinality || len(from.As.Cols) > 0 {
+		return nil, nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"changefeed queries must select from a table, got %s", tree.AsString(from))
+	}
+	return from, tn, nil
+}
+
+// newChangefeedQuery returns the changefeedQuery for the SELECT clause of a
+// changefeed query. The evaluation context is used to evaluate the query, and
+// must not be shared.
+func newChangefeedQuery(sql string, evalCtx *tree.EvalContext) (*changefeedQuery, error) {
+	sel, err := parseChangefeedQuery(sql)
+	if err != nil {
+		return nil, err
+	}
+	from, tn, err := changefeedQueryTarget(sel)
+	if err != nil {
+		return nil, err
+	}
+	q := &changefeedQuery{
+		sel:      sel,
+		from:     *tn,
+		evalCtx:  evalCtx,
+		compiled: make(map[tableIDAndVersion]*compiledChangefeedQuery),
+	}
+	if from.As.Alias != "" {
+		q.from = tree.MakeUnqualifiedTableName(from.As.Alias)
+	}
+	return q, nil
+}
+
+// compile compiles the query for a version of the table it selects from.
+func (q *changefeedQuery) compile(
+	ctx context.Context, desc catalog.TableDescriptor,
+) (*compiledChangefeedQuery, error) {
+	cacheKey := makeTableIDAndVersion(desc.GetID(), desc.GetVersion())
+	if c, ok := q.compiled[cacheKey]; ok {
+		return c, nil
+	}
+
+	cols := desc.PublicColumns()
+	c := &compiledChangefeedQuery{
+		ivars: &changefeedQueryIVarContainer{cols: cols, row: make(tree.Datums, len(cols))},
+	}
+	ivarHelper := tree.MakeIndexedVarHelper(c.ivars, len(cols))
+	source := colinfo.NewSourceInfoForSingleTable(
+		q.from, colinfo.ResultColumnsFromColumns(desc.GetID(), cols),
+	)
+	searchPath := q.evalCtx.SessionData().SearchPath
+	semaCtx := tree.MakeSemaContext()
+	semaCtx.IVarContainer = c.ivars
+	semaCtx.SearchPath = searchPath
+	typeCheck := func(expr tree.Expr, typ *types.T, context string) (tree.TypedExpr, error) {
+		var v schemaexpr.NameResolutionVisitor
+		expr, err := schemaexpr.ResolveNamesUsingVisitor(&v, expr, source, ivarHelper, searchPath)
+		if err != nil {
+			return nil, err
+		}
+		semaCtx.Properties.Require(context, changefeedQueryRejectFlags)
+		return tree.TypeCheck(ctx, expr, &semaCtx, typ)
+	}
+
+	var projection []descpb.ColumnDescriptor
+	names := make(map[string]struct{})
+	addColumn := func(name string, expr tree.TypedExpr) error {
+		if _, ok := names[name]; ok {
+			return pgerror.Newf(pgcode.DuplicateColumn,
+				"changefeed query has several columns named %q; use AS to rename them", name)
+		}
+		names[name] = struct{}{}
+		projection = append(projection, descpb.ColumnDescriptor{
+			Name:     name,
+			ID:       descpb.ColumnID(len(projection) + 1),
+			Type:     expr.ResolvedType(),
+			Nullable: true,
+		})
+		c.exprs = append(c.exprs, expr)
+		return nil
+	}
+	for _, target := range q.sel.Exprs {
+		star, err := q.isStar(ctx, target.Expr, source)
+		if err != nil {
+			return nil, err
+		}
+		if star {
+			for i, col := range cols {
+				if col.IsHidden() {
+					continue
+				}
+				if err := addColumn(col.GetName(), ivarHelper.IndexedVar(i)); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+		expr, err := typeCheck(target.Expr, types.Any, "CHANGEFEED")
+		if err != nil {
+			return nil, err
+		}
+		name, err := tree.GetRenderColName(searchPath, target)
+		if err != nil {
+			return nil, err
+		}
+		if err := addColumn(name, expr); err != nil {
+			return nil, err
+		}
+	}
+	if q.sel.Where != nil {
+		var err error
+		if c.where, err = typeCheck(q.sel.Where.Expr, types.Bool, "WHERE"); err != nil {
+			return nil, err
+		}
+	}
+
+	c.desc = tabledesc.NewBuilder(&descpb.TableDescriptor{
+		ID:                      desc.GetID(),
+		Name:                    desc.GetName(),
+		ParentID:                desc.GetParentID(),
+		UnexposedParentSchemaID: desc.GetParentSchemaID(),
+		Version:                 desc.GetVersion(),
+		ModificationTime:        desc.GetModificationTime(),
+		Columns:                 projection,
+		NextColumnID:            descpb.ColumnID(len(projection) + 1),
+	}).BuildImmutableTable()
+	q.compiled[cacheKey] = c
+	return c, nil
+}
+
+// isStar returns whether an expression of the SELECT clause is `*`, or
+// `<table>.*`.
+func (q *changefeedQuery) isStar(
+	ctx context.Context, expr tree.Expr, source *colinfo.DataSourceInfo,
+) (bool, error) {
+	vBase, ok := expr.(tree.VarName)
+	if !ok {
+		return false, nil
+	}
+	v, err := vBase.NormalizeVarName()
+	if err != nil {
+		return false, err
+	}
+	switch t := v.(type) {
+	case tree.UnqualifiedStar:
+		return true, nil
+	case *tree.AllColumnsSelector:
+		_, _, err := colinfo.ResolveAllColumnsSelector(ctx, &colinfo.ColumnResolver{Source: source}, t)
+		return err == nil, err
+	}
+	return false, nil
+}
+
+// eval evaluates the query on a row. It returns the projected row, whose key
+// is still the key of the row, or false if the row does not match the
+// predicate of the query.
+func (q *changefeedQuery) eval(ctx context.Context, row encodeRow) (encodeRow, bool, error) {
+	c, err := q.compile(ctx, row.tableDesc)
+	if err != nil {
+		return encodeRow{}, false, err
+	}
+	var prev *compiledChangefeedQuery
+	if row.prevDatums != nil {
+		if prev, err = q.compile(ctx, row.prevTableDesc); err != nil {
+			return encodeRow{}, false, err
+		}
+	}
+
+	projected := row
+	projected.tableDesc = c.desc
+	projected.source = &row
+	if row.deleted {
+		if prev != nil && !row.prevDeleted {
+			if matches, err := q.matches(prev, row.prevDatums); err != nil || !matches {
+				return encodeRow{}, false, err
+			}
+		}
+		// Only the key of deleted rows is known.
+		projected.datums = make(rowenc.EncDatumRow, len(c.exprs))
+		for i := range projected.datums {
+			projected.datums[i] = rowenc.EncDatum{Datum: tree.DNull}
+		}
+	} else {
+		if matches, err := q.matches(c, row.datums); err != nil || !matches {
+			return encodeRow{}, false, err
+		}
+		if projected.datums, err = q.project(c, row.datums); err != nil {
+			return encodeRow{}, false, err
+		}
+	}
+	if prev != nil {
+		projected.prevTableDesc = prev.desc
+		projected.prevDatums = rowenc.EncDatumRow{}
+		if !row.prevDeleted {
+			if projected.prevDatums, err = q.project(prev, row.prevDatums); err != nil {
+				return encodeRow{}, false, err
+			}
+		}
+	}
+	return projected, true, nil
+}
+
+// bind binds the columns of the table to the datums of a row.
+func (q *changefeedQuery) bind(c *compiledChangefeedQuery, datums rowenc.EncDatumRow) error {
+	for i, col := range c.ivars.cols {
+		if err := datums[i].EnsureDecoded(col.GetType(), &q.alloc); err != nil {
+			return err
+		}
+		c.ivars.row[i] = datums[i].Datum
+	}
+	q.evalCtx.IVarContainer = c.ivars
+	return nil
+}
+
+// matches returns whether a row matches the predicate of the query.
+func (q *changefeedQuery) matches(
+	c *compiledChangefeedQuery, datums rowenc.EncDatumRow,
+) (bool, error) {
+	if c.where == nil {
+		return true, nil
+	}
+	if err := q.bind(c, datums); err != nil {
+		return false, err
+	}
+	d, err := c.where.Eval(q.evalCtx)
+	if err != nil {
+		return false, err
+	}
+	return d == tree.DBoolTrue, nil
+}
+
+// project returns the values of the expressions of the query for a row.
+func (q *changefeedQuery) project(
+	c *compiledChangefeedQuery, datums rowenc.EncDatumRow,
+) (rowenc.EncDatumRow, error) {
+	if err := q.bind(c, datums); err != nil {
+		return nil, err
+	}
+	projected := make(rowenc.EncDatumRow, len(c.exprs))
+	for i, expr := range c.exprs {
+		d, err := expr.Eval(q.evalCtx)
+		if err != nil {
+			return nil, err
+		}
+		projected[i] = rowenc.EncDatum{Datum: d}
+	}
+	return projected, nil
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
 	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/docs"
 	"github.com/cockroachdb/cockroach/pkg/featureflag"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
@@ -188,14 +189,26 @@ func changefeedPlanHook(
 			statementTime = initialHighWater
 		}
 
+		// A changefeed query targets the table it selects from.
+		targetList := changefeedStmt.Targets
+		if changefeedStmt.Select != nil {
+			_, tn, err := changefeedQueryTarget(changefeedStmt.Select)
+			if err != nil {
+				return err
+			}
+			targetList = tree.TargetList{
+				Tables: tree.TablePatterns{tn.ToUnresolvedObjectName().ToUnresolvedName()},
+			}
+		}
+
 		// For now, disallow targeting a database or wildcard table selection.
 		// Getting it right as tables enter and leave the set over time is
 		// tricky.
-		if len(changefeedStmt.Targets.Databases) > 0 {
+		if len(targetList.Databases) > 0 {
 			return errors.Errorf(`CHANGEFEED cannot target %s`,
-				tree.AsString(&changefeedStmt.Targets))
+				tree.AsString(&targetList))
 		}
-		for _, t := range changefeedStmt.Targets.Tables {
+		for _, t := range targetList.Tables {
 			p, err := t.NormalizeTablePattern()
 			if err != nil {
 				return err
@@ -207,7 +220,7 @@ func changefeedPlanHook(
 
 		// This grabs table descriptors once to get their ids.
 		targetDescs, _, err := backupresolver.ResolveTargetsToDescriptors(
-			ctx, p, statementTime, &changefeedStmt.Targets)
+			ctx, p, statementTime, &targetList)
 		if err != nil {
 			err = errors.Wrap(err, "failed to resolve targets in the CHANGEFEED stmt")
 			if !initialHighWater.IsEmpty() {
@@ -298,11 +311,28 @@ func changefeedPlanHook(
 		if details, err = validateDetails(details); err != nil {
 			return err
 		}
+		if isTransactionEnvelope(details) &&
+			!p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.ChangefeedTransactionEnvelope) {
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"version %v must be finalized to use %s=%s",
+				clusterversion.ChangefeedTransactionEnvelope,
+				changefeedbase.OptEnvelope, changefeedbase.OptEnvelopeTransaction)
+		}
 
 		if _, err := getEncoder(details.Opts, details.Targets); err != nil {
 			return err
 		}
 
+		if changefeedStmt.Select != nil {
+			if err := validateChangefeedQuery(
+				ctx, p, changefeedStmt.Select, details.Opts, targetDescs,
+			); err != nil {
+				return err
+			}
+			details.Select = tree.AsStringWithFlags(changefeedStmt.Select, tree.FmtParsable)
+			telemetry.Count(`changefeed.create.query`)
+		}
+
 		if isCloudStorageSink(parsedSink) || isWebhookSink(parsedSink) {
 			details.Opts[changefeedbase.OptKeyInValue] = ``
 		}
@@ -494,6 +524,7 @@ func changefeedJobDescription(
 	c := &tree.CreateChangefeed{
 		Targets: changefeed.Targets,
 		SinkURI: tree.NewDString(cleanedSinkURI),
+		Select:  changefeed.Select,
 	}
 	for k, v := range opts {
 		if k == changefeedbase.OptWebhookAuthHeader {
@@ -510,6 +541,36 @@ func changefeedJobDescription(
 	return tree.AsStringWithFQNames(c, ann), nil
 }
 
+// validateChangefeedQuery checks that a changefeed query can be evaluated on
+// the rows of the table it selects from, so that errors in the query are
+// returned to the user rather than failing the changefeed once it runs.
+func validateChangefeedQuery(
+	ctx context.Context,
+	p sql.PlanHookState,
+	sel *tree.SelectClause,
+	opts map[string]string,
+	targetDescs []catalog.Descriptor,
+) error {
+	if opts[changefeedbase.OptFormat] == string(changefeedbase.OptFormatNative) {
+		return errors.Errorf(`%s=%s is not supported with changefeed queries`,
+			changefeedbase.OptFormat, changefeedbase.OptFormatNative)
+	}
+	query, err := newChangefeedQuery(
+		tree.AsStringWithFlags(sel, tree.FmtParsable), p.ExtendedEvalContext().EvalContext.Copy(),
+	)
+	if err != nil {
+		return err
+	}
+	for _, desc := range targetDescs {
+		if table, isTable := desc.(catalog.TableDescriptor); isTable {
+			if _, err := query.compile(ctx, table); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func redactUser(uri string) string {
 	u, _ := url.Parse(uri)
 	if u.User != nil {
@@ -589,6 +650,8 @@ func validateDetails(details jobspb.ChangefeedDetails) (jobspb.ChangefeedDetails
 			details.Opts[opt] = string(changefeedbase.OptEnvelopeKeyOnly)
 		case ``, changefeedbase.OptEnvelopeWrapped:
 			details.Opts[opt] = string(changefeedbase.OptEnvelopeWrapped)
+		case changefeedbase.OptEnvelopeTransaction:
+			details.Opts[opt] = string(changefeedbase.OptEnvelopeTransaction)
 		default:
 			return jobspb.ChangefeedDetails{}, errors.Errorf(
 				`unknown %s: %s`, opt, v)
@@ -599,7 +662,8 @@ func validateDetails(details jobspb.ChangefeedDetails) (jobspb.ChangefeedDetails
 		switch v := changefeedbase.FormatType(details.Opts[opt]); v {
 		case ``, changefeedbase.OptFormatJSON:
 			details.Opts[opt] = string(changefeedbase.OptFormatJSON)
-		case changefeedbase.OptFormatAvro, changefeedbase.DeprecatedOptFormatAvro:
+		case changefeedbase.OptFormatAvro, changefeedbase.DeprecatedOptFormatAvro,
+			changefeedbase.OptFormatParquet:
 			// No-op.
 		default:
 			return jobspb.ChangefeedDetails{}, errors.Errorf(
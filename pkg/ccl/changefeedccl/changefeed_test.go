@@ -38,6 +38,7 @@ import (
 	_ "github.com/cockroachdb/cockroach/pkg/ccl/partitionccl"
 	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
 	_ "github.com/cockroachdb/cockroach/pkg/cloud/impl" // registers cloud storage providers
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/keys"
@@ -283,6 +284,77 @@ func TestChangefeedDiff(t *testing.T) {
 	t.Run(`webhook`, webhookTest(testFn))
 }
 
+func TestChangefeedQuery(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, db *gosql.DB, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(db)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING, ssn STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (0, 'zero', '000'), (1, 'one', '111')`)
+
+		foo := feed(t, f,
+			`CREATE CHANGEFEED AS SELECT a, upper(f.b) AS b FROM foo AS f WHERE a % 2 = 1`)
+		defer closeFeed(t, foo)
+
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "ONE"}}`,
+		})
+
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (2, 'two', '222'), (3, 'three', '333')`)
+		sqlDB.Exec(t, `UPDATE foo SET b = 'uno' WHERE a = 1`)
+		assertPayloads(t, foo, []string{
+			`foo: [3]->{"after": {"a": 3, "b": "THREE"}}`,
+			`foo: [1]->{"after": {"a": 1, "b": "UNO"}}`,
+		})
+
+		// Without the diff option, the previous value of deleted rows is not
+		// known, so they are always emitted.
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a IN (2, 3)`)
+		assertPayloads(t, foo, []string{
+			`foo: [2]->{"after": null}`,
+			`foo: [3]->{"after": null}`,
+		})
+	}
+
+	t.Run(`sinkless`, sinklessTest(testFn))
+	t.Run(`enterprise`, enterpriseTest(testFn))
+	t.Run(`kafka`, kafkaTest(testFn))
+	t.Run(`webhook`, webhookTest(testFn))
+}
+
+func TestChangefeedQueryDiff(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, db *gosql.DB, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(db)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING, ssn STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (0, 'zero', '000'), (1, 'one', '111')`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED WITH diff AS SELECT * FROM foo WHERE b != 'hidden'`)
+		defer closeFeed(t, foo)
+
+		assertPayloads(t, foo, []string{
+			`foo: [0]->{"after": {"a": 0, "b": "zero", "ssn": "000"}, "before": null}`,
+			`foo: [1]->{"after": {"a": 1, "b": "one", "ssn": "111"}, "before": null}`,
+		})
+
+		sqlDB.Exec(t, `UPDATE foo SET b = 'hidden' WHERE a = 0`)
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 0`)
+		sqlDB.Exec(t, `UPDATE foo SET ssn = '123' WHERE a = 1`)
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 1`)
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "one", "ssn": "123"}, "before": {"a": 1, "b": "one", "ssn": "111"}}`,
+			`foo: [1]->{"after": null, "before": {"a": 1, "b": "one", "ssn": "123"}}`,
+		})
+	}
+
+	t.Run(`sinkless`, sinklessTest(testFn))
+	t.Run(`enterprise`, enterpriseTest(testFn))
+	t.Run(`kafka`, kafkaTest(testFn))
+}
+
 func TestChangefeedTenants(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -424,6 +496,130 @@ func TestChangefeedEnvelope(t *testing.T) {
 	t.Run(`kafka`, kafkaTest(testFn))
 }
 
+func TestChangefeedTransactionEnvelope(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, db *gosql.DB, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(db)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `CREATE TABLE bar (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (0, 'initial')`)
+		sqlDB.Exec(t, `INSERT INTO bar VALUES (0, 'initial')`)
+
+		foobar := feed(t, f, `CREATE CHANGEFEED FOR foo, bar WITH envelope=transaction`)
+		defer closeFeed(t, foobar)
+
+		// The rows of the initial scan are emitted as a single transaction, on
+		// the topic of the first table.
+		assertPayloadsStripTs(t, foobar, []string{
+			`foo: ->{"rows": [` +
+				`{"after": {"a": 0, "b": "initial"}, "key": [0], "topic": "bar"}, ` +
+				`{"after": {"a": 0, "b": "initial"}, "key": [0], "topic": "foo"}]}`,
+		})
+
+		tx, err := db.Begin()
+		require.NoError(t, err)
+		_, err = tx.Exec(`INSERT INTO foo VALUES (1, 'a')`)
+		require.NoError(t, err)
+		_, err = tx.Exec(`INSERT INTO bar VALUES (1, 'b')`)
+		require.NoError(t, err)
+		_, err = tx.Exec(`UPDATE foo SET b = 'updated' WHERE a = 0`)
+		require.NoError(t, err)
+		var commitTS string
+		require.NoError(t, tx.QueryRow(`SELECT cluster_logical_timestamp()`).Scan(&commitTS))
+		require.NoError(t, tx.Commit())
+
+		msgs, err := readNextMessages(foobar, 1)
+		require.NoError(t, err)
+		require.Equal(t, parseTimeToHLC(t, commitTS),
+			parseTimeToHLC(t, extractTransactionTimestamp(t, msgs[0].Value)))
+		actual, err := stripTsFromPayloads(msgs)
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			`foo: ->{"rows": [` +
+				`{"after": {"a": 1, "b": "b"}, "key": [1], "topic": "bar"}, ` +
+				`{"after": {"a": 0, "b": "updated"}, "key": [0], "topic": "foo"}, ` +
+				`{"after": {"a": 1, "b": "a"}, "key": [1], "topic": "foo"}]}`,
+		}, actual)
+
+		sqlDB.Exec(t, `DELETE FROM bar WHERE a = 0`)
+		sqlDB.Exec(t, `UPSERT INTO foo VALUES (2, 'c'), (3, 'd')`)
+		assertPayloadsStripTs(t, foobar, []string{
+			`foo: ->{"rows": [{"after": null, "key": [0], "topic": "bar"}]}`,
+			`foo: ->{"rows": [` +
+				`{"after": {"a": 2, "b": "c"}, "key": [2], "topic": "foo"}, ` +
+				`{"after": {"a": 3, "b": "d"}, "key": [3], "topic": "foo"}]}`,
+		})
+	}
+
+	t.Run(`sinkless`, sinklessTest(testFn))
+	t.Run(`enterprise`, enterpriseTest(testFn))
+	t.Run(`kafka`, kafkaTest(testFn))
+}
+
+func TestChangefeedTransactionEnvelopeScanBatches(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, db *gosql.DB, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(db)
+		sqlDB.Exec(t, `SET CLUSTER SETTING changefeed.transaction_envelope.scan_batch_size = 2`)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (0), (1), (2), (3), (4)`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH envelope=transaction`)
+		defer closeFeed(t, foo)
+
+		// The rows of the initial scan are emitted in batches, but the
+		// transactions committed after the scan aren't.
+		assertPayloadsStripTs(t, foo, []string{
+			`foo: ->{"rows": [` +
+				`{"after": {"a": 0}, "key": [0], "topic": "foo"}, ` +
+				`{"after": {"a": 1}, "key": [1], "topic": "foo"}]}`,
+			`foo: ->{"rows": [` +
+				`{"after": {"a": 2}, "key": [2], "topic": "foo"}, ` +
+				`{"after": {"a": 3}, "key": [3], "topic": "foo"}]}`,
+			`foo: ->{"rows": [{"after": {"a": 4}, "key": [4], "topic": "foo"}]}`,
+		})
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (5), (6), (7)`)
+		assertPayloadsStripTs(t, foo, []string{
+			`foo: ->{"rows": [` +
+				`{"after": {"a": 5}, "key": [5], "topic": "foo"}, ` +
+				`{"after": {"a": 6}, "key": [6], "topic": "foo"}, ` +
+				`{"after": {"a": 7}, "key": [7], "topic": "foo"}]}`,
+		})
+	}
+
+	t.Run(`sinkless`, sinklessTest(testFn))
+	t.Run(`enterprise`, enterpriseTest(testFn))
+	t.Run(`kafka`, kafkaTest(testFn))
+}
+
+func TestChangefeedTransactionEnvelopeMixedVersion(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{
+		Knobs: base.TestingKnobs{
+			Server: &server.TestingKnobs{
+				DisableAutomaticVersionUpgrade: 1,
+				BinaryVersionOverride: clusterversion.ByKey(
+					clusterversion.ChangefeedTransactionEnvelope - 1),
+			},
+		},
+	})
+	defer s.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY)`)
+
+	sqlDB.ExpectErr(
+		t, `must be finalized to use envelope=transaction`,
+		`EXPERIMENTAL CHANGEFEED FOR foo WITH envelope=transaction`,
+	)
+}
+
 func TestChangefeedFullTableName(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -1627,7 +1823,7 @@ func fetchDescVersionModificationTime(
 			if err := value.GetProto(&desc); err != nil {
 				t.Fatal(err)
 			}
-			if tableDesc, _, _, _ := descpb.FromDescriptorWithMVCCTimestamp(&desc, k.Timestamp); tableDesc != nil {
+			if tableDesc, _, _, _, _ := descpb.FromDescriptorWithMVCCTimestamp(&desc, k.Timestamp); tableDesc != nil {
 				if int(tableDesc.Version) == version {
 					return tableDesc.ModificationTime
 				}
@@ -3034,6 +3230,10 @@ func TestChangefeedErrors(t *testing.T) {
 		t, `unknown envelope: nope`,
 		`EXPERIMENTAL CHANGEFEED FOR foo WITH envelope=nope`,
 	)
+	sqlDB.ExpectErr(
+		t, `envelope=transaction is not supported with format=avro`,
+		`EXPERIMENTAL CHANGEFEED FOR foo WITH envelope=transaction, format=avro`,
+	)
 
 	sqlDB.ExpectErr(
 		t, `time: invalid duration "bar"`,
@@ -3075,6 +3275,39 @@ func TestChangefeedErrors(t *testing.T) {
 		t, `table "bar" does not exist`,
 		`EXPERIMENTAL CHANGEFEED FOR bar`,
 	)
+
+	sqlDB.ExpectErr(
+		t, `GROUP BY is not supported in changefeed queries`,
+		`CREATE CHANGEFEED AS SELECT a FROM foo GROUP BY a`,
+	)
+	sqlDB.ExpectErr(
+		t, `changefeed queries must select from a single table`,
+		`CREATE CHANGEFEED AS SELECT * FROM foo, foo AS bar`,
+	)
+	sqlDB.ExpectErr(
+		t, `table "bar" does not exist`,
+		`CREATE CHANGEFEED AS SELECT * FROM bar`,
+	)
+	sqlDB.ExpectErr(
+		t, `column "nope" does not exist`,
+		`CREATE CHANGEFEED AS SELECT nope FROM foo`,
+	)
+	sqlDB.ExpectErr(
+		t, `changefeed query has several columns named "a"`,
+		`CREATE CHANGEFEED AS SELECT a, a FROM foo`,
+	)
+	sqlDB.ExpectErr(
+		t, `aggregate functions are not allowed in CHANGEFEED`,
+		`CREATE CHANGEFEED AS SELECT sum(a) FROM foo`,
+	)
+	sqlDB.ExpectErr(
+		t, `volatile functions are not allowed in WHERE`,
+		`CREATE CHANGEFEED AS SELECT a FROM foo WHERE random() > 0.5`,
+	)
+	sqlDB.ExpectErr(
+		t, `format=native is not supported with changefeed queries`,
+		`CREATE CHANGEFEED WITH format=native AS SELECT a FROM foo`,
+	)
 	sqlDB.Exec(t, `CREATE SEQUENCE seq`)
 	sqlDB.ExpectErr(
 		t, `CHANGEFEED cannot target sequences: seq`,
@@ -3299,6 +3532,17 @@ func TestChangefeedErrors(t *testing.T) {
 		`CREATE CHANGEFEED FOR foo INTO $1 WITH diff, envelope='row'`, `kafka://nope`,
 	)
 
+	// format=parquet is only supported by cloud storage sinks, with envelope=wrapped.
+	sqlDB.ExpectErr(
+		t, `format=parquet is only supported by cloud storage sinks`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH format='parquet'`, `kafka://nope`,
+	)
+	sqlDB.ExpectErr(
+		t, `format=parquet is only usable with envelope=wrapped`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH format='parquet', envelope='row'`,
+		`nodelocal://0/bar`,
+	)
+
 	// WITH initial_scan and no_initial_scan disallowed
 	sqlDB.ExpectErr(
 		t, `cannot specify both initial_scan and no_initial_scan`,
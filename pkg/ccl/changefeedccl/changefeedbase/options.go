@@ -88,9 +88,18 @@ const (
 	OptEnvelopeRow           EnvelopeType = `row`
 	OptEnvelopeDeprecatedRow EnvelopeType = `deprecated_row`
 	OptEnvelopeWrapped       EnvelopeType = `wrapped`
+	// OptEnvelopeTransaction groups the rows committed at the same timestamp,
+	// i.e. by the same transaction, into a single message. Non-conflicting
+	// transactions which commit at the same timestamp share a message, and the
+	// rows of initial scans are emitted in batches. It is only supported with
+	// format=json.
+	OptEnvelopeTransaction EnvelopeType = `transaction`
 
 	OptFormatJSON FormatType = `json`
 	OptFormatAvro FormatType = `avro`
+	// OptFormatParquet writes rows to columnar Parquet files. It is only
+	// supported by cloud storage sinks.
+	OptFormatParquet FormatType = `parquet`
 
 	OptFormatNative FormatType = `native`
 
@@ -108,10 +117,16 @@ const (
 	// OptKafkaSinkConfig is a JSON configuration for kafka sink (kafkaSinkConfig).
 	OptKafkaSinkConfig   = `kafka_sink_config`
 	OptWebhookSinkConfig = `webhook_sink_config`
+	// OptNATSSinkConfig and OptPubsubSinkConfig are JSON configurations for
+	// the batching and retries of message queue sinks, with the same schema
+	// as OptWebhookSinkConfig.
+	OptNATSSinkConfig   = `nats_sink_config`
+	OptPubsubSinkConfig = `pubsub_sink_config`
 
 	SinkParamCACert                 = `ca_cert`
 	SinkParamClientCert             = `client_cert`
 	SinkParamClientKey              = `client_key`
+	SinkParamEndpoint               = `endpoint`
 	SinkParamFileSize               = `file_size`
 	SinkParamSchemaTopic            = `schema_topic`
 	SinkParamTLSEnabled             = `tls_enabled`
@@ -125,9 +140,11 @@ const (
 	SinkSchemeCloudStorageNodelocal = `nodelocal`
 	SinkSchemeCloudStorageS3        = `s3`
 	SinkSchemeExperimentalSQL       = `experimental-sql`
+	SinkSchemeGCPubsub              = `gcpubsub`
 	SinkSchemeHTTP                  = `http`
 	SinkSchemeHTTPS                 = `https`
 	SinkSchemeKafka                 = `kafka`
+	SinkSchemeNATS                  = `nats`
 	SinkSchemeNull                  = `null`
 	SinkSchemeWebhookHTTP           = `webhook-http`
 	SinkSchemeWebhookHTTPS          = `webhook-https`
@@ -163,6 +180,8 @@ var ChangefeedOptionExpectValues = map[string]sql.KVStringOptValidate{
 	OptProtectDataFromGCOnPause: sql.KVStringOptRequireNoValue,
 	OptKafkaSinkConfig:          sql.KVStringOptRequireValue,
 	OptWebhookSinkConfig:        sql.KVStringOptRequireValue,
+	OptNATSSinkConfig:           sql.KVStringOptRequireValue,
+	OptPubsubSinkConfig:         sql.KVStringOptRequireValue,
 	OptWebhookAuthHeader:        sql.KVStringOptRequireValue,
 	OptWebhookClientTimeout:     sql.KVStringOptRequireValue,
 	OptOnError:                  sql.KVStringOptRequireValue,
@@ -200,6 +219,12 @@ var CloudStorageValidOptions = makeStringSet(OptCompression)
 // WebhookValidOptions is options exclusive to webhook sink
 var WebhookValidOptions = makeStringSet(OptWebhookAuthHeader, OptWebhookClientTimeout, OptWebhookSinkConfig)
 
+// NATSValidOptions is options exclusive to NATS JetStream sink
+var NATSValidOptions = makeStringSet(OptNATSSinkConfig)
+
+// PubsubValidOptions is options exclusive to Google Cloud Pub/Sub sink
+var PubsubValidOptions = makeStringSet(OptPubsubSinkConfig)
+
 // CaseInsensitiveOpts options which supports case Insensitive value
 var CaseInsensitiveOpts = makeStringSet(OptFormat, OptEnvelope, OptCompression, OptSchemaChangeEvents, OptSchemaChangePolicy, OptOnError)
 
@@ -166,6 +166,16 @@ var EventMemoryMultiplier = settings.RegisterFloatSetting(
 	},
 )
 
+// TransactionScanBatchSize controls how many of the rows of an initial scan or
+// a backfill are emitted in each message by changefeeds with
+// envelope=transaction.
+var TransactionScanBatchSize = settings.RegisterIntSetting(
+	"changefeed.transaction_envelope.scan_batch_size",
+	"the maximum number of rows of an initial scan or backfill emitted in each message of changefeeds with envelope=transaction",
+	1000,
+	settings.PositiveInt,
+)
+
 // ProtectTimestampInterval controls the frequency of protected timestamp record updates
 var ProtectTimestampInterval = settings.RegisterDurationSetting(
 	"changefeed.protect_timestamp_interval",
@@ -11,6 +11,7 @@ package changefeeddist
 import (
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -30,6 +31,24 @@ var ChangefeedResultTypes = []*types.T{
 	types.Bytes,  // value
 }
 
+// TransactionChangefeedResultTypes is the types returned by the stream of
+// changefeeds with envelope=transaction, whose changed rows are grouped by
+// the timestamp at which they were committed.
+var TransactionChangefeedResultTypes = append(
+	ChangefeedResultTypes[:len(ChangefeedResultTypes):len(ChangefeedResultTypes)],
+	types.Decimal, // updated timestamp of changed rows
+)
+
+// ResultTypes returns the types returned by the stream of the given
+// changefeed.
+func ResultTypes(details jobspb.ChangefeedDetails) []*types.T {
+	if changefeedbase.EnvelopeType(details.Opts[changefeedbase.OptEnvelope]) ==
+		changefeedbase.OptEnvelopeTransaction {
+		return TransactionChangefeedResultTypes
+	}
+	return ChangefeedResultTypes
+}
+
 // StartDistChangefeed starts distributed changefeed execution.
 func StartDistChangefeed(
 	ctx context.Context,
@@ -101,13 +120,14 @@ func StartDistChangefeed(
 		UserProto:    execCtx.User().EncodeProto(),
 	}
 
+	resultTypes := ResultTypes(details)
 	p := planCtx.NewPhysicalPlan()
-	p.AddNoInputStage(corePlacement, execinfrapb.PostProcessSpec{}, ChangefeedResultTypes, execinfrapb.Ordering{})
+	p.AddNoInputStage(corePlacement, execinfrapb.PostProcessSpec{}, resultTypes, execinfrapb.Ordering{})
 	p.AddSingleGroupStage(
 		dsp.GatewayID(),
 		execinfrapb.ProcessorCoreUnion{ChangeFrontier: &changeFrontierSpec},
 		execinfrapb.PostProcessSpec{},
-		ChangefeedResultTypes,
+		resultTypes,
 	)
 
 	p.PlanToStreamColMap = []int{1, 2, 3}
@@ -14,6 +14,7 @@ import (
 	"encoding/binary"
 	gojson "encoding/json"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
@@ -60,6 +61,18 @@ type encodeRow struct {
 	// prevTableDesc is a TableDescriptor for the table containing `prevDatums`.
 	// It's valid for interpreting the row at `updated.Prev()`.
 	prevTableDesc catalog.TableDescriptor
+	// source is set for the rows projected by changefeed queries, in which
+	// case it's the row of the table that the projection was computed from,
+	// whose primary key is the key of the projected row.
+	source *encodeRow
+}
+
+// keyRow returns the row holding the primary key of the given row.
+func (r encodeRow) keyRow() encodeRow {
+	if r.source != nil {
+		return *r.source
+	}
+	return r
 }
 
 // Encoder turns a row into a serialized changefeed key, value, or resolved
@@ -81,6 +94,16 @@ type Encoder interface {
 	EncodeResolvedTimestamp(context.Context, string, hlc.Timestamp) ([]byte, error)
 }
 
+// transactionEncoder is implemented by the Encoders which support
+// envelope=transaction, where the rows committed by a transaction are emitted
+// as a single message.
+type transactionEncoder interface {
+	// EncodeTransaction encodes the values of the rows committed at the given
+	// timestamp, as returned by EncodeValue, into a single payload. The
+	// returned bytes are only valid until the next call to Encode*.
+	EncodeTransaction(context.Context, hlc.Timestamp, [][]byte) ([]byte, error)
+}
+
 func getEncoder(opts map[string]string, targets jobspb.ChangefeedTargets) (Encoder, error) {
 	switch changefeedbase.FormatType(opts[changefeedbase.OptFormat]) {
 	case ``, changefeedbase.OptFormatJSON:
@@ -89,6 +112,8 @@ func getEncoder(opts map[string]string, targets jobspb.ChangefeedTargets) (Encod
 		return newConfluentAvroEncoder(opts, targets)
 	case changefeedbase.OptFormatNative:
 		return &nativeEncoder{}, nil
+	case changefeedbase.OptFormatParquet:
+		return makeParquetEncoder(opts)
 	default:
 		return nil, errors.Errorf(`unknown %s: %s`, changefeedbase.OptFormat, opts[changefeedbase.OptFormat])
 	}
@@ -100,6 +125,10 @@ func getEncoder(opts map[string]string, targets jobspb.ChangefeedTargets) (Encod
 // stored in a sub-object under the `__crdb__` key in the top-level JSON object.
 type jsonEncoder struct {
 	updatedField, mvccTimestampField, beforeField, wrapped, keyOnly, keyInValue, topicInValue bool
+	// transaction is set for envelope=transaction, in which case rows are
+	// encoded as with the wrapped envelope, along with their key and topic,
+	// and then grouped by EncodeTransaction.
+	transaction bool
 
 	targets jobspb.ChangefeedTargets
 	alloc   rowenc.DatumAlloc
@@ -107,6 +136,7 @@ type jsonEncoder struct {
 }
 
 var _ Encoder = &jsonEncoder{}
+var _ transactionEncoder = &jsonEncoder{}
 
 func makeJSONEncoder(
 	opts map[string]string, targets jobspb.ChangefeedTargets,
@@ -115,6 +145,11 @@ func makeJSONEncoder(
 		targets: targets,
 		keyOnly: changefeedbase.EnvelopeType(opts[changefeedbase.OptEnvelope]) == changefeedbase.OptEnvelopeKeyOnly,
 		wrapped: changefeedbase.EnvelopeType(opts[changefeedbase.OptEnvelope]) == changefeedbase.OptEnvelopeWrapped,
+		transaction: changefeedbase.EnvelopeType(opts[changefeedbase.OptEnvelope]) ==
+			changefeedbase.OptEnvelopeTransaction,
+	}
+	if e.transaction {
+		e.wrapped, e.keyInValue, e.topicInValue = true, true, true
 	}
 	_, e.updatedField = opts[changefeedbase.OptUpdatedTimestamps]
 	_, e.mvccTimestampField = opts[changefeedbase.OptMVCCTimestamps]
@@ -123,12 +158,16 @@ func makeJSONEncoder(
 		return nil, errors.Errorf(`%s is only usable with %s=%s`,
 			changefeedbase.OptDiff, changefeedbase.OptEnvelope, changefeedbase.OptEnvelopeWrapped)
 	}
-	_, e.keyInValue = opts[changefeedbase.OptKeyInValue]
+	if _, ok := opts[changefeedbase.OptKeyInValue]; ok {
+		e.keyInValue = true
+	}
 	if e.keyInValue && !e.wrapped {
 		return nil, errors.Errorf(`%s is only usable with %s=%s`,
 			changefeedbase.OptKeyInValue, changefeedbase.OptEnvelope, changefeedbase.OptEnvelopeWrapped)
 	}
-	_, e.topicInValue = opts[changefeedbase.OptTopicInValue]
+	if _, ok := opts[changefeedbase.OptTopicInValue]; ok {
+		e.topicInValue = true
+	}
 	if e.topicInValue && !e.wrapped {
 		return nil, errors.Errorf(`%s is only usable with %s=%s`,
 			changefeedbase.OptTopicInValue, changefeedbase.OptEnvelope, changefeedbase.OptEnvelopeWrapped)
@@ -152,6 +191,7 @@ func (e *jsonEncoder) EncodeKey(_ context.Context, row encodeRow) ([]byte, error
 }
 
 func (e *jsonEncoder) encodeKeyRaw(row encodeRow) ([]interface{}, error) {
+	row = row.keyRow()
 	colIdxByID := catalog.ColumnIDToOrdinalMap(row.tableDesc.PublicColumns())
 	primaryIndex := row.tableDesc.GetPrimaryIndex()
 	jsonEntries := make([]interface{}, primaryIndex.NumKeyColumns())
@@ -295,6 +335,31 @@ func (e *jsonEncoder) EncodeValue(_ context.Context, row encodeRow) ([]byte, err
 	return e.buf.Bytes(), nil
 }
 
+// EncodeTransaction implements the transactionEncoder interface. The message
+// is a JSON object with the rows of the transaction under the `rows` key, as
+// an array of the values encoded by EncodeValue, and the commit timestamp of
+// the transaction under the `updated` key.
+func (e *jsonEncoder) EncodeTransaction(
+	_ context.Context, updated hlc.Timestamp, values [][]byte,
+) ([]byte, error) {
+	if !e.transaction {
+		return nil, errors.AssertionFailedf(`%s=%s is required to encode transactions`,
+			changefeedbase.OptEnvelope, changefeedbase.OptEnvelopeTransaction)
+	}
+	e.buf.Reset()
+	e.buf.WriteString(`{"rows": [`)
+	for i, value := range values {
+		if i > 0 {
+			e.buf.WriteString(`, `)
+		}
+		e.buf.Write(value)
+	}
+	e.buf.WriteString(`], "updated": `)
+	e.buf.WriteString(strconv.Quote(updated.AsOfSystemTime()))
+	e.buf.WriteString(`}`)
+	return e.buf.Bytes(), nil
+}
+
 // EncodeResolvedTimestamp implements the Encoder interface.
 func (e *jsonEncoder) EncodeResolvedTimestamp(
 	_ context.Context, _ string, resolved hlc.Timestamp,
@@ -406,6 +471,7 @@ func (e *confluentAvroEncoder) rawTableName(desc catalog.TableDescriptor) string
 
 // EncodeKey implements the Encoder interface.
 func (e *confluentAvroEncoder) EncodeKey(ctx context.Context, row encodeRow) ([]byte, error) {
+	row = row.keyRow()
 	cacheKey := makeTableIDAndVersion(row.tableDesc.GetID(), row.tableDesc.GetVersion())
 
 	registered, ok := e.keyCache[cacheKey]
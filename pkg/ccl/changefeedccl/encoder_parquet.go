@@ -0,0 +1,174 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/parquet"
+	"github.com/cockroachdb/errors"
+)
+
+// Names of the columns that parquetEncoder adds to the columns of a table.
+const (
+	parquetDeletedColumn       = `__crdb__deleted`
+	parquetUpdatedColumn       = `__crdb__updated`
+	parquetMVCCTimestampColumn = `__crdb__mvcc_timestamp`
+	parquetBeforeColumn        = `__crdb__before`
+)
+
+// parquetEncoder encodes changefeed rows as the rows of Parquet files. Unlike
+// the other encoders, rows are not serialized one at a time: the cloud storage
+// sink buffers them into a parquet.Writer per file, and the encoder only
+// produces the columns of a table and the values of its rows. Each file holds
+// the public columns of the table, followed by:
+//
+//   - `__crdb__deleted`, set for deleted rows, which only hold the primary key.
+//   - `__crdb__updated` and `__crdb__mvcc_timestamp` with the `updated` and
+//     `mvcc_timestamp` options.
+//   - `__crdb__before` with the `diff` option, a struct of the columns of the
+//     table holding the previous value of the row, or NULL if there was none.
+//
+// Resolved timestamps are written as JSON, like with format=json.
+type parquetEncoder struct {
+	updatedField, mvccTimestampField, beforeField bool
+
+	alloc rowenc.DatumAlloc
+}
+
+var _ Encoder = &parquetEncoder{}
+
+func makeParquetEncoder(opts map[string]string) (*parquetEncoder, error) {
+	if changefeedbase.EnvelopeType(opts[changefeedbase.OptEnvelope]) != changefeedbase.OptEnvelopeWrapped {
+		return nil, errors.Errorf(`%s=%s is only usable with %s=%s`,
+			changefeedbase.OptFormat, changefeedbase.OptFormatParquet,
+			changefeedbase.OptEnvelope, changefeedbase.OptEnvelopeWrapped)
+	}
+	if _, ok := opts[changefeedbase.OptTopicInValue]; ok {
+		return nil, errors.Errorf(`%s is not supported with %s=%s`,
+			changefeedbase.OptTopicInValue, changefeedbase.OptFormat, changefeedbase.OptFormatParquet)
+	}
+	e := &parquetEncoder{}
+	_, e.updatedField = opts[changefeedbase.OptUpdatedTimestamps]
+	_, e.mvccTimestampField = opts[changefeedbase.OptMVCCTimestamps]
+	_, e.beforeField = opts[changefeedbase.OptDiff]
+	return e, nil
+}
+
+// EncodeKey implements the Encoder interface.
+func (e *parquetEncoder) EncodeKey(context.Context, encodeRow) ([]byte, error) {
+	return nil, errors.AssertionFailedf("EncodeKey unexpectedly called on parquetEncoder")
+}
+
+// EncodeValue implements the Encoder interface.
+func (e *parquetEncoder) EncodeValue(context.Context, encodeRow) ([]byte, error) {
+	return nil, errors.AssertionFailedf("EncodeValue unexpectedly called on parquetEncoder")
+}
+
+// EncodeResolvedTimestamp implements the Encoder interface.
+func (e *parquetEncoder) EncodeResolvedTimestamp(
+	ctx context.Context, topic string, resolved hlc.Timestamp,
+) ([]byte, error) {
+	resolvedEncoder := jsonEncoder{wrapped: true}
+	return resolvedEncoder.EncodeResolvedTimestamp(ctx, topic, resolved)
+}
+
+// columns returns the columns of the files holding the rows of the given
+// version of a table.
+func (e *parquetEncoder) columns(desc catalog.TableDescriptor) []parquet.Column {
+	tableCols := desc.PublicColumns()
+	cols := make([]parquet.Column, 0, len(tableCols)+4)
+	for _, col := range tableCols {
+		cols = append(cols, parquet.NewColumn(col.GetName(), col.GetType()))
+	}
+	cols = append(cols, parquet.Column{Name: parquetDeletedColumn, Type: parquet.Boolean})
+	timestampColumn := parquet.Column{
+		Type: parquet.ByteArray, Logical: parquet.LogicalType{Kind: parquet.LogicalString},
+	}
+	if e.updatedField {
+		timestampColumn.Name = parquetUpdatedColumn
+		cols = append(cols, timestampColumn)
+	}
+	if e.mvccTimestampField {
+		timestampColumn.Name = parquetMVCCTimestampColumn
+		cols = append(cols, timestampColumn)
+	}
+	if e.beforeField {
+		fields := make([]parquet.Column, len(tableCols))
+		copy(fields, cols)
+		cols = append(cols, parquet.Column{Name: parquetBeforeColumn, Fields: fields})
+	}
+	return cols
+}
+
+// values returns the values of a row, for the columns returned by columns
+// for the table descriptor of the row.
+func (e *parquetEncoder) values(row encodeRow) ([]interface{}, error) {
+	tableCols := row.tableDesc.PublicColumns()
+	values := make([]interface{}, 0, len(tableCols)+4)
+	for i, col := range tableCols {
+		datum := row.datums[i]
+		if err := datum.EnsureDecoded(col.GetType(), &e.alloc); err != nil {
+			return nil, err
+		}
+		v, err := parquet.DatumToValue(datum.Datum, col.GetType())
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	values = append(values, row.deleted)
+	if e.updatedField {
+		values = append(values, []byte(row.updated.AsOfSystemTime()))
+	}
+	if e.mvccTimestampField {
+		values = append(values, []byte(row.mvccTimestamp.AsOfSystemTime()))
+	}
+	if e.beforeField {
+		before, err := e.beforeValues(row)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, before)
+	}
+	return values, nil
+}
+
+// beforeValues returns the value of the `__crdb__before` column of a row. The
+// previous value of a row may have been written with an older version of the
+// table, so its columns are matched to the columns of the current version by
+// ID. Columns which did not exist, or whose type changed, are NULL.
+func (e *parquetEncoder) beforeValues(row encodeRow) (interface{}, error) {
+	if row.prevDatums == nil || row.prevDeleted {
+		return nil, nil
+	}
+	prevCols := row.prevTableDesc.PublicColumns()
+	prevIdxByID := catalog.ColumnIDToOrdinalMap(prevCols)
+	tableCols := row.tableDesc.PublicColumns()
+	fields := make([]interface{}, len(tableCols))
+	for i, col := range tableCols {
+		idx, ok := prevIdxByID.Get(col.GetID())
+		if !ok || !prevCols[idx].GetType().Identical(col.GetType()) {
+			continue
+		}
+		datum := row.prevDatums[idx]
+		if err := datum.EnsureDecoded(col.GetType(), &e.alloc); err != nil {
+			return nil, err
+		}
+		var err error
+		if fields[i], err = parquet.DatumToValue(datum.Datum, col.GetType()); err != nil {
+			return nil, err
+		}
+	}
+	return fields, nil
+}
@@ -133,6 +133,17 @@ func extractUpdatedFromValue(value []byte) (float64, error) {
 
 }
 
+// extractTransactionTimestamp returns the commit timestamp of a transaction
+// emitted with envelope=transaction.
+func extractTransactionTimestamp(t testing.TB, value []byte) string {
+	t.Helper()
+	var txn struct {
+		Updated string `json:"updated"`
+	}
+	require.NoError(t, gojson.Unmarshal(value, &txn))
+	return txn.Updated
+}
+
 func checkPerKeyOrdering(payloads []cdctest.TestFeedMessage) (bool, error) {
 	// map key to list of timestamp, ensure each list is ordered
 	keysToTimestamps := make(map[string][]float64)
@@ -66,6 +66,16 @@ type Sink interface {
 	Close() error
 }
 
+// rowSink is implemented by sinks which encode rows themselves, rather than
+// emitting the keys and values produced by an Encoder. This is the case of
+// the cloud storage sink for format=parquet, which buffers rows into columnar
+// files.
+type rowSink interface {
+	// EmitDatumRow enqueues a row for asynchronous delivery on the sink, like
+	// EmitRow.
+	EmitDatumRow(ctx context.Context, topic TopicDescriptor, row encodeRow, alloc kvevent.Alloc) error
+}
+
 func getSink(
 	ctx context.Context,
 	serverCfg *execinfra.ServerConfig,
@@ -93,8 +103,13 @@ func getSink(
 	}
 
 	newSink := func() (Sink, error) {
+		if changefeedbase.FormatType(feedCfg.Opts[changefeedbase.OptFormat]) == changefeedbase.OptFormatParquet &&
+			!isCloudStorageSink(u) {
+			return nil, errors.Errorf(`%s=%s is only supported by cloud storage sinks`,
+				changefeedbase.OptFormat, changefeedbase.OptFormatParquet)
+		}
 		if feedCfg.SinkURI == "" {
-			return &bufferSink{metrics: m}, nil
+			return &bufferSink{metrics: m, withUpdated: isTransactionEnvelope(feedCfg)}, nil
 		}
 
 		switch {
@@ -116,6 +131,16 @@ func getSink(
 					feedCfg.Opts, timestampOracle, serverCfg.ExternalStorageFromURI, user, m,
 				)
 			})
+		case u.Scheme == changefeedbase.SinkSchemeNATS:
+			return validateOptionsAndMakeSink(changefeedbase.NATSValidOptions, func() (Sink, error) {
+				return makeNATSSink(ctx, sinkURL{URL: u}, feedCfg.Targets, feedCfg.Opts,
+					defaultWorkerCount(), timeutil.DefaultTimeSource{}, m)
+			})
+		case u.Scheme == changefeedbase.SinkSchemeGCPubsub:
+			return validateOptionsAndMakeSink(changefeedbase.PubsubValidOptions, func() (Sink, error) {
+				return makePubsubSink(ctx, sinkURL{URL: u}, feedCfg.Targets, feedCfg.Opts,
+					defaultWorkerCount(), timeutil.DefaultTimeSource{}, m)
+			})
 		case u.Scheme == changefeedbase.SinkSchemeExperimentalSQL:
 			return validateOptionsAndMakeSink(changefeedbase.SQLValidOptions, func() (Sink, error) {
 				return makeSQLSink(sinkURL{URL: u}, sqlSinkTableName, feedCfg.Targets, m)
@@ -237,6 +262,20 @@ func (s errorWrapperSink) EmitRow(
 	return nil
 }
 
+// EmitDatumRow implements rowSink interface.
+func (s errorWrapperSink) EmitDatumRow(
+	ctx context.Context, topic TopicDescriptor, row encodeRow, alloc kvevent.Alloc,
+) error {
+	rs, ok := s.wrapped.(rowSink)
+	if !ok {
+		return errors.AssertionFailedf("sink %T does not support emitting rows", s.wrapped)
+	}
+	if err := rs.EmitDatumRow(ctx, topic, row, alloc); err != nil {
+		return changefeedbase.MarkRetryableError(err)
+	}
+	return nil
+}
+
 // EmitResolvedTimestamp implements Sink interface.
 func (s errorWrapperSink) EmitResolvedTimestamp(
 	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
@@ -292,6 +331,9 @@ type bufferSink struct {
 	scratch bufalloc.ByteAllocator
 	closed  bool
 	metrics *sliMetrics
+	// withUpdated is set if the rows have an updated column, i.e. with
+	// envelope=transaction. See changefeeddist.ResultTypes.
+	withUpdated bool
 }
 
 // EmitRow implements the Sink interface.
@@ -308,12 +350,16 @@ func (s *bufferSink) EmitRow(
 	if s.closed {
 		return errors.New(`cannot EmitRow on a closed sink`)
 	}
-	s.buf.Push(rowenc.EncDatumRow{
+	row := rowenc.EncDatumRow{
 		{Datum: tree.DNull}, // resolved span
 		{Datum: s.alloc.NewDString(tree.DString(topic.GetName()))}, // topic
 		{Datum: s.alloc.NewDBytes(tree.DBytes(key))},               // key
 		{Datum: s.alloc.NewDBytes(tree.DBytes(value))},             // value
-	})
+	}
+	if s.withUpdated {
+		row = append(row, rowenc.EncDatum{Datum: tree.TimestampToDecimalDatum(updated)}) // updated
+	}
+	s.buf.Push(row)
 	return nil
 }
 
@@ -332,12 +378,16 @@ func (s *bufferSink) EmitResolvedTimestamp(
 		return err
 	}
 	s.scratch, payload = s.scratch.Copy(payload, 0 /* extraCap */)
-	s.buf.Push(rowenc.EncDatumRow{
+	row := rowenc.EncDatumRow{
 		{Datum: tree.DNull}, // resolved span
 		{Datum: tree.DNull}, // topic
 		{Datum: tree.DNull}, // key
 		{Datum: s.alloc.NewDBytes(tree.DBytes(payload))}, // value
-	})
+	}
+	if s.withUpdated {
+		row = append(row, rowenc.EncDatum{Datum: tree.DNull}) // updated
+	}
+	s.buf.Push(row)
 	return nil
 }
 
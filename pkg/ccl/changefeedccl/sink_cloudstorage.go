@@ -31,6 +31,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/parquet"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 	"github.com/google/btree"
@@ -66,6 +67,8 @@ type cloudStorageSinkFile struct {
 	buf         bytes.Buffer
 	alloc       kvevent.Alloc
 	oldestMVCC  hlc.Timestamp
+	// parquet is set for files of format=parquet, and writes to buf.
+	parquet *parquet.Writer
 }
 
 var _ io.Writer = &cloudStorageSinkFile{}
@@ -351,12 +354,15 @@ func makeCloudStorageSink(
 		s.dataFilePartition = timestampOracle.inclusiveLowerBoundTS().GoTime().Format(s.partitionFormat)
 	}
 
-	switch changefeedbase.FormatType(opts[changefeedbase.OptFormat]) {
+	format := changefeedbase.FormatType(opts[changefeedbase.OptFormat])
+	switch format {
 	case changefeedbase.OptFormatJSON:
 		// TODO(dan): It seems like these should be on the encoder, but that
 		// would require a bit of refactoring.
 		s.ext = `.ndjson`
 		s.rowDelimiter = []byte{'\n'}
+	case changefeedbase.OptFormatParquet:
+		s.ext = `.parquet`
 	default:
 		return nil, errors.Errorf(`this sink is incompatible with %s=%s`,
 			changefeedbase.OptFormat, opts[changefeedbase.OptFormat])
@@ -373,8 +379,19 @@ func makeCloudStorageSink(
 		return nil, errors.Errorf(`this sink requires the WITH %s option`, changefeedbase.OptKeyInValue)
 	}
 
+	parquetCodec := parquet.CompressionNone
 	if codec, ok := opts[changefeedbase.OptCompression]; ok && codec != "" {
-		if strings.EqualFold(codec, "gzip") {
+		// Parquet files compress their pages rather than the whole file.
+		if format == changefeedbase.OptFormatParquet {
+			switch {
+			case strings.EqualFold(codec, "gzip"):
+				parquetCodec = parquet.CompressionGZIP
+			case strings.EqualFold(codec, "snappy"):
+				parquetCodec = parquet.CompressionSnappy
+			default:
+				return nil, errors.Errorf(`unsupported compression codec %q`, codec)
+			}
+		} else if strings.EqualFold(codec, "gzip") {
 			s.compression = sinkCompressionGzip
 			s.ext = s.ext + ".gz"
 		} else {
@@ -387,6 +404,14 @@ func makeCloudStorageSink(
 		return nil, err
 	}
 
+	if format == changefeedbase.OptFormatParquet {
+		encoder, err := makeParquetEncoder(opts)
+		if err != nil {
+			_ = s.es.Close()
+			return nil, err
+		}
+		return &parquetCloudStorageSink{cloudStorageSink: s, encoder: encoder, codec: parquetCodec}, nil
+	}
 	return s, nil
 }
 
@@ -542,6 +567,11 @@ func (s *cloudStorageSink) flushFile(ctx context.Context, file *cloudStorageSink
 		return nil
 	}
 
+	if file.parquet != nil {
+		if err := file.parquet.Close(); err != nil {
+			return err
+		}
+	}
 	if file.codec != nil {
 		if err := file.codec.Close(); err != nil {
 			return err
@@ -605,3 +635,66 @@ func keyLess(a, b cloudStorageSinkKey) bool {
 	}
 	return a.topic < b.topic
 }
+
+// parquetCloudStorageSink is the cloud storage sink for format=parquet. Rows
+// are buffered per topic and schema version into a Parquet file, which is
+// written once its size reaches the file_size of the sink.
+type parquetCloudStorageSink struct {
+	*cloudStorageSink
+	encoder *parquetEncoder
+	codec   parquet.CompressionCodec
+}
+
+var _ rowSink = (*parquetCloudStorageSink)(nil)
+
+// EmitRow implements the Sink interface.
+func (s *parquetCloudStorageSink) EmitRow(
+	context.Context, TopicDescriptor, []byte, []byte, hlc.Timestamp, hlc.Timestamp, kvevent.Alloc,
+) error {
+	return errors.AssertionFailedf("EmitRow unexpectedly called on a %s=%s sink",
+		changefeedbase.OptFormat, changefeedbase.OptFormatParquet)
+}
+
+// EmitDatumRow implements the rowSink interface.
+func (s *parquetCloudStorageSink) EmitDatumRow(
+	ctx context.Context, topic TopicDescriptor, row encodeRow, alloc kvevent.Alloc,
+) error {
+	if s.files == nil {
+		return errors.New(`cannot EmitRow on a closed sink`)
+	}
+
+	values, err := s.encoder.values(row)
+	if err != nil {
+		return err
+	}
+	file := s.getOrCreateFile(topic, row.mvccTimestamp)
+	file.alloc.Merge(&alloc)
+	if file.parquet == nil {
+		// Files are keyed by the version of the table, so all the rows of a
+		// file have the same columns.
+		file.parquet, err = parquet.NewWriter(
+			&file.buf, s.encoder.columns(row.tableDesc), parquet.WithCompressionCodec(s.codec))
+		if err != nil {
+			return err
+		}
+	}
+
+	prevSize := file.parquet.Size()
+	if err := file.parquet.AddRow(values); err != nil {
+		return err
+	}
+	file.numMessages++
+	// The size of the file can shrink when a row group is flushed and
+	// compressed, in which case the row is not accounted for.
+	if size := file.parquet.Size() - prevSize; size > 0 {
+		s.metrics.recordMessageSize(size)
+		file.rawSize += int(size)
+	}
+
+	if file.parquet.Size() > s.targetMaxFileSize {
+		if err := s.flushTopicVersions(ctx, file.topic, file.schemaID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
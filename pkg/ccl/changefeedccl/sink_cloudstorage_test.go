@@ -33,10 +33,13 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/parquet"
 	"github.com/cockroachdb/cockroach/pkg/util/span"
 	"github.com/stretchr/testify/require"
 )
@@ -584,4 +587,121 @@ func TestCloudStorageSink(t *testing.T) {
 			"w1\n",
 		}, slurpDir(t, dir))
 	})
+
+	t.Run(`parquet`, func(t *testing.T) {
+		tableDesc, err := parseTableDesc(`CREATE TABLE t1 (a INT PRIMARY KEY, b STRING)`)
+		require.NoError(t, err)
+		t1 := tableDescriptorTopic{tableDesc}
+		parquetOpts := map[string]string{
+			changefeedbase.OptFormat:            string(changefeedbase.OptFormatParquet),
+			changefeedbase.OptEnvelope:          string(changefeedbase.OptEnvelopeWrapped),
+			changefeedbase.OptKeyInValue:        ``,
+			changefeedbase.OptUpdatedTimestamps: ``,
+			changefeedbase.OptMVCCTimestamps:    ``,
+			changefeedbase.OptDiff:              ``,
+			changefeedbase.OptCompression:       `snappy`,
+		}
+
+		// readParquet returns the rows of every parquet file under root, in the
+		// order of the files. Values are formatted as in EXPORT, and structs as
+		// {field,...}.
+		readParquet := func(t *testing.T, root string) [][]string {
+			paths, err := filepath.Glob(filepath.Join(dir, root, `*`, `*.parquet`))
+			require.NoError(t, err)
+			sort.Strings(paths)
+			var format func(v interface{}, col *parquet.Column) string
+			format = func(v interface{}, col *parquet.Column) string {
+				if v != nil && col.Fields != nil {
+					fields := make([]string, len(col.Fields))
+					for i, f := range v.([]interface{}) {
+						fields[i] = format(f, &col.Fields[i])
+					}
+					return `{` + strings.Join(fields, `,`) + `}`
+				}
+				d, err := parquet.ValueToDatum(v, col)
+				require.NoError(t, err)
+				return tree.AsStringWithFlags(d, tree.FmtExport)
+			}
+			var rows [][]string
+			for _, path := range paths {
+				data, err := ioutil.ReadFile(path)
+				require.NoError(t, err)
+				r, err := parquet.NewReader(bytes.NewReader(data), int64(len(data)))
+				require.NoError(t, err)
+				cols := r.Columns()
+				for i := 0; i < r.NumRowGroups(); i++ {
+					values, err := r.ReadRowGroup(i)
+					require.NoError(t, err)
+					for _, row := range values {
+						formatted := make([]string, len(row))
+						for j, v := range row {
+							formatted[j] = format(v, &cols[j])
+						}
+						rows = append(rows, formatted)
+					}
+				}
+			}
+			return rows
+		}
+
+		datums := func(a int, b tree.Datum) rowenc.EncDatumRow {
+			return rowenc.EncDatumRow{{Datum: tree.NewDInt(tree.DInt(a))}, {Datum: b}}
+		}
+		x, y := tree.NewDString(`x`), tree.NewDString(`y`)
+		rows := []encodeRow{
+			{datums: datums(1, x), updated: ts(1), mvccTimestamp: ts(1)},
+			{datums: datums(1, y), updated: ts(2), mvccTimestamp: ts(2), prevDatums: datums(1, x)},
+			{datums: datums(1, tree.DNull), deleted: true, updated: ts(3), mvccTimestamp: ts(3),
+				prevDatums: datums(1, y)},
+		}
+		expected := [][]string{
+			{`1`, `x`, `false`, `1.0000000000`, `1.0000000000`, `NULL`},
+			{`1`, `y`, `false`, `2.0000000000`, `2.0000000000`, `{1,x}`},
+			{`1`, `NULL`, `true`, `3.0000000000`, `3.0000000000`, `{1,y}`},
+		}
+
+		for _, tc := range []struct {
+			dir         string
+			maxFileSize int64
+			numFiles    int
+		}{
+			{dir: `parquet`, maxFileSize: unlimitedFileSize, numFiles: 1},
+			// Every row exceeds the file size, so each is flushed to its own file.
+			{dir: `parquet-file-size`, maxFileSize: 1, numFiles: 3},
+		} {
+			t.Run(tc.dir, func(t *testing.T) {
+				testSpan := roachpb.Span{Key: []byte("a"), EndKey: []byte("b")}
+				sf, err := span.MakeFrontier(testSpan)
+				require.NoError(t, err)
+				timestampOracle := &changeAggregatorLowerBoundOracle{sf: sf}
+				s, err := makeCloudStorageSink(
+					ctx, sinkURI(tc.dir, tc.maxFileSize), 1, settings,
+					parquetOpts, timestampOracle, externalStorageFromURI, user, nil,
+				)
+				require.NoError(t, err)
+				defer func() { require.NoError(t, s.Close()) }()
+
+				require.Error(t, s.EmitRow(ctx, t1, noKey, []byte(`v1`), ts(1), ts(1), zeroAlloc))
+				for _, row := range rows {
+					row.tableDesc, row.prevTableDesc = tableDesc, tableDesc
+					require.NoError(t, s.(rowSink).EmitDatumRow(ctx, t1, row, zeroAlloc))
+				}
+				require.NoError(t, s.Flush(ctx))
+				files, err := filepath.Glob(filepath.Join(dir, tc.dir, `*`, `*.parquet`))
+				require.NoError(t, err)
+				require.Len(t, files, tc.numFiles)
+				require.Equal(t, expected, readParquet(t, tc.dir))
+			})
+		}
+
+		topicInValue := map[string]string{changefeedbase.OptTopicInValue: ``}
+		for k, v := range parquetOpts {
+			topicInValue[k] = v
+		}
+		_, err = makeCloudStorageSink(
+			ctx, sinkURI(`parquet-error`, unlimitedFileSize), 1, settings,
+			topicInValue, nil /* timestampOracle */, externalStorageFromURI, user, nil,
+		)
+		require.EqualError(t, err, `topic_in_value is not supported with format=parquet`)
+	})
 }
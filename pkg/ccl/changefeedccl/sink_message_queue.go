@@ -0,0 +1,369 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"hash/crc32"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// mqMessage is a message published to a message queue.
+type mqMessage struct {
+	key, value []byte
+	alloc      kvevent.Alloc
+	emitTime   time.Time
+	mvcc       hlc.Timestamp
+}
+
+// messageQueueClient publishes messages to a message queue, such as NATS
+// JetStream or Google Cloud Pub/Sub. It is the pluggable part of a
+// messageQueueSink, which takes care of batching, ordering and retries.
+type messageQueueClient interface {
+	// dial checks the configuration of the client, and connects to the
+	// message queue if the client holds a connection.
+	dial(ctx context.Context) error
+	// publish delivers messages to a topic in order, and returns once the
+	// message queue acknowledged all of them. If an error is returned, some
+	// of the messages may have been delivered. It may be called concurrently.
+	publish(ctx context.Context, topic string, msgs []mqMessage) error
+	// close releases the resources of the client.
+	close() error
+}
+
+// messageQueueSink emits to a message queue through a messageQueueClient.
+// Rows are assigned to one of parallelism workers based on the hash of their
+// key, and each worker publishes its messages in batches, one batch at a time,
+// which guarantees per-key ordering. Batches which fail to publish are retried
+// with backoff according to the retry configuration of the sink; once the
+// retries are exhausted, the error is returned to the changefeed, which fails
+// or pauses according to its on_error option.
+type messageQueueSink struct {
+	client      messageQueueClient
+	topics      map[descpb.ID]string
+	parallelism int
+	batchCfg    batchConfig
+	retryCfg    retry.Options
+	ts          timeutil.TimeSource
+	metrics     *sliMetrics
+
+	// The workers run in the workerGroup, with workerCtx. Each worker reads
+	// its own events channel.
+	workerCtx   context.Context
+	workerGroup ctxgroup.Group
+	exitWorkers func()
+	eventsChans []chan mqEvent
+
+	mu struct {
+		syncutil.Mutex
+		// err is the first error encountered by a worker.
+		err error
+	}
+}
+
+// mqEvent is either a message to publish or, if flushed is set, a flush
+// request, which the worker acknowledges by closing flushed once every
+// message it received before it was published.
+type mqEvent struct {
+	topic   string
+	msg     mqMessage
+	flushed chan struct{}
+}
+
+func makeMessageQueueSink(
+	ctx context.Context,
+	client messageQueueClient,
+	u sinkURL,
+	targets jobspb.ChangefeedTargets,
+	opts map[string]string,
+	configOpt string,
+	parallelism int,
+	source timeutil.TimeSource,
+	m *sliMetrics,
+) (*messageQueueSink, error) {
+	switch changefeedbase.FormatType(opts[changefeedbase.OptFormat]) {
+	case changefeedbase.OptFormatJSON:
+	// Keys are used as ordering keys and headers, which must be text.
+	default:
+		return nil, errors.Errorf(`this sink is incompatible with %s=%s`,
+			changefeedbase.OptFormat, opts[changefeedbase.OptFormat])
+	}
+
+	topicPrefix := u.consumeParam(changefeedbase.SinkParamTopicPrefix)
+	topicName := u.consumeParam(changefeedbase.SinkParamTopicName)
+	s := &messageQueueSink{
+		client:      client,
+		topics:      makeTopicsMap(topicPrefix, topicName, targets),
+		parallelism: parallelism,
+		ts:          source,
+		metrics:     m,
+	}
+	var err error
+	if s.batchCfg, s.retryCfg, err = getBatchingSinkConfig(opts, configOpt); err != nil {
+		return nil, errors.Wrapf(err, "error processing option %s", configOpt)
+	}
+	s.workerCtx, s.exitWorkers = context.WithCancel(ctx)
+	return s, nil
+}
+
+// Dial implements the Sink interface.
+func (s *messageQueueSink) Dial() error {
+	if err := s.client.dial(s.workerCtx); err != nil {
+		return err
+	}
+	s.eventsChans = make([]chan mqEvent, s.parallelism)
+	s.workerGroup = ctxgroup.WithContext(s.workerCtx)
+	for i := range s.eventsChans {
+		events := make(chan mqEvent)
+		s.eventsChans[i] = events
+		s.workerGroup.GoCtx(func(ctx context.Context) error {
+			s.workerLoop(ctx, events)
+			return nil
+		})
+	}
+	return nil
+}
+
+func (s *messageQueueSink) shouldSendBatch(numMessages, numBytes int) bool {
+	switch {
+	// all zero values should batch every time, otherwise batch will wait forever
+	case s.batchCfg.Messages == 0 && s.batchCfg.Bytes == 0 && s.batchCfg.Frequency == 0:
+		return true
+	case s.batchCfg.Messages > 0 && numMessages >= s.batchCfg.Messages:
+		return true
+	case s.batchCfg.Bytes > 0 && numBytes >= s.batchCfg.Bytes:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *messageQueueSink) workerLoop(ctx context.Context, events chan mqEvent) {
+	var batch []mqEvent
+	var batchBytes int
+	batchTimer := s.ts.NewTimer()
+	defer batchTimer.Stop()
+
+	sendBatch := func() bool {
+		if err := s.publishBatch(ctx, batch); err != nil {
+			s.exitWorkersWithError(err)
+			return false
+		}
+		batch, batchBytes = batch[:0], 0
+		return true
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev := <-events:
+			if ev.flushed != nil {
+				if !sendBatch() {
+					return
+				}
+				close(ev.flushed)
+				continue
+			}
+			batch = append(batch, ev)
+			batchBytes += len(ev.msg.value)
+			if s.shouldSendBatch(len(batch), batchBytes) {
+				if !sendBatch() {
+					return
+				}
+			} else if len(batch) == 1 && s.batchCfg.Frequency > 0 {
+				// only start timer when first message appears
+				batchTimer.Reset(time.Duration(s.batchCfg.Frequency))
+			}
+		// If the batch is empty, the timer was started for a batch which was
+		// sent since.
+		case <-batchTimer.Ch():
+			batchTimer.MarkRead()
+			if len(batch) > 0 && !sendBatch() {
+				return
+			}
+		}
+	}
+}
+
+// publishBatch publishes the messages of a batch, each consecutive run of
+// messages to the same topic at once.
+func (s *messageQueueSink) publishBatch(ctx context.Context, batch []mqEvent) error {
+	msgs := make([]mqMessage, 0, len(batch))
+	for start := 0; start < len(batch); {
+		topic := batch[start].topic
+		msgs = msgs[:0]
+		end := start
+		for ; end < len(batch) && batch[end].topic == topic; end++ {
+			msgs = append(msgs, batch[end].msg)
+		}
+		if err := s.publishWithRetries(ctx, topic, msgs); err != nil {
+			return err
+		}
+
+		emitTime, mvcc, numBytes := timeutil.Now(), hlc.Timestamp{}, 0
+		for i := range msgs {
+			msgs[i].alloc.Release(ctx)
+			if msgs[i].emitTime.Before(emitTime) {
+				emitTime = msgs[i].emitTime
+			}
+			if mvcc.IsEmpty() || msgs[i].mvcc.Less(mvcc) {
+				mvcc = msgs[i].mvcc
+			}
+			numBytes += len(msgs[i].key) + len(msgs[i].value)
+		}
+		s.metrics.recordEmittedBatch(emitTime, len(msgs), mvcc, numBytes, sinkDoesNotCompress)
+		start = end
+	}
+	return nil
+}
+
+func (s *messageQueueSink) publishWithRetries(
+	ctx context.Context, topic string, msgs []mqMessage,
+) error {
+	return retry.WithMaxAttempts(ctx, s.retryCfg, s.retryCfg.MaxRetries+1, func() error {
+		return s.client.publish(ctx, topic, msgs)
+	})
+}
+
+// workerIndex assigns rows to workers based on the hash of their key, so that
+// all the messages with the same key are published in order by one worker.
+func (s *messageQueueSink) workerIndex(key []byte) uint32 {
+	return crc32.ChecksumIEEE(key) % uint32(s.parallelism)
+}
+
+// exitWorkersWithError saves the first error encountered by the workers, and
+// requests all workers to terminate.
+func (s *messageQueueSink) exitWorkersWithError(err error) {
+	s.mu.Lock()
+	if s.mu.err == nil {
+		s.mu.err = err
+	}
+	s.mu.Unlock()
+	s.exitWorkers()
+}
+
+// sinkError returns the error which terminated the workers.
+func (s *messageQueueSink) sinkError() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.mu.err != nil {
+		return s.mu.err
+	}
+	return s.workerCtx.Err()
+}
+
+// EmitRow implements the Sink interface.
+func (s *messageQueueSink) EmitRow(
+	ctx context.Context,
+	topicDescr TopicDescriptor,
+	key, value []byte,
+	updated, mvcc hlc.Timestamp,
+	alloc kvevent.Alloc,
+) error {
+	topic, isKnownTopic := s.topics[topicDescr.GetID()]
+	if !isKnownTopic {
+		return errors.Errorf(`cannot emit to undeclared topic: %s`, topicDescr.GetName())
+	}
+	ev := mqEvent{
+		topic: topic,
+		msg: mqMessage{
+			key:      key,
+			value:    value,
+			alloc:    alloc,
+			emitTime: timeutil.Now(),
+			mvcc:     mvcc,
+		},
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-s.workerCtx.Done():
+		return s.sinkError()
+	case s.eventsChans[s.workerIndex(key)] <- ev:
+		s.metrics.recordMessageSize(int64(len(key) + len(value)))
+		return nil
+	}
+}
+
+// EmitResolvedTimestamp implements the Sink interface.
+func (s *messageQueueSink) EmitResolvedTimestamp(
+	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
+) error {
+	defer s.metrics.recordResolvedCallback()()
+
+	if err := s.sinkError(); err != nil {
+		return err
+	}
+	// Resolved timestamps have no key, and are published to every topic
+	// directly, without going through the workers.
+	seen := make(map[string]struct{}, len(s.topics))
+	for _, topic := range s.topics {
+		if _, ok := seen[topic]; ok {
+			continue
+		}
+		seen[topic] = struct{}{}
+		payload, err := encoder.EncodeResolvedTimestamp(ctx, topic, resolved)
+		if err != nil {
+			return err
+		}
+		msg := mqMessage{value: append([]byte(nil), payload...)}
+		if err := s.publishWithRetries(ctx, topic, []mqMessage{msg}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush implements the Sink interface.
+func (s *messageQueueSink) Flush(ctx context.Context) error {
+	defer s.metrics.recordFlushRequestCallback()()
+
+	flushed := make([]chan struct{}, len(s.eventsChans))
+	for i, events := range s.eventsChans {
+		flushed[i] = make(chan struct{})
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.workerCtx.Done():
+			return s.sinkError()
+		case events <- mqEvent{flushed: flushed[i]}:
+		}
+	}
+	for _, done := range flushed {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.workerCtx.Done():
+			return s.sinkError()
+		case <-done:
+		}
+	}
+	return nil
+}
+
+// Close implements the Sink interface.
+func (s *messageQueueSink) Close() error {
+	s.exitWorkers()
+	if s.eventsChans != nil {
+		// ignore errors here since we're closing the sink anyway
+		_ = s.workerGroup.Wait()
+	}
+	return s.client.close()
+}
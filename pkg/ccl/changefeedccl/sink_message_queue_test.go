@@ -0,0 +1,386 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMessageQueue is a local stand-in for a message queue, which records the
+// messages published to its topics as "key->value" strings.
+type fakeMessageQueue interface {
+	// messages returns the messages published to a topic.
+	messages(topic string) []string
+	// failNext makes the next n publish attempts fail.
+	failNext(n int)
+	// sinkURI returns the URI of a sink publishing to the message queue.
+	sinkURI() string
+	close()
+}
+
+// fakeMessageQueueState is the state shared by the fake message queues. Only
+// the topics it was created with exist.
+type fakeMessageQueueState struct {
+	syncutil.Mutex
+	topics   map[string][]string
+	failures int
+}
+
+func (s *fakeMessageQueueState) init(topics ...string) {
+	s.topics = make(map[string][]string)
+	for _, topic := range topics {
+		s.topics[topic] = nil
+	}
+}
+
+func (s *fakeMessageQueueState) messages(topic string) []string {
+	s.Lock()
+	defer s.Unlock()
+	return append([]string(nil), s.topics[topic]...)
+}
+
+func (s *fakeMessageQueueState) failNext(n int) {
+	s.Lock()
+	defer s.Unlock()
+	s.failures = n
+}
+
+// fakeNATSServer speaks enough of the NATS client protocol to acknowledge the
+// messages published to the subjects of its JetStream streams.
+type fakeNATSServer struct {
+	fakeMessageQueueState
+	ln    net.Listener
+	conns []net.Conn
+	wg    sync.WaitGroup
+}
+
+var _ fakeMessageQueue = (*fakeNATSServer)(nil)
+
+func startFakeNATSServer(t *testing.T, subjects ...string) *fakeNATSServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &fakeNATSServer{ln: ln}
+	s.init(subjects...)
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			s.Lock()
+			s.conns = append(s.conns, conn)
+			s.Unlock()
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				_ = s.serve(conn)
+			}()
+		}
+	}()
+	return s
+}
+
+func (s *fakeNATSServer) serve(conn net.Conn) error {
+	r, w := bufio.NewReader(conn), bufio.NewWriter(conn)
+	fmt.Fprintf(w, "INFO {\"headers\":true}\r\n")
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	for {
+		line, err := natsReadLine(r)
+		if err != nil {
+			return err
+		}
+		switch op, args := natsSplitOp(line); op {
+		case "CONNECT", "SUB":
+		case "PING":
+			fmt.Fprintf(w, "PONG\r\n")
+		case "HPUB":
+			// HPUB <subject> <reply-to> <#header bytes> <#total bytes>
+			fields := strings.Fields(args)
+			headerSize, _ := strconv.Atoi(fields[2])
+			total, _ := strconv.Atoi(fields[3])
+			payload := make([]byte, total+2)
+			if _, err := io.ReadFull(r, payload); err != nil {
+				return err
+			}
+			s.publish(w, fields[0], fields[1], payload[:headerSize], payload[headerSize:total])
+		default:
+			return fmt.Errorf("unexpected message %q", line)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *fakeNATSServer) publish(w io.Writer, subject, reply string, header, value []byte) {
+	s.Lock()
+	defer s.Unlock()
+	if _, ok := s.topics[subject]; !ok {
+		const noResponders = "NATS/1.0 503\r\n\r\n"
+		fmt.Fprintf(w, "HMSG %s 1 %d %d\r\n%s\r\n", reply, len(noResponders), len(noResponders), noResponders)
+		return
+	}
+	ack := fmt.Sprintf(`{"stream":"test","seq":%d}`, len(s.topics[subject])+1)
+	if s.failures > 0 {
+		s.failures--
+		ack = `{"error":{"code":503,"description":"unavailable"}}`
+	} else {
+		var key string
+		for _, h := range strings.Split(string(header), "\r\n") {
+			if strings.HasPrefix(h, natsKeyHeader+": ") {
+				key = strings.TrimPrefix(h, natsKeyHeader+": ")
+			}
+		}
+		s.topics[subject] = append(s.topics[subject], key+"->"+string(value))
+	}
+	fmt.Fprintf(w, "MSG %s 1 %d\r\n%s\r\n", reply, len(ack), ack)
+}
+
+func (s *fakeNATSServer) sinkURI() string {
+	return "nats://" + s.ln.Addr().String()
+}
+
+func (s *fakeNATSServer) close() {
+	_ = s.ln.Close()
+	s.Lock()
+	for _, conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.Unlock()
+	s.wg.Wait()
+}
+
+// fakePubsubServer implements the publish method of the Pub/Sub REST API, like
+// the Pub/Sub emulator.
+type fakePubsubServer struct {
+	fakeMessageQueueState
+	srv *httptest.Server
+}
+
+var _ fakeMessageQueue = (*fakePubsubServer)(nil)
+
+func startFakePubsubServer(project string, topics ...string) *fakePubsubServer {
+	s := &fakePubsubServer{}
+	s.init(topics...)
+	prefix := "/v1/projects/" + project + "/topics/"
+	s.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, prefix) || !strings.HasSuffix(r.URL.Path, ":publish") {
+			http.NotFound(w, r)
+			return
+		}
+		topic := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, prefix), ":publish")
+		var req pubsubPublishRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.Lock()
+		defer s.Unlock()
+		if _, ok := s.topics[topic]; !ok {
+			http.Error(w, "Topic not found", http.StatusNotFound)
+			return
+		}
+		if s.failures > 0 {
+			s.failures--
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		for _, m := range req.Messages {
+			if m.OrderingKey != m.Attributes[pubsubKeyAttribute] {
+				http.Error(w, "unexpected ordering key", http.StatusBadRequest)
+				return
+			}
+			s.topics[topic] = append(s.topics[topic], m.OrderingKey+"->"+string(m.Data))
+		}
+		fmt.Fprintf(w, `{"messageIds":[]}`)
+	}))
+	return s
+}
+
+func (s *fakePubsubServer) sinkURI() string {
+	return fmt.Sprintf("gcpubsub://test-project?%s=%s",
+		changefeedbase.SinkParamEndpoint, url.QueryEscape(s.srv.URL))
+}
+
+func (s *fakePubsubServer) close() {
+	s.srv.Close()
+}
+
+type makeMessageQueueSinkFn func(
+	ctx context.Context,
+	u sinkURL,
+	targets jobspb.ChangefeedTargets,
+	opts map[string]string,
+	parallelism int,
+	source timeutil.TimeSource,
+	m *sliMetrics,
+) (Sink, error)
+
+func testMessageQueueSink(
+	t *testing.T, mq fakeMessageQueue, makeSink makeMessageQueueSinkFn, configOpt string,
+) {
+	ctx := context.Background()
+	targets := jobspb.ChangefeedTargets{0: jobspb.ChangefeedTarget{StatementTimeName: "t1"}}
+	opts := map[string]string{
+		changefeedbase.OptFormat:   string(changefeedbase.OptFormatJSON),
+		changefeedbase.OptEnvelope: string(changefeedbase.OptEnvelopeWrapped),
+		// speed up test by using faster backoff times
+		configOpt: `{"Retry":{"Max":2,"Backoff":"1ms"}}`,
+	}
+	setupSink := func(t *testing.T, uri string, opts map[string]string) (Sink, error) {
+		u, err := url.Parse(uri)
+		require.NoError(t, err)
+		s, err := makeSink(ctx, sinkURL{URL: u}, targets, opts, 4, timeutil.DefaultTimeSource{}, nil)
+		if err != nil {
+			return nil, err
+		}
+		require.NoError(t, s.Dial())
+		return s, nil
+	}
+	withParam := func(uri, param, value string) string {
+		u, err := url.Parse(uri)
+		require.NoError(t, err)
+		q := u.Query()
+		q.Set(param, value)
+		u.RawQuery = q.Encode()
+		return u.String()
+	}
+	messagesWithKey := func(topic, key string) []string {
+		var msgs []string
+		for _, m := range mq.messages(topic) {
+			if strings.HasPrefix(m, key+"->") {
+				msgs = append(msgs, m)
+			}
+		}
+		return msgs
+	}
+
+	t.Run("emit", func(t *testing.T) {
+		s, err := setupSink(t, mq.sinkURI(), opts)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, s.Close()) }()
+
+		pool := testAllocPool{}
+		for i := 0; i < 10; i++ {
+			key := fmt.Sprintf(`[%d]`, i%3)
+			require.NoError(t, s.EmitRow(ctx, topic(`t1`), []byte(key), []byte(strconv.Itoa(i)),
+				zeroTS, zeroTS, pool.alloc()))
+		}
+		require.NoError(t, s.Flush(ctx))
+		require.EqualValues(t, 0, pool.used())
+		require.Len(t, mq.messages(`t1`), 10)
+		require.Equal(t, []string{`[0]->0`, `[0]->3`, `[0]->6`, `[0]->9`}, messagesWithKey(`t1`, `[0]`))
+		require.Equal(t, []string{`[1]->1`, `[1]->4`, `[1]->7`}, messagesWithKey(`t1`, `[1]`))
+
+		t2 := tableDescriptorTopic{
+			tabledesc.NewBuilder(&descpb.TableDescriptor{Name: `t2`, ID: 1}).BuildImmutableTable(),
+		}
+		err = s.EmitRow(ctx, t2, []byte(`[0]`), nil, zeroTS, zeroTS, zeroAlloc)
+		require.Regexp(t, `cannot emit to undeclared topic: t2`, err)
+
+		encoder, err := makeJSONEncoder(opts, targets)
+		require.NoError(t, err)
+		require.NoError(t, s.EmitResolvedTimestamp(ctx, encoder, hlc.Timestamp{WallTime: 1}))
+		msgs := mq.messages(`t1`)
+		require.Len(t, msgs, 11)
+		require.Equal(t, `->{"resolved":"1.0000000000"}`, msgs[10])
+	})
+
+	t.Run("retry", func(t *testing.T) {
+		s, err := setupSink(t, mq.sinkURI(), opts)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, s.Close()) }()
+
+		// A failure is retried...
+		mq.failNext(2)
+		require.NoError(t, s.EmitRow(ctx, topic(`t1`), []byte(`[3]`), []byte(`0`),
+			zeroTS, zeroTS, zeroAlloc))
+		require.NoError(t, s.Flush(ctx))
+		require.Equal(t, []string{`[3]->0`}, messagesWithKey(`t1`, `[3]`))
+
+		// ...until the retries are exhausted, and the sink returns an error.
+		mq.failNext(3)
+		require.NoError(t, s.EmitRow(ctx, topic(`t1`), []byte(`[3]`), []byte(`1`),
+			zeroTS, zeroTS, zeroAlloc))
+		require.Regexp(t, `unavailable`, s.Flush(ctx))
+		require.Regexp(t, `unavailable`, s.EmitRow(ctx, topic(`t1`), []byte(`[3]`), []byte(`2`),
+			zeroTS, zeroTS, zeroAlloc))
+		require.Equal(t, []string{`[3]->0`}, messagesWithKey(`t1`, `[3]`))
+	})
+
+	t.Run("missing topic", func(t *testing.T) {
+		s, err := setupSink(t, withParam(mq.sinkURI(), changefeedbase.SinkParamTopicName, `missing`), opts)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, s.Close()) }()
+
+		require.NoError(t, s.EmitRow(ctx, topic(`t1`), []byte(`[0]`), []byte(`0`),
+			zeroTS, zeroTS, zeroAlloc))
+		require.Regexp(t, `missing`, s.Flush(ctx))
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		invalidOpts := map[string]string{}
+		for k, v := range opts {
+			invalidOpts[k] = v
+		}
+		invalidOpts[configOpt] = `{"Flush":{"Messages":100}}`
+		_, err := setupSink(t, mq.sinkURI(), invalidOpts)
+		require.Regexp(t, `flush frequency is not set, messages may never be sent`, err)
+
+		invalidOpts[configOpt] = opts[configOpt]
+		invalidOpts[changefeedbase.OptFormat] = string(changefeedbase.OptFormatAvro)
+		_, err = setupSink(t, mq.sinkURI(), invalidOpts)
+		require.Regexp(t, `this sink is incompatible with format=avro`, err)
+
+		_, err = setupSink(t, withParam(mq.sinkURI(), `foo`, `bar`), opts)
+		require.Regexp(t, `unknown .* sink query parameters: foo`, err)
+	})
+}
+
+func TestNATSSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	mq := startFakeNATSServer(t, `t1`)
+	defer mq.close()
+	testMessageQueueSink(t, mq, makeNATSSink, changefeedbase.OptNATSSinkConfig)
+}
+
+func TestPubsubSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	mq := startFakePubsubServer(`test-project`, `t1`)
+	defer mq.close()
+	testMessageQueueSink(t, mq, makePubsubSink, changefeedbase.OptPubsubSinkConfig)
+}
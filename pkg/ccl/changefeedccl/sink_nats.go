@@ -0,0 +1,524 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	// natsDefaultPort is the port of NATS servers when the sink URI has none.
+	natsDefaultPort = "4222"
+	// natsTimeout bounds the time to connect to a NATS server, and to receive
+	// the acknowledgements of published messages.
+	natsTimeout = 10 * time.Second
+	// natsKeyHeader is the header holding the key of a row in its message.
+	natsKeyHeader = "Crdb-Key"
+)
+
+// makeNATSSink returns a sink publishing to NATS JetStream. The sink URI is
+// nats://[user:password@]host[:port], or nats://token@host[:port] for token
+// authentication. Each table is published to a subject named like the Kafka
+// topic of the table, which must be bound to a JetStream stream; messages are
+// published with the key of their row in the Crdb-Key header.
+func makeNATSSink(
+	ctx context.Context,
+	u sinkURL,
+	targets jobspb.ChangefeedTargets,
+	opts map[string]string,
+	parallelism int,
+	source timeutil.TimeSource,
+	m *sliMetrics,
+) (Sink, error) {
+	client := &natsClient{addr: u.Host, timeout: natsTimeout}
+	if u.Port() == `` {
+		client.addr = net.JoinHostPort(u.Hostname(), natsDefaultPort)
+	}
+	if u.User != nil {
+		if password, ok := u.User.Password(); ok {
+			client.user, client.password = u.User.Username(), password
+		} else {
+			client.token = u.User.Username()
+		}
+	}
+
+	var tlsEnabled, tlsSkipVerify bool
+	var caCert []byte
+	if _, err := u.consumeBool(changefeedbase.SinkParamTLSEnabled, &tlsEnabled); err != nil {
+		return nil, err
+	}
+	if _, err := u.consumeBool(changefeedbase.SinkParamSkipTLSVerify, &tlsSkipVerify); err != nil {
+		return nil, err
+	}
+	if err := u.decodeBase64(changefeedbase.SinkParamCACert, &caCert); err != nil {
+		return nil, err
+	}
+	if tlsEnabled {
+		client.tlsConfig = &tls.Config{
+			ServerName:         u.Hostname(),
+			InsecureSkipVerify: tlsSkipVerify,
+		}
+		if caCert != nil {
+			caCertPool := x509.NewCertPool()
+			if !caCertPool.AppendCertsFromPEM(caCert) {
+				return nil, errors.Errorf("failed to parse certificate data:%s", string(caCert))
+			}
+			client.tlsConfig.RootCAs = caCertPool
+		}
+	} else if tlsSkipVerify || caCert != nil {
+		return nil, errors.Errorf(`%s must be enabled to use %s or %s`,
+			changefeedbase.SinkParamTLSEnabled, changefeedbase.SinkParamSkipTLSVerify,
+			changefeedbase.SinkParamCACert)
+	}
+
+	sink, err := makeMessageQueueSink(ctx, client, u, targets, opts,
+		changefeedbase.OptNATSSinkConfig, parallelism, source, m)
+	if err != nil {
+		return nil, err
+	}
+	if unknownParams := u.remainingQueryParams(); len(unknownParams) > 0 {
+		return nil, errors.Errorf(
+			`unknown nats sink query parameters: %s`, strings.Join(unknownParams, ", "))
+	}
+	return sink, nil
+}
+
+// natsClient is a messageQueueClient publishing to NATS JetStream. It speaks
+// the NATS client protocol: messages are published with a reply subject, to
+// which JetStream sends an acknowledgement once the message is stored. The
+// client holds a single connection, which is reestablished by the next publish
+// once it fails.
+type natsClient struct {
+	addr                  string
+	tlsConfig             *tls.Config
+	user, password, token string
+	timeout               time.Duration
+
+	mu struct {
+		syncutil.Mutex
+		conn *natsConn
+	}
+}
+
+var _ messageQueueClient = (*natsClient)(nil)
+
+func (c *natsClient) getConn(ctx context.Context) (*natsConn, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.mu.conn != nil {
+		if c.mu.conn.err() == nil {
+			return c.mu.conn, nil
+		}
+		c.mu.conn.close()
+		c.mu.conn = nil
+	}
+	conn, err := c.connect(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "connecting to NATS server %s", c.addr)
+	}
+	c.mu.conn = conn
+	return conn, nil
+}
+
+// dial implements the messageQueueClient interface.
+func (c *natsClient) dial(ctx context.Context) error {
+	_, err := c.getConn(ctx)
+	return err
+}
+
+// publish implements the messageQueueClient interface.
+func (c *natsClient) publish(ctx context.Context, subject string, msgs []mqMessage) error {
+	conn, err := c.getConn(ctx)
+	if err != nil {
+		return err
+	}
+	return conn.publish(ctx, subject, msgs, c.timeout)
+}
+
+// close implements the messageQueueClient interface.
+func (c *natsClient) close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.mu.conn != nil {
+		c.mu.conn.close()
+		c.mu.conn = nil
+	}
+	return nil
+}
+
+// natsServerInfo is the subset of the INFO message of a NATS server used by
+// the client.
+type natsServerInfo struct {
+	TLSRequired bool `json:"tls_required"`
+	Headers     bool `json:"headers"`
+}
+
+// natsConnectOptions is the payload of the CONNECT message of the client.
+type natsConnectOptions struct {
+	Verbose      bool   `json:"verbose"`
+	Pedantic     bool   `json:"pedantic"`
+	TLSRequired  bool   `json:"tls_required"`
+	Name         string `json:"name"`
+	Lang         string `json:"lang"`
+	Version      string `json:"version"`
+	Protocol     int    `json:"protocol"`
+	Headers      bool   `json:"headers"`
+	NoResponders bool   `json:"no_responders"`
+	User         string `json:"user,omitempty"`
+	Pass         string `json:"pass,omitempty"`
+	AuthToken    string `json:"auth_token,omitempty"`
+}
+
+// connect establishes a connection: it reads the INFO message of the server,
+// upgrades the connection to TLS if needed, authenticates, and subscribes to
+// the reply subjects of the connection.
+func (c *natsClient) connect(ctx context.Context) (*natsConn, error) {
+	dialer := net.Dialer{Timeout: c.timeout}
+	netConn, err := dialer.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := c.handshake(netConn)
+	if err != nil {
+		_ = netConn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+func (c *natsClient) handshake(netConn net.Conn) (*natsConn, error) {
+	if err := netConn.SetDeadline(timeutil.Now().Add(c.timeout)); err != nil {
+		return nil, err
+	}
+	r := bufio.NewReader(netConn)
+	line, err := natsReadLine(r)
+	if err != nil {
+		return nil, err
+	}
+	op, args := natsSplitOp(line)
+	if op != "INFO" {
+		return nil, errors.Errorf("expected INFO from NATS server, got %q", line)
+	}
+	var info natsServerInfo
+	if err := json.Unmarshal([]byte(args), &info); err != nil {
+		return nil, errors.Wrap(err, "decoding NATS server INFO")
+	}
+	if !info.Headers {
+		return nil, errors.New("NATS server does not support headers; NATS 2.2 or later is required")
+	}
+	if info.TLSRequired && c.tlsConfig == nil {
+		return nil, errors.Errorf("NATS server requires TLS; set %s=true", changefeedbase.SinkParamTLSEnabled)
+	}
+	if c.tlsConfig != nil {
+		tlsConn := tls.Client(netConn, c.tlsConfig)
+		if err := tlsConn.Handshake(); err != nil {
+			return nil, err
+		}
+		netConn = tlsConn
+		r = bufio.NewReader(netConn)
+	}
+
+	connectOpts, err := json.Marshal(natsConnectOptions{
+		TLSRequired:  c.tlsConfig != nil,
+		Name:         "cockroachdb-changefeed",
+		Lang:         "go",
+		Version:      "1.0.0",
+		Protocol:     1,
+		Headers:      true,
+		NoResponders: true,
+		User:         c.user,
+		Pass:         c.password,
+		AuthToken:    c.token,
+	})
+	if err != nil {
+		return nil, err
+	}
+	w := bufio.NewWriter(netConn)
+	fmt.Fprintf(w, "CONNECT %s\r\nPING\r\n", connectOpts)
+	if err := w.Flush(); err != nil {
+		return nil, err
+	}
+	// The server responds to the PING once it processed the CONNECT, or
+	// returns an error if the authentication failed.
+	for done := false; !done; {
+		line, err := natsReadLine(r)
+		if err != nil {
+			return nil, err
+		}
+		switch op, args := natsSplitOp(line); op {
+		case "PONG":
+			done = true
+		case "INFO", "+OK", "PING":
+		case "-ERR":
+			return nil, errors.Errorf("NATS server error: %s", args)
+		default:
+			return nil, errors.Errorf("unexpected message from NATS server: %q", line)
+		}
+	}
+
+	conn := &natsConn{
+		conn:  netConn,
+		inbox: "_INBOX." + strings.ReplaceAll(uuid.MakeV4().String(), "-", "") + ".",
+		done:  make(chan struct{}),
+	}
+	conn.mu.w = w
+	conn.mu.pending = make(map[string]chan<- error)
+	fmt.Fprintf(w, "SUB %s* 1\r\n", conn.inbox)
+	if err := w.Flush(); err != nil {
+		return nil, err
+	}
+	if err := netConn.SetDeadline(time.Time{}); err != nil {
+		return nil, err
+	}
+	go conn.readLoop(r)
+	return conn, nil
+}
+
+// natsConn is a connection to a NATS server. Its reader goroutine dispatches
+// the acknowledgements of published messages, and answers the PINGs of the
+// server.
+type natsConn struct {
+	conn net.Conn
+	// inbox is the prefix of the reply subjects of the connection.
+	inbox string
+	// done is closed once the reader goroutine exited, after setting mu.err.
+	done chan struct{}
+
+	mu struct {
+		syncutil.Mutex
+		w         *bufio.Writer
+		nextReply uint64
+		// pending maps the reply subjects of messages waiting for their
+		// acknowledgement to the channel to send it to.
+		pending map[string]chan<- error
+		err     error
+	}
+}
+
+// err returns the error which broke the connection, if any.
+func (c *natsConn) err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.mu.err
+}
+
+func (c *natsConn) fail(err error) {
+	c.mu.Lock()
+	if c.mu.err == nil {
+		c.mu.err = err
+	}
+	c.mu.Unlock()
+	_ = c.conn.Close()
+}
+
+func (c *natsConn) close() {
+	c.fail(errors.New("NATS connection closed"))
+	<-c.done
+}
+
+// publish publishes messages, and waits for JetStream to acknowledge them.
+func (c *natsConn) publish(
+	ctx context.Context, subject string, msgs []mqMessage, timeout time.Duration,
+) error {
+	acks := make(chan error, len(msgs))
+	replies := make([]string, 0, len(msgs))
+	defer func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		for _, reply := range replies {
+			delete(c.mu.pending, reply)
+		}
+	}()
+
+	if err := func() error {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if c.mu.err != nil {
+			return c.mu.err
+		}
+		for _, msg := range msgs {
+			c.mu.nextReply++
+			reply := c.inbox + strconv.FormatUint(c.mu.nextReply, 10)
+			c.mu.pending[reply] = acks
+			replies = append(replies, reply)
+			header := "NATS/1.0\r\n"
+			if len(msg.key) > 0 {
+				header += natsKeyHeader + ": " + string(msg.key) + "\r\n"
+			}
+			header += "\r\n"
+			fmt.Fprintf(c.mu.w, "HPUB %s %s %d %d\r\n%s", subject, reply,
+				len(header), len(header)+len(msg.value), header)
+			_, _ = c.mu.w.Write(msg.value)
+			_, _ = c.mu.w.WriteString("\r\n")
+		}
+		if err := c.conn.SetWriteDeadline(timeutil.Now().Add(timeout)); err != nil {
+			return err
+		}
+		return c.mu.w.Flush()
+	}(); err != nil {
+		c.fail(err)
+		return err
+	}
+
+	timer := timeutil.NewTimer()
+	defer timer.Stop()
+	timer.Reset(timeout)
+	for range msgs {
+		select {
+		case err := <-acks:
+			if err != nil {
+				return errors.Wrapf(err, "publishing to NATS subject %s", subject)
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-c.done:
+			return c.err()
+		case <-timer.C:
+			timer.Read = true
+			return errors.Errorf("timed out waiting for JetStream to acknowledge messages on subject %s", subject)
+		}
+	}
+	return nil
+}
+
+// readLoop reads the messages sent by the server until the connection fails.
+func (c *natsConn) readLoop(r *bufio.Reader) {
+	defer close(c.done)
+	for {
+		if err := c.readMessage(r); err != nil {
+			c.fail(err)
+			return
+		}
+	}
+}
+
+func (c *natsConn) readMessage(r *bufio.Reader) error {
+	line, err := natsReadLine(r)
+	if err != nil {
+		return err
+	}
+	op, args := natsSplitOp(line)
+	switch op {
+	case "MSG", "HMSG":
+		// MSG <subject> <sid> [reply-to] <#bytes>
+		// HMSG <subject> <sid> [reply-to] <#header bytes> <#total bytes>
+		fields := strings.Fields(args)
+		numSizes := 1
+		if op == "HMSG" {
+			numSizes = 2
+		}
+		if len(fields) < 2+numSizes {
+			return errors.Errorf("malformed NATS message %q", line)
+		}
+		sizes := fields[len(fields)-numSizes:]
+		total, err := strconv.Atoi(sizes[len(sizes)-1])
+		if err != nil {
+			return errors.Wrapf(err, "malformed NATS message %q", line)
+		}
+		headerSize := 0
+		if op == "HMSG" {
+			if headerSize, err = strconv.Atoi(sizes[0]); err != nil || headerSize > total {
+				return errors.Errorf("malformed NATS message %q", line)
+			}
+		}
+		payload := make([]byte, total+2)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return err
+		}
+		c.acknowledge(fields[0], payload[:headerSize], payload[headerSize:total])
+	case "PING":
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		_, _ = c.mu.w.WriteString("PONG\r\n")
+		return c.mu.w.Flush()
+	case "PONG", "+OK", "INFO":
+	case "-ERR":
+		return errors.Errorf("NATS server error: %s", args)
+	default:
+		return errors.Errorf("unexpected message from NATS server: %q", line)
+	}
+	return nil
+}
+
+// acknowledge delivers the result of publishing a message, given the reply
+// of JetStream.
+func (c *natsConn) acknowledge(subject string, header, payload []byte) {
+	c.mu.Lock()
+	ack, ok := c.mu.pending[subject]
+	delete(c.mu.pending, subject)
+	c.mu.Unlock()
+	if ok {
+		ack <- parseJetStreamAck(header, payload)
+	}
+}
+
+// jetStreamAck is the reply of JetStream to a published message.
+type jetStreamAck struct {
+	Stream string `json:"stream"`
+	Error  *struct {
+		Code        int    `json:"code"`
+		Description string `json:"description"`
+	} `json:"error"`
+}
+
+func parseJetStreamAck(header, payload []byte) error {
+	// Replies without responders have a 503 status in their header.
+	if status := strings.Fields(strings.SplitN(string(header), "\r\n", 2)[0]); len(status) > 1 {
+		if status[1] == "503" {
+			return errors.New("no responders: no JetStream stream is bound to the subject")
+		}
+		return errors.Errorf("unexpected NATS status %s", strings.Join(status[1:], " "))
+	}
+	var ack jetStreamAck
+	if err := json.Unmarshal(payload, &ack); err != nil {
+		return errors.Wrapf(err, "decoding JetStream acknowledgement %q", payload)
+	}
+	if ack.Error != nil {
+		return errors.Errorf("JetStream error %d: %s", ack.Error.Code, ack.Error.Description)
+	}
+	if ack.Stream == "" {
+		return errors.Errorf("unexpected JetStream acknowledgement %q", payload)
+	}
+	return nil
+}
+
+func natsReadLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// natsSplitOp splits a protocol line into its operation, in upper case, and
+// its arguments.
+func natsSplitOp(line string) (op, args string) {
+	op = line
+	if i := strings.IndexAny(line, " \t"); i >= 0 {
+		op, args = line[:i], strings.TrimSpace(line[i+1:])
+	}
+	return strings.ToUpper(op), args
+}
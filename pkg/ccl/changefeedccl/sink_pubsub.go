@@ -0,0 +1,216 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/cloud/gcp"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	// pubsubDefaultEndpoint is the global endpoint of the Pub/Sub API.
+	pubsubDefaultEndpoint = `https://pubsub.googleapis.com`
+	pubsubScope           = `https://www.googleapis.com/auth/pubsub`
+	pubsubTimeout         = 30 * time.Second
+	// Limits of the Pub/Sub publish API. The limit on the size of a request
+	// is 10MB, some of which is kept for the encoding of the request.
+	pubsubMaxMessagesPerRequest = 1000
+	pubsubMaxRequestBytes       = 9 << 20
+	pubsubMaxOrderingKeyBytes   = 1024
+	// pubsubKeyAttribute is the attribute holding the key of a row in its
+	// message.
+	pubsubKeyAttribute = `key`
+)
+
+// makePubsubSink returns a sink publishing to Google Cloud Pub/Sub. The sink
+// URI is gcpubsub://<project>, with either the CREDENTIALS or AUTH=implicit
+// parameters like for Google Cloud Storage. The endpoint parameter overrides
+// the endpoint of the Pub/Sub API, e.g. to use a regional endpoint, or the
+// Pub/Sub emulator, which does not require credentials. Each table is
+// published to a topic named like the Kafka topic of the table, which must
+// exist; messages have the key of their row as their ordering key, and in
+// their key attribute.
+func makePubsubSink(
+	ctx context.Context,
+	u sinkURL,
+	targets jobspb.ChangefeedTargets,
+	opts map[string]string,
+	parallelism int,
+	source timeutil.TimeSource,
+	m *sliMetrics,
+) (Sink, error) {
+	if u.Host == `` {
+		return nil, errors.Errorf(`this sink requires the project ID as the host of the sink URI`)
+	}
+	client := &pubsubClient{
+		project:  u.Host,
+		endpoint: strings.TrimSuffix(u.consumeParam(changefeedbase.SinkParamEndpoint), `/`),
+	}
+	emulator := client.endpoint != ``
+	if !emulator {
+		client.endpoint = pubsubDefaultEndpoint
+	}
+
+	var tokenSource oauth2.TokenSource
+	auth := u.consumeParam(cloud.AuthParam)
+	credentials := u.consumeParam(gcp.CredentialsParam)
+	switch {
+	case auth == cloud.AuthParamImplicit:
+		var err error
+		if tokenSource, err = google.DefaultTokenSource(ctx, pubsubScope); err != nil {
+			return nil, errors.Wrap(err, "creating Pub/Sub oauth token source from implicit credentials")
+		}
+	case credentials != ``:
+		decodedKey, err := base64.StdEncoding.DecodeString(credentials)
+		if err != nil {
+			return nil, errors.Wrapf(err, "decoding value of %s", gcp.CredentialsParam)
+		}
+		creds, err := google.CredentialsFromJSON(ctx, decodedKey, pubsubScope)
+		if err != nil {
+			return nil, errors.Wrap(err, "creating Pub/Sub oauth token source from specified credentials")
+		}
+		tokenSource = creds.TokenSource
+	case !emulator:
+		return nil, errors.Errorf(
+			"%s must be set unless %q is %q", gcp.CredentialsParam, cloud.AuthParam, cloud.AuthParamImplicit)
+	}
+	client.client = &http.Client{Timeout: pubsubTimeout}
+	if tokenSource != nil {
+		client.client = oauth2.NewClient(ctx, tokenSource)
+		client.client.Timeout = pubsubTimeout
+	}
+
+	sink, err := makeMessageQueueSink(ctx, client, u, targets, opts,
+		changefeedbase.OptPubsubSinkConfig, parallelism, source, m)
+	if err != nil {
+		return nil, err
+	}
+	if unknownParams := u.remainingQueryParams(); len(unknownParams) > 0 {
+		return nil, errors.Errorf(
+			`unknown pubsub sink query parameters: %s`, strings.Join(unknownParams, ", "))
+	}
+	return sink, nil
+}
+
+// pubsubClient is a messageQueueClient publishing to Google Cloud Pub/Sub
+// with the publish method of its REST API.
+type pubsubClient struct {
+	endpoint string
+	project  string
+	client   *http.Client
+}
+
+var _ messageQueueClient = (*pubsubClient)(nil)
+
+// pubsubMessage is a PubsubMessage of the Pub/Sub API.
+type pubsubMessage struct {
+	Data        []byte            `json:"data,omitempty"`
+	Attributes  map[string]string `json:"attributes,omitempty"`
+	OrderingKey string            `json:"orderingKey,omitempty"`
+}
+
+type pubsubPublishRequest struct {
+	Messages []pubsubMessage `json:"messages"`
+}
+
+// dial implements the messageQueueClient interface.
+func (c *pubsubClient) dial(context.Context) error {
+	return nil
+}
+
+// publish implements the messageQueueClient interface.
+func (c *pubsubClient) publish(ctx context.Context, topic string, msgs []mqMessage) error {
+	var req pubsubPublishRequest
+	var reqBytes int
+	for _, msg := range msgs {
+		m := pubsubMessage{Data: msg.value}
+		if len(msg.key) > 0 {
+			m.Attributes = map[string]string{pubsubKeyAttribute: string(msg.key)}
+			m.OrderingKey = pubsubOrderingKey(msg.key)
+		}
+		size := len(msg.key) + len(msg.value)
+		if len(req.Messages) > 0 &&
+			(len(req.Messages) == pubsubMaxMessagesPerRequest || reqBytes+size > pubsubMaxRequestBytes) {
+			if err := c.sendPublishRequest(ctx, topic, &req); err != nil {
+				return err
+			}
+			req.Messages, reqBytes = req.Messages[:0], 0
+		}
+		req.Messages = append(req.Messages, m)
+		reqBytes += size
+	}
+	if len(req.Messages) == 0 {
+		return nil
+	}
+	return c.sendPublishRequest(ctx, topic, &req)
+}
+
+// pubsubOrderingKey returns the ordering key of the messages of a row. Keys
+// longer than the limit of ordering keys are hashed.
+func pubsubOrderingKey(key []byte) string {
+	if len(key) <= pubsubMaxOrderingKeyBytes {
+		return string(key)
+	}
+	hash := sha256.Sum256(key)
+	return hex.EncodeToString(hash[:])
+}
+
+func (c *pubsubClient) sendPublishRequest(
+	ctx context.Context, topic string, req *pubsubPublishRequest,
+) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	u := fmt.Sprintf(`%s/v1/projects/%s/topics/%s:publish`,
+		c.endpoint, url.PathEscape(c.project), url.PathEscape(topic))
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", applicationTypeJSON)
+	res, err := c.client.Do(httpReq)
+	if err != nil {
+		return errors.Wrapf(err, "publishing to Pub/Sub topic %s", topic)
+	}
+	defer res.Body.Close()
+	if !(res.StatusCode >= http.StatusOK && res.StatusCode < http.StatusMultipleChoices) {
+		resBody, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read body for HTTP response with status: %d", res.StatusCode)
+		}
+		return errors.Errorf("publishing to Pub/Sub topic %s: %s: %s", topic, res.Status, resBody)
+	}
+	return nil
+}
+
+// close implements the messageQueueClient interface.
+func (c *pubsubClient) close() error {
+	c.client.CloseIdleConnections()
+	return nil
+}
@@ -195,7 +195,7 @@ type retryConfig struct {
 	Backoff jsonDuration   `json:",omitempty"`
 }
 
-// proper JSON schema for webhook sink config:
+// proper JSON schema for webhook and message queue sink configs:
 // {
 //   "Flush": {
 //	   "Messages":  ...,
@@ -207,20 +207,22 @@ type retryConfig struct {
 //	   "Backoff": ...,
 //   }
 // }
-type webhookSinkConfig struct {
+type batchingSinkConfig struct {
 	Flush batchConfig `json:",omitempty"`
 	Retry retryConfig `json:",omitempty"`
 }
 
-func (s *webhookSink) getWebhookSinkConfig(
-	opts map[string]string,
+// getBatchingSinkConfig parses the batching and retry configuration of a sink
+// from the given option.
+func getBatchingSinkConfig(
+	opts map[string]string, opt string,
 ) (batchCfg batchConfig, retryCfg retry.Options, err error) {
 	retryCfg = defaultRetryConfig()
 
-	var cfg webhookSinkConfig
+	var cfg batchingSinkConfig
 	cfg.Retry.Max = jsonMaxRetries(retryCfg.MaxRetries)
 	cfg.Retry.Backoff = jsonDuration(retryCfg.InitialBackoff)
-	if configStr, ok := opts[changefeedbase.OptWebhookSinkConfig]; ok {
+	if configStr, ok := opts[opt]; ok {
 		// set retry defaults to be overridden if included in JSON
 		if err = json.Unmarshal([]byte(configStr), &cfg); err != nil {
 			return batchCfg, retryCfg, errors.Wrapf(err, "error unmarshalling json")
@@ -230,12 +232,12 @@ func (s *webhookSink) getWebhookSinkConfig(
 	// don't support negative values
 	if cfg.Flush.Messages < 0 || cfg.Flush.Bytes < 0 || cfg.Flush.Frequency < 0 ||
 		cfg.Retry.Max < 0 || cfg.Retry.Backoff < 0 {
-		return batchCfg, retryCfg, errors.Errorf("invalid option value %s, all config values must be non-negative", changefeedbase.OptWebhookSinkConfig)
+		return batchCfg, retryCfg, errors.Errorf("invalid option value %s, all config values must be non-negative", opt)
 	}
 
 	// errors if other batch values are set, but frequency is not
 	if (cfg.Flush.Messages > 0 || cfg.Flush.Bytes > 0) && cfg.Flush.Frequency == 0 {
-		return batchCfg, retryCfg, errors.Errorf("invalid option value %s, flush frequency is not set, messages may never be sent", changefeedbase.OptWebhookSinkConfig)
+		return batchCfg, retryCfg, errors.Errorf("invalid option value %s, flush frequency is not set, messages may never be sent", opt)
 	}
 
 	retryCfg.MaxRetries = int(cfg.Retry.Max)
@@ -304,7 +306,7 @@ func makeWebhookSink(
 	}
 
 	var err error
-	sink.batchCfg, sink.retryCfg, err = sink.getWebhookSinkConfig(opts)
+	sink.batchCfg, sink.retryCfg, err = getBatchingSinkConfig(opts, changefeedbase.OptWebhookSinkConfig)
 	if err != nil {
 		return nil, errors.Wrapf(err, "error processing option %s", changefeedbase.OptWebhookSinkConfig)
 	}
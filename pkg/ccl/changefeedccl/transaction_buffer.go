@@ -0,0 +1,246 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"unsafe"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeeddist"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/errors"
+)
+
+// isTransactionEnvelope returns whether the changefeed emits the rows
+// committed by a transaction as a single message, i.e. envelope=transaction.
+func isTransactionEnvelope(details jobspb.ChangefeedDetails) bool {
+	return changefeedbase.EnvelopeType(details.Opts[changefeedbase.OptEnvelope]) ==
+		changefeedbase.OptEnvelopeTransaction
+}
+
+// transactionTopic is the topic on which the transactions of a changefeed are
+// emitted. A transaction may change the rows of several tables, and emitting
+// all of them on a single topic lets consumers see them in commit order, so
+// it's the topic of the target with the lowest descriptor ID. Sinks which
+// support the topic_name parameter can be used to name it.
+type transactionTopic struct {
+	id   descpb.ID
+	name string
+}
+
+var _ TopicDescriptor = transactionTopic{}
+
+func makeTransactionTopic(targets jobspb.ChangefeedTargets) transactionTopic {
+	var topic transactionTopic
+	for id, target := range targets {
+		if topic.id == 0 || id < topic.id {
+			topic = transactionTopic{id: id, name: target.StatementTimeName}
+		}
+	}
+	return topic
+}
+
+// GetName implements the TopicDescriptor interface.
+func (t transactionTopic) GetName() string {
+	return t.name
+}
+
+// GetID implements the TopicDescriptor interface.
+func (t transactionTopic) GetID() descpb.ID {
+	return t.id
+}
+
+// GetVersion implements the TopicDescriptor interface.
+func (t transactionTopic) GetVersion() descpb.DescriptorVersion {
+	return 0
+}
+
+// bufferedRow is a changed row forwarded by a changeAggregator, as encoded by
+// its Encoder.
+type bufferedRow struct {
+	topic      string
+	key, value []byte
+}
+
+// bufferedTransaction is the rows committed at a timestamp.
+type bufferedTransaction struct {
+	rows  []bufferedRow
+	bytes int64
+}
+
+const bufferedRowOverhead = int64(unsafe.Sizeof(bufferedRow{}))
+
+// transactionBuffer groups the changed rows forwarded to the changeFrontier
+// by the timestamp at which they were committed. The changeAggregators
+// forward the rows of their spans before the resolved timestamps of these
+// spans, so once the frontier reaches a timestamp, all the rows committed at
+// that timestamp have been buffered and can be emitted as a transaction.
+//
+// Rangefeeds don't identify the transactions that wrote the rows, so
+// transactions that commit at the same timestamp are emitted as one. This
+// only happens for transactions which don't conflict with each other, since
+// the ones that do are ordered by their timestamps.
+//
+// The rows of initial scans and backfills all have the timestamp of the scan.
+// They are emitted as they are scanned, in batches of at most
+// changefeedbase.TransactionScanBatchSize rows, so that they aren't all
+// buffered until the scan completes. Since the frontier is at the timestamp
+// just before the scan, all the transactions committed before it have
+// already been emitted.
+type transactionBuffer struct {
+	encoder transactionEncoder
+	topic   transactionTopic
+	sv      *settings.Values
+	// acc accounts for the memory used by buffered rows.
+	acc   *mon.BoundAccount
+	alloc rowenc.DatumAlloc
+
+	txns map[hlc.Timestamp]*bufferedTransaction
+	// values is reused across calls to flush.
+	values [][]byte
+}
+
+func makeTransactionBuffer(
+	encoder transactionEncoder,
+	topic transactionTopic,
+	sv *settings.Values,
+	acc *mon.BoundAccount,
+) *transactionBuffer {
+	return &transactionBuffer{
+		encoder: encoder,
+		topic:   topic,
+		sv:      sv,
+		acc:     acc,
+		txns:    make(map[hlc.Timestamp]*bufferedTransaction),
+	}
+}
+
+// add buffers a changed row forwarded by a changeAggregator. backfillTS is
+// the timestamp of the ongoing initial scan or backfill, if any: once a batch
+// of the rows of the scan has been buffered, it is emitted to the sink.
+func (b *transactionBuffer) add(
+	ctx context.Context, sink Sink, row rowenc.EncDatumRow, backfillTS hlc.Timestamp,
+) error {
+	for i := 1; i < len(row); i++ {
+		if err := row[i].EnsureDecoded(changefeeddist.TransactionChangefeedResultTypes[i], &b.alloc); err != nil {
+			return err
+		}
+	}
+	topic, ok := row[1].Datum.(*tree.DString)
+	if !ok {
+		return errors.AssertionFailedf(`unexpected topic datum type %T: %s`, row[1].Datum, row[1].Datum)
+	}
+	key, ok := row[2].Datum.(*tree.DBytes)
+	if !ok {
+		return errors.AssertionFailedf(`unexpected key datum type %T: %s`, row[2].Datum, row[2].Datum)
+	}
+	value, ok := row[3].Datum.(*tree.DBytes)
+	if !ok {
+		return errors.AssertionFailedf(`unexpected value datum type %T: %s`, row[3].Datum, row[3].Datum)
+	}
+	updatedDatum, ok := row[4].Datum.(*tree.DDecimal)
+	if !ok {
+		return errors.AssertionFailedf(`unexpected updated datum type %T: %s`, row[4].Datum, row[4].Datum)
+	}
+	updated, err := tree.DecimalToHLC(&updatedDatum.Decimal)
+	if err != nil {
+		return err
+	}
+
+	r := bufferedRow{
+		topic: string(*topic),
+		key:   []byte(*key),
+		value: []byte(*value),
+	}
+	size := bufferedRowOverhead + int64(len(r.topic)+len(r.key)+len(r.value))
+	if err := b.acc.Grow(ctx, size); err != nil {
+		return errors.Wrapf(err, `buffering the rows committed at %s`, updated)
+	}
+	txn, ok := b.txns[updated]
+	if !ok {
+		txn = &bufferedTransaction{}
+		b.txns[updated] = txn
+	}
+	txn.rows = append(txn.rows, r)
+	txn.bytes += size
+
+	if !backfillTS.IsEmpty() && updated.Equal(backfillTS) &&
+		int64(len(txn.rows)) >= changefeedbase.TransactionScanBatchSize.Get(b.sv) {
+		if err := b.emit(ctx, sink, updated, txn); err != nil {
+			return err
+		}
+		return sink.Flush(ctx)
+	}
+	return nil
+}
+
+// flush emits to the sink, in commit order, the transactions committed at or
+// before the resolved timestamp. The rows of a transaction are ordered by
+// topic and key. The sink is flushed if any transaction was emitted.
+func (b *transactionBuffer) flush(ctx context.Context, sink Sink, resolved hlc.Timestamp) error {
+	var ready []hlc.Timestamp
+	for ts := range b.txns {
+		if ts.LessEq(resolved) {
+			ready = append(ready, ts)
+		}
+	}
+	if len(ready) == 0 {
+		return nil
+	}
+	sort.Slice(ready, func(i, j int) bool { return ready[i].Less(ready[j]) })
+
+	for _, ts := range ready {
+		if err := b.emit(ctx, sink, ts, b.txns[ts]); err != nil {
+			return err
+		}
+	}
+	return sink.Flush(ctx)
+}
+
+// emit emits to the sink the buffered rows committed at the given timestamp,
+// ordered by topic and key, and releases them.
+func (b *transactionBuffer) emit(
+	ctx context.Context, sink Sink, ts hlc.Timestamp, txn *bufferedTransaction,
+) error {
+	sort.Slice(txn.rows, func(i, j int) bool {
+		if txn.rows[i].topic != txn.rows[j].topic {
+			return txn.rows[i].topic < txn.rows[j].topic
+		}
+		return bytes.Compare(txn.rows[i].key, txn.rows[j].key) < 0
+	})
+	b.values = b.values[:0]
+	for _, r := range txn.rows {
+		b.values = append(b.values, r.value)
+	}
+	payload, err := b.encoder.EncodeTransaction(ctx, ts, b.values)
+	if err != nil {
+		return err
+	}
+	// Sinks may hold on to the payload until they're flushed, and it's only
+	// valid until the next call to the encoder.
+	payload = append([]byte(nil), payload...)
+	if err := sink.EmitRow(
+		ctx, b.topic, nil /* key */, payload, ts, ts, kvevent.Alloc{},
+	); err != nil {
+		return err
+	}
+	delete(b.txns, ts)
+	b.acc.Shrink(ctx, txn.bytes)
+	return nil
+}
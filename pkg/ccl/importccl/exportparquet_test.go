@@ -0,0 +1,87 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl_test
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportParquet(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	dir, cleanupDir := testutils.TempDir(t)
+	defer cleanupDir()
+
+	srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{ExternalIODir: dir})
+	defer srv.Stopper().Stop(context.Background())
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	sqlDB.Exec(t, `CREATE TYPE mood AS ENUM ('happy', 'sad')`)
+	sqlDB.Exec(t, `CREATE TABLE foo (
+		i INT PRIMARY KEY, s STRING, b BYTES, d DECIMAL(10, 2), f FLOAT, t TIMESTAMPTZ,
+		dt DATE, u UUID, j JSONB, a INT[], m mood, iv INTERVAL
+	)`)
+	sqlDB.Exec(t, `INSERT INTO foo VALUES
+		(1, 'a', 'b', 1.23, 1.5, '2021-01-01 00:00:00+00', '2021-01-01',
+		 'e2e2a4e2-6b7c-4f5e-9f62-1c1a7a8c0d52', '{"a": 1}', ARRAY[1, NULL, 3], 'happy', '1 day'),
+		(2, NULL, NULL, NULL, NULL, NULL, NULL, NULL, NULL, NULL, NULL, NULL),
+		(3, '', '', -1.5, 'NaN', '-infinity', 'infinity', NULL, 'null', ARRAY[], 'sad', '-1 hour')`)
+
+	for _, tc := range []struct {
+		name, options, pattern string
+		files, rows            int
+	}{
+		{name: "plain", pattern: "export*-n*.0.parquet", files: 1, rows: 3},
+		{name: "snappy", options: `WITH compression = snappy`, pattern: "export*.parquet", files: 1, rows: 3},
+		{name: "gzip", options: `WITH compression = gzip`, pattern: "export*.parquet", files: 1, rows: 3},
+		{name: "chunked", options: `WITH chunk_rows = 2`, pattern: "export*.parquet", files: 2, rows: 3},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var rows int
+			for _, row := range sqlDB.QueryStr(t,
+				`EXPORT INTO PARQUET 'nodelocal://0/`+tc.name+`' `+tc.options+` FROM SELECT * FROM foo`,
+			) {
+				require.Len(t, row, 3)
+				n, err := strconv.Atoi(row[1])
+				require.NoError(t, err)
+				rows += n
+			}
+			require.Equal(t, tc.rows, rows)
+
+			files, err := filepath.Glob(filepath.Join(dir, tc.name, tc.pattern))
+			require.NoError(t, err)
+			require.Len(t, files, tc.files)
+			for _, f := range files {
+				content, err := ioutil.ReadFile(f)
+				require.NoError(t, err)
+				require.Equal(t, "PAR1", string(content[:4]))
+				require.Equal(t, "PAR1", string(content[len(content)-4:]))
+			}
+		})
+	}
+
+	sqlDB.ExpectErr(t, `delimiter option is not supported for PARQUET exports`,
+		`EXPORT INTO PARQUET 'nodelocal://0/err' WITH delimiter = '|' FROM SELECT * FROM foo`)
+	sqlDB.ExpectErr(t, `nullas option is not supported for PARQUET exports`,
+		`EXPORT INTO PARQUET 'nodelocal://0/err' WITH nullas = '' FROM SELECT * FROM foo`)
+	sqlDB.ExpectErr(t, `unsupported compression codec snappy`,
+		`EXPORT INTO CSV 'nodelocal://0/err' WITH compression = snappy FROM SELECT * FROM foo`)
+}
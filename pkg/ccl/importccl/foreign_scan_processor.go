@@ -0,0 +1,223 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowexec"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+const foreignScanProcessorName = "foreignScanProcessor"
+
+// foreignScanBufferSize is the number of decoded rows the reading goroutine
+// may get ahead of the consumer.
+const foreignScanBufferSize = 64
+
+// foreignScanProcessor reads the rows of a foreign table out of a set of
+// files in external storage. It reuses the IMPORT readers to parse the files
+// but, instead of converting the rows to KVs, it emits the visible columns of
+// each row. The files are read by a worker goroutine started in Start(), which
+// sends the decoded rows over an internally maintained channel that Next()
+// drains.
+type foreignScanProcessor struct {
+	execinfra.ProcessorBase
+
+	flowCtx *execinfra.FlowCtx
+	spec    execinfrapb.ForeignScanSpec
+	desc    catalog.TableDescriptor
+	types   []*types.T
+
+	rowCh  chan rowenc.EncDatumRow
+	cancel context.CancelFunc
+	// readErr is set by the worker goroutine before it closes rowCh.
+	readErr error
+}
+
+var _ execinfra.Processor = &foreignScanProcessor{}
+var _ execinfra.RowSource = &foreignScanProcessor{}
+
+func newForeignScanProcessor(
+	flowCtx *execinfra.FlowCtx,
+	processorID int32,
+	spec execinfrapb.ForeignScanSpec,
+	post *execinfrapb.PostProcessSpec,
+	output execinfra.RowReceiver,
+) (execinfra.Processor, error) {
+	// Install type metadata in the table before building the readers, which
+	// parse the file contents according to the column types.
+	resolver := flowCtx.TypeResolverFactory.NewTypeResolver(flowCtx.EvalCtx.Txn)
+	if err := typedesc.HydrateTypesInTableDescriptor(
+		flowCtx.EvalCtx.Context, &spec.Table, resolver,
+	); err != nil {
+		return nil, err
+	}
+	desc := tabledesc.NewBuilder(&spec.Table).BuildImmutableTable()
+	cols := desc.VisibleColumns()
+	typs := make([]*types.T, len(cols))
+	for i, col := range cols {
+		typs[i] = col.GetType()
+	}
+
+	fsp := &foreignScanProcessor{
+		flowCtx: flowCtx,
+		spec:    spec,
+		desc:    desc,
+		types:   typs,
+		rowCh:   make(chan rowenc.EncDatumRow, foreignScanBufferSize),
+	}
+	if err := fsp.Init(fsp, post, typs, flowCtx, processorID, output, nil, /* memMonitor */
+		execinfra.ProcStateOpts{
+			// This processor doesn't have any inputs to drain.
+			InputsToDrain: nil,
+			TrailingMetaCallback: func() []execinfrapb.ProducerMetadata {
+				fsp.close()
+				return nil
+			},
+		}); err != nil {
+		return nil, err
+	}
+	return fsp, nil
+}
+
+// Start is part of the RowSource interface.
+func (fsp *foreignScanProcessor) Start(ctx context.Context) {
+	ctx = fsp.StartInternal(ctx, foreignScanProcessorName)
+	ctx, fsp.cancel = context.WithCancel(ctx)
+	go func() {
+		defer close(fsp.rowCh)
+		dataFiles := make(map[int32]string, len(fsp.spec.Uris))
+		for i, uri := range fsp.spec.Uris {
+			dataFiles[int32(i)] = uri
+		}
+		fsp.readErr = readInputFiles(ctx, dataFiles, nil /* resumePos */, fsp.spec.Format,
+			fsp.readFile, fsp.flowCtx.Cfg.ExternalStorage, fsp.spec.User())
+	}()
+}
+
+// readFile decodes every row of a single file and sends it to rowCh.
+func (fsp *foreignScanProcessor) readFile(
+	ctx context.Context, input *fileReader, _ int32, _ int64, _ chan string,
+) error {
+	evalCtx := fsp.flowCtx.NewEvalCtx()
+	semaCtx := tree.MakeSemaContext()
+	semaCtx.TypeResolver = fsp.flowCtx.TypeResolverFactory.NewTypeResolver(evalCtx.Txn)
+
+	var producer importRowProducer
+	var consumer importRowConsumer
+	var skip int64
+	switch fsp.spec.Format.Format {
+	case roachpb.IOFileFormat_CSV:
+		opts := fsp.spec.Format.Csv
+		r := newCSVInputReader(&semaCtx, nil /* kvCh */, opts, 0 /* walltime */, 1, /* parallelism */
+			fsp.desc, nil /* targetCols */, evalCtx, nil /* seqChunkProvider */)
+		producer, consumer = newCSVPipeline(r, input)
+		skip = int64(opts.Skip)
+	case roachpb.IOFileFormat_Avro:
+		r, err := newAvroInputReader(&semaCtx, nil /* kvCh */, fsp.desc, fsp.spec.Format.Avro,
+			0 /* walltime */, 1 /* parallelism */, evalCtx)
+		if err != nil {
+			return err
+		}
+		if producer, consumer, err = newImportAvroPipeline(r, input); err != nil {
+			return err
+		}
+	default:
+		return errors.AssertionFailedf("unsupported foreign table format %s", fsp.spec.Format.Format)
+	}
+
+	conv, err := row.NewDatumRowConverter(ctx, &semaCtx, fsp.desc, nil /* targetColNames */, evalCtx,
+		nil /* kvCh */, nil /* seqChunkProvider */, nil /* metrics */)
+	if err != nil {
+		return err
+	}
+
+	var rowNum int64
+	for producer.Scan() {
+		rowNum++
+		if rowNum <= skip {
+			if err := producer.Skip(); err != nil {
+				return err
+			}
+			continue
+		}
+		data, err := producer.Row()
+		if err != nil {
+			return err
+		}
+		// The consumers leave unset columns alone, so clear out the previous row.
+		for i := range conv.Datums {
+			conv.Datums[i] = nil
+		}
+		if err := consumer.FillDatums(data, rowNum, conv); err != nil {
+			return err
+		}
+		encRow := make(rowenc.EncDatumRow, len(fsp.types))
+		for i, typ := range fsp.types {
+			d := conv.Datums[i]
+			if d == nil {
+				d = tree.DNull
+			}
+			encRow[i] = rowenc.DatumToEncDatum(typ, d)
+		}
+		select {
+		case fsp.rowCh <- encRow:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return producer.Err()
+}
+
+// Next is part of the RowSource interface.
+func (fsp *foreignScanProcessor) Next() (rowenc.EncDatumRow, *execinfrapb.ProducerMetadata) {
+	for fsp.State == execinfra.StateRunning {
+		encRow, ok := <-fsp.rowCh
+		if !ok {
+			fsp.MoveToDraining(fsp.readErr)
+			break
+		}
+		if outRow := fsp.ProcessRowHelper(encRow); outRow != nil {
+			return outRow, nil
+		}
+	}
+	return nil, fsp.DrainHelper()
+}
+
+func (fsp *foreignScanProcessor) close() {
+	if fsp.InternalClose() {
+		if fsp.cancel != nil {
+			fsp.cancel()
+			// Wait for the worker goroutine to notice the cancellation.
+			for range fsp.rowCh {
+			}
+		}
+	}
+}
+
+// ConsumerClosed is part of the RowSource interface.
+func (fsp *foreignScanProcessor) ConsumerClosed() {
+	// The consumer is done, Next() will not be called again.
+	fsp.close()
+}
+
+func init() {
+	rowexec.NewForeignScanProcessor = newForeignScanProcessor
+}
@@ -295,6 +295,14 @@ func makeInputConverter(
 		return newAvroInputReader(
 			semaCtx, kvCh, singleTable, spec.Format.Avro, spec.WalltimeNanos,
 			int(spec.ReaderParallelism), evalCtx)
+	case roachpb.IOFileFormat_Parquet:
+		return newParquetInputReader(
+			semaCtx, kvCh, singleTable, singleTableTargetCols, spec.Format.Parquet,
+			spec.WalltimeNanos, int(spec.ReaderParallelism), evalCtx, seqChunkProvider), nil
+	case roachpb.IOFileFormat_ORC:
+		return newORCInputReader(
+			semaCtx, kvCh, singleTable, singleTableTargetCols, spec.Format.Orc,
+			spec.WalltimeNanos, int(spec.ReaderParallelism), evalCtx, seqChunkProvider), nil
 	default:
 		return nil, errors.Errorf(
 			"Requested IMPORT format (%d) not supported by this node", spec.Format.Format)
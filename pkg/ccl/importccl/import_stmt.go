@@ -191,6 +191,8 @@ var mysqlDumpAllowedOptions = makeStringSet(importOptionSkipFKs, csvRowLimit)
 var pgCopyAllowedOptions = makeStringSet(pgCopyDelimiter, pgCopyNull, optMaxRowSize)
 var pgDumpAllowedOptions = makeStringSet(optMaxRowSize, importOptionSkipFKs, csvRowLimit,
 	pgDumpIgnoreAllUnsupported, pgDumpIgnoreShuntFileDest)
+var parquetAllowedOptions = makeStringSet(avroStrict, csvRowLimit)
+var orcAllowedOptions = makeStringSet(avroStrict, csvRowLimit)
 
 // DROP is required because the target table needs to be take offline during
 // IMPORT INTO.
@@ -202,6 +204,8 @@ var allowedIntoFormats = map[string]struct{}{
 	"AVRO":      {},
 	"DELIMITED": {},
 	"PGCOPY":    {},
+	"PARQUET":   {},
+	"ORC":       {},
 }
 
 // featureImportEnabled is used to enable and disable the IMPORT feature.
@@ -475,7 +479,10 @@ func importPlanHook(
 
 		// Typically the SQL grammar means it is only possible to specifying exactly
 		// one pgdump/mysqldump URI, but glob-expansion could have changed that.
-		if importStmt.Bundle && len(files) != 1 {
+		// Parquet and ORC files carry the schema of their table only, so a table
+		// can be imported from many of them.
+		if importStmt.Bundle && importStmt.FileFormat != "PARQUET" && importStmt.FileFormat != "ORC" &&
+			len(files) != 1 {
 			return pgerror.New(pgcode.FeatureNotSupported, "SQL dump files must be imported individually")
 		}
 
@@ -753,6 +760,28 @@ func importPlanHook(
 			if err != nil {
 				return err
 			}
+		case "PARQUET":
+			if err = validateFormatOptions(importStmt.FileFormat, opts, parquetAllowedOptions); err != nil {
+				return err
+			}
+			if importStmt.Bundle && table == nil {
+				return pgerror.New(pgcode.Syntax,
+					"PARQUET files do not name their table; use IMPORT TABLE <name> FROM PARQUET")
+			}
+			if err := parseParquetOptions(opts, &format); err != nil {
+				return err
+			}
+		case "ORC":
+			if err = validateFormatOptions(importStmt.FileFormat, opts, orcAllowedOptions); err != nil {
+				return err
+			}
+			if importStmt.Bundle && table == nil {
+				return pgerror.New(pgcode.Syntax,
+					"ORC files do not name their table; use IMPORT TABLE <name> FROM ORC")
+			}
+			if err := parseORCOptions(opts, &format); err != nil {
+				return err
+			}
 		default:
 			return unimplemented.Newf("import.format", "unsupported import format: %q", importStmt.FileFormat)
 		}
@@ -826,6 +855,11 @@ func importPlanHook(
 				return pgerror.New(pgcode.FeatureNotSupported, "Cannot use IMPORT INTO with interleaved tables")
 			}
 
+			// Foreign tables are read-only, their rows live in external files.
+			if found.IsForeignTable() {
+				return pgerror.New(pgcode.WrongObjectType, "Cannot use IMPORT INTO with foreign tables")
+			}
+
 			// Validate target columns.
 			var intoCols []string
 			var isTargetCol = make(map[string]bool)
@@ -875,7 +909,7 @@ func importPlanHook(
 		} else {
 			seqVals := make(map[descpb.ID]int64)
 
-			if importStmt.Bundle {
+			if importStmt.Bundle && !isColumnarFormat(format.Format) {
 				// If we target a single table, populate details with one entry of tableName.
 				if table != nil {
 					tableDetails = make([]jobspb.ImportDetails_Table, 1)
@@ -905,6 +939,18 @@ func importPlanHook(
 						Table: *importStmt.Table,
 						Defs:  importStmt.CreateDefs,
 					}
+				} else if importStmt.Bundle {
+					// IMPORT TABLE ... FROM PARQUET or ORC infers the columns of the
+					// table from the schema of the first file.
+					readCreateTable := readParquetCreateTable
+					if format.Format == roachpb.IOFileFormat_ORC {
+						readCreateTable = readORCCreateTable
+					}
+					create, err = readCreateTable(ctx, table, files[0],
+						p.ExecCfg().DistSQLSrv.ExternalStorageFromURI, p.User())
+					if err != nil {
+						return err
+					}
 				} else {
 					filename, err := createFileFn()
 					if err != nil {
@@ -1030,7 +1076,7 @@ func importPlanHook(
 			SSTSize:               sstSize,
 			Oversample:            oversample,
 			SkipFKs:               skipFKs,
-			ParseBundleSchema:     importStmt.Bundle,
+			ParseBundleSchema:     importStmt.Bundle && !isColumnarFormat(format.Format),
 			DefaultIntSize:        p.SessionData().DefaultIntSize,
 			DatabasePrimaryRegion: databasePrimaryRegion,
 		}
@@ -1103,6 +1149,40 @@ func importPlanHook(
 	return fn, utilccl.BulkJobExecutionResultHeader, nil, false, nil
 }
 
+func parseParquetOptions(opts map[string]string, format *roachpb.IOFileFormat) error {
+	format.Format = roachpb.IOFileFormat_Parquet
+	_, format.Parquet.StrictMode = opts[avroStrict]
+
+	if override, ok := opts[csvRowLimit]; ok {
+		rowLimit, err := strconv.Atoi(override)
+		if err != nil {
+			return pgerror.Wrapf(err, pgcode.Syntax, "invalid numeric %s value", csvRowLimit)
+		}
+		if rowLimit <= 0 {
+			return pgerror.Newf(pgcode.Syntax, "%s must be > 0", csvRowLimit)
+		}
+		format.Parquet.RowLimit = int64(rowLimit)
+	}
+	return nil
+}
+
+func parseORCOptions(opts map[string]string, format *roachpb.IOFileFormat) error {
+	format.Format = roachpb.IOFileFormat_ORC
+	_, format.Orc.StrictMode = opts[avroStrict]
+
+	if override, ok := opts[csvRowLimit]; ok {
+		rowLimit, err := strconv.Atoi(override)
+		if err != nil {
+			return pgerror.Wrapf(err, pgcode.Syntax, "invalid numeric %s value", csvRowLimit)
+		}
+		if rowLimit <= 0 {
+			return pgerror.Newf(pgcode.Syntax, "%s must be > 0", csvRowLimit)
+		}
+		format.Orc.RowLimit = int64(rowLimit)
+	}
+	return nil
+}
+
 func parseAvroOptions(
 	ctx context.Context, opts map[string]string, p sql.PlanHookState, format *roachpb.IOFileFormat,
 ) error {
@@ -1296,7 +1376,8 @@ func prepareNewTableDescsForIngestion(
 	// imported data.
 	if err := backupccl.WriteDescriptors(ctx, p.ExecCfg().Codec, txn, p.User(), descsCol,
 		nil /* databases */, nil, /* schemas */
-		tableDescs, nil, tree.RequestedDescriptors, seqValKVs); err != nil {
+		tableDescs, nil /* types */, nil, /* functions */
+		tree.RequestedDescriptors, seqValKVs); err != nil {
 		return nil, errors.Wrapf(err, "creating importTables")
 	}
 
@@ -0,0 +1,355 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"context"
+	"io"
+	"runtime"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/errors"
+)
+
+// columnarFile is a file of a columnar format, such as Parquet or ORC. The
+// rows of such files are stored in groups, which can be read independently.
+type columnarFile interface {
+	// numRows returns the number of rows of the file.
+	numRows() int64
+	// numGroups returns the number of groups of rows of the file.
+	numGroups() int
+	// readGroup reads the rows of the i-th group. Rows hold one value per
+	// column of the file. It can be called concurrently.
+	readGroup(i int) ([][]interface{}, error)
+	// columnNames returns the names of the columns of the file.
+	columnNames() []string
+	// valueToDatum converts a value of the i-th column to a datum of the
+	// given type.
+	valueToDatum(v interface{}, i int, typ *types.T, evalCtx *tree.EvalContext) (tree.Datum, error)
+}
+
+// isColumnarFormat returns true if the files of the format are read by a
+// columnarInputReader.
+func isColumnarFormat(format roachpb.IOFileFormat_FileFormat) bool {
+	return format == roachpb.IOFileFormat_Parquet || format == roachpb.IOFileFormat_ORC
+}
+
+// columnarInputReader imports files of a columnar format. Unlike the other
+// formats, these files are not read as a stream: their metadata describes
+// where the columns of each group of rows are, so the files are read at
+// random offsets, and several groups are read and decoded in parallel.
+type columnarInputReader struct {
+	importCtx *parallelImportContext
+	// format names the format of the files in error messages.
+	format   string
+	strict   bool
+	rowLimit int64
+	open     func(ctx context.Context, store cloud.ExternalStorage) (columnarFile, error)
+}
+
+var _ inputConverter = &columnarInputReader{}
+
+func newColumnarInputReader(
+	semaCtx *tree.SemaContext,
+	kvCh chan row.KVBatch,
+	tableDesc catalog.TableDescriptor,
+	targetCols tree.NameList,
+	walltime int64,
+	parallelism int,
+	evalCtx *tree.EvalContext,
+	seqChunkProvider *row.SeqChunkProvider,
+) *columnarInputReader {
+	return &columnarInputReader{
+		importCtx: &parallelImportContext{
+			semaCtx:          semaCtx,
+			walltime:         walltime,
+			numWorkers:       parallelism,
+			evalCtx:          evalCtx,
+			tableDesc:        tableDesc,
+			targetCols:       targetCols,
+			kvCh:             kvCh,
+			seqChunkProvider: seqChunkProvider,
+		},
+	}
+}
+
+func (c *columnarInputReader) start(group ctxgroup.Group) {}
+
+func (c *columnarInputReader) readFiles(
+	ctx context.Context,
+	dataFiles map[int32]string,
+	resumePos map[int32]int64,
+	format roachpb.IOFileFormat,
+	makeExternalStorage cloud.ExternalStorageFactory,
+	user security.SQLUsername,
+) error {
+	done := ctx.Done()
+	for dataFileIndex, dataFile := range dataFiles {
+		select {
+		case <-done:
+			return ctx.Err()
+		default:
+		}
+		if err := func() error {
+			conf, err := cloud.ExternalStorageConfFromURI(dataFile, user)
+			if err != nil {
+				return err
+			}
+			es, err := makeExternalStorage(ctx, conf)
+			if err != nil {
+				return err
+			}
+			defer es.Close()
+			f, err := c.open(ctx, es)
+			if err != nil {
+				return err
+			}
+			return c.readFile(ctx, f, dataFileIndex, resumePos[dataFileIndex])
+		}(); err != nil {
+			return errors.Wrapf(err, "%s", dataFile)
+		}
+	}
+	return nil
+}
+
+func (c *columnarInputReader) readFile(
+	ctx context.Context, f columnarFile, inputIdx int32, resumePos int64,
+) error {
+	consumer, err := newColumnarConsumer(c.importCtx, f, c.format, c.strict)
+	if err != nil {
+		return err
+	}
+	fileCtx := &importFileContext{
+		source:   inputIdx,
+		skip:     resumePos,
+		rowLimit: c.rowLimit,
+	}
+
+	parallelism := c.importCtx.numWorkers
+	if parallelism <= 0 {
+		parallelism = runtime.GOMAXPROCS(0)
+	}
+
+	// The groups of the file are read by up to parallelism workers ahead of
+	// the producer, which hands their rows to runParallelImport in order so
+	// that row numbers, and thus resume positions, are stable across attempts.
+	// The context is canceled once the rows are consumed, which stops the
+	// reads ahead if the import stopped early because of the row limit.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	groups := make([]chan [][]interface{}, f.numGroups())
+	for i := range groups {
+		groups[i] = make(chan [][]interface{}, 1)
+	}
+	sem := make(chan struct{}, parallelism)
+	group := ctxgroup.WithContext(ctx)
+	group.GoCtx(func(ctx context.Context) error {
+		for i := range groups {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return nil
+			}
+			i := i
+			group.GoCtx(func(ctx context.Context) error {
+				rows, err := f.readGroup(i)
+				if err != nil {
+					return err
+				}
+				groups[i] <- rows
+				return nil
+			})
+		}
+		return nil
+	})
+	group.GoCtx(func(ctx context.Context) error {
+		defer cancel()
+		producer := &columnarRowStream{
+			ctx:     ctx,
+			groups:  groups,
+			sem:     sem,
+			numRows: f.numRows(),
+		}
+		return runParallelImport(ctx, c.importCtx, fileCtx, producer, consumer)
+	})
+	return group.Wait()
+}
+
+// columnarRowStream produces the rows of a columnar file from the groups read
+// by the workers started in columnarInputReader.readFile.
+type columnarRowStream struct {
+	ctx context.Context
+	// groups receives the rows of each group of the file once read.
+	groups []chan [][]interface{}
+	// sem bounds the number of groups being read or waiting to be consumed.
+	sem     chan struct{}
+	next    int
+	rows    [][]interface{}
+	row     []interface{}
+	numRows int64
+	done    int64
+	err     error
+}
+
+var _ importRowProducer = &columnarRowStream{}
+
+// Scan implements importRowProducer interface.
+func (s *columnarRowStream) Scan() bool {
+	for len(s.rows) == 0 {
+		if s.next == len(s.groups) {
+			return false
+		}
+		select {
+		case s.rows = <-s.groups[s.next]:
+			s.next++
+			<-s.sem
+		case <-s.ctx.Done():
+			s.err = s.ctx.Err()
+			return false
+		}
+	}
+	s.row, s.rows = s.rows[0], s.rows[1:]
+	s.done++
+	return true
+}
+
+// Err implements importRowProducer interface.
+func (s *columnarRowStream) Err() error {
+	return s.err
+}
+
+// Skip implements importRowProducer interface.
+func (s *columnarRowStream) Skip() error {
+	s.row = nil
+	return nil
+}
+
+// Row implements importRowProducer interface.
+func (s *columnarRowStream) Row() (interface{}, error) {
+	res := s.row
+	s.row = nil
+	return res, nil
+}
+
+// Progress implements importRowProducer interface.
+func (s *columnarRowStream) Progress() float32 {
+	if s.numRows == 0 {
+		return 0
+	}
+	return float32(s.done) / float32(s.numRows)
+}
+
+// columnarConsumer converts the rows of a columnar file to datums. Columns of
+// the file are matched by name to the columns of the table.
+type columnarConsumer struct {
+	file  columnarFile
+	names []string
+	// colIdx holds, for each column of the file, the index of the visible
+	// column of the table it is imported into, or -1 if it is not imported.
+	colIdx []int
+	// missing holds the indexes of the target columns absent from the file.
+	missing []int
+}
+
+var _ importRowConsumer = &columnarConsumer{}
+
+func newColumnarConsumer(
+	importCtx *parallelImportContext, f columnarFile, format string, strict bool,
+) (*columnarConsumer, error) {
+	var targets map[string]struct{}
+	if len(importCtx.targetCols) > 0 {
+		targets = make(map[string]struct{}, len(importCtx.targetCols))
+		for _, name := range importCtx.targetCols {
+			targets[string(name)] = struct{}{}
+		}
+	}
+	colIdxByName := make(map[string]int)
+	for idx, col := range importCtx.tableDesc.VisibleColumns() {
+		if _, ok := targets[col.GetName()]; targets == nil || ok {
+			colIdxByName[col.GetName()] = idx
+		}
+	}
+
+	names := f.columnNames()
+	c := &columnarConsumer{file: f, names: names, colIdx: make([]int, len(names))}
+	found := make(map[int]struct{}, len(names))
+	for i, name := range names {
+		idx, ok := colIdxByName[name]
+		if !ok {
+			idx, ok = colIdxByName[lexbase.NormalizeName(name)]
+		}
+		if !ok {
+			if strict {
+				return nil, errors.Newf("could not find column for %s column %s", format, name)
+			}
+			idx = -1
+		} else if _, ok := found[idx]; ok {
+			return nil, errors.Newf("%s column %s maps to a column already set by another column", format, name)
+		}
+		c.colIdx[i] = idx
+		found[idx] = struct{}{}
+	}
+	for name, idx := range colIdxByName {
+		if _, ok := found[idx]; !ok {
+			if strict {
+				return nil, errors.Newf("column %s was not found in the %s file", name, format)
+			}
+			c.missing = append(c.missing, idx)
+		}
+	}
+	return c, nil
+}
+
+// FillDatums implements importRowConsumer interface.
+func (c *columnarConsumer) FillDatums(
+	native interface{}, rowIndex int64, conv *row.DatumRowConverter,
+) error {
+	values := native.([]interface{})
+	for i, v := range values {
+		idx := c.colIdx[i]
+		if idx < 0 {
+			continue
+		}
+		d, err := c.file.valueToDatum(v, i, conv.VisibleColTypes[idx], conv.EvalCtx)
+		if err != nil {
+			return wrapRowErr(err, rowIndex, pgcode.Uncategorized, "column %q", c.names[i])
+		}
+		conv.Datums[idx] = d
+	}
+	for _, idx := range c.missing {
+		conv.Datums[idx] = tree.DNull
+	}
+	return nil
+}
+
+// externalStorageReaderAt implements io.ReaderAt on top of the file held by
+// an ExternalStorage.
+type externalStorageReaderAt struct {
+	ctx   context.Context
+	store cloud.ExternalStorage
+}
+
+// ReadAt implements io.ReaderAt.
+func (r *externalStorageReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	body, _, err := r.store.ReadFileAt(r.ctx, "", off)
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+	return io.ReadFull(body, p)
+}
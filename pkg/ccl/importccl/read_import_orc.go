@@ -0,0 +1,130 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/orc"
+	"github.com/cockroachdb/errors"
+)
+
+// newORCInputReader returns an inputConverter which imports ORC files.
+func newORCInputReader(
+	semaCtx *tree.SemaContext,
+	kvCh chan row.KVBatch,
+	tableDesc catalog.TableDescriptor,
+	targetCols tree.NameList,
+	opts roachpb.ORCOptions,
+	walltime int64,
+	parallelism int,
+	evalCtx *tree.EvalContext,
+	seqChunkProvider *row.SeqChunkProvider,
+) *columnarInputReader {
+	c := newColumnarInputReader(semaCtx, kvCh, tableDesc, targetCols, walltime, parallelism,
+		evalCtx, seqChunkProvider)
+	c.format = "orc"
+	c.strict = opts.StrictMode
+	c.rowLimit = opts.RowLimit
+	c.open = func(ctx context.Context, store cloud.ExternalStorage) (columnarFile, error) {
+		r, err := openORCFile(ctx, store)
+		if err != nil {
+			return nil, err
+		}
+		return orcFile{r}, nil
+	}
+	return c
+}
+
+// orcFile implements columnarFile for ORC files, whose groups of rows are
+// their stripes.
+type orcFile struct {
+	*orc.Reader
+}
+
+var _ columnarFile = orcFile{}
+
+func (f orcFile) numRows() int64 {
+	return f.NumRows()
+}
+
+func (f orcFile) numGroups() int {
+	return f.NumStripes()
+}
+
+func (f orcFile) readGroup(i int) ([][]interface{}, error) {
+	rows, err := f.ReadStripe(i)
+	return rows, errors.Wrapf(err, "stripe %d", i)
+}
+
+func (f orcFile) columnNames() []string {
+	cols := f.Columns()
+	names := make([]string, len(cols))
+	for i := range cols {
+		names[i] = cols[i].Name
+	}
+	return names
+}
+
+func (f orcFile) valueToDatum(
+	v interface{}, i int, typ *types.T, evalCtx *tree.EvalContext,
+) (tree.Datum, error) {
+	d, err := orc.ValueToDatum(v, &f.Columns()[i])
+	if err != nil || d == tree.DNull || d.ResolvedType().Equivalent(typ) {
+		return d, err
+	}
+	return tree.PerformCast(evalCtx, d, typ)
+}
+
+// readORCCreateTable returns a CREATE TABLE statement for the given table
+// with a column for each column of the ORC file at uri.
+func readORCCreateTable(
+	ctx context.Context,
+	table *tree.TableName,
+	uri string,
+	externalStorageFromURI cloud.ExternalStorageFromURIFactory,
+	user security.SQLUsername,
+) (*tree.CreateTable, error) {
+	store, err := externalStorageFromURI(ctx, uri, user)
+	if err != nil {
+		return nil, err
+	}
+	defer store.Close()
+	r, err := openORCFile(ctx, store)
+	if err != nil {
+		return nil, err
+	}
+	create := &tree.CreateTable{Table: *table}
+	cols := r.Columns()
+	for i := range cols {
+		def, err := tree.NewColumnTableDef(tree.Name(cols[i].Name), orc.TypeForColumn(&cols[i]),
+			false /* isSerial */, nil /* qualifications */)
+		if err != nil {
+			return nil, err
+		}
+		create.Defs = append(create.Defs, def)
+	}
+	return create, nil
+}
+
+// openORCFile returns a reader of the ORC file held by store.
+func openORCFile(ctx context.Context, store cloud.ExternalStorage) (*orc.Reader, error) {
+	size, err := store.Size(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	return orc.NewReader(&externalStorageReaderAt{ctx: ctx, store: store}, size)
+}
@@ -0,0 +1,125 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl_test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/orc"
+	"github.com/stretchr/testify/require"
+)
+
+// writeORCTestFile writes an ORC file of numRows rows with small stripes, so
+// that imports read many stripes in parallel.
+func writeORCTestFile(t *testing.T, path string, numRows int) {
+	cols := []orc.Column{
+		{Name: "ID", Kind: orc.Long},
+		{Name: "name", Kind: orc.String},
+		{Name: "price", Kind: orc.Decimal, Precision: 10, Scale: 2},
+		{Name: "tags", Kind: orc.List, Elem: &orc.Column{Kind: orc.String}},
+		{Name: "at", Kind: orc.TimestampInstant},
+	}
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+	w, err := orc.NewWriter(f, cols, orc.WithMaxStripeRows(7))
+	require.NoError(t, err)
+	for i := 0; i < numRows; i++ {
+		row := []interface{}{
+			int64(i),
+			[]byte(fmt.Sprintf("name-%d", i)),
+			orc.DecimalValue{Unscaled: big.NewInt(int64(i*100 + 25)), Scale: 2},
+			nil,
+			time.Date(2021, 1, 1, i, 0, 0, 0, time.UTC),
+		}
+		if i%2 == 0 {
+			row[3] = []interface{}{[]byte("even")}
+		}
+		require.NoError(t, w.AddRow(row))
+	}
+	require.NoError(t, w.Close())
+}
+
+func TestImportORC(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	dir, cleanupDir := testutils.TempDir(t)
+	defer cleanupDir()
+
+	srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{ExternalIODir: dir})
+	defer srv.Stopper().Stop(context.Background())
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	const numRows = 100
+	writeORCTestFile(t, filepath.Join(dir, "data.orc"), numRows)
+	const data = `'nodelocal://0/data.orc'`
+
+	t.Run("infer-schema", func(t *testing.T) {
+		sqlDB.Exec(t, `IMPORT TABLE inferred FROM ORC `+data)
+		sqlDB.CheckQueryResults(t,
+			`SELECT column_name, data_type FROM [SHOW COLUMNS FROM inferred] WHERE NOT is_hidden`,
+			[][]string{
+				{"ID", "INT8"}, {"name", "STRING"}, {"price", "DECIMAL(10,2)"}, {"tags", "STRING[]"},
+				{"at", "TIMESTAMPTZ"},
+			})
+		sqlDB.CheckQueryResults(t,
+			`SELECT count(*), sum("ID"), sum(price), count(tags) FROM inferred`,
+			[][]string{{"100", "4950", "4975.00", "50"}})
+		sqlDB.CheckQueryResults(t,
+			`SELECT "ID", name, price, tags, at::STRING FROM inferred WHERE "ID" IN (6, 7) ORDER BY "ID"`,
+			[][]string{
+				{"6", "name-6", "6.25", "{even}", "2021-01-01 06:00:00+00:00"},
+				{"7", "name-7", "7.25", "NULL", "2021-01-01 07:00:00+00:00"},
+			})
+	})
+
+	t.Run("into", func(t *testing.T) {
+		// Column names are matched case-insensitively, types are cast to the
+		// types of the table, and columns missing from the file are NULL.
+		sqlDB.Exec(t, `CREATE TABLE t (id INT PRIMARY KEY, name STRING, price FLOAT, extra INT)`)
+		sqlDB.Exec(t, `IMPORT INTO t ORC DATA (`+data+`)`)
+		sqlDB.CheckQueryResults(t,
+			`SELECT count(*), sum(id), sum(price), count(extra) FROM t`,
+			[][]string{{"100", "4950", "4975", "0"}})
+
+		sqlDB.Exec(t, `CREATE TABLE target_cols (id INT PRIMARY KEY, name STRING DEFAULT 'x')`)
+		sqlDB.Exec(t, `IMPORT INTO target_cols (id) ORC DATA (`+data+`)`)
+		sqlDB.CheckQueryResults(t,
+			`SELECT count(*), count(DISTINCT name) FROM target_cols`, [][]string{{"100", "1"}})
+	})
+
+	t.Run("options", func(t *testing.T) {
+		sqlDB.Exec(t, `IMPORT TABLE limited FROM ORC `+data+` WITH row_limit = '10'`)
+		sqlDB.CheckQueryResults(t, `SELECT count(*) FROM limited`, [][]string{{"10"}})
+
+		sqlDB.Exec(t, `CREATE TABLE strict_cols (id INT PRIMARY KEY, name STRING)`)
+		sqlDB.ExpectErr(t, `could not find column for orc column price`,
+			`IMPORT INTO strict_cols ORC DATA (`+data+`) WITH strict_validation`)
+		sqlDB.ExpectErr(t, `invalid option "delimiter"`,
+			`IMPORT TABLE bad FROM ORC `+data+` WITH delimiter = '|'`)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		sqlDB.ExpectErr(t, `use IMPORT TABLE <name> FROM ORC`, `IMPORT ORC `+data)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "bad.orc"), []byte("this is not an orc file"), 0644))
+		sqlDB.ExpectErr(t, `not an orc file`, `IMPORT TABLE bad FROM ORC 'nodelocal://0/bad.orc'`)
+	})
+}
@@ -0,0 +1,145 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/parquet"
+	"github.com/cockroachdb/errors"
+)
+
+// newParquetInputReader returns an inputConverter which imports Parquet
+// files.
+func newParquetInputReader(
+	semaCtx *tree.SemaContext,
+	kvCh chan row.KVBatch,
+	tableDesc catalog.TableDescriptor,
+	targetCols tree.NameList,
+	opts roachpb.ParquetOptions,
+	walltime int64,
+	parallelism int,
+	evalCtx *tree.EvalContext,
+	seqChunkProvider *row.SeqChunkProvider,
+) *columnarInputReader {
+	c := newColumnarInputReader(semaCtx, kvCh, tableDesc, targetCols, walltime, parallelism,
+		evalCtx, seqChunkProvider)
+	c.format = "parquet"
+	c.strict = opts.StrictMode
+	c.rowLimit = opts.RowLimit
+	c.open = func(ctx context.Context, store cloud.ExternalStorage) (columnarFile, error) {
+		r, err := openParquetFile(ctx, store)
+		if err != nil {
+			return nil, err
+		}
+		return parquetFile{r}, nil
+	}
+	return c
+}
+
+// parquetFile implements columnarFile for Parquet files, whose groups of rows
+// are their row groups.
+type parquetFile struct {
+	*parquet.Reader
+}
+
+var _ columnarFile = parquetFile{}
+
+func (f parquetFile) numRows() int64 {
+	return f.NumRows()
+}
+
+func (f parquetFile) numGroups() int {
+	return f.NumRowGroups()
+}
+
+func (f parquetFile) readGroup(i int) ([][]interface{}, error) {
+	rows, err := f.ReadRowGroup(i)
+	return rows, errors.Wrapf(err, "row group %d", i)
+}
+
+func (f parquetFile) columnNames() []string {
+	cols := f.Columns()
+	names := make([]string, len(cols))
+	for i := range cols {
+		names[i] = cols[i].Name
+	}
+	return names
+}
+
+func (f parquetFile) valueToDatum(
+	v interface{}, i int, typ *types.T, evalCtx *tree.EvalContext,
+) (tree.Datum, error) {
+	return parquetValueToDatum(v, &f.Columns()[i], typ, evalCtx)
+}
+
+// parquetValueToDatum converts a value of a Parquet column to a datum of the
+// given type.
+func parquetValueToDatum(
+	v interface{}, col *parquet.Column, typ *types.T, evalCtx *tree.EvalContext,
+) (tree.Datum, error) {
+	d, err := parquet.ValueToDatum(v, col)
+	if err != nil || d == tree.DNull || d.ResolvedType().Equivalent(typ) {
+		return d, err
+	}
+	// Many writers store strings as byte arrays without annotating them, so
+	// bytes are parsed like the values of text formats rather than cast.
+	if b, ok := d.(*tree.DBytes); ok {
+		return rowenc.ParseDatumStringAs(typ, string(*b), evalCtx)
+	}
+	return tree.PerformCast(evalCtx, d, typ)
+}
+
+// readParquetCreateTable returns a CREATE TABLE statement for the given table
+// with a column for each column of the Parquet file at uri.
+func readParquetCreateTable(
+	ctx context.Context,
+	table *tree.TableName,
+	uri string,
+	externalStorageFromURI cloud.ExternalStorageFromURIFactory,
+	user security.SQLUsername,
+) (*tree.CreateTable, error) {
+	store, err := externalStorageFromURI(ctx, uri, user)
+	if err != nil {
+		return nil, err
+	}
+	defer store.Close()
+	r, err := openParquetFile(ctx, store)
+	if err != nil {
+		return nil, err
+	}
+	create := &tree.CreateTable{Table: *table}
+	cols := r.Columns()
+	for i := range cols {
+		def, err := tree.NewColumnTableDef(tree.Name(cols[i].Name), parquet.TypeForColumn(&cols[i]),
+			false /* isSerial */, nil /* qualifications */)
+		if err != nil {
+			return nil, err
+		}
+		create.Defs = append(create.Defs, def)
+	}
+	return create, nil
+}
+
+// openParquetFile returns a reader of the Parquet file held by store.
+func openParquetFile(ctx context.Context, store cloud.ExternalStorage) (*parquet.Reader, error) {
+	size, err := store.Size(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	return parquet.NewReader(&externalStorageReaderAt{ctx: ctx, store: store}, size)
+}
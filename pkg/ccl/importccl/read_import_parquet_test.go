@@ -0,0 +1,137 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/parquet"
+	"github.com/stretchr/testify/require"
+)
+
+// writeParquetTestFile writes a Parquet file of numRows rows with small row
+// groups, so that imports read many row groups in parallel.
+func writeParquetTestFile(t *testing.T, path string, numRows int) {
+	names := []string{"ID", "name", "price", "tags"}
+	typs := []*types.T{
+		types.Int, types.String, types.MakeDecimal(10, 2), types.MakeArray(types.String),
+	}
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+	w, err := parquet.NewWriter(f, parquet.NewColumns(names, typs), parquet.WithMaxRowGroupLength(7))
+	require.NoError(t, err)
+	for i := 0; i < numRows; i++ {
+		price, err := tree.ParseDDecimal(fmt.Sprintf("%d.25", i))
+		require.NoError(t, err)
+		datums := tree.Datums{
+			tree.NewDInt(tree.DInt(i)), tree.NewDString(fmt.Sprintf("name-%d", i)), price, tree.DNull,
+		}
+		if i%2 == 0 {
+			arr := tree.NewDArray(types.String)
+			require.NoError(t, arr.Append(tree.NewDString("even")))
+			datums[3] = arr
+		}
+		row := make([]interface{}, len(datums))
+		for j, d := range datums {
+			row[j], err = parquet.DatumToValue(d, typs[j])
+			require.NoError(t, err)
+		}
+		require.NoError(t, w.AddRow(row))
+	}
+	require.NoError(t, w.Close())
+}
+
+func TestImportParquet(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	dir, cleanupDir := testutils.TempDir(t)
+	defer cleanupDir()
+
+	srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{ExternalIODir: dir})
+	defer srv.Stopper().Stop(context.Background())
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	const numRows = 100
+	writeParquetTestFile(t, filepath.Join(dir, "data.parquet"), numRows)
+	const data = `'nodelocal://0/data.parquet'`
+
+	t.Run("infer-schema", func(t *testing.T) {
+		sqlDB.Exec(t, `IMPORT TABLE inferred FROM PARQUET `+data)
+		sqlDB.CheckQueryResults(t,
+			`SELECT column_name, data_type FROM [SHOW COLUMNS FROM inferred] WHERE NOT is_hidden`,
+			[][]string{
+				{"ID", "INT8"}, {"name", "STRING"}, {"price", "DECIMAL(10,2)"}, {"tags", "STRING[]"},
+			})
+		sqlDB.CheckQueryResults(t,
+			`SELECT count(*), sum("ID"), sum(price), count(tags) FROM inferred`,
+			[][]string{{"100", "4950", "4975.00", "50"}})
+		sqlDB.CheckQueryResults(t,
+			`SELECT "ID", name, price, tags FROM inferred WHERE "ID" IN (6, 7) ORDER BY "ID"`,
+			[][]string{{"6", "name-6", "6.25", "{even}"}, {"7", "name-7", "7.25", "NULL"}})
+	})
+
+	t.Run("into", func(t *testing.T) {
+		// Column names are matched case-insensitively, types are cast to the
+		// types of the table, and columns missing from the file are NULL.
+		sqlDB.Exec(t, `CREATE TABLE t (id INT PRIMARY KEY, name STRING, price FLOAT, extra INT)`)
+		sqlDB.Exec(t, `IMPORT INTO t PARQUET DATA (`+data+`)`)
+		sqlDB.CheckQueryResults(t,
+			`SELECT count(*), sum(id), sum(price), count(extra) FROM t`,
+			[][]string{{"100", "4950", "4975", "0"}})
+
+		sqlDB.Exec(t, `CREATE TABLE target_cols (id INT PRIMARY KEY, name STRING DEFAULT 'x')`)
+		sqlDB.Exec(t, `IMPORT INTO target_cols (id) PARQUET DATA (`+data+`)`)
+		sqlDB.CheckQueryResults(t,
+			`SELECT count(*), count(DISTINCT name) FROM target_cols`, [][]string{{"100", "1"}})
+	})
+
+	t.Run("options", func(t *testing.T) {
+		sqlDB.Exec(t, `IMPORT TABLE limited FROM PARQUET `+data+` WITH row_limit = '10'`)
+		sqlDB.CheckQueryResults(t, `SELECT count(*) FROM limited`, [][]string{{"10"}})
+
+		sqlDB.Exec(t, `CREATE TABLE strict_cols (id INT PRIMARY KEY, name STRING)`)
+		sqlDB.ExpectErr(t, `could not find column for parquet column price`,
+			`IMPORT INTO strict_cols PARQUET DATA (`+data+`) WITH strict_validation`)
+		sqlDB.ExpectErr(t, `invalid option "delimiter"`,
+			`IMPORT TABLE bad FROM PARQUET `+data+` WITH delimiter = '|'`)
+	})
+
+	t.Run("export-round-trip", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE TABLE src (
+			i INT PRIMARY KEY, s STRING, b BYTES, f FLOAT, t TIMESTAMPTZ, d DATE, u UUID, j JSONB, a INT[]
+		)`)
+		sqlDB.Exec(t, `INSERT INTO src VALUES
+			(1, 'a', 'b', 1.5, '2021-01-01 00:00:00+00', '2021-01-01',
+			 'e2e2a4e2-6b7c-4f5e-9f62-1c1a7a8c0d52', '{"a": 1}', ARRAY[1, NULL, 3]),
+			(2, NULL, NULL, NULL, NULL, NULL, NULL, NULL, NULL),
+			(3, '', '', -1.5, '2000-01-01 12:34:56.789+00', 'infinity', NULL, 'null', ARRAY[])`)
+		sqlDB.Exec(t, `EXPORT INTO PARQUET 'nodelocal://0/export' WITH chunk_rows = 2 FROM TABLE src`)
+		sqlDB.Exec(t, `IMPORT TABLE dst FROM PARQUET 'nodelocal://0/export/*.parquet'`)
+		sqlDB.CheckQueryResults(t, `SELECT * FROM dst ORDER BY i`, sqlDB.QueryStr(t, `SELECT * FROM src ORDER BY i`))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		sqlDB.ExpectErr(t, `use IMPORT TABLE <name> FROM PARQUET`, `IMPORT PARQUET `+data)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "bad.parquet"), []byte("this is not a parquet file"), 0644))
+		sqlDB.ExpectErr(t, `not a parquet file`, `IMPORT TABLE bad FROM PARQUET 'nodelocal://0/bad.parquet'`)
+	})
+}
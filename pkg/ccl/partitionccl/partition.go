@@ -11,8 +11,10 @@ package partitionccl
 import (
 	"context"
 	"strings"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
@@ -239,7 +241,28 @@ func createPartitioningImpl(
 		partDesc.List = append(partDesc.List, p)
 	}
 
-	for _, r := range partBy.Range {
+	ranges := partBy.Range
+	if partBy.Interval != nil {
+		if colOffset != 0 || numImplicitColumns != 0 || len(cols) != 1 {
+			return partDesc, pgerror.Newf(pgcode.FeatureNotSupported,
+				"INTERVAL partitioning requires a single partition column")
+		}
+		var err error
+		partDesc.Interval, err = sql.MakeIntervalPartitioning(ctx, evalCtx, partBy.Interval, cols[0])
+		if err != nil {
+			return partDesc, err
+		}
+		if len(ranges) == 0 {
+			ranges, err = sql.MakeIntervalRangePartitions(
+				partDesc.Interval, cols[0].GetType(), time.Time{}, evalCtx.GetTxnTimestamp(time.Microsecond).Time,
+			)
+			if err != nil {
+				return partDesc, err
+			}
+		}
+	}
+
+	for _, r := range ranges {
 		p := descpb.PartitioningDescriptor_Range{
 			Name: string(r.Name),
 		}
@@ -384,6 +407,20 @@ func createPartitioning(
 		}
 	}
 
+	if partBy != nil && partBy.Interval != nil {
+		if !st.Version.IsActive(ctx, clusterversion.IntervalPartitioning) {
+			return nil, newPartitioning, pgerror.Newf(pgcode.FeatureNotSupported,
+				"version %v must be finalized to use INTERVAL partitioning",
+				clusterversion.IntervalPartitioning)
+		}
+		// The partitions of an interval partitioning are maintained by a schedule
+		// which only knows about the primary index of the table.
+		if tableDesc.IsPartitionAllBy() || indexDesc.ID == 0 || indexDesc.ID != tableDesc.GetPrimaryIndexID() {
+			return nil, newPartitioning, pgerror.Newf(pgcode.FeatureNotSupported,
+				"INTERVAL partitioning is only supported on the primary index of a table")
+		}
+	}
+
 	newPartitioning, err = createPartitioningImpl(
 		ctx,
 		evalCtx,
@@ -397,6 +434,10 @@ func createPartitioning(
 	if err != nil {
 		return nil, descpb.PartitioningDescriptor{}, err
 	}
+	// Keep the schedule maintaining the partitions of the index, if any.
+	if newPartitioning.Interval != nil && indexDesc.Partitioning.Interval != nil {
+		newPartitioning.Interval.ScheduleID = indexDesc.Partitioning.Interval.ScheduleID
+	}
 	return newImplicitCols, newPartitioning, err
 }
 
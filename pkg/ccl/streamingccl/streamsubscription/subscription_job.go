@@ -0,0 +1,378 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package streamsubscription
+
+import (
+	"context"
+	gosql "database/sql"
+	gojson "encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/apd/v2"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/errors"
+)
+
+// subscriptionResumer runs a subscription: it streams the changes of the
+// tables of the subscribed publications from the source cluster, using a core
+// changefeed which emits the rows committed by each transaction in a single
+// message, and applies each of them in a transaction of the destination.
+type subscriptionResumer struct {
+	job *jobs.Job
+
+	// highWater is the timestamp up to which all the changes have been
+	// applied. Changes are streamed again from there when reconnecting.
+	highWater hlc.Timestamp
+	progress  jobspb.SubscriptionProgress
+}
+
+var _ jobs.Resumer = &subscriptionResumer{}
+
+// errSourceUnavailable marks the errors of the connection to the source
+// cluster, after which the subscription reconnects.
+var errSourceUnavailable = errors.New("subscription source unavailable")
+
+// Resume is part of the jobs.Resumer interface.
+func (s *subscriptionResumer) Resume(ctx context.Context, execCtx interface{}) error {
+	p := execCtx.(sql.JobExecContext)
+	details := s.job.Details().(jobspb.SubscriptionDetails)
+	progress := s.job.Progress()
+	if h := progress.GetHighWater(); h != nil {
+		s.highWater = *h
+	}
+	s.progress = *progress.GetSubscription()
+
+	opts := retry.Options{
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Minute,
+	}
+	for r := retry.StartWithCtx(ctx, opts); r.Next(); {
+		err := s.subscribe(ctx, p, details)
+		if !errors.Is(err, errSourceUnavailable) {
+			return err
+		}
+		log.Warningf(ctx, "subscription %q: reconnecting after error: %v", details.Name, err)
+	}
+	return ctx.Err()
+}
+
+// subscribe streams the changes from the source cluster until an error occurs.
+func (s *subscriptionResumer) subscribe(
+	ctx context.Context, p sql.JobExecContext, details jobspb.SubscriptionDetails,
+) error {
+	db, err := gosql.Open("postgres", details.ConnectionURI)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return errors.Mark(err, errSourceUnavailable)
+	}
+	defer conn.Close()
+
+	// The tables of the publications are resolved each time the subscription
+	// connects to the source, after which the tables added to publications
+	// aren't subscribed to until it reconnects.
+	sourceTables, err := publishedTables(ctx, conn, details.Publications)
+	if err != nil {
+		return errors.Mark(err, errSourceUnavailable)
+	}
+	override := sessiondata.InternalExecutorOverride{User: s.job.Payload().UsernameProto.Decode()}
+	origins, err := createOriginsTable(ctx, p.ExecCfg(), override, details.DatabaseID)
+	if err != nil {
+		return err
+	}
+	appliers, err := makeTableAppliers(ctx, p.ExecCfg(), details.DatabaseID, sourceTables, origins)
+	if err != nil {
+		return err
+	}
+
+	targets := make([]string, len(sourceTables))
+	for i := range sourceTables {
+		targets[i] = sourceTables[i].String()
+	}
+	feedOpts := []string{`envelope = 'transaction'`, `full_table_name`, `resolved`}
+	if !s.highWater.IsEmpty() {
+		feedOpts = append(feedOpts, fmt.Sprintf(`cursor = '%s'`, s.highWater.AsOfSystemTime()))
+	} else if !details.CopyData {
+		feedOpts = append(feedOpts, `no_initial_scan`)
+	}
+	rows, err := conn.QueryContext(ctx, fmt.Sprintf(
+		`EXPERIMENTAL CHANGEFEED FOR TABLE %s WITH %s`,
+		strings.Join(targets, ", "), strings.Join(feedOpts, ", "),
+	))
+	if err != nil {
+		return errors.Mark(errors.Wrap(err, "creating source changefeed"), errSourceUnavailable)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var ignoredTopic gosql.NullString
+		var ignoredKey, value []byte
+		if err := rows.Scan(&ignoredTopic, &ignoredKey, &value); err != nil {
+			return errors.Mark(err, errSourceUnavailable)
+		}
+		var msg struct {
+			Resolved string       `json:"resolved"`
+			Rows     []changedRow `json:"rows"`
+			Updated  string       `json:"updated"`
+		}
+		if err := gojson.Unmarshal(value, &msg); err != nil {
+			return errors.Wrap(err, "decoding changefeed message")
+		}
+		if msg.Resolved != "" {
+			resolved, err := parseTimestamp(msg.Resolved)
+			if err != nil {
+				return err
+			}
+			if err := s.checkpoint(ctx, resolved); err != nil {
+				return err
+			}
+			continue
+		}
+		updated, err := parseTimestamp(msg.Updated)
+		if err != nil {
+			return err
+		}
+		if err := s.applyTransaction(ctx, p.ExecCfg(), override, appliers, msg.Rows, updated); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return errors.Mark(errors.Wrap(err, "streaming source changefeed"), errSourceUnavailable)
+	}
+	return errors.Mark(errors.New("source changefeed ended"), errSourceUnavailable)
+}
+
+// applyTransaction applies the rows committed by a transaction of the source
+// cluster in a single transaction.
+func (s *subscriptionResumer) applyTransaction(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	override sessiondata.InternalExecutorOverride,
+	appliers map[string]*tableApplier,
+	rows []changedRow,
+	updated hlc.Timestamp,
+) error {
+	var applied, skipped int64
+	if err := execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		applied, skipped = 0, 0
+		for i := range rows {
+			a, ok := appliers[rows[i].Topic]
+			if !ok {
+				return errors.AssertionFailedf("unexpected changefeed topic %q", rows[i].Topic)
+			}
+			written, err := a.apply(ctx, execCfg.InternalExecutor, txn, override, &rows[i], updated)
+			if err != nil {
+				return err
+			}
+			if written {
+				applied++
+			} else {
+				skipped++
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	s.progress.AppliedRows += applied
+	s.progress.SkippedRows += skipped
+	return nil
+}
+
+// checkpoint records that all the changes up to the given timestamp have been
+// applied.
+func (s *subscriptionResumer) checkpoint(ctx context.Context, resolved hlc.Timestamp) error {
+	if resolved.LessEq(s.highWater) {
+		return nil
+	}
+	if err := s.job.Update(ctx, nil /* txn */, func(
+		txn *kv.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater,
+	) error {
+		if err := md.CheckRunningOrReverting(); err != nil {
+			return err
+		}
+		md.Progress.Progress = &jobspb.Progress_HighWater{HighWater: &resolved}
+		progress := s.progress
+		md.Progress.Details = &jobspb.Progress_Subscription{Subscription: &progress}
+		ju.UpdateProgress(md.Progress)
+		return nil
+	}); err != nil {
+		return err
+	}
+	s.highWater = resolved
+	return nil
+}
+
+// publishedTables returns the names of the tables of the given publications in
+// the database of the source cluster.
+func publishedTables(
+	ctx context.Context, conn *gosql.Conn, publications []string,
+) ([]tree.TableName, error) {
+	var database string
+	if err := conn.QueryRowContext(ctx, `SELECT current_database()`).Scan(&database); err != nil {
+		return nil, err
+	}
+	names := make([]string, len(publications))
+	for i, pub := range publications {
+		names[i] = lexbase.EscapeSQLString(pub)
+	}
+	pubList := strings.Join(names, ", ")
+
+	var found int
+	if err := conn.QueryRowContext(ctx, fmt.Sprintf(
+		`SELECT count(*) FROM pg_catalog.pg_publication WHERE pubname IN (%s)`, pubList,
+	)).Scan(&found); err != nil {
+		return nil, err
+	}
+	if found != len(publications) {
+		return nil, pgerror.Newf(pgcode.UndefinedObject,
+			"publications %s don't all exist in source database %q", pubList, database)
+	}
+
+	rows, err := conn.QueryContext(ctx, fmt.Sprintf(
+		`SELECT DISTINCT schemaname, tablename FROM pg_catalog.pg_publication_tables
+		WHERE pubname IN (%s) ORDER BY schemaname, tablename`, pubList,
+	))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var tables []tree.TableName
+	for rows.Next() {
+		var schema, table string
+		if err := rows.Scan(&schema, &table); err != nil {
+			return nil, err
+		}
+		tables = append(tables, tree.MakeTableNameWithSchema(
+			tree.Name(database), tree.Name(schema), tree.Name(table)))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(tables) == 0 {
+		return nil, pgerror.Newf(pgcode.UndefinedObject,
+			"publications %s don't have any tables", pubList)
+	}
+	return tables, nil
+}
+
+// createOriginsTable creates the table of the destination database in which
+// the origins of the applied rows are recorded, unless it exists, and returns
+// its name. It is shared by the subscriptions of the database.
+func createOriginsTable(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	override sessiondata.InternalExecutorOverride,
+	dbID descpb.ID,
+) (*tree.TableName, error) {
+	var dbName string
+	if err := sql.DescsTxn(ctx, execCfg, func(
+		ctx context.Context, txn *kv.Txn, col *descs.Collection,
+	) error {
+		_, dbDesc, err := col.GetImmutableDatabaseByID(ctx, txn, dbID,
+			tree.DatabaseLookupFlags{Required: true})
+		if err != nil {
+			return err
+		}
+		dbName = dbDesc.GetName()
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	name := tree.MakeTableNameWithSchema(
+		tree.Name(dbName), tree.PublicSchemaName, sql.ReplicationOriginsTableName)
+	if _, err := execCfg.InternalExecutor.ExecEx(
+		ctx, "subscription-create-origins", nil /* txn */, override,
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s %s`, name.String(), originsTableSchema),
+	); err != nil {
+		return nil, errors.Wrap(err, "creating replication origins table")
+	}
+	return &name, nil
+}
+
+// makeTableAppliers makes the tableAppliers of the tables of the destination
+// database which have the same names as the published tables, keyed by the
+// changefeed topic of the published table.
+func makeTableAppliers(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	dbID descpb.ID,
+	sourceTables []tree.TableName,
+	origins *tree.TableName,
+) (map[string]*tableApplier, error) {
+	appliers := make(map[string]*tableApplier, len(sourceTables))
+	err := sql.DescsTxn(ctx, execCfg, func(
+		ctx context.Context, txn *kv.Txn, col *descs.Collection,
+	) error {
+		_, dbDesc, err := col.GetImmutableDatabaseByID(ctx, txn, dbID,
+			tree.DatabaseLookupFlags{Required: true})
+		if err != nil {
+			return err
+		}
+		for i := range sourceTables {
+			name := tree.MakeTableNameWithSchema(tree.Name(dbDesc.GetName()),
+				sourceTables[i].SchemaName, sourceTables[i].ObjectName)
+			_, desc, err := col.GetImmutableTableByName(ctx, txn, &name,
+				tree.ObjectLookupFlagsWithRequired())
+			if err != nil {
+				return errors.Wrap(err, "resolving destination table")
+			}
+			a, err := makeTableApplier(name, desc, origins)
+			if err != nil {
+				return err
+			}
+			appliers[sourceTables[i].String()] = a
+		}
+		return nil
+	})
+	return appliers, err
+}
+
+// parseTimestamp parses a timestamp emitted by a changefeed.
+func parseTimestamp(s string) (hlc.Timestamp, error) {
+	d, _, err := apd.NewFromString(s)
+	if err != nil {
+		return hlc.Timestamp{}, errors.Wrapf(err, "parsing changefeed timestamp %q", s)
+	}
+	return tree.DecimalToHLC(d)
+}
+
+// OnFailOrCancel is part of the jobs.Resumer interface. The changes which were
+// applied are kept.
+func (s *subscriptionResumer) OnFailOrCancel(context.Context, interface{}) error {
+	return nil
+}
+
+func init() {
+	jobs.RegisterConstructor(
+		jobspb.TypeSubscription,
+		func(job *jobs.Job, settings *cluster.Settings) jobs.Resumer {
+			return &subscriptionResumer{job: job}
+		},
+	)
+}
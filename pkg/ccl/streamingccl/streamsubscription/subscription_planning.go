@@ -0,0 +1,255 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package streamsubscription
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+)
+
+const (
+	// optCopyData controls whether the existing rows of the published tables
+	// are copied when the subscription is created. It defaults to true.
+	optCopyData = "copy_data"
+)
+
+var subscriptionOptionExpectValues = map[string]sql.KVStringOptValidate{
+	optCopyData: sql.KVStringOptAny,
+}
+
+func createSubscriptionPlanHook(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (sql.PlanHookRowFn, colinfo.ResultColumns, []sql.PlanNode, bool, error) {
+	createStmt, ok := stmt.(*tree.CreateSubscription)
+	if !ok {
+		return nil, nil, nil, false, nil
+	}
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.LogicalReplication) {
+		return nil, nil, nil, false, pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to use subscriptions",
+			clusterversion.LogicalReplication)
+	}
+
+	uriFn, err := p.TypeAsString(ctx, createStmt.ConnectionURI, "CREATE SUBSCRIPTION")
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	optsFn, err := p.TypeAsStringOpts(ctx, createStmt.Options, subscriptionOptionExpectValues)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	fn := func(ctx context.Context, _ []sql.PlanNode, resultsCh chan<- tree.Datums) error {
+		ctx, span := tracing.ChildSpan(ctx, stmt.StatementTag())
+		defer span.Finish()
+
+		if err := utilccl.CheckEnterpriseEnabled(
+			p.ExecCfg().Settings, p.ExecCfg().ClusterID(), p.ExecCfg().Organization(),
+			"CREATE SUBSCRIPTION",
+		); err != nil {
+			return err
+		}
+		// Applying the changes of a subscription bypasses the privileges of the
+		// destination tables for the rows it writes, as PostgreSQL does for
+		// subscriptions, which require superuser privileges.
+		if err := p.RequireAdminRole(ctx, "CREATE SUBSCRIPTION"); err != nil {
+			return err
+		}
+
+		uri, err := uriFn()
+		if err != nil {
+			return err
+		}
+		opts, err := optsFn()
+		if err != nil {
+			return err
+		}
+		copyData := true
+		if v, ok := opts[optCopyData]; ok {
+			if copyData, err = strconv.ParseBool(v); err != nil {
+				return pgerror.Newf(pgcode.InvalidParameterValue,
+					"invalid value for %s: %q", optCopyData, v)
+			}
+		}
+
+		dbID, err := currentDatabaseID(ctx, p)
+		if err != nil {
+			return err
+		}
+		if _, found, err := findSubscriptionJob(ctx, p, dbID, string(createStmt.Name)); err != nil {
+			return err
+		} else if found {
+			return pgerror.Newf(pgcode.DuplicateObject,
+				"subscription %q already exists", createStmt.Name)
+		}
+
+		details := jobspb.SubscriptionDetails{
+			Name:          string(createStmt.Name),
+			ConnectionURI: uri,
+			Publications:  make([]string, len(createStmt.Publications)),
+			DatabaseID:    dbID,
+			CopyData:      copyData,
+		}
+		for i, pub := range createStmt.Publications {
+			details.Publications[i] = string(pub)
+		}
+
+		description, err := subscriptionJobDescription(p, createStmt, uri)
+		if err != nil {
+			return err
+		}
+		jr := jobs.Record{
+			Description: description,
+			Username:    p.User(),
+			Progress:    jobspb.SubscriptionProgress{},
+			Details:     details,
+		}
+		jobID := p.ExecCfg().JobRegistry.MakeJobID()
+		if _, err := p.ExecCfg().JobRegistry.CreateAdoptableJobWithTxn(
+			ctx, jr, jobID, p.ExtendedEvalContext().Txn,
+		); err != nil {
+			return err
+		}
+		telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("subscription"))
+		resultsCh <- tree.Datums{tree.NewDInt(tree.DInt(jobID))}
+		return nil
+	}
+
+	return fn, utilccl.DetachedJobExecutionResultHeader, nil, false, nil
+}
+
+func dropSubscriptionPlanHook(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (sql.PlanHookRowFn, colinfo.ResultColumns, []sql.PlanNode, bool, error) {
+	dropStmt, ok := stmt.(*tree.DropSubscription)
+	if !ok {
+		return nil, nil, nil, false, nil
+	}
+
+	fn := func(ctx context.Context, _ []sql.PlanNode, _ chan<- tree.Datums) error {
+		ctx, span := tracing.ChildSpan(ctx, stmt.StatementTag())
+		defer span.Finish()
+
+		if err := p.RequireAdminRole(ctx, "DROP SUBSCRIPTION"); err != nil {
+			return err
+		}
+
+		dbID, err := currentDatabaseID(ctx, p)
+		if err != nil {
+			return err
+		}
+		jobID, found, err := findSubscriptionJob(ctx, p, dbID, string(dropStmt.Name))
+		if err != nil {
+			return err
+		}
+		if !found {
+			if dropStmt.IfExists {
+				return nil
+			}
+			return pgerror.Newf(pgcode.UndefinedObject,
+				"subscription %q does not exist", dropStmt.Name)
+		}
+		telemetry.Inc(sqltelemetry.SchemaChangeDropCounter("subscription"))
+		return p.ExecCfg().JobRegistry.CancelRequested(ctx, p.ExtendedEvalContext().Txn, jobID)
+	}
+
+	return fn, nil, nil, false, nil
+}
+
+// subscriptionJobDescription returns the description of the job of the
+// subscription, in which the password of the connection URI is redacted.
+func subscriptionJobDescription(
+	p sql.PlanHookState, createStmt *tree.CreateSubscription, uri string,
+) (string, error) {
+	redactedURI, err := redactConnectionURI(uri)
+	if err != nil {
+		return "", err
+	}
+	c := *createStmt
+	c.ConnectionURI = tree.NewDString(redactedURI)
+	ann := p.ExtendedEvalContext().Annotations
+	return tree.AsStringWithFQNames(&c, ann), nil
+}
+
+// redactConnectionURI redacts the password of a postgres connection URI,
+// which can either be part of its user info or a query parameter.
+func redactConnectionURI(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+	if params := u.Query(); params.Get("password") != "" {
+		params.Set("password", "redacted")
+		u.RawQuery = params.Encode()
+	}
+	return u.Redacted(), nil
+}
+
+func currentDatabaseID(ctx context.Context, p sql.PlanHookState) (descpb.ID, error) {
+	if p.SessionData().Database == "" {
+		return 0, pgerror.New(pgcode.UndefinedDatabase,
+			"cannot create or drop a subscription without a current database")
+	}
+	dbDesc, err := p.ExtendedEvalContext().Descs.GetImmutableDatabaseByName(
+		ctx, p.ExtendedEvalContext().Txn, p.SessionData().Database,
+		tree.DatabaseLookupFlags{Required: true},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return dbDesc.GetID(), nil
+}
+
+// findSubscriptionJob returns the ID of the job of the named subscription of
+// the given database, if it hasn't terminated.
+func findSubscriptionJob(
+	ctx context.Context, p sql.PlanHookState, dbID descpb.ID, name string,
+) (jobspb.JobID, bool, error) {
+	rows, err := p.ExecCfg().InternalExecutor.QueryBufferedEx(
+		ctx, "find-subscription-job", p.ExtendedEvalContext().Txn,
+		sessiondata.InternalExecutorOverride{User: security.RootUserName()},
+		`SELECT id, payload FROM system.jobs WHERE status IN `+jobs.NonTerminalStatusTupleString,
+	)
+	if err != nil {
+		return 0, false, err
+	}
+	for _, row := range rows {
+		payload, err := jobs.UnmarshalPayload(row[1])
+		if err != nil {
+			return 0, false, err
+		}
+		details := payload.GetSubscription()
+		if details != nil && details.DatabaseID == dbID && details.Name == name {
+			return jobspb.JobID(tree.MustBeDInt(row[0])), true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+func init() {
+	sql.AddPlanHook(createSubscriptionPlanHook)
+	sql.AddPlanHook(dropSubscriptionPlanHook)
+}
@@ -0,0 +1,201 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package streamsubscription
+
+import (
+	"context"
+	gosql "database/sql"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	_ "github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl" // Ensure changefeed init hooks run.
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/testutils/jobutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+const serverSetupStatements = `
+SET CLUSTER SETTING kv.rangefeed.enabled = true;
+SET CLUSTER SETTING kv.closed_timestamp.target_duration = '100ms';
+SET CLUSTER SETTING changefeed.experimental_poll_interval = '10ms';
+CREATE DATABASE src;
+CREATE DATABASE dst;
+`
+
+// testDatabase is a database of the test server, which is either the source
+// or the destination of subscriptions.
+type testDatabase struct {
+	*sqlutils.SQLRunner
+	url url.URL
+}
+
+// startTestServer starts a server with a src and a dst database.
+func startTestServer(t *testing.T) (src, dst testDatabase, cleanup func()) {
+	ctx := context.Background()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{
+		Knobs: base.TestingKnobs{JobsTestingKnobs: jobs.NewTestingKnobsWithShortIntervals()},
+	})
+	sqlutils.MakeSQLRunner(db).Exec(t, serverSetupStatements)
+
+	pgURL, cleanupURL := sqlutils.PGUrl(t, s.ServingSQLAddr(), t.Name(), url.User(security.RootUser))
+	var dbs []*gosql.DB
+	for _, d := range []*testDatabase{&src, &dst} {
+		d.url = pgURL
+		d.url.Path = "src"
+		if d == &dst {
+			d.url.Path = "dst"
+		}
+		conn, err := gosql.Open("postgres", d.url.String())
+		require.NoError(t, err)
+		dbs = append(dbs, conn)
+		d.SQLRunner = sqlutils.MakeSQLRunner(conn)
+	}
+	return src, dst, func() {
+		for _, conn := range dbs {
+			require.NoError(t, conn.Close())
+		}
+		cleanupURL()
+		s.Stopper().Stop(ctx)
+	}
+}
+
+func TestSubscription(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	src, dst, cleanup := startTestServer(t)
+	defer cleanup()
+
+	const schema = `(k INT PRIMARY KEY, v STRING, a INT[], j JSONB, c INT AS (k * 2) STORED)`
+	src.Exec(t, `CREATE TABLE t `+schema)
+	dst.Exec(t, `CREATE TABLE t `+schema)
+	src.Exec(t, `CREATE TABLE other (k INT PRIMARY KEY)`)
+	src.Exec(t, `INSERT INTO t VALUES (1, 'a', ARRAY[1, 2], '{"x": 1}'), (2, 'b', NULL, NULL)`)
+	src.Exec(t, `INSERT INTO other VALUES (1)`)
+	src.Exec(t, `CREATE PUBLICATION pub FOR TABLE t`)
+
+	dst.ExpectErr(t, `invalid value for copy_data: "nope"`,
+		`CREATE SUBSCRIPTION sub CONNECTION $1 PUBLICATION pub WITH (copy_data = 'nope')`,
+		src.url.String())
+	dst.ExpectErr(t, `invalid option "nope"`,
+		`CREATE SUBSCRIPTION sub CONNECTION $1 PUBLICATION pub WITH (nope = 'true')`,
+		src.url.String())
+
+	var jobID jobspb.JobID
+	dst.QueryRow(t, `CREATE SUBSCRIPTION sub CONNECTION $1 PUBLICATION pub`,
+		src.url.String()).Scan(&jobID)
+	dst.ExpectErr(t, `subscription "sub" already exists`,
+		`CREATE SUBSCRIPTION sub CONNECTION $1 PUBLICATION pub`, src.url.String())
+
+	// The existing rows are copied, and then the changes are applied. The tables
+	// which aren't published aren't replicated, and the origins of the applied
+	// rows are recorded in a table of the destination database.
+	dst.CheckQueryResultsRetry(t, `SELECT k, v, a, j, c FROM t ORDER BY k`, [][]string{
+		{"1", "a", "{1,2}", `{"x": 1}`, "2"},
+		{"2", "b", "NULL", "NULL", "4"},
+	})
+	dst.CheckQueryResults(t, `SELECT table_name FROM [SHOW TABLES] ORDER BY table_name`,
+		[][]string{{"crdb_replication_origins"}, {"t"}})
+
+	src.Exec(t, `UPDATE t SET v = 'c', a = ARRAY[3] WHERE k = 1`)
+	src.Exec(t, `DELETE FROM t WHERE k = 2`)
+	src.Exec(t, `INSERT INTO t VALUES (3, 'd', ARRAY[]::INT[], '[1, "2"]')`)
+	dst.CheckQueryResultsRetry(t, `SELECT k, v, a, j, c FROM t ORDER BY k`, [][]string{
+		{"1", "c", "{3}", `{"x": 1}`, "2"},
+		{"3", "d", "{}", `[1, "2"]`, "6"},
+	})
+
+	// A change of the source isn't applied if the destination row was written
+	// more recently.
+	dst.Exec(t, `PAUSE JOB $1`, jobID)
+	jobutils.WaitForJobToPause(t, dst.SQLRunner, jobID)
+	src.Exec(t, `UPDATE t SET v = 'older' WHERE k = 1`)
+	dst.Exec(t, `UPDATE t SET v = 'newer' WHERE k = 1`)
+	src.Exec(t, `INSERT INTO t VALUES (4, 'e', NULL, NULL)`)
+	dst.Exec(t, `RESUME JOB $1`, jobID)
+	dst.CheckQueryResultsRetry(t, `SELECT k, v FROM t ORDER BY k`, [][]string{
+		{"1", "newer"},
+		{"3", "d"},
+		{"4", "e"},
+	})
+
+	// Changes are compared with the commit timestamp in the source of the last
+	// change which was applied to the row, rather than with the time at which
+	// it was applied, which is later than both changes when the subscription
+	// lags behind.
+	dst.Exec(t, `PAUSE JOB $1`, jobID)
+	jobutils.WaitForJobToPause(t, dst.SQLRunner, jobID)
+	src.Exec(t, `UPDATE t SET v = 'first' WHERE k = 3`)
+	src.Exec(t, `UPDATE t SET v = 'second' WHERE k = 3`)
+	src.Exec(t, `DELETE FROM t WHERE k = 4`)
+	src.Exec(t, `INSERT INTO t VALUES (4, 'again', NULL, NULL)`)
+	dst.Exec(t, `RESUME JOB $1`, jobID)
+	dst.CheckQueryResultsRetry(t, `SELECT k, v FROM t ORDER BY k`, [][]string{
+		{"1", "newer"},
+		{"3", "second"},
+		{"4", "again"},
+	})
+
+	dst.CheckQueryResultsRetry(t, fmt.Sprintf(
+		`SELECT high_water_timestamp IS NOT NULL FROM [SHOW JOBS] WHERE job_id = %d`, jobID,
+	), [][]string{{"true"}})
+	progress := jobutils.GetJobProgress(t, dst.SQLRunner, jobID)
+	require.Greater(t, progress.GetSubscription().AppliedRows, int64(0))
+	require.Greater(t, progress.GetSubscription().SkippedRows, int64(0))
+
+	dst.Exec(t, `DROP SUBSCRIPTION sub`)
+	jobutils.WaitForJobToCancel(t, dst.SQLRunner, jobID)
+	dst.Exec(t, `DROP SUBSCRIPTION IF EXISTS sub`)
+	dst.ExpectErr(t, `subscription "sub" does not exist`, `DROP SUBSCRIPTION sub`)
+}
+
+// TestSubscriptionActiveActive tests tables which are both published and
+// subscribed to, in both directions.
+func TestSubscriptionActiveActive(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	src, dst, cleanup := startTestServer(t)
+	defer cleanup()
+
+	for _, db := range []testDatabase{src, dst} {
+		db.Exec(t, `CREATE TABLE t (k INT PRIMARY KEY, v STRING)`)
+		db.Exec(t, `CREATE PUBLICATION pub FOR ALL TABLES`)
+	}
+	src.Exec(t, `CREATE SUBSCRIPTION sub CONNECTION $1 PUBLICATION pub`, dst.url.String())
+	dst.Exec(t, `CREATE SUBSCRIPTION sub CONNECTION $1 PUBLICATION pub`, src.url.String())
+
+	src.Exec(t, `INSERT INTO t VALUES (1, 'src'), (2, 'src')`)
+	dst.Exec(t, `INSERT INTO t VALUES (3, 'dst')`)
+	// The last write of the row wins on both sides.
+	dst.Exec(t, `UPSERT INTO t VALUES (2, 'dst')`)
+
+	expected := [][]string{{"1", "src"}, {"2", "dst"}, {"3", "dst"}}
+	src.CheckQueryResultsRetry(t, `SELECT k, v FROM t ORDER BY k`, expected)
+	dst.CheckQueryResultsRetry(t, `SELECT k, v FROM t ORDER BY k`, expected)
+
+	dst.Exec(t, `DELETE FROM t WHERE k = 1`)
+	expected = [][]string{{"2", "dst"}, {"3", "dst"}}
+	src.CheckQueryResultsRetry(t, `SELECT k, v FROM t ORDER BY k`, expected)
+	dst.CheckQueryResultsRetry(t, `SELECT k, v FROM t ORDER BY k`, expected)
+
+	// The tables recording the origins of the applied rows aren't published.
+	for _, db := range []testDatabase{src, dst} {
+		db.CheckQueryResults(t, `SELECT tablename FROM pg_catalog.pg_publication_tables`,
+			[][]string{{"t"}})
+	}
+}
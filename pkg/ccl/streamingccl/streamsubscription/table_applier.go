@@ -0,0 +1,331 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package streamsubscription
+
+import (
+	"context"
+	gojson "encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/errors"
+)
+
+// changedRow is a row of a transaction emitted by a changefeed with
+// envelope=transaction.
+type changedRow struct {
+	Topic string `json:"topic"`
+	// Key holds the values of the primary key columns of the row.
+	Key gojson.RawMessage `json:"key"`
+	// After holds the values of the columns of the row, or is null if the row
+	// was deleted.
+	After gojson.RawMessage `json:"after"`
+}
+
+func (r *changedRow) deleted() bool {
+	return len(r.After) == 0 || string(r.After) == "null"
+}
+
+// tableApplier applies the changed rows of a published table to the table of
+// the same name in the destination database. The statements it runs on the
+// destination table take the JSON encoding of the row as their only
+// placeholder, and convert its fields into the types of the destination
+// columns.
+type tableApplier struct {
+	name tree.TableName
+	id   descpb.ID
+	// columns are the names of the columns of the destination table which are
+	// written by the applier, i.e. all its public columns but the computed
+	// ones, which must be part of the changed rows.
+	columns map[string]struct{}
+	// computed are the names of the computed columns of the destination table,
+	// whose values in the changed rows are ignored.
+	computed map[string]struct{}
+
+	// lookupRow returns the MVCC timestamp of the row with the primary key of
+	// an upserted row, and whether it already has the same values.
+	lookupRow string
+	// lookupKey returns the MVCC timestamp of the row with the primary key of a
+	// deleted row.
+	lookupKey string
+	upsert    string
+	delete    string
+
+	// lookupOrigin returns the commit timestamp in the source cluster of the
+	// last change of a row which was applied, along with the MVCC timestamp at
+	// which it was applied and whether it deleted the row, and recordOrigin
+	// records them. The rows are identified by the ID of the destination table
+	// and the JSON encoding of their primary key.
+	lookupOrigin string
+	recordOrigin string
+}
+
+// originsTableSchema is the schema of the table in which the origins of the
+// applied rows are recorded.
+const originsTableSchema = `(
+	table_id INT8 NOT NULL,
+	key STRING NOT NULL,
+	origin_timestamp DECIMAL NOT NULL,
+	deleted BOOL NOT NULL,
+	PRIMARY KEY (table_id, key)
+)`
+
+// makeTableApplier makes the tableApplier of the given destination table,
+// which records the origins of its rows in the given table. The primary key of
+// the destination table must have the same columns as the one of the published
+// table.
+func makeTableApplier(
+	name tree.TableName, desc catalog.TableDescriptor, origins *tree.TableName,
+) (*tableApplier, error) {
+	if !desc.IsTable() || desc.IsVirtualTable() {
+		return nil, pgerror.Newf(pgcode.WrongObjectType,
+			"%q is not a table", tree.ErrString(&name))
+	}
+	a := &tableApplier{
+		name:     name,
+		id:       desc.GetID(),
+		columns:  make(map[string]struct{}),
+		computed: make(map[string]struct{}),
+	}
+	var columns, values []string
+	for _, col := range desc.PublicColumns() {
+		if col.IsComputed() {
+			a.computed[col.GetName()] = struct{}{}
+			continue
+		}
+		a.columns[col.GetName()] = struct{}{}
+		columns = append(columns, tree.NameString(col.GetName()))
+		values = append(values, fieldExpr(lexbase.EscapeSQLString(col.GetName()), col.GetType()))
+	}
+
+	pk := desc.GetPrimaryIndex()
+	var keyColumns, rowKey, key []string
+	for i := 0; i < pk.NumKeyColumns(); i++ {
+		col, err := desc.FindColumnWithID(pk.GetKeyColumnID(i))
+		if err != nil {
+			return nil, err
+		}
+		keyColumns = append(keyColumns, tree.NameString(col.GetName()))
+		rowKey = append(rowKey, fieldExpr(lexbase.EscapeSQLString(col.GetName()), col.GetType()))
+		key = append(key, fieldExpr(fmt.Sprint(i), col.GetType()))
+	}
+
+	tableName := name.String()
+	a.lookupRow = fmt.Sprintf(
+		`SELECT crdb_internal_mvcc_timestamp, (%s) IS NOT DISTINCT FROM (%s) FROM %s WHERE (%s) = (%s)`,
+		strings.Join(columns, ", "), strings.Join(values, ", "), tableName,
+		strings.Join(keyColumns, ", "), strings.Join(rowKey, ", "),
+	)
+	a.lookupKey = fmt.Sprintf(`SELECT crdb_internal_mvcc_timestamp FROM %s WHERE (%s) = (%s)`,
+		tableName, strings.Join(keyColumns, ", "), strings.Join(key, ", "))
+	a.upsert = fmt.Sprintf(`UPSERT INTO %s (%s) VALUES (%s)`,
+		tableName, strings.Join(columns, ", "), strings.Join(values, ", "))
+	a.delete = fmt.Sprintf(`DELETE FROM %s WHERE (%s) = (%s)`,
+		tableName, strings.Join(keyColumns, ", "), strings.Join(key, ", "))
+	a.lookupOrigin = fmt.Sprintf(
+		`SELECT origin_timestamp, crdb_internal_mvcc_timestamp, deleted FROM %s
+		WHERE table_id = $1 AND key = $2`, origins.String())
+	a.recordOrigin = fmt.Sprintf(
+		`UPSERT INTO %s (table_id, key, origin_timestamp, deleted) VALUES ($1, $2, $3, $4)`,
+		origins.String())
+	return a, nil
+}
+
+// fieldExpr returns the expression which converts the given field of the JSON
+// placeholder, as encoded by changefeeds, into a value of the given type.
+func fieldExpr(field string, typ *types.T) string {
+	const placeholder = `$1::JSONB`
+	switch typ.Family() {
+	case types.JsonFamily:
+		return fmt.Sprintf(`(%s->%s)`, placeholder, field)
+	case types.ArrayFamily:
+		return fmt.Sprintf(
+			`(CASE WHEN jsonb_typeof(%[1]s->%[2]s) = 'array' THEN `+
+				`ARRAY(SELECT e::%[3]s FROM jsonb_array_elements_text(%[1]s->%[2]s) `+
+				`WITH ORDINALITY AS a (e, i) ORDER BY i) END)`,
+			placeholder, field, typeName(typ.ArrayContents()))
+	case types.GeometryFamily:
+		return fmt.Sprintf(`st_geomfromgeojson(%s->>%s)`, placeholder, field)
+	case types.GeographyFamily:
+		return fmt.Sprintf(`st_geogfromgeojson(%s->>%s)`, placeholder, field)
+	default:
+		return fmt.Sprintf(`(%s->>%s)::%s`, placeholder, field, typeName(typ))
+	}
+}
+
+// typeName returns the name of the given type in casts. User-defined types
+// are referenced by OID, since their names can change.
+func typeName(typ *types.T) string {
+	if typ.UserDefined() {
+		return fmt.Sprintf("@%d", typ.Oid())
+	}
+	return typ.SQLString()
+}
+
+// apply applies the changed row, committed at the given timestamp, to the
+// destination table, unless its row was written more recently. That is, the
+// last write wins, which lets tables be both published and subscribed to in
+// active-active configurations. Rows which already have the same values aren't
+// written again, which stops a change from being replicated back and forth.
+// It returns whether the row was written.
+//
+// The commit timestamp of the applied change is recorded in the origins table,
+// in the same transaction, so that later changes are compared with it rather
+// than with the timestamp at which it was applied, which is later when the
+// subscription lags behind the source.
+func (a *tableApplier) apply(
+	ctx context.Context,
+	ie *sql.InternalExecutor,
+	txn *kv.Txn,
+	override sessiondata.InternalExecutorOverride,
+	row *changedRow,
+	updated hlc.Timestamp,
+) (bool, error) {
+	key, err := tree.ParseDJSON(string(row.Key))
+	if err != nil {
+		return false, err
+	}
+	originKey := tree.NewDString(key.(*tree.DJSON).JSON.String())
+
+	var after tree.Datum
+	var existing tree.Datums
+	if row.deleted() {
+		existing, err = ie.QueryRowEx(ctx, "subscription-lookup-key", txn, override,
+			a.lookupKey, key)
+	} else {
+		if err := a.checkColumns(row); err != nil {
+			return false, err
+		}
+		if after, err = tree.ParseDJSON(string(row.After)); err != nil {
+			return false, err
+		}
+		existing, err = ie.QueryRowEx(ctx, "subscription-lookup-row", txn, override,
+			a.lookupRow, after)
+	}
+	if err != nil {
+		return false, err
+	}
+	origin, err := ie.QueryRowEx(ctx, "subscription-lookup-origin", txn, override,
+		a.lookupOrigin, tree.NewDInt(tree.DInt(a.id)), originKey)
+	if err != nil {
+		return false, err
+	}
+	if written, err := writtenSince(existing, origin, updated); err != nil || written {
+		return false, err
+	}
+
+	if row.deleted() {
+		if existing == nil {
+			return false, nil
+		}
+		if _, err := ie.ExecEx(ctx, "subscription-delete", txn, override, a.delete, key); err != nil {
+			return false, err
+		}
+	} else {
+		if existing != nil && tree.MustBeDBool(existing[1]) {
+			return false, nil
+		}
+		if _, err := ie.ExecEx(ctx, "subscription-upsert", txn, override, a.upsert, after); err != nil {
+			return false, err
+		}
+	}
+	_, err = ie.ExecEx(ctx, "subscription-record-origin", txn, override, a.recordOrigin,
+		tree.NewDInt(tree.DInt(a.id)), originKey, tree.TimestampToDecimalDatum(updated),
+		tree.MakeDBool(tree.DBool(row.deleted())))
+	return err == nil, err
+}
+
+// checkColumns checks that the changed row has the columns of the destination
+// table, and no others.
+func (a *tableApplier) checkColumns(row *changedRow) error {
+	var after map[string]gojson.RawMessage
+	if err := gojson.Unmarshal(row.After, &after); err != nil {
+		return errors.Wrapf(err, "decoding row of %s", tree.ErrString(&a.name))
+	}
+	for col := range after {
+		_, ok := a.columns[col]
+		if _, computed := a.computed[col]; !ok && !computed {
+			return pgerror.Newf(pgcode.UndefinedColumn,
+				"column %q of the published table does not exist in destination table %s",
+				col, tree.ErrString(&a.name))
+		}
+	}
+	for col := range a.columns {
+		if _, ok := after[col]; !ok {
+			return pgerror.Newf(pgcode.UndefinedColumn,
+				"column %q of destination table %s does not exist in the published table",
+				col, tree.ErrString(&a.name))
+		}
+	}
+	return nil
+}
+
+// writtenSince returns whether a destination row was last written at or after
+// the given timestamp, given the row, whose first column is its MVCC
+// timestamp, and the origin of the last change applied to it, either of which
+// can be nil.
+//
+// The rows whose MVCC timestamp is the one of their origin were last written by
+// the subscription, at the commit timestamp of the change in the source. The
+// other ones were written locally since then, at their MVCC timestamp. The rows
+// which were deleted by the subscription were deleted at the commit timestamp
+// of the change, and the ones deleted locally since they were written by the
+// subscription were deleted at some point after it was applied, which is used
+// as the timestamp of the deletion.
+func writtenSince(row, origin tree.Datums, ts hlc.Timestamp) (bool, error) {
+	var lastWrite hlc.Timestamp
+	var err error
+	switch {
+	case origin == nil && row == nil:
+		return false, nil
+	case origin == nil:
+		lastWrite, err = datumToHLC(row[0])
+	case row == nil && tree.MustBeDBool(origin[2]):
+		lastWrite, err = datumToHLC(origin[0])
+	case row == nil:
+		lastWrite, err = datumToHLC(origin[1])
+	default:
+		var applied hlc.Timestamp
+		if lastWrite, err = datumToHLC(row[0]); err != nil {
+			return false, err
+		}
+		if applied, err = datumToHLC(origin[1]); err != nil {
+			return false, err
+		}
+		if applied.EqOrdering(lastWrite) {
+			lastWrite, err = datumToHLC(origin[0])
+		}
+	}
+	if err != nil {
+		return false, err
+	}
+	return ts.LessEq(lastWrite), nil
+}
+
+// datumToHLC converts a DECIMAL timestamp, such as an MVCC timestamp, into an
+// HLC timestamp.
+func datumToHLC(d tree.Datum) (hlc.Timestamp, error) {
+	dec, ok := d.(*tree.DDecimal)
+	if !ok {
+		return hlc.Timestamp{}, errors.AssertionFailedf(
+			"unexpected timestamp datum type %T", d)
+	}
+	return tree.DecimalToHLC(&dec.Decimal)
+}
@@ -291,6 +291,62 @@ const (
 	// V21_2 is CockroachDB v21.2. It's used for all v21.2.x patch releases.
 	V21_2
 
+	// v22.1 versions.
+	//
+	// Start22_1 demarcates work towards CockroachDB v22.1.
+	Start22_1
+	// NotificationsTable adds the system.notifications table, which backs
+	// LISTEN/NOTIFY.
+	NotificationsTable
+	// ReadCommittedIsolation enables READ COMMITTED transactions, whose
+	// isolation level is understood by all nodes.
+	ReadCommittedIsolation
+	// AdvisoryLocksTable adds the system.advisory_locks table, which backs
+	// pg_advisory_lock() and friends.
+	AdvisoryLocksTable
+	// Triggers enables CREATE TRIGGER. Nodes running older versions ignore the
+	// triggers stored in table descriptors.
+	Triggers
+	// UserDefinedFunctions enables CREATE FUNCTION, which writes function
+	// descriptors that nodes running older versions cannot decode.
+	UserDefinedFunctions
+	// IntervalPartitioning enables PARTITION BY RANGE ... INTERVAL. Nodes
+	// running older versions drop the interval from the partitioning of a
+	// table descriptor when they rewrite it.
+	IntervalPartitioning
+	// RowLevelTTL enables the ttl_expire_after storage parameter. Nodes running
+	// older versions drop the row-level TTL of a table descriptor when they
+	// rewrite it.
+	RowLevelTTL
+	// RowLevelSecurity enables CREATE POLICY and ALTER TABLE ... ENABLE ROW LEVEL
+	// SECURITY. Nodes running older versions ignore the policies of a table and
+	// would return the rows they hide.
+	RowLevelSecurity
+	// LoadBasedRebalancingObjectives enables load-based rebalancing and splitting
+	// on request time and write bytes. Nodes running older versions don't
+	// populate the request_time_per_second and write_bytes_per_second fields of
+	// their StoreCapacity, so until then the objective is always qps.
+	LoadBasedRebalancingObjectives
+	// LogicalReplication enables CREATE PUBLICATION and CREATE SUBSCRIPTION.
+	// Nodes running older versions drop the publications of a database
+	// descriptor when they rewrite it, and can't decode subscription jobs.
+	LogicalReplication
+	// ForeignTables enables CREATE SERVER and CREATE FOREIGN TABLE. Nodes
+	// running older versions drop the foreign servers of a database descriptor
+	// and the foreign table options of a table descriptor when they rewrite
+	// them, and would treat foreign tables as regular, empty tables.
+	ForeignTables
+	// DeferrableConstraints enables DEFERRABLE foreign key and UNIQUE WITHOUT
+	// INDEX constraints. Nodes running older versions drop the deferrability of
+	// the constraints when they rewrite a table descriptor, and check them
+	// immediately.
+	DeferrableConstraints
+	// ReplicationSlots adds logical replication slots to database descriptors.
+	ReplicationSlots
+	// ChangefeedTransactionEnvelope allows changefeeds with
+	// envelope=transaction, whose flows have an updated column.
+	ChangefeedTransactionEnvelope
+
 	// *************************************************
 	// Step (1): Add new versions here.
 	// Do not add new versions to a patch release.
@@ -496,6 +552,68 @@ var versionsSingleton = keyedVersions{
 		Key:     V21_2,
 		Version: roachpb.Version{Major: 21, Minor: 2},
 	},
+
+	// v22.1 versions. Internal versions defined here-on-forth must be even.
+	{
+		Key:     Start22_1,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 2},
+	},
+	{
+		Key:     NotificationsTable,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 4},
+	},
+	{
+		Key:     ReadCommittedIsolation,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 6},
+	},
+	{
+		Key:     AdvisoryLocksTable,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 8},
+	},
+	{
+		Key:     Triggers,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 10},
+	},
+	{
+		Key:     UserDefinedFunctions,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 12},
+	},
+	{
+		Key:     IntervalPartitioning,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 14},
+	},
+	{
+		Key:     RowLevelTTL,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 16},
+	},
+	{
+		Key:     RowLevelSecurity,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 18},
+	},
+	{
+		Key:     LoadBasedRebalancingObjectives,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 20},
+	},
+	{
+		Key:     LogicalReplication,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 22},
+	},
+	{
+		Key:     ForeignTables,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 24},
+	},
+	{
+		Key:     DeferrableConstraints,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 26},
+	},
+	{
+		Key:     ReplicationSlots,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 28},
+	},
+	{
+		Key:     ChangefeedTransactionEnvelope,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 30},
+	},
 	// *************************************************
 	// Step (2): Add new versions here.
 	// Do not add new versions to a patch release.
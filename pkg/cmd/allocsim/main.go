@@ -43,6 +43,8 @@ var numNodes = flag.Int("n", 4, "number of nodes")
 var duration = flag.Duration("duration", math.MaxInt64, "how long to run the simulation for")
 var blockSize = flag.Int("b", 1000, "block size")
 var configFile = flag.String("f", "", "config file that specifies an allocsim workload (overrides -n)")
+var whatIfFile = flag.String("what-if", "", "config file that specifies a cluster to simulate in-process with the "+
+	"real allocator, instead of running a local cluster (ignores all other flags)")
 
 // Configuration provides a way to configure allocsim via a JSON file.
 // TODO(a-robinson): Consider moving all the above options into the config file.
@@ -416,6 +418,13 @@ func main() {
 
 	flag.Parse()
 
+	if *whatIfFile != "" {
+		if err := runWhatIf(context.Background(), *whatIfFile); err != nil {
+			log.Fatalf(context.Background(), "%v", err)
+		}
+		return
+	}
+
 	var config Configuration
 	if *configFile != "" {
 		var err error
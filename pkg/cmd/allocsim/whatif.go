@@ -0,0 +1,255 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/config/zonepb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/liveness/livenesspb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
+	"github.com/cockroachdb/errors"
+	"gopkg.in/yaml.v2"
+)
+
+const defaultWhatIfMaxTicks = 1000
+
+// WhatIfConfiguration describes a cluster whose replica and lease placement
+// is simulated in-process by a kvserver.AllocatorSimulator, and the changes
+// applied to it, one step at a time. It is read from a YAML (or JSON) file.
+type WhatIfConfiguration struct {
+	// Settings maps cluster setting names to their encoded values, e.g. "1m"
+	// for a duration or "1" for the second value of an enum.
+	Settings     map[string]string `yaml:"settings"`
+	TickInterval string            `yaml:"tick_interval"`
+	Stores       []WhatIfStore     `yaml:"stores"`
+	Ranges       []WhatIfRanges    `yaml:"ranges"`
+	Steps        []WhatIfStep      `yaml:"steps"`
+}
+
+// WhatIfStore describes a simulated store.
+type WhatIfStore struct {
+	StoreID  roachpb.StoreID `yaml:"store_id"`
+	NodeID   roachpb.NodeID  `yaml:"node_id"`
+	Attrs    []string        `yaml:"attrs"`
+	Locality string          `yaml:"locality"`
+	Capacity string          `yaml:"capacity"`
+}
+
+// WhatIfRanges describes Count simulated ranges with the same replicas,
+// load and zone config.
+type WhatIfRanges struct {
+	Count     int               `yaml:"count"`
+	Voters    []roachpb.StoreID `yaml:"voters"`
+	NonVoters []roachpb.StoreID `yaml:"non_voters"`
+	Zone      whatIfZoneConfig  `yaml:"zone"`
+
+	LogicalBytes         string  `yaml:"logical_bytes"`
+	QueriesPerSecond     float64 `yaml:"qps"`
+	WritesPerSecond      float64 `yaml:"writes_per_second"`
+	RequestTimePerSecond string  `yaml:"request_time_per_second"`
+	WriteBytesPerSecond  string  `yaml:"write_bytes_per_second"`
+}
+
+// WhatIfStep is a change to the simulated cluster, after which the simulator
+// runs until the allocator stops making changes.
+type WhatIfStep struct {
+	Name string `yaml:"name"`
+	// Zone, if set, replaces the zone config of all ranges.
+	Zone *whatIfZoneConfig `yaml:"zone"`
+	// Liveness maps node IDs to their new liveness status: live, dead,
+	// decommissioning or decommissioned.
+	Liveness map[roachpb.NodeID]string `yaml:"liveness"`
+	MaxTicks int                       `yaml:"max_ticks"`
+}
+
+// whatIfZoneConfig is a zone config whose unset fields are inherited from
+// the default zone config.
+type whatIfZoneConfig struct {
+	zonepb.ZoneConfig
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (z *whatIfZoneConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	z.ZoneConfig = zonepb.DefaultZoneConfig()
+	return unmarshal(&z.ZoneConfig)
+}
+
+func (z *whatIfZoneConfig) spanConfig() (roachpb.SpanConfig, error) {
+	if z.ZoneConfig.Equal(zonepb.ZoneConfig{}) {
+		z.ZoneConfig = zonepb.DefaultZoneConfig()
+	}
+	if err := z.Validate(); err != nil {
+		return roachpb.SpanConfig{}, err
+	}
+	return z.AsSpanConfig(), nil
+}
+
+var whatIfLiveness = map[string]livenesspb.NodeLivenessStatus{
+	"live":            livenesspb.NodeLivenessStatus_LIVE,
+	"dead":            livenesspb.NodeLivenessStatus_DEAD,
+	"decommissioning": livenesspb.NodeLivenessStatus_DECOMMISSIONING,
+	"decommissioned":  livenesspb.NodeLivenessStatus_DECOMMISSIONED,
+}
+
+func loadWhatIfConfig(file string) (WhatIfConfiguration, error) {
+	var config WhatIfConfiguration
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return config, errors.Wrapf(err, "failed to read config file %q", file)
+	}
+	if err := yaml.UnmarshalStrict(b, &config); err != nil {
+		return config, errors.Wrapf(err, "failed to decode %q", file)
+	}
+	return config, nil
+}
+
+// parseByteSize parses a humanized byte size, which defaults to zero.
+func parseByteSize(s string) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return humanizeutil.ParseBytes(s)
+}
+
+func (c WhatIfConfiguration) simulatorConfig(
+	ctx context.Context,
+) (kvserver.AllocatorSimulatorConfig, error) {
+	var cfg kvserver.AllocatorSimulatorConfig
+	// The simulated cluster runs at the binary's version.
+	cfg.Settings = cluster.MakeTestingClusterSettings()
+	updater := settings.NewUpdater(&cfg.Settings.SV)
+	for name, value := range c.Settings {
+		setting, ok := settings.Lookup(name, settings.LookupForLocalAccess)
+		if !ok {
+			return cfg, errors.Errorf("unknown setting %q", name)
+		}
+		if err := updater.Set(ctx, name, value, setting.Typ()); err != nil {
+			return cfg, errors.Wrapf(err, "setting %q", name)
+		}
+	}
+	if c.TickInterval != "" {
+		var err error
+		if cfg.TickInterval, err = time.ParseDuration(c.TickInterval); err != nil {
+			return cfg, errors.Wrap(err, "tick_interval")
+		}
+	}
+
+	for _, s := range c.Stores {
+		store := kvserver.SimulatedStore{
+			StoreID: s.StoreID,
+			NodeID:  s.NodeID,
+			Attrs:   roachpb.Attributes{Attrs: s.Attrs},
+		}
+		if s.Locality != "" {
+			if err := store.Locality.Set(s.Locality); err != nil {
+				return cfg, errors.Wrapf(err, "s%d", s.StoreID)
+			}
+		}
+		var err error
+		if store.Capacity, err = parseByteSize(s.Capacity); err != nil {
+			return cfg, errors.Wrapf(err, "s%d", s.StoreID)
+		}
+		cfg.Stores = append(cfg.Stores, store)
+	}
+
+	var rangeID roachpb.RangeID
+	for i := range c.Ranges {
+		r := &c.Ranges[i]
+		conf, err := r.Zone.spanConfig()
+		if err != nil {
+			return cfg, errors.Wrapf(err, "ranges[%d]", i)
+		}
+		usage := kvserver.RangeUsageInfo{
+			QueriesPerSecond: r.QueriesPerSecond,
+			WritesPerSecond:  r.WritesPerSecond,
+		}
+		if usage.LogicalBytes, err = parseByteSize(r.LogicalBytes); err != nil {
+			return cfg, errors.Wrapf(err, "ranges[%d]", i)
+		}
+		writeBytes, err := parseByteSize(r.WriteBytesPerSecond)
+		if err != nil {
+			return cfg, errors.Wrapf(err, "ranges[%d]", i)
+		}
+		usage.WriteBytesPerSecond = float64(writeBytes)
+		if r.RequestTimePerSecond != "" {
+			requestTime, err := time.ParseDuration(r.RequestTimePerSecond)
+			if err != nil {
+				return cfg, errors.Wrapf(err, "ranges[%d]", i)
+			}
+			usage.RequestTimePerSecond = float64(requestTime.Nanoseconds())
+		}
+		for j := 0; j < r.Count; j++ {
+			rangeID++
+			cfg.Ranges = append(cfg.Ranges, kvserver.SimulatedRange{
+				RangeID:   rangeID,
+				Voters:    r.Voters,
+				NonVoters: r.NonVoters,
+				Config:    conf,
+				Usage:     usage,
+			})
+		}
+	}
+	return cfg, nil
+}
+
+// runWhatIf simulates the cluster described by the given config file, and
+// prints a report of the allocator's decisions after each of its steps. The
+// initial placement is simulated first, as an implicit step.
+func runWhatIf(ctx context.Context, file string) error {
+	config, err := loadWhatIfConfig(file)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.simulatorConfig(ctx)
+	if err != nil {
+		return err
+	}
+	sim, err := kvserver.NewAllocatorSimulator(cfg)
+	if err != nil {
+		return err
+	}
+
+	steps := append([]WhatIfStep{{Name: "initial placement"}}, config.Steps...)
+	for i, step := range steps {
+		if step.Zone != nil {
+			conf, err := step.Zone.spanConfig()
+			if err != nil {
+				return errors.Wrapf(err, "step %q", step.Name)
+			}
+			if err := sim.SetSpanConfig(conf); err != nil {
+				return err
+			}
+		}
+		for nodeID, status := range step.Liveness {
+			s, ok := whatIfLiveness[strings.ToLower(status)]
+			if !ok {
+				return errors.Errorf("step %q: unknown liveness status %q for n%d", step.Name, status, nodeID)
+			}
+			sim.SetNodeLiveness(nodeID, s)
+		}
+		maxTicks := step.MaxTicks
+		if maxTicks == 0 {
+			maxTicks = defaultWhatIfMaxTicks
+		}
+		report := sim.Run(ctx, maxTicks)
+		fmt.Printf("step %d: %s\n%s\n", i, step.Name, report)
+	}
+	return nil
+}
@@ -13,6 +13,7 @@ package jobspb
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
@@ -44,6 +45,9 @@ var _ Details = StreamIngestionDetails{}
 var _ Details = NewSchemaChangeDetails{}
 var _ Details = MigrationDetails{}
 var _ Details = AutoSpanConfigReconciliationDetails{}
+var _ Details = SubscriptionDetails{}
+var _ Details = IntervalPartitioningDetails{}
+var _ Details = RowLevelTTLDetails{}
 
 // ProgressDetails is a marker interface for job progress details proto structs.
 type ProgressDetails interface{}
@@ -58,6 +62,9 @@ var _ ProgressDetails = StreamIngestionProgress{}
 var _ ProgressDetails = NewSchemaChangeProgress{}
 var _ ProgressDetails = MigrationProgress{}
 var _ ProgressDetails = AutoSpanConfigReconciliationDetails{}
+var _ ProgressDetails = SubscriptionProgress{}
+var _ ProgressDetails = IntervalPartitioningProgress{}
+var _ ProgressDetails = RowLevelTTLProgress{}
 
 // Type returns the payload's job type.
 func (p *Payload) Type() Type {
@@ -78,6 +85,8 @@ var AutomaticJobTypes = [...]Type{
 	TypeAutoCreateStats,
 	TypeAutoSpanConfigReconciliation,
 	TypeAutoSQLStatsCompaction,
+	TypeIntervalPartitioning,
+	TypeRowLevelTTL,
 }
 
 // DetailsType returns the type for a payload detail.
@@ -113,6 +122,12 @@ func DetailsType(d isPayload_Details) Type {
 		return TypeAutoSpanConfigReconciliation
 	case *Payload_AutoSQLStatsCompaction:
 		return TypeAutoSQLStatsCompaction
+	case *Payload_Subscription:
+		return TypeSubscription
+	case *Payload_IntervalPartitioning:
+		return TypeIntervalPartitioning
+	case *Payload_RowLevelTTL:
+		return TypeRowLevelTTL
 	default:
 		panic(errors.AssertionFailedf("Payload.Type called on a payload with an unknown details type: %T", d))
 	}
@@ -153,6 +168,12 @@ func WrapProgressDetails(details ProgressDetails) interface {
 		return &Progress_AutoSpanConfigReconciliation{AutoSpanConfigReconciliation: &d}
 	case AutoSQLStatsCompactionProgress:
 		return &Progress_AutoSQLStatsCompaction{AutoSQLStatsCompaction: &d}
+	case SubscriptionProgress:
+		return &Progress_Subscription{Subscription: &d}
+	case IntervalPartitioningProgress:
+		return &Progress_IntervalPartitioning{IntervalPartitioning: &d}
+	case RowLevelTTLProgress:
+		return &Progress_RowLevelTTL{RowLevelTTL: &d}
 	default:
 		panic(errors.AssertionFailedf("WrapProgressDetails: unknown details type %T", d))
 	}
@@ -188,6 +209,12 @@ func (p *Payload) UnwrapDetails() Details {
 		return *d.AutoSpanConfigReconciliation
 	case *Payload_AutoSQLStatsCompaction:
 		return *d.AutoSQLStatsCompaction
+	case *Payload_Subscription:
+		return *d.Subscription
+	case *Payload_IntervalPartitioning:
+		return *d.IntervalPartitioning
+	case *Payload_RowLevelTTL:
+		return *d.RowLevelTTL
 	default:
 		return nil
 	}
@@ -223,6 +250,12 @@ func (p *Progress) UnwrapDetails() ProgressDetails {
 		return *d.AutoSpanConfigReconciliation
 	case *Progress_AutoSQLStatsCompaction:
 		return *d.AutoSQLStatsCompaction
+	case *Progress_Subscription:
+		return *d.Subscription
+	case *Progress_IntervalPartitioning:
+		return *d.IntervalPartitioning
+	case *Progress_RowLevelTTL:
+		return *d.RowLevelTTL
 	default:
 		return nil
 	}
@@ -271,6 +304,12 @@ func WrapPayloadDetails(details Details) interface {
 		return &Payload_AutoSpanConfigReconciliation{AutoSpanConfigReconciliation: &d}
 	case AutoSQLStatsCompactionDetails:
 		return &Payload_AutoSQLStatsCompaction{AutoSQLStatsCompaction: &d}
+	case SubscriptionDetails:
+		return &Payload_Subscription{Subscription: &d}
+	case IntervalPartitioningDetails:
+		return &Payload_IntervalPartitioning{IntervalPartitioning: &d}
+	case RowLevelTTLDetails:
+		return &Payload_RowLevelTTL{RowLevelTTL: &d}
 	default:
 		panic(errors.AssertionFailedf("jobs.WrapPayloadDetails: unknown details type %T", d))
 	}
@@ -306,7 +345,7 @@ const (
 func (Type) SafeValue() {}
 
 // NumJobTypes is the number of jobs types.
-const NumJobTypes = 15
+const NumJobTypes = 18
 
 // MarshalJSONPB redacts sensitive sink URI parameters from ChangefeedDetails.
 func (p ChangefeedDetails) MarshalJSONPB(x *jsonpb.Marshaler) ([]byte, error) {
@@ -318,6 +357,21 @@ func (p ChangefeedDetails) MarshalJSONPB(x *jsonpb.Marshaler) ([]byte, error) {
 	return json.Marshal(p)
 }
 
+// MarshalJSONPB redacts the password of the connection URI from
+// SubscriptionDetails.
+func (p SubscriptionDetails) MarshalJSONPB(x *jsonpb.Marshaler) ([]byte, error) {
+	uri, err := url.Parse(p.ConnectionURI)
+	if err != nil {
+		return nil, err
+	}
+	if params := uri.Query(); params.Get("password") != "" {
+		params.Set("password", "redacted")
+		uri.RawQuery = params.Encode()
+	}
+	p.ConnectionURI = uri.Redacted()
+	return json.Marshal(p)
+}
+
 func init() {
 	if len(Type_name) != NumJobTypes {
 		panic(fmt.Errorf("NumJobTypes (%d) does not match generated job type name map length (%d)",
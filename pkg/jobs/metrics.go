@@ -27,6 +27,7 @@ type Metrics struct {
 
 	Changefeed   metric.Struct
 	StreamIngest metric.Struct
+	RowLevelTTL  metric.Struct
 
 	// AdoptIterations counts the number of adopt loops executed by Registry.
 	AdoptIterations *metric.Counter
@@ -171,6 +172,9 @@ func (m *Metrics) init(histogramWindowInterval time.Duration) {
 	if MakeStreamIngestMetricsHook != nil {
 		m.StreamIngest = MakeStreamIngestMetricsHook(histogramWindowInterval)
 	}
+	if MakeRowLevelTTLMetricsHook != nil {
+		m.RowLevelTTL = MakeRowLevelTTLMetricsHook(histogramWindowInterval)
+	}
 	m.AdoptIterations = metric.NewCounter(metaAdoptIterations)
 	m.ClaimedJobs = metric.NewCounter(metaClaimedJobs)
 	m.ResumedJobs = metric.NewCounter(metaResumedClaimedJobs)
@@ -200,6 +204,10 @@ var MakeChangefeedMetricsHook func(time.Duration) metric.Struct
 // ccl code.
 var MakeStreamIngestMetricsHook func(duration time.Duration) metric.Struct
 
+// MakeRowLevelTTLMetricsHook allows for registration of row-level TTL metrics
+// from sql code.
+var MakeRowLevelTTLMetricsHook func(time.Duration) metric.Struct
+
 // JobTelemetryMetrics is a telemetry metrics for individual job types.
 type JobTelemetryMetrics struct {
 	Successful telemetry.Counter
@@ -413,6 +413,8 @@ const (
 	TenantUsageTableID                  = 45
 	SQLInstancesTableID                 = 46
 	SpanConfigurationsTableID           = 47
+	NotificationsTableID                = 48
+	AdvisoryLocksTableID                = 49
 
 	// CommentType is type for system.comments
 	DatabaseCommentType = 0
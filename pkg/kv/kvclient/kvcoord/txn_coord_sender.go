@@ -132,6 +132,15 @@ type TxnCoordSender struct {
 		// caller of DeferCommitWait has assumed responsibility for performing
 		// the commit-wait.
 		commitWaitDeferred bool
+
+		// stmtSavepoint captures the state of the transaction at the start of
+		// the current statement, as established by StepReadTimestamp. It is only
+		// populated on rootTxns whose isolation level takes a new read snapshot
+		// in each statement. Such transactions roll back to it in order to
+		// retry the current statement instead of restarting when they encounter
+		// a retryable error which can be avoided by moving their read timestamp
+		// forward.
+		stmtSavepoint *savepoint
 	}
 
 	// A pointer member to the creating factory provides access to
@@ -786,6 +795,13 @@ func (tc *TxnCoordSender) handleRetryableErrLocked(
 	default:
 		tc.metrics.RestartsUnknown.Inc()
 	}
+
+	// Transactions which take a new read snapshot in each statement may be
+	// able to retry only the current statement instead of restarting.
+	if retErr := tc.maybePrepareStatementRetryLocked(ctx, pErr); retErr != nil {
+		return retErr
+	}
+
 	errTxnID := pErr.GetTxn().ID
 	newTxn := roachpb.PrepareTransactionForRetry(ctx, pErr, tc.mu.userPriority, tc.clock)
 
@@ -818,6 +834,48 @@ func (tc *TxnCoordSender) handleRetryableErrLocked(
 	for _, reqInt := range tc.interceptorStack {
 		reqInt.epochBumpedLocked()
 	}
+	tc.mu.stmtSavepoint = nil
+	return retErr
+}
+
+// maybePrepareStatementRetryLocked attempts to handle a retryable error by
+// preparing the transaction for a retry of the current statement, instead of
+// for a restart. This is possible for transactions which take a new read
+// snapshot in each statement, when the error can be avoided by moving the
+// transaction's read timestamp forward. The writes performed by the statement
+// are rolled back and the statement is then retried at the new read
+// timestamp. The work performed by previous statements remains valid, since it
+// did not need to be consistent with the statement's snapshot.
+//
+// Returns nil if the transaction needs to be restarted instead.
+func (tc *TxnCoordSender) maybePrepareStatementRetryLocked(
+	ctx context.Context, pErr *roachpb.Error,
+) *roachpb.TransactionRetryWithProtoRefreshError {
+	sp := tc.mu.stmtSavepoint
+	if sp == nil || !tc.mu.txn.IsoLevel.PerStatementReadSnapshot() {
+		return nil
+	}
+	// A statement whose batch may have staged the transaction's commit is not
+	// retried.
+	if pErr.GetTxn().Status != roachpb.PENDING {
+		return nil
+	}
+	ok, refreshTxn := roachpb.CanTransactionRefresh(ctx, pErr)
+	if !ok {
+		return nil
+	}
+	if err := tc.checkSavepointLocked(sp); err != nil {
+		return nil
+	}
+
+	log.VEventf(ctx, 2, "retrying statement at refreshed timestamp %s", refreshTxn.ReadTimestamp)
+	tc.mu.txn.Update(refreshTxn)
+	tc.rollbackToSavepointLocked(ctx, sp)
+	tc.interceptorAlloc.txnSpanRefresher.stepReadTimestampLocked(tc.mu.txn.ReadTimestamp)
+
+	retErr := roachpb.NewTransactionRetryWithProtoRefreshError(
+		pErr.String(), tc.mu.txn.ID, tc.mu.txn)
+	retErr.StatementRetry = true
 	return retErr
 }
 
@@ -986,6 +1044,24 @@ func (tc *TxnCoordSender) SetUserPriority(pri roachpb.UserPriority) error {
 	return nil
 }
 
+// SetIsoLevel is part of the client.TxnSender interface.
+func (tc *TxnCoordSender) SetIsoLevel(isoLevel enginepb.IsolationLevel) error {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	if tc.mu.active && isoLevel != tc.mu.txn.IsoLevel {
+		return errors.New("cannot change the isolation level of a running transaction")
+	}
+	tc.mu.txn.IsoLevel = isoLevel
+	return nil
+}
+
+// IsoLevel is part of the client.TxnSender interface.
+func (tc *TxnCoordSender) IsoLevel() enginepb.IsolationLevel {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	return tc.mu.txn.IsoLevel
+}
+
 // SetDebugName is part of the client.TxnSender interface.
 func (tc *TxnCoordSender) SetDebugName(name string) {
 	tc.mu.Lock()
@@ -1089,6 +1165,7 @@ func (tc *TxnCoordSender) ManualRestart(
 	for _, reqInt := range tc.interceptorStack {
 		reqInt.epochBumpedLocked()
 	}
+	tc.mu.stmtSavepoint = nil
 
 	// The txn might have entered the txnError state after the epoch was bumped.
 	// Reset the state for the retry.
@@ -1100,6 +1177,10 @@ func (tc *TxnCoordSender) IsSerializablePushAndRefreshNotPossible() bool {
 	tc.mu.Lock()
 	defer tc.mu.Unlock()
 
+	if tc.mu.txn.IsoLevel.ToleratesWriteSkew() {
+		// The transaction can commit at its pushed timestamp without refreshing.
+		return false
+	}
 	isTxnPushed := tc.mu.txn.WriteTimestamp != tc.mu.txn.ReadTimestamp
 	refreshAttemptNotPossible := tc.interceptorAlloc.txnSpanRefresher.refreshInvalid ||
 		tc.mu.txn.CommitTimestampFixed
@@ -1274,6 +1355,39 @@ func (tc *TxnCoordSender) Step(ctx context.Context) error {
 	return tc.interceptorAlloc.txnSeqNumAllocator.stepLocked(ctx)
 }
 
+// StepReadTimestamp is part of the TxnSender interface.
+func (tc *TxnCoordSender) StepReadTimestamp(ctx context.Context) error {
+	if tc.typ != kv.RootTxn {
+		return errors.AssertionFailedf("cannot step read timestamp in non-root txn")
+	}
+
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	if !tc.mu.txn.IsoLevel.PerStatementReadSnapshot() {
+		return nil
+	}
+	if err := tc.assertNotFinalized(); err != nil {
+		return err
+	}
+	if tc.mu.txnState == txnError {
+		return tc.mu.storedErr.GoError()
+	}
+
+	// Transactions with a fixed commit timestamp keep reading at it.
+	if !tc.mu.txn.CommitTimestampFixed {
+		now := tc.clock.Now()
+		tc.mu.txn.Refresh(now)
+		// Values written before the statement started must be observed by it,
+		// so the uncertainty interval starts over as well.
+		tc.mu.txn.GlobalUncertaintyLimit.Forward(now.Add(tc.clock.MaxOffset().Nanoseconds(), 0))
+		tc.mu.txn.ResetObservedTimestamps()
+		tc.interceptorAlloc.txnSpanRefresher.stepReadTimestampLocked(tc.mu.txn.ReadTimestamp)
+	}
+	tc.mu.stmtSavepoint = tc.createSavepointLocked(ctx)
+	return nil
+}
+
 // ConfigureStepping is part of the TxnSender interface.
 func (tc *TxnCoordSender) ConfigureStepping(
 	ctx context.Context, mode kv.SteppingMode,
@@ -1292,6 +1406,13 @@ func (tc *TxnCoordSender) GetSteppingMode(ctx context.Context) (curMode kv.Stepp
 	return curMode
 }
 
+// SetReadSeqNum is part of the TxnSender interface.
+func (tc *TxnCoordSender) SetReadSeqNum(seq enginepb.TxnSeq) error {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	return tc.interceptorAlloc.txnSeqNumAllocator.setReadSeqLocked(seq)
+}
+
 // ManualRefresh is part of the TxnSender interface.
 func (tc *TxnCoordSender) ManualRefresh(ctx context.Context) error {
 	tc.mu.Lock()
@@ -1348,6 +1469,11 @@ func (tc *TxnCoordSender) HasPerformedWrites() bool {
 }
 
 func (tc *TxnCoordSender) hasPerformedReadsLocked() bool {
+	if tc.mu.txn.IsoLevel.ToleratesWriteSkew() {
+		// The txnSpanRefresher does not track the reads of transactions which
+		// tolerate write skew, so conservatively assume that any request read.
+		return tc.mu.active
+	}
 	return !tc.interceptorAlloc.txnSpanRefresher.refreshFootprint.empty()
 }
 
@@ -73,10 +73,16 @@ func (tc *TxnCoordSender) CreateSavepoint(ctx context.Context) (kv.SavepointToke
 		return nil, ErrSavepointOperationInErrorTxn
 	}
 
+	return tc.createSavepointLocked(ctx), nil
+}
+
+// createSavepointLocked captures the current state of the TxnCoordSender in a
+// savepoint.
+func (tc *TxnCoordSender) createSavepointLocked(ctx context.Context) *savepoint {
 	if !tc.mu.active {
 		// Return a preallocated savepoint for the common case of savepoints placed
 		// at the beginning of transactions.
-		return &initialSavepoint, nil
+		return &initialSavepoint
 	}
 
 	s := &savepoint{
@@ -87,8 +93,7 @@ func (tc *TxnCoordSender) CreateSavepoint(ctx context.Context) (kv.SavepointToke
 	for _, reqInt := range tc.interceptorStack {
 		reqInt.createSavepointLocked(ctx, s)
 	}
-
-	return s, nil
+	return s
 }
 
 // RollbackToSavepoint is part of the client.TxnSender interface.
@@ -133,6 +138,14 @@ func (tc *TxnCoordSender) RollbackToSavepoint(ctx context.Context, s kv.Savepoin
 		return err
 	}
 
+	tc.rollbackToSavepointLocked(ctx, sp)
+	return nil
+}
+
+// rollbackToSavepointLocked restores the state of the TxnCoordSender captured
+// in the given savepoint, which must have been validated using
+// checkSavepointLocked.
+func (tc *TxnCoordSender) rollbackToSavepointLocked(ctx context.Context, sp *savepoint) {
 	// Restore the transaction's state, in case we're rewiding after an error.
 	tc.mu.txnState = txnPending
 
@@ -150,8 +163,6 @@ func (tc *TxnCoordSender) RollbackToSavepoint(ctx context.Context, s kv.Savepoin
 				Start: sp.seqNum + 1, End: tc.interceptorAlloc.txnSeqNumAllocator.writeSeq,
 			})
 	}
-
-	return nil
 }
 
 // ReleaseSavepoint is part of the client.TxnSender interface.
@@ -144,6 +144,21 @@ func (s *txnSeqNumAllocator) stepLocked(ctx context.Context) error {
 	return nil
 }
 
+// setReadSeqLocked rewinds or advances the read seqnum to the given
+// value, which must not exceed the current write seqnum. Used by the
+// TxnCoordSender's SetReadSeqNum() method.
+func (s *txnSeqNumAllocator) setReadSeqLocked(seq enginepb.TxnSeq) error {
+	if !s.steppingModeEnabled {
+		return errors.AssertionFailedf("stepping mode is not enabled")
+	}
+	if seq > s.writeSeq {
+		return errors.AssertionFailedf(
+			"cannot set read seq num to %d beyond write seq num %d", seq, s.writeSeq)
+	}
+	s.readSeq = seq
+	return nil
+}
+
 // configureSteppingLocked configures the stepping mode.
 //
 // When enabling stepping from the non-enabled state, the read seqnum
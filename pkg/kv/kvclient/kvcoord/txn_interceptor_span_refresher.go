@@ -125,6 +125,15 @@ type txnSpanRefresher struct {
 	// batch. It is then bumped after every successful refresh.
 	refreshedTimestamp hlc.Timestamp
 
+	// stmtReadsPerformed is set if the current statement has performed reads.
+	// It is only tracked for transactions which tolerate write skew, which
+	// don't track refresh spans because they never need to refresh the reads
+	// of previous statements. The reads of the current statement do need to be
+	// consistent with each other, so they prevent its read timestamp from
+	// being moved forward until the transaction steps its read timestamp for
+	// the next statement.
+	stmtReadsPerformed bool
+
 	// canAutoRetry is set if the txnSpanRefresher is allowed to auto-retry.
 	canAutoRetry bool
 
@@ -183,6 +192,17 @@ func (sr *txnSpanRefresher) SendLocked(
 		return br, nil
 	}
 
+	// Transactions which tolerate write skew only need to know whether the
+	// current statement has performed reads.
+	if br.Txn.IsoLevel.ToleratesWriteSkew() {
+		if !sr.stmtReadsPerformed {
+			ba.RefreshSpanIterate(br, func(roachpb.Span) {
+				sr.stmtReadsPerformed = true
+			})
+		}
+		return br, nil
+	}
+
 	// Iterate over and aggregate refresh spans in the requests, qualified by
 	// possible resume spans in the responses.
 	if !sr.refreshInvalid {
@@ -436,9 +456,11 @@ func (sr *txnSpanRefresher) maybeRefreshPreemptivelyLocked(
 	// If true, tryUpdatingTxnSpans will trivially succeed.
 	refreshFree := ba.CanForwardReadTimestamp
 
-	// If true, this batch is guaranteed to fail without a refresh.
+	// If true, this batch is guaranteed to fail without a refresh. Transactions
+	// which tolerate write skew can commit at a pushed timestamp without one.
 	args, hasET := ba.GetArg(roachpb.EndTxn)
-	refreshInevitable := hasET && args.(*roachpb.EndTxnRequest).Commit
+	refreshInevitable := hasET && args.(*roachpb.EndTxnRequest).Commit &&
+		!ba.Txn.IsoLevel.ToleratesWriteSkew()
 
 	// If neither condition is true, defer the refresh.
 	if !refreshFree && !refreshInevitable && !force {
@@ -495,6 +517,9 @@ func (sr *txnSpanRefresher) tryUpdatingTxnSpans(
 	if sr.refreshInvalid {
 		log.VEvent(ctx, 2, "can't refresh txn spans; not valid")
 		return false
+	} else if sr.stmtReadsPerformed {
+		log.VEvent(ctx, 2, "can't refresh the reads of the current statement")
+		return false
 	} else if sr.refreshFootprint.empty() {
 		log.VEvent(ctx, 2, "there are no txn spans to refresh")
 		sr.refreshedTimestamp.Forward(refreshTxn.ReadTimestamp)
@@ -576,14 +601,16 @@ func (sr *txnSpanRefresher) appendRefreshSpans(
 // higher read-timestamp without returning to transaction coordinator.
 //
 // This requires that the transaction has encountered no spans which require
-// refreshing at the forwarded timestamp and that the transaction's timestamp
-// has not leaked. If either of those conditions are true, a client-side refresh
-// is required.
+// refreshing at the forwarded timestamp (nor performed reads in the current
+// statement, for transactions which tolerate write skew) and that the
+// transaction's timestamp has not leaked. If either of those conditions are
+// true, a client-side refresh is required.
 //
 // Note that when deciding whether a transaction can be bumped to a particular
 // timestamp, the transaction's deadling must also be taken into account.
 func (sr *txnSpanRefresher) canForwardReadTimestampWithoutRefresh(txn *roachpb.Transaction) bool {
-	return sr.canAutoRetry && !sr.refreshInvalid && sr.refreshFootprint.empty() && !txn.CommitTimestampFixed
+	return sr.canAutoRetry && !sr.refreshInvalid && sr.refreshFootprint.empty() &&
+		!sr.stmtReadsPerformed && !txn.CommitTimestampFixed
 }
 
 // forwardRefreshTimestampOnResponse updates the refresher's tracked
@@ -620,13 +647,16 @@ func (sr *txnSpanRefresher) setWrapped(wrapped lockedSender) { sr.wrapped = wrap
 
 // populateLeafInputState is part of the txnInterceptor interface.
 func (sr *txnSpanRefresher) populateLeafInputState(tis *roachpb.LeafTxnInputState) {
-	tis.RefreshInvalid = sr.refreshInvalid
+	tis.RefreshInvalid = sr.refreshInvalid || sr.stmtReadsPerformed
 }
 
 // populateLeafFinalState is part of the txnInterceptor interface.
 func (sr *txnSpanRefresher) populateLeafFinalState(tfs *roachpb.LeafTxnFinalState) {
-	tfs.RefreshInvalid = sr.refreshInvalid
-	if !sr.refreshInvalid {
+	// The reads performed by the current statement of a transaction which
+	// tolerates write skew make the root unable to refresh until the next
+	// statement.
+	tfs.RefreshInvalid = sr.refreshInvalid || sr.stmtReadsPerformed
+	if !tfs.RefreshInvalid {
 		// Copy mutable state so access is safe for the caller.
 		tfs.RefreshSpans = append([]roachpb.Span(nil), sr.refreshFootprint.asSlice()...)
 	}
@@ -649,9 +679,21 @@ func (sr *txnSpanRefresher) importLeafFinalState(
 func (sr *txnSpanRefresher) epochBumpedLocked() {
 	sr.refreshFootprint.clear()
 	sr.refreshInvalid = false
+	sr.stmtReadsPerformed = false
 	sr.refreshedTimestamp.Reset()
 }
 
+// stepReadTimestampLocked is called when a transaction which takes a new read
+// snapshot in each statement moves its read timestamp forward, either at the
+// start of a statement or to retry one. The reads performed before then never
+// need to be refreshed.
+func (sr *txnSpanRefresher) stepReadTimestampLocked(readTimestamp hlc.Timestamp) {
+	sr.refreshFootprint.clear()
+	sr.refreshInvalid = false
+	sr.stmtReadsPerformed = false
+	sr.refreshedTimestamp.Forward(readTimestamp)
+}
+
 // createSavepointLocked is part of the txnInterceptor interface.
 func (sr *txnSpanRefresher) createSavepointLocked(ctx context.Context, s *savepoint) {
 	s.refreshSpans = make([]roachpb.Span, len(sr.refreshFootprint.asSlice()))
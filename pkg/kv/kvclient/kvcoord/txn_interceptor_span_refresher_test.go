@@ -18,6 +18,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
@@ -1191,3 +1192,111 @@ func TestTxnSpanRefresherSavepoint(t *testing.T) {
 	tsr.rollbackToSavepointLocked(ctx, s)
 	require.True(t, tsr.refreshInvalid)
 }
+
+// TestTxnSpanRefresherReadCommitted tests that the txnSpanRefresher does not
+// collect refresh spans for transactions which tolerate write skew, but that
+// it prevents the read timestamp of a statement which has performed reads
+// from being moved forward.
+func TestTxnSpanRefresherReadCommitted(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+	tsr, mockSender := makeMockTxnSpanRefresher()
+
+	txn := makeTxnProto()
+	txn.IsoLevel = enginepb.READ_COMMITTED
+	keyA, keyB := roachpb.Key("a"), roachpb.Key("b")
+
+	// Perform a read. No refresh spans are collected.
+	var ba roachpb.BatchRequest
+	ba.Header = roachpb.Header{Txn: txn.Clone()}
+	ba.Add(&roachpb.ScanRequest{RequestHeader: roachpb.RequestHeader{Key: keyA, EndKey: keyB}})
+
+	mockSender.MockSend(func(ba roachpb.BatchRequest) (*roachpb.BatchResponse, *roachpb.Error) {
+		require.True(t, ba.CanForwardReadTimestamp)
+		br := ba.CreateReply()
+		br.Txn = ba.Txn
+		return br, nil
+	})
+	br, pErr := tsr.SendLocked(ctx, ba)
+	require.Nil(t, pErr)
+	require.NotNil(t, br)
+	require.True(t, tsr.refreshFootprint.empty())
+	require.False(t, tsr.refreshInvalid)
+	require.True(t, tsr.stmtReadsPerformed)
+
+	// A write that hits a WriteTooOldError can't be retried by a refresh,
+	// since the statement has performed a read.
+	putArgs := roachpb.PutRequest{RequestHeader: roachpb.RequestHeader{Key: keyA}}
+	ba.Requests = nil
+	ba.Add(&putArgs)
+
+	pushedTS := txn.WriteTimestamp.Add(10, 0)
+	onWriteTooOld := func(ba roachpb.BatchRequest) (*roachpb.BatchResponse, *roachpb.Error) {
+		pErr := roachpb.NewError(&roachpb.WriteTooOldError{ActualTimestamp: pushedTS})
+		pErr.SetTxn(ba.Txn)
+		return nil, pErr
+	}
+	mockSender.MockSend(func(ba roachpb.BatchRequest) (*roachpb.BatchResponse, *roachpb.Error) {
+		require.False(t, ba.CanForwardReadTimestamp)
+		return onWriteTooOld(ba)
+	})
+	br, pErr = tsr.SendLocked(ctx, ba)
+	require.Nil(t, br)
+	require.NotNil(t, pErr)
+	require.IsType(t, &roachpb.WriteTooOldError{}, pErr.GetDetail())
+	require.Equal(t, int64(1), tsr.refreshFail.Count())
+
+	// Once the read timestamp is stepped for the next statement, the write can
+	// be retried at a higher timestamp without a refresh.
+	tsr.stepReadTimestampLocked(txn.ReadTimestamp.Add(5, 0))
+	require.False(t, tsr.stmtReadsPerformed)
+	ba.Txn.ReadTimestamp = txn.ReadTimestamp.Add(5, 0)
+	ba.Txn.WriteTimestamp = txn.ReadTimestamp.Add(5, 0)
+
+	onRetry := func(ba roachpb.BatchRequest) (*roachpb.BatchResponse, *roachpb.Error) {
+		require.Equal(t, pushedTS, ba.Txn.ReadTimestamp)
+		br := ba.CreateReply()
+		br.Txn = ba.Txn
+		return br, nil
+	}
+	mockSender.ChainMockSend(onWriteTooOld, onRetry)
+	br, pErr = tsr.SendLocked(ctx, ba)
+	require.Nil(t, pErr)
+	require.NotNil(t, br)
+	require.Equal(t, pushedTS, br.Txn.ReadTimestamp)
+	require.Equal(t, pushedTS, tsr.refreshedTimestamp)
+	require.Equal(t, int64(1), tsr.refreshSuccess.Count())
+
+	// A committing EndTxn at a pushed write timestamp is not preceded by a
+	// refresh.
+	ba.Requests = nil
+	ba.Txn = br.Txn.Clone()
+	ba.Add(&roachpb.ScanRequest{RequestHeader: roachpb.RequestHeader{Key: keyA, EndKey: keyB}})
+	mockSender.MockSend(func(ba roachpb.BatchRequest) (*roachpb.BatchResponse, *roachpb.Error) {
+		br := ba.CreateReply()
+		br.Txn = ba.Txn
+		return br, nil
+	})
+	br, pErr = tsr.SendLocked(ctx, ba)
+	require.Nil(t, pErr)
+	require.NotNil(t, br)
+	require.True(t, tsr.stmtReadsPerformed)
+
+	ba.Requests = nil
+	ba.Txn.WriteTimestamp = pushedTS.Add(10, 0)
+	ba.Add(&roachpb.EndTxnRequest{Commit: true})
+	mockSender.MockSend(func(ba roachpb.BatchRequest) (*roachpb.BatchResponse, *roachpb.Error) {
+		require.Equal(t, pushedTS, ba.Txn.ReadTimestamp)
+		require.Equal(t, pushedTS.Add(10, 0), ba.Txn.WriteTimestamp)
+		br := ba.CreateReply()
+		br.Txn = ba.Txn
+		br.Txn.Status = roachpb.COMMITTED
+		return br, nil
+	})
+	br, pErr = tsr.SendLocked(ctx, ba)
+	require.Nil(t, pErr)
+	require.NotNil(t, br)
+	require.Equal(t, int64(1), tsr.refreshSuccess.Count())
+	require.Equal(t, int64(1), tsr.refreshFail.Count())
+}
@@ -13,6 +13,7 @@ package rangefeed
 import (
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/retry"
 )
 
@@ -27,6 +28,7 @@ type config struct {
 	withInitialScan    bool
 	withDiff           bool
 	onInitialScanError OnInitialScanError
+	onFrontierAdvance  OnFrontierAdvance
 }
 
 type optionFunc func(*config)
@@ -73,6 +75,20 @@ func WithDiff() Option {
 	})
 }
 
+// OnFrontierAdvance is called when the rangefeed frontier, i.e. the timestamp
+// up to which all the values of the span have been passed to the value
+// function, advances.
+type OnFrontierAdvance func(ctx context.Context, frontier hlc.Timestamp)
+
+// WithOnFrontierAdvance sets up a callback which is called whenever the
+// frontier of the rangefeed advances. It is called from the goroutine which
+// calls the value function, after the values up to the new frontier.
+func WithOnFrontierAdvance(f OnFrontierAdvance) Option {
+	return optionFunc(func(c *config) {
+		c.onFrontierAdvance = f
+	})
+}
+
 // WithRetry configures the retry options for the rangefeed.
 func WithRetry(options retry.Options) Option {
 	return optionFunc(func(c *config) {
@@ -279,6 +279,49 @@ func TestRangeFeedMock(t *testing.T) {
 		<-rows
 		r.Close()
 	})
+	t.Run("frontier advance", func(t *testing.T) {
+		stopper := stop.NewStopper()
+		ctx := context.Background()
+		defer stopper.Stop(ctx)
+		sp := roachpb.Span{
+			Key:    roachpb.Key("a"),
+			EndKey: roachpb.Key("c"),
+		}
+		ts := func(wallTime int64) hlc.Timestamp { return hlc.Timestamp{WallTime: wallTime} }
+		mc := mockClient{
+			rangefeed: func(
+				ctx context.Context, span roachpb.Span, startFrom hlc.Timestamp, withDiff bool, eventC chan<- *roachpb.RangeFeedEvent,
+			) error {
+				checkpoint := func(key, endKey string, wallTime int64) {
+					eventC <- &roachpb.RangeFeedEvent{
+						Checkpoint: &roachpb.RangeFeedCheckpoint{
+							Span:       roachpb.Span{Key: roachpb.Key(key), EndKey: roachpb.Key(endKey)},
+							ResolvedTS: ts(wallTime),
+						},
+					}
+				}
+				// The frontier only advances once the whole span is checkpointed.
+				checkpoint("a", "b", 2)
+				checkpoint("b", "c", 3)
+				checkpoint("a", "b", 4)
+				<-ctx.Done()
+				return ctx.Err()
+			},
+		}
+		f := rangefeed.NewFactoryWithDB(stopper, &mc, nil /* knobs */)
+		frontiers := make(chan hlc.Timestamp)
+		r, err := f.RangeFeed(ctx, "foo", sp, ts(1), func(
+			ctx context.Context, value *roachpb.RangeFeedValue,
+		) {
+			t.Error("this should not be called")
+		}, rangefeed.WithOnFrontierAdvance(func(ctx context.Context, frontier hlc.Timestamp) {
+			frontiers <- frontier
+		}))
+		require.NoError(t, err)
+		require.Equal(t, ts(2), <-frontiers)
+		require.Equal(t, ts(3), <-frontiers)
+		r.Close()
+	})
 	t.Run("stopper already stopped", func(t *testing.T) {
 		stopper := stop.NewStopper()
 		ctx := context.Background()
@@ -330,21 +330,30 @@ func makeAllocatorRand(source rand.Source) allocatorRand {
 // RangeUsageInfo contains usage information (sizes and traffic) needed by the
 // allocator to make rebalancing decisions for a given range.
 type RangeUsageInfo struct {
-	LogicalBytes     int64
-	QueriesPerSecond float64
-	WritesPerSecond  float64
+	LogicalBytes         int64
+	QueriesPerSecond     float64
+	WritesPerSecond      float64
+	RequestTimePerSecond float64
+	WriteBytesPerSecond  float64
 }
 
-func rangeUsageInfoForRepl(repl *Replica) RangeUsageInfo {
+// rangeUsageInfo returns the usage information of the replica's range.
+func (r *Replica) rangeUsageInfo() RangeUsageInfo {
 	info := RangeUsageInfo{
-		LogicalBytes: repl.GetMVCCStats().Total(),
+		LogicalBytes: r.GetMVCCStats().Total(),
 	}
-	if queriesPerSecond, dur := repl.leaseholderStats.avgQPS(); dur >= MinStatsDuration {
+	if queriesPerSecond, dur := r.leaseholderStats.avgQPS(); dur >= MinStatsDuration {
 		info.QueriesPerSecond = queriesPerSecond
 	}
-	if writesPerSecond, dur := repl.writeStats.avgQPS(); dur >= MinStatsDuration {
+	if writesPerSecond, dur := r.writeStats.avgQPS(); dur >= MinStatsDuration {
 		info.WritesPerSecond = writesPerSecond
 	}
+	if requestTimePerSecond, dur := r.requestTimeStats.avgQPS(); dur >= MinStatsDuration {
+		info.RequestTimePerSecond = requestTimePerSecond
+	}
+	if writeBytesPerSecond, dur := r.writeBytesStats.avgQPS(); dur >= MinStatsDuration {
+		info.WriteBytesPerSecond = writeBytesPerSecond
+	}
 	return info
 }
 
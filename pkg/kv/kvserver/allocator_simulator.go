@@ -0,0 +1,934 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/constraint"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/liveness/livenesspb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"go.etcd.io/etcd/raft/v3"
+	"go.etcd.io/etcd/raft/v3/tracker"
+)
+
+// defaultSimulatorTickInterval is the amount of simulated time that passes
+// between two replicate queue passes over the ranges of an
+// AllocatorSimulator.
+const defaultSimulatorTickInterval = 10 * time.Second
+
+// SimulatedStore describes a store of the cluster simulated by an
+// AllocatorSimulator.
+type SimulatedStore struct {
+	StoreID  roachpb.StoreID
+	NodeID   roachpb.NodeID
+	Attrs    roachpb.Attributes
+	Locality roachpb.Locality
+	// Capacity is the size of the store's disk, in bytes.
+	Capacity int64
+}
+
+// SimulatedRange describes a range of the cluster simulated by an
+// AllocatorSimulator.
+type SimulatedRange struct {
+	RangeID roachpb.RangeID
+	// Voters and NonVoters are the stores that initially hold the range's
+	// voting and non-voting replicas. The first voter holds the lease.
+	Voters    []roachpb.StoreID
+	NonVoters []roachpb.StoreID
+	// Config is the span config that applies to the range.
+	Config roachpb.SpanConfig
+	// Usage is the load on the range. The QPS and request time are incurred by
+	// the leaseholder's store, while the writes are applied by the stores of
+	// all replicas.
+	Usage RangeUsageInfo
+}
+
+// AllocatorSimulatorConfig configures an AllocatorSimulator.
+type AllocatorSimulatorConfig struct {
+	// Settings are the cluster settings the allocator runs with. Testing
+	// cluster settings are used if unset.
+	Settings *cluster.Settings
+	Stores   []SimulatedStore
+	Ranges   []SimulatedRange
+	// TickInterval is the amount of simulated time between two replicate
+	// queue passes over the ranges. Defaults to 10s.
+	TickInterval time.Duration
+}
+
+// SimulatedStoreStats describes the replicas and load of a simulated store.
+type SimulatedStoreStats struct {
+	StoreID              roachpb.StoreID
+	RangeCount           int
+	LeaseCount           int
+	LogicalBytes         int64
+	QueriesPerSecond     float64
+	WritesPerSecond      float64
+	RequestTimePerSecond float64
+	WriteBytesPerSecond  float64
+}
+
+// SimulatorTickStats describes the changes made during a tick of an
+// AllocatorSimulator and the state of the stores at the end of the tick.
+type SimulatorTickStats struct {
+	Tick int
+	// ReplicaAdditions and ReplicaRemovals count the replicas added to and
+	// removed from ranges, including the ones that were part of a rebalance.
+	ReplicaAdditions int
+	ReplicaRemovals  int
+	// ReplicaTypeChanges counts the promotions of non-voters to voters and
+	// the demotions of voters to non-voters.
+	ReplicaTypeChanges int
+	// Rebalances counts the replicas that were moved between stores.
+	Rebalances int
+	// LeaseTransfers counts the leases transferred by the replicate queues
+	// and the StoreRebalancers.
+	LeaseTransfers int
+	// LoadBasedLeaseTransfers and LoadBasedRangeRebalances count the lease
+	// transfers and range relocations made by the StoreRebalancers. They are
+	// included in the counts above.
+	LoadBasedLeaseTransfers  int
+	LoadBasedRangeRebalances int
+	// Errors counts the ranges for which the replicate queue returned an
+	// error, for instance because no store satisfies the range's constraints.
+	Errors int
+	// Stores is the state of each store at the end of the tick, ordered by
+	// store ID.
+	Stores []SimulatedStoreStats
+}
+
+// Changes returns the number of replication changes and lease transfers made
+// during the tick.
+func (s SimulatorTickStats) Changes() int {
+	return s.ReplicaAdditions + s.ReplicaRemovals + s.ReplicaTypeChanges + s.LeaseTransfers
+}
+
+// SimulatedRangeAction is an allocator action that a simulated range still
+// needs at the end of an AllocatorSimulator run.
+type SimulatedRangeAction struct {
+	RangeID roachpb.RangeID
+	Action  AllocatorAction
+}
+
+// AllocatorSimulationReport summarizes a run of an AllocatorSimulator.
+type AllocatorSimulationReport struct {
+	// Ticks describes each tick of the run.
+	Ticks []SimulatorTickStats
+	// Converged is set if the run ended because the replicate queues and the
+	// StoreRebalancers stopped making changes, as opposed to reaching the
+	// maximum number of ticks.
+	Converged bool
+	// PendingActions lists the ranges that still need an action other than
+	// rebalancing, e.g. because they are under-replicated or because one of
+	// their replicas violates their constraints.
+	PendingActions []SimulatedRangeAction
+	// LeasePreferenceViolations lists the ranges whose leaseholder doesn't
+	// satisfy any of their lease preferences.
+	LeasePreferenceViolations []roachpb.RangeID
+}
+
+// Totals sums up the changes made during the run.
+func (r AllocatorSimulationReport) Totals() SimulatorTickStats {
+	var totals SimulatorTickStats
+	for _, t := range r.Ticks {
+		totals.Tick = t.Tick
+		totals.ReplicaAdditions += t.ReplicaAdditions
+		totals.ReplicaRemovals += t.ReplicaRemovals
+		totals.ReplicaTypeChanges += t.ReplicaTypeChanges
+		totals.Rebalances += t.Rebalances
+		totals.LeaseTransfers += t.LeaseTransfers
+		totals.LoadBasedLeaseTransfers += t.LoadBasedLeaseTransfers
+		totals.LoadBasedRangeRebalances += t.LoadBasedRangeRebalances
+		totals.Errors += t.Errors
+		totals.Stores = t.Stores
+	}
+	return totals
+}
+
+func (r AllocatorSimulationReport) String() string {
+	var buf bytes.Buffer
+	totals := r.Totals()
+	if r.Converged {
+		fmt.Fprintf(&buf, "converged after %d ticks", len(r.Ticks))
+	} else {
+		fmt.Fprintf(&buf, "did not converge after %d ticks", len(r.Ticks))
+	}
+	fmt.Fprintf(&buf, ": %d replica additions, %d replica removals (%d rebalances, %d type changes), %d lease transfers\n",
+		totals.ReplicaAdditions, totals.ReplicaRemovals, totals.Rebalances,
+		totals.ReplicaTypeChanges, totals.LeaseTransfers)
+	fmt.Fprintf(&buf, "load-based rebalancing: %d lease transfers, %d range rebalances\n",
+		totals.LoadBasedLeaseTransfers, totals.LoadBasedRangeRebalances)
+	for _, s := range totals.Stores {
+		fmt.Fprintf(&buf, "s%d: ranges=%d leases=%d logical-bytes=%s qps=%.2f writes-per-second=%.2f "+
+			"request-time-per-second=%s write-bytes-per-second=%s\n",
+			s.StoreID, s.RangeCount, s.LeaseCount, humanizeutil.IBytes(s.LogicalBytes),
+			s.QueriesPerSecond, s.WritesPerSecond, time.Duration(s.RequestTimePerSecond),
+			humanizeutil.IBytes(int64(s.WriteBytesPerSecond)))
+	}
+	for _, a := range r.PendingActions {
+		fmt.Fprintf(&buf, "r%d: pending action %s\n", a.RangeID, a.Action)
+	}
+	for _, rangeID := range r.LeasePreferenceViolations {
+		fmt.Fprintf(&buf, "r%d: lease violates lease preferences\n", rangeID)
+	}
+	return buf.String()
+}
+
+// simulatedStore is a store of an AllocatorSimulator, along with the
+// replicate queue and StoreRebalancer that run on it.
+type simulatedStore struct {
+	SimulatedStore
+	rq           *replicateQueue
+	sr           *StoreRebalancer
+	replRankings *replicaRankings
+}
+
+// simulatedRange is the state of a range in an AllocatorSimulator. It is the
+// leaseholderReplica through which the replicate queue and StoreRebalancer of
+// the leaseholder's store process the range. Replication changes, lease
+// transfers and relocations take effect immediately, so the range is never in
+// a joint configuration, its replicas are always caught up and its
+// leaseholder is always the raft leader.
+type simulatedRange struct {
+	sim         *AllocatorSimulator
+	desc        roachpb.RangeDescriptor
+	conf        roachpb.SpanConfig
+	usage       RangeUsageInfo
+	leaseholder roachpb.StoreID
+}
+
+var _ leaseholderReplica = &simulatedRange{}
+
+func (r *simulatedRange) String() string {
+	return fmt.Sprintf("[s%d,r%d]", r.leaseholder, r.desc.RangeID)
+}
+
+// AnnotateCtx implements the replicaInQueue interface.
+func (r *simulatedRange) AnnotateCtx(ctx context.Context) context.Context {
+	return ctx
+}
+
+// ReplicaID returns the ID of the leaseholder's replica.
+func (r *simulatedRange) ReplicaID() roachpb.ReplicaID {
+	repl, _ := r.desc.GetReplicaDescriptor(r.leaseholder)
+	return repl.ReplicaID
+}
+
+// StoreID returns the store of the range's leaseholder.
+func (r *simulatedRange) StoreID() roachpb.StoreID {
+	return r.leaseholder
+}
+
+// GetRangeID returns the range's ID.
+func (r *simulatedRange) GetRangeID() roachpb.RangeID {
+	return r.desc.RangeID
+}
+
+// IsInitialized implements the replicaInQueue interface.
+func (r *simulatedRange) IsInitialized() bool {
+	return true
+}
+
+// IsDestroyed implements the replicaInQueue interface.
+func (r *simulatedRange) IsDestroyed() (DestroyReason, error) {
+	return destroyReasonAlive, nil
+}
+
+// Desc returns a copy of the range's descriptor, which remains unchanged by
+// the replication changes made to the range.
+func (r *simulatedRange) Desc() *roachpb.RangeDescriptor {
+	desc := r.desc
+	desc.InternalReplicas = append([]roachpb.ReplicaDescriptor(nil), r.desc.InternalReplicas...)
+	return &desc
+}
+
+// DescAndSpanConfig returns a copy of the range's descriptor, and its span
+// config.
+func (r *simulatedRange) DescAndSpanConfig() (*roachpb.RangeDescriptor, roachpb.SpanConfig) {
+	return r.Desc(), r.conf
+}
+
+// maybeInitializeRaftGroup implements the replicaInQueue interface.
+func (r *simulatedRange) maybeInitializeRaftGroup(context.Context) {}
+
+// redirectOnOrAcquireLease returns the status of the leaseholder's lease,
+// which is always valid.
+func (r *simulatedRange) redirectOnOrAcquireLease(
+	ctx context.Context,
+) (kvserverpb.LeaseStatus, *roachpb.Error) {
+	return r.LeaseStatusAt(ctx, r.sim.clock.NowAsClockTimestamp()), nil
+}
+
+// LeaseStatusAt returns the status of the leaseholder's lease, which is
+// always valid.
+func (r *simulatedRange) LeaseStatusAt(
+	_ context.Context, now hlc.ClockTimestamp,
+) kvserverpb.LeaseStatus {
+	repl, _ := r.desc.GetReplicaDescriptor(r.leaseholder)
+	return kvserverpb.LeaseStatus{
+		Lease: roachpb.Lease{Replica: repl},
+		Now:   now,
+		State: kvserverpb.LeaseState_VALID,
+	}
+}
+
+// OwnsValidLease returns true: the range is only ever processed by the
+// leaseholder's store.
+func (r *simulatedRange) OwnsValidLease(context.Context, hlc.ClockTimestamp) bool {
+	return true
+}
+
+// RaftStatus returns the raft status of the range's leaseholder.
+func (r *simulatedRange) RaftStatus() *raft.Status {
+	status := &raft.Status{
+		Progress: make(map[uint64]tracker.Progress),
+	}
+	status.RaftState = raft.StateLeader
+	status.Commit = 1
+	for _, repl := range r.desc.Replicas().Descriptors() {
+		if repl.StoreID == r.leaseholder {
+			status.Lead = uint64(repl.ReplicaID)
+		}
+		status.Progress[uint64(repl.ReplicaID)] = tracker.Progress{
+			Match: 1,
+			State: tracker.StateReplicate,
+		}
+	}
+	return status
+}
+
+// LastReplicaAdded returns no replica, since new replicas are caught up as
+// soon as they're added.
+func (r *simulatedRange) LastReplicaAdded() (roachpb.ReplicaID, time.Time) {
+	return 0, time.Time{}
+}
+
+// QueriesPerSecond returns the range's simulated QPS.
+func (r *simulatedRange) QueriesPerSecond() (float64, time.Duration) {
+	return r.usage.QueriesPerSecond, MinStatsDuration
+}
+
+// getLeaseholderStats returns nil: the locality of requests isn't simulated,
+// so the allocator makes lease decisions without request stats.
+func (r *simulatedRange) getLeaseholderStats() *replicaStats {
+	return nil
+}
+
+// rangeUsageInfo returns the range's simulated load.
+func (r *simulatedRange) rangeUsageInfo() RangeUsageInfo {
+	return r.usage
+}
+
+// checkLeaseRespectsPreferences checks whether the leaseholder's store
+// satisfies one of the range's lease preferences.
+func (r *simulatedRange) checkLeaseRespectsPreferences(context.Context) (bool, error) {
+	return r.sim.leaseRespectsPreferences(r), nil
+}
+
+// AdminTransferLease moves the lease to the target store.
+func (r *simulatedRange) AdminTransferLease(_ context.Context, target roachpb.StoreID) error {
+	if repl, ok := r.desc.GetReplicaDescriptor(target); !ok || !repl.IsVoterNewConfig() {
+		return errors.Errorf("%s: s%d has no voter to transfer the lease to", r, target)
+	}
+	r.transferLease(target)
+	return nil
+}
+
+func (r *simulatedRange) transferLease(target roachpb.StoreID) {
+	if target == r.leaseholder {
+		return
+	}
+	r.leaseholder = target
+	r.sim.cur.LeaseTransfers++
+}
+
+// changeReplicasImpl applies the replication changes to the range.
+func (r *simulatedRange) changeReplicasImpl(
+	_ context.Context,
+	_ *roachpb.RangeDescriptor,
+	_ SnapshotRequest_Priority,
+	reason kvserverpb.RangeLogEventReason,
+	_ string,
+	chgs roachpb.ReplicationChanges,
+) (*roachpb.RangeDescriptor, error) {
+	for _, chg := range chgs {
+		if chg.ChangeType.IsRemoval() && chg.Target.StoreID == r.leaseholder {
+			return nil, errors.Errorf("%s: cannot remove the leaseholder's replica on s%d",
+				r, chg.Target.StoreID)
+		}
+	}
+	if err := r.applyChanges(chgs); err != nil {
+		return nil, err
+	}
+	if reason == kvserverpb.ReasonRebalance && len(chgs) > 1 {
+		r.sim.cur.Rebalances++
+	}
+	return r.Desc(), nil
+}
+
+// relocateRange moves the range's replicas to the given stores, and its lease
+// to the first voter target, like Store.AdminRelocateRange.
+func (r *simulatedRange) relocateRange(
+	_ context.Context,
+	desc roachpb.RangeDescriptor,
+	voterTargets, nonVoterTargets []roachpb.ReplicationTarget,
+) error {
+	if len(voterTargets) == 0 {
+		return errors.AssertionFailedf("%s: cannot relocate range without voters", r)
+	}
+	if desc.Generation != r.desc.Generation {
+		return errors.Errorf("%s: descriptor changed during relocation", r)
+	}
+	var additions, removals roachpb.ReplicationChanges
+	add := func(targets []roachpb.ReplicationTarget, typ roachpb.ReplicaType, addType roachpb.ReplicaChangeType) {
+		for _, target := range targets {
+			if existing, ok := r.desc.GetReplicaDescriptor(target.StoreID); ok && existing.GetType() == typ {
+				continue
+			}
+			additions = append(additions, roachpb.ReplicationChange{ChangeType: addType, Target: target})
+		}
+	}
+	add(voterTargets, roachpb.VOTER_FULL, roachpb.ADD_VOTER)
+	add(nonVoterTargets, roachpb.NON_VOTER, roachpb.ADD_NON_VOTER)
+	for _, repl := range r.desc.Replicas().Descriptors() {
+		if storeHasReplica(repl.StoreID, voterTargets) || storeHasReplica(repl.StoreID, nonVoterTargets) {
+			continue
+		}
+		changeType := roachpb.REMOVE_VOTER
+		if repl.GetType() == roachpb.NON_VOTER {
+			changeType = roachpb.REMOVE_NON_VOTER
+		}
+		removals = append(removals, roachpb.ReplicationChange{
+			ChangeType: changeType,
+			Target:     roachpb.ReplicationTarget{NodeID: repl.NodeID, StoreID: repl.StoreID},
+		})
+	}
+	if err := r.applyChanges(additions); err != nil {
+		return err
+	}
+	r.transferLease(voterTargets[0].StoreID)
+	if err := r.applyChanges(removals); err != nil {
+		return err
+	}
+	r.sim.cur.Rebalances += len(removals)
+	return nil
+}
+
+// applyChanges adds, removes, promotes and demotes the range's replicas.
+// Promotions and demotions are expressed as an addition of the new type of
+// replica, possibly along with a removal of the old type which is then a
+// no-op.
+func (r *simulatedRange) applyChanges(chgs roachpb.ReplicationChanges) error {
+	for _, chg := range chgs {
+		existing, found := r.desc.GetReplicaDescriptor(chg.Target.StoreID)
+		switch chg.ChangeType {
+		case roachpb.ADD_VOTER, roachpb.ADD_NON_VOTER:
+			typ := roachpb.VOTER_FULL
+			if chg.ChangeType == roachpb.ADD_NON_VOTER {
+				typ = roachpb.NON_VOTER
+			}
+			if found {
+				r.desc.SetReplicaType(chg.Target.NodeID, chg.Target.StoreID, typ)
+				r.sim.cur.ReplicaTypeChanges++
+				continue
+			}
+			r.desc.AddReplica(chg.Target.NodeID, chg.Target.StoreID, typ)
+			r.sim.cur.ReplicaAdditions++
+		case roachpb.REMOVE_VOTER, roachpb.REMOVE_NON_VOTER:
+			if !found {
+				return errors.AssertionFailedf("%s: no replica on s%d to remove", r, chg.Target.StoreID)
+			}
+			if isVoter := existing.GetType() != roachpb.NON_VOTER; isVoter != (chg.ChangeType == roachpb.REMOVE_VOTER) {
+				// See above.
+				continue
+			}
+			r.desc.RemoveReplica(chg.Target.NodeID, chg.Target.StoreID)
+			r.sim.cur.ReplicaRemovals++
+		default:
+			return errors.AssertionFailedf("unexpected replica change %s", chg.ChangeType)
+		}
+	}
+	return nil
+}
+
+// maybeLeaveAtomicChangeReplicasAndRemoveLearners returns the range's
+// descriptor, since the range is never in a joint configuration.
+func (r *simulatedRange) maybeLeaveAtomicChangeReplicasAndRemoveLearners(
+	context.Context,
+) (*roachpb.RangeDescriptor, error) {
+	return r.Desc(), nil
+}
+
+// AllocatorSimulator is a deterministic, in-process simulation of the
+// placement of replicas and leases in a cluster. It feeds synthetic store
+// descriptors, range load and span configs into a real Allocator, and runs
+// the replicate queue and StoreRebalancer of each store over the ranges it
+// holds the lease for, one tick of simulated time at a time.
+//
+// The simulator is meant to evaluate how the allocator reacts to changes in
+// zone configs, cluster topology or load before applying them to a real
+// cluster. It doesn't simulate the follow-the-workload lease transfers that
+// depend on the locality of requests.
+type AllocatorSimulator struct {
+	st           *cluster.Settings
+	manual       *hlc.ManualClock
+	clock        *hlc.Clock
+	tickInterval time.Duration
+	storePool    *StorePool
+	allocator    Allocator
+
+	stores   map[roachpb.StoreID]*simulatedStore
+	storeIDs roachpb.StoreIDSlice
+	liveness map[roachpb.NodeID]livenesspb.NodeLivenessStatus
+	// ranges is sorted by range ID, which is the order in which the
+	// replicate queues process them on each tick.
+	ranges []*simulatedRange
+	// lastStoreRebalance is the simulated time at which the StoreRebalancers
+	// last ran.
+	lastStoreRebalance time.Time
+
+	tick int
+	// cur accumulates the stats of the current tick.
+	cur SimulatorTickStats
+}
+
+// NewAllocatorSimulator creates an AllocatorSimulator for the given cluster.
+// All nodes start out live.
+func NewAllocatorSimulator(cfg AllocatorSimulatorConfig) (*AllocatorSimulator, error) {
+	st := cfg.Settings
+	if st == nil {
+		st = cluster.MakeTestingClusterSettings()
+	}
+	tickInterval := cfg.TickInterval
+	if tickInterval == 0 {
+		tickInterval = defaultSimulatorTickInterval
+	}
+	s := &AllocatorSimulator{
+		st:           st,
+		manual:       hlc.NewManualClock(0),
+		tickInterval: tickInterval,
+		stores:       make(map[roachpb.StoreID]*simulatedStore),
+		liveness:     make(map[roachpb.NodeID]livenesspb.NodeLivenessStatus),
+	}
+	s.clock = hlc.NewClock(s.manual.UnixNano, time.Nanosecond)
+	s.storePool = newStorePool(
+		log.AmbientContext{Tracer: st.Tracer},
+		st,
+		s.clock,
+		s.nodeCount,
+		s.nodeLiveness,
+		true, /* deterministic */
+	)
+	s.allocator = MakeAllocator(s.storePool, func(string) (time.Duration, bool) {
+		return 0, false
+	})
+
+	for _, store := range cfg.Stores {
+		if err := s.AddStore(store); err != nil {
+			return nil, err
+		}
+	}
+	for _, rng := range cfg.Ranges {
+		if err := s.addRange(rng); err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(s.ranges, func(i, j int) bool {
+		return s.ranges[i].desc.RangeID < s.ranges[j].desc.RangeID
+	})
+	return s, nil
+}
+
+// AddStore adds a store to the simulated cluster. Its node is considered
+// live unless SetNodeLiveness was called for it.
+func (s *AllocatorSimulator) AddStore(store SimulatedStore) error {
+	if _, ok := s.stores[store.StoreID]; ok {
+		return errors.Errorf("duplicate store s%d", store.StoreID)
+	}
+	// Set up a fake store with just what the replicate queue and the
+	// StoreRebalancer call into.
+	ambient := log.AmbientContext{Tracer: s.st.Tracer}
+	ambient.AddLogTag("s", store.StoreID)
+	fakeStore := &Store{
+		cfg: StoreConfig{
+			Settings:   s.st,
+			Clock:      s.clock,
+			AmbientCtx: ambient,
+		},
+		Ident: &roachpb.StoreIdent{
+			NodeID:  store.NodeID,
+			StoreID: store.StoreID,
+		},
+		metrics: newStoreMetrics(metric.TestSampleInterval),
+	}
+	rq := newReplicateQueue(fakeStore, s.allocator)
+	rq.now = s.now
+	replRankings := newReplicaRankings()
+	s.stores[store.StoreID] = &simulatedStore{
+		SimulatedStore: store,
+		rq:             rq,
+		sr:             NewStoreRebalancer(ambient, s.st, rq, replRankings),
+		replRankings:   replRankings,
+	}
+	s.storeIDs = append(s.storeIDs, store.StoreID)
+	sort.Sort(s.storeIDs)
+	if _, ok := s.liveness[store.NodeID]; !ok {
+		s.liveness[store.NodeID] = livenesspb.NodeLivenessStatus_LIVE
+	}
+	return nil
+}
+
+func (s *AllocatorSimulator) addRange(rng SimulatedRange) error {
+	if len(rng.Voters) == 0 {
+		return errors.Errorf("r%d has no voters", rng.RangeID)
+	}
+	r := &simulatedRange{
+		sim: s,
+		desc: roachpb.RangeDescriptor{
+			RangeID:       rng.RangeID,
+			NextReplicaID: 1,
+		},
+		conf:        rng.Config,
+		usage:       rng.Usage,
+		leaseholder: rng.Voters[0],
+	}
+	add := func(storeIDs []roachpb.StoreID, typ roachpb.ReplicaType) error {
+		for _, storeID := range storeIDs {
+			store, ok := s.stores[storeID]
+			if !ok {
+				return errors.Errorf("r%d has a replica on unknown store s%d", rng.RangeID, storeID)
+			}
+			if _, ok := r.desc.GetReplicaDescriptor(storeID); ok {
+				return errors.Errorf("r%d has several replicas on s%d", rng.RangeID, storeID)
+			}
+			r.desc.AddReplica(store.NodeID, storeID, typ)
+		}
+		return nil
+	}
+	if err := add(rng.Voters, roachpb.VOTER_FULL); err != nil {
+		return err
+	}
+	if err := add(rng.NonVoters, roachpb.NON_VOTER); err != nil {
+		return err
+	}
+	for _, other := range s.ranges {
+		if other.desc.RangeID == rng.RangeID {
+			return errors.Errorf("duplicate range r%d", rng.RangeID)
+		}
+	}
+	s.ranges = append(s.ranges, r)
+	return nil
+}
+
+// SetNodeLiveness sets the liveness status of a node, e.g. to simulate its
+// death or decommissioning. Stores on dead or decommissioned nodes stop
+// gossiping their descriptors.
+func (s *AllocatorSimulator) SetNodeLiveness(
+	nodeID roachpb.NodeID, status livenesspb.NodeLivenessStatus,
+) {
+	s.liveness[nodeID] = status
+}
+
+// SetSpanConfig sets the span config of the given ranges, or of all ranges if
+// none are given.
+func (s *AllocatorSimulator) SetSpanConfig(
+	conf roachpb.SpanConfig, rangeIDs ...roachpb.RangeID,
+) error {
+	return s.forEachRange(rangeIDs, func(r *simulatedRange) {
+		r.conf = conf
+	})
+}
+
+// SetRangeUsage sets the load on the given ranges, or on all ranges if none
+// are given.
+func (s *AllocatorSimulator) SetRangeUsage(
+	usage RangeUsageInfo, rangeIDs ...roachpb.RangeID,
+) error {
+	return s.forEachRange(rangeIDs, func(r *simulatedRange) {
+		r.usage = usage
+	})
+}
+
+func (s *AllocatorSimulator) forEachRange(
+	rangeIDs []roachpb.RangeID, fn func(r *simulatedRange),
+) error {
+	if len(rangeIDs) == 0 {
+		for _, r := range s.ranges {
+			fn(r)
+		}
+		return nil
+	}
+	for _, rangeID := range rangeIDs {
+		i := sort.Search(len(s.ranges), func(i int) bool {
+			return s.ranges[i].desc.RangeID >= rangeID
+		})
+		if i == len(s.ranges) || s.ranges[i].desc.RangeID != rangeID {
+			return errors.Errorf("unknown range r%d", rangeID)
+		}
+		fn(s.ranges[i])
+	}
+	return nil
+}
+
+// now returns the current simulated time.
+func (s *AllocatorSimulator) now() time.Time {
+	return timeutil.Unix(0, s.manual.UnixNano())
+}
+
+// nodeCount is the NodeCountFunc of the simulator's StorePool. Like
+// NodeLiveness.GetNodeCount, it doesn't count decommissioned nodes.
+func (s *AllocatorSimulator) nodeCount() int {
+	var count int
+	for _, status := range s.liveness {
+		if status != livenesspb.NodeLivenessStatus_DECOMMISSIONED {
+			count++
+		}
+	}
+	return count
+}
+
+// nodeLiveness is the NodeLivenessFunc of the simulator's StorePool.
+func (s *AllocatorSimulator) nodeLiveness(
+	nodeID roachpb.NodeID, _ time.Time, _ time.Duration,
+) livenesspb.NodeLivenessStatus {
+	if status, ok := s.liveness[nodeID]; ok {
+		return status
+	}
+	return livenesspb.NodeLivenessStatus_UNKNOWN
+}
+
+// nodeGossips returns whether the stores of the node periodically gossip
+// their descriptors.
+func (s *AllocatorSimulator) nodeGossips(nodeID roachpb.NodeID) bool {
+	switch s.liveness[nodeID] {
+	case livenesspb.NodeLivenessStatus_DEAD, livenesspb.NodeLivenessStatus_DECOMMISSIONED:
+		return false
+	default:
+		return true
+	}
+}
+
+// Run ticks the simulator until the replicate queues and StoreRebalancers
+// stop making changes, or until maxTicks ticks have elapsed. They are
+// considered done once they haven't made any change for as long as a store
+// remains suspect after failing its liveness heartbeat, since suspect stores
+// are transiently ignored by the allocator, and for at least one
+// StoreRebalancer pass.
+func (s *AllocatorSimulator) Run(ctx context.Context, maxTicks int) AllocatorSimulationReport {
+	var report AllocatorSimulationReport
+	quiescentPeriod := TimeAfterStoreSuspect.Get(&s.st.SV)
+	if quiescentPeriod < storeRebalancerTimerDuration {
+		quiescentPeriod = storeRebalancerTimerDuration
+	}
+	quiescentTicks := int(quiescentPeriod/s.tickInterval) + 1
+	var idle int
+	for i := 0; i < maxTicks; i++ {
+		stats := s.Tick(ctx)
+		report.Ticks = append(report.Ticks, stats)
+		if stats.Changes() > 0 {
+			idle = 0
+			continue
+		}
+		if idle++; idle >= quiescentTicks {
+			report.Converged = true
+			break
+		}
+	}
+	for _, r := range s.ranges {
+		switch action, _ := s.allocator.ComputeAction(ctx, r.conf, &r.desc); action {
+		case AllocatorNoop, AllocatorConsiderRebalance:
+		default:
+			report.PendingActions = append(report.PendingActions, SimulatedRangeAction{
+				RangeID: r.desc.RangeID,
+				Action:  action,
+			})
+		}
+		if !s.leaseRespectsPreferences(r) {
+			report.LeasePreferenceViolations = append(report.LeasePreferenceViolations, r.desc.RangeID)
+		}
+	}
+	return report
+}
+
+// Tick advances the simulated time by one tick interval, gossips the store
+// descriptors and lets the replicate queue of each range's leaseholder
+// process the range once. Whenever the StoreRebalancer interval has elapsed,
+// it then gossips the store descriptors again and runs the StoreRebalancer of
+// each store.
+func (s *AllocatorSimulator) Tick(ctx context.Context) SimulatorTickStats {
+	s.tick++
+	s.manual.Increment(s.tickInterval.Nanoseconds())
+	s.cur = SimulatorTickStats{Tick: s.tick}
+	s.gossipStores()
+	for _, r := range s.ranges {
+		s.maybeAcquireLease(r)
+		rq := s.stores[r.leaseholder].rq
+		if _, err := rq.processOneChange(ctx, r, rq.canTransferLeaseFrom, false /* dryRun */); err != nil {
+			log.VEventf(ctx, 1, "%s: %v", r, err)
+			s.cur.Errors++
+		}
+	}
+	if s.now().Sub(s.lastStoreRebalance) >= storeRebalancerTimerDuration {
+		s.lastStoreRebalance = s.now()
+		s.gossipStores()
+		s.rebalanceStores(ctx)
+	}
+	s.cur.Stores = s.storeStats()
+	return s.cur
+}
+
+// rebalanceStores runs the StoreRebalancer of each store whose node is
+// around, like StoreRebalancer.Start does periodically.
+func (s *AllocatorSimulator) rebalanceStores(ctx context.Context) {
+	mode := LBRebalancingMode(LoadBasedRebalancingMode.Get(&s.st.SV))
+	if mode == LBRebalancingOff {
+		return
+	}
+	objective := loadBasedRebalancingObjective(ctx, s.st)
+	for _, storeID := range s.storeIDs {
+		store := s.stores[storeID]
+		if !s.nodeGossips(store.NodeID) {
+			continue
+		}
+		acc := store.replRankings.newAccumulator(objective)
+		for _, r := range s.ranges {
+			if r.leaseholder != storeID {
+				continue
+			}
+			acc.addReplica(replicaWithStats{
+				repl:        r,
+				qps:         r.usage.QueriesPerSecond,
+				requestTime: r.usage.RequestTimePerSecond,
+				writeBytes:  r.usage.WriteBytesPerSecond,
+			})
+		}
+		if acc.load.Len() == 0 {
+			// The replica rankings would keep returning the ranges the store held
+			// the lease for the last time it had any, and simulated ranges always
+			// claim to be owned by the store processing them.
+			continue
+		}
+		store.replRankings.update(acc)
+
+		leaseTransfers := store.sr.metrics.LeaseTransferCount.Count()
+		rangeRebalances := store.sr.metrics.RangeRebalanceCount.Count()
+		storeList, _, _ := s.storePool.getStoreList(storeFilterSuspect)
+		store.sr.rebalanceStore(store.sr.AnnotateCtx(ctx), mode, objective, storeList)
+		s.cur.LoadBasedLeaseTransfers += int(store.sr.metrics.LeaseTransferCount.Count() - leaseTransfers)
+		s.cur.LoadBasedRangeRebalances += int(store.sr.metrics.RangeRebalanceCount.Count() - rangeRebalances)
+	}
+}
+
+// storeStats computes the replicas and load of each store.
+func (s *AllocatorSimulator) storeStats() []SimulatedStoreStats {
+	stats := make([]SimulatedStoreStats, len(s.storeIDs))
+	idx := make(map[roachpb.StoreID]int, len(s.storeIDs))
+	for i, storeID := range s.storeIDs {
+		stats[i].StoreID = storeID
+		idx[storeID] = i
+	}
+	for _, r := range s.ranges {
+		for _, repl := range r.desc.Replicas().Descriptors() {
+			ss := &stats[idx[repl.StoreID]]
+			ss.RangeCount++
+			ss.LogicalBytes += r.usage.LogicalBytes
+			ss.WritesPerSecond += r.usage.WritesPerSecond
+			ss.WriteBytesPerSecond += r.usage.WriteBytesPerSecond
+		}
+		ss := &stats[idx[r.leaseholder]]
+		ss.LeaseCount++
+		ss.QueriesPerSecond += r.usage.QueriesPerSecond
+		ss.RequestTimePerSecond += r.usage.RequestTimePerSecond
+	}
+	return stats
+}
+
+// gossipStores supplies the StorePool with the current descriptors of the
+// stores whose nodes are still around, like those nodes would periodically
+// gossip them.
+func (s *AllocatorSimulator) gossipStores() {
+	for _, ss := range s.storeStats() {
+		store := s.stores[ss.StoreID]
+		if !s.nodeGossips(store.NodeID) {
+			continue
+		}
+		available := store.Capacity - ss.LogicalBytes
+		if available < 0 {
+			available = 0
+		}
+		s.storePool.storeDescriptorUpdate(roachpb.StoreDescriptor{
+			StoreID: store.StoreID,
+			Attrs:   store.Attrs,
+			Node: roachpb.NodeDescriptor{
+				NodeID:   store.NodeID,
+				Locality: store.Locality,
+			},
+			Capacity: roachpb.StoreCapacity{
+				Capacity:             store.Capacity,
+				Available:            available,
+				Used:                 ss.LogicalBytes,
+				LogicalBytes:         ss.LogicalBytes,
+				RangeCount:           int32(ss.RangeCount),
+				LeaseCount:           int32(ss.LeaseCount),
+				QueriesPerSecond:     ss.QueriesPerSecond,
+				WritesPerSecond:      ss.WritesPerSecond,
+				RequestTimePerSecond: ss.RequestTimePerSecond,
+				WriteBytesPerSecond:  ss.WriteBytesPerSecond,
+			},
+		})
+	}
+}
+
+// leaseRespectsPreferences returns whether the store of the range's
+// leaseholder satisfies one of the range's lease preferences, like
+// Replica.checkLeaseRespectsPreferences.
+func (s *AllocatorSimulator) leaseRespectsPreferences(r *simulatedRange) bool {
+	if len(r.conf.LeasePreferences) == 0 {
+		return true
+	}
+	storeDesc, ok := s.storePool.getStoreDescriptor(r.leaseholder)
+	if !ok {
+		return false
+	}
+	for _, preference := range r.conf.LeasePreferences {
+		if constraint.ConjunctionsCheck(storeDesc, preference.Constraints) {
+			return true
+		}
+	}
+	return false
+}
+
+// maybeAcquireLease moves the lease of a range whose leaseholder's store is
+// dead to the first live voter, which is what happens once the lease expires
+// and another replica acquires it.
+func (s *AllocatorSimulator) maybeAcquireLease(r *simulatedRange) {
+	if s.nodeGossips(s.stores[r.leaseholder].NodeID) {
+		return
+	}
+	for _, repl := range r.desc.Replicas().VoterDescriptors() {
+		if s.nodeGossips(repl.NodeID) {
+			r.leaseholder = repl.StoreID
+			return
+		}
+	}
+}
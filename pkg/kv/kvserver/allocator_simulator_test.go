@@ -0,0 +1,256 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/liveness/livenesspb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+const simulatorMaxTicks = 500
+
+// simulatorStores returns n stores, each on its own node, with the stores
+// spread round-robin across the given regions.
+func simulatorStores(n int, regions ...string) []SimulatedStore {
+	stores := make([]SimulatedStore, n)
+	for i := range stores {
+		stores[i] = SimulatedStore{
+			StoreID:  roachpb.StoreID(i + 1),
+			NodeID:   roachpb.NodeID(i + 1),
+			Capacity: 1 << 30,
+		}
+		if len(regions) > 0 {
+			stores[i].Locality = roachpb.Locality{Tiers: []roachpb.Tier{
+				{Key: "region", Value: regions[i%len(regions)]},
+			}}
+		}
+	}
+	return stores
+}
+
+// simulatorRanges returns n ranges using the given span config, with their
+// replicas on the given stores.
+func simulatorRanges(
+	n int, conf roachpb.SpanConfig, voters ...roachpb.StoreID,
+) []SimulatedRange {
+	ranges := make([]SimulatedRange, n)
+	for i := range ranges {
+		ranges[i] = SimulatedRange{
+			RangeID: roachpb.RangeID(i + 1),
+			Voters:  voters,
+			Config:  conf,
+			Usage: RangeUsageInfo{
+				LogicalBytes:     1 << 20,
+				QueriesPerSecond: float64(10 * (i + 1)),
+				WritesPerSecond:  1,
+			},
+		}
+	}
+	return ranges
+}
+
+func simulatorStoreStats(
+	t *testing.T, report AllocatorSimulationReport,
+) map[roachpb.StoreID]SimulatedStoreStats {
+	require.NotEmpty(t, report.Ticks)
+	stats := make(map[roachpb.StoreID]SimulatedStoreStats)
+	for _, s := range report.Totals().Stores {
+		stats[s.StoreID] = s
+	}
+	return stats
+}
+
+func TestAllocatorSimulatorUpReplicateAndRebalance(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	const numRanges = 20
+	sim, err := NewAllocatorSimulator(AllocatorSimulatorConfig{
+		Stores: simulatorStores(5),
+		Ranges: simulatorRanges(numRanges, roachpb.SpanConfig{NumReplicas: 3}, 1),
+	})
+	require.NoError(t, err)
+
+	report := sim.Run(ctx, simulatorMaxTicks)
+	t.Log(report)
+	require.True(t, report.Converged)
+	require.Empty(t, report.PendingActions)
+
+	totals := report.Totals()
+	require.Equal(t, 2*numRanges, totals.ReplicaAdditions-totals.ReplicaRemovals)
+	require.NotZero(t, totals.Rebalances)
+	require.NotZero(t, totals.LeaseTransfers)
+
+	const mean = 3 * numRanges / 5
+	var rangeCount, leaseCount int
+	for _, s := range simulatorStoreStats(t, report) {
+		require.InDelta(t, mean, s.RangeCount, 2, "s%d", s.StoreID)
+		require.NotZero(t, s.LeaseCount, "s%d", s.StoreID)
+		rangeCount += s.RangeCount
+		leaseCount += s.LeaseCount
+	}
+	require.Equal(t, 3*numRanges, rangeCount)
+	require.Equal(t, numRanges, leaseCount)
+}
+
+func TestAllocatorSimulatorZoneConfigChange(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	const numRanges = 6
+	// s1, s3 and s5 are in region a, s2, s4 and s6 in region b.
+	sim, err := NewAllocatorSimulator(AllocatorSimulatorConfig{
+		Stores: simulatorStores(6, "a", "b"),
+		Ranges: simulatorRanges(numRanges, roachpb.SpanConfig{NumReplicas: 3}, 1, 2, 3),
+	})
+	require.NoError(t, err)
+
+	inRegionB := []roachpb.Constraint{
+		{Key: "region", Value: "b", Type: roachpb.Constraint_REQUIRED},
+	}
+	require.NoError(t, sim.SetSpanConfig(roachpb.SpanConfig{
+		NumReplicas:      3,
+		Constraints:      []roachpb.ConstraintsConjunction{{Constraints: inRegionB}},
+		LeasePreferences: []roachpb.LeasePreference{{Constraints: inRegionB}},
+	}))
+
+	report := sim.Run(ctx, simulatorMaxTicks)
+	t.Log(report)
+	require.True(t, report.Converged)
+	require.Empty(t, report.PendingActions)
+	require.Empty(t, report.LeasePreferenceViolations)
+	require.NotZero(t, report.Totals().LeaseTransfers)
+
+	for _, s := range simulatorStoreStats(t, report) {
+		if s.StoreID%2 == 1 {
+			require.Zero(t, s.RangeCount, "s%d", s.StoreID)
+			require.Zero(t, s.LeaseCount, "s%d", s.StoreID)
+		} else {
+			require.Equal(t, numRanges, s.RangeCount, "s%d", s.StoreID)
+		}
+	}
+}
+
+func TestAllocatorSimulatorDeadNode(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	const numRanges = 8
+	sim, err := NewAllocatorSimulator(AllocatorSimulatorConfig{
+		Stores: simulatorStores(4),
+		Ranges: simulatorRanges(numRanges, roachpb.SpanConfig{NumReplicas: 3}, 3, 1, 2),
+	})
+	require.NoError(t, err)
+	sim.SetNodeLiveness(3, livenesspb.NodeLivenessStatus_DEAD)
+
+	report := sim.Run(ctx, simulatorMaxTicks)
+	t.Log(report)
+	require.True(t, report.Converged)
+	require.Empty(t, report.PendingActions)
+
+	stats := simulatorStoreStats(t, report)
+	require.Zero(t, stats[3].RangeCount)
+	require.Zero(t, stats[3].LeaseCount)
+	for _, storeID := range []roachpb.StoreID{1, 2, 4} {
+		require.Equal(t, numRanges, stats[storeID].RangeCount, "s%d", storeID)
+	}
+}
+
+// TestAllocatorSimulatorDeterministic verifies that simulations of the same
+// cluster make the same decisions.
+func TestAllocatorSimulatorDeterministic(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	run := func() AllocatorSimulationReport {
+		sim, err := NewAllocatorSimulator(AllocatorSimulatorConfig{
+			Stores: simulatorStores(7, "a", "b", "c"),
+			Ranges: simulatorRanges(30, roachpb.SpanConfig{NumReplicas: 3}, 1, 2, 3),
+		})
+		require.NoError(t, err)
+		return sim.Run(ctx, simulatorMaxTicks)
+	}
+	require.Equal(t, run(), run())
+}
+
+func TestAllocatorSimulatorLoadBasedRebalancing(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	// Each store holds a replica of every range and the lease of two of them,
+	// so the replicate queue has nothing to do. The two ranges with load both
+	// have their lease on s1 though, so its StoreRebalancer transfers one of
+	// those leases away when load-based rebalancing is enabled.
+	ranges := make([]SimulatedRange, 6)
+	for i := range ranges {
+		ranges[i] = SimulatedRange{
+			RangeID: roachpb.RangeID(i + 1),
+			Config:  roachpb.SpanConfig{NumReplicas: 3},
+			Usage:   RangeUsageInfo{LogicalBytes: 1 << 20},
+		}
+		switch i / 2 {
+		case 0:
+			ranges[i].Voters = []roachpb.StoreID{1, 2, 3}
+			ranges[i].Usage.QueriesPerSecond = 300
+		case 1:
+			ranges[i].Voters = []roachpb.StoreID{2, 3, 1}
+		case 2:
+			ranges[i].Voters = []roachpb.StoreID{3, 1, 2}
+		}
+	}
+
+	testCases := []struct {
+		mode                   LBRebalancingMode
+		expLeaseTransfers      int
+		expMaxQueriesPerSecond float64
+	}{
+		{LBRebalancingOff, 0, 600},
+		{LBRebalancingLeasesOnly, 1, 300},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("mode=%d", tc.mode), func(t *testing.T) {
+			ctx := context.Background()
+			st := cluster.MakeTestingClusterSettings()
+			LoadBasedRebalancingMode.Override(ctx, &st.SV, int64(tc.mode))
+			sim, err := NewAllocatorSimulator(AllocatorSimulatorConfig{
+				Settings: st,
+				Stores:   simulatorStores(3),
+				Ranges:   ranges,
+			})
+			require.NoError(t, err)
+
+			stats := sim.Tick(ctx)
+			require.Equal(t, tc.expLeaseTransfers, stats.LoadBasedLeaseTransfers)
+			require.Equal(t, tc.expLeaseTransfers, stats.LeaseTransfers)
+			require.Zero(t, stats.ReplicaAdditions+stats.ReplicaRemovals)
+			var maxQPS float64
+			for _, s := range stats.Stores {
+				if s.QueriesPerSecond > maxQPS {
+					maxQPS = s.QueriesPerSecond
+				}
+			}
+			require.Equal(t, tc.expMaxQueriesPerSecond, maxQPS)
+			require.Equal(t, tc.expMaxQueriesPerSecond, stats.Stores[0].QueriesPerSecond)
+		})
+	}
+}
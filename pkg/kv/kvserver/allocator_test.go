@@ -1002,6 +1002,8 @@ func TestAllocatorRebalanceTarget(t *testing.T) {
 
 	repl.leaseholderStats = newReplicaStats(clock, nil)
 	repl.writeStats = newReplicaStats(clock, nil)
+	repl.requestTimeStats = newReplicaStats(clock, nil)
+	repl.writeBytesStats = newReplicaStats(clock, nil)
 
 	var rangeUsageInfo RangeUsageInfo
 
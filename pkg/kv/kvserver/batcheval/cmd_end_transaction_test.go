@@ -949,6 +949,50 @@ func TestEndTxnUpdatesTransactionRecord(t *testing.T) {
 	}
 }
 
+// TestIsEndTxnTriggeringRetryError tests that a transaction whose write
+// timestamp has been pushed can only commit if its isolation level tolerates
+// write skew.
+func TestIsEndTxnTriggeringRetryError(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ts1 := hlc.Timestamp{WallTime: 1}
+	ts2 := hlc.Timestamp{WallTime: 2}
+	ts3 := hlc.Timestamp{WallTime: 3}
+	testCases := []struct {
+		isoLevel    enginepb.IsolationLevel
+		pushed      bool
+		writeTooOld bool
+		deadline    *hlc.Timestamp
+		expRetry    bool
+		expReason   roachpb.TransactionRetryReason
+	}{
+		{isoLevel: enginepb.SERIALIZABLE, expRetry: false},
+		{isoLevel: enginepb.SERIALIZABLE, pushed: true, expRetry: true, expReason: roachpb.RETRY_SERIALIZABLE},
+		{isoLevel: enginepb.SERIALIZABLE, writeTooOld: true, expRetry: true, expReason: roachpb.RETRY_WRITE_TOO_OLD},
+		{isoLevel: enginepb.SERIALIZABLE, deadline: &ts1, expRetry: true, expReason: roachpb.RETRY_COMMIT_DEADLINE_EXCEEDED},
+		{isoLevel: enginepb.READ_COMMITTED, expRetry: false},
+		{isoLevel: enginepb.READ_COMMITTED, pushed: true, expRetry: false},
+		{isoLevel: enginepb.READ_COMMITTED, pushed: true, deadline: &ts3, expRetry: false},
+		{isoLevel: enginepb.READ_COMMITTED, pushed: true, deadline: &ts2, expRetry: true, expReason: roachpb.RETRY_COMMIT_DEADLINE_EXCEEDED},
+		{isoLevel: enginepb.READ_COMMITTED, writeTooOld: true, expRetry: true, expReason: roachpb.RETRY_WRITE_TOO_OLD},
+	}
+	for _, tc := range testCases {
+		txn := roachpb.MakeTransaction("test", roachpb.Key("a"), 0, ts1, 0)
+		txn.IsoLevel = tc.isoLevel
+		txn.WriteTooOld = tc.writeTooOld
+		if tc.pushed {
+			txn.WriteTimestamp = ts2
+		}
+		args := &roachpb.EndTxnRequest{Commit: true, Deadline: tc.deadline}
+		retry, reason, _ := IsEndTxnTriggeringRetryError(&txn, args)
+		require.Equal(t, tc.expRetry, retry, "%+v", tc)
+		if tc.expRetry {
+			require.Equal(t, tc.expReason, reason, "%+v", tc)
+		}
+	}
+}
+
 // TestPartialRollbackOnEndTransaction verifies that the intent
 // resolution performed synchronously as a side effect of
 // EndTransaction request properly takes into account the ignored
@@ -249,6 +249,11 @@ func PushTxn(
 		// If just attempting to cleanup old or already-committed txns,
 		// pusher always fails.
 		pusherWins = false
+	case pushType == roachpb.PUSH_TIMESTAMP && reply.PusheeTxn.IsoLevel.ToleratesWriteSkew():
+		// The pushee can commit at the pushed timestamp without refreshing its
+		// reads, so the push does not force it to restart.
+		reason = "pushee tolerates write skew"
+		pusherWins = true
 	case txnwait.CanPushWithPriority(args.PusherTxn.Priority, reply.PusheeTxn.Priority):
 		reason = "pusher has priority"
 		pusherWins = true
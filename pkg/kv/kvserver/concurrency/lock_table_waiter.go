@@ -226,7 +226,10 @@ func (w *lockTableWaiterImpl) WaitOn(
 				// If the pushee has the minimum priority or if the pusher has the
 				// maximum priority, push immediately to proceed without queueing.
 				// The push should succeed without entering the txn wait-queue.
-				priorityPush := canPushWithPriority(req, state)
+				// The same is true for timestamp pushes of lock holders which
+				// tolerate write skew.
+				priorityPush := canPushWithPriority(req, state) ||
+					canPushTimestampImmediately(req, state)
 
 				// If the request doesn't want to perform a delayed push for any
 				// reason, continue waiting without a timer.
@@ -959,6 +962,19 @@ func canPushWithPriority(req Request, s waitingState) bool {
 	return txnwait.CanPushWithPriority(pusher, pushee)
 }
 
+// canPushTimestampImmediately returns whether the request pushes the timestamp
+// of the lock holder that it conflicts with, and the lock holder tolerates write
+// skew. Such pushes succeed regardless of priority, since the lock holder can
+// commit at the pushed timestamp without refreshing its reads.
+func canPushTimestampImmediately(req Request, s waitingState) bool {
+	if s.txn == nil || !s.held {
+		return false
+	}
+	return req.WaitPolicy == lock.WaitPolicy_Block &&
+		s.guardAccess == spanset.SpanReadOnly &&
+		s.txn.IsoLevel.ToleratesWriteSkew()
+}
+
 func minDuration(a, b time.Duration) time.Duration {
 	if a < b {
 		return a
@@ -267,8 +267,10 @@ func (mq *mergeQueue) process(
 	// Check if the merged range would need to be split, if so, skip merge.
 	// Use a lower threshold for load based splitting so we don't find ourselves
 	// in a situation where we keep merging ranges that would be split soon after
-	// by a small increase in load.
-	conservativeLoadBasedSplitThreshold := 0.5 * lhsRepl.SplitByLoadQPSThreshold()
+	// by a small increase in load. Note that the QPS measured for load based
+	// splitting is weighted along the load-based rebalancing objective, so it
+	// is compared against that objective's threshold.
+	conservativeLoadBasedSplitThreshold := 0.5 * lhsRepl.SplitByLoadThreshold(ctx)
 	shouldSplit, _ := shouldSplitRange(ctx, mergedDesc, mergedStats,
 		lhsRepl.GetMaxBytes(), lhsRepl.shouldBackpressureWrites(), confReader)
 	if shouldSplit || mergedQPS >= conservativeLoadBasedSplitThreshold {
@@ -0,0 +1,204 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/redact"
+)
+
+// LBRebalancingObjective is the load dimension that the store rebalancer tries
+// to balance across stores and that the load-based splitter measures ranges
+// by.
+type LBRebalancingObjective int64
+
+const (
+	// LBRebalancingQueries balances the number of batch requests per second
+	// served by each store.
+	LBRebalancingQueries LBRebalancingObjective = iota
+	// LBRebalancingRequestTime balances the time per second that each store
+	// spends evaluating requests. This is wall time, not CPU time: it includes
+	// time spent blocked on IO or waiting to be scheduled, so it only
+	// approximates the CPU usage of a store's ranges.
+	LBRebalancingRequestTime
+	// LBRebalancingWriteBytes balances the number of bytes per second written
+	// to each store.
+	LBRebalancingWriteBytes
+)
+
+var lbRebalancingObjectiveNames = map[int64]string{
+	int64(LBRebalancingQueries):     "qps",
+	int64(LBRebalancingRequestTime): "request_time",
+	int64(LBRebalancingWriteBytes):  "write_bytes",
+}
+
+// LoadBasedRebalancingObjective controls which load dimension load-based
+// rebalancing and load-based splitting consider.
+var LoadBasedRebalancingObjective = settings.RegisterEnumSetting(
+	"kv.allocator.load_based_rebalancing.objective",
+	"what load dimension load-based rebalancing and splitting balance across stores",
+	"qps",
+	lbRebalancingObjectiveNames,
+).WithPublic()
+
+// requestTimeRebalanceThreshold is the equivalent of qpsRebalanceThreshold for
+// the request_time objective.
+var requestTimeRebalanceThreshold = settings.RegisterFloatSetting(
+	"kv.allocator.request_time_rebalance_threshold",
+	"minimum fraction away from the mean a store's request evaluation time per second can be before it is considered overfull or underfull",
+	0.25,
+	settings.NonNegativeFloat,
+).WithPublic()
+
+// writeBytesRebalanceThreshold is the equivalent of qpsRebalanceThreshold for
+// the write_bytes objective.
+var writeBytesRebalanceThreshold = settings.RegisterFloatSetting(
+	"kv.allocator.write_bytes_rebalance_threshold",
+	"minimum fraction away from the mean a store's bytes written per second can be before it is considered overfull or underfull",
+	0.25,
+	settings.NonNegativeFloat,
+).WithPublic()
+
+const (
+	// minRequestTimeThresholdDifference is the equivalent of
+	// minQPSThresholdDifference for the request_time objective, in nanoseconds
+	// of request evaluation per second.
+	minRequestTimeThresholdDifference = float64(100 * time.Millisecond)
+
+	// minWriteBytesThresholdDifference is the equivalent of
+	// minQPSThresholdDifference for the write_bytes objective, in bytes written
+	// per second.
+	minWriteBytesThresholdDifference = 1 << 20 // 1 MiB/s
+)
+
+// loadBasedRebalancingObjective returns the objective selected by the
+// kv.allocator.load_based_rebalancing.objective cluster setting. Until the
+// LoadBasedRebalancingObjectives version is active, some stores may not gossip
+// their load along the other dimensions, so the objective is always
+// LBRebalancingQueries.
+func loadBasedRebalancingObjective(
+	ctx context.Context, st *cluster.Settings,
+) LBRebalancingObjective {
+	if !st.Version.IsActive(ctx, clusterversion.LoadBasedRebalancingObjectives) {
+		return LBRebalancingQueries
+	}
+	return LBRebalancingObjective(LoadBasedRebalancingObjective.Get(&st.SV))
+}
+
+// String implements the fmt.Stringer interface.
+func (o LBRebalancingObjective) String() string {
+	return lbRebalancingObjectiveNames[int64(o)]
+}
+
+// SafeValue implements the redact.SafeValue interface.
+func (o LBRebalancingObjective) SafeValue() {}
+
+// unit returns the unit the objective's load is measured in, for use in log
+// messages.
+func (o LBRebalancingObjective) unit() redact.SafeString {
+	switch o {
+	case LBRebalancingRequestTime:
+		return "request-ns/s"
+	case LBRebalancingWriteBytes:
+		return "write-bytes/s"
+	default:
+		return "qps"
+	}
+}
+
+// movesWithLease returns whether transferring a range's lease moves the range's
+// load along the objective's dimension. Requests are evaluated by the
+// leaseholder, but every replica of a range applies its writes.
+func (o LBRebalancingObjective) movesWithLease() bool {
+	return o != LBRebalancingWriteBytes
+}
+
+// storeLoad returns the load of a store along the objective's dimension.
+func (o LBRebalancingObjective) storeLoad(c roachpb.StoreCapacity) float64 {
+	switch o {
+	case LBRebalancingRequestTime:
+		return c.RequestTimePerSecond
+	case LBRebalancingWriteBytes:
+		return c.WriteBytesPerSecond
+	default:
+		return c.QueriesPerSecond
+	}
+}
+
+// adjustStoreLoad adds delta to the load of a store along the objective's
+// dimension.
+func (o LBRebalancingObjective) adjustStoreLoad(c *roachpb.StoreCapacity, delta float64) {
+	switch o {
+	case LBRebalancingRequestTime:
+		c.RequestTimePerSecond += delta
+	case LBRebalancingWriteBytes:
+		c.WriteBytesPerSecond += delta
+	default:
+		c.QueriesPerSecond += delta
+	}
+}
+
+// candidateMean returns the mean load of the candidate stores in the store
+// list along the objective's dimension.
+func (o LBRebalancingObjective) candidateMean(sl StoreList) float64 {
+	switch o {
+	case LBRebalancingRequestTime:
+		return sl.candidateRequestTimePerSecond.mean
+	case LBRebalancingWriteBytes:
+		return sl.candidateWriteBytesPerSecond.mean
+	default:
+		return sl.candidateQueriesPerSecond.mean
+	}
+}
+
+// replicaLoad returns the load of a replica along the objective's dimension.
+func (o LBRebalancingObjective) replicaLoad(r replicaWithStats) float64 {
+	switch o {
+	case LBRebalancingRequestTime:
+		return r.requestTime
+	case LBRebalancingWriteBytes:
+		return r.writeBytes
+	default:
+		return r.qps
+	}
+}
+
+// rebalanceThreshold returns the minimum fraction away from the mean a store's
+// load can be before it is considered overfull or underfull.
+func (o LBRebalancingObjective) rebalanceThreshold(sv *settings.Values) float64 {
+	switch o {
+	case LBRebalancingRequestTime:
+		return requestTimeRebalanceThreshold.Get(sv)
+	case LBRebalancingWriteBytes:
+		return writeBytesRebalanceThreshold.Get(sv)
+	default:
+		return qpsRebalanceThreshold.Get(sv)
+	}
+}
+
+// minThresholdDifference returns the minimum difference from the mean load
+// that the store rebalancer should care about.
+func (o LBRebalancingObjective) minThresholdDifference() float64 {
+	switch o {
+	case LBRebalancingRequestTime:
+		return minRequestTimeThresholdDifference
+	case LBRebalancingWriteBytes:
+		return minWriteBytesThresholdDifference
+	default:
+		return minQPSThresholdDifference
+	}
+}
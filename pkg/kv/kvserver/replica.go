@@ -223,6 +223,14 @@ type Replica struct {
 	// writeStats tracks the number of keys written by applied raft commands
 	// in order to aid in replica rebalancing decisions.
 	writeStats *replicaStats
+	// requestTimeStats tracks the wall time, in nanoseconds, spent evaluating
+	// requests on the replica. It aids in load-based rebalancing and splitting
+	// decisions when the objective is request_time.
+	requestTimeStats *replicaStats
+	// writeBytesStats tracks the number of bytes written by applied raft
+	// commands in order to aid in load-based rebalancing decisions when the
+	// objective is write_bytes.
+	writeBytesStats *replicaStats
 
 	// creatingReplica is set when a replica is created as uninitialized
 	// via a raft message.
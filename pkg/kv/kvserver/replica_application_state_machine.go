@@ -408,6 +408,7 @@ type replicaAppBatch struct {
 	entries      int
 	emptyEntries int
 	mutations    int
+	writeBytes   int64
 	start        time.Time
 }
 
@@ -555,6 +556,7 @@ func (b *replicaAppBatch) stageWriteBatch(ctx context.Context, cmd *replicatedCm
 	} else {
 		b.mutations += mutations
 	}
+	b.writeBytes += int64(len(wb.Data))
 	if err := b.batch.ApplyBatchRepr(wb.Data, false); err != nil {
 		return wrapWithNonDeterministicFailure(err, "unable to apply WriteBatch")
 	}
@@ -635,6 +637,7 @@ func (b *replicaAppBatch) runPreApplyTriggersAfterStagingWriteBatch(
 		if added := res.Delta.KeyCount; added > 0 {
 			b.r.writeStats.recordCount(float64(added), 0)
 		}
+		b.r.writeBytesStats.recordCount(float64(len(res.AddSSTable.Data)), 0)
 		res.AddSSTable = nil
 	}
 
@@ -938,8 +941,10 @@ func (b *replicaAppBatch) ApplyToStateMachine(ctx context.Context) error {
 	r.store.metrics.addMVCCStats(ctx, tenantID, deltaStats)
 
 	// Record the write activity, passing a 0 nodeID because replica.writeStats
-	// intentionally doesn't track the origin of the writes.
+	// and replica.writeBytesStats intentionally don't track the origin of the
+	// writes.
 	b.r.writeStats.recordCount(float64(b.mutations), 0 /* nodeID */)
+	b.r.writeBytesStats.recordCount(float64(b.writeBytes), 0 /* nodeID */)
 
 	now := timeutil.Now()
 	if needsSplitBySize && r.splitQueueThrottle.ShouldProcess(now) {
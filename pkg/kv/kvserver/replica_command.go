@@ -1197,6 +1197,14 @@ func maybeLeaveAtomicChangeReplicas(
 		})
 }
 
+// maybeLeaveAtomicChangeReplicasAndRemoveLearners calls the function of the
+// same name on the replica's current descriptor.
+func (r *Replica) maybeLeaveAtomicChangeReplicasAndRemoveLearners(
+	ctx context.Context,
+) (*roachpb.RangeDescriptor, error) {
+	return maybeLeaveAtomicChangeReplicasAndRemoveLearners(ctx, r.store, r.Desc())
+}
+
 // maybeLeaveAtomicChangeReplicasAndRemoveLearners transitions out of the joint
 // config (if there is one), and then removes all learners. After this function
 // returns, all remaining replicas will be of type VOTER_FULL or NON_VOTER.
@@ -2675,6 +2683,16 @@ func updateRangeDescriptor(
 	return nil
 }
 
+// relocateRange relocates the replica's range through its store's
+// AdminRelocateRange.
+func (r *Replica) relocateRange(
+	ctx context.Context,
+	desc roachpb.RangeDescriptor,
+	voterTargets, nonVoterTargets []roachpb.ReplicationTarget,
+) error {
+	return r.store.AdminRelocateRange(ctx, desc, voterTargets, nonVoterTargets)
+}
+
 // AdminRelocateRange relocates a given range to a given set of stores. The
 // first store in the slice becomes the new leaseholder.
 //
@@ -3202,7 +3220,7 @@ func (r *Replica) adminScatter(
 	// range. Note that we disable lease transfers until the final step as
 	// transferring the lease prevents any further action on this node.
 	var allowLeaseTransfer bool
-	canTransferLease := func(ctx context.Context, repl *Replica) bool { return allowLeaseTransfer }
+	canTransferLease := func(context.Context, leaseholderReplica) bool { return allowLeaseTransfer }
 	for re := retry.StartWithCtx(ctx, retryOpts); re.Next(); {
 		requeue, err := rq.processOneChange(ctx, r, canTransferLease, false /* dryRun */)
 		if err != nil {
@@ -255,6 +255,12 @@ func (r *Replica) QueriesPerSecond() (float64, time.Duration) {
 	return r.leaseholderStats.avgQPS()
 }
 
+// getLeaseholderStats returns the stats of the requests received by the
+// replica while it is the leaseholder, by locality of their gateway node.
+func (r *Replica) getLeaseholderStats() *replicaStats {
+	return r.leaseholderStats
+}
+
 // WritesPerSecond returns the range's average keys written per second. A
 // "Write" is a mutation applied by Raft as measured by
 // engine.RocksDBBatchCount(writeBatch). This corresponds roughly to the number
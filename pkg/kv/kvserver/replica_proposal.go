@@ -450,6 +450,9 @@ func (r *Replica) leasePostApplyLocked(
 		if r.leaseholderStats != nil {
 			r.leaseholderStats.resetRequestCounts()
 		}
+		if r.requestTimeStats != nil {
+			r.requestTimeStats.resetRequestCounts()
+		}
 		r.loadBasedSplitter.Reset(r.Clock().PhysicalTime())
 	}
 
@@ -509,6 +512,9 @@ func (r *Replica) leasePostApplyLocked(
 		if r.leaseholderStats != nil {
 			r.leaseholderStats.resetRequestCounts()
 		}
+		if r.requestTimeStats != nil {
+			r.requestTimeStats.resetRequestCounts()
+		}
 	}
 
 	// Potentially re-gossip if the range contains system data (e.g. system
@@ -800,7 +806,9 @@ func (r *Replica) evaluateProposal(
 	//
 	// TODO(tschottdorf): absorb all returned values in `res` below this point
 	// in the call stack as well.
+	evalStart := timeutil.Now()
 	batch, ms, br, res, pErr := r.evaluateWriteBatch(ctx, idKey, ba, lul, latchSpans, lockSpans)
+	r.recordBatchEvaluation(ctx, ba, latchSpans, timeutil.Since(evalStart))
 
 	// Note: reusing the proposer's batch when applying the command on the
 	// proposer was explored as an optimization but resulted in no performance
@@ -856,6 +864,8 @@ func (r *Replica) evaluateProposal(
 		res.WriteBatch = &kvserverpb.WriteBatch{
 			Data: batch.Repr(),
 		}
+		r.recordBatchForLoadBasedSplitting(
+			ctx, ba, latchSpans, LBRebalancingWriteBytes, len(res.WriteBatch.Data))
 
 		// Set the proposal's replicated result, which contains metadata and
 		// side-effects that are to be replicated to all replicas.
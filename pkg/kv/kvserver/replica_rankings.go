@@ -22,17 +22,25 @@ const (
 )
 
 type replicaWithStats struct {
-	repl *Replica
+	repl leaseholderReplica
 	qps  float64
-	// TODO(aayush): Include writes-per-second and logicalBytes of storage?
+	// requestTime is the time per second, in nanoseconds, spent evaluating
+	// requests on the replica.
+	requestTime float64
+	// writeBytes is the number of bytes per second written to the replica.
+	writeBytes float64
+	// TODO(aayush): Include logicalBytes of storage?
 }
 
-// replicaRankings maintains top-k orderings of the replicas in a store by QPS.
+// replicaRankings maintains top-k orderings of the replicas in a store by QPS
+// and by load, as measured along the LBRebalancingObjective in use when the
+// rankings were last updated.
 type replicaRankings struct {
 	mu struct {
 		syncutil.Mutex
-		qpsAccumulator *rrAccumulator
-		byQPS          []replicaWithStats
+		accumulator *rrAccumulator
+		byQPS       []replicaWithStats
+		byLoad      []replicaWithStats
 	}
 }
 
@@ -40,29 +48,46 @@ func newReplicaRankings() *replicaRankings {
 	return &replicaRankings{}
 }
 
-func (rr *replicaRankings) newAccumulator() *rrAccumulator {
+// newAccumulator returns an accumulator that ranks replicas by their load
+// along the given objective.
+func (rr *replicaRankings) newAccumulator(objective LBRebalancingObjective) *rrAccumulator {
 	res := &rrAccumulator{}
 	res.qps.val = func(r replicaWithStats) float64 { return r.qps }
+	res.load.val = objective.replicaLoad
 	return res
 }
 
 func (rr *replicaRankings) update(acc *rrAccumulator) {
 	rr.mu.Lock()
-	rr.mu.qpsAccumulator = acc
+	rr.mu.accumulator = acc
 	rr.mu.Unlock()
 }
 
+// topQPS returns the hottest replicas in the store, ordered by decreasing QPS.
 func (rr *replicaRankings) topQPS() []replicaWithStats {
 	rr.mu.Lock()
 	defer rr.mu.Unlock()
 	// If we have a new set of data, consume it. Otherwise, just return the most
 	// recently consumed data.
-	if rr.mu.qpsAccumulator != nil && rr.mu.qpsAccumulator.qps.Len() > 0 {
-		rr.mu.byQPS = consumeAccumulator(&rr.mu.qpsAccumulator.qps)
+	if rr.mu.accumulator != nil && rr.mu.accumulator.qps.Len() > 0 {
+		rr.mu.byQPS = consumeAccumulator(&rr.mu.accumulator.qps)
 	}
 	return rr.mu.byQPS
 }
 
+// topLoad returns the hottest replicas in the store, ordered by decreasing
+// load.
+func (rr *replicaRankings) topLoad() []replicaWithStats {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	// If we have a new set of data, consume it. Otherwise, just return the most
+	// recently consumed data.
+	if rr.mu.accumulator != nil && rr.mu.accumulator.load.Len() > 0 {
+		rr.mu.byLoad = consumeAccumulator(&rr.mu.accumulator.load)
+	}
+	return rr.mu.byLoad
+}
+
 // rrAccumulator is used to update the replicas tracked by replicaRankings.
 // The typical pattern should be to call replicaRankings.newAccumulator, add
 // all the replicas you care about to the accumulator using addReplica, then
@@ -72,21 +97,29 @@ func (rr *replicaRankings) topQPS() []replicaWithStats {
 // prevents concurrent loaders of data from messing with each other -- the last
 // `update`d accumulator will win.
 type rrAccumulator struct {
-	qps rrPriorityQueue
+	qps  rrPriorityQueue
+	load rrPriorityQueue
 }
 
 func (a *rrAccumulator) addReplica(repl replicaWithStats) {
+	a.qps.maybePush(repl)
+	a.load.maybePush(repl)
+}
+
+// maybePush pushes the replica onto the heap if the heap isn't full or if the
+// replica is more deserving than the current tip of the heap.
+func (pq *rrPriorityQueue) maybePush(repl replicaWithStats) {
 	// If the heap isn't full, just push the new replica and return.
-	if a.qps.Len() < numTopReplicasToTrack {
-		heap.Push(&a.qps, repl)
+	if pq.Len() < numTopReplicasToTrack {
+		heap.Push(pq, repl)
 		return
 	}
 
 	// Otherwise, conditionally push if the new replica is more deserving than
 	// the current tip of the heap.
-	if repl.qps > a.qps.entries[0].qps {
-		heap.Pop(&a.qps)
-		heap.Push(&a.qps, repl)
+	if pq.val(repl) > pq.val(pq.entries[0]) {
+		heap.Pop(pq)
+		heap.Push(pq, repl)
 	}
 }
 
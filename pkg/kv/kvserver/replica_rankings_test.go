@@ -38,7 +38,7 @@ func TestReplicaRankings(t *testing.T) {
 	}
 
 	for _, tc := range testCases {
-		acc := rr.newAccumulator()
+		acc := rr.newAccumulator(LBRebalancingQueries)
 
 		// Randomize the order of the inputs each time the test is run.
 		want := make([]float64, len(tc.replicasByQPS))
@@ -56,7 +56,7 @@ func TestReplicaRankings(t *testing.T) {
 		rr.update(acc)
 
 		// Make sure we can read off all expected replicas in the correct order.
-		repls := rr.topQPS()
+		repls := rr.topLoad()
 		if len(repls) != len(want) {
 			t.Errorf("wrong number of replicas in output; got: %v; want: %v", repls, tc.replicasByQPS)
 			continue
@@ -67,9 +67,58 @@ func TestReplicaRankings(t *testing.T) {
 				break
 			}
 		}
-		replsCopy := rr.topQPS()
+		replsCopy := rr.topLoad()
 		if !reflect.DeepEqual(repls, replsCopy) {
-			t.Errorf("got different replicas on second call to topQPS; first call: %v, second call: %v", repls, replsCopy)
+			t.Errorf("got different replicas on second call to topLoad; first call: %v, second call: %v", repls, replsCopy)
 		}
 	}
 }
+
+// TestReplicaRankingsObjective verifies that replicas are ranked by their load
+// along the objective the accumulator was created with.
+func TestReplicaRankingsObjective(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	// Each replica is the hottest along exactly one dimension.
+	repls := []replicaWithStats{
+		{repl: &Replica{RangeID: 1}, qps: 300, requestTime: 2e6, writeBytes: 10},
+		{repl: &Replica{RangeID: 2}, qps: 100, requestTime: 3e6, writeBytes: 20},
+		{repl: &Replica{RangeID: 3}, qps: 200, requestTime: 1e6, writeBytes: 30},
+	}
+	testCases := []struct {
+		objective LBRebalancingObjective
+		want      []roachpb.RangeID
+	}{
+		{LBRebalancingQueries, []roachpb.RangeID{1, 3, 2}},
+		{LBRebalancingRequestTime, []roachpb.RangeID{2, 1, 3}},
+		{LBRebalancingWriteBytes, []roachpb.RangeID{3, 2, 1}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.objective.String(), func(t *testing.T) {
+			rr := newReplicaRankings()
+			acc := rr.newAccumulator(tc.objective)
+			for _, repl := range repls {
+				acc.addReplica(repl)
+			}
+			rr.update(acc)
+
+			var got []roachpb.RangeID
+			for _, repl := range rr.topLoad() {
+				got = append(got, repl.repl.GetRangeID())
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got ranking %v; want %v", got, tc.want)
+			}
+
+			// The QPS ranking doesn't depend on the objective.
+			got = nil
+			for _, repl := range rr.topQPS() {
+				got = append(got, repl.repl.GetRangeID())
+			}
+			if wantQPS := []roachpb.RangeID{1, 3, 2}; !reflect.DeepEqual(got, wantQPS) {
+				t.Errorf("got QPS ranking %v; want %v", got, wantQPS)
+			}
+		})
+	}
+}
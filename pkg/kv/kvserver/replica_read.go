@@ -26,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/kr/pretty"
 )
 
@@ -82,9 +83,11 @@ func (r *Replica) executeReadOnlyBatch(
 	// the latches are released.
 
 	var result result.Result
+	evalStart := timeutil.Now()
 	br, result, pErr = r.executeReadOnlyBatchWithServersideRefreshes(
 		ctx, rw, rec, ba, localUncertaintyLimit, spans,
 	)
+	r.recordBatchEvaluation(ctx, ba, spans, timeutil.Since(evalStart))
 
 	// If the request hit a server-side concurrency retry error, immediately
 	// propagate the error. Don't assume ownership of the concurrency guard.
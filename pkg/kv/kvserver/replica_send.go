@@ -333,7 +333,7 @@ func (r *Replica) executeBatchWithConcurrencyRetries(
 	}
 
 	// Handle load-based splitting.
-	r.recordBatchForLoadBasedSplitting(ctx, ba, latchSpans)
+	r.recordBatchForLoadBasedSplitting(ctx, ba, latchSpans, LBRebalancingQueries, len(ba.Requests))
 
 	// Try to execute command; exit retry loop on success.
 	var g *concurrency.Guard
@@ -12,10 +12,12 @@ package kvserver
 
 import (
 	"context"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 )
 
@@ -33,9 +35,40 @@ var SplitByLoadQPSThreshold = settings.RegisterIntSetting(
 	2500, // 2500 req/s
 ).WithPublic()
 
-// SplitByLoadQPSThreshold returns the QPS request rate for a given replica.
-func (r *Replica) SplitByLoadQPSThreshold() float64 {
-	return float64(SplitByLoadQPSThreshold.Get(&r.store.cfg.Settings.SV))
+// SplitByLoadRequestTimeThreshold wraps
+// "kv.range_split.load_request_time_threshold".
+var SplitByLoadRequestTimeThreshold = settings.RegisterDurationSetting(
+	"kv.range_split.load_request_time_threshold",
+	"the request evaluation time per second over which, the range becomes a candidate for load based splitting when the load-based rebalancing objective is request_time",
+	500*time.Millisecond,
+	settings.PositiveDuration,
+).WithPublic()
+
+// SplitByLoadWriteBytesThreshold wraps
+// "kv.range_split.load_write_bytes_threshold".
+var SplitByLoadWriteBytesThreshold = settings.RegisterByteSizeSetting(
+	"kv.range_split.load_write_bytes_threshold",
+	"the bytes written per second over which, the range becomes a candidate for load based splitting when the load-based rebalancing objective is write_bytes",
+	16<<20, // 16 MiB/s
+).WithPublic()
+
+// SplitByLoadThreshold returns the load over which a given replica becomes a
+// candidate for load based splitting, measured along the load-based
+// rebalancing objective.
+func (r *Replica) SplitByLoadThreshold(ctx context.Context) float64 {
+	return splitByLoadThreshold(ctx, r.store.cfg.Settings)
+}
+
+func splitByLoadThreshold(ctx context.Context, st *cluster.Settings) float64 {
+	sv := &st.SV
+	switch loadBasedRebalancingObjective(ctx, st) {
+	case LBRebalancingRequestTime:
+		return float64(SplitByLoadRequestTimeThreshold.Get(sv))
+	case LBRebalancingWriteBytes:
+		return float64(SplitByLoadWriteBytesThreshold.Get(sv))
+	default:
+		return float64(SplitByLoadQPSThreshold.Get(sv))
+	}
 }
 
 // SplitByLoadEnabled returns whether load based splitting is enabled.
@@ -47,17 +80,34 @@ func (r *Replica) SplitByLoadEnabled() bool {
 }
 
 // recordBatchForLoadBasedSplitting records the batch's spans to be considered
-// for load based splitting.
+// for load based splitting, weighted by the load the batch contributed along
+// the given objective. Batches are only recorded if the objective is the
+// load-based rebalancing objective in use.
 func (r *Replica) recordBatchForLoadBasedSplitting(
-	ctx context.Context, ba *roachpb.BatchRequest, spans *spanset.SpanSet,
+	ctx context.Context,
+	ba *roachpb.BatchRequest,
+	spans *spanset.SpanSet,
+	objective LBRebalancingObjective,
+	load int,
 ) {
-	if !r.SplitByLoadEnabled() {
+	if !r.SplitByLoadEnabled() ||
+		loadBasedRebalancingObjective(ctx, r.store.cfg.Settings) != objective {
 		return
 	}
-	shouldInitSplit := r.loadBasedSplitter.Record(timeutil.Now(), len(ba.Requests), func() roachpb.Span {
+	shouldInitSplit := r.loadBasedSplitter.Record(timeutil.Now(), load, func() roachpb.Span {
 		return spans.BoundarySpan(spanset.SpanGlobal)
 	})
 	if shouldInitSplit {
 		r.store.splitQueue.MaybeAddAsync(ctx, r, r.store.Clock().NowAsClockTimestamp())
 	}
 }
+
+// recordBatchEvaluation records the time spent evaluating a batch in the
+// replica's stats and, when the load-based rebalancing objective is request_time, for
+// load based splitting.
+func (r *Replica) recordBatchEvaluation(
+	ctx context.Context, ba *roachpb.BatchRequest, spans *spanset.SpanSet, evalDuration time.Duration,
+) {
+	r.requestTimeStats.recordDuration(evalDuration)
+	r.recordBatchForLoadBasedSplitting(ctx, ba, spans, LBRebalancingRequestTime, int(evalDuration))
+}
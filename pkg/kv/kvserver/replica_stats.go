@@ -38,7 +38,10 @@ type perLocalityCounts map[string]float64
 
 // replicaStats maintains statistics about the work done by a replica. Its
 // initial use is tracking the number of requests received from each
-// cluster locality in order to inform lease transfer decisions.
+// cluster locality in order to inform lease transfer decisions. The recorded
+// counts need not be request counts: they can also be weighted by the amount
+// of work done, such as the keys or bytes written or the time spent evaluating
+// requests, in which case avgQPS returns that amount of work per second.
 type replicaStats struct {
 	clock           *hlc.Clock
 	getNodeLocality localityOracle
@@ -106,6 +109,11 @@ func (rs *replicaStats) record(nodeID roachpb.NodeID) {
 	rs.recordCount(1, nodeID)
 }
 
+// recordDuration records the given duration of work, in nanoseconds.
+func (rs *replicaStats) recordDuration(d time.Duration) {
+	rs.recordCount(float64(d.Nanoseconds()), 0)
+}
+
 func (rs *replicaStats) recordCount(count float64, nodeID roachpb.NodeID) {
 	var locality string
 	if rs.getNodeLocality != nil {
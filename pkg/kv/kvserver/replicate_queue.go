@@ -156,6 +156,41 @@ func makeReplicateQueueMetrics() ReplicateQueueMetrics {
 	}
 }
 
+// leaseholderReplica is the view of a leaseholder replica through which the
+// replicate queue and the StoreRebalancer decide on and carry out replication
+// changes and lease transfers. Besides *Replica, it is implemented by the
+// ranges of an AllocatorSimulator, which drives both against a simulated
+// cluster.
+type leaseholderReplica interface {
+	replicaInQueue
+	fmt.Stringer
+	DescAndSpanConfig() (*roachpb.RangeDescriptor, roachpb.SpanConfig)
+	RaftStatus() *raft.Status
+	LastReplicaAdded() (roachpb.ReplicaID, time.Time)
+	OwnsValidLease(context.Context, hlc.ClockTimestamp) bool
+	QueriesPerSecond() (float64, time.Duration)
+	AdminTransferLease(ctx context.Context, target roachpb.StoreID) error
+	checkLeaseRespectsPreferences(context.Context) (bool, error)
+	getLeaseholderStats() *replicaStats
+	rangeUsageInfo() RangeUsageInfo
+	changeReplicasImpl(
+		ctx context.Context,
+		desc *roachpb.RangeDescriptor,
+		priority SnapshotRequest_Priority,
+		reason kvserverpb.RangeLogEventReason,
+		details string,
+		chgs roachpb.ReplicationChanges,
+	) (*roachpb.RangeDescriptor, error)
+	relocateRange(
+		ctx context.Context,
+		desc roachpb.RangeDescriptor,
+		voterTargets, nonVoterTargets []roachpb.ReplicationTarget,
+	) error
+	maybeLeaveAtomicChangeReplicasAndRemoveLearners(context.Context) (*roachpb.RangeDescriptor, error)
+}
+
+var _ leaseholderReplica = &Replica{}
+
 // replicateQueue manages a queue of replicas which may need to add an
 // additional replica to their range.
 type replicateQueue struct {
@@ -164,6 +199,9 @@ type replicateQueue struct {
 	allocator         Allocator
 	updateChan        chan time.Time
 	lastLeaseTransfer atomic.Value // read and written by scanner & queue goroutines
+	// now returns the current time. It is timeutil.Now, except in an
+	// AllocatorSimulator, which runs the queue in simulated time.
+	now func() time.Time
 }
 
 // newReplicateQueue returns a new instance of replicateQueue.
@@ -172,6 +210,7 @@ func newReplicateQueue(store *Store, allocator Allocator) *replicateQueue {
 		metrics:    makeReplicateQueueMetrics(),
 		allocator:  allocator,
 		updateChan: make(chan time.Time, 1),
+		now:        timeutil.Now,
 	}
 	store.metrics.registry.AddMetricStruct(&rq.metrics)
 	rq.baseQueue = newBaseQueue(
@@ -243,7 +282,7 @@ func (rq *replicateQueue) shouldQueue(
 	voterReplicas := desc.Replicas().VoterDescriptors()
 	nonVoterReplicas := desc.Replicas().NonVoterDescriptors()
 	if !rq.store.TestingKnobs().DisableReplicaRebalancing {
-		rangeUsageInfo := rangeUsageInfoForRepl(repl)
+		rangeUsageInfo := repl.rangeUsageInfo()
 		_, _, _, ok := rq.allocator.RebalanceVoter(
 			ctx,
 			conf,
@@ -336,8 +375,8 @@ func (rq *replicateQueue) process(
 
 func (rq *replicateQueue) processOneChange(
 	ctx context.Context,
-	repl *Replica,
-	canTransferLeaseFrom func(ctx context.Context, repl *Replica) bool,
+	repl leaseholderReplica,
+	canTransferLeaseFrom func(context.Context, leaseholderReplica) bool,
 	dryRun bool,
 ) (requeue bool, _ error) {
 	// Check lease and destroy status here. The queue does this higher up already, but
@@ -481,7 +520,7 @@ func (rq *replicateQueue) processOneChange(
 	case AllocatorConsiderRebalance:
 		return rq.considerRebalance(ctx, repl, voterReplicas, nonVoterReplicas, canTransferLeaseFrom, dryRun)
 	case AllocatorFinalizeAtomicReplicationChange:
-		_, err := maybeLeaveAtomicChangeReplicasAndRemoveLearners(ctx, repl.store, repl.Desc())
+		_, err := repl.maybeLeaveAtomicChangeReplicasAndRemoveLearners(ctx)
 		// Requeue because either we failed to transition out of a joint state
 		// (bad) or we did and there might be more to do for that range.
 		return true, err
@@ -512,7 +551,7 @@ func getRemoveIdx(
 // follow-up step for the next scanner cycle.
 func (rq *replicateQueue) addOrReplaceVoters(
 	ctx context.Context,
-	repl *Replica,
+	repl leaseholderReplica,
 	liveVoterReplicas, liveNonVoterReplicas []roachpb.ReplicaDescriptor,
 	removeIdx int,
 	dryRun bool,
@@ -655,7 +694,7 @@ func (rq *replicateQueue) addOrReplaceVoters(
 // addOrReplaceNonVoters adds a non-voting replica to `repl`s range.
 func (rq *replicateQueue) addOrReplaceNonVoters(
 	ctx context.Context,
-	repl *Replica,
+	repl leaseholderReplica,
 	liveVoterReplicas, liveNonVoterReplicas []roachpb.ReplicaDescriptor,
 	removeIdx int,
 	dryRun bool,
@@ -795,12 +834,12 @@ func (rq *replicateQueue) findRemoveVoter(
 // returned if it returns false.
 func (rq *replicateQueue) maybeTransferLeaseAway(
 	ctx context.Context,
-	repl *Replica,
+	repl leaseholderReplica,
 	removeStoreID roachpb.StoreID,
 	dryRun bool,
-	canTransferLeaseFrom func(ctx context.Context, repl *Replica) bool,
+	canTransferLeaseFrom func(context.Context, leaseholderReplica) bool,
 ) (done bool, _ error) {
-	if removeStoreID != repl.store.StoreID() {
+	if removeStoreID != repl.StoreID() {
 		return false, nil
 	}
 	if canTransferLeaseFrom != nil && !canTransferLeaseFrom(ctx, repl) {
@@ -834,7 +873,7 @@ func (rq *replicateQueue) maybeTransferLeaseAway(
 
 func (rq *replicateQueue) removeVoter(
 	ctx context.Context,
-	repl *Replica,
+	repl leaseholderReplica,
 	existingVoters, existingNonVoters []roachpb.ReplicaDescriptor,
 	dryRun bool,
 ) (requeue bool, _ error) {
@@ -883,7 +922,7 @@ func (rq *replicateQueue) removeVoter(
 
 func (rq *replicateQueue) removeNonVoter(
 	ctx context.Context,
-	repl *Replica,
+	repl leaseholderReplica,
 	existingVoters, existingNonVoters []roachpb.ReplicaDescriptor,
 	dryRun bool,
 ) (requeue bool, _ error) {
@@ -923,7 +962,7 @@ func (rq *replicateQueue) removeNonVoter(
 }
 
 func (rq *replicateQueue) removeDecommissioning(
-	ctx context.Context, repl *Replica, targetType targetReplicaType, dryRun bool,
+	ctx context.Context, repl leaseholderReplica, targetType targetReplicaType, dryRun bool,
 ) (requeue bool, _ error) {
 	desc := repl.Desc()
 	var decommissioningReplicas []roachpb.ReplicaDescriptor
@@ -980,7 +1019,7 @@ func (rq *replicateQueue) removeDecommissioning(
 
 func (rq *replicateQueue) removeDead(
 	ctx context.Context,
-	repl *Replica,
+	repl leaseholderReplica,
 	deadReplicas []roachpb.ReplicaDescriptor,
 	targetType targetReplicaType,
 	dryRun bool,
@@ -1024,7 +1063,7 @@ func (rq *replicateQueue) removeDead(
 }
 
 func (rq *replicateQueue) removeLearner(
-	ctx context.Context, repl *Replica, dryRun bool,
+	ctx context.Context, repl leaseholderReplica, dryRun bool,
 ) (requeue bool, _ error) {
 	desc := repl.Desc()
 	learnerReplicas := desc.Replicas().LearnerDescriptors()
@@ -1060,15 +1099,15 @@ func (rq *replicateQueue) removeLearner(
 
 func (rq *replicateQueue) considerRebalance(
 	ctx context.Context,
-	repl *Replica,
+	repl leaseholderReplica,
 	existingVoters, existingNonVoters []roachpb.ReplicaDescriptor,
-	canTransferLeaseFrom func(ctx context.Context, repl *Replica) bool,
+	canTransferLeaseFrom func(context.Context, leaseholderReplica) bool,
 	dryRun bool,
 ) (requeue bool, _ error) {
 	desc, conf := repl.DescAndSpanConfig()
 	rebalanceTargetType := voterTarget
 	if !rq.store.TestingKnobs().DisableReplicaRebalancing {
-		rangeUsageInfo := rangeUsageInfoForRepl(repl)
+		rangeUsageInfo := repl.rangeUsageInfo()
 		addTarget, removeTarget, details, ok := rq.allocator.RebalanceVoter(
 			ctx,
 			conf,
@@ -1297,7 +1336,7 @@ func (o leaseTransferOutcome) String() string {
 // transfers the lease away.
 func (rq *replicateQueue) shedLease(
 	ctx context.Context,
-	repl *Replica,
+	repl leaseholderReplica,
 	desc *roachpb.RangeDescriptor,
 	conf roachpb.SpanConfig,
 	opts transferLeaseOptions,
@@ -1309,7 +1348,7 @@ func (rq *replicateQueue) shedLease(
 		conf,
 		desc.Replicas().VoterDescriptors(),
 		repl,
-		repl.leaseholderStats,
+		repl.getLeaseholderStats(),
 		opts.checkTransferLeaseSource,
 		opts.checkCandidateFullness,
 		false, /* alwaysAllowDecisionWithoutStats */
@@ -1323,7 +1362,7 @@ func (rq *replicateQueue) shedLease(
 		return noTransferDryRun, nil
 	}
 
-	avgQPS, qpsMeasurementDur := repl.leaseholderStats.avgQPS()
+	avgQPS, qpsMeasurementDur := repl.QueriesPerSecond()
 	if qpsMeasurementDur < MinStatsDuration {
 		avgQPS = 0
 	}
@@ -1334,22 +1373,22 @@ func (rq *replicateQueue) shedLease(
 }
 
 func (rq *replicateQueue) transferLease(
-	ctx context.Context, repl *Replica, target roachpb.ReplicaDescriptor, rangeQPS float64,
+	ctx context.Context, repl leaseholderReplica, target roachpb.ReplicaDescriptor, rangeQPS float64,
 ) error {
 	rq.metrics.TransferLeaseCount.Inc(1)
 	log.VEventf(ctx, 1, "transferring lease to s%d", target.StoreID)
 	if err := repl.AdminTransferLease(ctx, target.StoreID); err != nil {
 		return errors.Wrapf(err, "%s: unable to transfer lease to s%d", repl, target.StoreID)
 	}
-	rq.lastLeaseTransfer.Store(timeutil.Now())
+	rq.lastLeaseTransfer.Store(rq.now())
 	rq.allocator.storePool.updateLocalStoresAfterLeaseTransfer(
-		repl.store.StoreID(), target.StoreID, rangeQPS)
+		repl.StoreID(), target.StoreID, rangeQPS)
 	return nil
 }
 
 func (rq *replicateQueue) changeReplicas(
 	ctx context.Context,
-	repl *Replica,
+	repl leaseholderReplica,
 	chgs roachpb.ReplicationChanges,
 	desc *roachpb.RangeDescriptor,
 	priority SnapshotRequest_Priority,
@@ -1366,7 +1405,7 @@ func (rq *replicateQueue) changeReplicas(
 	if _, err := repl.changeReplicasImpl(ctx, desc, priority, reason, details, chgs); err != nil {
 		return err
 	}
-	rangeUsageInfo := rangeUsageInfoForRepl(repl)
+	rangeUsageInfo := repl.rangeUsageInfo()
 	for _, chg := range chgs {
 		rq.allocator.storePool.updateLocalStoreAfterRebalance(
 			chg.Target.StoreID, rangeUsageInfo, chg.ChangeType)
@@ -1377,7 +1416,7 @@ func (rq *replicateQueue) changeReplicas(
 // canTransferLeaseFrom checks is a lease can be transferred from the specified
 // replica. It considers two factors if the replica is in -conformance with
 // lease preferences and the last time a transfer occurred to avoid thrashing.
-func (rq *replicateQueue) canTransferLeaseFrom(ctx context.Context, repl *Replica) bool {
+func (rq *replicateQueue) canTransferLeaseFrom(ctx context.Context, repl leaseholderReplica) bool {
 	// Do a best effort check to see if this replica conforms to the configured
 	// lease preferences (if any), if it does not we want to encourage more
 	// aggressive lease movement and not delay it.
@@ -1387,7 +1426,7 @@ func (rq *replicateQueue) canTransferLeaseFrom(ctx context.Context, repl *Replic
 	}
 	if lastLeaseTransfer := rq.lastLeaseTransfer.Load(); lastLeaseTransfer != nil {
 		minInterval := MinLeaseTransferInterval.Get(&rq.store.cfg.Settings.SV)
-		return timeutil.Since(lastLeaseTransfer.(time.Time)) > minInterval
+		return rq.now().Sub(lastLeaseTransfer.(time.Time)) > minInterval
 	}
 	return true
 }
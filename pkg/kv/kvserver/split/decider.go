@@ -88,7 +88,10 @@ func Init(
 // Record notifies the Decider that 'n' operations are being carried out which
 // operate on the span returned by the supplied method. The closure will only
 // be called when necessary, that is, when the Decider is considering a split
-// and is sampling key spans to determine a suitable split point.
+// and is sampling key spans to determine a suitable split point. Callers may
+// also weight the operations by the load they represent (e.g. the time spent
+// evaluating them), in which case the QPS tracked by the Decider, and the
+// threshold it is compared against, are in units of that load per second.
 //
 // If the returned boolean is true, a split key is available (though it may
 // disappear as more keys are sampled) and should be initiated by the caller,
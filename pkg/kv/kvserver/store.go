@@ -2422,10 +2422,13 @@ func (s *Store) Capacity(ctx context.Context, useCached bool) (roachpb.StoreCapa
 	var logicalBytes int64
 	var totalQueriesPerSecond float64
 	var totalWritesPerSecond float64
+	var totalRequestTimePerSecond float64
+	var totalWriteBytesPerSecond float64
 	replicaCount := s.metrics.ReplicaCount.Value()
 	bytesPerReplica := make([]float64, 0, replicaCount)
 	writesPerReplica := make([]float64, 0, replicaCount)
-	rankingsAccumulator := s.replRankings.newAccumulator()
+	rankingsAccumulator := s.replRankings.newAccumulator(
+		loadBasedRebalancingObjective(ctx, s.cfg.Settings))
 	newStoreReplicaVisitor(s).Visit(func(r *Replica) bool {
 		rangeCount++
 		if r.OwnsValidLease(ctx, now) {
@@ -2448,9 +2451,21 @@ func (s *Store) Capacity(ctx context.Context, useCached bool) (roachpb.StoreCapa
 			totalWritesPerSecond += wps
 			writesPerReplica = append(writesPerReplica, wps)
 		}
+		var requestTime float64
+		if avgRequestTime, dur := r.requestTimeStats.avgQPS(); dur >= MinStatsDuration {
+			requestTime = avgRequestTime
+			totalRequestTimePerSecond += avgRequestTime
+		}
+		var writeBytes float64
+		if avgWriteBytes, dur := r.writeBytesStats.avgQPS(); dur >= MinStatsDuration {
+			writeBytes = avgWriteBytes
+			totalWriteBytesPerSecond += avgWriteBytes
+		}
 		rankingsAccumulator.addReplica(replicaWithStats{
-			repl: r,
-			qps:  qps,
+			repl:        r,
+			qps:         qps,
+			requestTime: requestTime,
+			writeBytes:  writeBytes,
 		})
 		return true
 	})
@@ -2459,6 +2474,12 @@ func (s *Store) Capacity(ctx context.Context, useCached bool) (roachpb.StoreCapa
 	capacity.LogicalBytes = logicalBytes
 	capacity.QueriesPerSecond = totalQueriesPerSecond
 	capacity.WritesPerSecond = totalWritesPerSecond
+	// Nodes running older versions don't measure the load along these
+	// dimensions, so only gossip it once every node does.
+	if s.cfg.Settings.Version.IsActive(ctx, clusterversion.LoadBasedRebalancingObjectives) {
+		capacity.RequestTimePerSecond = totalRequestTimePerSecond
+		capacity.WriteBytesPerSecond = totalWriteBytesPerSecond
+	}
 	capacity.BytesPerReplica = roachpb.PercentilesFromData(bytesPerReplica)
 	capacity.WritesPerReplica = roachpb.PercentilesFromData(writesPerReplica)
 	s.recordNewPerSecondStats(totalQueriesPerSecond, totalWritesPerSecond)
@@ -2740,7 +2761,8 @@ type HotReplicaInfo struct {
 }
 
 // HottestReplicas returns the hottest replicas on a store, sorted by their
-// QPS. Only contains ranges for which this store is the leaseholder.
+// QPS regardless of the load-based rebalancing objective. Only contains ranges
+// for which this store is the leaseholder.
 //
 // Note that this uses cached information, so it's cheap but may be slightly
 // out of date.
@@ -2787,7 +2809,7 @@ func (s *Store) ComputeStatsForKeySpan(startKey, endKey roachpb.RKey) (StoreKeyS
 func (s *Store) AllocatorDryRun(ctx context.Context, repl *Replica) (tracing.Recording, error) {
 	ctx, collect, cancel := tracing.ContextWithRecordingSpan(ctx, s.ClusterSettings().Tracer, "allocator dry run")
 	defer cancel()
-	canTransferLease := func(ctx context.Context, repl *Replica) bool { return true }
+	canTransferLease := func(context.Context, leaseholderReplica) bool { return true }
 	_, err := s.replicateQueue.processOneChange(
 		ctx, repl, canTransferLease, true /* dryRun */)
 	if err != nil {
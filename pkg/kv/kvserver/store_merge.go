@@ -70,6 +70,12 @@ func (s *Store) MergeRange(
 		// logic that depends on them.
 		leftRepl.writeStats.resetRequestCounts()
 	}
+	if leftRepl.requestTimeStats != nil {
+		leftRepl.requestTimeStats.resetRequestCounts()
+	}
+	if leftRepl.writeBytesStats != nil {
+		leftRepl.writeBytesStats.resetRequestCounts()
+	}
 
 	// Clear the concurrency manager's lock and txn wait-queues to redirect the
 	// queued transactions to the left-hand replica, if necessary.
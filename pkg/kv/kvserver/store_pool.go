@@ -377,12 +377,34 @@ func NewStorePool(
 	nodeCountFn NodeCountFunc,
 	nodeLivenessFn NodeLivenessFunc,
 	deterministic bool,
+) *StorePool {
+	sp := newStorePool(ambient, st, clock, nodeCountFn, nodeLivenessFn, deterministic)
+	sp.gossip = g
+
+	// Enable redundant callbacks for the store keys because we use these
+	// callbacks as a clock to determine when a store was last updated even if it
+	// hasn't otherwise changed.
+	storeRegex := gossip.MakePrefixPattern(gossip.KeyStorePrefix)
+	g.RegisterCallback(storeRegex, sp.storeGossipUpdate, gossip.Redundant)
+
+	return sp
+}
+
+// newStorePool creates a StorePool that isn't connected to gossip. Its store
+// descriptors have to be supplied through storeDescriptorUpdate, which is what
+// the AllocatorSimulator does.
+func newStorePool(
+	ambient log.AmbientContext,
+	st *cluster.Settings,
+	clock *hlc.Clock,
+	nodeCountFn NodeCountFunc,
+	nodeLivenessFn NodeLivenessFunc,
+	deterministic bool,
 ) *StorePool {
 	sp := &StorePool{
 		AmbientContext: ambient,
 		st:             st,
 		clock:          clock,
-		gossip:         g,
 		nodeCountFn:    nodeCountFn,
 		nodeLivenessFn: nodeLivenessFn,
 		startTime:      clock.PhysicalTime(),
@@ -391,13 +413,6 @@ func NewStorePool(
 	sp.isStoreReadyForRoutineReplicaTransfer = sp.isStoreReadyForRoutineReplicaTransferInternal
 	sp.detailsMu.storeDetails = make(map[roachpb.StoreID]*storeDetail)
 	sp.localitiesMu.nodeLocalities = make(map[roachpb.NodeID]localityWithString)
-
-	// Enable redundant callbacks for the store keys because we use these
-	// callbacks as a clock to determine when a store was last updated even if it
-	// hasn't otherwise changed.
-	storeRegex := gossip.MakePrefixPattern(gossip.KeyStorePrefix)
-	g.RegisterCallback(storeRegex, sp.storeGossipUpdate, gossip.Redundant)
-
 	return sp
 }
 
@@ -444,7 +459,12 @@ func (sp *StorePool) storeGossipUpdate(_ string, content roachpb.Value) {
 		log.Errorf(ctx, "%v", err)
 		return
 	}
+	sp.storeDescriptorUpdate(storeDesc)
+}
 
+// storeDescriptorUpdate records the latest descriptor of a store, as received
+// through gossip.
+func (sp *StorePool) storeDescriptorUpdate(storeDesc roachpb.StoreDescriptor) {
 	sp.detailsMu.Lock()
 	detail := sp.getStoreDetailLocked(storeDesc.StoreID)
 	detail.desc = &storeDesc
@@ -476,6 +496,7 @@ func (sp *StorePool) updateLocalStoreAfterRebalance(
 		detail.desc.Capacity.RangeCount++
 		detail.desc.Capacity.LogicalBytes += rangeUsageInfo.LogicalBytes
 		detail.desc.Capacity.WritesPerSecond += rangeUsageInfo.WritesPerSecond
+		detail.desc.Capacity.WriteBytesPerSecond += rangeUsageInfo.WriteBytesPerSecond
 	case roachpb.REMOVE_VOTER, roachpb.REMOVE_NON_VOTER:
 		detail.desc.Capacity.RangeCount--
 		if detail.desc.Capacity.LogicalBytes <= rangeUsageInfo.LogicalBytes {
@@ -488,6 +509,11 @@ func (sp *StorePool) updateLocalStoreAfterRebalance(
 		} else {
 			detail.desc.Capacity.WritesPerSecond -= rangeUsageInfo.WritesPerSecond
 		}
+		if detail.desc.Capacity.WriteBytesPerSecond <= rangeUsageInfo.WriteBytesPerSecond {
+			detail.desc.Capacity.WriteBytesPerSecond = 0
+		} else {
+			detail.desc.Capacity.WriteBytesPerSecond -= rangeUsageInfo.WriteBytesPerSecond
+		}
 	default:
 		return
 	}
@@ -763,6 +789,14 @@ type StoreList struct {
 	// candidateWritesPerSecond tracks writes-per-second stats for stores that are
 	// eligible to be rebalance targets.
 	candidateWritesPerSecond stat
+
+	// candidateRequestTimePerSecond tracks request-evaluation-time-per-second
+	// stats for stores that are eligible to be rebalance targets.
+	candidateRequestTimePerSecond stat
+
+	// candidateWriteBytesPerSecond tracks write-bytes-per-second stats for
+	// stores that are eligible to be rebalance targets.
+	candidateWriteBytesPerSecond stat
 }
 
 // Generates a new store list based on the passed in descriptors. It will
@@ -777,6 +811,8 @@ func makeStoreList(descriptors []roachpb.StoreDescriptor) StoreList {
 		sl.candidateLogicalBytes.update(float64(desc.Capacity.LogicalBytes))
 		sl.candidateQueriesPerSecond.update(desc.Capacity.QueriesPerSecond)
 		sl.candidateWritesPerSecond.update(desc.Capacity.WritesPerSecond)
+		sl.candidateRequestTimePerSecond.update(desc.Capacity.RequestTimePerSecond)
+		sl.candidateWriteBytesPerSecond.update(desc.Capacity.WriteBytesPerSecond)
 	}
 	return sl
 }
@@ -547,8 +547,10 @@ func TestStorePoolUpdateLocalStore(t *testing.T) {
 	manual.Increment(int64(MinStatsDuration + time.Second))
 	replica.leaseholderStats = rs
 	replica.writeStats = rs
+	replica.requestTimeStats = newReplicaStats(clock, nil)
+	replica.writeBytesStats = newReplicaStats(clock, nil)
 
-	rangeUsageInfo := rangeUsageInfoForRepl(replica)
+	rangeUsageInfo := replica.rangeUsageInfo()
 
 	sp.updateLocalStoreAfterRebalance(roachpb.StoreID(1), rangeUsageInfo, roachpb.ADD_VOTER)
 	desc, ok := sp.getStoreDescriptor(roachpb.StoreID(1))
@@ -654,7 +656,7 @@ func TestStorePoolUpdateLocalStoreBeforeGossip(t *testing.T) {
 	}
 	replica.leaseholderStats = newReplicaStats(store.Clock(), nil)
 
-	rangeUsageInfo := rangeUsageInfoForRepl(replica)
+	rangeUsageInfo := replica.rangeUsageInfo()
 
 	// Update StorePool, which should be a no-op.
 	storeID := roachpb.StoreID(1)
@@ -77,7 +77,7 @@ func makeStoreRebalancerMetrics() StoreRebalancerMetrics {
 // If disabled, rebalancing is done purely based on replica count.
 var LoadBasedRebalancingMode = settings.RegisterEnumSetting(
 	"kv.allocator.load_based_rebalancing",
-	"whether to rebalance based on the distribution of QPS across stores",
+	"whether to rebalance based on the distribution of load across stores",
 	"leases and replicas",
 	map[int64]string{
 		int64(LBRebalancingOff):               "off",
@@ -111,10 +111,11 @@ const (
 	// based on load statistics.
 	LBRebalancingOff LBRebalancingMode = iota
 	// LBRebalancingLeasesOnly means that we rebalance leases based on
-	// store-level QPS imbalances.
+	// store-level load imbalances, as measured along the LBRebalancingObjective.
 	LBRebalancingLeasesOnly
 	// LBRebalancingLeasesAndReplicas means that we rebalance both leases and
-	// replicas based on store-level QPS imbalances.
+	// replicas based on store-level load imbalances, as measured along the
+	// LBRebalancingObjective.
 	LBRebalancingLeasesAndReplicas
 )
 
@@ -134,7 +135,7 @@ type StoreRebalancer struct {
 	st              *cluster.Settings
 	rq              *replicateQueue
 	replRankings    *replicaRankings
-	getRaftStatusFn func(replica *Replica) *raft.Status
+	getRaftStatusFn func(replica leaseholderReplica) *raft.Status
 }
 
 // NewStoreRebalancer creates a StoreRebalancer to work in tandem with the
@@ -151,7 +152,7 @@ func NewStoreRebalancer(
 		st:             st,
 		rq:             rq,
 		replRankings:   replRankings,
-		getRaftStatusFn: func(replica *Replica) *raft.Status {
+		getRaftStatusFn: func(replica leaseholderReplica) *raft.Status {
 			return replica.RaftStatus()
 		},
 	}
@@ -199,22 +200,30 @@ func (sr *StoreRebalancer) Start(ctx context.Context, stopper *stop.Stopper) {
 				continue
 			}
 
+			objective := loadBasedRebalancingObjective(ctx, sr.st)
 			storeList, _, _ := sr.rq.allocator.storePool.getStoreList(storeFilterSuspect)
-			sr.rebalanceStore(ctx, mode, storeList)
+			sr.rebalanceStore(ctx, mode, objective, storeList)
 		}
 	})
 }
 
+// rebalanceStore transfers leases and replicas away from the local store if its
+// load, measured along the given objective, is above the max threshold.
 func (sr *StoreRebalancer) rebalanceStore(
-	ctx context.Context, mode LBRebalancingMode, storeList StoreList,
+	ctx context.Context,
+	mode LBRebalancingMode,
+	objective LBRebalancingObjective,
+	storeList StoreList,
 ) {
-	qpsThresholdFraction := qpsRebalanceThreshold.Get(&sr.st.SV)
+	loadThresholdFraction := objective.rebalanceThreshold(&sr.st.SV)
+	meanLoad := objective.candidateMean(storeList)
+	unit := objective.unit()
 
-	// First check if we should transfer leases away to better balance QPS.
-	qpsMinThreshold := math.Min(storeList.candidateQueriesPerSecond.mean*(1-qpsThresholdFraction),
-		storeList.candidateQueriesPerSecond.mean-minQPSThresholdDifference)
-	qpsMaxThreshold := math.Max(storeList.candidateQueriesPerSecond.mean*(1+qpsThresholdFraction),
-		storeList.candidateQueriesPerSecond.mean+minQPSThresholdDifference)
+	// First check if we should transfer leases away to better balance load.
+	minLoad := math.Min(meanLoad*(1-loadThresholdFraction),
+		meanLoad-objective.minThresholdDifference())
+	maxLoad := math.Max(meanLoad*(1+loadThresholdFraction),
+		meanLoad+objective.minThresholdDifference())
 
 	var localDesc *roachpb.StoreDescriptor
 	for i := range storeList.stores {
@@ -227,9 +236,9 @@ func (sr *StoreRebalancer) rebalanceStore(
 		return
 	}
 
-	if !(localDesc.Capacity.QueriesPerSecond > qpsMaxThreshold) {
-		log.VEventf(ctx, 1, "local QPS %.2f is below max threshold %.2f (mean=%.2f); no rebalancing needed",
-			localDesc.Capacity.QueriesPerSecond, qpsMaxThreshold, storeList.candidateQueriesPerSecond.mean)
+	if !(objective.storeLoad(localDesc.Capacity) > maxLoad) {
+		log.VEventf(ctx, 1, "local load %.2f %s is below max threshold %.2f (mean=%.2f); no rebalancing needed",
+			objective.storeLoad(localDesc.Capacity), unit, maxLoad, meanLoad)
 		return
 	}
 
@@ -237,20 +246,23 @@ func (sr *StoreRebalancer) rebalanceStore(
 	storeMap := storeListToMap(storeList)
 
 	log.Infof(ctx,
-		"considering load-based lease transfers for s%d with %.2f qps (mean=%.2f, upperThreshold=%.2f)",
-		localDesc.StoreID, localDesc.Capacity.QueriesPerSecond, storeList.candidateQueriesPerSecond.mean, qpsMaxThreshold)
+		"considering load-based lease transfers for s%d with %.2f %s (mean=%.2f, upperThreshold=%.2f)",
+		localDesc.StoreID, objective.storeLoad(localDesc.Capacity), unit, meanLoad, maxLoad)
 
-	hottestRanges := sr.replRankings.topQPS()
-	for localDesc.Capacity.QueriesPerSecond > qpsMaxThreshold {
+	hottestRanges := sr.replRankings.topLoad()
+	// Transferring a lease only moves the load incurred by the leaseholder, so
+	// skip straight to replica rebalancing if every replica incurs the load.
+	for objective.movesWithLease() && objective.storeLoad(localDesc.Capacity) > maxLoad {
 		replWithStats, target, considerForRebalance := sr.chooseLeaseToTransfer(
-			ctx, &hottestRanges, localDesc, storeList, storeMap, qpsMinThreshold, qpsMaxThreshold)
+			ctx, &hottestRanges, localDesc, storeList, storeMap, objective, minLoad, maxLoad)
 		replicasToMaybeRebalance = append(replicasToMaybeRebalance, considerForRebalance...)
 		if replWithStats.repl == nil {
 			break
 		}
 
-		log.VEventf(ctx, 1, "transferring r%d (%.2f qps) to s%d to better balance load",
-			replWithStats.repl.RangeID, replWithStats.qps, target.StoreID)
+		replLoad := objective.replicaLoad(replWithStats)
+		log.VEventf(ctx, 1, "transferring r%d (%.2f %s) to s%d to better balance load",
+			replWithStats.repl.GetRangeID(), replLoad, unit, target.StoreID)
 		timeout := sr.rq.processTimeoutFunc(sr.st, replWithStats.repl)
 		if err := contextutil.RunWithTimeout(ctx, "transfer lease", timeout, func(ctx context.Context) error {
 			return sr.rq.transferLease(ctx, replWithStats.repl, target, replWithStats.qps)
@@ -264,57 +276,60 @@ func (sr *StoreRebalancer) rebalanceStore(
 		// additional transfers are needed we'll be making the decisions with more
 		// up-to-date info. The StorePool copies are updated by transferLease.
 		localDesc.Capacity.LeaseCount--
-		localDesc.Capacity.QueriesPerSecond -= replWithStats.qps
+		objective.adjustStoreLoad(&localDesc.Capacity, -replLoad)
 		if otherDesc := storeMap[target.StoreID]; otherDesc != nil {
 			otherDesc.Capacity.LeaseCount++
-			otherDesc.Capacity.QueriesPerSecond += replWithStats.qps
+			objective.adjustStoreLoad(&otherDesc.Capacity, replLoad)
 		}
 	}
 
-	if !(localDesc.Capacity.QueriesPerSecond > qpsMaxThreshold) {
+	if !(objective.storeLoad(localDesc.Capacity) > maxLoad) {
 		log.Infof(ctx,
-			"load-based lease transfers successfully brought s%d down to %.2f qps (mean=%.2f, upperThreshold=%.2f)",
-			localDesc.StoreID, localDesc.Capacity.QueriesPerSecond, storeList.candidateQueriesPerSecond.mean, qpsMaxThreshold)
+			"load-based lease transfers successfully brought s%d down to %.2f %s (mean=%.2f, upperThreshold=%.2f)",
+			localDesc.StoreID, objective.storeLoad(localDesc.Capacity), unit, meanLoad, maxLoad)
 		return
 	}
 
 	if mode != LBRebalancingLeasesAndReplicas {
 		log.Infof(ctx,
-			"ran out of leases worth transferring and qps (%.2f) is still above desired threshold (%.2f)",
-			localDesc.Capacity.QueriesPerSecond, qpsMaxThreshold)
+			"ran out of leases worth transferring and load (%.2f %s) is still above desired threshold (%.2f)",
+			objective.storeLoad(localDesc.Capacity), unit, maxLoad)
 		return
 	}
 	log.Infof(ctx,
-		"ran out of leases worth transferring and qps (%.2f) is still above desired threshold (%.2f); considering load-based replica rebalances",
-		localDesc.Capacity.QueriesPerSecond, qpsMaxThreshold)
+		"ran out of leases worth transferring and load (%.2f %s) is still above desired threshold (%.2f); considering load-based replica rebalances",
+		objective.storeLoad(localDesc.Capacity), unit, maxLoad)
 
 	// Re-combine replicasToMaybeRebalance with what remains of hottestRanges so
 	// that we'll reconsider them for replica rebalancing.
 	replicasToMaybeRebalance = append(replicasToMaybeRebalance, hottestRanges...)
 
-	for localDesc.Capacity.QueriesPerSecond > qpsMaxThreshold {
+	for objective.storeLoad(localDesc.Capacity) > maxLoad {
 		replWithStats, voterTargets, nonVoterTargets := sr.chooseRangeToRebalance(
 			ctx,
 			&replicasToMaybeRebalance,
 			localDesc,
 			storeList,
 			storeMap,
-			qpsMinThreshold,
-			qpsMaxThreshold)
+			objective,
+			minLoad,
+			maxLoad)
 		if replWithStats.repl == nil {
 			log.Infof(ctx,
-				"ran out of replicas worth transferring and qps (%.2f) is still above desired threshold (%.2f); will check again soon",
-				localDesc.Capacity.QueriesPerSecond, qpsMaxThreshold)
+				"ran out of replicas worth transferring and load (%.2f %s) is still above desired threshold (%.2f); will check again soon",
+				objective.storeLoad(localDesc.Capacity), unit, maxLoad)
 			return
 		}
 
+		replLoad := objective.replicaLoad(replWithStats)
 		descBeforeRebalance := replWithStats.repl.Desc()
 		log.VEventf(
 			ctx,
 			1,
-			"rebalancing r%d (%.2f qps) to better balance load: voters from %v to %v; non-voters from %v to %v",
-			replWithStats.repl.RangeID,
-			replWithStats.qps,
+			"rebalancing r%d (%.2f %s) to better balance load: voters from %v to %v; non-voters from %v to %v",
+			replWithStats.repl.GetRangeID(),
+			replLoad,
+			unit,
 			descBeforeRebalance.Replicas().Voters(),
 			voterTargets,
 			descBeforeRebalance.Replicas().NonVoters(),
@@ -323,7 +338,7 @@ func (sr *StoreRebalancer) rebalanceStore(
 
 		timeout := sr.rq.processTimeoutFunc(sr.st, replWithStats.repl)
 		if err := contextutil.RunWithTimeout(ctx, "relocate range", timeout, func(ctx context.Context) error {
-			return sr.rq.store.AdminRelocateRange(ctx, *descBeforeRebalance, voterTargets, nonVoterTargets)
+			return replWithStats.repl.relocateRange(ctx, *descBeforeRebalance, voterTargets, nonVoterTargets)
 		}); err != nil {
 			log.Errorf(ctx, "unable to relocate range to %v: %v", voterTargets, err)
 			continue
@@ -341,24 +356,31 @@ func (sr *StoreRebalancer) rebalanceStore(
 		for i := range replicasBeforeRebalance {
 			if storeDesc := storeMap[replicasBeforeRebalance[i].StoreID]; storeDesc != nil {
 				storeDesc.Capacity.RangeCount--
+				if !objective.movesWithLease() {
+					objective.adjustStoreLoad(&storeDesc.Capacity, -replLoad)
+				}
 			}
 		}
 		localDesc.Capacity.LeaseCount--
-		localDesc.Capacity.QueriesPerSecond -= replWithStats.qps
+		if objective.movesWithLease() {
+			objective.adjustStoreLoad(&localDesc.Capacity, -replLoad)
+		}
 		for i := range voterTargets {
 			if storeDesc := storeMap[voterTargets[i].StoreID]; storeDesc != nil {
 				storeDesc.Capacity.RangeCount++
 				if i == 0 {
 					storeDesc.Capacity.LeaseCount++
-					storeDesc.Capacity.QueriesPerSecond += replWithStats.qps
+				}
+				if i == 0 || !objective.movesWithLease() {
+					objective.adjustStoreLoad(&storeDesc.Capacity, replLoad)
 				}
 			}
 		}
 	}
 
 	log.Infof(ctx,
-		"load-based replica transfers successfully brought s%d down to %.2f qps (mean=%.2f, upperThreshold=%.2f)",
-		localDesc.StoreID, localDesc.Capacity.QueriesPerSecond, storeList.candidateQueriesPerSecond.mean, qpsMaxThreshold)
+		"load-based replica transfers successfully brought s%d down to %.2f %s (mean=%.2f, upperThreshold=%.2f)",
+		localDesc.StoreID, objective.storeLoad(localDesc.Capacity), unit, meanLoad, maxLoad)
 }
 
 // TODO(a-robinson): Should we take the number of leases on each store into
@@ -369,8 +391,9 @@ func (sr *StoreRebalancer) chooseLeaseToTransfer(
 	localDesc *roachpb.StoreDescriptor,
 	storeList StoreList,
 	storeMap map[roachpb.StoreID]*roachpb.StoreDescriptor,
-	minQPS float64,
-	maxQPS float64,
+	objective LBRebalancingObjective,
+	minLoad float64,
+	maxLoad float64,
 ) (replicaWithStats, roachpb.ReplicaDescriptor, []replicaWithStats) {
 	var considerForRebalance []replicaWithStats
 	now := sr.rq.store.Clock().NowAsClockTimestamp()
@@ -386,39 +409,41 @@ func (sr *StoreRebalancer) chooseLeaseToTransfer(
 			return replicaWithStats{}, roachpb.ReplicaDescriptor{}, considerForRebalance
 		}
 
-		if shouldNotMoveAway(ctx, replWithStats, localDesc, now, minQPS) {
+		if shouldNotMoveAway(ctx, replWithStats, localDesc, now, objective, minLoad) {
 			continue
 		}
 
-		// Don't bother moving leases whose QPS is below some small fraction of the
-		// store's QPS (unless the store has extra leases to spare anyway). It's
-		// just unnecessary churn with no benefit to move leases responsible for,
-		// for example, 1 qps on a store with 5000 qps.
-		const minQPSFraction = .001
-		if replWithStats.qps < localDesc.Capacity.QueriesPerSecond*minQPSFraction &&
+		// Don't bother moving leases whose load is below some small fraction of
+		// the store's load (unless the store has extra leases to spare anyway).
+		// It's just unnecessary churn with no benefit to move leases responsible
+		// for, for example, 1 qps on a store with 5000 qps.
+		const minLoadFraction = .001
+		replLoad := objective.replicaLoad(replWithStats)
+		storeLoad := objective.storeLoad(localDesc.Capacity)
+		if replLoad < storeLoad*minLoadFraction &&
 			float64(localDesc.Capacity.LeaseCount) <= storeList.candidateLeases.mean {
-			log.VEventf(ctx, 5, "r%d's %.2f qps is too little to matter relative to s%d's %.2f total qps",
-				replWithStats.repl.RangeID, replWithStats.qps, localDesc.StoreID, localDesc.Capacity.QueriesPerSecond)
+			log.VEventf(ctx, 5, "r%d's %.2f %s is too little to matter relative to s%d's %.2f total",
+				replWithStats.repl.GetRangeID(), replLoad, objective.unit(), localDesc.StoreID, storeLoad)
 			continue
 		}
 
 		desc, conf := replWithStats.repl.DescAndSpanConfig()
-		log.VEventf(ctx, 3, "considering lease transfer for r%d with %.2f qps",
-			desc.RangeID, replWithStats.qps)
+		log.VEventf(ctx, 3, "considering lease transfer for r%d with %.2f %s",
+			desc.RangeID, replLoad, objective.unit())
 
-		// Check all the other voting replicas in order of increasing qps.
+		// Check all the other voting replicas in order of increasing load.
 		// Learners or non-voters aren't allowed to become leaseholders or raft
 		// leaders, so only consider the `Voter` replicas.
 		candidates := desc.Replicas().DeepCopy().VoterDescriptors()
 		sort.Slice(candidates, func(i, j int) bool {
-			var iQPS, jQPS float64
+			var iLoad, jLoad float64
 			if desc := storeMap[candidates[i].StoreID]; desc != nil {
-				iQPS = desc.Capacity.QueriesPerSecond
+				iLoad = objective.storeLoad(desc.Capacity)
 			}
 			if desc := storeMap[candidates[j].StoreID]; desc != nil {
-				jQPS = desc.Capacity.QueriesPerSecond
+				jLoad = objective.storeLoad(desc.Capacity)
 			}
-			return iQPS < jQPS
+			return iLoad < jLoad
 		})
 
 		var raftStatus *raft.Status
@@ -437,8 +462,10 @@ func (sr *StoreRebalancer) chooseLeaseToTransfer(
 				continue
 			}
 
-			meanQPS := storeList.candidateQueriesPerSecond.mean
-			if sr.shouldNotMoveTo(ctx, storeMap, replWithStats, candidate.StoreID, meanQPS, minQPS, maxQPS) {
+			meanLoad := objective.candidateMean(storeList)
+			if sr.shouldNotMoveTo(
+				ctx, storeMap, replWithStats, candidate.StoreID, objective, meanLoad, minLoad, maxLoad,
+			) {
 				continue
 			}
 
@@ -465,7 +492,7 @@ func (sr *StoreRebalancer) chooseLeaseToTransfer(
 				*localDesc,
 				candidate.StoreID,
 				candidates,
-				replWithStats.repl.leaseholderStats,
+				replWithStats.repl.getLeaseholderStats(),
 			) {
 				log.VEventf(ctx, 3, "r%d is on s%d due to follow-the-workload; skipping",
 					desc.RangeID, localDesc.StoreID)
@@ -482,9 +509,10 @@ func (sr *StoreRebalancer) chooseLeaseToTransfer(
 }
 
 // rangeRebalanceContext represents a snapshot of a range's state during the
-// StoreRebalancer's attempt to rebalance it based on QPS.
+// StoreRebalancer's attempt to rebalance it based on load.
 type rangeRebalanceContext struct {
 	replWithStats                         replicaWithStats
+	objective                             LBRebalancingObjective
 	rangeDesc                             *roachpb.RangeDescriptor
 	conf                                  roachpb.SpanConfig
 	clusterNodes                          int
@@ -508,8 +536,9 @@ func (sr *StoreRebalancer) chooseRangeToRebalance(
 	localDesc *roachpb.StoreDescriptor,
 	storeList StoreList,
 	storeMap map[roachpb.StoreID]*roachpb.StoreDescriptor,
-	minQPS float64,
-	maxQPS float64,
+	objective LBRebalancingObjective,
+	minLoad float64,
+	maxLoad float64,
 ) (replWithStats replicaWithStats, voterTargets, nonVoterTargets []roachpb.ReplicationTarget) {
 	now := sr.rq.store.Clock().NowAsClockTimestamp()
 	for {
@@ -523,30 +552,32 @@ func (sr *StoreRebalancer) chooseRangeToRebalance(
 			return replicaWithStats{}, nil, nil
 		}
 
-		if shouldNotMoveAway(ctx, replWithStats, localDesc, now, minQPS) {
+		if shouldNotMoveAway(ctx, replWithStats, localDesc, now, objective, minLoad) {
 			continue
 		}
 
-		// Don't bother moving ranges whose QPS is below some small fraction of the
-		// store's QPS (unless the store has extra ranges to spare anyway). It's
-		// just unnecessary churn with no benefit to move ranges responsible for,
-		// for example, 1 qps on a store with 5000 qps.
-		const minQPSFraction = .001
-		if replWithStats.qps < localDesc.Capacity.QueriesPerSecond*minQPSFraction {
+		// Don't bother moving ranges whose load is below some small fraction of
+		// the store's load (unless the store has extra ranges to spare anyway).
+		// It's just unnecessary churn with no benefit to move ranges responsible
+		// for, for example, 1 qps on a store with 5000 qps.
+		const minLoadFraction = .001
+		replLoad := objective.replicaLoad(replWithStats)
+		if storeLoad := objective.storeLoad(localDesc.Capacity); replLoad < storeLoad*minLoadFraction {
 			log.VEventf(
 				ctx,
 				5,
-				"r%d's %.2f qps is too little to matter relative to s%d's %.2f total qps",
-				replWithStats.repl.RangeID,
-				replWithStats.qps,
+				"r%d's %.2f %s is too little to matter relative to s%d's %.2f total",
+				replWithStats.repl.GetRangeID(),
+				replLoad,
+				objective.unit(),
 				localDesc.StoreID,
-				localDesc.Capacity.QueriesPerSecond,
+				storeLoad,
 			)
 			continue
 		}
 
-		log.VEventf(ctx, 3, "considering replica rebalance for r%d with %.2f qps",
-			replWithStats.repl.GetRangeID(), replWithStats.qps)
+		log.VEventf(ctx, 3, "considering replica rebalance for r%d with %.2f %s",
+			replWithStats.repl.GetRangeID(), replLoad, objective.unit())
 		rangeDesc, conf := replWithStats.repl.DescAndSpanConfig()
 		clusterNodes := sr.rq.allocator.storePool.ClusterNodeCount()
 		numDesiredVoters := GetNeededVoters(conf.GetNumVoters(), clusterNodes)
@@ -563,14 +594,15 @@ func (sr *StoreRebalancer) chooseRangeToRebalance(
 
 		rebalanceCtx := rangeRebalanceContext{
 			replWithStats:       replWithStats,
+			objective:           objective,
 			rangeDesc:           rangeDesc,
 			conf:                conf,
 			clusterNodes:        clusterNodes,
 			numDesiredVoters:    numDesiredVoters,
 			numDesiredNonVoters: numDesiredNonVoters,
 		}
-		targetVoterRepls, targetNonVoterRepls := sr.getRebalanceCandidatesBasedOnQPS(
-			ctx, rebalanceCtx, localDesc, storeMap, storeList, minQPS, maxQPS,
+		targetVoterRepls, targetNonVoterRepls := sr.getRebalanceCandidatesBasedOnLoad(
+			ctx, rebalanceCtx, localDesc, storeMap, storeList, minLoad, maxLoad,
 		)
 
 		// If we couldn't find enough valid targets, forget about this range.
@@ -578,7 +610,7 @@ func (sr *StoreRebalancer) chooseRangeToRebalance(
 		// TODO(a-robinson): Support more incremental improvements -- move what we
 		// can if it makes things better even if it isn't great. For example,
 		// moving one of the other existing replicas that's on a store with less
-		// qps than the max threshold but above the mean would help in certain
+		// load than the max threshold but above the mean would help in certain
 		// locality configurations.
 		if len(targetVoterRepls) < rebalanceCtx.numDesiredVoters {
 			log.VEventf(ctx, 3, "couldn't find enough voter rebalance targets for r%d (%d/%d)",
@@ -593,7 +625,7 @@ func (sr *StoreRebalancer) chooseRangeToRebalance(
 
 		// If the new set of replicas has lower diversity scores than the existing
 		// set, we don't continue with the rebalance. since we want to ensure we
-		// don't hurt locality diversity just to improve QPS.
+		// don't hurt locality diversity just to improve load balance.
 		//
 		// 1. Ensure that diversity among voting replicas is not hurt by this
 		// rebalancing decision.
@@ -618,10 +650,10 @@ func (sr *StoreRebalancer) chooseRangeToRebalance(
 			continue
 		}
 
-		// Pick the voter with the least QPS to be leaseholder;
+		// Pick the voter with the least load to be leaseholder;
 		// RelocateRange transfers the lease to the first provided target.
 		newLeaseIdx := 0
-		newLeaseQPS := math.MaxFloat64
+		newLeaseLoad := math.MaxFloat64
 		var raftStatus *raft.Status
 		for i := 0; i < len(targetVoterRepls); i++ {
 			// Ensure we don't transfer the lease to an existing replica that is behind
@@ -636,9 +668,9 @@ func (sr *StoreRebalancer) chooseRangeToRebalance(
 			}
 
 			storeDesc, ok := storeMap[targetVoterRepls[i].StoreID]
-			if ok && storeDesc.Capacity.QueriesPerSecond < newLeaseQPS {
+			if ok && objective.storeLoad(storeDesc.Capacity) < newLeaseLoad {
 				newLeaseIdx = i
-				newLeaseQPS = storeDesc.Capacity.QueriesPerSecond
+				newLeaseLoad = objective.storeLoad(storeDesc.Capacity)
 			}
 		}
 		targetVoterRepls[0], targetVoterRepls[newLeaseIdx] = targetVoterRepls[newLeaseIdx], targetVoterRepls[0]
@@ -682,25 +714,27 @@ func (sr *StoreRebalancer) worsensDiversity(
 	return false
 }
 
-// getRebalanceCandidatesBasedOnQPS returns a list of rebalance targets for
+// getRebalanceCandidatesBasedOnLoad returns a list of rebalance targets for
 // voting and non-voting replicas on the range that match the relevant
-// constraints on the range and would further the goal of balancing the QPS on
-// the stores in this cluster. In case there aren't enough stores that meet the
-// constraints and are valid rebalance candidates based on QPS, the list of
-// targets returned may contain fewer-than-required replicas.
+// constraints on the range and would further the goal of balancing the load,
+// measured along the rebalancing objective, on the stores in this cluster. In
+// case there aren't enough stores that meet the constraints and are valid
+// rebalance candidates based on load, the list of targets returned may contain
+// fewer-than-required replicas.
 //
 // NB: `localStoreDesc` is expected to be the leaseholder of the range being
 // operated on.
-func (sr *StoreRebalancer) getRebalanceCandidatesBasedOnQPS(
+func (sr *StoreRebalancer) getRebalanceCandidatesBasedOnLoad(
 	ctx context.Context,
 	rebalanceCtx rangeRebalanceContext,
 	localStoreDesc *roachpb.StoreDescriptor,
 	storeMap map[roachpb.StoreID]*roachpb.StoreDescriptor,
 	storeList StoreList,
-	minQPS, maxQPS float64,
+	minLoad, maxLoad float64,
 ) (finalVoterTargets, finalNonVoterTargets []roachpb.ReplicaDescriptor) {
 	options := sr.rq.allocator.scorerOptions()
-	options.qpsRebalanceThreshold = qpsRebalanceThreshold.Get(&sr.st.SV)
+	options.loadObjective = rebalanceCtx.objective
+	options.loadRebalanceThreshold = rebalanceCtx.objective.rebalanceThreshold(&sr.st.SV)
 
 	// Decide which voting / non-voting replicas we want to keep around and find
 	// rebalance targets for the rest.
@@ -710,7 +744,7 @@ func (sr *StoreRebalancer) getRebalanceCandidatesBasedOnQPS(
 		nil, /* replsToExclude */
 		localStoreDesc,
 		storeMap,
-		maxQPS,
+		maxLoad,
 		voterTarget,
 	)
 	finalVoterTargets = sr.pickRemainingRepls(
@@ -721,7 +755,7 @@ func (sr *StoreRebalancer) getRebalanceCandidatesBasedOnQPS(
 		storeMap,
 		storeList,
 		options,
-		minQPS, maxQPS,
+		minLoad, maxLoad,
 		voterTarget,
 	)
 
@@ -734,7 +768,7 @@ func (sr *StoreRebalancer) getRebalanceCandidatesBasedOnQPS(
 		finalVoterTargets,
 		localStoreDesc,
 		storeMap,
-		maxQPS,
+		maxLoad,
 		nonVoterTarget,
 	)
 	finalNonVoterTargets = sr.pickRemainingRepls(
@@ -745,8 +779,8 @@ func (sr *StoreRebalancer) getRebalanceCandidatesBasedOnQPS(
 		storeMap,
 		storeList,
 		options,
-		minQPS,
-		maxQPS,
+		minLoad,
+		maxLoad,
 		nonVoterTarget,
 	)
 
@@ -766,7 +800,7 @@ func (sr *StoreRebalancer) pickRemainingRepls(
 	storeMap map[roachpb.StoreID]*roachpb.StoreDescriptor,
 	storeList StoreList,
 	options scorerOptions,
-	minQPS, maxQPS float64,
+	minLoad, maxLoad float64,
 	targetType targetReplicaType,
 ) []roachpb.ReplicaDescriptor {
 	// Alias the slice that corresponds to the set of replicas that is being
@@ -807,15 +841,16 @@ func (sr *StoreRebalancer) pickRemainingRepls(
 			break
 		}
 
-		meanQPS := storeList.candidateQueriesPerSecond.mean
+		meanLoad := rebalanceCtx.objective.candidateMean(storeList)
 		if sr.shouldNotMoveTo(
 			ctx,
 			storeMap,
 			rebalanceCtx.replWithStats,
 			target.StoreID,
-			meanQPS,
-			minQPS,
-			maxQPS,
+			rebalanceCtx.objective,
+			meanLoad,
+			minLoad,
+			maxLoad,
 		) {
 			// NB: If the target store returned by the allocator is not fit to
 			// receive a new replica due to balancing reasons, there is no point
@@ -841,7 +876,7 @@ func (sr *StoreRebalancer) pickReplsToKeep(
 	replsToExclude []roachpb.ReplicaDescriptor,
 	localStoreDesc *roachpb.StoreDescriptor,
 	storeMap map[roachpb.StoreID]*roachpb.StoreDescriptor,
-	maxQPS float64,
+	maxLoad float64,
 	targetType targetReplicaType,
 ) (partialTargetRepls []roachpb.ReplicaDescriptor) {
 	shouldExclude := func(repl roachpb.ReplicaDescriptor) bool {
@@ -870,18 +905,19 @@ func (sr *StoreRebalancer) pickReplsToKeep(
 			continue
 		}
 
-		// Keep the replica in the range if we don't know its QPS or if its QPS is
-		// below the upper threshold. Punishing stores not in our store map could
-		// cause mass evictions if the storePool gets out of sync.
+		// Keep the replica in the range if we don't know its load or if its load
+		// is below the upper threshold. Punishing stores not in our store map
+		// could cause mass evictions if the storePool gets out of sync.
 		storeDesc, ok := storeMap[currentReplsForType[i].StoreID]
-		if !ok || storeDesc.Capacity.QueriesPerSecond < maxQPS {
+		if !ok || rebalanceCtx.objective.storeLoad(storeDesc.Capacity) < maxLoad {
 			if log.V(3) {
 				var reason redact.RedactableString
 				if ok {
 					reason = redact.Sprintf(
-						" (qps %.2f vs max %.2f)",
-						storeDesc.Capacity.QueriesPerSecond,
-						maxQPS,
+						" (%s %.2f vs max %.2f)",
+						rebalanceCtx.objective.unit(),
+						rebalanceCtx.objective.storeLoad(storeDesc.Capacity),
+						maxLoad,
 					)
 				}
 				log.VEventf(
@@ -907,15 +943,17 @@ func shouldNotMoveAway(
 	replWithStats replicaWithStats,
 	localDesc *roachpb.StoreDescriptor,
 	now hlc.ClockTimestamp,
-	minQPS float64,
+	objective LBRebalancingObjective,
+	minLoad float64,
 ) bool {
 	if !replWithStats.repl.OwnsValidLease(ctx, now) {
-		log.VEventf(ctx, 3, "store doesn't own the lease for r%d", replWithStats.repl.RangeID)
+		log.VEventf(ctx, 3, "store doesn't own the lease for r%d", replWithStats.repl.GetRangeID())
 		return true
 	}
-	if localDesc.Capacity.QueriesPerSecond-replWithStats.qps < minQPS {
-		log.VEventf(ctx, 3, "moving r%d's %.2f qps would bring s%d below the min threshold (%.2f)",
-			replWithStats.repl.RangeID, replWithStats.qps, localDesc.StoreID, minQPS)
+	replLoad := objective.replicaLoad(replWithStats)
+	if objective.storeLoad(localDesc.Capacity)-replLoad < minLoad {
+		log.VEventf(ctx, 3, "moving r%d's %.2f %s would bring s%d below the min threshold (%.2f)",
+			replWithStats.repl.GetRangeID(), replLoad, objective.unit(), localDesc.StoreID, minLoad)
 		return true
 	}
 	return false
@@ -926,9 +964,10 @@ func (sr *StoreRebalancer) shouldNotMoveTo(
 	storeMap map[roachpb.StoreID]*roachpb.StoreDescriptor,
 	replWithStats replicaWithStats,
 	candidateStoreID roachpb.StoreID,
-	meanQPS float64,
-	minQPS float64,
-	maxQPS float64,
+	objective LBRebalancingObjective,
+	meanLoad float64,
+	minLoad float64,
+	maxLoad float64,
 ) bool {
 	candidateStore, ok := storeMap[candidateStoreID]
 	if !ok {
@@ -936,18 +975,20 @@ func (sr *StoreRebalancer) shouldNotMoveTo(
 		return true
 	}
 
-	newCandidateQPS := candidateStore.Capacity.QueriesPerSecond + replWithStats.qps
-	if candidateStore.Capacity.QueriesPerSecond < minQPS {
-		if newCandidateQPS > maxQPS {
+	replLoad := objective.replicaLoad(replWithStats)
+	candidateLoad := objective.storeLoad(candidateStore.Capacity)
+	newCandidateLoad := candidateLoad + replLoad
+	if candidateLoad < minLoad {
+		if newCandidateLoad > maxLoad {
 			log.VEventf(ctx, 3,
-				"r%d's %.2f qps would push s%d over the max threshold (%.2f) with %.2f qps afterwards",
-				replWithStats.repl.RangeID, replWithStats.qps, candidateStoreID, maxQPS, newCandidateQPS)
+				"r%d's %.2f %s would push s%d over the max threshold (%.2f) with %.2f afterwards",
+				replWithStats.repl.GetRangeID(), replLoad, objective.unit(), candidateStoreID, maxLoad, newCandidateLoad)
 			return true
 		}
-	} else if newCandidateQPS > meanQPS {
+	} else if newCandidateLoad > meanLoad {
 		log.VEventf(ctx, 3,
-			"r%d's %.2f qps would push s%d over the mean (%.2f) with %.2f qps afterwards",
-			replWithStats.repl.RangeID, replWithStats.qps, candidateStoreID, meanQPS, newCandidateQPS)
+			"r%d's %.2f %s would push s%d over the mean (%.2f) with %.2f afterwards",
+			replWithStats.repl.GetRangeID(), replLoad, objective.unit(), candidateStoreID, meanLoad, newCandidateLoad)
 		return true
 	}
 
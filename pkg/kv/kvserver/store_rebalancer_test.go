@@ -14,8 +14,11 @@ import (
 	"context"
 	"reflect"
 	"testing"
+	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/testutils/gossiputil"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
@@ -70,14 +73,29 @@ var (
 	}
 )
 
+// requestTimeImbalancedStores specifies a set of stores that are balanced in
+// terms of QPS, but where s5 is under-utilized in terms of request evaluation
+// time, s2-s4 are in the middle, and s1 is over-utilized.
+var requestTimeImbalancedStores = func() []*roachpb.StoreDescriptor {
+	var stores []*roachpb.StoreDescriptor
+	for _, desc := range noLocalityStores {
+		desc := *desc
+		desc.Capacity.RequestTimePerSecond = desc.Capacity.QueriesPerSecond * float64(time.Millisecond)
+		desc.Capacity.QueriesPerSecond = 1000
+		stores = append(stores, &desc)
+	}
+	return stores
+}()
+
 type testRange struct {
 	// The first storeID in the list will be the leaseholder.
 	voters, nonVoters []roachpb.StoreID
 	qps               float64
+	requestTime       float64
 }
 
 func loadRanges(rr *replicaRankings, s *Store, ranges []testRange) {
-	acc := rr.newAccumulator()
+	acc := rr.newAccumulator(LBRebalancingQueries)
 	for _, r := range ranges {
 		repl := &Replica{store: s}
 		repl.mu.state.Desc = &roachpb.RangeDescriptor{}
@@ -107,9 +125,12 @@ func loadRanges(rr *replicaRankings, s *Store, ranges []testRange) {
 		repl.mu.state.Stats = &enginepb.MVCCStats{}
 		repl.leaseholderStats = newReplicaStats(s.Clock(), nil)
 		repl.writeStats = newReplicaStats(s.Clock(), nil)
+		repl.requestTimeStats = newReplicaStats(s.Clock(), nil)
+		repl.writeBytesStats = newReplicaStats(s.Clock(), nil)
 		acc.addReplica(replicaWithStats{
-			repl: repl,
-			qps:  r.qps,
+			repl:        repl,
+			qps:         r.qps,
+			requestTime: r.requestTime,
 		})
 	}
 	rr.update(acc)
@@ -145,7 +166,7 @@ func TestChooseLeaseToTransfer(t *testing.T) {
 	// Rather than trying to populate every Replica with a real raft group in
 	// order to pass replicaIsBehind checks, fake out the function for getting
 	// raft status with one that always returns all replicas as up to date.
-	sr.getRaftStatusFn = func(r *Replica) *raft.Status {
+	sr.getRaftStatusFn = func(r leaseholderReplica) *raft.Status {
 		status := &raft.Status{
 			Progress: make(map[uint64]tracker.Progress),
 		}
@@ -190,9 +211,9 @@ func TestChooseLeaseToTransfer(t *testing.T) {
 
 	for _, tc := range testCases {
 		loadRanges(rr, s, []testRange{{voters: tc.storeIDs, qps: tc.qps}})
-		hottestRanges := rr.topQPS()
+		hottestRanges := rr.topLoad()
 		_, target, _ := sr.chooseLeaseToTransfer(
-			ctx, &hottestRanges, &localDesc, storeList, storeMap, minQPS, maxQPS)
+			ctx, &hottestRanges, &localDesc, storeList, storeMap, LBRebalancingQueries, minQPS, maxQPS)
 		if target.StoreID != tc.expectTarget {
 			t.Errorf("got target store %d for range with replicas %v and %f qps; want %d",
 				target.StoreID, tc.storeIDs, tc.qps, tc.expectTarget)
@@ -200,6 +221,90 @@ func TestChooseLeaseToTransfer(t *testing.T) {
 	}
 }
 
+// TestChooseLeaseToTransferObjective verifies that lease transfers balance
+// the load dimension selected by the rebalancing objective.
+func TestChooseLeaseToTransferObjective(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	stopper, g, _, a, _ := createTestAllocator(10, false /* deterministic */)
+	defer stopper.Stop(ctx)
+	gossiputil.NewStoreGossiper(g).GossipStores(requestTimeImbalancedStores, t)
+	storeList, _, _ := a.storePool.getStoreList(storeFilterThrottled)
+	storeMap := storeListToMap(storeList)
+
+	localDesc := *requestTimeImbalancedStores[0]
+	cfg := TestStoreConfig(nil)
+	cfg.Gossip = g
+	s := createTestStoreWithoutStart(t, stopper, testStoreOpts{createSystemRanges: true}, &cfg)
+	s.Ident = &roachpb.StoreIdent{StoreID: localDesc.StoreID}
+	rq := newReplicateQueue(s, a)
+	rr := newReplicaRankings()
+
+	sr := NewStoreRebalancer(cfg.AmbientCtx, cfg.Settings, rq, rr)
+	sr.getRaftStatusFn = func(r leaseholderReplica) *raft.Status {
+		status := &raft.Status{
+			Progress: make(map[uint64]tracker.Progress),
+		}
+		status.Lead = uint64(r.ReplicaID())
+		status.Commit = 1
+		for _, replica := range r.Desc().InternalReplicas {
+			status.Progress[uint64(replica.ReplicaID)] = tracker.Progress{
+				Match: 1,
+				State: tracker.StateReplicate,
+			}
+		}
+		return status
+	}
+
+	testCases := []struct {
+		objective        LBRebalancingObjective
+		minLoad, maxLoad float64
+		expectTarget     roachpb.StoreID
+	}{
+		// The stores are balanced in terms of QPS, so moving the lease to s5 would
+		// push it over the mean.
+		{LBRebalancingQueries, 800, 1200, 0},
+		// s5 is underfull in terms of request evaluation time, so it can take the lease.
+		{LBRebalancingRequestTime, float64(800 * time.Millisecond), float64(1200 * time.Millisecond), 5},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.objective.String(), func(t *testing.T) {
+			loadRanges(rr, s, []testRange{{
+				voters:      []roachpb.StoreID{1, 5},
+				qps:         100,
+				requestTime: float64(100 * time.Millisecond),
+			}})
+			hottestRanges := rr.topLoad()
+			_, target, _ := sr.chooseLeaseToTransfer(
+				ctx, &hottestRanges, &localDesc, storeList, storeMap, tc.objective, tc.minLoad, tc.maxLoad)
+			require.Equal(t, tc.expectTarget, target.StoreID)
+		})
+	}
+}
+
+// TestLoadBasedRebalancingObjectiveVersionGate verifies that load-based
+// rebalancing falls back to QPS until every node gossips its load along the
+// other dimensions.
+func TestLoadBasedRebalancingObjectiveVersionGate(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	oldVersion := clusterversion.ByKey(clusterversion.LoadBasedRebalancingObjectives - 1)
+	st := cluster.MakeTestingClusterSettingsWithVersions(
+		clusterversion.TestingBinaryVersion, oldVersion, false /* initializeVersion */)
+	require.NoError(t, clusterversion.Initialize(ctx, oldVersion, &st.SV))
+	LoadBasedRebalancingObjective.Override(ctx, &st.SV, int64(LBRebalancingWriteBytes))
+	require.Equal(t, LBRebalancingQueries, loadBasedRebalancingObjective(ctx, st))
+
+	require.NoError(t, st.Version.SetActiveVersion(ctx, clusterversion.ClusterVersion{
+		Version: clusterversion.ByKey(clusterversion.LoadBasedRebalancingObjectives),
+	}))
+	require.Equal(t, LBRebalancingWriteBytes, loadBasedRebalancingObjective(ctx, st))
+}
+
 func TestChooseRangeToRebalance(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -230,7 +335,7 @@ func TestChooseRangeToRebalance(t *testing.T) {
 	// Rather than trying to populate every Replica with a real raft group in
 	// order to pass replicaIsBehind checks, fake out the function for getting
 	// raft status with one that always returns all replicas as up to date.
-	sr.getRaftStatusFn = func(r *Replica) *raft.Status {
+	sr.getRaftStatusFn = func(r leaseholderReplica) *raft.Status {
 		status := &raft.Status{
 			Progress: make(map[uint64]tracker.Progress),
 		}
@@ -533,9 +638,9 @@ func TestChooseRangeToRebalance(t *testing.T) {
 					{voters: tc.voters, nonVoters: tc.nonVoters, qps: tc.qps},
 				},
 			)
-			hottestRanges := rr.topQPS()
+			hottestRanges := rr.topLoad()
 			_, voterTargets, nonVoterTargets := sr.chooseRangeToRebalance(
-				ctx, &hottestRanges, &localDesc, storeList, storeMap, minQPS, maxQPS,
+				ctx, &hottestRanges, &localDesc, storeList, storeMap, LBRebalancingQueries, minQPS, maxQPS,
 			)
 
 			require.Len(t, voterTargets, len(tc.expectedRebalancedVoters))
@@ -592,12 +697,12 @@ func TestNoLeaseTransferToBehindReplicas(t *testing.T) {
 	// Load in a range with replicas on an overfull node, a slightly underfull
 	// node, and a very underfull node.
 	loadRanges(rr, s, []testRange{{voters: []roachpb.StoreID{1, 4, 5}, qps: 100}})
-	hottestRanges := rr.topQPS()
+	hottestRanges := rr.topLoad()
 	repl := hottestRanges[0].repl
 
 	// Set up a fake RaftStatus that indicates s5 is behind (but all other stores
 	// are caught up). We thus shouldn't transfer a lease to s5.
-	sr.getRaftStatusFn = func(r *Replica) *raft.Status {
+	sr.getRaftStatusFn = func(r leaseholderReplica) *raft.Status {
 		status := &raft.Status{
 			Progress: make(map[uint64]tracker.Progress),
 		}
@@ -617,7 +722,7 @@ func TestNoLeaseTransferToBehindReplicas(t *testing.T) {
 	}
 
 	_, target, _ := sr.chooseLeaseToTransfer(
-		ctx, &hottestRanges, &localDesc, storeList, storeMap, minQPS, maxQPS)
+		ctx, &hottestRanges, &localDesc, storeList, storeMap, LBRebalancingQueries, minQPS, maxQPS)
 	expectTarget := roachpb.StoreID(4)
 	if target.StoreID != expectTarget {
 		t.Errorf("got target store s%d for range with RaftStatus %v; want s%d",
@@ -628,11 +733,11 @@ func TestNoLeaseTransferToBehindReplicas(t *testing.T) {
 	// that's behind, and see how a new replica is preferred as the leaseholder
 	// over it.
 	loadRanges(rr, s, []testRange{{voters: []roachpb.StoreID{1, 3, 5}, qps: 100}})
-	hottestRanges = rr.topQPS()
+	hottestRanges = rr.topLoad()
 	repl = hottestRanges[0].repl
 
 	_, targets, _ := sr.chooseRangeToRebalance(
-		ctx, &hottestRanges, &localDesc, storeList, storeMap, minQPS, maxQPS)
+		ctx, &hottestRanges, &localDesc, storeList, storeMap, LBRebalancingQueries, minQPS, maxQPS)
 	expectTargets := []roachpb.ReplicationTarget{
 		{NodeID: 4, StoreID: 4}, {NodeID: 5, StoreID: 5}, {NodeID: 3, StoreID: 3},
 	}
@@ -307,9 +307,13 @@ func (s *Store) SplitRange(
 	if rightReplOrNil == nil {
 		throwawayRightWriteStats := new(replicaStats)
 		leftRepl.writeStats.splitRequestCounts(throwawayRightWriteStats)
+		leftRepl.requestTimeStats.splitRequestCounts(new(replicaStats))
+		leftRepl.writeBytesStats.splitRequestCounts(new(replicaStats))
 	} else {
 		rightRepl := rightReplOrNil
 		leftRepl.writeStats.splitRequestCounts(rightRepl.writeStats)
+		leftRepl.requestTimeStats.splitRequestCounts(rightRepl.requestTimeStats)
+		leftRepl.writeBytesStats.splitRequestCounts(rightRepl.writeBytesStats)
 		if err := s.addReplicaInternalLocked(rightRepl); err != nil {
 			return errors.Wrapf(err, "unable to add replica %v", rightRepl)
 		}
@@ -115,6 +115,21 @@ func TestShouldPushImmediately(t *testing.T) {
 			}
 		})
 	}
+
+	// Timestamp pushes of transactions which tolerate write skew are never
+	// queued, since they succeed regardless of priority.
+	for _, typ := range []roachpb.PushTxnType{roachpb.PUSH_ABORT, roachpb.PUSH_TIMESTAMP} {
+		req := roachpb.PushTxnRequest{
+			PushType: typ,
+			PusherTxn: roachpb.Transaction{
+				TxnMeta: enginepb.TxnMeta{Priority: mid},
+			},
+			PusheeTxn: enginepb.TxnMeta{Priority: mid, IsoLevel: enginepb.READ_COMMITTED},
+		}
+		if shouldPush := ShouldPushImmediately(&req); shouldPush != (typ == roachpb.PUSH_TIMESTAMP) {
+			t.Errorf("%s: expected %t; got %t", typ, typ == roachpb.PUSH_TIMESTAMP, shouldPush)
+		}
+	}
 }
 
 func makeTS(w int64, l int32) hlc.Timestamp {
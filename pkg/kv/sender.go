@@ -126,6 +126,13 @@ type TxnSender interface {
 	// SetUserPriority sets the txn's priority.
 	SetUserPriority(roachpb.UserPriority) error
 
+	// SetIsoLevel sets the txn's isolation level. The isolation level must be
+	// set before any operations are performed on the transaction.
+	SetIsoLevel(enginepb.IsolationLevel) error
+
+	// IsoLevel returns the txn's isolation level.
+	IsoLevel() enginepb.IsolationLevel
+
 	// SetDebugName sets the txn's debug name.
 	SetDebugName(name string)
 
@@ -300,6 +307,25 @@ type TxnSender interface {
 	// for use in tests and assertion checks.
 	GetSteppingMode(ctx context.Context) (curMode SteppingMode)
 
+	// SetReadSeqNum sets the read sequence number of the transaction. It
+	// is used to let a read-only operation observe the transaction's
+	// writes as of an earlier sequencing point, for example when a SQL
+	// cursor is consumed after further writes have been performed.
+	//
+	// The sequence number must not exceed the current write sequence
+	// number, and stepping mode must be enabled.
+	SetReadSeqNum(seq enginepb.TxnSeq) error
+
+	// StepReadTimestamp establishes the read snapshot of a new statement in
+	// transactions whose isolation level takes a new read snapshot in each
+	// statement, by moving the transaction's read timestamp forward to the
+	// present time. It also establishes the state that the transaction is
+	// rolled back to if the statement needs to be retried (see
+	// roachpb.TransactionRetryWithProtoRefreshError.StatementRetry).
+	//
+	// It is a no-op for transactions of other isolation levels.
+	StepReadTimestamp(context.Context) error
+
 	// ManualRefresh attempts to refresh a transactions read timestamp up to its
 	// provisional commit timestamp. In the case that the two are already the
 	// same, it is a no-op. The reason one might want to do that is to ensure
@@ -290,6 +290,26 @@ func (txn *Txn) SetUserPriority(userPriority roachpb.UserPriority) error {
 	return txn.mu.sender.SetUserPriority(userPriority)
 }
 
+// SetIsoLevel sets the transaction's isolation level. Transactions default to
+// SERIALIZABLE. The isolation level must be set before any operations are
+// performed on the transaction.
+func (txn *Txn) SetIsoLevel(isoLevel enginepb.IsolationLevel) error {
+	if txn.typ != RootTxn {
+		return errors.AssertionFailedf("SetIsoLevel() called on leaf txn")
+	}
+
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.sender.SetIsoLevel(isoLevel)
+}
+
+// IsoLevel returns the transaction's isolation level.
+func (txn *Txn) IsoLevel() enginepb.IsolationLevel {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.sender.IsoLevel()
+}
+
 // TestingSetPriority sets the transaction priority. It is intended for
 // internal (testing) use only.
 func (txn *Txn) TestingSetPriority(priority enginepb.TxnPriority) {
@@ -973,6 +993,17 @@ func (txn *Txn) PrepareForRetry(ctx context.Context, err error) {
 	txn.commitTriggers = nil
 	log.VEventf(ctx, 2, "automatically retrying transaction: %s because of error: %s",
 		txn.DebugName(), err)
+
+	// If the error only prepared the transaction for a retry of the statement
+	// which encountered it, restart the transaction, since all of its work is
+	// about to be retried.
+	var retryErr *roachpb.TransactionRetryWithProtoRefreshError
+	if errors.As(err, &retryErr) && retryErr.StatementRetry {
+		txn.mu.Lock()
+		defer txn.mu.Unlock()
+		txn.resetDeadlineLocked()
+		txn.mu.sender.ManualRestart(ctx, txn.mu.userPriority, retryErr.Transaction.WriteTimestamp)
+	}
 }
 
 // IsRetryableErrMeantForTxn returns true if err is a retryable
@@ -1063,6 +1094,11 @@ func (txn *Txn) handleErrIfRetryableLocked(ctx context.Context, err error) {
 	if !errors.As(err, &retryErr) {
 		return
 	}
+	if retryErr.StatementRetry {
+		// The transaction was not restarted, so the deadline established by
+		// its previous statements still applies.
+		return
+	}
 	txn.resetDeadlineLocked()
 	txn.replaceRootSenderIfTxnAbortedLocked(ctx, retryErr, retryErr.TxnID)
 }
@@ -1528,6 +1564,26 @@ func (txn *Txn) ConfigureStepping(ctx context.Context, mode SteppingMode) (prevM
 	return txn.mu.sender.ConfigureStepping(ctx, mode)
 }
 
+// SetReadSeqNum sets the read sequence number for this transaction.
+// See TxnSender.SetReadSeqNum.
+func (txn *Txn) SetReadSeqNum(seq enginepb.TxnSeq) error {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.sender.SetReadSeqNum(seq)
+}
+
+// StepReadTimestamp establishes the read snapshot of a new statement. It is a
+// no-op unless the transaction's isolation level takes a new read snapshot in
+// each statement. See TxnSender.StepReadTimestamp.
+func (txn *Txn) StepReadTimestamp(ctx context.Context) error {
+	if txn.typ != RootTxn {
+		return errors.AssertionFailedf("StepReadTimestamp() called on leaf txn")
+	}
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.sender.StepReadTimestamp(ctx)
+}
+
 // CreateSavepoint establishes a savepoint.
 // This method is only valid when called on RootTxns.
 func (txn *Txn) CreateSavepoint(ctx context.Context) (SavepointToken, error) {
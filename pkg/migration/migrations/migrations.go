@@ -140,6 +140,18 @@ var migrations = []migration.Migration{
 		NoPrecondition,
 		sqlStatsTablesMigration,
 	),
+	migration.NewTenantMigration(
+		"add the system.notifications table",
+		toCV(clusterversion.NotificationsTable),
+		NoPrecondition,
+		notificationsTableMigration,
+	),
+	migration.NewTenantMigration(
+		"add the system.advisory_locks table",
+		toCV(clusterversion.AdvisoryLocksTable),
+		NoPrecondition,
+		advisoryLocksTableMigration,
+	),
 }
 
 func init() {
@@ -67,6 +67,10 @@ const (
 
 	graphiteIntervalKey = "external.graphite.interval"
 	maxGraphiteInterval = 15 * time.Minute
+
+	// tenantWeightsInterval is the interval for recomputing the tenant weights
+	// used by KV admission control.
+	tenantWeightsInterval = 10 * time.Second
 )
 
 // Metric names.
@@ -475,6 +479,9 @@ func (n *Node) start(
 	}
 
 	n.startComputePeriodicMetrics(n.stopper, base.DefaultMetricsSampleInterval)
+	if n.kvAdmissionQ != nil {
+		n.startUpdateTenantWeights(n.stopper, tenantWeightsInterval)
+	}
 
 	// Be careful about moving this line above where we start stores; store
 	// migrations rely on the fact that the cluster version has not been updated
@@ -710,6 +717,63 @@ func (n *Node) computePeriodicMetrics(ctx context.Context, tick int) error {
 	})
 }
 
+// startUpdateTenantWeights starts a loop which periodically recomputes the
+// tenant weights used by the KV admission control WorkQueues.
+func (n *Node) startUpdateTenantWeights(stopper *stop.Stopper, interval time.Duration) {
+	ctx := n.AnnotateCtx(context.Background())
+	_ = stopper.RunAsyncTask(ctx, "update-tenant-weights", func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				n.updateTenantWeights()
+			case <-stopper.ShouldQuiesce():
+				return
+			}
+		}
+	})
+}
+
+// updateTenantWeights sets the tenant weights of the KV admission control
+// WorkQueues, for the node and for each store. A tenant's weight is the
+// number of its replicas on the node or store, so that tenants with more
+// data on a node get a proportionally larger share of admission. When
+// admission.kv.tenant_weights.enabled is false, the weights are cleared.
+//
+// The weights are deliberately not derived from the tenant cost model: the
+// request units it charges measure what a tenant consumed, so weighting by
+// them would give the heaviest tenant the largest share, which is the
+// opposite of fair sharing. The tenants' request unit budgets, which could
+// serve as entitlements, are enforced by the tenants' SQL pods and aren't
+// known to KV nodes.
+func (n *Node) updateTenantWeights() {
+	if !admission.KVTenantWeightsEnabled.Get(&n.storeCfg.Settings.SV) {
+		n.kvAdmissionQ.SetTenantWeights(nil)
+		_ = n.stores.VisitStores(func(store *kvserver.Store) error {
+			n.storeGrantCoords.SetTenantWeights(int32(store.StoreID()), nil)
+			return nil
+		})
+		return
+	}
+	nodeWeights := make(map[uint64]uint32)
+	_ = n.stores.VisitStores(func(store *kvserver.Store) error {
+		storeWeights := make(map[uint64]uint32)
+		store.VisitReplicas(func(r *kvserver.Replica) bool {
+			if tenantID, ok := r.TenantID(); ok {
+				storeWeights[tenantID.ToUint64()]++
+			}
+			return true
+		})
+		for tenantID, weight := range storeWeights {
+			nodeWeights[tenantID] += weight
+		}
+		n.storeGrantCoords.SetTenantWeights(int32(store.StoreID()), storeWeights)
+		return nil
+	})
+	n.kvAdmissionQ.SetTenantWeights(nodeWeights)
+}
+
 // GetPebbleMetrics implements admission.PebbleMetricsProvider.
 func (n *Node) GetPebbleMetrics() []admission.StoreMetrics {
 	var metrics []admission.StoreMetrics
@@ -942,6 +1006,19 @@ func isSingleHeartbeatTxnRequest(b *roachpb.BatchRequest) bool {
 	return ok
 }
 
+// isBulkWrite returns whether a write batch is bulk work, which is subject to
+// the store's disk bandwidth in addition to its IO load. Only AddSSTable
+// requests, issued by backfills and imports, are bulk work: the disk bandwidth
+// tokens are estimated from the bytes ingested into the store, which other
+// writes don't contribute to, even at a low priority.
+func isBulkWrite(b *roachpb.BatchRequest) bool {
+	if len(b.Requests) != 1 {
+		return false
+	}
+	_, ok := b.Requests[0].GetInner().(*roachpb.AddSSTableRequest)
+	return ok
+}
+
 // Batch implements the roachpb.InternalServer interface.
 func (n *Node) Batch(
 	ctx context.Context, args *roachpb.BatchRequest,
@@ -997,8 +1074,15 @@ func (n *Node) Batch(
 		// all the slots, causing no useful work to happen. We do want useful work
 		// to continue even when throttling since there are often significant
 		// number of tokens available.
+		//
+		// Bulk writes are subject to a separate queue, which is only granted
+		// admission after the foreground writes waiting for the store.
 		if args.IsWrite() && !isSingleHeartbeatTxnRequest(args) {
-			storeAdmissionQ = n.storeGrantCoords.TryGetQueueForStore(int32(args.Replica.StoreID))
+			if isBulkWrite(args) {
+				storeAdmissionQ = n.storeGrantCoords.TryGetBulkQueueForStore(int32(args.Replica.StoreID))
+			} else {
+				storeAdmissionQ = n.storeGrantCoords.TryGetQueueForStore(int32(args.Replica.StoreID))
+			}
 		}
 		admissionEnabled := true
 		if storeAdmissionQ != nil {
@@ -676,6 +676,7 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 				jobRegistry, internalExecutor, jobsprotectedts.Jobs),
 			jobsprotectedts.GetMetaType(jobsprotectedts.Schedules): jobsprotectedts.MakeStatusFunc(jobRegistry,
 				internalExecutor, jobsprotectedts.Schedules),
+			sql.ReplicationSlotMetaType: sql.ReplicationSlotStatusFunc(keys.SystemSQLCodec),
 		},
 	})
 	registry.AddMetricStruct(protectedtsReconciler.Metrics())
@@ -0,0 +1,97 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/sql/advisorylock"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// AcquireAdvisoryLock is part of the tree.EvalPlanner interface.
+func (p *planner) AcquireAdvisoryLock(
+	ctx context.Context, key advisorylock.Key, xact, wait bool,
+) (bool, error) {
+	s, err := p.advisoryLockSession(ctx)
+	if err != nil {
+		return false, err
+	}
+	dbID, err := p.advisoryLockDatabaseID(ctx)
+	if err != nil {
+		return false, err
+	}
+	return s.Lock(ctx, dbID, key, xact, wait)
+}
+
+// ReleaseAdvisoryLock is part of the tree.EvalPlanner interface.
+func (p *planner) ReleaseAdvisoryLock(ctx context.Context, key advisorylock.Key) (bool, error) {
+	s, err := p.advisoryLockSession(ctx)
+	if err != nil {
+		return false, err
+	}
+	dbID, err := p.advisoryLockDatabaseID(ctx)
+	if err != nil {
+		return false, err
+	}
+	return s.Unlock(ctx, dbID, key), nil
+}
+
+// ReleaseAllAdvisoryLocks is part of the tree.EvalPlanner interface.
+func (p *planner) ReleaseAllAdvisoryLocks(ctx context.Context) error {
+	s, err := p.advisoryLockSession(ctx)
+	if err != nil {
+		return err
+	}
+	s.UnlockAll(ctx)
+	return nil
+}
+
+// advisoryLockSession returns the advisory locks held by the session.
+func (p *planner) advisoryLockSession(ctx context.Context) (*advisorylock.Session, error) {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.AdvisoryLocksTable) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to use advisory locks",
+			clusterversion.AdvisoryLocksTable)
+	}
+	if p.advisoryLocks == nil || p.ExecCfg().AdvisoryLockManager == nil {
+		return nil, pgerror.New(pgcode.FeatureNotSupported,
+			"advisory locks are not supported in this context")
+	}
+	return p.advisoryLocks(), nil
+}
+
+// advisoryLockDatabaseID returns the ID of the database advisory locks are
+// scoped to, which is the current database, or 0 if there is none.
+func (p *planner) advisoryLockDatabaseID(ctx context.Context) (uint32, error) {
+	if p.CurrentDatabase() == "" {
+		return 0, nil
+	}
+	dbDesc, err := p.Descriptors().GetImmutableDatabaseByName(
+		ctx, p.txn, p.CurrentDatabase(), tree.DatabaseLookupFlags{},
+	)
+	if err != nil || dbDesc == nil {
+		return 0, err
+	}
+	return uint32(dbDesc.GetID()), nil
+}
+
+// getAdvisoryLockSession returns the advisory locks held by the session,
+// creating the session's advisorylock.Session on first use.
+func (ex *connExecutor) getAdvisoryLockSession() *advisorylock.Session {
+	if ex.advisoryLocks == nil {
+		ex.advisoryLocks = ex.server.cfg.AdvisoryLockManager.NewSession(ex.sessionID.GetBytes())
+	}
+	return ex.advisoryLocks
+}
@@ -0,0 +1,138 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql_test
+
+import (
+	"context"
+	gosql "database/sql"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAdvisoryLockDeadlock checks that two sessions which each wait for the
+// advisory lock held by the other don't wait forever: one of them fails with
+// a deadlock error, and the other one acquires the lock once the first one
+// releases its locks.
+func TestAdvisoryLockDeadlock(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+
+	// Each connection is a separate session.
+	conns := make([]*gosql.Conn, 2)
+	for i := range conns {
+		var err error
+		conns[i], err = db.Conn(ctx)
+		require.NoError(t, err)
+		defer conns[i].Close()
+		_, err = conns[i].ExecContext(ctx, "SELECT pg_advisory_lock($1)", i)
+		require.NoError(t, err)
+	}
+
+	type result struct {
+		conn *gosql.Conn
+		err  error
+	}
+	results := make(chan result, len(conns))
+	for i := range conns {
+		conn, key := conns[i], (i+1)%len(conns)
+		go func() {
+			_, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", key)
+			results <- result{conn: conn, err: err}
+		}()
+	}
+
+	// One of the sessions is chosen to break the deadlock, and the other one
+	// keeps waiting.
+	victim := <-results
+	require.Error(t, victim.err)
+	var pqErr *pq.Error
+	require.True(t, errors.As(victim.err, &pqErr), "%+v", victim.err)
+	require.Equal(t, pgcode.DeadlockDetected.String(), string(pqErr.Code))
+
+	// The victim keeps the lock it already held until it releases it.
+	_, err := victim.conn.ExecContext(ctx, "SELECT pg_advisory_unlock_all()")
+	require.NoError(t, err)
+	require.NoError(t, (<-results).err)
+}
+
+// TestAdvisoryLockWaitInPgLocks checks that pg_locks lists the sessions
+// waiting for an advisory lock as not granted, along with the process IDs of
+// the sessions holding and waiting for the lock.
+func TestAdvisoryLockWaitInPgLocks(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+
+	conns := make([]*gosql.Conn, 2)
+	pids := make([]int, 2)
+	for i := range conns {
+		var err error
+		conns[i], err = db.Conn(ctx)
+		require.NoError(t, err)
+		defer conns[i].Close()
+		require.NoError(t, conns[i].QueryRowContext(ctx, "SELECT pg_backend_pid()").Scan(&pids[i]))
+	}
+	holder, waiter := conns[0], conns[1]
+	_, err := holder.ExecContext(ctx, "SELECT pg_advisory_lock(1)")
+	require.NoError(t, err)
+	acquired := make(chan error, 1)
+	go func() {
+		_, err := waiter.ExecContext(ctx, "SELECT pg_advisory_lock(1)")
+		acquired <- err
+	}()
+
+	type lockRow struct {
+		pid     int
+		granted bool
+	}
+	readLocks := func() []lockRow {
+		rows, err := db.Query("SELECT pid, granted FROM pg_locks ORDER BY granted DESC")
+		require.NoError(t, err)
+		defer rows.Close()
+		var res []lockRow
+		for rows.Next() {
+			var r lockRow
+			require.NoError(t, rows.Scan(&r.pid, &r.granted))
+			res = append(res, r)
+		}
+		require.NoError(t, rows.Err())
+		return res
+	}
+	testutils.SucceedsSoon(t, func() error {
+		if locks := readLocks(); len(locks) != 2 {
+			return errors.Errorf("expected the lock and its waiter, found %v", locks)
+		}
+		return nil
+	})
+	require.Equal(t, []lockRow{{pid: pids[0], granted: true}, {pid: pids[1], granted: false}}, readLocks())
+
+	// Once the waiter is granted the lock, its wait is no longer listed.
+	_, err = holder.ExecContext(ctx, "SELECT pg_advisory_unlock(1)")
+	require.NoError(t, err)
+	require.NoError(t, <-acquired)
+	require.Equal(t, []lockRow{{pid: pids[1], granted: true}}, readLocks())
+}
@@ -0,0 +1,614 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package advisorylock implements the cluster-wide advisory locks acquired
+// with pg_advisory_lock() and friends.
+//
+// Every lock held by a session is owned by a dedicated KV transaction, which
+// writes an intent on the lock's key in the system.advisory_locks table and
+// never commits. The lock is released by rolling the transaction back.
+// Acquiring a lock thus relies on the KV lock table for queueing: a session
+// waiting for a lock waits on the intent of the holder, and is granted the
+// lock as soon as that intent is removed. Locks held by a node which dies are
+// released once its transactions stop heartbeating and are aborted by the
+// waiters.
+//
+// A session which finds a lock held by another session records its wait in
+// the intent of another transaction, on a row of the lock identified by the
+// session's ID, so that the waiting requests are listed in pg_locks. Since
+// the locks of a session are held by distinct transactions, the KV layer
+// can't detect the deadlocks between sessions. Instead, the waiting sessions
+// periodically look for a cycle in the wait-for graph formed by these rows,
+// like Postgres does after deadlock_timeout. One session of a cycle fails to
+// acquire its lock with a DeadlockDetected error, which breaks it.
+//
+// Locks are acquired under the SQL instance's sqlliveness session, and are
+// released when that session expires. Session-level locks are released when
+// the SQL session that acquired them ends, and transaction-level locks when
+// its current transaction does.
+package advisorylock
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlliveness"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/logtags"
+)
+
+// deadlockCheckInterval is the interval at which a session waiting for a lock
+// checks whether it is part of a deadlock.
+var deadlockCheckInterval = time.Second
+
+// Key identifies an advisory lock within a database. Its fields match the
+// columns which identify advisory locks in pg_locks.
+type Key struct {
+	ClassID uint32
+	ObjID   uint32
+	// ObjSubID is 1 for the locks identified by a single int8 value, and 2 for
+	// the locks identified by a pair of int4 values.
+	ObjSubID uint32
+}
+
+// MakeKey returns the key of the lock identified by a single int8 value.
+func MakeKey(key int64) Key {
+	return Key{
+		ClassID:  uint32(uint64(key) >> 32),
+		ObjID:    uint32(key),
+		ObjSubID: 1,
+	}
+}
+
+// MakePairKey returns the key of the lock identified by a pair of int4
+// values.
+func MakePairKey(key1, key2 int32) Key {
+	return Key{
+		ClassID:  uint32(key1),
+		ObjID:    uint32(key2),
+		ObjSubID: 2,
+	}
+}
+
+// lockID identifies an advisory lock across the cluster. Advisory locks are
+// scoped to a database, like in Postgres.
+type lockID struct {
+	// databaseID is the ID of the database the lock was acquired in, or 0 if
+	// the session had no current database.
+	databaseID uint32
+	key        Key
+}
+
+// LockInfo describes a lock held by a session, or a session's request for a
+// lock held by another session.
+type LockInfo struct {
+	DatabaseID uint32
+	Key        Key
+	// SessionID is the ID of the SQL session holding or waiting for the lock.
+	SessionID []byte
+	// ClaimSessionID is the sqlliveness session the lock was acquired, or is
+	// waited for, under.
+	ClaimSessionID sqlliveness.SessionID
+	// Granted is false if the session waits for the lock.
+	Granted bool
+}
+
+// SessionIDString returns the hex-encoded ID of the session holding or
+// waiting for the lock, in the format used by SHOW SESSIONS.
+func (i LockInfo) SessionIDString() string {
+	return hex.EncodeToString(i.SessionID)
+}
+
+// Manager acquires and releases advisory locks on behalf of SQL sessions.
+type Manager struct {
+	codec    keys.SQLCodec
+	db       *kv.DB
+	liveness sqlliveness.Instance
+
+	mu struct {
+		syncutil.Mutex
+		// sessions is the set of open sessions.
+		sessions map[*Session]struct{}
+		// claims is the set of sqlliveness sessions for which an expiry
+		// callback has been registered.
+		claims map[sqlliveness.SessionID]struct{}
+	}
+}
+
+// NewManager constructs a new Manager.
+func NewManager(codec keys.SQLCodec, db *kv.DB, liveness sqlliveness.Instance) *Manager {
+	m := &Manager{
+		codec:    codec,
+		db:       db,
+		liveness: liveness,
+	}
+	m.mu.sessions = make(map[*Session]struct{})
+	m.mu.claims = make(map[sqlliveness.SessionID]struct{})
+	return m
+}
+
+// NewSession returns a new Session for the SQL session with the given ID. The
+// session must be closed once it is no longer used.
+func (m *Manager) NewSession(sessionID []byte) *Session {
+	s := &Session{m: m, id: sessionID}
+	s.mu.locks = make(map[lockID]*heldLock)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.mu.sessions[s] = struct{}{}
+	return s
+}
+
+// registerClaim makes sure that the locks acquired under the given
+// sqlliveness session are released when it expires.
+func (m *Manager) registerClaim(sess sqlliveness.Session) {
+	id := sess.ID()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.mu.claims[id]; ok {
+		return
+	}
+	m.mu.claims[id] = struct{}{}
+	sess.RegisterCallbackForSessionExpiry(func(ctx context.Context) {
+		m.releaseClaim(ctx, id)
+	})
+}
+
+// releaseClaim releases all the locks acquired under the given, expired,
+// sqlliveness session.
+func (m *Manager) releaseClaim(ctx context.Context, id sqlliveness.SessionID) {
+	var sessions []*Session
+	func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		delete(m.mu.claims, id)
+		for s := range m.mu.sessions {
+			sessions = append(sessions, s)
+		}
+	}()
+	for _, s := range sessions {
+		s.releaseIf(ctx, func(l *heldLock) bool { return l.claim == id })
+	}
+}
+
+// List returns the locks currently held across the cluster, followed for
+// each lock by the requests waiting for it. Since the locks are never
+// committed, they are read by scanning system.advisory_locks at the
+// READ_UNCOMMITTED consistency level.
+func (m *Manager) List(ctx context.Context) ([]LockInfo, error) {
+	prefix := m.indexPrefix()
+	var ba roachpb.BatchRequest
+	ba.ReadConsistency = roachpb.READ_UNCOMMITTED
+	ba.Add(&roachpb.ScanRequest{
+		RequestHeader: roachpb.RequestHeader{
+			Key:    prefix,
+			EndKey: prefix.PrefixEnd(),
+		},
+	})
+	br, pErr := m.db.NonTransactionalSender().Send(ctx, ba)
+	if pErr != nil {
+		return nil, pErr.GoError()
+	}
+	rows := br.Responses[0].GetInner().(*roachpb.ScanResponse).IntentRows
+	locks := make([]LockInfo, 0, len(rows))
+	for _, row := range rows {
+		// Intents which have been removed show up as deletions.
+		if !row.Value.IsPresent() {
+			continue
+		}
+		info, err := m.decodeLock(row)
+		if err != nil {
+			return nil, err
+		}
+		locks = append(locks, info)
+	}
+	return locks, nil
+}
+
+func (m *Manager) indexPrefix() roachpb.Key {
+	return m.codec.IndexPrefix(keys.AdvisoryLocksTableID, 1)
+}
+
+// makeLockKey returns the key of the row whose intent holds the lock.
+func (m *Manager) makeLockKey(id lockID) roachpb.Key {
+	return m.makeWaiterKey(id, nil /* sessionID */)
+}
+
+// makeWaiterKey returns the key of the row whose intent records that the
+// session with the given ID waits for the lock.
+func (m *Manager) makeWaiterKey(id lockID, sessionID []byte) roachpb.Key {
+	k := m.indexPrefix()
+	k = encoding.EncodeVarintAscending(k, int64(id.databaseID))
+	k = encoding.EncodeVarintAscending(k, int64(id.key.ClassID))
+	k = encoding.EncodeVarintAscending(k, int64(id.key.ObjID))
+	k = encoding.EncodeVarintAscending(k, int64(id.key.ObjSubID))
+	k = encoding.EncodeBytesAscending(k, sessionID)
+	return keys.MakeFamilyKey(k, 0)
+}
+
+func encodeLockValue(sessionID []byte, claim sqlliveness.SessionID) roachpb.Value {
+	var v roachpb.Value
+	// The session_id and claim_session_id columns have IDs 5 and 6; the value
+	// tuple stores the difference between consecutive column IDs.
+	b := encoding.EncodeBytesValue(nil, 5, sessionID)
+	b = encoding.EncodeBytesValue(b, 1, claim.UnsafeBytes())
+	v.SetTuple(b)
+	return v
+}
+
+func (m *Manager) decodeLock(row roachpb.KeyValue) (LockInfo, error) {
+	var info LockInfo
+	prefix := m.indexPrefix()
+	if !bytes.HasPrefix(row.Key, prefix) {
+		return LockInfo{}, errors.AssertionFailedf("unexpected key outside of advisory locks index: %v", row.Key)
+	}
+	rem := row.Key[len(prefix):]
+	var fields [4]int64
+	var err error
+	for i := range fields {
+		if rem, fields[i], err = encoding.DecodeVarintAscending(rem); err != nil {
+			return LockInfo{}, errors.Wrapf(err, "failed to decode advisory lock key %v", row.Key)
+		}
+	}
+	info.DatabaseID = uint32(fields[0])
+	info.Key = Key{
+		ClassID:  uint32(fields[1]),
+		ObjID:    uint32(fields[2]),
+		ObjSubID: uint32(fields[3]),
+	}
+	var waiterID []byte
+	if _, waiterID, err = encoding.DecodeBytesAscending(rem, nil); err != nil {
+		return LockInfo{}, errors.Wrapf(err, "failed to decode advisory lock key %v", row.Key)
+	}
+	info.Granted = len(waiterID) == 0
+	tup, err := row.Value.GetTuple()
+	if err != nil {
+		return LockInfo{}, errors.Wrapf(err, "failed to decode tuple from key %v", row.Key)
+	}
+	var claim []byte
+	if tup, info.SessionID, err = encoding.DecodeBytesValue(tup); err != nil {
+		return LockInfo{}, errors.Wrapf(err, "failed to decode session ID from key %v", row.Key)
+	}
+	if _, claim, err = encoding.DecodeBytesValue(tup); err != nil {
+		return LockInfo{}, errors.Wrapf(err, "failed to decode claim session ID from key %v", row.Key)
+	}
+	info.ClaimSessionID = sqlliveness.SessionID(claim)
+	return info, nil
+}
+
+// heldLock is a lock held by a session. The lock is held as long as at least
+// one of its counts is positive.
+type heldLock struct {
+	txn   *kv.Txn
+	claim sqlliveness.SessionID
+	// sessionCount and xactCount are the number of times the session- and
+	// transaction-level lock was acquired, respectively.
+	sessionCount int
+	xactCount    int
+}
+
+// Session holds the advisory locks acquired by a SQL session. Its methods are
+// not safe for concurrent use, except for the releases triggered by the
+// expiry of the sqlliveness session.
+type Session struct {
+	m  *Manager
+	id []byte
+
+	mu struct {
+		syncutil.Mutex
+		locks map[lockID]*heldLock
+	}
+}
+
+// Lock acquires the lock with the given key in the given database, at the
+// transaction level if xact is set and at the session level otherwise. Locks
+// are re-entrant: acquiring a lock the session already holds succeeds
+// immediately. If wait is false, Lock returns false instead of waiting when
+// another session holds the lock. If wait is true and waiting for the lock
+// would deadlock, Lock may return a DeadlockDetected error.
+func (s *Session) Lock(
+	ctx context.Context, databaseID uint32, key Key, xact, wait bool,
+) (acquired bool, _ error) {
+	id := lockID{databaseID: databaseID, key: key}
+	if s.incrementIfHeld(id, xact) {
+		return true, nil
+	}
+
+	sess, err := s.m.liveness.Session(ctx)
+	if err != nil {
+		return false, err
+	}
+	lockKey := s.m.makeLockKey(id)
+	txn, err := s.acquire(ctx, lockKey, sess.ID(), lock.WaitPolicy_Error)
+	if wait && errors.HasType(err, (*roachpb.WriteIntentError)(nil)) {
+		// Another session holds the lock.
+		txn, err = s.waitForLock(ctx, id, sess.ID())
+	}
+	if err != nil {
+		if !wait && errors.HasType(err, (*roachpb.WriteIntentError)(nil)) {
+			return false, nil
+		}
+		if pgerror.GetPGCode(err) == pgcode.DeadlockDetected {
+			return false, err
+		}
+		// The error belongs to the lock's transaction, not to the transaction
+		// of the session; hide its type so that it isn't mistaken for a
+		// retryable error of the latter.
+		return false, errors.Wrap(errors.Handled(err), "failed to acquire advisory lock")
+	}
+
+	s.m.registerClaim(sess)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	l := &heldLock{txn: txn, claim: sess.ID()}
+	if xact {
+		l.xactCount++
+	} else {
+		l.sessionCount++
+	}
+	s.mu.locks[id] = l
+	return true, nil
+}
+
+// acquire writes the intent holding the lock with the given key in a new
+// transaction, using the given wait policy if the lock is held by another
+// session. The transaction is rolled back if the intent couldn't be written.
+func (s *Session) acquire(
+	ctx context.Context, lockKey roachpb.Key, claim sqlliveness.SessionID, waitPolicy lock.WaitPolicy,
+) (*kv.Txn, error) {
+	txn := kv.NewTxn(ctx, s.m.db, 0 /* gatewayNodeID */)
+	txn.SetDebugName("advisory lock")
+	b := txn.NewBatch()
+	b.Header.WaitPolicy = waitPolicy
+	v := encodeLockValue(s.id, claim)
+	b.Put(lockKey, &v)
+	if err := txn.Run(ctx, b); err != nil {
+		if rbErr := txn.Rollback(ctx); rbErr != nil {
+			log.Warningf(ctx, "failed to roll back advisory lock transaction: %v", rbErr)
+		}
+		return nil, err
+	}
+	return txn, nil
+}
+
+// waitForLock acquires the lock with the given ID, waiting for it to be
+// released by the session holding it. While it waits, the wait is recorded in
+// the lock's row for the session, and the session periodically checks whether
+// it is the victim of a deadlock.
+func (s *Session) waitForLock(
+	ctx context.Context, id lockID, claim sqlliveness.SessionID,
+) (*kv.Txn, error) {
+	waitTxn := kv.NewTxn(ctx, s.m.db, 0 /* gatewayNodeID */)
+	waitTxn.SetDebugName("advisory lock wait")
+	v := encodeLockValue(s.id, claim)
+	if err := waitTxn.Put(ctx, s.m.makeWaiterKey(id, s.id), &v); err != nil {
+		// The wait is only needed for pg_locks and deadlock detection.
+		log.Warningf(ctx, "failed to record wait for advisory lock: %v", err)
+	}
+	defer func() {
+		// Remove the wait even if the acquisition was canceled, since a stale
+		// wait could make other sessions detect a deadlock which doesn't exist.
+		ctx := logtags.WithTags(context.Background(), logtags.FromContext(ctx))
+		if err := waitTxn.Rollback(ctx); err != nil {
+			log.Warningf(ctx, "failed to remove wait for advisory lock: %v", err)
+		}
+	}()
+
+	lockKey := s.m.makeLockKey(id)
+	var txn *kv.Txn
+	done := make(chan struct{})
+	g := ctxgroup.WithContext(ctx)
+	g.GoCtx(func(ctx context.Context) error {
+		defer close(done)
+		var err error
+		txn, err = s.acquire(ctx, lockKey, claim, lock.WaitPolicy_Block)
+		return err
+	})
+	g.GoCtx(func(ctx context.Context) error {
+		// A session which holds no locks can't be part of a deadlock.
+		if !s.holdsLocks() {
+			return nil
+		}
+		var timer timeutil.Timer
+		defer timer.Stop()
+		for {
+			timer.Reset(deadlockCheckInterval)
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				return nil
+			case <-timer.C:
+				timer.Read = true
+			}
+			deadlocked, err := s.isDeadlockVictim(ctx, lockKey)
+			if err != nil {
+				log.Warningf(ctx, "failed to check for advisory lock deadlock: %v", err)
+				continue
+			}
+			if deadlocked {
+				// Returning an error cancels the acquisition.
+				return pgerror.New(pgcode.DeadlockDetected,
+					"deadlock detected while waiting for advisory lock")
+			}
+		}
+	})
+	if err := g.Wait(); err != nil {
+		if txn != nil {
+			// The lock was acquired concurrently with the detection of a
+			// deadlock.
+			(&heldLock{txn: txn}).release(ctx)
+		}
+		return nil, err
+	}
+	return txn, nil
+}
+
+// holdsLocks returns true if the session holds at least one lock.
+func (s *Session) holdsLocks() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.mu.locks) > 0
+}
+
+// isDeadlockVictim returns true if the session, which waits for the lock with
+// the given key, is part of a cycle of sessions each waiting for a lock held
+// by the next one, and was chosen to break it. Every session of the cycle
+// finds the same cycle, and the one with the largest ID is chosen so that
+// only one of them fails.
+func (s *Session) isDeadlockVictim(ctx context.Context, lockKey roachpb.Key) (bool, error) {
+	locks, err := s.m.List(ctx)
+	if err != nil {
+		return false, err
+	}
+	holders := make(map[string][]byte, len(locks))
+	waits := make(map[string]roachpb.Key)
+	for _, l := range locks {
+		key := s.m.makeLockKey(lockID{databaseID: l.DatabaseID, key: l.Key})
+		if l.Granted {
+			holders[string(key)] = l.SessionID
+		} else {
+			waits[string(l.SessionID)] = key
+		}
+	}
+
+	victim := s.id
+	visited := make(map[string]struct{})
+	for key := lockKey; ; {
+		holder, ok := holders[string(key)]
+		if !ok {
+			// The lock was released in the meantime.
+			return false, nil
+		}
+		if bytes.Equal(holder, s.id) {
+			return bytes.Equal(victim, s.id), nil
+		}
+		if _, ok := visited[string(holder)]; ok {
+			// The session waits for a cycle it isn't part of.
+			return false, nil
+		}
+		visited[string(holder)] = struct{}{}
+		if bytes.Compare(holder, victim) > 0 {
+			victim = holder
+		}
+		if key, ok = waits[string(holder)]; !ok {
+			return false, nil
+		}
+	}
+}
+
+func (s *Session) incrementIfHeld(id lockID, xact bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	l, ok := s.mu.locks[id]
+	if !ok {
+		return false
+	}
+	if xact {
+		l.xactCount++
+	} else {
+		l.sessionCount++
+	}
+	return true
+}
+
+// Unlock releases one acquisition of the session-level lock with the given
+// key in the given database. It returns false if the session doesn't hold the
+// lock at the session level.
+func (s *Session) Unlock(ctx context.Context, databaseID uint32, key Key) bool {
+	id := lockID{databaseID: databaseID, key: key}
+	var toRelease *heldLock
+	if ok := func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		l, ok := s.mu.locks[id]
+		if !ok || l.sessionCount == 0 {
+			return false
+		}
+		l.sessionCount--
+		if l.sessionCount == 0 && l.xactCount == 0 {
+			delete(s.mu.locks, id)
+			toRelease = l
+		}
+		return true
+	}(); !ok {
+		return false
+	}
+	if toRelease != nil {
+		toRelease.release(ctx)
+	}
+	return true
+}
+
+// UnlockAll releases all the session-level locks held by the session.
+func (s *Session) UnlockAll(ctx context.Context) {
+	s.releaseIf(ctx, func(l *heldLock) bool {
+		l.sessionCount = 0
+		return l.xactCount == 0
+	})
+}
+
+// ReleaseXactLocks releases all the transaction-level locks held by the
+// session. It is called when the session's transaction finishes.
+func (s *Session) ReleaseXactLocks(ctx context.Context) {
+	s.releaseIf(ctx, func(l *heldLock) bool {
+		l.xactCount = 0
+		return l.sessionCount == 0
+	})
+}
+
+// Close releases all the locks held by the session.
+func (s *Session) Close(ctx context.Context) {
+	s.releaseIf(ctx, func(*heldLock) bool { return true })
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+	delete(s.m.mu.sessions, s)
+}
+
+// releaseIf releases the locks for which fn returns true. fn is called with
+// s.mu held and may update the lock's counts.
+func (s *Session) releaseIf(ctx context.Context, fn func(*heldLock) bool) {
+	var toRelease []*heldLock
+	func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for id, l := range s.mu.locks {
+			if fn(l) {
+				delete(s.mu.locks, id)
+				toRelease = append(toRelease, l)
+			}
+		}
+	}()
+	for _, l := range toRelease {
+		l.release(ctx)
+	}
+}
+
+// release releases the lock by rolling its transaction back, which removes
+// its intent and lets the next waiter in.
+func (l *heldLock) release(ctx context.Context) {
+	if err := l.txn.Rollback(ctx); err != nil {
+		// The transaction stops heartbeating regardless, so the lock is
+		// eventually released once the transaction is considered abandoned.
+		log.Warningf(ctx, "failed to release advisory lock: %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package advisorylock
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlliveness"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMakeKey(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	// The int8 values are split in two halves, like in Postgres.
+	require.Equal(t, Key{ClassID: 0, ObjID: 1, ObjSubID: 1}, MakeKey(1))
+	require.Equal(t, Key{ClassID: 1, ObjID: 2, ObjSubID: 1}, MakeKey(1<<32+2))
+	require.Equal(t, Key{ClassID: math.MaxUint32, ObjID: math.MaxUint32, ObjSubID: 1}, MakeKey(-1))
+	// The pairs of int4 values don't conflict with the int8 values.
+	require.Equal(t, Key{ClassID: 1, ObjID: 2, ObjSubID: 2}, MakePairKey(1, 2))
+	require.Equal(t, Key{ClassID: math.MaxUint32, ObjID: 0, ObjSubID: 2}, MakePairKey(-1, 0))
+}
+
+func TestEncodeDecodeLock(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, codec := range []keys.SQLCodec{
+		keys.SystemSQLCodec,
+		keys.MakeSQLCodec(roachpb.MakeTenantID(5)),
+	} {
+		m := NewManager(codec, nil /* db */, nil /* liveness */)
+		for _, id := range []lockID{
+			{databaseID: 0, key: MakeKey(0)},
+			{databaseID: 52, key: MakeKey(math.MinInt64)},
+			{databaseID: 52, key: MakeKey(math.MaxInt64)},
+			{databaseID: math.MaxUint32, key: MakePairKey(math.MinInt32, math.MaxInt32)},
+		} {
+			// The row may hold the lock, or record a session waiting for it.
+			for _, granted := range []bool{true, false} {
+				exp := LockInfo{
+					DatabaseID:     id.databaseID,
+					Key:            id.key,
+					SessionID:      []byte("session"),
+					ClaimSessionID: sqlliveness.SessionID("claim"),
+					Granted:        granted,
+				}
+				row := roachpb.KeyValue{
+					Key:   m.makeLockKey(id),
+					Value: encodeLockValue(exp.SessionID, exp.ClaimSessionID),
+				}
+				if !granted {
+					row.Key = m.makeWaiterKey(id, exp.SessionID)
+				}
+				info, err := m.decodeLock(row)
+				require.NoError(t, err)
+				require.Equal(t, exp, info)
+			}
+		}
+	}
+}
@@ -128,6 +128,13 @@ func (n *alterIndexNode) startExec(params runParams) error {
 				); err != nil {
 					return err
 				}
+				if n.index.Primary() {
+					if err := params.p.updateIntervalPartitioningSchedule(
+						params.ctx, n.tableDesc, oldPartitioning.PartitioningDesc().Interval,
+					); err != nil {
+						return err
+					}
+				}
 			}
 		default:
 			return errors.AssertionFailedf(
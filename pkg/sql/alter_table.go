@@ -26,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/paramparse"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
@@ -83,6 +84,9 @@ func (p *planner) AlterTable(ctx context.Context, n *tree.AlterTable) (planNode,
 	if tableDesc == nil {
 		return newZeroNode(nil /* columns */), nil
 	}
+	if err := checkNotForeignTable(tableDesc, "alter"); err != nil {
+		return nil, err
+	}
 
 	// This check for CREATE privilege is kept for backwards compatibility.
 	if err := p.CheckPrivilege(ctx, tableDesc, privilege.CREATE); err != nil {
@@ -213,6 +217,9 @@ func (n *alterTableNode) startExec(params runParams) error {
 					}
 					continue
 				}
+				if d.Deferrable.IsDeferrable() {
+					return errDeferrableUniqueIndex
+				}
 
 				if d.PrimaryKey {
 					// We only support "adding" a primary key when we are using the
@@ -579,6 +586,10 @@ func (n *alterTableNode) startExec(params runParams) error {
 			if err := schemaexpr.ValidateColumnHasNoDependents(n.tableDesc, colToDrop); err != nil {
 				return err
 			}
+			// Nor if there are row-level security policies that use it.
+			if err := schemaexpr.ValidateColumnHasNoPolicyDependents(n.tableDesc, colToDrop); err != nil {
+				return err
+			}
 
 			if n.tableDesc.GetPrimaryIndex().CollectKeyColumnIDs().Contains(colToDrop.GetID()) {
 				return pgerror.Newf(pgcode.InvalidColumnReference,
@@ -955,8 +966,43 @@ func (n *alterTableNode) startExec(params runParams) error {
 				); err != nil {
 					return err
 				}
+				if err := params.p.updateIntervalPartitioningSchedule(
+					params.ctx, n.tableDesc, oldPartitioning.PartitioningDesc().Interval,
+				); err != nil {
+					return err
+				}
 			}
 
+		case *tree.AlterTableSetStorageParams:
+			oldTTL := copyRowLevelTTL(n.tableDesc.GetRowLevelTTL())
+			if err := paramparse.ApplyStorageParameters(
+				params.ctx,
+				params.p.SemaCtx(),
+				params.EvalContext(),
+				t.StorageParams,
+				&paramparse.TableStorageParamObserver{TableDesc: n.tableDesc.TableDesc()},
+			); err != nil {
+				return err
+			}
+			if err := params.p.updateRowLevelTTLSchedule(params.ctx, n.tableDesc, oldTTL); err != nil {
+				return err
+			}
+			descriptorChanged = true
+
+		case *tree.AlterTableResetStorageParams:
+			oldTTL := copyRowLevelTTL(n.tableDesc.GetRowLevelTTL())
+			if err := paramparse.ResetStorageParameters(
+				params.EvalContext(),
+				t.Params,
+				&paramparse.TableStorageParamObserver{TableDesc: n.tableDesc.TableDesc()},
+			); err != nil {
+				return err
+			}
+			if err := params.p.updateRowLevelTTLSchedule(params.ctx, n.tableDesc, oldTTL); err != nil {
+				return err
+			}
+			descriptorChanged = true
+
 		case *tree.AlterTableSetAudit:
 			changed, err := params.p.setAuditMode(params.ctx, n.tableDesc, t.Mode)
 			if err != nil {
@@ -964,6 +1010,28 @@ func (n *alterTableNode) startExec(params runParams) error {
 			}
 			descriptorChanged = descriptorChanged || changed
 
+		case *tree.AlterTableRowLevelSecurity:
+			if n.tableDesc.IsVirtualTable() || n.tableDesc.IsForeignTable() {
+				return pgerror.Newf(pgcode.WrongObjectType,
+					"%q does not support row-level security", n.tableDesc.Name)
+			}
+			if !params.EvalContext().Settings.Version.IsActive(params.ctx, clusterversion.RowLevelSecurity) {
+				return pgerror.Newf(pgcode.FeatureNotSupported,
+					"version %v must be finalized to use row-level security",
+					clusterversion.RowLevelSecurity)
+			}
+			switch t.Mode {
+			case tree.RowLevelSecurityEnable:
+				n.tableDesc.RowLevelSecurity = true
+			case tree.RowLevelSecurityDisable:
+				n.tableDesc.RowLevelSecurity = false
+			case tree.RowLevelSecurityForce:
+				n.tableDesc.ForceRowLevelSecurity = true
+			case tree.RowLevelSecurityNoForce:
+				n.tableDesc.ForceRowLevelSecurity = false
+			}
+			descriptorChanged = true
+
 		case *tree.AlterTableInjectStats:
 			sd, ok := n.statsData[i]
 			if !ok {
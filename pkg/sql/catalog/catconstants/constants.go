@@ -79,6 +79,8 @@ const (
 	TenantUsageTableName                   SystemTableName = "tenant_usage"
 	SQLInstancesTableName                  SystemTableName = "sql_instances"
 	SpanConfigurationsTableName            SystemTableName = "span_configurations"
+	NotificationsTableName                 SystemTableName = "notifications"
+	AdvisoryLocksTableName                 SystemTableName = "advisory_locks"
 )
 
 // Oid for virtual database and table.
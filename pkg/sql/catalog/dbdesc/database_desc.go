@@ -14,6 +14,7 @@ package dbdesc
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
@@ -223,6 +224,42 @@ func (desc *immutable) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
 	if desc.IsMultiRegion() {
 		desc.validateMultiRegion(vea)
 	}
+
+	// Validate the publications.
+	for i := range desc.Publications {
+		pub := &desc.Publications[i]
+		vea.Report(catalog.ValidateName(pub.Name, "publication"))
+		if i > 0 && desc.Publications[i-1].Name >= pub.Name {
+			vea.Report(errors.AssertionFailedf(
+				"publications are not sorted by name: %q before %q", desc.Publications[i-1].Name, pub.Name))
+		}
+		if pub.AllTables && len(pub.TableIDs) > 0 {
+			vea.Report(errors.AssertionFailedf(
+				"publication %q of all tables has table IDs %v", pub.Name, pub.TableIDs))
+		}
+	}
+
+	// Validate the replication slots.
+	for i := range desc.ReplicationSlots {
+		slot := &desc.ReplicationSlots[i]
+		vea.Report(catalog.ValidateName(slot.Name, "replication slot"))
+		if i > 0 && desc.ReplicationSlots[i-1].Name >= slot.Name {
+			vea.Report(errors.AssertionFailedf(
+				"replication slots are not sorted by name: %q before %q",
+				desc.ReplicationSlots[i-1].Name, slot.Name))
+		}
+	}
+
+	// Validate the foreign servers.
+	for i := range desc.ForeignServers {
+		server := &desc.ForeignServers[i]
+		vea.Report(catalog.ValidateName(server.Name, "foreign server"))
+		if i > 0 && desc.ForeignServers[i-1].Name >= server.Name {
+			vea.Report(errors.AssertionFailedf(
+				"foreign servers are not sorted by name: %q before %q",
+				desc.ForeignServers[i-1].Name, server.Name))
+		}
+	}
 }
 
 // validateMultiRegion performs checks specific to multi-region DBs.
@@ -443,6 +480,118 @@ func (desc *Mutable) SetDefaultPrivilegeDescriptor(
 	desc.DefaultPrivileges = defaultPrivilegeDescriptor
 }
 
+// GetPublication returns the publication of the database with the given name.
+func (desc *immutable) GetPublication(name string) (descpb.DatabaseDescriptor_Publication, bool) {
+	i := sort.Search(len(desc.Publications), func(i int) bool {
+		return desc.Publications[i].Name >= name
+	})
+	if i < len(desc.Publications) && desc.Publications[i].Name == name {
+		return desc.Publications[i], true
+	}
+	return descpb.DatabaseDescriptor_Publication{}, false
+}
+
+// AddPublication adds a publication to the database. The caller is
+// responsible for checking that no publication with the same name exists.
+func (desc *Mutable) AddPublication(pub descpb.DatabaseDescriptor_Publication) {
+	i := sort.Search(len(desc.Publications), func(i int) bool {
+		return desc.Publications[i].Name >= pub.Name
+	})
+	desc.Publications = append(desc.Publications, descpb.DatabaseDescriptor_Publication{})
+	copy(desc.Publications[i+1:], desc.Publications[i:])
+	desc.Publications[i] = pub
+}
+
+// RemovePublication removes the publication with the given name from the
+// database, and returns whether it existed.
+func (desc *Mutable) RemovePublication(name string) bool {
+	for i := range desc.Publications {
+		if desc.Publications[i].Name == name {
+			desc.Publications = append(desc.Publications[:i], desc.Publications[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// GetReplicationSlot returns the replication slot of the database with the
+// given name.
+func (desc *immutable) GetReplicationSlot(
+	name string,
+) (descpb.DatabaseDescriptor_ReplicationSlot, bool) {
+	i := sort.Search(len(desc.ReplicationSlots), func(i int) bool {
+		return desc.ReplicationSlots[i].Name >= name
+	})
+	if i < len(desc.ReplicationSlots) && desc.ReplicationSlots[i].Name == name {
+		return desc.ReplicationSlots[i], true
+	}
+	return descpb.DatabaseDescriptor_ReplicationSlot{}, false
+}
+
+// SetReplicationSlot adds a replication slot to the database, or replaces the
+// one with the same name.
+func (desc *Mutable) SetReplicationSlot(slot descpb.DatabaseDescriptor_ReplicationSlot) {
+	i := sort.Search(len(desc.ReplicationSlots), func(i int) bool {
+		return desc.ReplicationSlots[i].Name >= slot.Name
+	})
+	if i < len(desc.ReplicationSlots) && desc.ReplicationSlots[i].Name == slot.Name {
+		desc.ReplicationSlots[i] = slot
+		return
+	}
+	desc.ReplicationSlots = append(desc.ReplicationSlots, descpb.DatabaseDescriptor_ReplicationSlot{})
+	copy(desc.ReplicationSlots[i+1:], desc.ReplicationSlots[i:])
+	desc.ReplicationSlots[i] = slot
+}
+
+// RemoveReplicationSlot removes the replication slot with the given name from
+// the database, and returns whether it existed.
+func (desc *Mutable) RemoveReplicationSlot(name string) bool {
+	for i := range desc.ReplicationSlots {
+		if desc.ReplicationSlots[i].Name == name {
+			desc.ReplicationSlots = append(desc.ReplicationSlots[:i], desc.ReplicationSlots[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// GetForeignServer returns the foreign server of the database with the given
+// name.
+func (desc *immutable) GetForeignServer(
+	name string,
+) (descpb.DatabaseDescriptor_ForeignServer, bool) {
+	i := sort.Search(len(desc.ForeignServers), func(i int) bool {
+		return desc.ForeignServers[i].Name >= name
+	})
+	if i < len(desc.ForeignServers) && desc.ForeignServers[i].Name == name {
+		return desc.ForeignServers[i], true
+	}
+	return descpb.DatabaseDescriptor_ForeignServer{}, false
+}
+
+// AddForeignServer adds a foreign server to the database. The database must
+// not have a foreign server with the same name.
+func (desc *Mutable) AddForeignServer(server descpb.DatabaseDescriptor_ForeignServer) {
+	i := sort.Search(len(desc.ForeignServers), func(i int) bool {
+		return desc.ForeignServers[i].Name >= server.Name
+	})
+	desc.ForeignServers = append(desc.ForeignServers, descpb.DatabaseDescriptor_ForeignServer{})
+	copy(desc.ForeignServers[i+1:], desc.ForeignServers[i:])
+	desc.ForeignServers[i] = server
+}
+
+// RemoveForeignServer removes the foreign server with the given name from the
+// database, and returns whether it existed.
+func (desc *Mutable) RemoveForeignServer(name string) bool {
+	for i := range desc.ForeignServers {
+		if desc.ForeignServers[i].Name == name {
+			desc.ForeignServers = append(desc.ForeignServers[:i], desc.ForeignServers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
 // maybeRemoveDroppedSelfEntryFromSchemas removes an entry in the Schemas map corresponding to the
 // database itself which was added due to a bug in prior versions when dropping any user-defined schema.
 // The bug inserted an entry for the database rather than the schema being dropped. This function fixes the
@@ -83,6 +83,22 @@ func (x ForeignKeyReference_Action) String() string {
 	}
 }
 
+// ConstraintDeferrabilityType allows the conversion from a
+// ConstraintDeferrability to a tree.ConstraintDeferrability.
+var ConstraintDeferrabilityType = [...]tree.ConstraintDeferrability{
+	ConstraintDeferrability_NOT_DEFERRABLE:                 tree.NotDeferrable,
+	ConstraintDeferrability_DEFERRABLE_INITIALLY_IMMEDIATE: tree.DeferrableInitiallyImmediate,
+	ConstraintDeferrability_DEFERRABLE_INITIALLY_DEFERRED:  tree.DeferrableInitiallyDeferred,
+}
+
+// ConstraintDeferrabilityValue allows the conversion from a
+// tree.ConstraintDeferrability to a ConstraintDeferrability.
+var ConstraintDeferrabilityValue = [...]ConstraintDeferrability{
+	tree.NotDeferrable:                ConstraintDeferrability_NOT_DEFERRABLE,
+	tree.DeferrableInitiallyImmediate: ConstraintDeferrability_DEFERRABLE_INITIALLY_IMMEDIATE,
+	tree.DeferrableInitiallyDeferred:  ConstraintDeferrability_DEFERRABLE_INITIALLY_DEFERRED,
+}
+
 // ConstraintType is used to identify the type of a constraint.
 type ConstraintType string
 
@@ -120,3 +136,16 @@ type ConstraintDetail struct {
 	// Only populated for Check Constraints.
 	CheckConstraint *TableDescriptor_CheckConstraint
 }
+
+// Deferrability returns whether the validation of the constraint can be
+// deferred until the end of the transaction. Only foreign key and unique
+// without index constraints can be deferrable.
+func (c *ConstraintDetail) Deferrability() tree.ConstraintDeferrability {
+	switch {
+	case c.FK != nil:
+		return ConstraintDeferrabilityType[c.FK.Deferrability]
+	case c.UniqueWithoutIndexConstraint != nil:
+		return ConstraintDeferrabilityType[c.UniqueWithoutIndexConstraint.Deferrability]
+	}
+	return tree.NotDeferrable
+}
@@ -0,0 +1,34 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package descpb
+
+import "github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+
+// PolicyCommandValue allows the conversion from a tree.PolicyCommand to a
+// PolicyDescriptor_Command.
+var PolicyCommandValue = [...]PolicyDescriptor_Command{
+	tree.PolicyCommandAll:    PolicyDescriptor_ALL,
+	tree.PolicyCommandSelect: PolicyDescriptor_SELECT,
+	tree.PolicyCommandInsert: PolicyDescriptor_INSERT,
+	tree.PolicyCommandUpdate: PolicyDescriptor_UPDATE,
+	tree.PolicyCommandDelete: PolicyDescriptor_DELETE,
+}
+
+// PolicyDescriptorCommand allows the conversion from a
+// PolicyDescriptor_Command to a tree.PolicyCommand. This should match
+// PolicyCommandValue.
+var PolicyDescriptorCommand = [...]tree.PolicyCommand{
+	PolicyDescriptor_ALL:    tree.PolicyCommandAll,
+	PolicyDescriptor_SELECT: tree.PolicyCommandSelect,
+	PolicyDescriptor_INSERT: tree.PolicyCommandInsert,
+	PolicyDescriptor_UPDATE: tree.PolicyCommandUpdate,
+	PolicyDescriptor_DELETE: tree.PolicyCommandDelete,
+}
@@ -75,6 +75,18 @@ type FamilyID uint32
 // SafeValue implements the redact.SafeValue interface.
 func (FamilyID) SafeValue() {}
 
+// TriggerID is a custom type for TriggerDescriptor IDs.
+type TriggerID uint32
+
+// SafeValue implements the redact.SafeValue interface.
+func (TriggerID) SafeValue() {}
+
+// PolicyID is a custom type for PolicyDescriptor IDs.
+type PolicyID uint32
+
+// SafeValue implements the redact.SafeValue interface.
+func (PolicyID) SafeValue() {}
+
 // IndexID is a custom type for IndexDescriptor IDs.
 type IndexID tree.IndexID
 
@@ -277,6 +289,32 @@ func (desc *TableDescriptor) IsSequence() bool {
 	return desc.SequenceOpts != nil
 }
 
+// IsForeignTable returns true if the TableDescriptor describes a foreign
+// table, whose rows are read from external files. Foreign tables are also
+// tables, whose spans stay empty.
+func (desc *TableDescriptor) IsForeignTable() bool {
+	return desc.Foreign != nil
+}
+
+// HasRowLevelTTL returns true if the rows of the table expire and are deleted
+// by a scheduled job.
+func (desc *TableDescriptor) HasRowLevelTTL() bool {
+	return desc.RowLevelTTL != nil
+}
+
+// DefaultRowLevelTTLDeletionCron is the default schedule of the jobs deleting
+// the expired rows of tables with row-level TTL.
+const DefaultRowLevelTTLDeletionCron = "@hourly"
+
+// DeletionCronOrDefault returns the cron expression of the schedule of the
+// job deleting the expired rows.
+func (ttl *TableDescriptor_RowLevelTTL) DeletionCronOrDefault() string {
+	if ttl.DeletionCron != "" {
+		return ttl.DeletionCron
+	}
+	return DefaultRowLevelTTLDeletionCron
+}
+
 // IsVirtualTable returns true if the TableDescriptor describes a
 // virtual Table (like the information_schema tables) and thus doesn't
 // need to be physically stored.
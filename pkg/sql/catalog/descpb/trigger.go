@@ -0,0 +1,54 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package descpb
+
+import "github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+
+// TriggerActionTimeValue allows the conversion from a tree.TriggerActionTime
+// to a TriggerDescriptor_ActionTime.
+var TriggerActionTimeValue = [...]TriggerDescriptor_ActionTime{
+	tree.TriggerActionTimeBefore: TriggerDescriptor_BEFORE,
+	tree.TriggerActionTimeAfter:  TriggerDescriptor_AFTER,
+}
+
+// TriggerDescriptorActionTime allows the conversion from a
+// TriggerDescriptor_ActionTime to a tree.TriggerActionTime. This should match
+// TriggerActionTimeValue.
+var TriggerDescriptorActionTime = [...]tree.TriggerActionTime{
+	TriggerDescriptor_BEFORE: tree.TriggerActionTimeBefore,
+	TriggerDescriptor_AFTER:  tree.TriggerActionTimeAfter,
+}
+
+// TriggerEventValue allows the conversion from a tree.TriggerEvent to a
+// TriggerDescriptor_Event.
+var TriggerEventValue = [...]TriggerDescriptor_Event{
+	tree.TriggerEventInsert: TriggerDescriptor_INSERT,
+	tree.TriggerEventUpdate: TriggerDescriptor_UPDATE,
+	tree.TriggerEventDelete: TriggerDescriptor_DELETE,
+}
+
+// TriggerDescriptorEvent allows the conversion from a TriggerDescriptor_Event
+// to a tree.TriggerEvent. This should match TriggerEventValue.
+var TriggerDescriptorEvent = [...]tree.TriggerEvent{
+	TriggerDescriptor_INSERT: tree.TriggerEventInsert,
+	TriggerDescriptor_UPDATE: tree.TriggerEventUpdate,
+	TriggerDescriptor_DELETE: tree.TriggerEventDelete,
+}
+
+// HasEvent returns true if the trigger fires for the given event.
+func (desc *TriggerDescriptor) HasEvent(event TriggerDescriptor_Event) bool {
+	for _, e := range desc.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
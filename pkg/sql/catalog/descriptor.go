@@ -44,6 +44,9 @@ const (
 
 	// Schema is for schema descriptors.
 	Schema = "schema"
+
+	// Function is for function descriptors.
+	Function = "function"
 )
 
 // MutationPublicationFilter is used by MakeFirstMutationPublic to filter the
@@ -180,6 +183,12 @@ type DatabaseDescriptor interface {
 	GetSchemaID(name string) descpb.ID
 	GetNonDroppedSchemaName(schemaID descpb.ID) string
 	GetDefaultPrivilegeDescriptor() DefaultPrivilegeDescriptor
+	GetPublications() []descpb.DatabaseDescriptor_Publication
+	GetPublication(name string) (descpb.DatabaseDescriptor_Publication, bool)
+	GetReplicationSlots() []descpb.DatabaseDescriptor_ReplicationSlot
+	GetReplicationSlot(name string) (descpb.DatabaseDescriptor_ReplicationSlot, bool)
+	GetForeignServers() []descpb.DatabaseDescriptor_ForeignServer
+	GetForeignServer(name string) (descpb.DatabaseDescriptor_ForeignServer, bool)
 }
 
 // TableDescriptor is an interface around the table descriptor types.
@@ -190,6 +199,8 @@ type TableDescriptor interface {
 
 	GetState() descpb.DescriptorState
 	GetSequenceOpts() *descpb.TableDescriptor_SequenceOpts
+	GetForeign() *descpb.TableDescriptor_ForeignTableOptions
+	GetRowLevelTTL() *descpb.TableDescriptor_RowLevelTTL
 	GetCreateQuery() string
 	GetViewQuery() string
 	GetLease() *descpb.TableDescriptor_SchemaChangeLease
@@ -309,6 +320,8 @@ type TableDescriptor interface {
 	IsTable() bool
 	IsView() bool
 	IsSequence() bool
+	IsForeignTable() bool
+	HasRowLevelTTL() bool
 	IsTemporary() bool
 	IsVirtualTable() bool
 	IsPhysicalTable() bool
@@ -344,6 +357,12 @@ type TableDescriptor interface {
 	AllActiveAndInactiveForeignKeys() []*descpb.ForeignKeyConstraint
 	GetInboundFKs() []descpb.ForeignKeyConstraint
 	GetOutboundFKs() []descpb.ForeignKeyConstraint
+	GetTriggers() []descpb.TriggerDescriptor
+	GetNextTriggerID() descpb.TriggerID
+	GetPolicies() []descpb.PolicyDescriptor
+	GetNextPolicyID() descpb.PolicyID
+	GetRowLevelSecurity() bool
+	GetForceRowLevelSecurity() bool
 
 	GetLocalityConfig() *descpb.TableDescriptor_LocalityConfig
 	IsLocalityRegionalByRow() bool
@@ -388,6 +407,28 @@ type TypeDescriptor interface {
 	GetReferencingDescriptorID(refOrdinal int) descpb.ID
 }
 
+// FunctionDescriptor is an interface around the function descriptor types.
+type FunctionDescriptor interface {
+	Descriptor
+	FuncDesc() *descpb.FunctionDescriptor
+
+	// GetParams returns the parameters of the function.
+	GetParams() []descpb.FunctionDescriptor_Param
+	// GetReturnType returns the type of the value returned by the function.
+	GetReturnType() *types.T
+	// GetVolatility returns the declared volatility of the function.
+	GetVolatility() descpb.FunctionDescriptor_Volatility
+	// Volatility returns the declared volatility of the function as a
+	// tree.Volatility.
+	Volatility() tree.Volatility
+	// GetLeakProof returns true if the function is declared LEAKPROOF.
+	GetLeakProof() bool
+	// GetNullInputBehavior returns how the function behaves on NULL input.
+	GetNullInputBehavior() descpb.FunctionDescriptor_NullInputBehavior
+	// GetFunctionBody returns the SQL text of the body of the function.
+	GetFunctionBody() string
+}
+
 // TypeDescriptorResolver is an interface used during hydration of type
 // metadata in types.T's. It is similar to tree.TypeReferenceResolver, except
 // that it has the power to return TypeDescriptor, rather than only a
@@ -97,6 +97,10 @@ func (tc *Collection) getObjectByName(
 		if flags.DesiredObjectKind != tree.TypeObject {
 			return prefix, nil, nil
 		}
+	case catalog.FunctionDescriptor:
+		if flags.DesiredObjectKind != tree.FunctionObject {
+			return prefix, nil, nil
+		}
 	default:
 		return prefix, nil, errors.AssertionFailedf(
 			"unexpected object of type %T", t,
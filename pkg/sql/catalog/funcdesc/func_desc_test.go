@@ -0,0 +1,143 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package funcdesc_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/redact"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSafeMessage(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	desc := funcdesc.NewBuilder(&descpb.FunctionDescriptor{
+		ID:             52,
+		Version:        1,
+		ParentID:       50,
+		ParentSchemaID: keys.PublicSchemaID,
+		State:          descpb.DescriptorState_PUBLIC,
+	}).BuildImmutable()
+	require.Equal(t,
+		`funcdesc.immutable: {ID: 52, Version: 1, ModificationTime: "0,0", ParentID: 50, ParentSchemaID: 29, State: PUBLIC}`,
+		string(redact.Sprint(desc).Redact()),
+	)
+}
+
+func TestValidateFunctionDescriptor(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	valid := func() descpb.FunctionDescriptor {
+		return descpb.FunctionDescriptor{
+			ID:             52,
+			ParentID:       51,
+			ParentSchemaID: keys.PublicSchemaID,
+			Name:           "f",
+			Params: []descpb.FunctionDescriptor_Param{
+				{Name: "a", Type: types.Int},
+			},
+			ReturnType:   types.Int,
+			FunctionBody: "SELECT a + 1",
+		}
+	}
+
+	tests := []struct {
+		err  string
+		desc func() descpb.FunctionDescriptor
+	}{
+		{ // 0
+			desc: valid,
+		},
+		{ // 1
+			err: `empty function name`,
+			desc: func() descpb.FunctionDescriptor {
+				d := valid()
+				d.Name = ""
+				return d
+			},
+		},
+		{ // 2
+			err: `missing return type`,
+			desc: func() descpb.FunctionDescriptor {
+				d := valid()
+				d.ReturnType = nil
+				return d
+			},
+		},
+		{ // 3
+			err: `missing type for parameter 1`,
+			desc: func() descpb.FunctionDescriptor {
+				d := valid()
+				d.Params[0].Type = nil
+				return d
+			},
+		},
+		{ // 4
+			err: `missing function body`,
+			desc: func() descpb.FunctionDescriptor {
+				d := valid()
+				d.FunctionBody = ""
+				return d
+			},
+		},
+		{ // 5
+			err: `referenced database ID 500: referenced descriptor not found`,
+			desc: func() descpb.FunctionDescriptor {
+				d := valid()
+				d.ParentID = 500
+				return d
+			},
+		},
+		{ // 6
+			err: `referenced schema ID 600: referenced descriptor not found`,
+			desc: func() descpb.FunctionDescriptor {
+				d := valid()
+				d.ParentSchemaID = 600
+				return d
+			},
+		},
+	}
+
+	for i, test := range tests {
+		privilege := descpb.NewDefaultPrivilegeDescriptor(security.AdminRoleName())
+		descs := catalog.MakeMapDescGetter()
+		fnDesc := test.desc()
+		fnDesc.Privileges = privilege
+		desc := funcdesc.NewBuilder(&fnDesc).BuildImmutable()
+		descs.Descriptors[fnDesc.ID] = desc
+		descs.Descriptors[51] = dbdesc.NewBuilder(&descpb.DatabaseDescriptor{
+			ID:         51,
+			Name:       "db",
+			Privileges: privilege,
+		}).BuildImmutable()
+		expectedErr := fmt.Sprintf("%s %q (%d): %s", desc.DescriptorType(), desc.GetName(), desc.GetID(), test.err)
+		results := catalog.Validate(ctx, descs, catalog.NoValidationTelemetry, catalog.ValidationLevelCrossReferences, desc)
+		if err := results.CombinedError(); err == nil {
+			if test.err != "" {
+				t.Errorf("%d: expected \"%s\", but found success", i, expectedErr)
+			}
+		} else if expectedErr != err.Error() {
+			t.Errorf("%d: expected \"%s\", but found \"%s\"", i, expectedErr, err.Error())
+		}
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkeys"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catconstants"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
@@ -174,6 +175,37 @@ func ResolveMutableType(
 	return prefix, desc.(*typedesc.Mutable), nil
 }
 
+// ResolveFunction resolves a user-defined function descriptor.
+func ResolveFunction(
+	ctx context.Context, sc SchemaResolver, un *tree.UnresolvedObjectName, required bool,
+) (catalog.ResolvedObjectPrefix, catalog.FunctionDescriptor, error) {
+	lookupFlags := tree.ObjectLookupFlags{
+		CommonLookupFlags: tree.CommonLookupFlags{Required: required},
+		DesiredObjectKind: tree.FunctionObject,
+	}
+	desc, prefix, err := ResolveExistingObject(ctx, sc, un, lookupFlags)
+	if err != nil || desc == nil {
+		return prefix, nil, err
+	}
+	return prefix, desc.(catalog.FunctionDescriptor), nil
+}
+
+// ResolveMutableFunction resolves a user-defined function descriptor for
+// mutable access.
+func ResolveMutableFunction(
+	ctx context.Context, sc SchemaResolver, un *tree.UnresolvedObjectName, required bool,
+) (catalog.ResolvedObjectPrefix, *funcdesc.Mutable, error) {
+	lookupFlags := tree.ObjectLookupFlags{
+		CommonLookupFlags: tree.CommonLookupFlags{Required: required, RequireMutable: true},
+		DesiredObjectKind: tree.FunctionObject,
+	}
+	desc, prefix, err := ResolveExistingObject(ctx, sc, un, lookupFlags)
+	if err != nil || desc == nil {
+		return prefix, nil, err
+	}
+	return prefix, desc.(*funcdesc.Mutable), nil
+}
+
 // ResolveExistingObject resolves an object with the given flags.
 func ResolveExistingObject(
 	ctx context.Context,
@@ -218,6 +250,12 @@ func ResolveExistingObject(
 			return obj.(*typedesc.Mutable), prefix, nil
 		}
 		return typ, prefix, nil
+	case tree.FunctionObject:
+		fn, ok := obj.(catalog.FunctionDescriptor)
+		if !ok {
+			return nil, prefix, sqlerrors.NewUndefinedFunctionError(resolvedTn.String())
+		}
+		return fn, prefix, nil
 	case tree.TableObject:
 		table, ok := obj.(catalog.TableDescriptor)
 		if !ok {
@@ -374,7 +374,7 @@ func SanitizeVarFreeExpr(
 	defer semaCtx.Properties.Restore(semaCtx.Properties)
 
 	// Ensure that the expression doesn't contain special functions.
-	flags := tree.RejectSpecial
+	flags := tree.RejectSpecial | tree.RejectUserDefinedFunctions
 
 	switch maxVolatility {
 	case tree.VolatilityImmutable:
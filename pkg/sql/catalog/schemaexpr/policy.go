@@ -0,0 +1,76 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package schemaexpr
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// ValidatePolicyExpr validates that a USING or WITH CHECK expression of a
+// row-level security policy is a boolean expression which only refers to the
+// columns of the table, and contains no volatile functions or subqueries. The
+// dequalified and serialized expression is returned if it is valid.
+func ValidatePolicyExpr(
+	ctx context.Context,
+	desc catalog.TableDescriptor,
+	expr tree.Expr,
+	context string,
+	semaCtx *tree.SemaContext,
+	tn *tree.TableName,
+) (string, error) {
+	ret, _, _, err := DequalifyAndValidateExpr(
+		ctx, desc, expr, types.Bool, context, semaCtx, tree.VolatilityStable, tn,
+	)
+	return ret, err
+}
+
+// ValidateColumnHasNoPolicyDependents verifies that the input column is not
+// referenced by the expressions of the row-level security policies of the
+// table.
+func ValidateColumnHasNoPolicyDependents(desc catalog.TableDescriptor, col catalog.Column) error {
+	policies := desc.GetPolicies()
+	for i := range policies {
+		p := &policies[i]
+		for _, s := range []string{p.UsingExpr, p.WithCheckExpr} {
+			if s == "" {
+				continue
+			}
+			expr, err := parser.ParseExpr(s)
+			if err != nil {
+				// At this point, we should be able to parse the policy expression.
+				return errors.WithAssertionFailure(err)
+			}
+			err = iterColDescriptors(desc, expr, func(colVar catalog.Column) error {
+				if colVar.GetID() == col.GetID() {
+					return pgerror.Newf(
+						pgcode.DependentObjectsStillExist,
+						"column %q is referenced by policy %q",
+						col.GetName(),
+						p.Name,
+					)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package schemaexpr
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// Names of the qualifiers through which the expressions of a trigger refer to
+// the new and old versions of the row that fired the trigger.
+const (
+	TriggerNewRowName = "new"
+	TriggerOldRowName = "old"
+)
+
+// ValidateTriggerExpr validates that a WHEN condition or an assignment of a
+// trigger has the given type. Each column reference in the expression must be
+// qualified with NEW or OLD and refer to a column of the table. The serialized
+// expression is returned, with its qualifiers intact, if it is valid.
+func ValidateTriggerExpr(
+	ctx context.Context,
+	desc catalog.TableDescriptor,
+	rootExpr tree.Expr,
+	typ *types.T,
+	context string,
+	semaCtx *tree.SemaContext,
+) (string, error) {
+	replacedExpr, err := tree.SimpleVisit(rootExpr, func(expr tree.Expr) (recurse bool, newExpr tree.Expr, err error) {
+		vBase, ok := expr.(tree.VarName)
+		if !ok {
+			// Not a VarName, don't do anything to this node.
+			return true, expr, nil
+		}
+
+		v, err := vBase.NormalizeVarName()
+		if err != nil {
+			return false, nil, err
+		}
+
+		c, ok := v.(*tree.ColumnItem)
+		if !ok {
+			return true, expr, nil
+		}
+
+		if c.TableName == nil || c.TableName.NumParts != 1 ||
+			(c.TableName.Parts[0] != TriggerNewRowName && c.TableName.Parts[0] != TriggerOldRowName) {
+			return false, nil, pgerror.Newf(pgcode.UndefinedColumn,
+				"column reference %q in %s must be qualified with NEW or OLD",
+				tree.ErrString(c), context)
+		}
+
+		col, err := desc.FindColumnWithName(c.ColumnName)
+		if err != nil || col.Dropped() {
+			return false, nil, pgerror.Newf(pgcode.UndefinedColumn,
+				"column %q does not exist, referenced in %q", c.ColumnName, rootExpr.String())
+		}
+		if col.IsInaccessible() {
+			return false, nil, pgerror.Newf(pgcode.UndefinedColumn,
+				"column %q is inaccessible and cannot be referenced", c.ColumnName)
+		}
+
+		// Convert to a dummyColumn of the correct type.
+		return false, &dummyColumn{typ: col.GetType(), name: c.ColumnName}, nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := SanitizeVarFreeExpr(
+		ctx, replacedExpr, typ, context, semaCtx, tree.VolatilityVolatile,
+	); err != nil {
+		return "", err
+	}
+
+	return tree.Serialize(rootExpr), nil
+}
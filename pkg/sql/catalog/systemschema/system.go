@@ -616,6 +616,38 @@ CREATE TABLE system.span_configurations (
     CONSTRAINT check_bounds CHECK (start_key < end_key),
     FAMILY "primary" (start_key, end_key, config)
 )`
+
+	// notifications stores the payloads of NOTIFY statements so that they can
+	// be fanned out to listening sessions on every node using a rangefeed.
+	// created_idx lets the periodic cleanup find expired notifications without
+	// scanning the whole table.
+	NotificationsTableSchema = `
+CREATE TABLE system.notifications (
+    id        INT8      DEFAULT unique_rowid() PRIMARY KEY,
+    created   TIMESTAMP NOT NULL DEFAULT now(),
+    channel   STRING    NOT NULL,
+    payload   STRING    NOT NULL,
+    pid       INT4      NOT NULL,
+    INDEX created_idx (created),
+    FAMILY "primary" (id, created, channel, payload, pid)
+)`
+
+	// advisory_locks holds the advisory locks acquired with pg_advisory_lock()
+	// and friends. Rows are only ever written by transactions which are rolled
+	// back when the lock is released, so the table is always empty: a lock is
+	// held for as long as its intent is.
+	AdvisoryLocksTableSchema = `
+CREATE TABLE system.advisory_locks (
+    database_id      INT8  NOT NULL,
+    class_id         INT8  NOT NULL,
+    obj_id           INT8  NOT NULL,
+    obj_sub_id       INT8  NOT NULL,
+    session_id       BYTES NOT NULL,
+    claim_session_id BYTES NOT NULL,
+    waiter_id        BYTES NOT NULL,
+    PRIMARY KEY (database_id, class_id, obj_id, obj_sub_id, waiter_id),
+    FAMILY "primary" (database_id, class_id, obj_id, obj_sub_id, session_id, claim_session_id, waiter_id)
+)`
 )
 
 func pk(name string) descpb.IndexDescriptor {
@@ -2231,6 +2263,87 @@ var (
 		},
 	)
 
+	// NotificationsTable is the descriptor for the notifications table. It
+	// holds the notifications produced by NOTIFY and pg_notify() until they are
+	// garbage collected.
+	NotificationsTable = registerSystemTable(
+		NotificationsTableSchema,
+		systemTable(
+			catconstants.NotificationsTableName,
+			keys.NotificationsTableID,
+			[]descpb.ColumnDescriptor{
+				{Name: "id", ID: 1, Type: types.Int, DefaultExpr: &uniqueRowIDString},
+				{Name: "created", ID: 2, Type: types.Timestamp, DefaultExpr: &nowString},
+				{Name: "channel", ID: 3, Type: types.String},
+				{Name: "payload", ID: 4, Type: types.String},
+				{Name: "pid", ID: 5, Type: types.Int4},
+			},
+			[]descpb.ColumnFamilyDescriptor{
+				{
+					Name:        "primary",
+					ID:          0,
+					ColumnNames: []string{"id", "created", "channel", "payload", "pid"},
+					ColumnIDs:   []descpb.ColumnID{1, 2, 3, 4, 5},
+				},
+			},
+			pk("id"),
+			descpb.IndexDescriptor{
+				Name:                "created_idx",
+				ID:                  2,
+				Unique:              false,
+				KeyColumnNames:      []string{"created"},
+				KeyColumnDirections: []descpb.IndexDescriptor_Direction{descpb.IndexDescriptor_ASC},
+				KeyColumnIDs:        []descpb.ColumnID{2},
+				KeySuffixColumnIDs:  []descpb.ColumnID{1},
+				Version:             descpb.StrictIndexColumnIDGuaranteesVersion,
+			},
+		))
+
+	// AdvisoryLocksTable is the descriptor for the advisory_locks table. The
+	// locks held by a session are the intents of its lock transactions on the
+	// table, whose waiter_id is empty. While a session waits for a lock, its
+	// wait is recorded by the intent of another transaction on a row of the
+	// lock whose waiter_id is the session's ID, which is used to list waiting
+	// requests in pg_locks and to detect deadlocks.
+	AdvisoryLocksTable = registerSystemTable(
+		AdvisoryLocksTableSchema,
+		systemTable(
+			catconstants.AdvisoryLocksTableName,
+			keys.AdvisoryLocksTableID,
+			[]descpb.ColumnDescriptor{
+				{Name: "database_id", ID: 1, Type: types.Int},
+				{Name: "class_id", ID: 2, Type: types.Int},
+				{Name: "obj_id", ID: 3, Type: types.Int},
+				{Name: "obj_sub_id", ID: 4, Type: types.Int},
+				{Name: "session_id", ID: 5, Type: types.Bytes},
+				{Name: "claim_session_id", ID: 6, Type: types.Bytes},
+				{Name: "waiter_id", ID: 7, Type: types.Bytes},
+			},
+			[]descpb.ColumnFamilyDescriptor{
+				{
+					Name: "primary",
+					ID:   0,
+					ColumnNames: []string{
+						"database_id", "class_id", "obj_id", "obj_sub_id", "session_id", "claim_session_id",
+						"waiter_id",
+					},
+					ColumnIDs: []descpb.ColumnID{1, 2, 3, 4, 5, 6, 7},
+				},
+			},
+			descpb.IndexDescriptor{
+				Name:           tabledesc.PrimaryKeyIndexName,
+				ID:             1,
+				Unique:         true,
+				KeyColumnNames: []string{"database_id", "class_id", "obj_id", "obj_sub_id", "waiter_id"},
+				KeyColumnDirections: []descpb.IndexDescriptor_Direction{
+					descpb.IndexDescriptor_ASC, descpb.IndexDescriptor_ASC,
+					descpb.IndexDescriptor_ASC, descpb.IndexDescriptor_ASC,
+					descpb.IndexDescriptor_ASC,
+				},
+				KeyColumnIDs: []descpb.ColumnID{1, 2, 3, 4, 7},
+			},
+		))
+
 	// UnleasableSystemDescriptors contains the system descriptors which cannot
 	// be leased. This includes the lease table itself, among others.
 	UnleasableSystemDescriptors = func(s []catalog.Descriptor) map[descpb.ID]catalog.Descriptor {
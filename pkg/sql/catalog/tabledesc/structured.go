@@ -1125,6 +1125,50 @@ func (desc *Mutable) AddFamily(fam descpb.ColumnFamilyDescriptor) {
 	desc.Families = append(desc.Families, fam)
 }
 
+// AddTrigger adds a trigger to the table, allocating its ID.
+func (desc *Mutable) AddTrigger(trigger descpb.TriggerDescriptor) {
+	if desc.NextTriggerID == 0 {
+		desc.NextTriggerID = 1
+	}
+	trigger.ID = desc.NextTriggerID
+	desc.NextTriggerID++
+	desc.Triggers = append(desc.Triggers, trigger)
+}
+
+// DropTrigger removes the trigger with the given name from the table. It
+// returns false if there is no such trigger.
+func (desc *Mutable) DropTrigger(name string) bool {
+	for i := range desc.Triggers {
+		if desc.Triggers[i].Name == name {
+			desc.Triggers = append(desc.Triggers[:i], desc.Triggers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// AddPolicy adds a row-level security policy to the table, allocating its ID.
+func (desc *Mutable) AddPolicy(policy descpb.PolicyDescriptor) {
+	if desc.NextPolicyID == 0 {
+		desc.NextPolicyID = 1
+	}
+	policy.ID = desc.NextPolicyID
+	desc.NextPolicyID++
+	desc.Policies = append(desc.Policies, policy)
+}
+
+// DropPolicy removes the policy with the given name from the table. It
+// returns false if there is no such policy.
+func (desc *Mutable) DropPolicy(name string) bool {
+	for i := range desc.Policies {
+		if desc.Policies[i].Name == name {
+			desc.Policies = append(desc.Policies[:i], desc.Policies[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
 // AddPrimaryIndex adds a primary index to a mutable table descriptor, assuming
 // that none has yet been set, and performs some sanity checks.
 func (desc *Mutable) AddPrimaryIndex(idx descpb.IndexDescriptor) error {
@@ -515,6 +515,21 @@ func (desc *wrapper) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
 		return
 	}
 
+	if desc.IsForeignTable() {
+		if desc.Foreign.Server == "" {
+			vea.Report(errors.AssertionFailedf("foreign table %q has no server", desc.Name))
+		}
+		if len(desc.Indexes) > 0 || desc.IsView() {
+			vea.Report(errors.AssertionFailedf(
+				"foreign table %q has secondary indexes or a view query", desc.Name))
+		}
+	}
+
+	if desc.HasRowLevelTTL() && (!desc.IsTable() || desc.IsForeignTable()) {
+		vea.Report(errors.AssertionFailedf(
+			"row-level TTL is only supported on tables, but %q is not a stored table", desc.Name))
+	}
+
 	// We maintain forward compatibility, so if you see this error message with a
 	// version older that what this client supports, then there's a
 	// maybeFillInDescriptor missing from some codepath.
@@ -556,6 +571,8 @@ func (desc *wrapper) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
 			desc.validateColumnFamilies(columnIDs),
 			desc.validateCheckConstraints(columnIDs),
 			desc.validateUniqueWithoutIndexConstraints(columnIDs),
+			desc.validateTriggers(columnIDs),
+			desc.validatePolicies(),
 			desc.validateTableIndexes(columnNames),
 			desc.validatePartitioning(),
 		}
@@ -949,6 +966,90 @@ func (desc *wrapper) validateUniqueWithoutIndexConstraints(
 	return nil
 }
 
+// validateTriggers validates that triggers are well formed. Checks include
+// validating the trigger names and IDs are unique, and that the columns
+// assigned to by BEFORE triggers exist.
+func (desc *wrapper) validateTriggers(
+	columnIDs map[descpb.ColumnID]*descpb.ColumnDescriptor,
+) error {
+	names := make(map[string]struct{}, len(desc.Triggers))
+	ids := make(map[descpb.TriggerID]string, len(desc.Triggers))
+	for i := range desc.Triggers {
+		t := &desc.Triggers[i]
+		if err := catalog.ValidateName(t.Name, "trigger"); err != nil {
+			return err
+		}
+		if _, ok := names[t.Name]; ok {
+			return errors.Newf("duplicate trigger name: %q", t.Name)
+		}
+		names[t.Name] = struct{}{}
+		if t.ID == 0 {
+			return errors.Newf("invalid trigger ID %d", t.ID)
+		}
+		if other, ok := ids[t.ID]; ok {
+			return errors.Newf("trigger %q duplicate ID of trigger %q: %d", t.Name, other, t.ID)
+		}
+		ids[t.ID] = t.Name
+		if t.ID >= desc.NextTriggerID {
+			return errors.AssertionFailedf("trigger %q invalid ID (%d) > next trigger ID (%d)",
+				t.Name, t.ID, desc.NextTriggerID)
+		}
+		if len(t.Events) == 0 {
+			return errors.Newf("trigger %q has no events", t.Name)
+		}
+		switch t.ActionTime {
+		case descpb.TriggerDescriptor_BEFORE:
+			if t.Body != "" || len(t.Assignments) == 0 {
+				return errors.Newf("BEFORE trigger %q must only have assignments", t.Name)
+			}
+		case descpb.TriggerDescriptor_AFTER:
+			if t.Body == "" || len(t.Assignments) != 0 {
+				return errors.Newf("AFTER trigger %q must only have a body", t.Name)
+			}
+		default:
+			return errors.Newf("trigger %q has unknown action time %d", t.Name, t.ActionTime)
+		}
+		for _, a := range t.Assignments {
+			if _, ok := columnIDs[a.ColumnID]; !ok {
+				return errors.Newf("trigger %q assigns to unknown column \"%d\"", t.Name, a.ColumnID)
+			}
+		}
+	}
+	return nil
+}
+
+// validatePolicies validates that row-level security policies are well formed.
+// Checks include validating the policy names and IDs are unique.
+func (desc *wrapper) validatePolicies() error {
+	names := make(map[string]struct{}, len(desc.Policies))
+	ids := make(map[descpb.PolicyID]string, len(desc.Policies))
+	for i := range desc.Policies {
+		p := &desc.Policies[i]
+		if err := catalog.ValidateName(p.Name, "policy"); err != nil {
+			return err
+		}
+		if _, ok := names[p.Name]; ok {
+			return errors.Newf("duplicate policy name: %q", p.Name)
+		}
+		names[p.Name] = struct{}{}
+		if p.ID == 0 {
+			return errors.Newf("invalid policy ID %d", p.ID)
+		}
+		if other, ok := ids[p.ID]; ok {
+			return errors.Newf("policy %q duplicate ID of policy %q: %d", p.Name, other, p.ID)
+		}
+		ids[p.ID] = p.Name
+		if p.ID >= desc.NextPolicyID {
+			return errors.AssertionFailedf("policy %q invalid ID (%d) > next policy ID (%d)",
+				p.Name, p.ID, desc.NextPolicyID)
+		}
+		if _, ok := descpb.PolicyDescriptor_Command_name[int32(p.Command)]; !ok {
+			return errors.Newf("policy %q has unknown command %d", p.Name, p.Command)
+		}
+	}
+	return nil
+}
+
 // validateTableIndexes validates that indexes are well formed. Checks include
 // validating the columns involved in the index, verifying the index names and
 // IDs are unique, and the family of the primary key is 0. This does not check
@@ -1199,6 +1300,11 @@ func (desc *wrapper) validatePartitioningDescriptor(
 	if part.NumLists() > 0 && part.NumRanges() > 0 {
 		return errors.Newf("only one LIST or RANGE partitioning may used")
 	}
+	if part.PartitioningDesc().Interval != nil {
+		if part.NumLists() > 0 || part.NumColumns() != 1 || colOffset != 0 {
+			return errors.Newf("INTERVAL partitioning must be a RANGE partitioning on a single column")
+		}
+	}
 
 	// Do not validate partitions which use unhydrated user-defined types.
 	// This should only happen at read time and descriptors should not become
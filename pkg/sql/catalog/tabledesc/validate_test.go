@@ -122,10 +122,18 @@ var validationMap = []struct {
 			"OutboundFKs":                   {status: iSolemnlySwearThisFieldIsValidated},
 			"InboundFKs":                    {status: iSolemnlySwearThisFieldIsValidated},
 			"UniqueWithoutIndexConstraints": {status: iSolemnlySwearThisFieldIsValidated},
+			"Triggers":                      {status: iSolemnlySwearThisFieldIsValidated},
+			"NextTriggerID":                 {status: iSolemnlySwearThisFieldIsValidated},
 			"Temporary":                     {status: thisFieldReferencesNoObjects},
 			"LocalityConfig":                {status: iSolemnlySwearThisFieldIsValidated},
 			"PartitionAllBy":                {status: iSolemnlySwearThisFieldIsValidated},
+			"Foreign":                       {status: iSolemnlySwearThisFieldIsValidated},
+			"RowLevelTTL":                   {status: iSolemnlySwearThisFieldIsValidated},
 			"NewSchemaChangeJobID":          {status: iSolemnlySwearThisFieldIsValidated},
+			"Policies":                      {status: iSolemnlySwearThisFieldIsValidated},
+			"NextPolicyID":                  {status: iSolemnlySwearThisFieldIsValidated},
+			"RowLevelSecurity":              {status: thisFieldReferencesNoObjects},
+			"ForceRowLevelSecurity":         {status: thisFieldReferencesNoObjects},
 		},
 	},
 	{
@@ -212,16 +220,18 @@ var validationMap = []struct {
 			"OnDelete":          {status: thisFieldReferencesNoObjects},
 			"OnUpdate":          {status: thisFieldReferencesNoObjects},
 			"Match":             {status: thisFieldReferencesNoObjects},
+			"Deferrability":     {status: thisFieldReferencesNoObjects},
 		},
 	},
 	{
 		obj: descpb.UniqueWithoutIndexConstraint{},
 		fieldMap: map[string]validationStatusInfo{
-			"TableID":   {status: iSolemnlySwearThisFieldIsValidated},
-			"ColumnIDs": {status: iSolemnlySwearThisFieldIsValidated},
-			"Name":      {status: thisFieldReferencesNoObjects},
-			"Validity":  {status: thisFieldReferencesNoObjects},
-			"Predicate": {status: iSolemnlySwearThisFieldIsValidated},
+			"TableID":       {status: iSolemnlySwearThisFieldIsValidated},
+			"ColumnIDs":     {status: iSolemnlySwearThisFieldIsValidated},
+			"Name":          {status: thisFieldReferencesNoObjects},
+			"Validity":      {status: thisFieldReferencesNoObjects},
+			"Predicate":     {status: iSolemnlySwearThisFieldIsValidated},
+			"Deferrability": {status: thisFieldReferencesNoObjects},
 		},
 	},
 	{
@@ -259,6 +269,9 @@ var validationMap = []struct {
 			"OfflineReason":     {status: thisFieldReferencesNoObjects},
 			"RegionConfig":      {status: iSolemnlySwearThisFieldIsValidated},
 			"DefaultPrivileges": {status: iSolemnlySwearThisFieldIsValidated},
+			"ForeignServers":    {status: iSolemnlySwearThisFieldIsValidated},
+			"ReplicationSlots":  {status: iSolemnlySwearThisFieldIsValidated},
+			"Publications":      {status: iSolemnlySwearThisFieldIsValidated},
 		},
 	},
 	{
@@ -1308,6 +1321,51 @@ func TestValidateTableDesc(t *testing.T) {
 				},
 				NextColumnID: 2,
 			}},
+		{`duplicate policy name: "p"`,
+			descpb.TableDescriptor{
+				ID:            2,
+				ParentID:      1,
+				Name:          "foo",
+				FormatVersion: descpb.InterleavedFormatVersion,
+				Columns: []descpb.ColumnDescriptor{
+					{ID: 1, Name: "bar"},
+				},
+				Families: []descpb.ColumnFamilyDescriptor{
+					{ID: 0, Name: "primary",
+						ColumnIDs:   []descpb.ColumnID{1},
+						ColumnNames: []string{"bar"},
+					},
+				},
+				NextColumnID: 2,
+				NextFamilyID: 1,
+				Policies: []descpb.PolicyDescriptor{
+					{ID: 1, Name: "p", UsingExpr: "bar > 0"},
+					{ID: 2, Name: "p", UsingExpr: "bar < 0"},
+				},
+				NextPolicyID: 3,
+			}},
+		{`policy "p" invalid ID (1) > next policy ID (1)`,
+			descpb.TableDescriptor{
+				ID:            2,
+				ParentID:      1,
+				Name:          "foo",
+				FormatVersion: descpb.InterleavedFormatVersion,
+				Columns: []descpb.ColumnDescriptor{
+					{ID: 1, Name: "bar"},
+				},
+				Families: []descpb.ColumnFamilyDescriptor{
+					{ID: 0, Name: "primary",
+						ColumnIDs:   []descpb.ColumnID{1},
+						ColumnNames: []string{"bar"},
+					},
+				},
+				NextColumnID: 2,
+				NextFamilyID: 1,
+				Policies: []descpb.PolicyDescriptor{
+					{ID: 1, Name: "p", UsingExpr: "bar > 0"},
+				},
+				NextPolicyID: 1,
+			}},
 	}
 	for i, d := range testData {
 		t.Run(d.err, func(t *testing.T) {
@@ -53,3 +53,19 @@ func BytesToClusterWideID(b []byte) ClusterWideID {
 func (id ClusterWideID) GetNodeID() int32 {
 	return int32(0xFFFFFFFF & id.Lo)
 }
+
+// BackendPID returns the process ID reported to clients for the session
+// identified by id, in the BackendKeyData message sent during the connection
+// handshake and in the notifications the session sends. Clients expect a
+// positive 32-bit integer, so the ID is folded into 11 bits of node ID and 20
+// bits of timestamp. Unlike the ID itself, the result is not guaranteed to be
+// unique, although collisions between concurrent sessions are unlikely.
+func (id ClusterWideID) BackendPID() int32 {
+	ts := uint32(id.Hi) ^ uint32(id.Hi>>32) ^ uint32(id.Lo>>32)
+	pid := int32((uint32(id.GetNodeID())&0x7ff)<<20 | ts&0xfffff)
+	if pid == 0 {
+		// Postgres never reports a process ID of 0.
+		pid = 1
+	}
+	return pid
+}
@@ -21,6 +21,7 @@ import (
 	"time"
 	"unicode/utf8"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/kv"
@@ -28,6 +29,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/advisorylock"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catconstants"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
@@ -35,6 +37,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
 	"github.com/cockroachdb/cockroach/pkg/sql/execstats"
 	"github.com/cockroachdb/cockroach/pkg/sql/idxusage"
+	"github.com/cockroachdb/cockroach/pkg/sql/notifications"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
@@ -51,6 +54,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlstats/persistedsqlstats"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlstats/sslocal"
 	"github.com/cockroachdb/cockroach/pkg/sql/stmtdiagnostics"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/cancelchecker"
 	"github.com/cockroachdb/cockroach/pkg/util/envutil"
@@ -627,6 +631,9 @@ func (s *Server) SetupConn(
 		ctx, sdMutIterator, stmtBuf, clientComm, memMetrics, &s.Metrics,
 		s.sqlStats.GetApplicationStats(sd.ApplicationName),
 	)
+	// The session ID is generated upfront so that the process ID derived from
+	// it can be sent to the client during the connection handshake.
+	ex.sessionID = ex.generateID()
 	return ConnectionHandler{ex}, nil
 }
 
@@ -637,6 +644,11 @@ type ConnectionHandler struct {
 	ex *connExecutor
 }
 
+// BackendPID returns the process ID reported to the client for the session.
+func (h ConnectionHandler) BackendPID() int32 {
+	return h.ex.sessionID.BackendPID()
+}
+
 // GetParamStatus retrieves the configured value of the session
 // variable identified by varName. This is used for the initial
 // message sent to a client during a session set-up.
@@ -909,6 +921,7 @@ func (s *Server) newConnExecutorWithTxn(
 		txn.ReadTimestamp().GoTime(),
 		nil, /* historicalTimestamp */
 		roachpb.UnspecifiedUserPriority,
+		enginepb.SERIALIZABLE,
 		tree.ReadWrite,
 		txn,
 		ex.transitionCtx)
@@ -1048,6 +1061,14 @@ func (ex *connExecutor) close(ctx context.Context, closeType closeType) {
 		ex.eventLog = nil
 	}
 
+	if ex.notificationsListener != nil {
+		ex.notificationsListener.Close()
+	}
+
+	if ex.advisoryLocks != nil {
+		ex.advisoryLocks.Close(ctx)
+	}
+
 	// Stop idle timer if the connExecutor is closed to ensure cancel session
 	// is not called.
 	ex.mu.IdleInSessionTimeout.Stop()
@@ -1281,6 +1302,20 @@ type connExecutor struct {
 		// createdSequences keeps track of sequences created in the current transaction.
 		// The map key is the sequence descpb.ID.
 		createdSequences map[descpb.ID]struct{}
+
+		// sqlCursors contains the list of SQL CURSORs the session currently has
+		// access to. Cursors are bound to a transaction and they're all closed
+		// once the transaction finishes.
+		sqlCursors cursorMap
+
+		// listenOps contains the LISTEN and UNLISTEN statements executed in the
+		// current transaction. They are applied when the transaction commits and
+		// discarded otherwise.
+		listenOps []listenOp
+
+		// deferredConstraints contains the modes set by SET CONSTRAINTS and the
+		// constraint checks deferred until the transaction commits.
+		deferredConstraints deferredConstraints
 	}
 
 	// sessionDataStack contains the user-configurable connection variables.
@@ -1375,6 +1410,15 @@ type connExecutor struct {
 	// temporary schema, which requires special cleanup on close.
 	hasCreatedTemporarySchema bool
 
+	// notificationsListener buffers the notifications sent on the channels the
+	// session is listening on. It is nil until the session first listens on a
+	// channel.
+	notificationsListener *notifications.Listener
+
+	// advisoryLocks holds the advisory locks acquired by the session. It is nil
+	// until the session first acquires a lock.
+	advisoryLocks *advisorylock.Session
+
 	// stmtDiagnosticsRecorder is used to track which queries need to have
 	// information collected.
 	stmtDiagnosticsRecorder *stmtdiagnostics.Registry
@@ -1494,6 +1538,22 @@ func (ex *connExecutor) resetExtraTxnState(ctx context.Context, ev txnEvent) err
 
 	ex.extraTxnState.descCollection.ReleaseAll(ctx)
 
+	// Close all cursors.
+	ex.extraTxnState.sqlCursors.closeAll()
+
+	if ev == txnCommit {
+		ex.applyListenOps()
+	}
+	ex.extraTxnState.listenOps = nil
+
+	ex.extraTxnState.deferredConstraints.reset()
+
+	// Transaction-level advisory locks are released when the transaction
+	// finishes, whether it commits or not.
+	if ex.advisoryLocks != nil {
+		ex.advisoryLocks.ReleaseXactLocks(ctx)
+	}
+
 	// Close all portals.
 	for name, p := range ex.extraTxnState.prepStmtsNamespace.portals {
 		p.decRef(ctx, &ex.extraTxnState.prepStmtsNamespaceMemAcc, name)
@@ -1630,7 +1690,9 @@ func (ex *connExecutor) run(
 	ex.ctxHolder.connCtx = ctx
 	ex.onCancelSession = onCancel
 
-	ex.sessionID = ex.generateID()
+	if ex.sessionID == (ClusterWideID{}) {
+		ex.sessionID = ex.generateID()
+	}
 	ex.server.cfg.SessionRegistry.register(ex.sessionID, ex)
 	ex.planner.extendedEvalCtx.setSessionID(ex.sessionID)
 	defer ex.server.cfg.SessionRegistry.deregister(ex.sessionID)
@@ -1826,7 +1888,9 @@ func (ex *connExecutor) execCmd(ctx context.Context) error {
 		payload = eventNonRetriableErrPayload{err: tcmd.Err}
 	case Sync:
 		// Note that the Sync result will flush results to the network connection.
-		res = ex.clientComm.CreateSyncResult(pos)
+		syncRes := ex.clientComm.CreateSyncResult(pos)
+		ex.bufferNotifications(syncRes)
+		res = syncRes
 		if ex.draining {
 			// If we're draining, check whether this is a good time to finish the
 			// connection. If we're not inside a transaction, we stop processing
@@ -1846,6 +1910,13 @@ func (ex *connExecutor) execCmd(ctx context.Context) error {
 		if err != nil {
 			return err
 		}
+	case StartReplication:
+		res = ex.clientComm.CreateCopyInResult(pos)
+		var err error
+		ev, payload, err = ex.execStartReplication(ctx, tcmd)
+		if err != nil {
+			return err
+		}
 	case DrainRequest:
 		// We received a drain request. We terminate immediately if we're not in a
 		// transaction. If we are in a transaction, we'll finish as soon as a Sync
@@ -1859,6 +1930,12 @@ func (ex *connExecutor) execCmd(ctx context.Context) error {
 	case Flush:
 		// Closing the res will flush the connection's buffer.
 		res = ex.clientComm.CreateFlushResult(pos)
+	case DeliverNotifications:
+		// Closing the res will flush the notifications along with the
+		// connection's buffer.
+		flushRes := ex.clientComm.CreateFlushResult(pos)
+		ex.bufferNotifications(flushRes)
+		res = flushRes
 	default:
 		panic(errors.AssertionFailedf("unsupported command type: %T", cmd))
 	}
@@ -1918,11 +1995,15 @@ func (ex *connExecutor) execCmd(ctx context.Context) error {
 			return err
 		}
 	case rewind:
-		ex.rewindPrepStmtNamespace(ctx)
-		ex.extraTxnState.savepoints = ex.extraTxnState.rewindPosSnapshot.savepoints
-		// Note we use the Replace function instead of reassigning, as there are
-		// copies of the ex.sessionDataStack in the iterators and extendedEvalContext.
-		ex.sessionDataStack.Replace(ex.extraTxnState.rewindPosSnapshot.sessionDataStack)
+		// Statement retries rewind to the current statement, so only transaction
+		// retries restore the state snapshotted at the rewind position.
+		if advInfo.txnEvent == txnRestart {
+			ex.rewindPrepStmtNamespace(ctx)
+			ex.extraTxnState.savepoints = ex.extraTxnState.rewindPosSnapshot.savepoints
+			// Note we use the Replace function instead of reassigning, as there are
+			// copies of the ex.sessionDataStack in the iterators and extendedEvalContext.
+			ex.sessionDataStack.Replace(ex.extraTxnState.rewindPosSnapshot.sessionDataStack)
+		}
 		advInfo.rewCap.rewindAndUnlock(ctx)
 	case stayInPlace:
 		// Nothing to do. The same statement will be executed again.
@@ -2010,6 +2091,10 @@ func (ex *connExecutor) updateTxnRewindPosMaybe(
 		// if the rewind point is not current set to the command's position
 		// (i.e. we don't do anything if txnRewindPos != pos).
 
+		if advInfo.code == rewind {
+			// A statement is being retried; txnRewindPos stays unchanged.
+			return nil
+		}
 		if advInfo.code != advanceOne {
 			panic(errors.AssertionFailedf("unexpected advanceCode: %s", advInfo.code))
 		}
@@ -2037,10 +2122,14 @@ func (ex *connExecutor) updateTxnRewindPosMaybe(
 				canAdvance = true
 			case CopyIn:
 				// Can't advance.
+			case StartReplication:
+				// Can't advance.
 			case DrainRequest:
 				canAdvance = true
 			case Flush:
 				canAdvance = true
+			case DeliverNotifications:
+				canAdvance = true
 			default:
 				panic(errors.AssertionFailedf("unsupported cmd: %T", cmd))
 			}
@@ -2250,8 +2339,12 @@ func (ex *connExecutor) execCopyIn(
 }
 
 // stmtHasNoData returns true if describing a result of the input statement
-// type should return NoData.
+// type should return NoData. COPY ... TO STDOUT returns rows, but sends them
+// using the Copy-out subprotocol instead of DataRow messages.
 func stmtHasNoData(stmt tree.Statement) bool {
+	if _, ok := stmt.(*tree.CopyTo); ok {
+		return true
+	}
 	return stmt == nil || stmt.StatementReturnType() != tree.Rows
 }
 
@@ -2299,6 +2392,45 @@ func (ex *connExecutor) getRewindTxnCapability() (rewindCapability, bool) {
 	}, true
 }
 
+// maybeMakeStmtRetryEvent handles the retryable errors which only prepared
+// the transaction for a retry of the statement which encountered them, as is
+// done for READ COMMITTED transactions. If the statement can be rewound, it
+// returns an event that will retry the statement alone in the same
+// transaction. Otherwise, it restarts the KV transaction so that the error can
+// be handled like any other retryable error, and returns ok == false.
+func (ex *connExecutor) maybeMakeStmtRetryEvent(
+	err error, stmt tree.Statement,
+) (_ fsm.Event, _ fsm.EventPayload, ok bool) {
+	var retryErr *roachpb.TransactionRetryWithProtoRefreshError
+	if !errors.As(err, &retryErr) || !retryErr.StatementRetry {
+		return nil, nil, false
+	}
+	// Rolling back a statement does not undo its schema changes, so
+	// transactions which have performed DDL are retried from the start.
+	if ex.extraTxnState.numDDL == 0 && !isCommit(stmt) {
+		if _, pos, curErr := ex.stmtBuf.CurCmd(); curErr == nil {
+			cl := ex.clientComm.LockCommunication()
+			// If we already delivered results for the statement, we can't rewind.
+			if cl.ClientPos() < pos {
+				ex.extraTxnState.autoRetryReason = err
+				ev := eventRetriableErr{
+					IsCommit:     fsm.False,
+					CanAutoRetry: fsm.True,
+				}
+				payload := eventRetriableErrPayload{
+					err:       err,
+					rewCap:    rewindCapability{cl: cl, buf: ex.stmtBuf, rewindPos: pos},
+					stmtRetry: true,
+				}
+				return ev, payload, true
+			}
+			cl.Close()
+		}
+	}
+	ex.state.mu.txn.PrepareForRetry(ex.Ctx(), err)
+	return nil, nil, false
+}
+
 // isCommit returns true if stmt is a "COMMIT" statement.
 func isCommit(stmt tree.Statement) bool {
 	_, ok := stmt.(*tree.CommitTransaction)
@@ -2347,6 +2479,9 @@ func (ex *connExecutor) makeErrEvent(err error, stmt tree.Statement) (fsm.Event,
 
 	retriable := errIsRetriable(err)
 	if retriable {
+		if ev, payload, ok := ex.maybeMakeStmtRetryEvent(err, stmt); ok {
+			return ev, payload
+		}
 		rc, canAutoRetry := ex.getRewindTxnCapability()
 
 		if canAutoRetry {
@@ -2386,9 +2521,10 @@ func (ex *connExecutor) setTransactionModes(
 			return err
 		}
 	}
-	if modes.Isolation != tree.UnspecifiedIsolation && modes.Isolation != tree.SerializableIsolation {
-		return errors.AssertionFailedf(
-			"unknown isolation level: %s", errors.Safe(modes.Isolation))
+	if modes.Isolation != tree.UnspecifiedIsolation {
+		if err := ex.state.setIsolationLevel(ex.txnIsoLevelToProto(modes.Isolation)); err != nil {
+			return err
+		}
 	}
 	rwMode := modes.ReadWriteMode
 	if modes.AsOf.Expr != nil && asOfTs.IsEmpty() {
@@ -2433,6 +2569,33 @@ func (ex *connExecutor) txnPriorityWithSessionDefault(mode tree.UserPriority) ro
 	return txnPriorityToProto(mode)
 }
 
+// txnIsoLevelToProto returns the KV isolation level for the given SQL
+// isolation level. Transactions run as SERIALIZABLE until all nodes understand
+// READ COMMITTED.
+func (ex *connExecutor) txnIsoLevelToProto(level tree.IsolationLevel) enginepb.IsolationLevel {
+	switch level {
+	case tree.UnspecifiedIsolation, tree.SerializableIsolation:
+		return enginepb.SERIALIZABLE
+	case tree.ReadCommittedIsolation:
+		if !ex.server.cfg.Settings.Version.IsActive(ex.Ctx(), clusterversion.ReadCommittedIsolation) {
+			return enginepb.SERIALIZABLE
+		}
+		return enginepb.READ_COMMITTED
+	default:
+		log.Fatalf(context.Background(), "unknown isolation level: %s", level)
+	}
+	return enginepb.SERIALIZABLE
+}
+
+func (ex *connExecutor) txnIsoLevelWithSessionDefault(
+	level tree.IsolationLevel,
+) enginepb.IsolationLevel {
+	if level == tree.UnspecifiedIsolation {
+		level = tree.IsolationLevel(ex.sessionData().DefaultTxnIsolationLevel)
+	}
+	return ex.txnIsoLevelToProto(level)
+}
+
 func (ex *connExecutor) readWriteModeWithSessionDefault(
 	mode tree.ReadWriteMode,
 ) tree.ReadWriteMode {
@@ -2492,6 +2655,7 @@ func (ex *connExecutor) initEvalCtx(ctx context.Context, evalCtx *extendedEvalCo
 			JoinTokenCreator:          p,
 			Gossip:                    p,
 			PreparedStatementState:    &ex.extraTxnState.prepStmtsNamespace,
+			DeferredConstraints:       connExDeferredConstraintsAccessor{ex: ex},
 			SessionDataStack:          ex.sessionDataStack,
 			ReCache:                   ex.server.reCache,
 			InternalExecutor:          &ie,
@@ -2580,6 +2744,12 @@ func (ex *connExecutor) initPlanner(ctx context.Context, p *planner) {
 	p.noticeSender = nil
 	p.preparedStatements = ex.getPrepStmtsAccessor()
 	p.createdSequences = ex.getCreatedSequencesAccessor()
+	p.sqlCursors = connExCursorAccessor{ex: ex}
+	p.listenOps = &ex.extraTxnState.listenOps
+	p.deferredConstraints = &ex.extraTxnState.deferredConstraints
+	if ex.server.cfg.AdvisoryLockManager != nil {
+		p.advisoryLocks = ex.getAdvisoryLockSession
+	}
 
 	p.queryCacheSession.Init()
 	p.optPlanningCtx.init(p)
@@ -2601,6 +2771,7 @@ func (ex *connExecutor) resetPlanner(
 	p.semaCtx.Annotations = nil
 	p.semaCtx.TypeResolver = p
 	p.semaCtx.TableNameResolver = p
+	p.semaCtx.FunctionResolver = p
 	p.semaCtx.DateStyle = ex.sessionData().GetDateStyle()
 	p.semaCtx.IntervalStyle = ex.sessionData().GetIntervalStyle()
 
@@ -688,6 +688,11 @@ func (ex *connExecutor) execStmtInOpenState(
 	if err := ex.state.mu.txn.Step(ctx); err != nil {
 		return makeErrEvent(err)
 	}
+	// Under READ COMMITTED, each statement reads from a fresh snapshot. This is
+	// a no-op for SERIALIZABLE transactions.
+	if err := ex.state.mu.txn.StepReadTimestamp(ctx); err != nil {
+		return makeErrEvent(err)
+	}
 
 	if err := p.semaCtx.Placeholders.Assign(pinfo, stmt.NumPlaceholders); err != nil {
 		return makeErrEvent(err)
@@ -870,6 +875,18 @@ func (ex *connExecutor) reportSessionDataChanges(fn func() error) error {
 func (ex *connExecutor) commitSQLTransactionInternal(
 	ctx context.Context, ast tree.Statement,
 ) error {
+	// Close all cursors before committing, since the flows backing them are
+	// still running in this transaction.
+	ex.extraTxnState.sqlCursors.closeAll()
+
+	// Validate the constraint checks which were deferred until the end of the
+	// transaction.
+	if err := ex.planner.validateDeferredConstraintChecks(
+		ctx, ex.extraTxnState.deferredConstraints.takeAll(),
+	); err != nil {
+		return err
+	}
+
 	if err := ex.createJobs(ctx); err != nil {
 		return err
 	}
@@ -1014,6 +1031,12 @@ func (ex *connExecutor) dispatchToExecutionEngine(
 	if stmt.AST.StatementReturnType() == tree.Rows {
 		cols = planner.curPlan.main.planColumns()
 	}
+	if copyTo, ok := stmt.AST.(*tree.CopyTo); ok {
+		if err := planner.initCopyOutResult(ctx, copyTo, cols, res); err != nil {
+			res.SetError(err)
+			return nil
+		}
+	}
 	if err := ex.initStatementResult(ctx, res, stmt.AST, cols); err != nil {
 		res.SetError(err)
 		return nil
@@ -1488,6 +1511,7 @@ func (ex *connExecutor) execStmtInNoTxnState(
 		return eventTxnStart{ImplicitTxn: fsm.False},
 			makeEventTxnStartPayload(
 				ex.txnPriorityWithSessionDefault(s.Modes.UserPriority),
+				ex.txnIsoLevelWithSessionDefault(s.Modes.Isolation),
 				mode,
 				sqlTs,
 				historicalTs,
@@ -1508,6 +1532,7 @@ func (ex *connExecutor) execStmtInNoTxnState(
 		return eventTxnStart{ImplicitTxn: fsm.True},
 			makeEventTxnStartPayload(
 				ex.txnPriorityWithSessionDefault(tree.UnspecifiedUserPriority),
+				ex.txnIsoLevelWithSessionDefault(tree.UnspecifiedIsolation),
 				mode,
 				sqlTs,
 				historicalTs,
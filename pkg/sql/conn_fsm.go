@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlfsm"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/util/fsm"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 )
@@ -101,7 +102,8 @@ type eventTxnStart struct {
 type eventTxnStartPayload struct {
 	tranCtx transitionCtx
 
-	pri roachpb.UserPriority
+	pri      roachpb.UserPriority
+	isoLevel enginepb.IsolationLevel
 	// txnSQLTimestamp is the timestamp that statements executed in the
 	// transaction that is started by this event will report for now(),
 	// current_timestamp(), transaction_timestamp().
@@ -113,6 +115,7 @@ type eventTxnStartPayload struct {
 // makeEventTxnStartPayload creates an eventTxnStartPayload.
 func makeEventTxnStartPayload(
 	pri roachpb.UserPriority,
+	isoLevel enginepb.IsolationLevel,
 	readOnly tree.ReadWriteMode,
 	txnSQLTimestamp time.Time,
 	historicalTimestamp *hlc.Timestamp,
@@ -120,6 +123,7 @@ func makeEventTxnStartPayload(
 ) eventTxnStartPayload {
 	return eventTxnStartPayload{
 		pri:                 pri,
+		isoLevel:            isoLevel,
 		readOnly:            readOnly,
 		txnSQLTimestamp:     txnSQLTimestamp,
 		historicalTimestamp: historicalTimestamp,
@@ -166,6 +170,10 @@ type eventRetriableErrPayload struct {
 	// rewCap must be set if CanAutoRetry is set on the event. It will be passed
 	// back to the connExecutor to perform the rewind.
 	rewCap rewindCapability
+	// stmtRetry is set if only the statement that encountered the error is
+	// retried, in which case rewCap points to that statement and the
+	// transaction is not restarted.
+	stmtRetry bool
 }
 
 // errorCause implements the payloadWithError interface.
@@ -280,11 +288,13 @@ var TxnStateTransitions = fsm.Compile(fsm.Pattern{
 			Description: "Retriable err; will auto-retry",
 			Next:        stateOpen{ImplicitTxn: fsm.Var("implicitTxn")},
 			Action: func(args fsm.Args) error {
+				payload := args.Payload.(eventRetriableErrPayload)
+				ev := txnRestart
+				if payload.stmtRetry {
+					ev = noEvent
+				}
 				// The caller will call rewCap.rewindAndUnlock().
-				args.Extended.(*txnState).setAdvanceInfo(
-					rewind,
-					args.Payload.(eventRetriableErrPayload).rewCap,
-					txnRestart)
+				args.Extended.(*txnState).setAdvanceInfo(rewind, payload.rewCap, ev)
 				return nil
 			},
 		},
@@ -451,6 +461,7 @@ func noTxnToOpen(args fsm.Args) error {
 		payload.txnSQLTimestamp,
 		payload.historicalTimestamp,
 		payload.pri,
+		payload.isoLevel,
 		payload.readOnly,
 		nil, /* txn */
 		payload.tranCtx,
@@ -19,6 +19,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/notifications"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
@@ -337,6 +338,28 @@ func (c CopyIn) String() string {
 
 var _ Command = CopyIn{}
 
+// StartReplication is the command for streaming the changes of a logical
+// replication slot over a replication connection, with the Copy-both pgwire
+// subprotocol.
+type StartReplication struct {
+	Stmt *tree.StartReplication
+	// Conn is the network connection. Execution of the START_REPLICATION
+	// command takes control of the connection.
+	Conn pgwirebase.Conn
+	// ReplicationDone is decremented once streaming finishes, signaling that
+	// control of the connection is being handed back to the network routine.
+	ReplicationDone *sync.WaitGroup
+}
+
+// command implements the Command interface.
+func (StartReplication) command() string { return "start replication" }
+
+func (c StartReplication) String() string {
+	return fmt.Sprintf("StartReplication: %s", c.Stmt)
+}
+
+var _ Command = StartReplication{}
+
 // DrainRequest represents a notice that the server is draining and command
 // processing should stop soon.
 //
@@ -369,6 +392,21 @@ func (s SendError) String() string {
 
 var _ Command = SendError{}
 
+// DeliverNotifications is a command asking for the notifications received on
+// the channels the session is listening on to be delivered to the client. It
+// is pushed by the session's notifications listener, so that notifications
+// are delivered to idle connections without waiting for the next statement.
+type DeliverNotifications struct{}
+
+// command implements the Command interface.
+func (DeliverNotifications) command() string { return "deliver notifications" }
+
+func (DeliverNotifications) String() string {
+	return "DeliverNotifications"
+}
+
+var _ Command = DeliverNotifications{}
+
 // NewStmtBuf creates a StmtBuf.
 func NewStmtBuf() *StmtBuf {
 	var buf StmtBuf
@@ -791,12 +829,22 @@ type DeleteResult interface {
 // flushed.
 type SyncResult interface {
 	ResultBase
+	NotificationResult
 }
 
 // FlushResult represents the result of a Flush command. When this result is
 // closed, all previously accumulated results are flushed to the client.
 type FlushResult interface {
 	ResultBase
+	NotificationResult
+}
+
+// NotificationResult is the subset of the results which can carry the
+// notifications sent on the channels the session is listening on.
+type NotificationResult interface {
+	// BufferNotification appends a notification to the result.
+	// This gets flushed only when the result is closed.
+	BufferNotification(n notifications.Notification)
 }
 
 // DrainResult represents the result of a Drain command. Closing this result
@@ -817,6 +865,15 @@ type CopyInResult interface {
 	ResultBase
 }
 
+// CopyOutResult is implemented by the results of statements that can send
+// their rows to the client using the Copy-out subprotocol (COPY ... TO STDOUT).
+type CopyOutResult interface {
+	// SetCopyOut makes the result encode rows in the given format, as CopyData
+	// messages preceded by a CopyOutResponse and followed by a CopyDone, instead
+	// of DataRow messages. It must be called before SetColumns.
+	SetCopyOut(format CopyOutFormat)
+}
+
 // ClientLock is an interface returned by ClientComm.lockCommunication(). It
 // represents a lock on the delivery of results to a SQL client. While such a
 // lock is used, no more results are delivered. The lock itself can be used to
@@ -909,6 +966,11 @@ func (r *streamingCommandResult) BufferParamStatusUpdate(key string, val string)
 	panic("unimplemented")
 }
 
+// BufferNotification is part of the NotificationResult interface.
+func (r *streamingCommandResult) BufferNotification(notifications.Notification) {
+	// Internal executor sessions never listen for notifications.
+}
+
 // BufferNotice is part of the RestrictedCommandResult interface.
 func (r *streamingCommandResult) BufferNotice(notice pgnotice.Notice) {
 	panic("unimplemented")
@@ -61,7 +61,9 @@ type copyMachine struct {
 	resultColumns colinfo.ResultColumns
 	format        tree.CopyFormat
 	csvEscape     rune
-	delimiter     byte
+	// csvQuote is the character enclosing quoted CSV values.
+	csvQuote  byte
+	delimiter byte
 	// textDelim is delimiter converted to a []byte so that we don't have to do that per row.
 	textDelim   []byte
 	null        string
@@ -70,8 +72,17 @@ type copyMachine struct {
 	// NULL. The spec says this is only supported for CSV, and also must specify
 	// which columns it applies to.
 	forceNotNull bool
-	csvInput     bytes.Buffer
-	csvReader    *csv.Reader
+	// csvForceNotNull and csvForceNull have an entry for every column and are
+	// set by the FORCE NOT NULL and FORCE NULL options of the CSV format: the
+	// former disables converting values matching the null string to NULL in a
+	// column, the latter also converts quoted values matching the null string.
+	csvForceNotNull []bool
+	csvForceNull    []bool
+	// csvHeader is set if the first line of CSV input is a header line that is
+	// to be skipped. It is cleared once the header line has been read.
+	csvHeader bool
+	csvInput  bytes.Buffer
+	csvReader *csv.Reader
 	// buf is used to parse input data into rows. It also accumulates a partial
 	// row between protocol messages.
 	buf bytes.Buffer
@@ -144,6 +155,7 @@ func newCopyMachine(
 	case tree.CopyFormatCSV:
 		c.null = ""
 		c.delimiter = ','
+		c.csvQuote = '"'
 	}
 
 	if n.Options.Delimiter != nil {
@@ -194,6 +206,37 @@ func newCopyMachine(
 
 		c.csvEscape, _ = utf8.DecodeRuneInString(s)
 	}
+	if n.Options.Quote != nil {
+		if c.format != tree.CopyFormatCSV {
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported, "QUOTE can only be specified for CSV")
+		}
+		s := n.Options.Quote.RawString()
+		if len(s) != 1 {
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported, "QUOTE must be a single one-byte character")
+		}
+		c.csvQuote = s[0]
+		if c.csvQuote == c.delimiter {
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue, "COPY delimiter and quote must be different")
+		}
+	}
+	if n.Options.Header {
+		if c.format != tree.CopyFormatCSV {
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported, "HEADER can only be specified for CSV")
+		}
+		c.csvHeader = true
+	}
+	if n.Options.ForceQuoteAll || len(n.Options.ForceQuote) > 0 {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported, "FORCE QUOTE can only be used with COPY TO")
+	}
+	if (len(n.Options.ForceNotNull) > 0 || len(n.Options.ForceNull) > 0) && c.format != tree.CopyFormatCSV {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"FORCE NOT NULL and FORCE NULL can only be specified for CSV")
+	}
+	if err := checkCopyEncoding(n.Options.Encoding); err != nil {
+		return nil, err
+	}
+	// FREEZE is accepted for compatibility, but has no effect: rows are always
+	// written with their regular MVCC timestamps.
 
 	flags := tree.ObjectLookupFlagsWithRequiredTableKind(tree.ResolveRequireTableDesc)
 	_, tableDesc, err := resolver.ResolveExistingTableObject(ctx, &c.p, &n.Table, flags)
@@ -217,12 +260,65 @@ func newCopyMachine(
 			PGAttributeNum: col.GetPGAttributeNum(),
 		}
 	}
+	if c.csvForceNotNull, err = copyColumnFlags(
+		"FORCE NOT NULL", n.Options.ForceNotNull, c.resultColumns,
+	); err != nil {
+		return nil, err
+	}
+	if c.csvForceNull, err = copyColumnFlags(
+		"FORCE NULL", n.Options.ForceNull, c.resultColumns,
+	); err != nil {
+		return nil, err
+	}
 	c.rowsMemAcc = c.p.extendedEvalCtx.Mon.MakeBoundAccount()
 	c.bufMemAcc = c.p.extendedEvalCtx.Mon.MakeBoundAccount()
 	c.processRows = c.insertRows
 	return c, nil
 }
 
+// checkCopyEncoding returns an error unless the ENCODING option of a COPY
+// statement, if specified, names UTF8, the only supported encoding.
+func checkCopyEncoding(encoding *tree.StrVal) error {
+	if encoding == nil {
+		return nil
+	}
+	// Encoding names are matched like in Postgres, ignoring case and
+	// non-alphanumeric characters.
+	name := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, strings.ToLower(encoding.RawString()))
+	if name != "utf8" && name != "unicode" {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"unsupported encoding %q, only UTF8 is supported", encoding.RawString())
+	}
+	return nil
+}
+
+// copyColumnFlags returns a slice with an entry for every column, set for the
+// columns named in the given option of a COPY statement.
+func copyColumnFlags(
+	option string, names tree.NameList, cols colinfo.ResultColumns,
+) ([]bool, error) {
+	flags := make([]bool, len(cols))
+	for _, name := range names {
+		found := false
+		for i := range cols {
+			if cols[i].Name == string(name) {
+				flags[i] = true
+				found = true
+			}
+		}
+		if !found {
+			return nil, pgerror.Newf(pgcode.InvalidColumnReference,
+				"%s column %q not referenced by COPY", option, name)
+		}
+	}
+	return flags, nil
+}
+
 func (c *copyMachine) numInsertedRows() int {
 	if c == nil {
 		return 0
@@ -277,6 +373,9 @@ func (c *copyMachine) run(ctx context.Context) error {
 		c.csvReader.Comma = rune(c.delimiter)
 		c.csvReader.ReuseRecord = true
 		c.csvReader.FieldsPerRecord = len(c.resultColumns)
+		c.csvReader.Quote = rune(c.csvQuote)
+		// The escape character defaults to the quote character.
+		c.csvReader.Escape = rune(c.csvQuote)
 		if c.csvEscape != 0 {
 			c.csvReader.Escape = c.csvEscape
 		}
@@ -456,22 +555,18 @@ func (c *copyMachine) readCSVData(ctx context.Context, final bool) (brk bool, er
 
 		// Now we need to calculate if we are have reached the end of the quote.
 		// If so, break out.
-		if c.csvEscape == 0 {
-			// CSV escape is not specified and hence defaults to '"'.¥
+		if c.csvEscape == 0 || c.csvEscape == rune(c.csvQuote) {
+			// CSV escape is not specified and hence defaults to the QUOTE char.
 			// At this point, we know fullLine ends in '\n'. Keep track of the total
 			// number of QUOTE chars in fullLine -- if it is even, then it means that
 			// the quotes are balanced and '\n' is not in a quoted field.
-			// Currently, the QUOTE char and ESCAPE char are both always equal to '"'
-			// and are not configurable. As per the COPY spec, any appearance of the
-			// QUOTE or ESCAPE characters in an actual value must be preceded by an
-			// ESCAPE character. This means that an escaped '"' also results in an even
-			// number of '"' characters.
-			// This branch is kept in the interests of "backporting safely" - this
-			// was the old code. Users who use COPY ... ESCAPE will be the only
-			// ones hitting the new code below.
-			quoteCharsSeen += bytes.Count(line, []byte{'"'})
+			// As per the COPY spec, any appearance of the QUOTE or ESCAPE characters
+			// in an actual value must be preceded by an ESCAPE character. This means
+			// that an escaped QUOTE char also results in an even number of QUOTE
+			// characters.
+			quoteCharsSeen += bytes.Count(line, []byte{c.csvQuote})
 		} else {
-			// Otherwise, we have to do a manual count of double quotes and
+			// Otherwise, we have to do a manual count of quotes and
 			// ignore any escape characters preceding quotes for counting.
 			// For example, if the escape character is '\', we should ignore
 			// the intermediate quotes in a string such as `"start"\"\"end"`.
@@ -481,7 +576,7 @@ func (c *copyMachine) readCSVData(ctx context.Context, final bool) (brk bool, er
 					skipNextChar = false
 					continue
 				}
-				if ch == '"' {
+				if ch == c.csvQuote {
 					quoteCharsSeen++
 				}
 				if rune(ch) == c.csvEscape {
@@ -496,6 +591,11 @@ func (c *copyMachine) readCSVData(ctx context.Context, final bool) (brk bool, er
 
 	c.csvInput.Write(fullLine)
 	record, err := c.csvReader.Read()
+	if c.csvHeader {
+		// The header line is skipped without checking its contents.
+		c.csvHeader = false
+		return false, nil
+	}
 	// Look for end of data before checking for errors, since a field count
 	// error will still return record data.
 	if len(record) == 1 && !record[0].Quoted && record[0].Val == endOfData && c.buf.Len() == 0 {
@@ -516,9 +616,9 @@ func (c *copyMachine) readCSVTuple(ctx context.Context, record []csv.Record) err
 	}
 	exprs := make(tree.Exprs, len(record))
 	for i, s := range record {
-		// NB: When we implement FORCE_NULL, then quoted values also are allowed
-		// to be treated as NULL.
-		if !s.Quoted && s.Val == c.null {
+		// Unquoted values matching the null string are NULL unless FORCE NOT NULL
+		// was specified for the column, quoted ones only if FORCE NULL was.
+		if s.Val == c.null && ((!s.Quoted && !c.csvForceNotNull[i]) || (s.Quoted && c.csvForceNull[i])) {
 			exprs[i] = tree.DNull
 			continue
 		}
@@ -589,9 +689,9 @@ func (c *copyMachine) readBinaryTuple(ctx context.Context) (readSoFar []byte, er
 		c.binaryState = binaryStateFoundTrailer
 		return nil, nil
 	}
-	if fieldCount < 1 {
+	if int(fieldCount) != len(c.resultColumns) {
 		return nil, pgerror.Newf(pgcode.BadCopyFileFormat,
-			"unexpected field count: %d", fieldCount)
+			"row field count is %d, expected %d", fieldCount, len(c.resultColumns))
 	}
 	exprs := make(tree.Exprs, fieldCount)
 	var byteCount int32
@@ -607,18 +707,17 @@ func (c *copyMachine) readBinaryTuple(ctx context.Context) (readSoFar []byte, er
 			exprs[i] = tree.DNull
 			continue
 		}
+		if byteCount < 0 {
+			return nil, pgerror.Newf(pgcode.BadCopyFileFormat,
+				"invalid field size: %d", byteCount)
+		}
 		data := make([]byte, byteCount)
 		n, err = io.ReadFull(&c.buf, data)
 		readSoFar = append(readSoFar, data[:n]...)
 		if err != nil {
 			return readSoFar, err
 		}
-		d, err := pgwirebase.DecodeDatum(
-			c.parsingEvalCtx,
-			c.resultColumns[i].Typ,
-			pgwirebase.FormatBinary,
-			data,
-		)
+		d, err := c.decodeBinaryDatum(c.resultColumns[i].Typ, data)
 		if err != nil {
 			return nil, pgerror.Wrapf(err, pgcode.BadCopyFileFormat,
 				"decode datum as %s: %s", c.resultColumns[i].Typ.SQLString(), data)
@@ -636,21 +735,68 @@ func (c *copyMachine) readBinaryTuple(ctx context.Context) (readSoFar []byte, er
 	return nil, nil
 }
 
+// decodeBinaryDatum decodes a field of the binary format as a value of the
+// given column type.
+func (c *copyMachine) decodeBinaryDatum(typ *types.T, data []byte) (tree.Datum, error) {
+	d, err := pgwirebase.DecodeDatum(c.parsingEvalCtx, typ, pgwirebase.FormatBinary, data)
+	if err != nil {
+		return nil, err
+	}
+	// Collated strings are sent like other strings; apply the locale of the
+	// column.
+	if typ.Family() == types.CollatedStringFamily {
+		if s, ok := d.(*tree.DString); ok {
+			return tree.NewDCollatedString(string(*s), typ.Locale(), &c.parsingEvalCtx.CollationEnv)
+		}
+	}
+	return d, nil
+}
+
+// Flags of the header of the binary format. Bits 0-15 are reserved to signal
+// backwards-compatible format issues and are ignored, bits 16-31 signal
+// critical format issues.
+const (
+	binaryFlagOIDs          = 1 << 16
+	binaryFlagsCriticalMask = 0xffff0000
+)
+
 func (c *copyMachine) readBinarySignature() ([]byte, error) {
-	// This is the standard 11-byte binary signature with the flags and
-	// header 32-bit integers appended since we only support the zero value
-	// of them.
-	const binarySignature = "PGCOPY\n\377\r\n\000" + "\x00\x00\x00\x00" + "\x00\x00\x00\x00"
-	var sig [11 + 8]byte
-	if n, err := io.ReadFull(&c.buf, sig[:]); err != nil {
-		return sig[:n], err
-	}
-	if !bytes.Equal(sig[:], []byte(binarySignature)) {
-		return sig[:], pgerror.New(pgcode.BadCopyFileFormat,
+	// This is the standard 11-byte binary signature, followed by the flags and
+	// header extension area length 32-bit integers.
+	const binarySignature = "PGCOPY\n\377\r\n\000"
+	var header [11 + 8]byte
+	if n, err := io.ReadFull(&c.buf, header[:]); err != nil {
+		return header[:n], err
+	}
+	readSoFar := header[:]
+	if !bytes.Equal(header[:11], []byte(binarySignature)) {
+		return readSoFar, pgerror.New(pgcode.BadCopyFileFormat,
 			"unrecognized binary copy signature")
 	}
+	flags := binary.BigEndian.Uint32(header[11:15])
+	if flags&binaryFlagOIDs != 0 {
+		return readSoFar, pgerror.New(pgcode.BadCopyFileFormat,
+			"invalid COPY file header (WITH OIDS)")
+	}
+	if flags&binaryFlagsCriticalMask != 0 {
+		return readSoFar, pgerror.New(pgcode.BadCopyFileFormat,
+			"unrecognized critical flags in COPY file header")
+	}
+	extensionLen := int32(binary.BigEndian.Uint32(header[15:]))
+	if extensionLen < 0 {
+		return readSoFar, pgerror.New(pgcode.BadCopyFileFormat,
+			"invalid COPY file header (wrong length)")
+	}
+	// The contents of the header extension area are skipped, as no extensions
+	// are defined.
+	extension := make([]byte, extensionLen)
+	n, err := io.ReadFull(&c.buf, extension)
+	readSoFar = append(readSoFar, extension[:n]...)
+	if err != nil {
+		return readSoFar, err
+	}
 	c.binaryState = binaryStateRead
-	return sig[:], nil
+	return readSoFar, nil
 }
 
 // preparePlannerForCopy resets the planner so that it can be used during
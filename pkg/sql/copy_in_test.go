@@ -11,7 +11,9 @@
 package sql_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
 	"fmt"
 	"math"
 	"math/rand"
@@ -353,6 +355,95 @@ func TestCopyBinary(t *testing.T) {
 	sqlDB.CheckQueryResults(t, "SELECT * FROM t ORDER BY id", expect)
 }
 
+// TestCopyBinaryTypes sends a hand-built binary COPY stream with types that
+// the pgx driver can't encode, and checks the validation of the stream.
+func TestCopyBinaryTypes(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	params, _ := tests.CreateTestServerParams()
+	s, db, _ := serverutils.StartServer(t, params)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	defer s.Stopper().Stop(ctx)
+
+	pgURL, cleanupGoDB := sqlutils.PGUrl(
+		t, s.ServingSQLAddr(), "StartServer" /* prefix */, url.User(security.RootUser))
+	defer cleanupGoDB()
+	conn, err := pgx.Connect(ctx, pgURL.String())
+	require.NoError(t, err)
+	defer func() { _ = conn.Close(ctx) }()
+
+	sqlDB.Exec(t, `CREATE TABLE t (
+		id INT8 PRIMARY KEY,
+		a INT8[],
+		c STRING COLLATE en,
+		g GEOMETRY,
+		b BOX2D
+	)`)
+
+	var buf bytes.Buffer
+	put := func(vals ...interface{}) {
+		for _, v := range vals {
+			if str, ok := v.(string); ok {
+				buf.WriteString(str)
+				continue
+			}
+			require.NoError(t, binary.Write(&buf, binary.BigEndian, v))
+		}
+	}
+	// The header has a 4-byte extension area, which is skipped.
+	put("PGCOPY\n\377\r\n\000", int32(0), int32(4), "ext!")
+	put(int16(5))
+	put(int32(8), int64(1))
+	// A one-dimensional array of INT8 (OID 20) with a NULL element.
+	put(int32(48), int32(1), int32(1), int32(20), int32(3), int32(1))
+	put(int32(8), int64(1), int32(-1), int32(8), int64(2))
+	put(int32(2), "ab")
+	// POINT(1 2) as little-endian EWKB.
+	point := make([]byte, 21)
+	point[0] = 1
+	binary.LittleEndian.PutUint32(point[1:], 1)
+	binary.LittleEndian.PutUint64(point[5:], math.Float64bits(1))
+	binary.LittleEndian.PutUint64(point[13:], math.Float64bits(2))
+	put(int32(len(point)), string(point))
+	// BOX2D values are the bounds of the X and Y coordinates.
+	put(int32(32), math.Float64bits(0), math.Float64bits(1),
+		math.Float64bits(2), math.Float64bits(3))
+	put(int16(-1))
+
+	tag, err := conn.PgConn().CopyFrom(ctx, &buf, `COPY t FROM STDIN WITH (FORMAT binary)`)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), tag.RowsAffected())
+	sqlDB.CheckQueryResults(t,
+		`SELECT id, a::STRING, c::STRING, ST_AsText(g), b::STRING FROM t`,
+		[][]string{{"1", "{1,NULL,2}", "ab", "POINT (1 2)", "BOX(0 2,1 3)"}},
+	)
+
+	for _, tc := range []struct {
+		data     string
+		expected string
+	}{
+		{
+			data:     "PGCOPY\n\377\r\n\000\x00\x01\x00\x00\x00\x00\x00\x00",
+			expected: `invalid COPY file header \(WITH OIDS\)`,
+		},
+		{
+			data:     "PGCOPY\n\377\r\n\000\x00\x00\x00\x00\x00\x00\x00\x00\x00\x01\x00\x00\x00\x04\x00\x00\x00\x02",
+			expected: `row field count is 1, expected 5`,
+		},
+		{
+			data:     "PGCOPY\n\377\r\n\000\x00\x00\x00\x00\x00\x00\x00\x00\x00\x05\xff\xff\xff\xfe",
+			expected: `invalid field size: -2`,
+		},
+	} {
+		_, err := conn.PgConn().CopyFrom(ctx, strings.NewReader(tc.data), `COPY t FROM STDIN BINARY`)
+		if !testutils.IsError(err, tc.expected) {
+			t.Errorf("expected %q, got %v", tc.expected, err)
+		}
+	}
+}
+
 func TestCopyError(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -634,3 +725,66 @@ func TestCopyInReleasesLeases(t *testing.T) {
 	}
 	require.NoError(t, conn.Close(ctx))
 }
+
+// TestCopyCSVOptions tests the options of the CSV format of COPY FROM.
+func TestCopyCSVOptions(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	params, _ := tests.CreateTestServerParams()
+	s, db, _ := serverutils.StartServer(t, params)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	defer s.Stopper().Stop(ctx)
+
+	pgURL, cleanupGoDB := sqlutils.PGUrl(
+		t, s.ServingSQLAddr(), "StartServer" /* prefix */, url.User(security.RootUser))
+	defer cleanupGoDB()
+	conn, err := pgx.Connect(ctx, pgURL.String())
+	require.NoError(t, err)
+	defer func() { _ = conn.Close(ctx) }()
+
+	sqlDB.Exec(t, `CREATE TABLE t (a INT PRIMARY KEY, b STRING, c STRING)`)
+
+	// The header line is skipped, unquoted empty values of b and quoted empty
+	// values of c don't follow the default NULL handling.
+	tag, err := conn.PgConn().CopyFrom(ctx,
+		strings.NewReader("a,b,c\n1,,''\n2,'x,''y',z\n"),
+		`COPY t FROM STDIN WITH CSV HEADER QUOTE '''' FORCE NOT NULL b FORCE NULL c`,
+	)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), tag.RowsAffected())
+
+	tag, err = conn.PgConn().CopyFrom(ctx,
+		strings.NewReader("3;\"\\\"q\\\"\";\n"),
+		`COPY t FROM STDIN WITH (FORMAT csv, DELIMITER ';', ESCAPE '\', ENCODING 'UTF-8', FREEZE)`,
+	)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), tag.RowsAffected())
+
+	sqlDB.CheckQueryResults(t,
+		`SELECT a, COALESCE(b, 'NULL'), COALESCE(c, 'NULL') FROM t ORDER BY a`,
+		[][]string{{"1", "", "NULL"}, {"2", "x,'y", "z"}, {"3", `"q"`, "NULL"}},
+	)
+
+	for _, tc := range []struct {
+		stmt     string
+		expected string
+	}{
+		{`COPY t FROM STDIN WITH HEADER`, `HEADER can only be specified for CSV`},
+		{`COPY t FROM STDIN WITH QUOTE 'x'`, `QUOTE can only be specified for CSV`},
+		{`COPY t FROM STDIN WITH CSV QUOTE 'xy'`, `QUOTE must be a single one-byte character`},
+		{`COPY t FROM STDIN WITH CSV FORCE NULL z`, `FORCE NULL column "z" not referenced by COPY`},
+		{`COPY t (a, b) FROM STDIN WITH CSV FORCE NOT NULL c`, `FORCE NOT NULL column "c" not referenced by COPY`},
+		{`COPY t FROM STDIN WITH FORCE NULL c`, `FORCE NOT NULL and FORCE NULL can only be specified for CSV`},
+		{`COPY t FROM STDIN WITH CSV FORCE QUOTE *`, `FORCE QUOTE can only be used with COPY TO`},
+		{`COPY t FROM STDIN WITH ENCODING 'latin1'`, `unsupported encoding "latin1", only UTF8 is supported`},
+	} {
+		t.Run(tc.stmt, func(t *testing.T) {
+			_, err := conn.PgConn().CopyFrom(ctx, strings.NewReader(""), tc.stmt)
+			if !testutils.IsError(err, tc.expected) {
+				t.Fatalf("expected %q, got %v", tc.expected, err)
+			}
+		})
+	}
+}
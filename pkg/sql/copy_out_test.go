@@ -0,0 +1,152 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql_test
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/tests"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCopyOut tests the text, CSV and binary formats of COPY ... TO STDOUT.
+func TestCopyOut(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	params, _ := tests.CreateTestServerParams()
+	s, _, _ := serverutils.StartServer(t, params)
+	defer s.Stopper().Stop(ctx)
+
+	pgURL, cleanupGoDB := sqlutils.PGUrl(
+		t, s.ServingSQLAddr(), "StartServer" /* prefix */, url.User(security.RootUser))
+	defer cleanupGoDB()
+	conn, err := pgx.Connect(ctx, pgURL.String())
+	require.NoError(t, err)
+	defer func() { _ = conn.Close(ctx) }()
+
+	_, err = conn.Exec(ctx, `
+		CREATE TABLE t (i INT PRIMARY KEY, s STRING, n STRING);
+		INSERT INTO t VALUES
+			(1, 'hello', NULL),
+			(2, e'tab\there, "q"', 'x,y'),
+			(3, '', e'back\\slash\nline');
+	`)
+	require.NoError(t, err)
+
+	binaryRow := "PGCOPY\n\xff\r\n\x00" +
+		// Flags and header extension length.
+		"\x00\x00\x00\x00\x00\x00\x00\x00" +
+		// Field count, followed by the length and value of each field.
+		"\x00\x03" +
+		"\x00\x00\x00\x08\x00\x00\x00\x00\x00\x00\x00\x01" +
+		"\x00\x00\x00\x02ab" +
+		"\xff\xff\xff\xff" +
+		// Trailer.
+		"\xff\xff"
+
+	testCases := []struct {
+		stmt     string
+		expected string
+		rows     int64
+	}{
+		{
+			stmt:     `COPY t TO STDOUT`,
+			expected: "1\thello\t\\N\n2\ttab\\there, \"q\"\tx,y\n3\t\tback\\\\slash\\nline\n",
+			rows:     3,
+		},
+		{
+			stmt:     `COPY t (i, n) TO STDOUT WITH (FORMAT csv, HEADER)`,
+			expected: "i,n\n1,\n2,\"x,y\"\n3,\"back\\slash\nline\"\n",
+			rows:     3,
+		},
+		{
+			stmt:     `COPY (SELECT i, s FROM t WHERE i = 3) TO STDOUT CSV FORCE QUOTE *`,
+			expected: "\"3\",\"\"\n",
+			rows:     1,
+		},
+		{
+			stmt:     `COPY (SELECT 'it''s;x', 'y') TO STDOUT WITH (FORMAT csv, QUOTE '''', ESCAPE '\', DELIMITER ';')`,
+			expected: "'it\\'s;x';y\n",
+			rows:     1,
+		},
+		{
+			stmt:     `COPY (SELECT 1, NULL::STRING) TO STDOUT WITH NULL 'nil' DELIMITER '|'`,
+			expected: "1|nil\n",
+			rows:     1,
+		},
+		{
+			stmt:     `COPY (SELECT 1::INT8, 'ab', NULL::INT8) TO STDOUT WITH BINARY`,
+			expected: binaryRow,
+			rows:     1,
+		},
+		{
+			stmt:     `COPY (INSERT INTO t VALUES (4, 'd', NULL) RETURNING i, s) TO STDOUT`,
+			expected: "4\td\n",
+			rows:     1,
+		},
+		{
+			stmt:     `COPY (SELECT * FROM t WHERE i > 10) TO STDOUT`,
+			expected: "",
+			rows:     0,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.stmt, func(t *testing.T) {
+			var buf bytes.Buffer
+			tag, err := conn.PgConn().CopyTo(ctx, &buf, tc.stmt)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, buf.String())
+			require.Equal(t, tc.rows, tag.RowsAffected())
+		})
+	}
+
+	errorCases := []struct {
+		stmt     string
+		expected string
+	}{
+		{`COPY t TO STDOUT WITH HEADER`, `HEADER can only be specified for CSV`},
+		{`COPY t TO STDOUT WITH CSV FORCE NOT NULL s`, `FORCE NOT NULL and FORCE NULL can only be used with COPY FROM`},
+		{`COPY t TO STDOUT WITH CSV FORCE QUOTE z`, `FORCE QUOTE column "z" not referenced by COPY`},
+		{`COPY t TO STDOUT WITH CSV QUOTE ','`, `COPY delimiter and quote must be different`},
+		{`COPY t TO STDOUT WITH ENCODING 'latin1'`, `unsupported encoding "latin1", only UTF8 is supported`},
+		{`COPY t TO STDOUT WITH FREEZE`, `FREEZE can only be used with COPY FROM`},
+		{`COPY t TO STDOUT WITH (FORMAT xml)`, `COPY format "xml" not recognized`},
+		{`COPY (DELETE FROM t) TO STDOUT`, `COPY query must have a RETURNING clause`},
+		{`COPY missing TO STDOUT`, `relation "missing" does not exist`},
+	}
+	for _, tc := range errorCases {
+		t.Run(tc.stmt, func(t *testing.T) {
+			var buf bytes.Buffer
+			_, err := conn.PgConn().CopyTo(ctx, &buf, tc.stmt)
+			if !testutils.IsError(err, tc.expected) {
+				t.Fatalf("expected %q, got %v", tc.expected, err)
+			}
+		})
+	}
+
+	// The connection remains usable, and the failed statement did not delete
+	// any rows.
+	var count int
+	require.NoError(t, conn.QueryRow(ctx, `SELECT count(*) FROM t`).Scan(&count))
+	require.Equal(t, 4, count)
+}
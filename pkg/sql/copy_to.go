@@ -0,0 +1,174 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// CopyOutFormat describes how the rows of a COPY ... TO STDOUT statement are
+// encoded in the CopyData messages of the Copy-out pgwire subprotocol.
+//
+// See: https://www.postgresql.org/docs/current/static/sql-copy.html
+type CopyOutFormat struct {
+	Format tree.CopyFormat
+	// Delimiter separates the values of a row in the text and CSV formats.
+	Delimiter byte
+	// Null is the string NULL values are written as in the text and CSV
+	// formats.
+	Null string
+	// Header is set if the CSV output starts with a line holding the names of
+	// the columns.
+	Header bool
+	// Quote and Escape are the characters used to quote values, and to escape
+	// the quote character inside quoted values, in the CSV format.
+	Quote  byte
+	Escape byte
+	// ForceQuote has an entry for every column, set for the columns whose
+	// non-NULL values are always quoted in the CSV format.
+	ForceQuote []bool
+}
+
+// newCopyOutFormat validates the options of a COPY ... TO STDOUT statement
+// producing rows with the given columns.
+func newCopyOutFormat(
+	ctx context.Context, p *planner, opts *tree.CopyOptions, cols colinfo.ResultColumns,
+) (CopyOutFormat, error) {
+	f := CopyOutFormat{Format: opts.CopyFormat}
+	switch f.Format {
+	case tree.CopyFormatText:
+		f.Null = `\N`
+		f.Delimiter = '\t'
+	case tree.CopyFormatCSV:
+		f.Null = ""
+		f.Delimiter = ','
+		f.Quote = '"'
+	}
+
+	if opts.Destination != nil {
+		return f, pgerror.Newf(pgcode.FeatureNotSupported, "DESTINATION can only be used with COPY FROM")
+	}
+	if opts.Freeze {
+		return f, pgerror.Newf(pgcode.FeatureNotSupported, "FREEZE can only be used with COPY FROM")
+	}
+	if len(opts.ForceNotNull) > 0 || len(opts.ForceNull) > 0 {
+		return f, pgerror.Newf(pgcode.FeatureNotSupported,
+			"FORCE NOT NULL and FORCE NULL can only be used with COPY FROM")
+	}
+	if opts.Delimiter != nil {
+		if f.Format == tree.CopyFormatBinary {
+			return f, pgerror.Newf(pgcode.Syntax, "DELIMITER unsupported in BINARY format")
+		}
+		fn, err := p.TypeAsString(ctx, opts.Delimiter, "COPY")
+		if err != nil {
+			return f, err
+		}
+		delim, err := fn()
+		if err != nil {
+			return f, err
+		}
+		if len(delim) != 1 || !utf8.ValidString(delim) {
+			return f, pgerror.Newf(pgcode.FeatureNotSupported, "delimiter must be a single-byte character")
+		}
+		if delim[0] == '\n' || delim[0] == '\r' {
+			return f, pgerror.Newf(pgcode.InvalidParameterValue,
+				"COPY delimiter cannot be newline or carriage return")
+		}
+		f.Delimiter = delim[0]
+	}
+	if opts.Null != nil {
+		if f.Format == tree.CopyFormatBinary {
+			return f, pgerror.Newf(pgcode.Syntax, "NULL unsupported in BINARY format")
+		}
+		fn, err := p.TypeAsString(ctx, opts.Null, "COPY")
+		if err != nil {
+			return f, err
+		}
+		if f.Null, err = fn(); err != nil {
+			return f, err
+		}
+	}
+	if opts.Header {
+		if f.Format != tree.CopyFormatCSV {
+			return f, pgerror.Newf(pgcode.FeatureNotSupported, "HEADER can only be specified for CSV")
+		}
+		f.Header = true
+	}
+	if opts.Quote != nil {
+		if f.Format != tree.CopyFormatCSV {
+			return f, pgerror.Newf(pgcode.FeatureNotSupported, "QUOTE can only be specified for CSV")
+		}
+		s := opts.Quote.RawString()
+		if len(s) != 1 {
+			return f, pgerror.Newf(pgcode.FeatureNotSupported, "QUOTE must be a single one-byte character")
+		}
+		f.Quote = s[0]
+	}
+	f.Escape = f.Quote
+	if opts.Escape != nil {
+		if f.Format != tree.CopyFormatCSV {
+			return f, pgerror.Newf(pgcode.FeatureNotSupported, "ESCAPE can only be specified for CSV")
+		}
+		s := opts.Escape.RawString()
+		if len(s) != 1 {
+			return f, pgerror.Newf(pgcode.FeatureNotSupported, "ESCAPE must be a single one-byte character")
+		}
+		f.Escape = s[0]
+	}
+	if f.Format == tree.CopyFormatCSV && f.Quote == f.Delimiter {
+		return f, pgerror.Newf(pgcode.InvalidParameterValue, "COPY delimiter and quote must be different")
+	}
+	if opts.ForceQuoteAll || len(opts.ForceQuote) > 0 {
+		if f.Format != tree.CopyFormatCSV {
+			return f, pgerror.Newf(pgcode.FeatureNotSupported, "FORCE QUOTE can only be specified for CSV")
+		}
+		if opts.ForceQuoteAll {
+			f.ForceQuote = make([]bool, len(cols))
+			for i := range f.ForceQuote {
+				f.ForceQuote[i] = true
+			}
+		} else {
+			var err error
+			if f.ForceQuote, err = copyColumnFlags("FORCE QUOTE", opts.ForceQuote, cols); err != nil {
+				return f, err
+			}
+		}
+	}
+	if err := checkCopyEncoding(opts.Encoding); err != nil {
+		return f, err
+	}
+	return f, nil
+}
+
+// initCopyOutResult prepares res to send the rows of a COPY ... TO STDOUT
+// statement to the client as Copy-out data. It must be called before the
+// columns of the result are set.
+func (p *planner) initCopyOutResult(
+	ctx context.Context, n *tree.CopyTo, cols colinfo.ResultColumns, res RestrictedCommandResult,
+) error {
+	format, err := newCopyOutFormat(ctx, p, &n.Options, cols)
+	if err != nil {
+		return err
+	}
+	copyRes, ok := res.(CopyOutResult)
+	if !ok {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"COPY TO STDOUT is only supported by client connections")
+	}
+	copyRes.SetCopyOut(format)
+	return nil
+}
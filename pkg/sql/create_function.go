@@ -0,0 +1,427 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkeys"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+	"github.com/cockroachdb/errors"
+)
+
+type createFunctionNode struct {
+	n      *tree.CreateFunction
+	fnName tree.TableName
+	dbDesc catalog.DatabaseDescriptor
+	scDesc catalog.SchemaDescriptor
+}
+
+// Use to satisfy the linter.
+var _ planNode = &createFunctionNode{n: nil}
+
+// CreateFunction creates a user-defined function.
+// Privileges: CREATE on database and schema.
+//   Notes: postgres requires USAGE on the language and CREATE on the schema.
+func (p *planner) CreateFunction(ctx context.Context, n *tree.CreateFunction) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"CREATE FUNCTION",
+	); err != nil {
+		return nil, err
+	}
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.UserDefinedFunctions) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to use user-defined functions",
+			clusterversion.UserDefinedFunctions)
+	}
+
+	db, _, prefix, err := p.ResolveTargetObject(ctx, n.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.CheckPrivilege(ctx, db, privilege.CREATE); err != nil {
+		return nil, err
+	}
+	if db.GetID() == keys.SystemDatabaseID {
+		return nil, errors.New("cannot create a function in the system database")
+	}
+	sc, err := p.getNonTemporarySchemaForCreate(ctx, db, prefix.Schema())
+	if err != nil {
+		return nil, err
+	}
+	if err := p.canCreateOnSchema(
+		ctx, sc.GetID(), db.GetID(), p.User(), skipCheckPublicSchema); err != nil {
+		return nil, err
+	}
+
+	return &createFunctionNode{
+		n:      n,
+		fnName: tree.MakeTableNameFromPrefix(prefix, tree.Name(n.Name.Object())),
+		dbDesc: db,
+		scDesc: sc,
+	}, nil
+}
+
+func (n *createFunctionNode) startExec(params runParams) error {
+	p := params.p
+	name := n.fnName.Object()
+
+	// A user-defined function which has the same name as a built-in function
+	// could never be called, since built-in functions are resolved first.
+	builtinName := tree.UnresolvedName{NumParts: 1, Parts: tree.NameParts{name}}
+	if _, err := builtinName.ResolveFunction(p.CurrentSearchPath()); err == nil {
+		return pgerror.Newf(pgcode.DuplicateFunction,
+			"function %q conflicts with a built-in function", name)
+	}
+
+	fnDesc := descpb.FunctionDescriptor{Name: name}
+	if err := n.setParamsAndReturnType(params, &fnDesc); err != nil {
+		return err
+	}
+	if err := setFunctionOptions(&fnDesc, n.n.Options); err != nil {
+		return err
+	}
+	if err := validateFunctionBody(&fnDesc); err != nil {
+		return err
+	}
+	if fnDesc.LeakProof {
+		if err := p.RequireAdminRole(params.ctx, "create a LEAKPROOF function"); err != nil {
+			return err
+		}
+	}
+
+	// Look for an existing function with the same name in the target schema.
+	// The search path is not used here, since the function is always created
+	// in the schema which was resolved for the new name.
+	exists, id, err := catalogkv.LookupObjectID(
+		params.ctx, p.txn, p.ExecCfg().Codec, n.dbDesc.GetID(), n.scDesc.GetID(), name,
+	)
+	if err != nil {
+		return err
+	}
+	if exists {
+		desc, err := p.Descriptors().GetMutableDescriptorByID(params.ctx, id, p.txn)
+		if err != nil {
+			return err
+		}
+		existing, ok := desc.(*funcdesc.Mutable)
+		if !ok || !n.n.Replace {
+			// Let the collision check produce the appropriate error.
+			return catalogkv.CheckObjectCollision(
+				params.ctx, p.txn, p.ExecCfg().Codec, n.dbDesc.GetID(), n.scDesc.GetID(), &n.fnName,
+			)
+		}
+		return n.replaceFunction(params, existing, &fnDesc)
+	}
+	return n.createFunction(params, &fnDesc)
+}
+
+// setParamsAndReturnType resolves the types in the signature of the function.
+func (n *createFunctionNode) setParamsAndReturnType(
+	params runParams, fnDesc *descpb.FunctionDescriptor,
+) error {
+	typeResolver := params.p.semaCtx.GetTypeResolver()
+	resolveType := func(ref tree.ResolvableTypeReference) (*types.T, error) {
+		typ, err := tree.ResolveType(params.ctx, ref, typeResolver)
+		if err != nil {
+			return nil, err
+		}
+		if typ.UserDefined() {
+			return nil, unimplemented.NewWithIssue(17511,
+				"user-defined types in the signature of a user-defined function")
+		}
+		return typ, nil
+	}
+
+	seen := make(map[tree.Name]struct{}, len(n.n.Params))
+	fnDesc.Params = make([]descpb.FunctionDescriptor_Param, len(n.n.Params))
+	for i := range n.n.Params {
+		param := &n.n.Params[i]
+		if param.Name != "" {
+			if _, ok := seen[param.Name]; ok {
+				return pgerror.Newf(pgcode.InvalidFunctionDefinition,
+					"parameter name %q used more than once", param.Name)
+			}
+			seen[param.Name] = struct{}{}
+		}
+		typ, err := resolveType(param.Type)
+		if err != nil {
+			return err
+		}
+		fnDesc.Params[i] = descpb.FunctionDescriptor_Param{Name: string(param.Name), Type: typ}
+	}
+	returnType, err := resolveType(n.n.ReturnType)
+	if err != nil {
+		return err
+	}
+	fnDesc.ReturnType = returnType
+	return nil
+}
+
+// setFunctionOptions sets the properties of the function which are given by
+// the options of a CREATE FUNCTION statement.
+func setFunctionOptions(fnDesc *descpb.FunctionDescriptor, options tree.FunctionOptions) error {
+	var seenVolatility, seenLeakproof, seenNullInput, seenLanguage, seenBody bool
+	checkRedundant := func(seen *bool) error {
+		if *seen {
+			return pgerror.New(pgcode.Syntax, "conflicting or redundant options")
+		}
+		*seen = true
+		return nil
+	}
+
+	for _, option := range options {
+		switch t := option.(type) {
+		case tree.FunctionVolatility:
+			if err := checkRedundant(&seenVolatility); err != nil {
+				return err
+			}
+			switch tree.Volatility(t) {
+			case tree.VolatilityImmutable:
+				fnDesc.Volatility = descpb.FunctionDescriptor_IMMUTABLE
+			case tree.VolatilityStable:
+				fnDesc.Volatility = descpb.FunctionDescriptor_STABLE
+			default:
+				fnDesc.Volatility = descpb.FunctionDescriptor_VOLATILE
+			}
+		case tree.FunctionLeakproof:
+			if err := checkRedundant(&seenLeakproof); err != nil {
+				return err
+			}
+			fnDesc.LeakProof = bool(t)
+		case tree.FunctionNullInputBehavior:
+			if err := checkRedundant(&seenNullInput); err != nil {
+				return err
+			}
+			if t == tree.FunctionCalledOnNullInput {
+				fnDesc.NullInputBehavior = descpb.FunctionDescriptor_CALLED_ON_NULL_INPUT
+			} else {
+				fnDesc.NullInputBehavior = descpb.FunctionDescriptor_RETURNS_NULL_ON_NULL_INPUT
+			}
+		case tree.FunctionLanguage:
+			if err := checkRedundant(&seenLanguage); err != nil {
+				return err
+			}
+			if t != tree.FunctionLangSQL {
+				return unimplemented.NewWithIssueDetailf(17511, string(t),
+					"language %q is not supported for user-defined functions", string(t))
+			}
+			fnDesc.Lang = descpb.FunctionDescriptor_SQL
+		case tree.FunctionBodyStr:
+			if err := checkRedundant(&seenBody); err != nil {
+				return err
+			}
+			fnDesc.FunctionBody = string(t)
+		default:
+			return errors.AssertionFailedf("unknown function option %T", t)
+		}
+	}
+
+	if !seenLanguage {
+		return pgerror.New(pgcode.InvalidFunctionDefinition, "no language specified")
+	}
+	if !seenBody {
+		return pgerror.New(pgcode.InvalidFunctionDefinition, "no function body specified")
+	}
+	if fnDesc.LeakProof && fnDesc.Volatility != descpb.FunctionDescriptor_IMMUTABLE {
+		return pgerror.New(pgcode.InvalidFunctionDefinition,
+			"only IMMUTABLE functions can be declared LEAKPROOF")
+	}
+	return nil
+}
+
+// validateFunctionBody checks that the body of the function is a single query
+// which returns a single column. The body is planned, and its type checked,
+// when the function is called.
+func validateFunctionBody(fnDesc *descpb.FunctionDescriptor) error {
+	stmt, err := parser.ParseOne(fnDesc.FunctionBody)
+	if err != nil {
+		return pgerror.Wrap(err, pgcode.InvalidFunctionDefinition, "invalid function body")
+	}
+	sel, ok := stmt.AST.(*tree.Select)
+	if !ok {
+		return unimplemented.NewWithIssuef(17511,
+			"%s statements in the body of a user-defined function", stmt.AST.StatementTag())
+	}
+	if sel.With != nil {
+		return unimplemented.NewWithIssue(17511,
+			"WITH clauses in the body of a user-defined function")
+	}
+	numCols := -1
+	switch t := sel.Select.(type) {
+	case *tree.SelectClause:
+		numCols = len(t.Exprs)
+		for _, expr := range t.Exprs {
+			if _, ok := expr.Expr.(tree.UnqualifiedStar); ok {
+				numCols = -1
+			}
+		}
+	case *tree.ValuesClause:
+		numCols = len(t.Rows[0])
+	}
+	if numCols != -1 && numCols != 1 {
+		return pgerror.Newf(pgcode.InvalidFunctionDefinition,
+			"return type mismatch in function declared to return %s", fnDesc.ReturnType.SQLString())
+	}
+	return nil
+}
+
+// createFunction creates a new function descriptor and its namespace entry.
+func (n *createFunctionNode) createFunction(
+	params runParams, fnDesc *descpb.FunctionDescriptor,
+) error {
+	id, err := catalogkv.GenerateUniqueDescID(params.ctx, params.ExecCfg().DB, params.ExecCfg().Codec)
+	if err != nil {
+		return err
+	}
+
+	// Functions can be executed by everyone by default, as in Postgres.
+	privs := descpb.NewDefaultPrivilegeDescriptor(params.p.User())
+	privs.Grant(security.PublicRoleName(), privilege.List{privilege.EXECUTE})
+
+	fnDesc.ID = id
+	fnDesc.ParentID = n.dbDesc.GetID()
+	fnDesc.ParentSchemaID = n.scDesc.GetID()
+	fnDesc.Version = 1
+	fnDesc.Privileges = privs
+	desc := funcdesc.NewBuilder(fnDesc).BuildCreatedMutableFunction()
+
+	if err := params.p.createDescriptorWithID(
+		params.ctx,
+		catalogkeys.MakeObjectNameKey(params.ExecCfg().Codec, fnDesc.ParentID, fnDesc.ParentSchemaID, fnDesc.Name),
+		id,
+		desc,
+		params.EvalContext().Settings,
+		n.fnName.String(),
+	); err != nil {
+		return err
+	}
+
+	return params.p.logEvent(params.ctx,
+		desc.GetID(),
+		&eventpb.CreateFunction{
+			FunctionName: n.fnName.FQString(),
+			Owner:        privs.Owner().Normalized(),
+		})
+}
+
+// replaceFunction replaces the definition of an existing function. The
+// parameter types and the return type of the function cannot be changed.
+func (n *createFunctionNode) replaceFunction(
+	params runParams, existing *funcdesc.Mutable, fnDesc *descpb.FunctionDescriptor,
+) error {
+	if err := params.p.canModifyFunction(params.ctx, existing); err != nil {
+		return err
+	}
+	if existing.Dropped() {
+		return errors.Errorf("function %q is being dropped, try again later", existing.GetName())
+	}
+
+	if len(existing.Params) != len(fnDesc.Params) {
+		return pgerror.Newf(pgcode.DuplicateFunction,
+			"function %q already exists with different parameters", existing.GetName())
+	}
+	for i := range existing.Params {
+		if !existing.Params[i].Type.Identical(fnDesc.Params[i].Type) {
+			return pgerror.Newf(pgcode.DuplicateFunction,
+				"function %q already exists with different parameters", existing.GetName())
+		}
+	}
+	if !existing.ReturnType.Identical(fnDesc.ReturnType) {
+		return pgerror.New(pgcode.InvalidFunctionDefinition,
+			"cannot change return type of existing function")
+	}
+
+	existing.Params = fnDesc.Params
+	existing.Volatility = fnDesc.Volatility
+	existing.LeakProof = fnDesc.LeakProof
+	existing.NullInputBehavior = fnDesc.NullInputBehavior
+	existing.Lang = fnDesc.Lang
+	existing.FunctionBody = fnDesc.FunctionBody
+
+	if err := params.p.writeFuncDescChange(
+		params.ctx, existing, tree.AsStringWithFQNames(n.n, params.Ann()),
+	); err != nil {
+		return err
+	}
+
+	return params.p.logEvent(params.ctx,
+		existing.GetID(),
+		&eventpb.CreateFunction{
+			FunctionName: n.fnName.FQString(),
+			IsReplace:    true,
+			Owner:        existing.GetPrivileges().Owner().Normalized(),
+		})
+}
+
+// writeFuncDescChange writes a modified function descriptor, and queues a
+// schema change job which waits for the leases on the previous versions of
+// the descriptor to be released.
+func (p *planner) writeFuncDescChange(
+	ctx context.Context, desc *funcdesc.Mutable, jobDesc string,
+) error {
+	record, recordExists := p.extendedEvalCtx.SchemaChangeJobRecords[desc.ID]
+	if recordExists {
+		// Update it.
+		record.AppendDescription(jobDesc)
+		log.Infof(ctx, "job %d: updated job's specification for change on function %d", record.JobID, desc.ID)
+	} else {
+		// Or, create a new job.
+		jobRecord := jobs.Record{
+			JobID:         p.extendedEvalCtx.ExecCfg.JobRegistry.MakeJobID(),
+			Description:   jobDesc,
+			Username:      p.User(),
+			DescriptorIDs: descpb.IDs{desc.ID},
+			Details: jobspb.SchemaChangeDetails{
+				DescID: desc.ID,
+				// The version distinction for database jobs doesn't matter for
+				// function jobs.
+				FormatVersion: jobspb.DatabaseJobFormatVersion,
+			},
+			Progress:      jobspb.SchemaChangeProgress{},
+			NonCancelable: true,
+		}
+		p.extendedEvalCtx.SchemaChangeJobRecords[desc.ID] = &jobRecord
+		log.Infof(ctx, "queued new schema change job %d for function %d", jobRecord.JobID, desc.ID)
+	}
+
+	b := p.txn.NewBatch()
+	if err := p.Descriptors().WriteDescToBatch(
+		ctx, p.extendedEvalCtx.Tracing.KVTracingEnabled(), desc, b,
+	); err != nil {
+		return err
+	}
+	return p.txn.Run(ctx, b)
+}
+
+func (n *createFunctionNode) Next(params runParams) (bool, error) { return false, nil }
+func (n *createFunctionNode) Values() tree.Datums                 { return tree.Datums{} }
+func (n *createFunctionNode) Close(ctx context.Context)           {}
+func (n *createFunctionNode) ReadingOwnWrites()                   {}
@@ -0,0 +1,152 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+)
+
+type createPolicyNode struct {
+	n         *tree.CreatePolicy
+	tableName tree.TableName
+	tableDesc *tabledesc.Mutable
+	policy    descpb.PolicyDescriptor
+}
+
+// CreatePolicy creates a row-level security policy on a table.
+// Privileges: CREATE on table.
+func (p *planner) CreatePolicy(ctx context.Context, n *tree.CreatePolicy) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"CREATE POLICY",
+	); err != nil {
+		return nil, err
+	}
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.RowLevelSecurity) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to use row-level security",
+			clusterversion.RowLevelSecurity)
+	}
+
+	tn := n.Table.ToTableName()
+	prefix, tableDesc, err := p.ResolveMutableTableDescriptor(
+		ctx, &tn, true /* required */, tree.ResolveRequireTableDesc,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.CheckPrivilege(ctx, tableDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+	if tableDesc.IsVirtualTable() || tableDesc.IsForeignTable() {
+		return nil, pgerror.Newf(pgcode.WrongObjectType,
+			"%q does not support row-level security", tableDesc.Name)
+	}
+
+	for i := range tableDesc.Policies {
+		if tableDesc.Policies[i].Name == string(n.Name) {
+			return nil, pgerror.Newf(pgcode.DuplicateObject,
+				"policy %q for table %q already exists", n.Name, tableDesc.Name)
+		}
+	}
+
+	switch n.Command {
+	case tree.PolicyCommandSelect, tree.PolicyCommandDelete:
+		if n.WithCheck != nil {
+			return nil, pgerror.Newf(pgcode.Syntax,
+				"WITH CHECK cannot be applied to SELECT or DELETE")
+		}
+	case tree.PolicyCommandInsert:
+		if n.Using != nil {
+			return nil, pgerror.Newf(pgcode.Syntax,
+				"only WITH CHECK expression allowed for INSERT")
+		}
+	}
+
+	policy := descpb.PolicyDescriptor{
+		Name:        string(n.Name),
+		Command:     descpb.PolicyCommandValue[n.Command],
+		Restrictive: n.Restrictive,
+	}
+
+	roles, err := n.Roles.ToSQLUsernames()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.validateRoles(ctx, roles, true /* isPublicValid */); err != nil {
+		return nil, err
+	}
+	for _, role := range roles {
+		if role.IsPublicRole() {
+			// A policy which applies to PUBLIC applies to all roles.
+			policy.Roles = nil
+			break
+		}
+		policy.Roles = append(policy.Roles, role.Normalized())
+	}
+
+	if n.Using != nil {
+		policy.UsingExpr, err = schemaexpr.ValidatePolicyExpr(
+			ctx, tableDesc, n.Using, "policy USING", &p.semaCtx, &tn,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if n.WithCheck != nil {
+		policy.WithCheckExpr, err = schemaexpr.ValidatePolicyExpr(
+			ctx, tableDesc, n.WithCheck, "policy WITH CHECK", &p.semaCtx, &tn,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &createPolicyNode{
+		n:         n,
+		tableName: tree.MakeTableNameFromPrefix(prefix.NamePrefix(), tree.Name(tableDesc.Name)),
+		tableDesc: tableDesc,
+		policy:    policy,
+	}, nil
+}
+
+func (n *createPolicyNode) startExec(params runParams) error {
+	n.tableDesc.AddPolicy(n.policy)
+	if err := params.p.writeSchemaChange(
+		params.ctx, n.tableDesc, descpb.InvalidMutationID, tree.AsStringWithFQNames(n.n, params.Ann()),
+	); err != nil {
+		return err
+	}
+
+	// Record this table alteration in the event log. This is an auditable log
+	// event and is recorded in the same transaction as the table descriptor
+	// update.
+	return params.p.logEvent(params.ctx,
+		n.tableDesc.ID,
+		&eventpb.AlterTable{
+			TableName: n.tableName.FQString(),
+		})
+}
+
+func (n *createPolicyNode) Next(runParams) (bool, error) { return false, nil }
+func (n *createPolicyNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *createPolicyNode) Close(context.Context)        {}
@@ -0,0 +1,131 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+)
+
+type createPublicationNode struct {
+	n      *tree.CreatePublication
+	dbDesc *dbdesc.Mutable
+	pub    descpb.DatabaseDescriptor_Publication
+}
+
+// CreatePublication creates a publication of tables of the current database.
+// Privileges: CREATE on database and CREATE on the published tables, or the
+// admin role for publications of all tables.
+func (p *planner) CreatePublication(
+	ctx context.Context, n *tree.CreatePublication,
+) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"CREATE PUBLICATION",
+	); err != nil {
+		return nil, err
+	}
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.LogicalReplication) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to use publications",
+			clusterversion.LogicalReplication)
+	}
+
+	dbDesc, err := p.getCurrentMutableDatabase(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+	if _, ok := dbDesc.GetPublication(string(n.Name)); ok {
+		return nil, pgerror.Newf(pgcode.DuplicateObject,
+			"publication %q already exists", n.Name)
+	}
+
+	pub := descpb.DatabaseDescriptor_Publication{
+		Name:      string(n.Name),
+		AllTables: n.AllTables,
+	}
+	if n.AllTables {
+		if err := p.RequireAdminRole(ctx, "CREATE PUBLICATION FOR ALL TABLES"); err != nil {
+			return nil, err
+		}
+	}
+	var tableIDs catalog.DescriptorIDSet
+	for i := range n.Tables {
+		tn := &n.Tables[i]
+		tableDesc, err := p.resolveUncachedTableDescriptor(
+			ctx, tn, true /* required */, tree.ResolveRequireTableDesc,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if tableDesc.IsVirtualTable() || tableDesc.IsTemporary() {
+			return nil, pgerror.Newf(pgcode.InvalidObjectDefinition,
+				"cannot add relation %q to publication: only persistent tables can be published",
+				tree.ErrString(tn))
+		}
+		if isReplicationOriginsTable(tableDesc) {
+			return nil, pgerror.Newf(pgcode.InvalidObjectDefinition,
+				"cannot add relation %q to publication: it records the origins of the rows of subscriptions",
+				tree.ErrString(tn))
+		}
+		if tableDesc.GetParentID() != dbDesc.GetID() {
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+				"cannot add relation %q to publication: it is not in the current database %q",
+				tree.ErrString(tn), dbDesc.GetName())
+		}
+		if err := p.CheckPrivilege(ctx, tableDesc, privilege.CREATE); err != nil {
+			return nil, err
+		}
+		if !tableIDs.Contains(tableDesc.GetID()) {
+			tableIDs.Add(tableDesc.GetID())
+			pub.TableIDs = append(pub.TableIDs, tableDesc.GetID())
+		}
+	}
+
+	return &createPublicationNode{n: n, dbDesc: dbDesc, pub: pub}, nil
+}
+
+// getCurrentMutableDatabase returns the current database of the session, for
+// statements which create objects which can only belong to a database.
+func (p *planner) getCurrentMutableDatabase(ctx context.Context) (*dbdesc.Mutable, error) {
+	if p.CurrentDatabase() == "" {
+		return nil, errNoDatabase
+	}
+	return p.Descriptors().GetMutableDatabaseByName(ctx, p.txn, p.CurrentDatabase(),
+		tree.DatabaseLookupFlags{Required: true})
+}
+
+func (n *createPublicationNode) startExec(params runParams) error {
+	telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("publication"))
+
+	n.dbDesc.AddPublication(n.pub)
+	return params.p.writeNonDropDatabaseChange(
+		params.ctx, n.dbDesc, tree.AsStringWithFQNames(n.n, params.Ann()),
+	)
+}
+
+func (n *createPublicationNode) Next(runParams) (bool, error) { return false, nil }
+func (n *createPublicationNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *createPublicationNode) Close(context.Context)        {}
@@ -208,6 +208,12 @@ func (n *createStatsNode) makeJobRecord(ctx context.Context) (*jobs.Record, erro
 		)
 	}
 
+	if tableDesc.IsForeignTable() {
+		return nil, pgerror.New(
+			pgcode.WrongObjectType, "cannot create statistics on foreign tables",
+		)
+	}
+
 	if err := n.p.CheckPrivilege(ctx, tableDesc, privilege.SELECT); err != nil {
 		return nil, err
 	}
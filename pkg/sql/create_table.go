@@ -211,7 +211,11 @@ func getSchemaForCreateTable(
 }
 
 func (n *createTableNode) startExec(params runParams) error {
-	telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("table"))
+	if n.n.Foreign != nil {
+		telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("foreign_table"))
+	} else {
+		telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("table"))
+	}
 
 	schema, err := getSchemaForCreateTable(params, n.dbDesc, n.n.Persistence, &n.n.Table,
 		tree.ResolveRequireTableDesc, n.n.IfNotExists)
@@ -341,6 +345,15 @@ func (n *createTableNode) startExec(params runParams) error {
 		}
 	}
 
+	if err := params.p.updateIntervalPartitioningSchedule(
+		params.ctx, desc, nil, /* oldInterval */
+	); err != nil {
+		return err
+	}
+	if err := params.p.updateRowLevelTTLSchedule(params.ctx, desc, nil /* oldTTL */); err != nil {
+		return err
+	}
+
 	// Descriptor written to store here.
 	if err := params.p.createDescriptorWithID(
 		params.ctx,
@@ -562,6 +575,13 @@ const (
 	NonEmptyTable
 )
 
+// errDeferrableUniqueIndex is returned for deferrable unique constraints that
+// would be enforced by an index, since the uniqueness of index keys is enforced
+// as soon as the rows are written.
+var errDeferrableUniqueIndex = unimplemented.NewWithIssueDetail(31632,
+	"deferrable unique index",
+	"deferrable unique constraints are only supported with UNIQUE WITHOUT INDEX")
+
 // addUniqueWithoutIndexColumnTableDef runs various checks on the given
 // ColumnTableDef before adding it as a UNIQUE WITHOUT INDEX constraint to the
 // given table descriptor.
@@ -579,6 +599,9 @@ func addUniqueWithoutIndexColumnTableDef(
 			"unique constraints without an index are not yet supported",
 		)
 	}
+	if err := checkDeferrableConstraintsVersion(ctx, evalCtx.Settings, d.Deferrable); err != nil {
+		return err
+	}
 	// Add a unique constraint.
 	if err := ResolveUniqueWithoutIndexConstraint(
 		ctx,
@@ -586,6 +609,7 @@ func addUniqueWithoutIndexColumnTableDef(
 		string(d.Unique.ConstraintName),
 		[]string{string(d.Name)},
 		"", /* predicate */
+		tree.NotDeferrable,
 		ts,
 		validationBehavior,
 	); err != nil {
@@ -647,7 +671,7 @@ func addUniqueWithoutIndexTableDef(
 		colNames[i] = string(d.Columns[i].Column)
 	}
 	if err := ResolveUniqueWithoutIndexConstraint(
-		ctx, desc, string(d.Name), colNames, predicate, ts, validationBehavior,
+		ctx, desc, string(d.Name), colNames, predicate, d.Deferrable, ts, validationBehavior,
 	); err != nil {
 		return err
 	}
@@ -667,6 +691,7 @@ func ResolveUniqueWithoutIndexConstraint(
 	constraintName string,
 	colNames []string,
 	predicate string,
+	deferrability tree.ConstraintDeferrability,
 	ts TableState,
 	validationBehavior tree.ValidationBehavior,
 ) error {
@@ -720,11 +745,12 @@ func ResolveUniqueWithoutIndexConstraint(
 	}
 
 	uc := descpb.UniqueWithoutIndexConstraint{
-		Name:      constraintName,
-		TableID:   tbl.ID,
-		ColumnIDs: columnIDs,
-		Predicate: predicate,
-		Validity:  validity,
+		Name:          constraintName,
+		TableID:       tbl.ID,
+		ColumnIDs:     columnIDs,
+		Predicate:     predicate,
+		Validity:      validity,
+		Deferrability: descpb.ConstraintDeferrabilityValue[deferrability],
 	}
 
 	if ts == NewTable {
@@ -774,6 +800,9 @@ func ResolveFK(
 	validationBehavior tree.ValidationBehavior,
 	evalCtx *tree.EvalContext,
 ) error {
+	if err := checkDeferrableConstraintsVersion(ctx, evalCtx.Settings, d.Deferrable); err != nil {
+		return err
+	}
 	var originColSet catalog.TableColSet
 	originCols := make([]catalog.Column, len(d.FromCols))
 	for i, fromCol := range d.FromCols {
@@ -797,6 +826,10 @@ func ResolveFK(
 	if err != nil {
 		return err
 	}
+	if target.IsForeignTable() {
+		return pgerror.Newf(pgcode.InvalidForeignKey,
+			"foreign key constraints cannot reference foreign table %q", target.Name)
+	}
 	if target.ParentID != tbl.ParentID {
 		if !allowCrossDatabaseFKs.Get(&evalCtx.Settings.SV) {
 			return errors.WithHintf(
@@ -971,6 +1004,7 @@ func ResolveFK(
 		OnDelete:            descpb.ForeignKeyReferenceActionValue[d.Actions.Delete],
 		OnUpdate:            descpb.ForeignKeyReferenceActionValue[d.Actions.Update],
 		Match:               descpb.CompositeKeyMatchMethodValue[d.Match],
+		Deferrability:       descpb.ConstraintDeferrabilityValue[d.Deferrable],
 	}
 
 	if ts == NewTable {
@@ -1385,7 +1419,7 @@ func NewTableDesc(
 		semaCtx,
 		evalCtx,
 		n.StorageParams,
-		&paramparse.TableStorageParamObserver{},
+		&paramparse.TableStorageParamObserver{TableDesc: desc.TableDesc()},
 	); err != nil {
 		return nil, err
 	}
@@ -1903,6 +1937,9 @@ func NewTableDesc(
 				// We will add the unique constraint below.
 				break
 			}
+			if d.Deferrable.IsDeferrable() {
+				return nil, errDeferrableUniqueIndex
+			}
 			// If the index is named, ensure that the name is unique. Unnamed
 			// indexes will be given a unique auto-generated name later on when
 			// AllocateIDs is called.
@@ -2323,6 +2360,19 @@ func newTableDesc(
 	privileges *descpb.PrivilegeDescriptor,
 	affected map[descpb.ID]*tabledesc.Mutable,
 ) (ret *tabledesc.Mutable, err error) {
+	var foreign *descpb.TableDescriptor_ForeignTableOptions
+	if n.Foreign != nil {
+		if err := checkForeignTablesVersion(params.ctx, params.ExecCfg()); err != nil {
+			return nil, err
+		}
+		if err := checkForeignTableDefs(n); err != nil {
+			return nil, err
+		}
+		if foreign, err = makeForeignTableOptions(db, n.Foreign); err != nil {
+			return nil, err
+		}
+	}
+
 	newDefs, err := replaceLikeTableOpts(n, params)
 	if err != nil {
 		return nil, err
@@ -2380,6 +2430,7 @@ func newTableDesc(
 	if err != nil {
 		return ret, err
 	}
+	ret.Foreign = foreign
 
 	// We need to ensure sequence ownerships so that column owned sequences are
 	// correctly dropped when a column/table is dropped.
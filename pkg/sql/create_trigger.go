@@ -0,0 +1,213 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+)
+
+type createTriggerNode struct {
+	n         *tree.CreateTrigger
+	tableName tree.TableName
+	tableDesc *tabledesc.Mutable
+	trigger   descpb.TriggerDescriptor
+}
+
+// CreateTrigger creates a row-level trigger on a table.
+// Privileges: CREATE on table.
+func (p *planner) CreateTrigger(ctx context.Context, n *tree.CreateTrigger) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"CREATE TRIGGER",
+	); err != nil {
+		return nil, err
+	}
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.Triggers) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to use triggers",
+			clusterversion.Triggers)
+	}
+
+	tn := n.Table.ToTableName()
+	prefix, tableDesc, err := p.ResolveMutableTableDescriptor(
+		ctx, &tn, true /* required */, tree.ResolveRequireTableDesc,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.CheckPrivilege(ctx, tableDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	for i := range tableDesc.Triggers {
+		if tableDesc.Triggers[i].Name == string(n.Name) {
+			return nil, pgerror.Newf(pgcode.DuplicateObject,
+				"trigger %q for relation %q already exists", n.Name, tableDesc.Name)
+		}
+	}
+
+	trigger := descpb.TriggerDescriptor{
+		Name:       string(n.Name),
+		ActionTime: descpb.TriggerActionTimeValue[n.ActionTime],
+	}
+	for _, e := range n.Events {
+		if event := descpb.TriggerEventValue[e]; !trigger.HasEvent(event) {
+			trigger.Events = append(trigger.Events, event)
+		}
+	}
+
+	if n.When != nil {
+		trigger.When, err = schemaexpr.ValidateTriggerExpr(
+			ctx, tableDesc, n.When, types.Bool, "trigger WHEN condition", &p.semaCtx,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch n.ActionTime {
+	case tree.TriggerActionTimeBefore:
+		if n.Body != nil {
+			return nil, pgerror.New(pgcode.InvalidObjectDefinition,
+				"BEFORE triggers must use SET to modify the new row")
+		}
+		if n.HasEvent(tree.TriggerEventDelete) {
+			return nil, pgerror.New(pgcode.InvalidObjectDefinition,
+				"BEFORE triggers cannot fire for DELETE")
+		}
+		if err := p.addTriggerAssignments(ctx, tableDesc, n.Set, &trigger); err != nil {
+			return nil, err
+		}
+
+	case tree.TriggerActionTimeAfter:
+		if n.Body == nil {
+			return nil, pgerror.New(pgcode.InvalidObjectDefinition,
+				"AFTER triggers must execute an INSERT, UPSERT, UPDATE or DELETE statement")
+		}
+		if err := validateTriggerBody(n.Body); err != nil {
+			return nil, err
+		}
+		trigger.Body = tree.AsStringWithFlags(n.Body, tree.FmtParsable)
+	}
+
+	return &createTriggerNode{
+		n:         n,
+		tableName: tree.MakeTableNameFromPrefix(prefix.NamePrefix(), tree.Name(tableDesc.Name)),
+		tableDesc: tableDesc,
+		trigger:   trigger,
+	}, nil
+}
+
+// addTriggerAssignments validates the SET clause of a BEFORE trigger and adds
+// the corresponding assignments to the trigger descriptor.
+func (p *planner) addTriggerAssignments(
+	ctx context.Context,
+	tableDesc *tabledesc.Mutable,
+	exprs tree.UpdateExprs,
+	trigger *descpb.TriggerDescriptor,
+) error {
+	seen := make(map[descpb.ColumnID]struct{}, len(exprs))
+	for _, set := range exprs {
+		if set.Tuple {
+			return pgerror.New(pgcode.FeatureNotSupported,
+				"multiple-column assignments are not supported in triggers")
+		}
+		col, err := tableDesc.FindColumnWithName(set.Names[0])
+		if err != nil {
+			return err
+		}
+		if col.IsComputed() {
+			return schemaexpr.CannotWriteToComputedColError(col.GetName())
+		}
+		if col.IsInaccessible() || col.IsSystemColumn() {
+			return pgerror.Newf(pgcode.InvalidColumnReference,
+				"cannot assign to column %q", col.GetName())
+		}
+		if _, ok := seen[col.GetID()]; ok {
+			return pgerror.Newf(pgcode.Syntax,
+				"multiple assignments to the same column %q", col.GetName())
+		}
+		seen[col.GetID()] = struct{}{}
+
+		expr, err := schemaexpr.ValidateTriggerExpr(
+			ctx, tableDesc, set.Expr, col.GetType(), "trigger assignment", &p.semaCtx,
+		)
+		if err != nil {
+			return err
+		}
+		trigger.Assignments = append(trigger.Assignments, descpb.TriggerDescriptor_Assignment{
+			ColumnID: col.GetID(),
+			Expr:     expr,
+		})
+	}
+	return nil
+}
+
+// validateTriggerBody checks that the body of an AFTER trigger can be planned
+// as a cascade of the triggering statement.
+func validateTriggerBody(body tree.Statement) error {
+	var with *tree.With
+	var returning tree.ReturningClause
+	switch t := body.(type) {
+	case *tree.Insert:
+		with, returning = t.With, t.Returning
+	case *tree.Update:
+		with, returning = t.With, t.Returning
+	case *tree.Delete:
+		with, returning = t.With, t.Returning
+	default:
+		return pgerror.Newf(pgcode.InvalidObjectDefinition,
+			"%s is not supported in triggers", body.StatementTag())
+	}
+	if with != nil {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"WITH is not supported in trigger statements")
+	}
+	if _, ok := returning.(*tree.NoReturningClause); !ok {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"RETURNING is not supported in trigger statements")
+	}
+	return nil
+}
+
+func (n *createTriggerNode) startExec(params runParams) error {
+	n.tableDesc.AddTrigger(n.trigger)
+	if err := params.p.writeSchemaChange(
+		params.ctx, n.tableDesc, descpb.InvalidMutationID, tree.AsStringWithFQNames(n.n, params.Ann()),
+	); err != nil {
+		return err
+	}
+
+	// Record this table alteration in the event log. This is an auditable log
+	// event and is recorded in the same transaction as the table descriptor
+	// update.
+	return params.p.logEvent(params.ctx,
+		n.tableDesc.ID,
+		&eventpb.AlterTable{
+			TableName: n.tableName.FQString(),
+		})
+}
+
+func (n *createTriggerNode) Next(runParams) (bool, error) { return false, nil }
+func (n *createTriggerNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *createTriggerNode) Close(context.Context)        {}
@@ -0,0 +1,503 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/errors"
+)
+
+// constraintMode is the checking mode of a deferrable constraint, as set by
+// SET CONSTRAINTS.
+type constraintMode int
+
+const (
+	// constraintModeDefault checks the constraint according to its INITIALLY
+	// DEFERRED or INITIALLY IMMEDIATE clause.
+	constraintModeDefault constraintMode = iota
+	constraintModeImmediate
+	constraintModeDeferred
+)
+
+// deferredConstraintCheck is a foreign key or unique constraint check which
+// was deferred until the end of the transaction.
+type deferredConstraintCheck struct {
+	tableID descpb.ID
+	name    string
+	isFK    bool
+
+	// keys contains the distinct keys which violated the constraint when they
+	// were written. For foreign keys, these are the values of the origin
+	// columns; for unique constraints, the values of the unique columns.
+	keys []tree.Datums
+	// seen contains the formatted keys, to avoid duplicates.
+	seen map[string]struct{}
+}
+
+// deferredConstraints holds the constraint modes and the deferred constraint
+// checks of a transaction. The post-query checks of deferred constraints still
+// run after each statement, but the keys they find are accumulated instead of
+// generating an error. Only those keys are validated again, when the
+// transaction commits or when the constraint is set to IMMEDIATE, since
+// the constraint held for all other rows when they were written.
+type deferredConstraints struct {
+	// allMode is the mode set by SET CONSTRAINTS ALL.
+	allMode constraintMode
+	// modes contains the modes set for individual constraints since the last
+	// SET CONSTRAINTS ALL, keyed by constraint name.
+	modes map[string]constraintMode
+	// pending contains the checks which have not been validated yet, with one
+	// entry per constraint.
+	pending []*deferredConstraintCheck
+}
+
+// isDeferred returns whether the check of the constraint with the given name
+// and deferrability must be deferred.
+func (d *deferredConstraints) isDeferred(
+	name string, deferrability tree.ConstraintDeferrability,
+) bool {
+	if !deferrability.IsDeferrable() {
+		return false
+	}
+	switch d.mode(name) {
+	case constraintModeImmediate:
+		return false
+	case constraintModeDeferred:
+		return true
+	default:
+		return deferrability == tree.DeferrableInitiallyDeferred
+	}
+}
+
+// mode returns the mode set for the constraint with the given name.
+func (d *deferredConstraints) mode(name string) constraintMode {
+	if mode, ok := d.modes[name]; ok {
+		return mode
+	}
+	return d.allMode
+}
+
+// add adds a key to the pending check of the given constraint, unless it was
+// already added.
+func (d *deferredConstraints) add(tableID descpb.ID, name string, isFK bool, key tree.Datums) {
+	var check *deferredConstraintCheck
+	for _, c := range d.pending {
+		if c.tableID == tableID && c.name == name && c.isFK == isFK {
+			check = c
+			break
+		}
+	}
+	if check == nil {
+		check = &deferredConstraintCheck{
+			tableID: tableID,
+			name:    name,
+			isFK:    isFK,
+			seen:    make(map[string]struct{}),
+		}
+		d.pending = append(d.pending, check)
+	}
+	s := tree.AsString(&key)
+	if _, ok := check.seen[s]; ok {
+		return
+	}
+	check.seen[s] = struct{}{}
+	check.keys = append(check.keys, key)
+}
+
+// setMode sets the mode of the given constraints, or of all constraints if
+// all is true.
+func (d *deferredConstraints) setMode(names tree.NameList, all bool, mode constraintMode) {
+	if all {
+		d.allMode = mode
+		d.modes = nil
+		return
+	}
+	if d.modes == nil {
+		d.modes = make(map[string]constraintMode, len(names))
+	}
+	for _, name := range names {
+		d.modes[string(name)] = mode
+	}
+}
+
+// takePending removes and returns the pending checks of the constraints which
+// are set to IMMEDIATE.
+func (d *deferredConstraints) takePending() []*deferredConstraintCheck {
+	var ready []*deferredConstraintCheck
+	pending := d.pending[:0]
+	for _, check := range d.pending {
+		if d.mode(check.name) == constraintModeImmediate {
+			ready = append(ready, check)
+		} else {
+			pending = append(pending, check)
+		}
+	}
+	d.pending = pending
+	return ready
+}
+
+// takeAll removes and returns all pending checks.
+func (d *deferredConstraints) takeAll() []*deferredConstraintCheck {
+	checks := d.pending
+	d.pending = nil
+	return checks
+}
+
+func (d *deferredConstraints) reset() {
+	*d = deferredConstraints{}
+}
+
+// connExDeferredConstraintsAccessor exposes the deferred constraints of a
+// connExecutor's transaction to the optimizer.
+type connExDeferredConstraintsAccessor struct {
+	ex *connExecutor
+}
+
+var _ tree.DeferredConstraintState = connExDeferredConstraintsAccessor{}
+
+// IsConstraintDeferred is part of the tree.DeferredConstraintState interface.
+func (a connExDeferredConstraintsAccessor) IsConstraintDeferred(
+	name string, deferrability tree.ConstraintDeferrability,
+) bool {
+	// An internal executor may run in a transaction which it does not commit,
+	// so it always checks constraints immediately.
+	if a.ex.executorType == executorTypeInternal {
+		return false
+	}
+	return a.ex.extraTxnState.deferredConstraints.isDeferred(name, deferrability)
+}
+
+// DeferConstraintCheck is part of the tree.DeferredConstraintState interface.
+func (a connExDeferredConstraintsAccessor) DeferConstraintCheck(
+	tableID tree.ID, name string, isFK bool, key tree.Datums,
+) {
+	a.ex.extraTxnState.deferredConstraints.add(descpb.ID(tableID), name, isFK, key)
+}
+
+// SetConstraints implements the SET CONSTRAINTS statement.
+// See https://www.postgresql.org/docs/current/sql-set-constraints.html for
+// details.
+func (p *planner) SetConstraints(ctx context.Context, n *tree.SetConstraints) (planNode, error) {
+	return &delayedNode{
+		name: n.String(),
+		constructor: func(ctx context.Context, p *planner) (planNode, error) {
+			if p.deferredConstraints == nil {
+				return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+					"SET CONSTRAINTS is not supported in this context")
+			}
+			if !n.All {
+				if err := p.checkDeferrableConstraintNames(ctx, n.Names); err != nil {
+					return nil, err
+				}
+			}
+			if p.extendedEvalCtx.TxnImplicit {
+				p.BufferClientNotice(ctx, pgnotice.Newf(
+					"SET CONSTRAINTS can only be used in transaction blocks"))
+			}
+			mode := constraintModeImmediate
+			if n.Deferred {
+				mode = constraintModeDeferred
+			}
+			p.deferredConstraints.setMode(n.Names, n.All, mode)
+			// Checks of the constraints which are now IMMEDIATE are validated
+			// right away.
+			if err := p.validateDeferredConstraintChecks(
+				ctx, p.deferredConstraints.takePending(),
+			); err != nil {
+				return nil, err
+			}
+			return newZeroNode(nil /* columns */), nil
+		},
+	}, nil
+}
+
+// checkDeferrableConstraintsVersion returns an error if the given
+// deferrability requires a cluster version which is not active yet.
+func checkDeferrableConstraintsVersion(
+	ctx context.Context, st *cluster.Settings, deferrability tree.ConstraintDeferrability,
+) error {
+	if deferrability.IsDeferrable() && !st.Version.IsActive(ctx, clusterversion.DeferrableConstraints) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to use deferrable constraints",
+			clusterversion.DeferrableConstraints)
+	}
+	return nil
+}
+
+// checkDeferrableConstraintNames returns an error unless each of the given
+// names is the name of at least one deferrable foreign key or unique
+// constraint on a table of the current database.
+func (p *planner) checkDeferrableConstraintNames(ctx context.Context, names tree.NameList) error {
+	db, err := p.Descriptors().GetImmutableDatabaseByName(
+		ctx, p.txn, p.CurrentDatabase(), tree.DatabaseLookupFlags{Required: true},
+	)
+	if err != nil {
+		return err
+	}
+	tables, err := p.Descriptors().GetAllTableDescriptorsInDatabase(ctx, p.txn, db.GetID())
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		found, deferrable := false, false
+		for _, table := range tables {
+			_ = table.ForeachOutboundFK(func(fk *descpb.ForeignKeyConstraint) error {
+				if fk.Name == string(name) {
+					found = true
+					deferrable = deferrable || fk.Deferrability != descpb.ConstraintDeferrability_NOT_DEFERRABLE
+				}
+				return nil
+			})
+			for _, uc := range table.GetUniqueWithoutIndexConstraints() {
+				if uc.Name == string(name) {
+					found = true
+					deferrable = deferrable || uc.Deferrability != descpb.ConstraintDeferrability_NOT_DEFERRABLE
+				}
+			}
+		}
+		if !found {
+			return pgerror.Newf(pgcode.UndefinedObject, "constraint %q does not exist", name)
+		}
+		if !deferrable {
+			return pgerror.Newf(pgcode.WrongObjectType, "constraint %q is not deferrable", name)
+		}
+	}
+	return nil
+}
+
+// validateDeferredConstraintChecks validates the keys of the given deferred
+// checks in the current transaction. Constraints which were dropped since
+// their check was deferred are skipped.
+func (p *planner) validateDeferredConstraintChecks(
+	ctx context.Context, checks []*deferredConstraintCheck,
+) error {
+	ie := p.EvalContext().InternalExecutor.(*InternalExecutor)
+	flags := tree.ObjectLookupFlagsWithRequired()
+	flags.IncludeDropped = true
+	for _, check := range checks {
+		desc, err := p.Descriptors().GetMutableTableByID(ctx, p.txn, check.tableID, flags)
+		if err != nil {
+			return err
+		}
+		if desc.Dropped() {
+			continue
+		}
+		var syntheticDescs []catalog.Descriptor
+		if desc.Version > desc.ClusterVersion.Version {
+			syntheticDescs = append(syntheticDescs, desc)
+		}
+		if check.isFK {
+			fk := findOutboundFK(desc.OutboundFKs, check.name)
+			if fk == nil {
+				continue
+			}
+			var target catalog.TableDescriptor
+			target, err = p.Descriptors().GetImmutableTableByID(
+				ctx, p.txn, fk.ReferencedTableID, tree.ObjectLookupFlagsWithRequired(),
+			)
+			if err != nil {
+				return err
+			}
+			err = ie.WithSyntheticDescriptors(syntheticDescs, func() error {
+				return validateDeferredFKKeys(ctx, desc, fk, target, check.keys, ie, p.txn)
+			})
+		} else {
+			uc := findUniqueWithoutIndexConstraint(desc.UniqueWithoutIndexConstraints, check.name)
+			if uc == nil {
+				continue
+			}
+			err = ie.WithSyntheticDescriptors(syntheticDescs, func() error {
+				return validateDeferredUniqueKeys(ctx, desc, uc, check.keys, ie, p.txn, p.User())
+			})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateDeferredFKKeys verifies that the rows of srcTable which have one of
+// the given keys in the origin columns of the FK constraint have a matching
+// row in targetTable. Keys which are no longer present in srcTable are
+// ignored.
+//
+// For example, a FK constraint on columns (a_id, b_id) on the table "child",
+// referencing columns (a, b) on the table "parent", uses the following query
+// for each key:
+//
+//	SELECT 1 FROM [<ID of child> AS src] AS s
+//	 WHERE s.a_id = $1 AND s.b_id = $2
+//	   AND NOT EXISTS (
+//	         SELECT 1 FROM [<ID of parent> AS target] AS t
+//	          WHERE t.a = $1 AND t.b = $2
+//	       )
+//	 LIMIT 1
+//
+// A key containing NULLs can only have been recorded for a MATCH FULL FK, and
+// the rows which have it are always violations.
+func validateDeferredFKKeys(
+	ctx context.Context,
+	srcTable catalog.TableDescriptor,
+	fk *descpb.ForeignKeyConstraint,
+	targetTable catalog.TableDescriptor,
+	keys []tree.Datums,
+	ie *InternalExecutor,
+	txn *kv.Txn,
+) error {
+	originColNames, err := srcTable.NamesForColumnIDs(fk.OriginColumnIDs)
+	if err != nil {
+		return err
+	}
+	referencedColNames, err := targetTable.NamesForColumnIDs(fk.ReferencedColumnIDs)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		srcWhere := make([]string, len(key))
+		targetWhere := make([]string, 0, len(key))
+		args := make([]interface{}, 0, len(key))
+		for i, d := range key {
+			if d == tree.DNull {
+				srcWhere[i] = fmt.Sprintf("s.%s IS NULL", tree.NameString(originColNames[i]))
+				continue
+			}
+			args = append(args, d)
+			srcWhere[i] = fmt.Sprintf("s.%s = $%d", tree.NameString(originColNames[i]), len(args))
+			targetWhere = append(targetWhere, fmt.Sprintf(
+				"t.%s = $%d", tree.NameString(referencedColNames[i]), len(args),
+			))
+		}
+		query := fmt.Sprintf(
+			`SELECT 1 FROM [%d AS src] AS s WHERE %s`,
+			srcTable.GetID(), strings.Join(srcWhere, " AND "),
+		)
+		if len(targetWhere) == len(key) {
+			query += fmt.Sprintf(
+				` AND NOT EXISTS (SELECT 1 FROM [%d AS target] AS t WHERE %s)`,
+				targetTable.GetID(), strings.Join(targetWhere, " AND "),
+			)
+		}
+		query += " LIMIT 1"
+		values, err := ie.QueryRow(ctx, "validate deferred fk constraint", txn, query, args...)
+		if err != nil {
+			return err
+		}
+		if values.Len() > 0 {
+			return pgerror.WithConstraintName(pgerror.Newf(pgcode.ForeignKeyViolation,
+				"foreign key violation: %q row %s has no match in %q",
+				srcTable.GetName(), formatValues(originColNames, key), targetTable.GetName(),
+			), fk.Name)
+		}
+	}
+	return nil
+}
+
+// validateDeferredUniqueKeys verifies that at most one row of srcTable has
+// each of the given keys in the columns of the unique constraint.
+//
+// For example, a unique constraint on columns (a, b) on the table "tbl" uses
+// the following query for each key:
+//
+//	SELECT 1 FROM [<ID of tbl> AS tbl] WHERE a = $1 AND b = $2 LIMIT 1 OFFSET 1
+func validateDeferredUniqueKeys(
+	ctx context.Context,
+	srcTable catalog.TableDescriptor,
+	uc *descpb.UniqueWithoutIndexConstraint,
+	keys []tree.Datums,
+	ie *InternalExecutor,
+	txn *kv.Txn,
+	user security.SQLUsername,
+) error {
+	colNames, err := srcTable.NamesForColumnIDs(uc.ColumnIDs)
+	if err != nil {
+		return err
+	}
+	// There will be an expression in the WHERE clause for each of the columns,
+	// and possibly one for the predicate of a partial constraint.
+	srcWhere := make([]string, 0, len(colNames)+1)
+	for i := range colNames {
+		srcWhere = append(srcWhere, fmt.Sprintf("%s = $%d", tree.NameString(colNames[i]), i+1))
+	}
+	if uc.Predicate != "" {
+		srcWhere = append(srcWhere, fmt.Sprintf("(%s)", uc.Predicate))
+	}
+	query := fmt.Sprintf(
+		`SELECT 1 FROM [%d AS tbl] WHERE %s LIMIT 1 OFFSET 1`,
+		srcTable.GetID(), strings.Join(srcWhere, " AND "),
+	)
+	sessionDataOverride := sessiondata.NoSessionDataOverride
+	sessionDataOverride.User = user
+	for _, key := range keys {
+		args := make([]interface{}, len(key))
+		for i := range key {
+			args[i] = key[i]
+		}
+		values, err := ie.QueryRowEx(
+			ctx, "validate deferred unique constraint", txn, sessionDataOverride, query, args...,
+		)
+		if err != nil {
+			return err
+		}
+		if values.Len() > 0 {
+			valuesStr := make([]string, len(key))
+			for i := range key {
+				valuesStr[i] = key[i].String()
+			}
+			return errors.WithDetail(
+				pgerror.WithConstraintName(
+					pgerror.Newf(
+						pgcode.UniqueViolation, "duplicate key value violates unique constraint %q", uc.Name,
+					),
+					uc.Name,
+				),
+				fmt.Sprintf(
+					"Key (%s)=(%s) already exists.", strings.Join(colNames, ", "), strings.Join(valuesStr, ", "),
+				),
+			)
+		}
+	}
+	return nil
+}
+
+func findOutboundFK(fks []descpb.ForeignKeyConstraint, name string) *descpb.ForeignKeyConstraint {
+	for i := range fks {
+		if fks[i].Name == name {
+			return &fks[i]
+		}
+	}
+	return nil
+}
+
+func findUniqueWithoutIndexConstraint(
+	ucs []descpb.UniqueWithoutIndexConstraint, name string,
+) *descpb.UniqueWithoutIndexConstraint {
+	for i := range ucs {
+		if ucs[i].Name == name {
+			return &ucs[i]
+		}
+	}
+	return nil
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/rpc"
 	"github.com/cockroachdb/cockroach/pkg/rpc/nodedialer"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
@@ -327,6 +328,7 @@ func (dsp *DistSQLPlanner) mustWrapNode(planCtx *PlanningCtx, node planNode) boo
 	case *distinctNode:
 	case *exportNode:
 	case *filterNode:
+	case *foreignScanNode:
 	case *groupNode:
 	case *indexJoinNode:
 	case *invertedFilterNode:
@@ -392,6 +394,11 @@ func checkSupportForPlanNode(node planNode, outputNodeHasLimit bool) (distRecomm
 		}
 		return checkSupportForPlanNode(n.source.plan, false /* outputNodeHasLimit */)
 
+	case *foreignScanNode:
+		// The files of a foreign table are read in parallel on the nodes they
+		// are assigned to.
+		return shouldDistribute, nil
+
 	case *groupNode:
 		rec, err := checkSupportForPlanNode(n.plan, false /* outputNodeHasLimit */)
 		if err != nil {
@@ -2898,6 +2905,9 @@ func (dsp *DistSQLPlanner) createPhysPlanForPlanNode(
 			return nil, err
 		}
 
+	case *foreignScanNode:
+		plan, err = dsp.createPlanForForeignScan(planCtx, n)
+
 	case *groupNode:
 		plan, err = dsp.createPhysPlanForPlanNode(planCtx, n.plan)
 		if err != nil {
@@ -3845,7 +3855,8 @@ func (dsp *DistSQLPlanner) createPlanForWindow(
 }
 
 // createPlanForExport creates a physical plan for EXPORT.
-// We add a new stage of CSVWriter processors to the input plan.
+// We add a new stage of CSVWriter or ParquetWriter processors to the input
+// plan.
 func (dsp *DistSQLPlanner) createPlanForExport(
 	planCtx *PlanningCtx, n *exportNode,
 ) (*PhysicalPlan, error) {
@@ -3853,15 +3864,29 @@ func (dsp *DistSQLPlanner) createPlanForExport(
 	if err != nil {
 		return nil, err
 	}
-	core := execinfrapb.ProcessorCoreUnion{CSVWriter: &execinfrapb.CSVWriterSpec{
-		Destination:      n.destination,
-		NamePattern:      n.fileNamePattern,
-		Options:          n.csvOpts,
-		ChunkRows:        int64(n.chunkRows),
-		ChunkSize:        n.chunkSize,
-		CompressionCodec: n.fileCompression,
-		UserProto:        planCtx.planner.User().EncodeProto(),
-	}}
+	var core execinfrapb.ProcessorCoreUnion
+	switch n.fileFormat {
+	case exportFormatParquet:
+		core.ParquetWriter = &execinfrapb.ParquetWriterSpec{
+			Destination:      n.destination,
+			NamePattern:      n.fileNamePattern,
+			ColNames:         n.colNames,
+			ChunkRows:        int64(n.chunkRows),
+			ChunkSize:        n.chunkSize,
+			CompressionCodec: n.fileCompression,
+			UserProto:        planCtx.planner.User().EncodeProto(),
+		}
+	default:
+		core.CSVWriter = &execinfrapb.CSVWriterSpec{
+			Destination:      n.destination,
+			NamePattern:      n.fileNamePattern,
+			Options:          n.csvOpts,
+			ChunkRows:        int64(n.chunkRows),
+			ChunkSize:        n.chunkSize,
+			CompressionCodec: n.fileCompression,
+			UserProto:        planCtx.planner.User().EncodeProto(),
+		}
+	}
 
 	resTypes := make([]*types.T, len(colinfo.ExportColumns))
 	for i := range colinfo.ExportColumns {
@@ -3871,11 +3896,81 @@ func (dsp *DistSQLPlanner) createPlanForExport(
 		core, execinfrapb.PostProcessSpec{}, resTypes, execinfrapb.Ordering{},
 	)
 
-	// The CSVWriter produces the same columns as the EXPORT statement.
+	// The writers produce the same columns as the EXPORT statement.
 	plan.PlanToStreamColMap = identityMap(plan.PlanToStreamColMap, len(colinfo.ExportColumns))
 	return plan, nil
 }
 
+// createPlanForForeignScan creates a plan reading the files of a foreign table
+// with ForeignScan processors. The files are assigned round-robin to the
+// healthy nodes, starting with the gateway, unless the plan is local.
+func (dsp *DistSQLPlanner) createPlanForForeignScan(
+	planCtx *PlanningCtx, n *foreignScanNode,
+) (*PhysicalPlan, error) {
+	ctx := planCtx.ctx
+	user := planCtx.planner.User()
+	files, err := n.listFiles(ctx, planCtx.ExtendedEvalCtx.ExecCfg, user)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := []roachpb.NodeID{dsp.gatewayNodeID}
+	if !planCtx.isLocal && len(files) > 1 {
+		nodes, err = dsp.foreignScanNodes(planCtx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(nodes) > len(files) && len(files) > 0 {
+		nodes = nodes[:len(files)]
+	}
+	nodeFiles := make([][]string, len(nodes))
+	for i, file := range files {
+		nodeFiles[i%len(nodes)] = append(nodeFiles[i%len(nodes)], file)
+	}
+
+	p := planCtx.NewPhysicalPlan()
+	corePlacement := make([]physicalplan.ProcessorCorePlacement, len(nodes))
+	for i := range nodes {
+		corePlacement[i].NodeID = nodes[i]
+		corePlacement[i].Core.ForeignScan = &execinfrapb.ForeignScanSpec{
+			Table:     *n.desc.TableDesc(),
+			Uris:      nodeFiles[i],
+			Format:    n.format,
+			UserProto: user.EncodeProto(),
+		}
+	}
+	typs := make([]*types.T, len(n.columns))
+	for i := range n.columns {
+		typs[i] = n.columns[i].Typ
+	}
+	p.AddNoInputStage(corePlacement, execinfrapb.PostProcessSpec{}, typs, execinfrapb.Ordering{})
+	p.PlanToStreamColMap = identityMapInPlace(make([]int, len(typs)))
+	return p, nil
+}
+
+// foreignScanNodes returns the gateway followed by the other healthy nodes of
+// the cluster, among which the files of foreign tables are distributed.
+func (dsp *DistSQLPlanner) foreignScanNodes(planCtx *PlanningCtx) ([]roachpb.NodeID, error) {
+	nodes := []roachpb.NodeID{dsp.gatewayNodeID}
+	ss, err := planCtx.ExtendedEvalCtx.ExecCfg.NodesStatusServer.OptionalNodesStatusServer(47900)
+	if err != nil {
+		// Secondary tenants only read the files on their own SQL instance.
+		return nodes, nil //nolint:returnerrcheck
+	}
+	resp, err := ss.ListNodesInternal(planCtx.ctx, &serverpb.NodesRequest{})
+	if err != nil {
+		return nil, err
+	}
+	for _, node := range resp.Nodes {
+		nodeID := node.Desc.NodeID
+		if nodeID != dsp.gatewayNodeID && dsp.CheckNodeHealthAndVersion(planCtx, nodeID) == NodeOK {
+			nodes = append(nodes, nodeID)
+		}
+	}
+	return nodes, nil
+}
+
 // checkScanParallelizationIfLocal returns whether the plan contains scanNodes
 // that can be parallelized and is such that it is safe to do so.
 //
@@ -31,6 +31,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/flowinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/physicalplan"
@@ -51,6 +52,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/redact"
 	pbtypes "github.com/gogo/protobuf/types"
 )
 
@@ -1293,6 +1295,142 @@ func (dsp *DistSQLPlanner) PlanAndRun(
 	}
 }
 
+// planAndRunCascade plans and runs the i-th cascade of the given plan with the
+// given buffered input, and queues any cascades and checks of the cascading
+// query. The returned plan must be closed once all cascades have run.
+//
+// Returns false if an error was encountered and sets that error in the provided
+// receiver.
+func (dsp *DistSQLPlanner) planAndRunCascade(
+	ctx context.Context,
+	planner *planner,
+	evalCtxFactory func() *extendedEvalContext,
+	plan *planComponents,
+	i int,
+	buf exec.Node,
+	numBufferedRows int,
+	allowAutoCommit bool,
+	recv *DistSQLReceiver,
+) (_ planMaybePhysical, ok bool) {
+	// We place a sequence point before every cascade, so
+	// that each subsequent cascade can observe the writes
+	// by the previous step.
+	// TODO(radu): the cascades themselves can have more cascades; if any of
+	// those fall back to legacy cascades code, it will disable stepping. So we
+	// have to reenable stepping each time.
+	_ = planner.Txn().ConfigureStepping(ctx, kv.SteppingEnabled)
+	if err := planner.Txn().Step(ctx); err != nil {
+		recv.SetError(err)
+		return planMaybePhysical{}, false
+	}
+
+	evalCtx := evalCtxFactory()
+	execFactory := newExecFactory(planner)
+	cascadePlan, err := plan.cascades[i].PlanFn(
+		ctx, &planner.semaCtx, &evalCtx.EvalContext, execFactory,
+		buf, numBufferedRows, allowAutoCommit,
+	)
+	if err != nil {
+		recv.SetError(err)
+		return planMaybePhysical{}, false
+	}
+	cp := cascadePlan.(*planComponents)
+	if len(cp.subqueryPlans) > 0 {
+		cp.main.Close(ctx)
+		recv.SetError(errors.AssertionFailedf("cascades should not have subqueries"))
+		return planMaybePhysical{}, false
+	}
+
+	// Queue any new cascades.
+	if len(cp.cascades) > 0 {
+		plan.cascades = append(plan.cascades, cp.cascades...)
+	}
+
+	// Collect any new checks.
+	if len(cp.checkPlans) > 0 {
+		plan.checkPlans = append(plan.checkPlans, cp.checkPlans...)
+	}
+
+	// In cyclical reference situations, the number of cascading operations can
+	// be arbitrarily large. To avoid OOM, we enforce a limit. This is also a
+	// safeguard in case we have a bug that results in an infinite cascade loop.
+	if limit := int(evalCtx.SessionData().OptimizerFKCascadesLimit); len(plan.cascades) > limit {
+		cp.main.Close(ctx)
+		telemetry.Inc(sqltelemetry.CascadesLimitReached)
+		err := pgerror.Newf(pgcode.TriggeredActionException, "cascades limit (%d) reached", limit)
+		recv.SetError(err)
+		return planMaybePhysical{}, false
+	}
+
+	if err := dsp.planAndRunPostquery(
+		ctx,
+		cp.main,
+		planner,
+		evalCtx,
+		recv,
+	); err != nil {
+		cp.main.Close(ctx)
+		recv.SetError(err)
+		return planMaybePhysical{}, false
+	}
+	return cp.main, true
+}
+
+// planAndRunPerRowCascade plans and runs the i-th cascade of the given plan
+// once for each of the rows of its buffer, in order, with a buffer containing
+// only that row. Each execution observes the writes of the previous ones.
+//
+// Plans are closed as soon as they have run, unless they queued more cascades
+// (which may refer to their buffers); these are kept in rowPlans of the
+// cascade and closed along with the other plans.
+//
+// Returns false if an error was encountered and sets that error in the provided
+// receiver.
+func (dsp *DistSQLPlanner) planAndRunPerRowCascade(
+	ctx context.Context,
+	planner *planner,
+	evalCtxFactory func() *extendedEvalContext,
+	plan *planComponents,
+	i int,
+	buf *bufferNode,
+	allowAutoCommit bool,
+	recv *DistSQLReceiver,
+) bool {
+	rowBuf := &bufferNode{typs: buf.typs, label: buf.label}
+	rowBuf.rows.init(rowBuf.typs, evalCtxFactory(), redact.Sprint(rowBuf.label))
+	defer rowBuf.rows.close(ctx)
+
+	numRows := buf.rows.len()
+	it := newRowContainerIterator(ctx, buf.rows, buf.typs)
+	defer it.close()
+	for r := 0; r < numRows; r++ {
+		row, err := it.next()
+		if err == nil {
+			if err = rowBuf.rows.clear(ctx); err == nil {
+				err = rowBuf.rows.addRow(ctx, row)
+			}
+		}
+		if err != nil {
+			recv.SetError(err)
+			return false
+		}
+		numCascades := len(plan.cascades)
+		rowPlan, ok := dsp.planAndRunCascade(
+			ctx, planner, evalCtxFactory, plan, i, rowBuf, 1, /* numBufferedRows */
+			allowAutoCommit && r == numRows-1, recv,
+		)
+		if !ok {
+			return false
+		}
+		if len(plan.cascades) == numCascades {
+			rowPlan.Close(ctx)
+		} else {
+			plan.cascades[i].rowPlans = append(plan.cascades[i].rowPlans, rowPlan)
+		}
+	}
+	return true
+}
+
 // PlanAndRunCascadesAndChecks runs any cascade and check queries.
 //
 // Because cascades can themselves generate more cascades or check queries, this
@@ -1334,69 +1472,25 @@ func (dsp *DistSQLPlanner) PlanAndRunCascadesAndChecks(
 
 		log.VEventf(ctx, 2, "executing cascade for constraint %s", plan.cascades[i].FKName)
 
-		// We place a sequence point before every cascade, so
-		// that each subsequent cascade can observe the writes
-		// by the previous step.
-		// TODO(radu): the cascades themselves can have more cascades; if any of
-		// those fall back to legacy cascades code, it will disable stepping. So we
-		// have to reenable stepping each time.
-		_ = planner.Txn().ConfigureStepping(ctx, kv.SteppingEnabled)
-		if err := planner.Txn().Step(ctx); err != nil {
-			recv.SetError(err)
-			return false
-		}
-
-		evalCtx := evalCtxFactory()
-		execFactory := newExecFactory(planner)
 		// The cascading query is allowed to autocommit only if it is the last
 		// cascade and there are no check queries to run.
 		allowAutoCommit := planner.autoCommit
 		if len(plan.checkPlans) > 0 || i < len(plan.cascades)-1 {
 			allowAutoCommit = false
 		}
-		cascadePlan, err := plan.cascades[i].PlanFn(
-			ctx, &planner.semaCtx, &evalCtx.EvalContext, execFactory,
-			buf, numBufferedRows, allowAutoCommit,
-		)
-		if err != nil {
-			recv.SetError(err)
-			return false
-		}
-		cp := cascadePlan.(*planComponents)
-		plan.cascades[i].plan = cp.main
-		if len(cp.subqueryPlans) > 0 {
-			recv.SetError(errors.AssertionFailedf("cascades should not have subqueries"))
-			return false
-		}
-
-		// Queue any new cascades.
-		if len(cp.cascades) > 0 {
-			plan.cascades = append(plan.cascades, cp.cascades...)
-		}
-
-		// Collect any new checks.
-		if len(cp.checkPlans) > 0 {
-			plan.checkPlans = append(plan.checkPlans, cp.checkPlans...)
-		}
-
-		// In cyclical reference situations, the number of cascading operations can
-		// be arbitrarily large. To avoid OOM, we enforce a limit. This is also a
-		// safeguard in case we have a bug that results in an infinite cascade loop.
-		if limit := int(evalCtx.SessionData().OptimizerFKCascadesLimit); len(plan.cascades) > limit {
-			telemetry.Inc(sqltelemetry.CascadesLimitReached)
-			err := pgerror.Newf(pgcode.TriggeredActionException, "cascades limit (%d) reached", limit)
-			recv.SetError(err)
-			return false
+		if !plan.cascades[i].PerRow {
+			cascadePlan, ok := dsp.planAndRunCascade(
+				ctx, planner, evalCtxFactory, plan, i, buf, numBufferedRows, allowAutoCommit, recv,
+			)
+			if !ok {
+				return false
+			}
+			plan.cascades[i].plan = cascadePlan
+			continue
 		}
-
-		if err := dsp.planAndRunPostquery(
-			ctx,
-			cp.main,
-			planner,
-			evalCtx,
-			recv,
-		); err != nil {
-			recv.SetError(err)
+		if !dsp.planAndRunPerRowCascade(
+			ctx, planner, evalCtxFactory, plan, i, buf.(*bufferNode), allowAutoCommit, recv,
+		) {
 			return false
 		}
 	}
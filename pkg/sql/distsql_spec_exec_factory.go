@@ -942,6 +942,10 @@ func (e *distSQLSpecExecFactory) ConstructSequenceSelect(sequence cat.Sequence)
 	return nil, unimplemented.NewWithIssue(47473, "experimental opt-driven distsql planning: sequence select")
 }
 
+func (e *distSQLSpecExecFactory) ConstructForeignScan(table cat.ForeignTable) (exec.Node, error) {
+	return nil, unimplemented.NewWithIssue(47473, "experimental opt-driven distsql planning: foreign scan")
+}
+
 func (e *distSQLSpecExecFactory) ConstructSaveTable(
 	input exec.Node, table *cat.DataSourceName, colNames []string,
 ) (exec.Node, error) {
@@ -17,6 +17,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
@@ -36,10 +37,16 @@ type dropCascadeState struct {
 	toDeleteByID            map[descpb.ID]*toDelete
 	allTableObjectsToDelete []*tabledesc.Mutable
 	typesToDelete           []*typedesc.Mutable
+	functionsToDelete       []functionToDelete
 
 	droppedNames []string
 }
 
+type functionToDelete struct {
+	fn     tree.ObjectName
+	fnDesc *funcdesc.Mutable
+}
+
 type schemaWithDbDesc struct {
 	schema catalog.SchemaDescriptor
 	dbDesc *dbdesc.Mutable
@@ -143,7 +150,7 @@ func (d *dropCascadeState) resolveCollectedObjects(
 				ctx,
 				tree.ObjectLookupFlags{
 					CommonLookupFlags: tree.CommonLookupFlags{
-						Required:       true,
+						Required:       false,
 						RequireMutable: true,
 						IncludeOffline: true,
 					},
@@ -156,21 +163,52 @@ func (d *dropCascadeState) resolveCollectedObjects(
 			if err != nil {
 				return err
 			}
+			if found {
+				typDesc, ok := desc.(*typedesc.Mutable)
+				if !ok {
+					return errors.AssertionFailedf(
+						"descriptor for %q is not Mutable",
+						objName.Object(),
+					)
+				}
+				// Types can only depend on objects within this database, so we don't
+				// need to do any more verification about whether or not we can drop
+				// this type.
+				d.typesToDelete = append(d.typesToDelete, typDesc)
+				continue
+			}
+			// Finally, try a function.
+			found, _, desc, err = p.LookupObject(
+				ctx,
+				tree.ObjectLookupFlags{
+					CommonLookupFlags: tree.CommonLookupFlags{
+						Required:       false,
+						RequireMutable: true,
+						IncludeOffline: true,
+					},
+					DesiredObjectKind: tree.FunctionObject,
+				},
+				objName.Catalog(),
+				objName.Schema(),
+				objName.Object(),
+			)
+			if err != nil {
+				return err
+			}
 			// If we couldn't find the object at all, then continue.
 			if !found {
 				continue
 			}
-			typDesc, ok := desc.(*typedesc.Mutable)
+			fnDesc, ok := desc.(*funcdesc.Mutable)
 			if !ok {
 				return errors.AssertionFailedf(
 					"descriptor for %q is not Mutable",
 					objName.Object(),
 				)
 			}
-			// Types can only depend on objects within this database, so we don't
-			// need to do any more verification about whether or not we can drop
-			// this type.
-			d.typesToDelete = append(d.typesToDelete, typDesc)
+			// Functions don't keep references to other objects, so they can always
+			// be dropped along with their schema.
+			d.functionsToDelete = append(d.functionsToDelete, functionToDelete{objName, fnDesc})
 		}
 	}
 
@@ -219,6 +257,17 @@ func (d *dropCascadeState) dropAllCollectedObjects(ctx context.Context, p *plann
 		}
 	}
 
+	// Finally, delete all of the functions. Each of them queues a job which
+	// removes its namespace entry and descriptor.
+	for _, toDel := range d.functionsToDelete {
+		if err := p.dropFunctionImpl(
+			ctx, toDel.fnDesc, "dropping function "+toDel.fn.FQString(),
+		); err != nil {
+			return err
+		}
+		d.droppedNames = append(d.droppedNames, toDel.fn.FQString())
+	}
+
 	return nil
 }
 
@@ -0,0 +1,175 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+	"github.com/cockroachdb/errors"
+)
+
+type dropFunctionNode struct {
+	n      *tree.DropFunction
+	toDrop []*funcdesc.Mutable
+}
+
+// Use to satisfy the linter.
+var _ planNode = &dropFunctionNode{n: nil}
+
+// DropFunction drops user-defined functions.
+// Privileges: ownership of the function.
+func (p *planner) DropFunction(ctx context.Context, n *tree.DropFunction) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"DROP FUNCTION",
+	); err != nil {
+		return nil, err
+	}
+
+	if n.DropBehavior == tree.DropCascade {
+		return nil, unimplemented.NewWithIssue(17511, "DROP FUNCTION CASCADE is not yet supported")
+	}
+
+	node := &dropFunctionNode{n: n}
+	seen := make(map[descpb.ID]struct{}, len(n.Functions))
+	for i := range n.Functions {
+		fn := &n.Functions[i]
+		_, fnDesc, err := p.ResolveMutableFunctionDescriptor(ctx, fn.FuncName, !n.IfExists)
+		if err != nil {
+			return nil, err
+		}
+		if fnDesc == nil {
+			continue
+		}
+		if fn.Params != nil {
+			match, err := p.functionSignatureMatches(ctx, fnDesc, fn.Params)
+			if err != nil {
+				return nil, err
+			}
+			if !match {
+				if n.IfExists {
+					continue
+				}
+				return nil, sqlerrors.NewUndefinedFunctionError(tree.AsString(fn))
+			}
+		}
+		// If we've already seen this function, then skip it.
+		if _, ok := seen[fnDesc.ID]; ok {
+			continue
+		}
+		if err := p.canModifyFunction(ctx, fnDesc); err != nil {
+			return nil, err
+		}
+		seen[fnDesc.ID] = struct{}{}
+		node.toDrop = append(node.toDrop, fnDesc)
+	}
+	return node, nil
+}
+
+// functionSignatureMatches returns whether the types of the parameters of the
+// function are the given parameter types.
+func (p *planner) functionSignatureMatches(
+	ctx context.Context, fnDesc *funcdesc.Mutable, params tree.FuncParams,
+) (bool, error) {
+	if len(params) != len(fnDesc.Params) {
+		return false, nil
+	}
+	for i := range params {
+		typ, err := tree.ResolveType(ctx, params[i].Type, p.semaCtx.GetTypeResolver())
+		if err != nil {
+			return false, err
+		}
+		if !typ.Identical(fnDesc.Params[i].Type) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// canModifyFunction returns an error if the current user cannot modify or
+// drop the function.
+func (p *planner) canModifyFunction(ctx context.Context, desc *funcdesc.Mutable) error {
+	hasAdmin, err := p.HasAdminRole(ctx)
+	if err != nil {
+		return err
+	}
+	if hasAdmin {
+		return nil
+	}
+
+	hasOwnership, err := p.HasOwnership(ctx, desc)
+	if err != nil {
+		return err
+	}
+	if !hasOwnership {
+		return pgerror.Newf(pgcode.InsufficientPrivilege,
+			"must be owner of function %s", tree.Name(desc.GetName()))
+	}
+	return nil
+}
+
+func (n *dropFunctionNode) startExec(params runParams) error {
+	for _, fnDesc := range n.toDrop {
+		fnName, err := params.p.getQualifiedFunctionName(params.ctx, fnDesc)
+		if err != nil {
+			return err
+		}
+		if err := params.p.dropFunctionImpl(
+			params.ctx, fnDesc, "dropping function "+fnName.FQString(),
+		); err != nil {
+			return err
+		}
+		// Log a Drop Function event.
+		if err := params.p.logEvent(params.ctx,
+			fnDesc.ID,
+			&eventpb.DropFunction{
+				FunctionName: fnName.FQString(),
+			}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dropFunctionImpl marks a function as dropped, and queues a job which deletes
+// its descriptor once the leases on it have been released.
+func (p *planner) dropFunctionImpl(
+	ctx context.Context, fnDesc *funcdesc.Mutable, jobDesc string,
+) error {
+	if fnDesc.Dropped() {
+		return errors.Errorf("function %q is already being dropped", fnDesc.Name)
+	}
+
+	// Add a draining name.
+	fnDesc.DrainingNames = append(fnDesc.DrainingNames, descpb.NameInfo{
+		ParentID:       fnDesc.ParentID,
+		ParentSchemaID: fnDesc.ParentSchemaID,
+		Name:           fnDesc.Name,
+	})
+
+	// Actually mark the function as dropped.
+	fnDesc.SetDropped()
+	return p.writeFuncDescChange(ctx, fnDesc, jobDesc)
+}
+
+func (n *dropFunctionNode) Next(params runParams) (bool, error) { return false, nil }
+func (n *dropFunctionNode) Values() tree.Datums                 { return tree.Datums{} }
+func (n *dropFunctionNode) Close(ctx context.Context)           {}
+func (n *dropFunctionNode) ReadingOwnWrites()                   {}
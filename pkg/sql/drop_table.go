@@ -73,6 +73,10 @@ func (p *planner) DropTable(ctx context.Context, n *tree.DropTable) (planNode, e
 		if droppedDesc == nil {
 			continue
 		}
+		if n.Foreign && !droppedDesc.IsForeignTable() {
+			return nil, pgerror.Newf(pgcode.WrongObjectType,
+				"%q is not a foreign table", tn.String())
+		}
 
 		td[droppedDesc.ID] = toDelete{tn, droppedDesc}
 	}
@@ -385,6 +389,21 @@ func (p *planner) dropTableImpl(
 		return droppedViews, err
 	}
 
+	// Remove the schedule maintaining the partitions of an interval partitioned
+	// table.
+	if interval := tableDesc.PrimaryIndex.Partitioning.Interval; interval != nil {
+		if err := p.deleteTableSchedule(ctx, interval.ScheduleID); err != nil {
+			return droppedViews, err
+		}
+	}
+	// Remove the schedule of the job deleting the expired rows of a table with
+	// row-level TTL.
+	if ttl := tableDesc.GetRowLevelTTL(); ttl != nil {
+		if err := p.deleteTableSchedule(ctx, ttl.ScheduleID); err != nil {
+			return droppedViews, err
+		}
+	}
+
 	err = p.initiateDropTable(ctx, tableDesc, !droppingParent, jobDesc, true /* drain name */)
 	return droppedViews, err
 }
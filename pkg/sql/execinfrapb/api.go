@@ -66,11 +66,21 @@ func (m *CSVWriterSpec) User() security.SQLUsername {
 	return m.UserProto.Decode()
 }
 
+// User accesses the user field.
+func (m *ParquetWriterSpec) User() security.SQLUsername {
+	return m.UserProto.Decode()
+}
+
 // User accesses the user field.
 func (m *ReadImportDataSpec) User() security.SQLUsername {
 	return m.UserProto.Decode()
 }
 
+// User accesses the user field.
+func (m *ForeignScanSpec) User() security.SQLUsername {
+	return m.UserProto.Decode()
+}
+
 // User accesses the user field.
 func (m *ChangeAggregatorSpec) User() security.SQLUsername {
 	return m.UserProto.Decode()
@@ -491,11 +491,24 @@ func (c *ReadImportDataSpec) summary() (string, []string) {
 	return "ReadImportData", ss
 }
 
+// summary implements the diagramCellType interface.
+func (s *ForeignScanSpec) summary() (string, []string) {
+	// The URIs are not shown since they may contain credentials.
+	return "ForeignScan", []string{
+		fmt.Sprintf("%s: %d files", s.Table.Name, len(s.Uris)),
+	}
+}
+
 // summary implements the diagramCellType interface.
 func (s *CSVWriterSpec) summary() (string, []string) {
 	return "CSVWriter", []string{s.Destination}
 }
 
+// summary implements the diagramCellType interface.
+func (s *ParquetWriterSpec) summary() (string, []string) {
+	return "ParquetWriter", []string{s.Destination}
+}
+
 // summary implements the diagramCellType interface.
 func (s *BulkRowWriterSpec) summary() (string, []string) {
 	return "BulkRowWriterSpec", []string{}
@@ -41,10 +41,15 @@ type exportNode struct {
 	// fileNamePattern represents the file naming pattern for the
 	// export, typically to be appended to the destination URI
 	fileNamePattern string
+	// fileFormat is either exportFormatCSV or exportFormatParquet.
+	fileFormat      string
 	csvOpts         roachpb.CSVOptions
 	chunkRows       int
 	chunkSize       int64
 	fileCompression execinfrapb.FileCompression
+	// colNames are the names of the columns of the source, which are used as
+	// the column names of Parquet files.
+	colNames []string
 }
 
 func (e *exportNode) startExec(params runParams) error {
@@ -81,11 +86,23 @@ var exportOptionExpectValues = map[string]KVStringOptValidate{
 	exportOptionChunkSize:   KVStringOptRequireValue,
 }
 
+const (
+	exportFormatCSV     = "CSV"
+	exportFormatParquet = "PARQUET"
+)
+
 const exportChunkSizeDefault = int64(32 << 20) // 32 MB
 const exportChunkRowsDefault = 100000
 const exportFilePatternPart = "%part%"
-const exportFilePatternDefault = exportFilePatternPart + ".csv"
 const exportCompressionCodec = "gzip"
+const exportSnappyCompressionCodec = "snappy"
+
+// exportFileExtensions maps the export formats to the extension of the
+// exported files.
+var exportFileExtensions = map[string]string{
+	exportFormatCSV:     ".csv",
+	exportFormatParquet: ".parquet",
+}
 
 // featureExportEnabled is used to enable and disable the EXPORT feature.
 var featureExportEnabled = settings.RegisterBoolSetting(
@@ -118,7 +135,7 @@ func (ef *execFactory) ConstructExport(
 		return nil, errors.Errorf("EXPORT cannot be used inside a transaction")
 	}
 
-	if fileFormat != "CSV" {
+	if fileFormat != exportFormatCSV && fileFormat != exportFormatParquet {
 		return nil, errors.Errorf("unsupported export format: %q", fileFormat)
 	}
 
@@ -152,6 +169,16 @@ func (ef *execFactory) ConstructExport(
 		return nil, err
 	}
 
+	if fileFormat == exportFormatParquet {
+		// Parquet files have a schema and natively represent NULLs.
+		for _, opt := range []string{exportOptionDelimiter, exportOptionNullAs} {
+			if _, ok := optVals[opt]; ok {
+				return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+					"%s option is not supported for %s exports", opt, fileFormat)
+			}
+		}
+	}
+
 	csvOpts := roachpb.CSVOptions{}
 
 	if override, ok := optVals[exportOptionDelimiter]; ok {
@@ -172,7 +199,8 @@ func (ef *execFactory) ConstructExport(
 			return nil, pgerror.WithCandidateCode(err, pgcode.InvalidParameterValue)
 		}
 		if chunkRows < 1 {
-			return nil, pgerror.New(pgcode.InvalidParameterValue, "invalid csv chunk rows")
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"invalid %s chunk rows", strings.ToLower(fileFormat))
 		}
 	}
 
@@ -183,7 +211,8 @@ func (ef *execFactory) ConstructExport(
 			return nil, pgerror.WithCandidateCode(err, pgcode.InvalidParameterValue)
 		}
 		if chunkSize < 1 {
-			return nil, pgerror.New(pgcode.InvalidParameterValue, "invalid csv chunk size")
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"invalid %s chunk size", strings.ToLower(fileFormat))
 		}
 	}
 
@@ -193,6 +222,8 @@ func (ef *execFactory) ConstructExport(
 	if name, ok := optVals[exportOptionCompression]; ok && len(name) != 0 {
 		if strings.EqualFold(name, exportCompressionCodec) {
 			codec = execinfrapb.FileCompression_Gzip
+		} else if fileFormat == exportFormatParquet && strings.EqualFold(name, exportSnappyCompressionCodec) {
+			codec = execinfrapb.FileCompression_Snappy
 		} else {
 			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
 				"unsupported compression codec %s", name)
@@ -200,15 +231,24 @@ func (ef *execFactory) ConstructExport(
 	}
 
 	exportID := ef.planner.stmt.QueryID.String()
-	namePattern := fmt.Sprintf("export%s-%s", exportID, exportFilePatternDefault)
+	namePattern := fmt.Sprintf("export%s-%s%s", exportID, exportFilePatternPart, exportFileExtensions[fileFormat])
+
+	source := input.(planNode)
+	cols := planColumns(source)
+	colNames := make([]string, len(cols))
+	for i := range cols {
+		colNames[i] = cols[i].Name
+	}
 
 	return &exportNode{
-		source:          input.(planNode),
+		source:          source,
 		destination:     string(*destination),
 		fileNamePattern: namePattern,
+		fileFormat:      fileFormat,
 		csvOpts:         csvOpts,
 		chunkRows:       chunkRows,
 		chunkSize:       chunkSize,
 		fileCompression: codec,
+		colNames:        colNames,
 	}, nil
 }
@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/advisorylock"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
@@ -274,6 +275,30 @@ func (*DummyEvalPlanner) RevalidateUniqueConstraint(
 	return errors.WithStack(errEvalPlanner)
 }
 
+// Notify is part of the EvalPlanner interface.
+func (*DummyEvalPlanner) Notify(ctx context.Context, channel, payload string) error {
+	return errors.WithStack(errEvalPlanner)
+}
+
+// AcquireAdvisoryLock is part of the EvalPlanner interface.
+func (*DummyEvalPlanner) AcquireAdvisoryLock(
+	ctx context.Context, key advisorylock.Key, xact, wait bool,
+) (bool, error) {
+	return false, errors.WithStack(errEvalPlanner)
+}
+
+// ReleaseAdvisoryLock is part of the EvalPlanner interface.
+func (*DummyEvalPlanner) ReleaseAdvisoryLock(
+	ctx context.Context, key advisorylock.Key,
+) (bool, error) {
+	return false, errors.WithStack(errEvalPlanner)
+}
+
+// ReleaseAllAdvisoryLocks is part of the EvalPlanner interface.
+func (*DummyEvalPlanner) ReleaseAllAdvisoryLocks(ctx context.Context) error {
+	return errors.WithStack(errEvalPlanner)
+}
+
 var _ tree.EvalPlanner = &DummyEvalPlanner{}
 
 var errEvalPlanner = pgerror.New(pgcode.ScalarOperationCannotRunWithoutFullSessionContext,
@@ -427,6 +452,11 @@ func (ep *DummySessionAccessor) HasRoleOption(
 	return false, errors.WithStack(errEvalSessionVar)
 }
 
+// BackendPID is part of the tree.EvalSessionAccessor interface.
+func (ep *DummySessionAccessor) BackendPID(_ context.Context) (int32, error) {
+	return 0, errors.WithStack(errEvalSessionVar)
+}
+
 // DummyClientNoticeSender implements the tree.ClientNoticeSender interface.
 type DummyClientNoticeSender struct{}
 
@@ -0,0 +1,498 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/errors"
+)
+
+// fileForeignDataWrapper is the name of the only supported foreign data
+// wrapper, which reads CSV and Avro files from external storage.
+const fileForeignDataWrapper = "file_fdw"
+
+// The options of file_fdw servers and foreign tables.
+const (
+	// foreignOptionLocation is the base URI of the external storage of a
+	// server, and the path of the files of a foreign table relative to it. The
+	// path of a table may contain glob patterns.
+	foreignOptionLocation    = "location"
+	foreignOptionFormat      = "format"
+	foreignOptionDelimiter   = "delimiter"
+	foreignOptionHeader      = "header"
+	foreignOptionNull        = "null"
+	foreignOptionCompression = "compression"
+)
+
+var foreignServerOptions = map[string]struct{}{
+	foreignOptionLocation: {},
+}
+
+var foreignTableOptions = map[string]struct{}{
+	foreignOptionLocation:    {},
+	foreignOptionFormat:      {},
+	foreignOptionDelimiter:   {},
+	foreignOptionHeader:      {},
+	foreignOptionNull:        {},
+	foreignOptionCompression: {},
+}
+
+// makeForeignOptions checks the given OPTIONS clause of a foreign server or
+// table against the allowed option names and converts it into the form
+// stored in descriptors.
+func makeForeignOptions(
+	options tree.KVOptions, allowed map[string]struct{},
+) ([]descpb.ForeignOption, error) {
+	res := make([]descpb.ForeignOption, 0, len(options))
+	seen := make(map[string]struct{}, len(options))
+	for _, opt := range options {
+		key := string(opt.Key)
+		if _, ok := allowed[key]; !ok {
+			return nil, pgerror.Newf(pgcode.FdwInvalidOptionName, "invalid option %q", key)
+		}
+		if _, ok := seen[key]; ok {
+			return nil, pgerror.Newf(pgcode.Syntax, "option %q provided more than once", key)
+		}
+		seen[key] = struct{}{}
+		val, ok := opt.Value.(*tree.StrVal)
+		if !ok {
+			return nil, pgerror.Newf(pgcode.FdwInvalidAttributeValue,
+				"value of option %q must be a string", key)
+		}
+		res = append(res, descpb.ForeignOption{Key: key, Value: val.RawString()})
+	}
+	return res, nil
+}
+
+// getForeignOption returns the value of the option with the given key.
+func getForeignOption(options []descpb.ForeignOption, key string) (string, bool) {
+	for i := range options {
+		if options[i].Key == key {
+			return options[i].Value, true
+		}
+	}
+	return "", false
+}
+
+type createServerNode struct {
+	n      *tree.CreateServer
+	dbDesc *dbdesc.Mutable
+	server descpb.DatabaseDescriptor_ForeignServer
+}
+
+// CreateServer creates a foreign server in the current database.
+// Privileges: admin, since the location of the server may embed credentials
+// for the external storage, which are used on behalf of the users reading
+// from its foreign tables.
+func (p *planner) CreateServer(ctx context.Context, n *tree.CreateServer) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"CREATE SERVER",
+	); err != nil {
+		return nil, err
+	}
+	if err := checkForeignTablesVersion(ctx, p.ExecCfg()); err != nil {
+		return nil, err
+	}
+	if err := p.RequireAdminRole(ctx, "CREATE SERVER"); err != nil {
+		return nil, err
+	}
+
+	dbDesc, err := p.getCurrentMutableDatabase(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := dbDesc.GetForeignServer(string(n.Name)); ok {
+		if n.IfNotExists {
+			return newZeroNode(nil /* columns */), nil
+		}
+		return nil, pgerror.Newf(pgcode.DuplicateObject, "server %q already exists", n.Name)
+	}
+	if n.Wrapper != fileForeignDataWrapper {
+		return nil, pgerror.Newf(pgcode.UndefinedObject,
+			"foreign-data wrapper %q does not exist", n.Wrapper)
+	}
+
+	options, err := makeForeignOptions(n.Options, foreignServerOptions)
+	if err != nil {
+		return nil, err
+	}
+	location, ok := getForeignOption(options, foreignOptionLocation)
+	if !ok {
+		return nil, pgerror.Newf(pgcode.FdwOptionNameNotFound,
+			"option %q is required for servers of foreign-data wrapper %q",
+			foreignOptionLocation, fileForeignDataWrapper)
+	}
+	if _, err := cloud.ExternalStorageConfFromURI(location, p.User()); err != nil {
+		return nil, pgerror.Wrapf(err, pgcode.FdwInvalidAttributeValue,
+			"invalid location %q", location)
+	}
+	sort.Slice(options, func(i, j int) bool { return options[i].Key < options[j].Key })
+
+	return &createServerNode{
+		n:      n,
+		dbDesc: dbDesc,
+		server: descpb.DatabaseDescriptor_ForeignServer{
+			Name:    string(n.Name),
+			Wrapper: string(n.Wrapper),
+			Options: options,
+		},
+	}, nil
+}
+
+func (n *createServerNode) startExec(params runParams) error {
+	telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("server"))
+
+	n.dbDesc.AddForeignServer(n.server)
+	return params.p.writeNonDropDatabaseChange(
+		params.ctx, n.dbDesc, tree.AsStringWithFQNames(n.n, params.Ann()),
+	)
+}
+
+func (n *createServerNode) Next(runParams) (bool, error) { return false, nil }
+func (n *createServerNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *createServerNode) Close(context.Context)        {}
+
+type dropServerNode struct {
+	n      *tree.DropServer
+	dbDesc *dbdesc.Mutable
+}
+
+// DropServer drops a foreign server of the current database, which must not
+// be used by any foreign table.
+// Privileges: admin.
+func (p *planner) DropServer(ctx context.Context, n *tree.DropServer) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"DROP SERVER",
+	); err != nil {
+		return nil, err
+	}
+	if err := p.RequireAdminRole(ctx, "DROP SERVER"); err != nil {
+		return nil, err
+	}
+
+	dbDesc, err := p.getCurrentMutableDatabase(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := dbDesc.GetForeignServer(string(n.Name)); !ok {
+		if n.IfExists {
+			return newZeroNode(nil /* columns */), nil
+		}
+		return nil, pgerror.Newf(pgcode.UndefinedObject, "server %q does not exist", n.Name)
+	}
+
+	tables, err := p.Descriptors().GetAllTableDescriptorsInDatabase(ctx, p.txn, dbDesc.GetID())
+	if err != nil {
+		return nil, err
+	}
+	for _, table := range tables {
+		if table.Dropped() || !table.IsForeignTable() {
+			continue
+		}
+		if table.GetForeign().Server == string(n.Name) {
+			return nil, pgerror.Newf(pgcode.DependentObjectsStillExist,
+				"cannot drop server %q because foreign table %q depends on it",
+				n.Name, table.GetName())
+		}
+	}
+
+	return &dropServerNode{n: n, dbDesc: dbDesc}, nil
+}
+
+func (n *dropServerNode) startExec(params runParams) error {
+	telemetry.Inc(sqltelemetry.SchemaChangeDropCounter("server"))
+
+	n.dbDesc.RemoveForeignServer(string(n.n.Name))
+	return params.p.writeNonDropDatabaseChange(
+		params.ctx, n.dbDesc, tree.AsStringWithFQNames(n.n, params.Ann()),
+	)
+}
+
+func (n *dropServerNode) Next(runParams) (bool, error) { return false, nil }
+func (n *dropServerNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *dropServerNode) Close(context.Context)        {}
+
+// checkNotForeignTable returns an error if the given table is a foreign table,
+// for statements which change the rows, columns or indexes of tables.
+func checkNotForeignTable(desc catalog.TableDescriptor, op string) error {
+	if desc.IsForeignTable() {
+		return pgerror.Newf(pgcode.WrongObjectType,
+			"cannot %s foreign table %q", op, desc.GetName())
+	}
+	return nil
+}
+
+// checkForeignTablesVersion returns an error if the cluster version doesn't
+// support foreign servers and tables yet.
+func checkForeignTablesVersion(ctx context.Context, execCfg *ExecutorConfig) error {
+	if !execCfg.Settings.Version.IsActive(ctx, clusterversion.ForeignTables) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to use foreign tables",
+			clusterversion.ForeignTables)
+	}
+	return nil
+}
+
+// checkForeignTableDefs checks that the definitions of a CREATE FOREIGN TABLE
+// statement only declare columns with their types, since foreign tables have
+// neither indexes nor constraints, and their rows are never written.
+func checkForeignTableDefs(n *tree.CreateTable) error {
+	for _, def := range n.Defs {
+		d, ok := def.(*tree.ColumnTableDef)
+		if !ok {
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"foreign tables cannot have constraints, indexes or column families: %s",
+				tree.AsString(def))
+		}
+		if d.IsSerial || d.GeneratedIdentity.IsGeneratedAsIdentity || d.Hidden ||
+			d.Nullable.Nullability == tree.NotNull || d.PrimaryKey.IsPrimaryKey ||
+			d.Unique.IsUnique || d.DefaultExpr.Expr != nil || d.OnUpdateExpr.Expr != nil ||
+			len(d.CheckExprs) > 0 || d.References.Table != nil || d.Computed.Computed ||
+			d.Family.Name != "" || d.Family.Create {
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"column %q of a foreign table can only declare a type", d.Name)
+		}
+	}
+	return nil
+}
+
+// makeForeignTableOptions validates the SERVER and OPTIONS clauses of a
+// CREATE FOREIGN TABLE statement in the given database.
+func makeForeignTableOptions(
+	db catalog.DatabaseDescriptor, def *tree.ForeignTableDef,
+) (*descpb.TableDescriptor_ForeignTableOptions, error) {
+	server, ok := db.GetForeignServer(string(def.Server))
+	if !ok {
+		return nil, pgerror.Newf(pgcode.UndefinedObject, "server %q does not exist", def.Server)
+	}
+	options, err := makeForeignOptions(def.Options, foreignTableOptions)
+	if err != nil {
+		return nil, err
+	}
+	ret := &descpb.TableDescriptor_ForeignTableOptions{
+		Server:  server.Name,
+		Options: options,
+	}
+	// Check that the location and format of the table can be interpreted, so
+	// that mistakes surface now rather than when the table is queried.
+	if _, err := foreignTableLocation(server, ret); err != nil {
+		return nil, err
+	}
+	if _, err := foreignTableFormat(ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// foreignTableLocation returns the URI of the files of a foreign table, which
+// is the location of its server joined with the location of the table. The
+// returned URI may contain glob patterns.
+func foreignTableLocation(
+	server descpb.DatabaseDescriptor_ForeignServer,
+	table *descpb.TableDescriptor_ForeignTableOptions,
+) (string, error) {
+	serverLocation, _ := getForeignOption(server.Options, foreignOptionLocation)
+	uri, err := url.Parse(serverLocation)
+	if err != nil {
+		return "", err
+	}
+	tableLocation, ok := getForeignOption(table.Options, foreignOptionLocation)
+	if !ok {
+		return "", pgerror.Newf(pgcode.FdwOptionNameNotFound,
+			"option %q is required for foreign tables", foreignOptionLocation)
+	}
+	// The location of a table must stay under the location of its server, as
+	// the server may carry credentials for just that prefix.
+	for _, elem := range strings.Split(tableLocation, "/") {
+		if elem == ".." {
+			return "", pgerror.Newf(pgcode.FdwInvalidAttributeValue,
+				"location %q of a foreign table cannot refer to a parent directory", tableLocation)
+		}
+	}
+	uri.Path = cloud.JoinPathPreservingTrailingSlash(uri.Path, tableLocation)
+	return uri.String(), nil
+}
+
+// foreignTableFormat returns the format of the files of a foreign table.
+func foreignTableFormat(
+	table *descpb.TableDescriptor_ForeignTableOptions,
+) (roachpb.IOFileFormat, error) {
+	var format roachpb.IOFileFormat
+	name, _ := getForeignOption(table.Options, foreignOptionFormat)
+	switch strings.ToLower(name) {
+	case "", "csv":
+		format.Format = roachpb.IOFileFormat_CSV
+		if delimiter, ok := getForeignOption(table.Options, foreignOptionDelimiter); ok {
+			r, size := utf8.DecodeRuneInString(delimiter)
+			if size == 0 || size != len(delimiter) {
+				return format, pgerror.New(pgcode.FdwInvalidAttributeValue,
+					"delimiter must be a single character")
+			}
+			format.Csv.Comma = r
+		}
+		if header, ok := getForeignOption(table.Options, foreignOptionHeader); ok {
+			skip, err := tree.ParseDBool(header)
+			if err != nil {
+				return format, pgerror.Wrapf(err, pgcode.FdwInvalidAttributeValue,
+					"invalid value for option %q", foreignOptionHeader)
+			}
+			if *skip {
+				format.Csv.Skip = 1
+			}
+		}
+		if null, ok := getForeignOption(table.Options, foreignOptionNull); ok {
+			format.Csv.NullEncoding = &null
+		}
+	case "avro":
+		for _, key := range []string{foreignOptionDelimiter, foreignOptionHeader, foreignOptionNull} {
+			if _, ok := getForeignOption(table.Options, key); ok {
+				return format, pgerror.Newf(pgcode.FdwInvalidOptionName,
+					"option %q is only supported for the csv format", key)
+			}
+		}
+		format.Format = roachpb.IOFileFormat_Avro
+		format.Avro.Format = roachpb.AvroOptions_OCF
+	default:
+		return format, pgerror.Newf(pgcode.FdwInvalidAttributeValue,
+			"unsupported format %q, expected csv or avro", name)
+	}
+
+	compression, _ := getForeignOption(table.Options, foreignOptionCompression)
+	switch strings.ToLower(compression) {
+	case "", "auto":
+		format.Compression = roachpb.IOFileFormat_Auto
+	case "none":
+		format.Compression = roachpb.IOFileFormat_None
+	case "gzip":
+		format.Compression = roachpb.IOFileFormat_Gzip
+	case "bzip":
+		format.Compression = roachpb.IOFileFormat_Bzip
+	default:
+		return format, pgerror.Newf(pgcode.FdwInvalidAttributeValue,
+			"unsupported compression %q", compression)
+	}
+	return format, nil
+}
+
+// foreignScanNode reads all the rows of a foreign table. It is always planned
+// as ForeignScan processors by the DistSQL physical planner, which list the
+// files of the table and distribute them among the nodes.
+type foreignScanNode struct {
+	desc     catalog.TableDescriptor
+	columns  colinfo.ResultColumns
+	location string
+	format   roachpb.IOFileFormat
+}
+
+func (p *planner) newForeignScanNode(
+	ctx context.Context, desc catalog.TableDescriptor,
+) (*foreignScanNode, error) {
+	_, dbDesc, err := p.Descriptors().GetImmutableDatabaseByID(
+		ctx, p.txn, desc.GetParentID(), tree.DatabaseLookupFlags{Required: true},
+	)
+	if err != nil {
+		return nil, err
+	}
+	server, ok := dbDesc.GetForeignServer(desc.GetForeign().Server)
+	if !ok {
+		return nil, errors.AssertionFailedf(
+			"server %q of foreign table %q does not exist", desc.GetForeign().Server, desc.GetName())
+	}
+	location, err := foreignTableLocation(server, desc.GetForeign())
+	if err != nil {
+		return nil, err
+	}
+	format, err := foreignTableFormat(desc.GetForeign())
+	if err != nil {
+		return nil, err
+	}
+	visible := desc.VisibleColumns()
+	columns := make(colinfo.ResultColumns, len(visible))
+	for i, col := range visible {
+		columns[i] = colinfo.ResultColumn{
+			Name:           col.GetName(),
+			Typ:            col.GetType(),
+			TableID:        desc.GetID(),
+			PGAttributeNum: col.GetPGAttributeNum(),
+		}
+	}
+	return &foreignScanNode{
+		desc:     desc,
+		columns:  columns,
+		location: location,
+		format:   format,
+	}, nil
+}
+
+// listFiles returns the URIs of the files of the foreign table, expanding
+// the glob patterns in its location.
+func (n *foreignScanNode) listFiles(
+	ctx context.Context, execCfg *ExecutorConfig, user security.SQLUsername,
+) ([]string, error) {
+	uri, err := url.Parse(n.location)
+	if err != nil {
+		return nil, err
+	}
+	prefix := cloud.GetPrefixBeforeWildcard(uri.Path)
+	if len(prefix) == len(uri.Path) {
+		return []string{n.location}, nil
+	}
+	pattern := uri.Path[len(prefix):]
+	uri.Path = prefix
+	store, err := execCfg.DistSQLSrv.ExternalStorageFromURI(ctx, uri.String(), user)
+	if err != nil {
+		return nil, err
+	}
+	defer store.Close()
+	var files []string
+	if err := store.List(ctx, "", "", func(s string) error {
+		ok, err := path.Match(pattern, s)
+		if ok {
+			uri.Path = prefix + s
+			files = append(files, uri.String())
+		}
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+func (n *foreignScanNode) startExec(runParams) error {
+	return errors.AssertionFailedf("foreign scan of %q must be planned by DistSQL", n.desc.GetName())
+}
+
+func (n *foreignScanNode) Next(runParams) (bool, error) { return false, nil }
+func (n *foreignScanNode) Values() tree.Datums          { return nil }
+func (n *foreignScanNode) Close(context.Context)        {}
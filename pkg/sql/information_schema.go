@@ -1254,6 +1254,8 @@ https://www.postgresql.org/docs/9.5/infoschema-table-constraints.html`,
 				tbNameStr := tree.NewDString(table.GetName())
 
 				for conName, c := range conInfo {
+					isDeferrable := yesOrNoDatum(c.Deferrability().IsDeferrable())
+					initiallyDeferred := yesOrNoDatum(c.Deferrability() == tree.DeferrableInitiallyDeferred)
 					if err := addRow(
 						dbNameStr,                       // constraint_catalog
 						scNameStr,                       // constraint_schema
@@ -1262,8 +1264,8 @@ https://www.postgresql.org/docs/9.5/infoschema-table-constraints.html`,
 						scNameStr,                       // table_schema
 						tbNameStr,                       // table_name
 						tree.NewDString(string(c.Kind)), // constraint_type
-						yesOrNoDatum(false),             // is_deferrable
-						yesOrNoDatum(false),             // initially_deferred
+						isDeferrable,                    // is_deferrable
+						initiallyDeferred,               // initially_deferred
 					); err != nil {
 						return err
 					}
@@ -0,0 +1,937 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/config/zonepb"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/scheduledjobs"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/errors"
+	pbtypes "github.com/gogo/protobuf/types"
+	"github.com/gorhill/cronexpr"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// The options of a PARTITION BY RANGE ... INTERVAL clause.
+const (
+	intervalPartitioningPrecreateOption  = "precreate"
+	intervalPartitioningRetentionOption  = "retention"
+	intervalPartitioningArchiveOption    = "archive"
+	intervalPartitioningZoneConfigOption = "zone_config"
+	intervalPartitioningScheduleOption   = "schedule"
+)
+
+const (
+	// defaultIntervalPartitioningPrecreate is the default number of partitions
+	// created ahead of time.
+	defaultIntervalPartitioningPrecreate = 3
+	// maxIntervalPartitioningPrecreate is the maximum number of partitions
+	// created ahead of time.
+	maxIntervalPartitioningPrecreate = 1000
+	// defaultIntervalPartitioningRecurrence is the default crontab expression
+	// of the schedule maintaining the partitions.
+	defaultIntervalPartitioningRecurrence = "@hourly"
+	// intervalPartitioningDeleteBatchSize is the number of rows of an expired
+	// partition deleted by each DELETE statement.
+	intervalPartitioningDeleteBatchSize = 10000
+)
+
+// intervalPartitioningMonthsOrigin and intervalPartitioningOrigin are the
+// instants the partitions of an interval partitioning are aligned on, for
+// intervals which are a number of months and fixed durations respectively.
+// The latter is a Monday, so that weekly partitions start on Mondays.
+var (
+	intervalPartitioningMonthsOrigin = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+	intervalPartitioningOrigin       = time.Date(2000, time.January, 3, 0, 0, 0, 0, time.UTC)
+)
+
+// MakeIntervalPartitioning validates the INTERVAL clause of a PARTITION BY
+// RANGE on the given column and returns its descriptor, with the default
+// values of the options which were not specified.
+func MakeIntervalPartitioning(
+	ctx context.Context, evalCtx *tree.EvalContext, n *tree.PartitionInterval, col catalog.Column,
+) (*descpb.PartitioningDescriptor_Interval, error) {
+	switch col.GetType().Family() {
+	case types.TimestampFamily, types.TimestampTZFamily:
+	default:
+		return nil, pgerror.Newf(pgcode.InvalidObjectDefinition,
+			"INTERVAL partitioning requires a TIMESTAMP or TIMESTAMPTZ column, but column %q has type %s",
+			col.GetName(), col.GetType().SQLString())
+	}
+
+	d, err := evalIntervalPartitioningOption(ctx, evalCtx, n.Interval, types.Interval, "partition interval")
+	if err != nil {
+		return nil, err
+	}
+	interval := &descpb.PartitioningDescriptor_Interval{
+		Interval:   tree.MustBeDInterval(d).Duration.String(),
+		Precreate:  defaultIntervalPartitioningPrecreate,
+		Recurrence: defaultIntervalPartitioningRecurrence,
+	}
+	if _, err := parseIntervalPartitioningDuration(interval.Interval); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[tree.Name]struct{}, len(n.Options))
+	for _, opt := range n.Options {
+		if _, ok := seen[opt.Key]; ok {
+			return nil, pgerror.Newf(pgcode.Syntax, "option %q specified multiple times", opt.Key)
+		}
+		seen[opt.Key] = struct{}{}
+		if opt.Value == nil {
+			return nil, pgerror.Newf(pgcode.Syntax, "option %q requires a value", opt.Key)
+		}
+		switch opt.Key {
+		case intervalPartitioningPrecreateOption:
+			d, err := evalIntervalPartitioningOption(ctx, evalCtx, opt.Value, types.Int, string(opt.Key))
+			if err != nil {
+				return nil, err
+			}
+			precreate := int64(tree.MustBeDInt(d))
+			if precreate < 0 || precreate > maxIntervalPartitioningPrecreate {
+				return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+					"%s must be between 0 and %d", opt.Key, maxIntervalPartitioningPrecreate)
+			}
+			interval.Precreate = int32(precreate)
+		case intervalPartitioningRetentionOption:
+			d, err := evalIntervalPartitioningOption(ctx, evalCtx, opt.Value, types.Interval, string(opt.Key))
+			if err != nil {
+				return nil, err
+			}
+			retention := tree.MustBeDInterval(d).Duration
+			if retention.Compare(duration.Duration{}) <= 0 ||
+				retention.Months < 0 || retention.Days < 0 || retention.Nanos() < 0 {
+				return nil, pgerror.Newf(pgcode.InvalidParameterValue, "%s must be positive", opt.Key)
+			}
+			interval.Retention = retention.String()
+		case intervalPartitioningArchiveOption:
+			d, err := evalIntervalPartitioningOption(ctx, evalCtx, opt.Value, types.String, string(opt.Key))
+			if err != nil {
+				return nil, err
+			}
+			interval.ArchiveURI = string(tree.MustBeDString(d))
+			if _, err := url.Parse(interval.ArchiveURI); err != nil {
+				return nil, pgerror.Wrapf(err, pgcode.InvalidParameterValue, "invalid %s URI", opt.Key)
+			}
+		case intervalPartitioningZoneConfigOption:
+			d, err := evalIntervalPartitioningOption(ctx, evalCtx, opt.Value, types.String, string(opt.Key))
+			if err != nil {
+				return nil, err
+			}
+			interval.ZoneConfig = string(tree.MustBeDString(d))
+			var zone zonepb.ZoneConfig
+			if err := yaml.UnmarshalStrict([]byte(interval.ZoneConfig), &zone); err != nil {
+				return nil, pgerror.Wrapf(err, pgcode.InvalidParameterValue, "could not parse %s", opt.Key)
+			}
+		case intervalPartitioningScheduleOption:
+			d, err := evalIntervalPartitioningOption(ctx, evalCtx, opt.Value, types.String, string(opt.Key))
+			if err != nil {
+				return nil, err
+			}
+			interval.Recurrence = string(tree.MustBeDString(d))
+			if _, err := cronexpr.Parse(interval.Recurrence); err != nil {
+				return nil, pgerror.Wrapf(err, pgcode.InvalidParameterValue,
+					"invalid %s %q", opt.Key, interval.Recurrence)
+			}
+		default:
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"unknown partition interval option %q", opt.Key)
+		}
+	}
+	return interval, nil
+}
+
+func evalIntervalPartitioningOption(
+	ctx context.Context, evalCtx *tree.EvalContext, expr tree.Expr, typ *types.T, name string,
+) (tree.Datum, error) {
+	var semaCtx tree.SemaContext
+	typedExpr, err := schemaexpr.SanitizeVarFreeExpr(
+		ctx, expr, typ, name, &semaCtx, tree.VolatilityImmutable,
+	)
+	if err != nil {
+		return nil, err
+	}
+	d, err := typedExpr.Eval(evalCtx)
+	if err != nil {
+		return nil, err
+	}
+	if d == tree.DNull {
+		return nil, pgerror.Newf(pgcode.InvalidParameterValue, "%s must not be NULL", name)
+	}
+	return d, nil
+}
+
+// parseIntervalPartitioningDuration parses the interval of an interval
+// partitioning. It is either a positive number of months or a positive fixed
+// duration, which is a whole number of seconds.
+func parseIntervalPartitioningDuration(s string) (duration.Duration, error) {
+	di, err := tree.ParseDInterval(duration.IntervalStyle_POSTGRES, s)
+	if err != nil {
+		return duration.Duration{}, err
+	}
+	d := di.Duration
+	switch {
+	case d.Months < 0 || d.Days < 0 || d.Nanos() < 0 || d.Compare(duration.Duration{}) == 0:
+		return duration.Duration{}, pgerror.Newf(pgcode.InvalidParameterValue,
+			"partition interval must be positive")
+	case d.Months > 0 && (d.Days != 0 || d.Nanos() != 0):
+		return duration.Duration{}, pgerror.Newf(pgcode.InvalidParameterValue,
+			"partition interval cannot mix months with days or time")
+	case d.Nanos()%int64(time.Second) != 0:
+		return duration.Duration{}, pgerror.Newf(pgcode.InvalidParameterValue,
+			"partition interval must be a whole number of seconds")
+	}
+	return d, nil
+}
+
+// intervalPartitionStart returns the start of the interval of the given
+// length containing t.
+func intervalPartitionStart(d duration.Duration, t time.Time) time.Time {
+	t = t.UTC()
+	if d.Months > 0 {
+		months := int64(t.Year()-intervalPartitioningMonthsOrigin.Year())*12 + int64(t.Month()-time.January)
+		months = floorDiv(months, d.Months) * d.Months
+		return intervalPartitioningMonthsOrigin.AddDate(0, int(months), 0)
+	}
+	step := int64(d.Days)*int64(24*time.Hour) + d.Nanos()
+	n := floorDiv(int64(t.Sub(intervalPartitioningOrigin)), step)
+	return intervalPartitioningOrigin.Add(time.Duration(n * step))
+}
+
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+// intervalPartitionName returns the name of the partition of the interval of
+// the given length starting at start. The name has the precision of the
+// interval: p202201 for months, p20220131 for days, p20220131_1530 for
+// minutes, and p20220131_153000 otherwise.
+func intervalPartitionName(d duration.Duration, start time.Time) string {
+	start = start.UTC()
+	switch {
+	case d.Months > 0:
+		return fmt.Sprintf("p%04d%02d", start.Year(), int(start.Month()))
+	case d.Nanos() == 0:
+		return fmt.Sprintf("p%04d%02d%02d", start.Year(), int(start.Month()), start.Day())
+	case d.Nanos()%int64(time.Minute) == 0:
+		return fmt.Sprintf("p%04d%02d%02d_%02d%02d",
+			start.Year(), int(start.Month()), start.Day(), start.Hour(), start.Minute())
+	default:
+		return fmt.Sprintf("p%04d%02d%02d_%02d%02d%02d",
+			start.Year(), int(start.Month()), start.Day(), start.Hour(), start.Minute(), start.Second())
+	}
+}
+
+// makeIntervalPartitionBound returns the bound of a partition of an interval
+// partitioning on a column of the given type.
+func makeIntervalPartitionBound(typ *types.T, t time.Time) (tree.Datum, error) {
+	if typ.Family() == types.TimestampTZFamily {
+		return tree.MakeDTimestampTZ(t, time.Microsecond)
+	}
+	return tree.MakeDTimestamp(t.UTC(), time.Microsecond)
+}
+
+// intervalPartitionBound returns the time of a bound of a range partition of
+// an interval partitioning, and false if the bound is MINVALUE or MAXVALUE.
+func intervalPartitionBound(e tree.Expr) (time.Time, bool) {
+	switch t := e.(type) {
+	case *tree.DTimestamp:
+		return t.Time, true
+	case *tree.DTimestampTZ:
+		return t.Time, true
+	}
+	return time.Time{}, false
+}
+
+// MakeIntervalRangePartitions returns the range partitions of the interval
+// partitioning on a column of the given type which cover the time from `from`
+// to the end of the Precreate intervals following the one containing now. If
+// from is zero, or precedes the interval containing now, the partitions start
+// with the interval containing now. The first partition starts at from even if
+// it is not the start of an interval.
+func MakeIntervalRangePartitions(
+	interval *descpb.PartitioningDescriptor_Interval, typ *types.T, from, now time.Time,
+) ([]tree.RangePartition, error) {
+	d, err := parseIntervalPartitioningDuration(interval.Interval)
+	if err != nil {
+		return nil, err
+	}
+	current := intervalPartitionStart(d, now)
+	if from.Before(current) {
+		from = current
+	}
+	end := current
+	for i := 0; i <= int(interval.Precreate); i++ {
+		end = duration.Add(end, d)
+	}
+	var partitions []tree.RangePartition
+	for start := intervalPartitionStart(d, from); start.Before(end); start = duration.Add(start, d) {
+		lower := start
+		if lower.Before(from) {
+			lower = from
+		}
+		fromBound, err := makeIntervalPartitionBound(typ, lower)
+		if err != nil {
+			return nil, err
+		}
+		toBound, err := makeIntervalPartitionBound(typ, duration.Add(start, d))
+		if err != nil {
+			return nil, err
+		}
+		partitions = append(partitions, tree.RangePartition{
+			Name: tree.UnrestrictedName(intervalPartitionName(d, start)),
+			From: tree.Exprs{fromBound},
+			To:   tree.Exprs{toBound},
+		})
+	}
+	return partitions, nil
+}
+
+// intervalPartitioningToAST returns the INTERVAL clause of a PARTITION BY
+// RANGE for the given interval partitioning.
+func intervalPartitioningToAST(
+	interval *descpb.PartitioningDescriptor_Interval,
+) *tree.PartitionInterval {
+	n := &tree.PartitionInterval{
+		Interval: tree.NewStrVal(interval.Interval),
+		Options: tree.KVOptions{{
+			Key:   intervalPartitioningPrecreateOption,
+			Value: tree.NewDInt(tree.DInt(interval.Precreate)),
+		}},
+	}
+	for _, opt := range []struct {
+		key   tree.Name
+		value string
+	}{
+		{intervalPartitioningRetentionOption, interval.Retention},
+		{intervalPartitioningArchiveOption, interval.ArchiveURI},
+		{intervalPartitioningZoneConfigOption, interval.ZoneConfig},
+		{intervalPartitioningScheduleOption, interval.Recurrence},
+	} {
+		if opt.value != "" {
+			n.Options = append(n.Options, tree.KVOption{Key: opt.key, Value: tree.NewStrVal(opt.value)})
+		}
+	}
+	return n
+}
+
+// tableSchedulerEnv returns the environment of the schedules maintaining
+// tables, such as interval partitionings and row-level TTLs.
+func tableSchedulerEnv(execCfg *ExecutorConfig) scheduledjobs.JobSchedulerEnv {
+	if knobs, ok := execCfg.DistSQLSrv.TestingKnobs.JobsTestingKnobs.(*jobs.TestingKnobs); ok {
+		if knobs.JobSchedulerEnv != nil {
+			return knobs.JobSchedulerEnv
+		}
+	}
+	return scheduledjobs.ProdJobSchedulerEnv
+}
+
+// updateIntervalPartitioningSchedule creates, updates or deletes the schedule
+// maintaining the partitions of the primary index of the table after its
+// interval partitioning changed from oldInterval. The ID of a created schedule
+// is stored in the descriptor, which must be written afterwards.
+func (p *planner) updateIntervalPartitioningSchedule(
+	ctx context.Context,
+	tableDesc *tabledesc.Mutable,
+	oldInterval *descpb.PartitioningDescriptor_Interval,
+) error {
+	interval := tableDesc.PrimaryIndex.Partitioning.Interval
+	if interval == nil {
+		if oldInterval == nil {
+			return nil
+		}
+		return p.deleteTableSchedule(ctx, oldInterval.ScheduleID)
+	}
+	scheduleID, err := p.upsertTableSchedule(
+		ctx,
+		interval.ScheduleID,
+		interval.Recurrence,
+		fmt.Sprintf("interval-partitioning-%d", tableDesc.GetID()),
+		tree.ScheduledIntervalPartitioningExecutor,
+		&jobspb.IntervalPartitioningDetails{TableID: tableDesc.GetID()},
+	)
+	if err != nil {
+		return err
+	}
+	interval.ScheduleID = scheduleID
+	return nil
+}
+
+// upsertTableSchedule updates the recurrence of the schedule maintaining a
+// table, or creates the schedule if it does not exist, and returns its ID.
+func (p *planner) upsertTableSchedule(
+	ctx context.Context,
+	scheduleID int64,
+	recurrence string,
+	label string,
+	executor tree.ScheduledJobExecutorType,
+	args protoutil.Message,
+) (int64, error) {
+	ie := p.ExecCfg().InternalExecutor
+	env := tableSchedulerEnv(p.ExecCfg())
+	if scheduleID != jobs.InvalidScheduleID {
+		sj, err := jobs.LoadScheduledJob(ctx, env, scheduleID, ie, p.txn)
+		if err == nil {
+			if sj.ScheduleExpr() == recurrence {
+				return scheduleID, nil
+			}
+			if err := sj.SetSchedule(recurrence); err != nil {
+				return jobs.InvalidScheduleID, err
+			}
+			return scheduleID, sj.Update(ctx, ie, p.txn)
+		}
+		// The schedule may be missing if the table was restored.
+		if !jobs.HasScheduledJobNotFoundError(err) {
+			return jobs.InvalidScheduleID, err
+		}
+	}
+
+	sj := jobs.NewScheduledJob(env)
+	if err := sj.SetSchedule(recurrence); err != nil {
+		return jobs.InvalidScheduleID, err
+	}
+	sj.SetScheduleDetails(jobspb.ScheduleDetails{
+		Wait:    jobspb.ScheduleDetails_SKIP,
+		OnError: jobspb.ScheduleDetails_RETRY_SCHED,
+	})
+	sj.SetScheduleLabel(label)
+	sj.SetOwner(security.NodeUserName())
+	anyArgs, err := pbtypes.MarshalAny(args)
+	if err != nil {
+		return jobs.InvalidScheduleID, err
+	}
+	sj.SetExecutionDetails(executor.InternalName(), jobspb.ExecutionArguments{Args: anyArgs})
+	sj.SetScheduleStatus(string(jobs.StatusPending))
+	if err := sj.Create(ctx, ie, p.txn); err != nil {
+		return jobs.InvalidScheduleID, err
+	}
+	return sj.ScheduleID(), nil
+}
+
+// deleteTableSchedule deletes the schedule maintaining a table.
+func (p *planner) deleteTableSchedule(ctx context.Context, scheduleID int64) error {
+	if scheduleID == jobs.InvalidScheduleID {
+		return nil
+	}
+	env := tableSchedulerEnv(p.ExecCfg())
+	_, err := p.ExecCfg().InternalExecutor.ExecEx(
+		ctx,
+		"delete-table-schedule",
+		p.txn,
+		sessiondata.InternalExecutorOverride{User: security.RootUserName()},
+		fmt.Sprintf("DELETE FROM %s WHERE schedule_id = $1", env.ScheduledJobsTableName()),
+		scheduleID,
+	)
+	return err
+}
+
+type intervalPartitioningResumer struct {
+	job *jobs.Job
+	st  *cluster.Settings
+	sj  *jobs.ScheduledJob
+}
+
+var _ jobs.Resumer = &intervalPartitioningResumer{}
+
+// intervalPartitionedTable is the state of an interval partitioned table read
+// by the intervalPartitioningResumer.
+type intervalPartitionedTable struct {
+	id        descpb.ID
+	name      tree.TableName
+	column    string
+	colType   *types.T
+	indexID   descpb.IndexID
+	indexName string
+	interval  *descpb.PartitioningDescriptor_Interval
+	partBy    *tree.PartitionBy
+}
+
+// Resume implements the jobs.Resumer interface.
+func (r *intervalPartitioningResumer) Resume(ctx context.Context, execCtx interface{}) error {
+	p := execCtx.(JobExecContext)
+	execCfg := p.ExecCfg()
+	details := r.job.Details().(jobspb.IntervalPartitioningDetails)
+	log.Infof(ctx, "starting interval partitioning job for table %d", details.TableID)
+
+	var err error
+	if r.sj, err = markJobScheduleRunning(ctx, execCfg, r.job.ID()); err != nil {
+		return err
+	}
+
+	table, err := r.readTable(ctx, execCfg, details.TableID)
+	if err != nil {
+		return err
+	}
+	if table == nil {
+		// The table was dropped, or is no longer interval partitioned.
+		return r.maybeNotifyJobTerminated(ctx, execCfg, jobs.StatusSucceeded)
+	}
+
+	var progress jobspb.IntervalPartitioningProgress
+	if err := r.maintainPartitions(ctx, execCfg, table, &progress); err != nil {
+		return err
+	}
+	if err := r.job.SetProgress(ctx, nil /* txn */, progress); err != nil {
+		return err
+	}
+	return r.maybeNotifyJobTerminated(ctx, execCfg, jobs.StatusSucceeded)
+}
+
+// readTable reads the interval partitioning of the table, or returns nil if
+// the table does not exist or is no longer interval partitioned.
+func (r *intervalPartitioningResumer) readTable(
+	ctx context.Context, execCfg *ExecutorConfig, tableID descpb.ID,
+) (*intervalPartitionedTable, error) {
+	var table *intervalPartitionedTable
+	err := DescsTxn(ctx, execCfg, func(ctx context.Context, txn *kv.Txn, col *descs.Collection) error {
+		table = nil
+		flags := tree.ObjectLookupFlagsWithRequired()
+		flags.IncludeDropped = true
+		flags.IncludeOffline = true
+		desc, err := col.GetImmutableTableByID(ctx, txn, tableID, flags)
+		if err != nil {
+			if errors.Is(err, catalog.ErrDescriptorNotFound) {
+				return nil
+			}
+			return err
+		}
+		idx := desc.GetPrimaryIndex()
+		part := idx.GetPartitioning()
+		interval := part.PartitioningDesc().Interval
+		if !desc.Public() || interval == nil {
+			return nil
+		}
+		_, dbDesc, err := col.GetImmutableDatabaseByID(ctx, txn, desc.GetParentID(),
+			tree.DatabaseLookupFlags{Required: true})
+		if err != nil {
+			return err
+		}
+		scDesc, err := col.GetImmutableSchemaByID(ctx, txn, desc.GetParentSchemaID(),
+			tree.SchemaLookupFlags{Required: true})
+		if err != nil {
+			return err
+		}
+		partBy, err := partitionByFromTableDescImpl(execCfg.Codec, desc, idx, part, 0 /* colOffset */)
+		if err != nil {
+			return err
+		}
+		column, err := desc.FindColumnWithID(idx.GetKeyColumnID(int(part.NumImplicitColumns())))
+		if err != nil {
+			return err
+		}
+		table = &intervalPartitionedTable{
+			id: tableID,
+			name: tree.MakeTableNameWithSchema(
+				tree.Name(dbDesc.GetName()), tree.Name(scDesc.GetName()), tree.Name(desc.GetName()),
+			),
+			column:    column.GetName(),
+			colType:   column.GetType(),
+			indexID:   idx.GetID(),
+			indexName: idx.GetName(),
+			interval:  interval,
+			partBy:    partBy,
+		}
+		return nil
+	})
+	return table, err
+}
+
+// maintainPartitions deletes, after archiving them, the rows of the expired
+// partitions of the table and repartitions it without them and with the
+// partitions of the upcoming intervals.
+func (r *intervalPartitioningResumer) maintainPartitions(
+	ctx context.Context,
+	execCfg *ExecutorConfig,
+	table *intervalPartitionedTable,
+	progress *jobspb.IntervalPartitioningProgress,
+) error {
+	now := execCfg.Clock.PhysicalTime()
+	var cutoff time.Time
+	if table.interval.Retention != "" {
+		retention, err := tree.ParseDInterval(duration.IntervalStyle_POSTGRES, table.interval.Retention)
+		if err != nil {
+			return err
+		}
+		cutoff = duration.Add(now, retention.Duration.Mul(-1))
+	}
+
+	var kept []tree.RangePartition
+	var last time.Time
+	unbounded := false
+	for _, rp := range table.partBy.Range {
+		to, ok := intervalPartitionBound(rp.To[0])
+		if !ok {
+			unbounded = true
+		} else if !cutoff.IsZero() && !to.After(cutoff) {
+			if err := r.expirePartition(ctx, execCfg, table, rp); err != nil {
+				return errors.Wrapf(err, "expiring partition %s", rp.Name)
+			}
+			progress.ExpiredPartitions = append(progress.ExpiredPartitions, string(rp.Name))
+			continue
+		} else if to.After(last) {
+			last = to
+		}
+		kept = append(kept, rp)
+	}
+
+	var added []tree.RangePartition
+	if !unbounded {
+		var err error
+		added, err = MakeIntervalRangePartitions(table.interval, table.colType, last, now)
+		if err != nil {
+			return err
+		}
+	}
+	for _, rp := range added {
+		progress.CreatedPartitions = append(progress.CreatedPartitions, string(rp.Name))
+	}
+
+	ie := execCfg.InternalExecutor
+	override := sessiondata.InternalExecutorOverride{User: security.RootUserName()}
+	if len(progress.ExpiredPartitions) > 0 || len(added) > 0 {
+		partBy := &tree.PartitionBy{
+			Fields:   table.partBy.Fields,
+			Range:    append(kept, added...),
+			Interval: table.partBy.Interval,
+		}
+		stmt := fmt.Sprintf("ALTER TABLE %s%s", table.name.FQString(),
+			tree.AsStringWithFlags(&tree.PartitionByTable{PartitionBy: partBy}, tree.FmtParsable))
+		if _, err := ie.ExecEx(ctx, "interval-partitioning-alter", nil /* txn */, override, stmt); err != nil {
+			return err
+		}
+		table.partBy = partBy
+	}
+
+	if table.interval.ZoneConfig == "" {
+		return nil
+	}
+	var unconfigured []string
+	if err := execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		unconfigured = nil
+		zone, err := getZoneConfigRaw(ctx, txn, execCfg.Codec, execCfg.Settings, table.id)
+		if err != nil {
+			return err
+		}
+		for _, rp := range table.partBy.Range {
+			if zone == nil || zone.GetSubzone(uint32(table.indexID), string(rp.Name)) == nil {
+				unconfigured = append(unconfigured, string(rp.Name))
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	for _, name := range unconfigured {
+		stmt := fmt.Sprintf("ALTER PARTITION %s OF INDEX %s@%s CONFIGURE ZONE = %s",
+			tree.NameString(name), table.name.FQString(), tree.NameString(table.indexName),
+			lexbase.EscapeSQLString(table.interval.ZoneConfig))
+		if _, err := ie.ExecEx(ctx, "interval-partitioning-zone", nil /* txn */, override, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expirePartition exports the rows of an expired partition to the archive, if
+// any, and deletes them.
+func (r *intervalPartitioningResumer) expirePartition(
+	ctx context.Context,
+	execCfg *ExecutorConfig,
+	table *intervalPartitionedTable,
+	rp tree.RangePartition,
+) error {
+	cond := fmt.Sprintf("%s < %s",
+		tree.NameString(table.column), tree.AsStringWithFlags(rp.To[0], tree.FmtParsable))
+	if _, ok := intervalPartitionBound(rp.From[0]); ok {
+		cond = fmt.Sprintf("%s >= %s AND %s",
+			tree.NameString(table.column), tree.AsStringWithFlags(rp.From[0], tree.FmtParsable), cond)
+	}
+
+	ie := execCfg.InternalExecutor
+	override := sessiondata.InternalExecutorOverride{User: security.RootUserName()}
+	if table.interval.ArchiveURI != "" {
+		dest, err := url.Parse(table.interval.ArchiveURI)
+		if err != nil {
+			return err
+		}
+		dest.Path = path.Join(dest.Path, string(rp.Name))
+		stmt := fmt.Sprintf("EXPORT INTO CSV %s FROM SELECT * FROM %s WHERE %s",
+			lexbase.EscapeSQLString(dest.String()), table.name.FQString(), cond)
+		if _, err := ie.ExecEx(ctx, "interval-partitioning-archive", nil /* txn */, override, stmt); err != nil {
+			return err
+		}
+	}
+
+	stmt := fmt.Sprintf("DELETE FROM %s WHERE %s LIMIT %d",
+		table.name.FQString(), cond, intervalPartitioningDeleteBatchSize)
+	for {
+		n, err := ie.ExecEx(ctx, "interval-partitioning-delete", nil /* txn */, override, stmt)
+		if err != nil {
+			return err
+		}
+		if n < intervalPartitioningDeleteBatchSize {
+			return nil
+		}
+	}
+}
+
+// OnFailOrCancel implements the jobs.Resumer interface.
+func (r *intervalPartitioningResumer) OnFailOrCancel(ctx context.Context, execCtx interface{}) error {
+	p := execCtx.(JobExecContext)
+	return r.maybeNotifyJobTerminated(ctx, p.ExecCfg(), jobs.StatusFailed)
+}
+
+// maybeNotifyJobTerminated notifies the schedule which created the job, if
+// any, of the job termination.
+func (r *intervalPartitioningResumer) maybeNotifyJobTerminated(
+	ctx context.Context, execCfg *ExecutorConfig, status jobs.Status,
+) error {
+	log.Infof(ctx, "interval partitioning job terminated with status = %s", status)
+	if r.sj == nil {
+		return nil
+	}
+	return jobs.NotifyJobTermination(
+		ctx, tableSchedulerEnv(execCfg), r.job.ID(), status, r.job.Details(),
+		r.sj.ScheduleID(), execCfg.InternalExecutor, nil, /* txn */
+	)
+}
+
+// markJobScheduleRunning sets the status of the schedule which created the
+// job, if any, to running, and returns the schedule.
+func markJobScheduleRunning(
+	ctx context.Context, execCfg *ExecutorConfig, jobID jobspb.JobID,
+) (*jobs.ScheduledJob, error) {
+	var sj *jobs.ScheduledJob
+	err := execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		sj = nil
+		env := tableSchedulerEnv(execCfg)
+		scheduleID, err := getJobScheduleID(ctx, env, execCfg.InternalExecutor, txn, jobID)
+		if err != nil || scheduleID == jobs.InvalidScheduleID {
+			return err
+		}
+		sj, err = jobs.LoadScheduledJob(ctx, env, scheduleID, execCfg.InternalExecutor, txn)
+		if err != nil {
+			return err
+		}
+		sj.SetScheduleStatus(string(jobs.StatusRunning))
+		return sj.Update(ctx, execCfg.InternalExecutor, txn)
+	})
+	return sj, err
+}
+
+// getJobScheduleID returns the ID of the schedule which created the job, or
+// jobs.InvalidScheduleID if the job was not created by a schedule.
+func getJobScheduleID(
+	ctx context.Context,
+	env scheduledjobs.JobSchedulerEnv,
+	ie sqlutil.InternalExecutor,
+	txn *kv.Txn,
+	jobID jobspb.JobID,
+) (int64, error) {
+	row, err := ie.QueryRowEx(ctx, "lookup-job-schedule", txn,
+		sessiondata.InternalExecutorOverride{User: security.NodeUserName()},
+		fmt.Sprintf("SELECT created_by_id FROM %s WHERE id = $1 AND created_by_type = $2",
+			env.SystemJobsTableName()),
+		jobID, jobs.CreatedByScheduledJobs,
+	)
+	if err != nil {
+		return jobs.InvalidScheduleID, errors.Wrap(err, "failed to look up the schedule of the job")
+	}
+	if row == nil {
+		return jobs.InvalidScheduleID, nil
+	}
+	return int64(tree.MustBeDInt(row[0])), nil
+}
+
+type intervalPartitioningMetrics struct {
+	*jobs.ExecutorMetrics
+}
+
+var _ metric.Struct = &intervalPartitioningMetrics{}
+
+// MetricStruct implements metric.Struct interface.
+func (m *intervalPartitioningMetrics) MetricStruct() {}
+
+// scheduledIntervalPartitioningExecutor is executed by the scheduled job
+// subsystem to launch intervalPartitioningResumer through the job subsystem.
+type scheduledIntervalPartitioningExecutor struct {
+	metrics intervalPartitioningMetrics
+}
+
+var _ jobs.ScheduledJobExecutor = &scheduledIntervalPartitioningExecutor{}
+var _ jobs.ScheduledJobController = &scheduledIntervalPartitioningExecutor{}
+
+func intervalPartitioningScheduleArgs(
+	sj *jobs.ScheduledJob,
+) (*jobspb.IntervalPartitioningDetails, error) {
+	args := &jobspb.IntervalPartitioningDetails{}
+	if err := pbtypes.UnmarshalAny(sj.ExecutionArgs().Args, args); err != nil {
+		return nil, errors.Wrap(err, "un-marshaling args")
+	}
+	return args, nil
+}
+
+// OnDrop implements the jobs.ScheduledJobController interface.
+func (e *scheduledIntervalPartitioningExecutor) OnDrop(
+	ctx context.Context,
+	scheduleControllerEnv scheduledjobs.ScheduleControllerEnv,
+	env scheduledjobs.JobSchedulerEnv,
+	schedule *jobs.ScheduledJob,
+	txn *kv.Txn,
+) error {
+	args, err := intervalPartitioningScheduleArgs(schedule)
+	if err != nil {
+		return err
+	}
+	return errors.WithHint(
+		pgerror.Newf(pgcode.InvalidParameterValue,
+			"schedule %d maintains the partitions of table %d and cannot be dropped",
+			schedule.ScheduleID(), args.TableID),
+		"remove the INTERVAL from the PARTITION BY of the table instead.",
+	)
+}
+
+// ExecuteJob implements the jobs.ScheduledJobExecutor interface.
+func (e *scheduledIntervalPartitioningExecutor) ExecuteJob(
+	ctx context.Context,
+	cfg *scheduledjobs.JobExecutionConfig,
+	env scheduledjobs.JobSchedulerEnv,
+	sj *jobs.ScheduledJob,
+	txn *kv.Txn,
+) error {
+	if err := e.createIntervalPartitioningJob(ctx, cfg, sj, txn); err != nil {
+		e.metrics.NumFailed.Inc(1)
+		return err
+	}
+	e.metrics.NumStarted.Inc(1)
+	return nil
+}
+
+func (e *scheduledIntervalPartitioningExecutor) createIntervalPartitioningJob(
+	ctx context.Context, cfg *scheduledjobs.JobExecutionConfig, sj *jobs.ScheduledJob, txn *kv.Txn,
+) error {
+	args, err := intervalPartitioningScheduleArgs(sj)
+	if err != nil {
+		return err
+	}
+	p, cleanup := cfg.PlanHookMaker("invoke-interval-partitioning", txn, security.NodeUserName())
+	defer cleanup()
+
+	registry := p.(*planner).ExecCfg().JobRegistry
+	record := jobs.Record{
+		Description:   fmt.Sprintf("interval partitioning of table %d", args.TableID),
+		Username:      security.NodeUserName(),
+		DescriptorIDs: descpb.IDs{args.TableID},
+		Details:       *args,
+		Progress:      jobspb.IntervalPartitioningProgress{},
+		CreatedBy: &jobs.CreatedByInfo{
+			ID:   sj.ScheduleID(),
+			Name: jobs.CreatedByScheduledJobs,
+		},
+	}
+	_, err = registry.CreateAdoptableJobWithTxn(ctx, record, registry.MakeJobID(), txn)
+	return err
+}
+
+// NotifyJobTermination implements the jobs.ScheduledJobExecutor interface.
+func (e *scheduledIntervalPartitioningExecutor) NotifyJobTermination(
+	ctx context.Context,
+	jobID jobspb.JobID,
+	jobStatus jobs.Status,
+	details jobspb.Details,
+	env scheduledjobs.JobSchedulerEnv,
+	sj *jobs.ScheduledJob,
+	ex sqlutil.InternalExecutor,
+	txn *kv.Txn,
+) error {
+	if jobStatus == jobs.StatusFailed {
+		jobs.DefaultHandleFailedRun(sj, "interval partitioning %d failed", jobID)
+		e.metrics.NumFailed.Inc(1)
+		return nil
+	}
+	if jobStatus == jobs.StatusSucceeded {
+		e.metrics.NumSucceeded.Inc(1)
+	}
+	sj.SetScheduleStatus(string(jobStatus))
+	return nil
+}
+
+// Metrics implements the jobs.ScheduledJobExecutor interface.
+func (e *scheduledIntervalPartitioningExecutor) Metrics() metric.Struct {
+	return &e.metrics
+}
+
+// GetCreateScheduleStatement implements the jobs.ScheduledJobExecutor interface.
+func (e *scheduledIntervalPartitioningExecutor) GetCreateScheduleStatement(
+	ctx context.Context,
+	env scheduledjobs.JobSchedulerEnv,
+	txn *kv.Txn,
+	sj *jobs.ScheduledJob,
+	ex sqlutil.InternalExecutor,
+) (string, error) {
+	args, err := intervalPartitioningScheduleArgs(sj)
+	if err != nil {
+		return "", err
+	}
+	return "", errors.Newf(
+		"schedule %d is created by the PARTITION BY RANGE ... INTERVAL of table %d",
+		sj.ScheduleID(), args.TableID)
+}
+
+func init() {
+	jobs.RegisterConstructor(jobspb.TypeIntervalPartitioning, func(job *jobs.Job, settings *cluster.Settings) jobs.Resumer {
+		return &intervalPartitioningResumer{
+			job: job,
+			st:  settings,
+		}
+	})
+
+	jobs.RegisterScheduledJobExecutorFactory(
+		tree.ScheduledIntervalPartitioningExecutor.InternalName(),
+		func() (jobs.ScheduledJobExecutor, error) {
+			m := jobs.MakeExecutorMetrics(tree.ScheduledIntervalPartitioningExecutor.InternalName())
+			return &scheduledIntervalPartitioningExecutor{
+				metrics: intervalPartitioningMetrics{
+					ExecutorMetrics: &m,
+				},
+			}, nil
+		})
+}
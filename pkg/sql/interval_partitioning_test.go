@@ -0,0 +1,146 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMakeIntervalRangePartitions tests the alignment, the bounds and the
+// names of the partitions of interval partitionings.
+func TestMakeIntervalRangePartitions(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	now := time.Date(2022, time.March, 15, 13, 47, 12, 0, time.UTC)
+	ts := func(s string) time.Time {
+		t.Helper()
+		res, err := time.Parse("2006-01-02 15:04:05", s)
+		require.NoError(t, err)
+		return res
+	}
+
+	for _, tc := range []struct {
+		interval  string
+		precreate int32
+		from      time.Time
+		expected  []string
+	}{
+		{
+			interval:  "1 mon",
+			precreate: 2,
+			expected: []string{
+				"p202203 [2022-03-01 00:00:00, 2022-04-01 00:00:00)",
+				"p202204 [2022-04-01 00:00:00, 2022-05-01 00:00:00)",
+				"p202205 [2022-05-01 00:00:00, 2022-06-01 00:00:00)",
+			},
+		},
+		{
+			interval: "3 mons",
+			expected: []string{
+				"p202201 [2022-01-01 00:00:00, 2022-04-01 00:00:00)",
+			},
+		},
+		{
+			interval:  "7 days",
+			precreate: 1,
+			expected: []string{
+				"p20220314 [2022-03-14 00:00:00, 2022-03-21 00:00:00)",
+				"p20220321 [2022-03-21 00:00:00, 2022-03-28 00:00:00)",
+			},
+		},
+		{
+			interval:  "06:00:00",
+			precreate: 1,
+			expected: []string{
+				"p20220315_1200 [2022-03-15 12:00:00, 2022-03-15 18:00:00)",
+				"p20220315_1800 [2022-03-15 18:00:00, 2022-03-16 00:00:00)",
+			},
+		},
+		{
+			interval: "00:01:30",
+			expected: []string{
+				"p20220315_134630 [2022-03-15 13:46:30, 2022-03-15 13:48:00)",
+			},
+		},
+		{
+			// Partitions following existing ones start at their upper bound, even if
+			// it is not aligned.
+			interval:  "1 day",
+			precreate: 1,
+			from:      ts("2022-03-15 08:00:00"),
+			expected: []string{
+				"p20220315 [2022-03-15 08:00:00, 2022-03-16 00:00:00)",
+				"p20220316 [2022-03-16 00:00:00, 2022-03-17 00:00:00)",
+			},
+		},
+		{
+			// Past intervals are not created.
+			interval: "1 day",
+			from:     ts("2022-01-01 00:00:00"),
+			expected: []string{
+				"p20220315 [2022-03-15 00:00:00, 2022-03-16 00:00:00)",
+			},
+		},
+		{
+			// Nothing is created if the existing partitions cover the precreated
+			// intervals.
+			interval: "1 day",
+			from:     ts("2022-03-16 00:00:00"),
+			expected: nil,
+		},
+	} {
+		t.Run(fmt.Sprintf("%s/%d", tc.interval, tc.precreate), func(t *testing.T) {
+			interval := &descpb.PartitioningDescriptor_Interval{
+				Interval:  tc.interval,
+				Precreate: tc.precreate,
+			}
+			partitions, err := sql.MakeIntervalRangePartitions(interval, types.Timestamp, tc.from, now)
+			require.NoError(t, err)
+			var actual []string
+			for _, p := range partitions {
+				require.Len(t, p.From, 1)
+				require.Len(t, p.To, 1)
+				actual = append(actual, fmt.Sprintf("%s [%s, %s)", p.Name,
+					p.From[0].(*tree.DTimestamp).Time.Format("2006-01-02 15:04:05"),
+					p.To[0].(*tree.DTimestamp).Time.Format("2006-01-02 15:04:05"),
+				))
+			}
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+
+	t.Run("timestamptz", func(t *testing.T) {
+		interval := &descpb.PartitioningDescriptor_Interval{Interval: "1 day"}
+		partitions, err := sql.MakeIntervalRangePartitions(interval, types.TimestampTZ, time.Time{}, now)
+		require.NoError(t, err)
+		require.Len(t, partitions, 1)
+		require.Equal(t, ts("2022-03-15 00:00:00"), partitions[0].From[0].(*tree.DTimestampTZ).Time.UTC())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, s := range []string{"0 days", "-1 day", "1 mon 1 day", "1.5 seconds"} {
+			interval := &descpb.PartitioningDescriptor_Interval{Interval: s}
+			_, err := sql.MakeIntervalRangePartitions(interval, types.Timestamp, time.Time{}, now)
+			require.Error(t, err, s)
+		}
+	})
+}
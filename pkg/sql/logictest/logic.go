@@ -568,6 +568,15 @@ var logicTestConfigs = []testClusterConfig{
 		binaryVersion:       roachpb.Version{Major: 21, Minor: 2},
 		disableUpgrade:      true,
 	},
+	{
+		name:                "local-mixed-21.2-22.1",
+		numNodes:            1,
+		overrideDistSQLMode: "off",
+		overrideAutoStats:   "false",
+		bootstrapVersion:    roachpb.Version{Major: 21, Minor: 2},
+		binaryVersion:       clusterversion.TestingBinaryVersion,
+		disableUpgrade:      true,
+	},
 	{
 		name:                                "local-spec-planning",
 		numNodes:                            1,
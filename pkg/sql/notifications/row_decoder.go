@@ -0,0 +1,102 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package notifications
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/systemschema"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/errors"
+)
+
+// rowDecoder decodes rows from the system.notifications table.
+type rowDecoder struct {
+	codec     keys.SQLCodec
+	alloc     rowenc.DatumAlloc
+	colIdxMap catalog.TableColMap
+}
+
+func makeRowDecoder(codec keys.SQLCodec) rowDecoder {
+	return rowDecoder{
+		codec: codec,
+		colIdxMap: row.ColIDtoRowIndexFromCols(
+			systemschema.NotificationsTable.PublicColumns(),
+		),
+	}
+}
+
+// decodeRow decodes a row of the system.notifications table. The caller is
+// expected to have filtered out deletions, which carry no value.
+func (d *rowDecoder) decodeRow(kv roachpb.KeyValue) (id int64, _ Notification, _ error) {
+	tbl := systemschema.NotificationsTable
+	// First we need to decode the id field from the index key.
+	{
+		types := []*types.T{tbl.PublicColumns()[0].GetType()}
+		idRow := make([]rowenc.EncDatum, 1)
+		_, matches, _, err := rowenc.DecodeIndexKey(d.codec, tbl, tbl.GetPrimaryIndex(), types, idRow, nil, kv.Key)
+		if err != nil {
+			return 0, Notification{}, errors.Wrap(err, "failed to decode key")
+		}
+		if !matches {
+			return 0, Notification{}, errors.Errorf("unexpected non-notifications KV with notifications prefix: %v", kv.Key)
+		}
+		if err := idRow[0].EnsureDecoded(types[0], &d.alloc); err != nil {
+			return 0, Notification{}, err
+		}
+		id = int64(tree.MustBeDInt(idRow[0].Datum))
+	}
+
+	// The rest of the columns are stored as a family, packed with diff-encoded
+	// column IDs followed by their values.
+	var n Notification
+	bytes, err := kv.Value.GetTuple()
+	if err != nil {
+		return 0, Notification{}, err
+	}
+	var colIDDiff uint32
+	var lastColID descpb.ColumnID
+	var res tree.Datum
+	for len(bytes) > 0 {
+		_, _, colIDDiff, _, err = encoding.DecodeValueTag(bytes)
+		if err != nil {
+			return 0, Notification{}, err
+		}
+		colID := lastColID + descpb.ColumnID(colIDDiff)
+		lastColID = colID
+		idx, ok := d.colIdxMap.Get(colID)
+		if !ok {
+			return 0, Notification{}, errors.Errorf("unknown column: %v", colID)
+		}
+		res, bytes, err = rowenc.DecodeTableValue(&d.alloc, tbl.PublicColumns()[idx].GetType(), bytes)
+		if err != nil {
+			return 0, Notification{}, err
+		}
+		switch colID {
+		case tbl.PublicColumns()[1].GetID(): // created
+		case tbl.PublicColumns()[2].GetID(): // channel
+			n.Channel = string(tree.MustBeDString(res))
+		case tbl.PublicColumns()[3].GetID(): // payload
+			n.Payload = string(tree.MustBeDString(res))
+		case tbl.PublicColumns()[4].GetID(): // pid
+			n.PID = int32(tree.MustBeDInt(res))
+		default:
+			return 0, Notification{}, errors.Errorf("unknown column: %v", colID)
+		}
+	}
+	return id, n, nil
+}
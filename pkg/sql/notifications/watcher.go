@@ -0,0 +1,333 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package notifications implements the cluster-wide delivery of the
+// notifications sent with NOTIFY and pg_notify() to the sessions which
+// have issued a matching LISTEN.
+//
+// Notifications are written to the system.notifications table as part of the
+// sending transaction, which gives them transactional semantics for free: they
+// only become visible once (and if) that transaction commits. Every SQL
+// instance runs a Watcher, which establishes a rangefeed over the table and
+// hands the rows it observes to the local Listeners subscribed to the
+// corresponding channel. Rows are removed by a periodic cleanup task once they
+// are older than sql.notifications.retention.
+package notifications
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/rangefeed"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// Retention is the amount of time a notification is kept in
+// system.notifications before it is removed.
+var Retention = settings.RegisterDurationSetting(
+	"sql.notifications.retention",
+	"the amount of time notifications sent with NOTIFY are retained before being garbage collected",
+	10*time.Minute,
+	settings.PositiveDuration,
+)
+
+// cleanupInterval is the interval at which expired notifications are removed
+// from system.notifications.
+var cleanupInterval = settings.RegisterDurationSetting(
+	"sql.notifications.cleanup_interval",
+	"the interval at which expired notifications are removed",
+	time.Minute,
+	settings.PositiveDuration,
+)
+
+// cleanupBatchSize bounds the number of rows removed from
+// system.notifications by a single cleanup statement.
+const cleanupBatchSize = 1000
+
+// MaxPayloadLength is the length, in bytes, notification payloads must stay
+// below. It matches the limit enforced by Postgres.
+const MaxPayloadLength = 8000
+
+// MaxChannelLength is the length, in bytes, channel names must stay below. It
+// matches the maximum identifier length of Postgres.
+const MaxChannelLength = 64
+
+// Notification is a single notification sent on a channel.
+type Notification struct {
+	Channel string
+	Payload string
+	// PID is the process ID of the session which sent the notification, as
+	// reported to its client.
+	PID int32
+}
+
+// Watcher watches system.notifications with a rangefeed and delivers the
+// notifications it observes to the registered Listeners.
+type Watcher struct {
+	settings *cluster.Settings
+	codec    keys.SQLCodec
+	clock    *hlc.Clock
+	f        *rangefeed.Factory
+	stopper  *stop.Stopper
+	ie       sqlutil.InternalExecutor
+	// dec is only used by the rangefeed callback, which is never invoked
+	// concurrently.
+	dec rowDecoder
+
+	mu struct {
+		syncutil.Mutex
+		// listeners maps channel names to the listeners subscribed to them.
+		listeners map[string]map[*Listener]struct{}
+		// seen records the IDs of the notifications delivered recently, along
+		// with the time they were observed, so that values replayed by the
+		// rangefeed after a restart are not delivered twice.
+		seen map[int64]hlc.Timestamp
+	}
+}
+
+// New constructs a new Watcher.
+func New(
+	settings *cluster.Settings,
+	codec keys.SQLCodec,
+	clock *hlc.Clock,
+	f *rangefeed.Factory,
+	stopper *stop.Stopper,
+	ie sqlutil.InternalExecutor,
+) *Watcher {
+	w := &Watcher{
+		settings: settings,
+		codec:    codec,
+		clock:    clock,
+		f:        f,
+		stopper:  stopper,
+		ie:       ie,
+		dec:      makeRowDecoder(codec),
+	}
+	w.mu.listeners = make(map[string]map[*Listener]struct{})
+	w.mu.seen = make(map[int64]hlc.Timestamp)
+	return w
+}
+
+// Start establishes the rangefeed over system.notifications and starts the
+// task which removes expired notifications. Only notifications committed
+// after Start is called are delivered.
+func (w *Watcher) Start(ctx context.Context) error {
+	tablePrefix := w.codec.TablePrefix(keys.NotificationsTableID)
+	tableSpan := roachpb.Span{
+		Key:    tablePrefix,
+		EndKey: tablePrefix.PrefixEnd(),
+	}
+	rf, err := w.f.RangeFeed(ctx, "notifications", tableSpan, w.clock.Now(), w.onValue)
+	if err != nil {
+		return err
+	}
+	w.stopper.AddCloser(rf)
+	return w.stopper.RunAsyncTask(ctx, "notifications-cleanup", w.runCleanup)
+}
+
+func (w *Watcher) onValue(ctx context.Context, kv *roachpb.RangeFeedValue) {
+	// Deletions are the result of the cleanup of expired notifications.
+	if !kv.Value.IsPresent() {
+		return
+	}
+	id, n, err := w.dec.decodeRow(roachpb.KeyValue{Key: kv.Key, Value: kv.Value})
+	if err != nil {
+		log.Warningf(ctx, "failed to decode notifications row %v: %v", kv.Key, err)
+		return
+	}
+	w.deliver(ctx, id, kv.Value.Timestamp, n)
+}
+
+// deliver hands the notification with the given ID to the listeners
+// subscribed to its channel, unless it was delivered already.
+func (w *Watcher) deliver(ctx context.Context, id int64, ts hlc.Timestamp, n Notification) {
+	var toNotify []*Listener
+	func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		if _, ok := w.mu.seen[id]; ok {
+			return
+		}
+		w.mu.seen[id] = ts
+		for l := range w.mu.listeners[n.Channel] {
+			if l.push(ctx, n) {
+				toNotify = append(toNotify, l)
+			}
+		}
+	}()
+	for _, l := range toNotify {
+		l.onNotify()
+	}
+}
+
+func (w *Watcher) runCleanup(ctx context.Context) {
+	ctx, cancel := w.stopper.WithCancelOnQuiesce(ctx)
+	defer cancel()
+	timer := time.NewTimer(cleanupInterval.Get(&w.settings.SV))
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+			if err := w.cleanup(ctx); err != nil {
+				log.Warningf(ctx, "failed to remove expired notifications: %v", err)
+			}
+			timer.Reset(cleanupInterval.Get(&w.settings.SV))
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// cleanup removes the notifications older than the retention period from
+// system.notifications, and forgets about them in the seen set.
+func (w *Watcher) cleanup(ctx context.Context) error {
+	retention := Retention.Get(&w.settings.SV)
+	func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		threshold := w.clock.Now().Add(-retention.Nanoseconds(), 0)
+		for id, ts := range w.mu.seen {
+			if ts.Less(threshold) {
+				delete(w.mu.seen, id)
+			}
+		}
+	}()
+	if !w.settings.Version.IsActive(ctx, clusterversion.NotificationsTable) {
+		return nil
+	}
+	for {
+		n, err := w.ie.ExecEx(
+			ctx, "delete-expired-notifications", nil, /* txn */
+			sessiondata.InternalExecutorOverride{User: security.NodeUserName()},
+			`DELETE FROM system.notifications WHERE created < $1::TIMESTAMP LIMIT $2`,
+			w.clock.PhysicalTime().Add(-retention), cleanupBatchSize,
+		)
+		if err != nil || n < cleanupBatchSize {
+			return err
+		}
+	}
+}
+
+// NewListener returns a new Listener which is not subscribed to any channel.
+// onNotify is called, without any lock held, whenever the listener goes from
+// having no pending notifications to having some. The listener must be closed
+// once it is no longer used.
+func (w *Watcher) NewListener(onNotify func()) *Listener {
+	l := &Listener{w: w, onNotify: onNotify}
+	l.channels = make(map[string]struct{})
+	return l
+}
+
+// maxPendingNotifications bounds the number of notifications buffered by a
+// Listener that are yet to be delivered to the client. Notifications are
+// dropped once the limit is reached.
+const maxPendingNotifications = 10000
+
+// Listener buffers the notifications sent on the channels a session listens
+// on until they are delivered to the client.
+type Listener struct {
+	w        *Watcher
+	onNotify func()
+
+	// channels is the set of channels the listener is subscribed to. It is
+	// protected by w.mu.
+	channels map[string]struct{}
+
+	mu struct {
+		syncutil.Mutex
+		pending []Notification
+	}
+}
+
+var droppedNotificationsLogEvery = log.Every(time.Minute)
+
+// push buffers a notification. It returns true if the listener had no pending
+// notifications before the call, in which case onNotify needs to be called.
+func (l *Listener) push(ctx context.Context, n Notification) (wasEmpty bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.mu.pending) >= maxPendingNotifications {
+		if droppedNotificationsLogEvery.ShouldLog() {
+			log.Warningf(ctx, "dropping notification on channel %q: too many pending notifications", n.Channel)
+		}
+		return false
+	}
+	l.mu.pending = append(l.mu.pending, n)
+	return len(l.mu.pending) == 1
+}
+
+// Listen subscribes the listener to the given channel. It is a no-op if the
+// listener is already subscribed.
+func (l *Listener) Listen(channel string) {
+	l.w.mu.Lock()
+	defer l.w.mu.Unlock()
+	l.channels[channel] = struct{}{}
+	listeners, ok := l.w.mu.listeners[channel]
+	if !ok {
+		listeners = make(map[*Listener]struct{})
+		l.w.mu.listeners[channel] = listeners
+	}
+	listeners[l] = struct{}{}
+}
+
+// Unlisten unsubscribes the listener from the given channel. It is a no-op if
+// the listener is not subscribed.
+func (l *Listener) Unlisten(channel string) {
+	l.w.mu.Lock()
+	defer l.w.mu.Unlock()
+	l.unlistenLocked(channel)
+}
+
+// UnlistenAll unsubscribes the listener from all channels.
+func (l *Listener) UnlistenAll() {
+	l.w.mu.Lock()
+	defer l.w.mu.Unlock()
+	for channel := range l.channels {
+		l.unlistenLocked(channel)
+	}
+}
+
+func (l *Listener) unlistenLocked(channel string) {
+	delete(l.channels, channel)
+	if listeners, ok := l.w.mu.listeners[channel]; ok {
+		delete(listeners, l)
+		if len(listeners) == 0 {
+			delete(l.w.mu.listeners, channel)
+		}
+	}
+}
+
+// Drain returns the pending notifications, in the order in which they were
+// received, and clears them.
+func (l *Listener) Drain() []Notification {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	pending := l.mu.pending
+	l.mu.pending = nil
+	return pending
+}
+
+// Close unsubscribes the listener from all channels and discards its pending
+// notifications.
+func (l *Listener) Close() {
+	l.UnlistenAll()
+	l.Drain()
+}
@@ -0,0 +1,62 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package notifications
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListener(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	ts := hlc.Timestamp{WallTime: 1}
+	w := New(nil /* settings */, keys.SystemSQLCodec, nil /* clock */, nil /* f */, nil /* stopper */, nil /* ie */)
+	var wakeups1, wakeups2 int
+	l1 := w.NewListener(func() { wakeups1++ })
+	l2 := w.NewListener(func() { wakeups2++ })
+
+	l1.Listen("a")
+	l1.Listen("b")
+	l2.Listen("a")
+	// Listening twice on the same channel is a no-op.
+	l2.Listen("a")
+
+	w.deliver(ctx, 1, ts, Notification{Channel: "a", Payload: "1"})
+	w.deliver(ctx, 2, ts, Notification{Channel: "b", Payload: "2"})
+	w.deliver(ctx, 3, ts, Notification{Channel: "c", Payload: "3"})
+	// Notifications replayed by the rangefeed are not delivered twice.
+	w.deliver(ctx, 1, ts, Notification{Channel: "a", Payload: "1"})
+
+	// The callback is only invoked for the first pending notification.
+	require.Equal(t, 1, wakeups1)
+	require.Equal(t, 1, wakeups2)
+	require.Equal(t, []Notification{{Channel: "a", Payload: "1"}, {Channel: "b", Payload: "2"}}, l1.Drain())
+	require.Equal(t, []Notification{{Channel: "a", Payload: "1"}}, l2.Drain())
+	require.Empty(t, l1.Drain())
+
+	l1.Unlisten("a")
+	w.deliver(ctx, 4, ts, Notification{Channel: "a", Payload: "4"})
+	require.Equal(t, 1, wakeups1)
+	require.Equal(t, 2, wakeups2)
+	require.Empty(t, l1.Drain())
+	require.Equal(t, []Notification{{Channel: "a", Payload: "4"}}, l2.Drain())
+
+	l1.UnlistenAll()
+	l2.Close()
+	require.Empty(t, w.mu.listeners)
+}
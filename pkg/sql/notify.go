@@ -0,0 +1,155 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/notifications"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+)
+
+// listenOp is a LISTEN or UNLISTEN statement which takes effect once the
+// transaction it was executed in commits.
+type listenOp struct {
+	channel string
+	// unlisten is set for UNLISTEN.
+	unlisten bool
+	// all is set for UNLISTEN *.
+	all bool
+}
+
+// Listen implements the LISTEN statement.
+// See https://www.postgresql.org/docs/current/sql-listen.html for details.
+func (p *planner) Listen(ctx context.Context, n *tree.Listen) (planNode, error) {
+	return p.addListenOp(n, listenOp{channel: string(n.ChannelName)})
+}
+
+// Unlisten implements the UNLISTEN statement.
+// See https://www.postgresql.org/docs/current/sql-unlisten.html for details.
+func (p *planner) Unlisten(ctx context.Context, n *tree.Unlisten) (planNode, error) {
+	return p.addListenOp(n, listenOp{channel: string(n.ChannelName), unlisten: true, all: n.All})
+}
+
+func (p *planner) addListenOp(n tree.Statement, op listenOp) (planNode, error) {
+	return &delayedNode{
+		name: n.String(),
+		constructor: func(ctx context.Context, p *planner) (planNode, error) {
+			if p.listenOps == nil {
+				return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+					"%s is not supported in this context", n.StatementTag())
+			}
+			*p.listenOps = append(*p.listenOps, op)
+			return newZeroNode(nil /* columns */), nil
+		},
+	}, nil
+}
+
+// NotifyStmt implements the NOTIFY statement.
+// See https://www.postgresql.org/docs/current/sql-notify.html for details.
+func (p *planner) NotifyStmt(ctx context.Context, n *tree.Notify) (planNode, error) {
+	return &delayedNode{
+		name: n.String(),
+		constructor: func(ctx context.Context, p *planner) (planNode, error) {
+			var payload string
+			if n.Payload != nil {
+				payload = n.Payload.RawString()
+			}
+			if err := p.Notify(ctx, string(n.ChannelName), payload); err != nil {
+				return nil, err
+			}
+			return newZeroNode(nil /* columns */), nil
+		},
+	}, nil
+}
+
+// Notify is part of the tree.EvalPlanner interface.
+//
+// The notification is written to system.notifications as part of the current
+// transaction, so it is only delivered to the listening sessions if and when
+// the transaction commits.
+func (p *planner) Notify(ctx context.Context, channel, payload string) error {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.NotificationsTable) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to use NOTIFY",
+			clusterversion.NotificationsTable)
+	}
+	if channel == "" {
+		return pgerror.New(pgcode.InvalidParameterValue, "channel name cannot be empty")
+	}
+	if len(channel) >= notifications.MaxChannelLength {
+		return pgerror.New(pgcode.InvalidParameterValue, "channel name too long")
+	}
+	if len(payload) >= notifications.MaxPayloadLength {
+		return pgerror.New(pgcode.InvalidParameterValue, "payload string too long")
+	}
+	_, err := p.ExecCfg().InternalExecutor.ExecEx(
+		ctx,
+		"notify",
+		p.Txn(),
+		sessiondata.InternalExecutorOverride{User: security.NodeUserName()},
+		`INSERT INTO system.notifications (channel, payload, pid) VALUES ($1, $2, $3)`,
+		channel,
+		payload,
+		p.ExtendedEvalContext().SessionID.BackendPID(),
+	)
+	return err
+}
+
+// applyListenOps applies the LISTEN and UNLISTEN statements executed by the
+// transaction which just committed. The session's listener is created the
+// first time the session listens on a channel.
+func (ex *connExecutor) applyListenOps() {
+	ops := ex.extraTxnState.listenOps
+	if len(ops) == 0 {
+		return
+	}
+	// Internal executors don't have a client to deliver notifications to.
+	if ex.executorType == executorTypeInternal || ex.server.cfg.NotificationsWatcher == nil {
+		return
+	}
+	if ex.notificationsListener == nil {
+		ctx := ex.ctxHolder.connCtx
+		ex.notificationsListener = ex.server.cfg.NotificationsWatcher.NewListener(func() {
+			// Wake up the session in case it is idle. If it is in the middle of a
+			// transaction instead, the notifications stay pending until the
+			// transaction finishes.
+			_ = ex.stmtBuf.Push(ctx, DeliverNotifications{})
+		})
+	}
+	for _, op := range ops {
+		switch {
+		case op.all:
+			ex.notificationsListener.UnlistenAll()
+		case op.unlisten:
+			ex.notificationsListener.Unlisten(op.channel)
+		default:
+			ex.notificationsListener.Listen(op.channel)
+		}
+	}
+}
+
+// bufferNotifications adds the notifications received on the channels the
+// session is listening on to res. Notifications are only delivered outside of
+// transactions; they remain pending otherwise.
+func (ex *connExecutor) bufferNotifications(res NotificationResult) {
+	if ex.notificationsListener == nil || !ex.idleConn() {
+		return
+	}
+	for _, n := range ex.notificationsListener.Drain() {
+		res.BufferNotification(n)
+	}
+}
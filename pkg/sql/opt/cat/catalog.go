@@ -137,6 +137,14 @@ type Catalog interface {
 	// returns true. Returns an error if query on the `system.users` table failed
 	HasAdminRole(ctx context.Context) (bool, error)
 
+	// HasOwnership returns true if the current user, or a role it is a member
+	// of, owns the given catalog object.
+	HasOwnership(ctx context.Context, o Object) (bool, error)
+
+	// IsMemberOfRole returns true if the current user is the given role or is
+	// a member of it.
+	IsMemberOfRole(ctx context.Context, role string) (bool, error)
+
 	// RequireAdminRole checks that the current user has admin privileges. If not,
 	// returns an error.
 	RequireAdminRole(ctx context.Context, action string) error
@@ -0,0 +1,38 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cat
+
+import "github.com/cockroachdb/cockroach/pkg/util/treeprinter"
+
+// ForeignTable is an interface to a foreign table, whose rows are read from
+// files in external storage. Foreign tables are read-only, and have neither
+// indexes nor statistics, so they can only be planned as full scans.
+type ForeignTable interface {
+	DataSource
+
+	// ColumnCount returns the number of columns of the foreign table.
+	ColumnCount() int
+
+	// Column returns the column at the ith ordinal position within the foreign
+	// table, where i < ColumnCount. Each row read from the files of the table
+	// has one value per column, in this order.
+	Column(i int) *Column
+}
+
+// FormatForeignTable nicely formats a catalog foreign table using a
+// treeprinter for debugging and testing.
+func FormatForeignTable(cat Catalog, ft ForeignTable, tp treeprinter.Node) {
+	child := tp.Childf("FOREIGN TABLE %s", ft.Name())
+	for i := 0; i < ft.ColumnCount(); i++ {
+		col := ft.Column(i)
+		child.Childf("%s %s", col.ColName(), col.DatumType())
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cat
+
+import "github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+
+// Policy is an interface to a row-level security policy defined on a table,
+// exposing only the information needed by the query optimizer. For example:
+//
+//   CREATE POLICY tenant ON t USING (tenant_id = current_setting('app.tenant'))
+//
+// When row-level security is enabled on the table, the USING expression of the
+// applicable policies filters the rows read by queries, and the WITH CHECK
+// expression restricts the rows which can be written.
+type Policy interface {
+	// Name of the policy. The name is unique among the policies on the table.
+	Name() string
+
+	// Command returns the kind of statement the policy applies to.
+	Command() tree.PolicyCommand
+
+	// IsRestrictive returns true if the policy must be satisfied in addition
+	// to one of the permissive policies.
+	IsRestrictive() bool
+
+	// RoleCount returns the number of roles the policy applies to. A policy
+	// with no roles applies to all roles.
+	RoleCount() int
+
+	// Role returns the name of the ith role the policy applies to, where
+	// i < RoleCount.
+	Role(i int) string
+
+	// UsingExpr returns the SQL text of the condition which existing rows must
+	// satisfy, or the empty string if there is no condition.
+	UsingExpr() string
+
+	// WithCheckExpr returns the SQL text of the condition which new rows must
+	// satisfy, or the empty string if there is no condition.
+	WithCheckExpr() string
+}
@@ -133,6 +133,30 @@ type Table interface {
 	// Unique returns the ith unique constraint defined on this table, where
 	// i < UniqueCount.
 	Unique(i UniqueOrdinal) UniqueConstraint
+
+	// TriggerCount returns the number of row-level triggers defined on this
+	// table.
+	TriggerCount() int
+
+	// Trigger returns the ith trigger defined on this table, where
+	// i < TriggerCount. Triggers are ordered by name.
+	Trigger(i int) Trigger
+
+	// IsRowLevelSecurityEnabled returns true if the policies defined on this
+	// table restrict the rows which can be read and written.
+	IsRowLevelSecurityEnabled() bool
+
+	// IsRowLevelSecurityForced returns true if the policies defined on this
+	// table also apply to its owner.
+	IsRowLevelSecurityForced() bool
+
+	// PolicyCount returns the number of row-level security policies defined on
+	// this table.
+	PolicyCount() int
+
+	// Policy returns the ith policy defined on this table, where
+	// i < PolicyCount. Policies are ordered by name.
+	Policy(i int) Policy
 }
 
 // CheckConstraint contains the SQL text and the validity status for a check
@@ -246,6 +270,12 @@ type ForeignKeyConstraint interface {
 	// UpdateReferenceAction returns the action to be performed if the foreign key
 	// constraint would be violated by an update.
 	UpdateReferenceAction() tree.ReferenceAction
+
+	// Deferrability returns whether the validation of the foreign key
+	// constraint can be deferred until the end of the transaction. The data of
+	// a deferrable constraint is not guaranteed to satisfy the constraint
+	// within a transaction.
+	Deferrability() tree.ConstraintDeferrability
 }
 
 // UniqueConstraint represents a uniqueness constraint. UniqueConstraints may
@@ -284,6 +314,12 @@ type UniqueConstraint interface {
 	// cannot make any assumptions about the data. An unvalidated constraint still
 	// needs to be enforced on new mutations.
 	Validated() bool
+
+	// Deferrability returns whether the validation of the unique constraint
+	// can be deferred until the end of the transaction. The data of a
+	// deferrable constraint is not guaranteed to satisfy the constraint within
+	// a transaction.
+	Deferrability() tree.ConstraintDeferrability
 }
 
 // UniqueOrdinal identifies a unique constraint (in the context of a Table).
@@ -0,0 +1,62 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cat
+
+import "github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+
+// Trigger is an interface to a row-level trigger defined on a table, exposing
+// only the information needed by the query optimizer. For example:
+//
+//   CREATE TRIGGER audit AFTER UPDATE ON t FOR EACH ROW
+//     INSERT INTO t_audit VALUES (OLD.k, OLD.v, NEW.v)
+//
+// A BEFORE trigger modifies the rows being written by the triggering statement
+// using a list of assignments, and an AFTER trigger executes a statement once
+// the triggering statement has completed. In both cases, the trigger's
+// expressions can refer to the new and old versions of each modified row as
+// NEW.<colname> and OLD.<colname>.
+type Trigger interface {
+	// Name of the trigger. The name is unique among the triggers on the table.
+	Name() string
+
+	// ActionTime returns whether the trigger fires before or after the rows of
+	// the triggering statement are written.
+	ActionTime() tree.TriggerActionTime
+
+	// HasEvent returns true if the trigger fires for rows that are modified by
+	// the given kind of mutation.
+	HasEvent(event tree.TriggerEvent) bool
+
+	// WhenExpr returns the SQL text of the condition which a row must satisfy
+	// for the trigger to fire, or the empty string if there is no condition.
+	WhenExpr() string
+
+	// Body returns the SQL text of the statement executed by an AFTER trigger.
+	// It is empty for BEFORE triggers.
+	Body() string
+
+	// AssignmentCount returns the number of assignments to the new row made by
+	// a BEFORE trigger. It is zero for AFTER triggers.
+	AssignmentCount() int
+
+	// Assignment returns the ith assignment, where i < AssignmentCount.
+	Assignment(i int) TriggerAssignment
+}
+
+// TriggerAssignment is an assignment of the value of a scalar expression to a
+// column of the new row, made by a BEFORE trigger.
+type TriggerAssignment struct {
+	// ColumnOrdinal is the ordinal of the assigned column (see Table.Column).
+	ColumnOrdinal int
+
+	// Expr is the SQL text of the assigned expression.
+	Expr string
+}
@@ -156,6 +156,7 @@ func (cb *cascadeBuilder) setupCascade(cascade *memo.FKCascade) exec.Cascade {
 	return exec.Cascade{
 		FKName: cascade.FKName,
 		Buffer: cb.mutationBuffer,
+		PerRow: cascade.PerRow,
 		PlanFn: func(
 			ctx context.Context,
 			semaCtx *tree.SemaContext,
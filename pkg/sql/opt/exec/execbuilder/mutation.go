@@ -124,6 +124,10 @@ func (b *Builder) buildInsert(ins *memo.InsertExpr) (execPlan, error) {
 		return execPlan{}, err
 	}
 
+	if err := b.buildFKCascades(ins.WithID, ins.FKCascades); err != nil {
+		return execPlan{}, err
+	}
+
 	return ep, nil
 }
 
@@ -157,6 +161,12 @@ func (b *Builder) tryBuildFastPathInsert(ins *memo.InsertExpr) (_ execPlan, ok b
 		return execPlan{}, false, nil
 	}
 
+	// We cannot use the fast path if there are cascades (e.g. AFTER INSERT
+	// triggers) to run after the insert.
+	if len(ins.FKCascades) > 0 {
+		return execPlan{}, false, nil
+	}
+
 	md := b.mem.Metadata()
 	tab := md.Table(ins.Table)
 
@@ -169,6 +179,10 @@ func (b *Builder) tryBuildFastPathInsert(ins *memo.InsertExpr) (_ execPlan, ok b
 			// Self-referencing FK.
 			return execPlan{}, false, nil
 		}
+		if c.Deferred {
+			// The fast path cannot record the violations of deferred checks.
+			return execPlan{}, false, nil
+		}
 		fk := tab.OutboundForeignKey(c.FKOrdinal)
 		lookupJoin, isLookupJoin := c.Check.(*memo.LookupJoinExpr)
 		if !isLookupJoin || lookupJoin.JoinType != opt.AntiJoinOp {
@@ -811,6 +825,8 @@ func mutationOutputColMap(mutation memo.RelExpr) opt.ColMap {
 // The checks consist of queries that will only return rows if a constraint is
 // violated. Those queries are each wrapped in an ErrorIfRows operator, which
 // will throw an appropriate error in case the inner query returns any rows.
+// The rows returned by deferred checks are instead recorded in the transaction,
+// to be validated again before it commits.
 func (b *Builder) buildUniqueChecks(checks memo.UniqueChecksExpr) error {
 	md := b.mem.Metadata()
 	for i := range checks {
@@ -826,6 +842,13 @@ func (b *Builder) buildUniqueChecks(checks memo.UniqueChecksExpr) error {
 			for i, col := range c.KeyCols {
 				keyVals[i] = row[query.getNodeColumnOrdinal(col)]
 			}
+			if c.Deferred {
+				tab := md.Table(c.Table)
+				b.evalCtx.DeferredConstraints.DeferConstraintCheck(
+					tree.ID(tab.ID()), tab.Unique(c.CheckOrdinal).Name(), false /* isFK */, keyVals,
+				)
+				return nil
+			}
 			return mkUniqueCheckErr(md, c, keyVals)
 		}
 		node, err := b.factory.ConstructErrorIfRows(query.root, mkErr)
@@ -852,6 +875,19 @@ func (b *Builder) buildFKChecks(checks memo.FKChecksExpr) error {
 			for i, col := range c.KeyCols {
 				keyVals[i] = row[query.getNodeColumnOrdinal(col)]
 			}
+			if c.Deferred {
+				origin := md.Table(c.OriginTable)
+				var fk cat.ForeignKeyConstraint
+				if c.FKOutbound {
+					fk = origin.OutboundForeignKey(c.FKOrdinal)
+				} else {
+					fk = md.Table(c.ReferencedTable).InboundForeignKey(c.FKOrdinal)
+				}
+				b.evalCtx.DeferredConstraints.DeferConstraintCheck(
+					tree.ID(origin.ID()), fk.Name(), true /* isFK */, keyVals,
+				)
+				return nil
+			}
 			return mkFKCheckErr(md, c, keyVals)
 		}
 		node, err := b.factory.ConstructErrorIfRows(query.root, mkErr)
@@ -249,6 +249,10 @@ func (b *Builder) buildRelational(e memo.RelExpr) (execPlan, error) {
 	case *memo.Max1RowExpr:
 		ep, err = b.buildMax1Row(t)
 
+	case *memo.BarrierExpr:
+		// Barriers only constrain the optimizer; the input is executed as is.
+		ep, err = b.buildRelational(t.Input)
+
 	case *memo.ProjectSetExpr:
 		ep, err = b.buildProjectSet(t)
 
@@ -258,6 +262,9 @@ func (b *Builder) buildRelational(e memo.RelExpr) (execPlan, error) {
 	case *memo.SequenceSelectExpr:
 		ep, err = b.buildSequenceSelect(t)
 
+	case *memo.ForeignScanExpr:
+		ep, err = b.buildForeignScan(t)
+
 	case *memo.InsertExpr:
 		ep, err = b.buildInsert(t)
 
@@ -2412,6 +2419,21 @@ func (b *Builder) buildSequenceSelect(seqSel *memo.SequenceSelectExpr) (execPlan
 	return ep, nil
 }
 
+func (b *Builder) buildForeignScan(scan *memo.ForeignScanExpr) (execPlan, error) {
+	table := b.mem.Metadata().ForeignTable(scan.Table)
+	node, err := b.factory.ConstructForeignScan(table)
+	if err != nil {
+		return execPlan{}, err
+	}
+
+	ep := execPlan{root: node}
+	for i, c := range scan.Cols {
+		ep.outputCols.Set(int(c), i)
+	}
+
+	return ep, nil
+}
+
 func (b *Builder) applySaveTable(
 	input execPlan, e memo.RelExpr, saveTableName string,
 ) (execPlan, error) {
@@ -2557,6 +2579,7 @@ var boundedStalenessAllowList = map[opt.Operator]struct{}{
 	opt.SortOp:             {},
 	opt.OrdinalityOp:       {},
 	opt.Max1RowOp:          {},
+	opt.BarrierOp:          {},
 	opt.ProjectSetOp:       {},
 	opt.WindowOp:           {},
 	opt.ExplainOp:          {},
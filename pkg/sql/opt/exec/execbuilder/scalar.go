@@ -26,6 +26,10 @@ type buildScalarCtx struct {
 	// ivarMap is a map from opt.ColumnID to the index of an IndexedVar.
 	// If a ColumnID is not in the map, it cannot appear in the expression.
 	ivarMap opt.ColMap
+
+	// udfArgs maps the parameters of the user-defined functions being built to
+	// the expressions of the corresponding arguments.
+	udfArgs map[opt.ColumnID]tree.TypedExpr
 }
 
 type buildFunc func(b *Builder, ctx *buildScalarCtx, scalar opt.ScalarExpr) (tree.TypedExpr, error)
@@ -43,6 +47,7 @@ func init() {
 		opt.PlaceholderOp:     (*Builder).buildTypedExpr,
 		opt.TupleOp:           (*Builder).buildTuple,
 		opt.FunctionOp:        (*Builder).buildFunction,
+		opt.UDFOp:             (*Builder).buildUDF,
 		opt.CaseOp:            (*Builder).buildCase,
 		opt.CastOp:            (*Builder).buildCast,
 		opt.CoalesceOp:        (*Builder).buildCoalesce,
@@ -125,7 +130,11 @@ func (b *Builder) buildNull(ctx *buildScalarCtx, scalar opt.ScalarExpr) (tree.Ty
 func (b *Builder) buildVariable(
 	ctx *buildScalarCtx, scalar opt.ScalarExpr,
 ) (tree.TypedExpr, error) {
-	return b.indexedVar(ctx, b.mem.Metadata(), *scalar.Private().(*opt.ColumnID)), nil
+	colID := *scalar.Private().(*opt.ColumnID)
+	if arg, ok := ctx.udfArgs[colID]; ok {
+		return arg, nil
+	}
+	return b.indexedVar(ctx, b.mem.Metadata(), colID), nil
 }
 
 func (b *Builder) indexedVar(
@@ -295,6 +304,72 @@ func (b *Builder) buildFunction(
 	), nil
 }
 
+// buildUDF builds the body of a user-defined function that was not inlined by
+// the optimizer, in which each reference to a parameter is replaced by the
+// corresponding argument. The optbuilder only constructs a UDF expression when
+// no argument with volatile operators is referenced more than once in the
+// body, so this does not duplicate side effects. The arguments which the body
+// doesn't reference are evaluated along with the body, like Postgres evaluates
+// all the arguments of a function call, so that their side effects are not
+// dropped either.
+func (b *Builder) buildUDF(ctx *buildScalarCtx, scalar opt.ScalarExpr) (tree.TypedExpr, error) {
+	udf := scalar.(*memo.UDFExpr)
+	bodyCtx := *ctx
+	bodyCtx.udfArgs = make(map[opt.ColumnID]tree.TypedExpr, len(ctx.udfArgs)+len(udf.Args))
+	for col, arg := range ctx.udfArgs {
+		bodyCtx.udfArgs[col] = arg
+	}
+	for i := range udf.Args {
+		arg, err := b.buildScalar(ctx, udf.Args[i])
+		if err != nil {
+			return nil, err
+		}
+		bodyCtx.udfArgs[udf.Params[i]] = arg
+	}
+	body, err := b.buildScalar(&bodyCtx, udf.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Evaluate the unreferenced arguments and the body as the elements of a
+	// tuple, and return its last element.
+	refs := udfParamRefs(udf.Body, udf.Params)
+	var exprs tree.Exprs
+	var typs []*types.T
+	for i, col := range udf.Params {
+		if !refs.Contains(col) {
+			arg := bodyCtx.udfArgs[col]
+			exprs = append(exprs, arg)
+			typs = append(typs, udf.Args[i].DataType())
+		}
+	}
+	if len(exprs) == 0 {
+		return body, nil
+	}
+	exprs = append(exprs, body)
+	typs = append(typs, body.ResolvedType())
+	tuple := tree.NewTypedTuple(types.MakeTuple(typs), exprs)
+	return tree.NewTypedColumnAccessExpr(tuple, "" /* colName */, len(exprs)-1), nil
+}
+
+// udfParamRefs returns the parameters of a user-defined function which are
+// referenced in the given expression.
+func udfParamRefs(e opt.Expr, params opt.ColList) opt.ColSet {
+	var refs opt.ColSet
+	if v, ok := e.(*memo.VariableExpr); ok {
+		for _, col := range params {
+			if col == v.Col {
+				refs.Add(col)
+			}
+		}
+		return refs
+	}
+	for i, n := 0, e.ChildCount(); i < n; i++ {
+		refs.UnionWith(udfParamRefs(e.Child(i), params))
+	}
+	return refs
+}
+
 func (b *Builder) buildCase(ctx *buildScalarCtx, scalar opt.ScalarExpr) (tree.TypedExpr, error) {
 	cas := scalar.(*memo.CaseExpr)
 	input, err := b.buildScalar(ctx, cas.Input)
@@ -295,6 +295,7 @@ var nodeNames = [...]string{
 	scanBufferOp:           "scan buffer",
 	scanOp:                 "", // This node does not have a fixed name.
 	sequenceSelectOp:       "sequence select",
+	foreignScanOp:          "foreign scan",
 	hashSetOpOp:            "", // This node does not have a fixed name.
 	streamingSetOpOp:       "", // This node does not have a fixed name.
 	unionAllOp:             "union all",
@@ -472,6 +473,10 @@ func (e *emitter) emitNodeAttributes(n *Node) error {
 		}
 		e.emitLockingPolicy(a.Params.Locking)
 
+	case foreignScanOp:
+		a := n.args.(*foreignScanArgs)
+		ob.Attr("table", a.Table.Name())
+
 	case valuesOp:
 		a := n.args.(*valuesArgs)
 		// Don't emit anything for the "norows" and "emptyrow" cases.
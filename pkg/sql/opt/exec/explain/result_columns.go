@@ -161,6 +161,9 @@ func getResultColumns(
 	case sequenceSelectOp:
 		return colinfo.SequenceSelectColumns, nil
 
+	case foreignScanOp:
+		return foreignTableColumns(args.(*foreignScanArgs).Table), nil
+
 	case explainOp:
 		return colinfo.ExplainPlanColumns, nil
 
@@ -195,6 +198,18 @@ func tableColumns(table cat.Table, ordinals exec.TableColumnOrdinalSet) colinfo.
 	return cols
 }
 
+func foreignTableColumns(table cat.ForeignTable) colinfo.ResultColumns {
+	cols := make(colinfo.ResultColumns, table.ColumnCount())
+	for i := range cols {
+		col := table.Column(i)
+		cols[i] = colinfo.ResultColumn{
+			Name: string(col.ColName()),
+			Typ:  col.DatumType(),
+		}
+	}
+	return cols
+}
+
 func joinColumns(
 	joinType descpb.JoinType, left, right colinfo.ResultColumns,
 ) colinfo.ResultColumns {
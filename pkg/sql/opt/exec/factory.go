@@ -239,6 +239,11 @@ type Cascade struct {
 		numBufferedRows int,
 		allowAutoCommit bool,
 	) (Plan, error)
+
+	// PerRow is set if the cascade must be planned and executed once for each
+	// of the buffered rows, with a bufferRef holding only that row. See
+	// memo.FKCascade.PerRow.
+	PerRow bool
 }
 
 // InsertFastPathFKCheck contains information about a foreign key check to be
@@ -262,7 +267,7 @@ type InsertFastPathFKCheck struct {
 }
 
 // MkErrFn is a function that generates an error which includes values from a
-// relevant row.
+// relevant row. For ErrorIfRows, it can return nil to ignore the row.
 type MkErrFn func(tree.Datums) error
 
 // ExplainFactory is an extension of Factory used when constructing a plan that
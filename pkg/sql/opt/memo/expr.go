@@ -980,6 +980,11 @@ type FKCascade struct {
 	// It is empty if the mutation is a deletion. Empty if the cascade does not
 	// require input.
 	NewValues opt.ColList
+
+	// PerRow is set if the cascading query must be executed once for each of
+	// the modified rows, with only that row as input. Each execution observes
+	// the writes of the previous ones. It is used by AFTER triggers.
+	PerRow bool
 }
 
 // CascadeBuilder is an interface used to construct a cascading query for a
@@ -473,6 +473,10 @@ func (h *hasher) HashSequenceID(val opt.SequenceID) {
 	h.HashUint64(uint64(val))
 }
 
+func (h *hasher) HashForeignTableID(val opt.ForeignTableID) {
+	h.HashUint64(uint64(val))
+}
+
 func (h *hasher) HashUniqueID(val opt.UniqueID) {
 	h.HashUint64(uint64(val))
 }
@@ -709,6 +713,11 @@ func (h *hasher) HashPersistence(val tree.Persistence) {
 	h.hash *= prime64
 }
 
+func (h *hasher) HashVolatility(val tree.Volatility) {
+	h.hash ^= internHash(val)
+	h.hash *= prime64
+}
+
 // ----------------------------------------------------------------------
 //
 // Equality functions
@@ -885,6 +894,10 @@ func (h *hasher) IsSequenceIDEqual(l, r opt.SequenceID) bool {
 	return l == r
 }
 
+func (h *hasher) IsForeignTableIDEqual(l, r opt.ForeignTableID) bool {
+	return l == r
+}
+
 func (h *hasher) IsUniqueIDEqual(l, r opt.UniqueID) bool {
 	return l == r
 }
@@ -1149,6 +1162,10 @@ func (h *hasher) IsPersistenceEqual(l, r tree.Persistence) bool {
 	return l == r
 }
 
+func (h *hasher) IsVolatilityEqual(l, r tree.Volatility) bool {
+	return l == r
+}
+
 // encodeDatum turns the given datum into an encoded string of bytes. If two
 // datums are equivalent, then their encoded bytes will be identical.
 // Conversely, if two datums are not equivalent, then their encoded bytes will
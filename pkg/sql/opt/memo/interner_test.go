@@ -416,6 +416,11 @@ func TestInterner(t *testing.T) {
 			{val1: tree.ShowTraceKV, val2: tree.ShowTraceRaw, equal: false},
 		}},
 
+		{hashFn: in.hasher.HashVolatility, eqFn: in.hasher.IsVolatilityEqual, variations: []testVariation{
+			{val1: tree.VolatilityStable, val2: tree.VolatilityStable, equal: true},
+			{val1: tree.VolatilityStable, val2: tree.VolatilityVolatile, equal: false},
+		}},
+
 		{hashFn: in.hasher.HashWindowFrame, eqFn: in.hasher.IsWindowFrameEqual, variations: []testVariation{
 			{
 				val1:  WindowFrame{tree.RANGE, tree.UnboundedPreceding, tree.CurrentRow, tree.NoExclusion},
@@ -212,6 +212,39 @@ func (b *logicalPropsBuilder) buildSequenceSelectProps(
 	}
 }
 
+func (b *logicalPropsBuilder) buildForeignScanProps(
+	scan *ForeignScanExpr, rel *props.Relational,
+) {
+	// Output Columns
+	// --------------
+	// Output columns are stored in the definition.
+	rel.OutputCols = scan.Cols.ToSet()
+
+	// Not Null Columns
+	// ----------------
+	// Nothing is known about the values in the files of the table, so every
+	// column can be null.
+
+	// Outer Columns
+	// -------------
+	// The operator never has outer columns.
+
+	// Functional Dependencies
+	// -----------------------
+	// The rows of foreign tables have no key, so there are no functional
+	// dependencies.
+
+	// Cardinality
+	// -----------
+	rel.Cardinality = props.AnyCardinality
+
+	// Statistics
+	// ----------
+	if !b.disableStats {
+		b.sb.buildForeignScan(rel)
+	}
+}
+
 func (b *logicalPropsBuilder) buildSelectProps(sel *SelectExpr, rel *props.Relational) {
 	BuildSharedProps(sel, &rel.Shared, b.evalCtx)
 
@@ -1205,6 +1238,42 @@ func (b *logicalPropsBuilder) buildMax1RowProps(max1Row *Max1RowExpr, rel *props
 	}
 }
 
+func (b *logicalPropsBuilder) buildBarrierProps(barrier *BarrierExpr, rel *props.Relational) {
+	BuildSharedProps(barrier, &rel.Shared, b.evalCtx)
+
+	inputProps := barrier.Input.Relational()
+
+	// Output Columns
+	// --------------
+	// Output columns are inherited from input.
+	rel.OutputCols = inputProps.OutputCols
+
+	// Not Null Columns
+	// ----------------
+	// Not null columns are inherited from input.
+	rel.NotNullCols = inputProps.NotNullCols
+
+	// Outer Columns
+	// -------------
+	// Outer columns were already derived by BuildSharedProps.
+
+	// Functional Dependencies
+	// -----------------------
+	// Inherit functional dependencies from input.
+	rel.FuncDeps.CopyFrom(&inputProps.FuncDeps)
+
+	// Cardinality
+	// -----------
+	// Inherit cardinality from input.
+	rel.Cardinality = inputProps.Cardinality
+
+	// Statistics
+	// ----------
+	if !b.disableStats {
+		b.sb.buildBarrier(barrier, rel)
+	}
+}
+
 func (b *logicalPropsBuilder) buildOrdinalityProps(ord *OrdinalityExpr, rel *props.Relational) {
 	BuildSharedProps(ord, &rel.Shared, b.evalCtx)
 
@@ -1578,6 +1647,32 @@ func BuildSharedProps(e opt.Expr, shared *props.Shared, evalCtx *tree.EvalContex
 	case *FunctionExpr:
 		shared.VolatilitySet.Add(t.Overload.Volatility)
 
+	case *UDFExpr:
+		// The volatility of a user-defined function is the volatility declared
+		// by its definition, regardless of the volatility of its body.
+		shared.VolatilitySet.Add(t.Volatility)
+		BuildSharedProps(&t.Args, shared, evalCtx)
+
+		// The parameters of the function are bound by the UDF expression, so
+		// they are not outer columns.
+		var body props.Shared
+		BuildSharedProps(t.Body, &body, evalCtx)
+		body.OuterCols.DifferenceWith(t.Params.ToSet())
+		shared.OuterCols.UnionWith(body.OuterCols)
+		if body.HasPlaceholder {
+			shared.HasPlaceholder = true
+		}
+		if body.CanMutate {
+			shared.CanMutate = true
+		}
+		if body.HasSubquery {
+			shared.HasSubquery = true
+		}
+		if body.HasCorrelatedSubquery {
+			shared.HasCorrelatedSubquery = true
+		}
+		return
+
 	case *CastExpr:
 		from, to := t.Input.DataType(), t.Typ
 		volatility, ok := tree.LookupCastVolatility(from, to, evalCtx.SessionData())
@@ -1765,9 +1860,10 @@ func MakeTableFuncDep(md *opt.Metadata, tabID opt.TableID) *props.FuncDepSet {
 		for i := 0; i < tab.UniqueCount(); i++ {
 			unique := tab.Unique(i)
 
-			if !unique.Validated() {
-				// This unique constraint has not been validated, so we cannot use it
-				// as a key.
+			if !unique.Validated() || unique.Deferrability().IsDeferrable() {
+				// This unique constraint has not been validated, or it may be
+				// violated until the end of the transaction, so we cannot use it as
+				// a key.
 				continue
 			}
 
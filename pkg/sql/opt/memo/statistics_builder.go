@@ -390,6 +390,9 @@ func (sb *statisticsBuilder) colStat(colSet opt.ColSet, e RelExpr) *props.Column
 	case opt.OrdinalityOp:
 		return sb.colStatOrdinality(colSet, e.(*OrdinalityExpr))
 
+	case opt.BarrierOp:
+		return sb.colStatBarrier(colSet, e.(*BarrierExpr))
+
 	case opt.WindowOp:
 		return sb.colStatWindow(colSet, e.(*WindowExpr))
 
@@ -405,6 +408,9 @@ func (sb *statisticsBuilder) colStat(colSet opt.ColSet, e RelExpr) *props.Column
 	case opt.SequenceSelectOp:
 		return sb.colStatSequenceSelect(colSet, e.(*SequenceSelectExpr))
 
+	case opt.ForeignScanOp:
+		return sb.colStatForeignScan(colSet, e.(*ForeignScanExpr))
+
 	case opt.ExplainOp, opt.ShowTraceForSessionOp,
 		opt.OpaqueRelOp, opt.OpaqueMutationOp, opt.OpaqueDDLOp, opt.RecursiveCTEOp:
 		return sb.colStatUnknown(colSet, e.Relational())
@@ -2154,6 +2160,42 @@ func (sb *statisticsBuilder) colStatOrdinality(
 	return colStat
 }
 
+// +---------+
+// | Barrier |
+// +---------+
+
+func (sb *statisticsBuilder) buildBarrier(barrier *BarrierExpr, relProps *props.Relational) {
+	s := &relProps.Stats
+	if zeroCardinality := s.Init(relProps); zeroCardinality {
+		// Short cut if cardinality is 0.
+		return
+	}
+	s.Available = sb.availabilityFromInput(barrier)
+
+	inputStats := &barrier.Input.Relational().Stats
+
+	s.RowCount = inputStats.RowCount
+	sb.finalizeFromCardinality(relProps)
+}
+
+func (sb *statisticsBuilder) colStatBarrier(
+	colSet opt.ColSet, barrier *BarrierExpr,
+) *props.ColumnStatistic {
+	relProps := barrier.Relational()
+	s := &relProps.Stats
+
+	inputColStat := sb.colStatFromChild(colSet, barrier, 0 /* childIdx */)
+	colStat, _ := s.ColStats.Add(colSet)
+	colStat.DistinctCount = inputColStat.DistinctCount
+	colStat.NullCount = inputColStat.NullCount
+
+	if colSet.Intersects(relProps.NotNullCols) {
+		colStat.NullCount = 0
+	}
+	sb.finalizeFromRowCountAndDistinctCounts(colStat, s)
+	return colStat
+}
+
 // +------------+
 // |   Window   |
 // +------------+
@@ -2450,6 +2492,30 @@ func (sb *statisticsBuilder) colStatSequenceSelect(
 	return colStat
 }
 
+// +--------------+
+// | Foreign Scan |
+// +--------------+
+
+func (sb *statisticsBuilder) buildForeignScan(relProps *props.Relational) {
+	// Foreign tables have no statistics, so fall back to the row count used
+	// for tables without statistics.
+	s := &relProps.Stats
+	s.Available = false
+	s.RowCount = unknownRowCount
+}
+
+func (sb *statisticsBuilder) colStatForeignScan(
+	colSet opt.ColSet, scan *ForeignScanExpr,
+) *props.ColumnStatistic {
+	s := &scan.Relational().Stats
+
+	colStat, _ := s.ColStats.Add(colSet)
+	colStat.DistinctCount = unknownDistinctCountRatio * s.RowCount
+	colStat.NullCount = unknownNullCountRatio * s.RowCount
+	sb.finalizeFromRowCountAndDistinctCounts(colStat, s)
+	return colStat
+}
+
 // +---------+
 // | Unknown |
 // +---------+
@@ -89,6 +89,10 @@ type Metadata struct {
 	// sequences stores information about each metadata sequence, indexed by SequenceID.
 	sequences []cat.Sequence
 
+	// foreignTables stores the foreign tables referenced by the query, indexed
+	// by ForeignTableID.
+	foreignTables []cat.ForeignTable
+
 	// userDefinedTypes contains all user defined types present in expressions
 	// in this query.
 	// TODO (rohany): This only contains user defined types present in the query
@@ -171,6 +175,11 @@ func (md *Metadata) Init() {
 		sequences[i] = nil
 	}
 
+	foreignTables := md.foreignTables
+	for i := range foreignTables {
+		foreignTables[i] = nil
+	}
+
 	deps := md.deps
 	for i := range deps {
 		deps[i] = mdDep{}
@@ -188,6 +197,7 @@ func (md *Metadata) Init() {
 	md.cols = cols[:0]
 	md.tables = tables[:0]
 	md.sequences = sequences[:0]
+	md.foreignTables = foreignTables[:0]
 	md.deps = deps[:0]
 	md.views = views[:0]
 }
@@ -202,7 +212,7 @@ func (md *Metadata) Init() {
 // expression.
 func (md *Metadata) CopyFrom(from *Metadata, copyScalarFn func(Expr) Expr) {
 	if len(md.schemas) != 0 || len(md.cols) != 0 || len(md.tables) != 0 ||
-		len(md.sequences) != 0 || len(md.deps) != 0 || len(md.views) != 0 ||
+		len(md.sequences) != 0 || len(md.foreignTables) != 0 || len(md.deps) != 0 || len(md.views) != 0 ||
 		len(md.userDefinedTypes) != 0 || len(md.userDefinedTypesSlice) != 0 {
 		panic(errors.AssertionFailedf("CopyFrom requires empty destination"))
 	}
@@ -231,6 +241,7 @@ func (md *Metadata) CopyFrom(from *Metadata, copyScalarFn func(Expr) Expr) {
 	}
 
 	md.sequences = append(md.sequences, from.sequences...)
+	md.foreignTables = append(md.foreignTables, from.foreignTables...)
 	md.deps = append(md.deps, from.deps...)
 	md.views = append(md.views, from.views...)
 	md.currUniqueID = from.currUniqueID
@@ -619,6 +630,31 @@ func (md *Metadata) Sequence(seqID SequenceID) cat.Sequence {
 	return md.sequences[seqID.index()]
 }
 
+// ForeignTableID uniquely identifies the usage of a foreign table within the
+// scope of a query. ForeignTableID 0 is reserved to mean "unknown foreign
+// table".
+type ForeignTableID uint64
+
+// index returns the index of the foreign table in Metadata.foreignTables. It's
+// biased by 1, so that ForeignTableID 0 can be be reserved to mean "unknown
+// foreign table".
+func (f ForeignTableID) index() int {
+	return int(f - 1)
+}
+
+// AddForeignTable adds the foreign table to the metadata, returning a
+// ForeignTableID that can be used to retrieve it.
+func (md *Metadata) AddForeignTable(ft cat.ForeignTable) ForeignTableID {
+	md.foreignTables = append(md.foreignTables, ft)
+	return ForeignTableID(len(md.foreignTables))
+}
+
+// ForeignTable looks up the catalog foreign table associated with the given
+// metadata id.
+func (md *Metadata) ForeignTable(id ForeignTableID) cat.ForeignTable {
+	return md.foreignTables[id.index()]
+}
+
 // UniqueID should be used to disambiguate multiple uses of an expression
 // within the scope of a query. For example, a UniqueID field should be
 // added to an expression type if two instances of that type might otherwise
@@ -676,6 +712,14 @@ func (md *Metadata) AllDataSourceNames(
 	if err != nil {
 		return nil, nil, nil, err
 	}
+	// Foreign tables are reported along with the other tables.
+	foreignTables, err := getNames(len(md.foreignTables), func(i int) cat.DataSource {
+		return md.foreignTables[i]
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	tables = append(tables, foreignTables...)
 	sequences, err = getNames(len(md.sequences), func(i int) cat.DataSource {
 		return md.sequences[i]
 	})
@@ -14,6 +14,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/props"
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/errors"
 )
@@ -430,3 +431,103 @@ func (c *CustomFuncs) InlineConstVar(f memo.FiltersExpr) memo.FiltersExpr {
 	}
 	return result
 }
+
+// CanInlineUDF returns true if the given call to a user-defined function can
+// be replaced by its body. This is the case when the volatility of the body is
+// no greater than the volatility declared by the function, the body has no
+// subqueries, and inlining neither duplicates nor drops any volatile argument
+// (see DuplicatesVolatileUDFArgs and DropsVolatileUDFArgs).
+func (c *CustomFuncs) CanInlineUDF(
+	args memo.ScalarListExpr, body opt.ScalarExpr, private *memo.UDFPrivate,
+) bool {
+	var bodyProps props.Shared
+	memo.BuildSharedProps(body, &bodyProps, c.f.evalCtx)
+	if bodyProps.HasSubquery || !bodyProps.VolatilitySet.IsAtMost(private.Volatility) {
+		return false
+	}
+	return !c.DuplicatesVolatileUDFArgs(args, body, private.Params) &&
+		!c.DropsVolatileUDFArgs(args, body, private.Params)
+}
+
+// DuplicatesVolatileUDFArgs returns true if any of the given parameters which
+// is bound to an argument with volatile operators is referenced more than once
+// in the body of a user-defined function. Such a body cannot be evaluated by
+// substituting the arguments for the parameters, since that would evaluate the
+// argument more than once.
+func (c *CustomFuncs) DuplicatesVolatileUDFArgs(
+	args memo.ScalarListExpr, body opt.ScalarExpr, params opt.ColList,
+) bool {
+	var volatileParams opt.ColSet
+	for i := range args {
+		var argProps props.Shared
+		memo.BuildSharedProps(args[i], &argProps, c.f.evalCtx)
+		if argProps.VolatilitySet.HasVolatile() {
+			volatileParams.Add(params[i])
+		}
+	}
+	if volatileParams.Empty() {
+		return false
+	}
+
+	var refs opt.ColSet
+	var findDupRefs func(e opt.Expr) bool
+	findDupRefs = func(e opt.Expr) bool {
+		if v, ok := e.(*memo.VariableExpr); ok && volatileParams.Contains(v.Col) {
+			if refs.Contains(v.Col) {
+				return true
+			}
+			refs.Add(v.Col)
+			return false
+		}
+		for i, n := 0, e.ChildCount(); i < n; i++ {
+			if findDupRefs(e.Child(i)) {
+				return true
+			}
+		}
+		return false
+	}
+	return findDupRefs(body)
+}
+
+// DropsVolatileUDFArgs returns true if any of the given parameters which is
+// bound to an argument with volatile operators is not referenced in the body
+// of a user-defined function. Substituting the arguments for the parameters
+// would never evaluate such an argument, so that f(nextval('s')) would not
+// advance the sequence if the body of f doesn't use its parameter.
+func (c *CustomFuncs) DropsVolatileUDFArgs(
+	args memo.ScalarListExpr, body opt.ScalarExpr, params opt.ColList,
+) bool {
+	var bodyProps props.Shared
+	memo.BuildSharedProps(body, &bodyProps, c.f.evalCtx)
+	for i := range args {
+		if bodyProps.OuterCols.Contains(params[i]) {
+			continue
+		}
+		var argProps props.Shared
+		memo.BuildSharedProps(args[i], &argProps, c.f.evalCtx)
+		if argProps.VolatilitySet.HasVolatile() {
+			return true
+		}
+	}
+	return false
+}
+
+// InlineUDF returns the body of the given user-defined function, in which each
+// reference to a parameter is replaced by the corresponding argument.
+func (c *CustomFuncs) InlineUDF(
+	args memo.ScalarListExpr, body opt.ScalarExpr, private *memo.UDFPrivate,
+) opt.ScalarExpr {
+	var replace ReplaceFunc
+	replace = func(e opt.Expr) opt.Expr {
+		if v, ok := e.(*memo.VariableExpr); ok {
+			for i, col := range private.Params {
+				if col == v.Col {
+					return args[i]
+				}
+			}
+			return v
+		}
+		return c.f.Replace(e, replace)
+	}
+	return replace(body).(opt.ScalarExpr)
+}
@@ -87,6 +87,12 @@ func (c *CustomFuncs) neededMutationCols(
 			withUses := memo.WithUses(fkChecks[i].Check)
 			cols.UnionWith(withUses[private.WithID].UsedCols)
 		}
+		// Cascades, including AFTER triggers, read the old and new values of the
+		// modified rows from the buffered input.
+		for i := range private.FKCascades {
+			cols.UnionWith(private.FKCascades[i].OldValues.ToSet())
+			cols.UnionWith(private.FKCascades[i].NewValues.ToSet())
+		}
 	}
 
 	return cols
@@ -145,6 +151,19 @@ func (c *CustomFuncs) NeededMutationFetchCols(
 		}
 	}
 
+	// Retain any FetchCols that are needed by cascades. AFTER triggers can
+	// refer to the old value of any column of the modified rows.
+	var cascadeCols opt.ColSet
+	for i := range private.FKCascades {
+		cascadeCols.UnionWith(private.FKCascades[i].OldValues.ToSet())
+		cascadeCols.UnionWith(private.FKCascades[i].NewValues.ToSet())
+	}
+	for ord, col := range private.FetchCols {
+		if col != 0 && cascadeCols.Contains(col) {
+			cols.Add(tabMeta.MetaID.ColumnID(ord))
+		}
+	}
+
 	switch op {
 	case opt.UpdateOp, opt.UpsertOp:
 		// Determine set of target table columns that need to be updated.
@@ -520,6 +539,10 @@ func DerivePruneCols(e memo.RelExpr) opt.ColSet {
 		usedCols := sel.Filters.OuterCols()
 		relProps.Rule.PruneCols.DifferenceWith(usedCols)
 
+	case opt.BarrierOp:
+		// Any pruneable input columns can potentially be pruned.
+		relProps.Rule.PruneCols = DerivePruneCols(e.Child(0).(memo.RelExpr)).Copy()
+
 	case opt.ProjectOp:
 		// All columns can potentially be pruned from the Project, if they're never
 		// used in a higher-level expression.
@@ -409,3 +409,62 @@ func (c *CustomFuncs) addConjuncts(
 	}
 	return filters, true
 }
+
+// CanPushThroughBarrier returns true if the given filter can be pushed below a
+// Barrier whose input has the given output columns. It must be bound by these
+// columns and it must be leakproof, i.e. it cannot raise an error, so that it
+// cannot reveal anything about the rows filtered out by the Barrier's input.
+// Only comparisons of columns and constants, combined with boolean operators,
+// are considered leakproof.
+func (c *CustomFuncs) CanPushThroughBarrier(item *memo.FiltersItem, inputCols opt.ColSet) bool {
+	return c.IsBoundBy(item, inputCols) && isLeakproof(item.Condition)
+}
+
+// isLeakproof returns true if the given scalar expression cannot raise an
+// error. See CanPushThroughBarrier.
+func isLeakproof(e opt.ScalarExpr) bool {
+	switch e.Op() {
+	case opt.VariableOp, opt.ConstOp, opt.NullOp, opt.TrueOp, opt.FalseOp,
+		opt.AndOp, opt.OrOp, opt.NotOp, opt.RangeOp, opt.TupleOp,
+		opt.EqOp, opt.NeOp, opt.LtOp, opt.LeOp, opt.GtOp, opt.GeOp,
+		opt.IsOp, opt.IsNotOp, opt.InOp, opt.NotInOp:
+	default:
+		return false
+	}
+	for i, n := 0, e.ChildCount(); i < n; i++ {
+		if !isLeakproof(e.Child(i).(opt.ScalarExpr)) {
+			return false
+		}
+	}
+	return true
+}
+
+// ExtractBarrierPushableFilters returns the filters which can be pushed below
+// a Barrier whose input has the given output columns. See
+// CanPushThroughBarrier.
+func (c *CustomFuncs) ExtractBarrierPushableFilters(
+	filters memo.FiltersExpr, inputCols opt.ColSet,
+) memo.FiltersExpr {
+	newFilters := make(memo.FiltersExpr, 0, len(filters))
+	for i := range filters {
+		if c.CanPushThroughBarrier(&filters[i], inputCols) {
+			newFilters = append(newFilters, filters[i])
+		}
+	}
+	return newFilters
+}
+
+// ExtractBarrierUnpushableFilters is the opposite of
+// ExtractBarrierPushableFilters: it returns the filters which must stay above
+// the Barrier.
+func (c *CustomFuncs) ExtractBarrierUnpushableFilters(
+	filters memo.FiltersExpr, inputCols opt.ColSet,
+) memo.FiltersExpr {
+	newFilters := make(memo.FiltersExpr, 0, len(filters))
+	for i := range filters {
+		if !c.CanPushThroughBarrier(&filters[i], inputCols) {
+			newFilters = append(newFilters, filters[i])
+		}
+	}
+	return newFilters
+}
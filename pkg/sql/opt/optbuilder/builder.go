@@ -118,6 +118,15 @@ type Builder struct {
 	// are disabled and certain statements (like mutations) are disallowed.
 	insideViewDef bool
 
+	// udfParams contains the columns of the parameters of the user-defined
+	// function whose body is being built, if any. Placeholders in the body refer
+	// to these columns.
+	udfParams opt.ColList
+
+	// udfStack contains the IDs of the user-defined functions whose bodies are
+	// being built, and is used to detect recursive calls.
+	udfStack []uint32
+
 	// If set, we are collecting view dependencies in viewDeps. This can only
 	// happen inside view definitions.
 	//
@@ -144,6 +153,11 @@ type Builder struct {
 	// (without ON CONFLICT) or false otherwise. All mutated tables will have an
 	// entry in the map.
 	areAllTableMutationsSimpleInserts map[cat.StableID]bool
+
+	// triggerRows is set while building the body of an AFTER trigger. It holds
+	// the NEW and OLD values of the rows that fired the trigger; see
+	// mutationBuilder.joinTriggerRows.
+	triggerRows *scope
 }
 
 // New creates a new Builder structure initialized with the given
@@ -355,6 +369,9 @@ func (b *Builder) buildStmt(
 	case *tree.Export:
 		return b.buildExport(stmt, inScope)
 
+	case *tree.CopyTo:
+		return b.buildCopyTo(stmt, inScope)
+
 	default:
 		// See if this statement can be rewritten to another statement using the
 		// delegate functionality.
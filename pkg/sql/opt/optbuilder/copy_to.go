@@ -0,0 +1,57 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// buildCopyTo builds a COPY ... TO STDOUT statement. The statement produces
+// the rows of its query (or of the table being copied), which are encoded in
+// the requested COPY format by the connection the results are sent to.
+func (b *Builder) buildCopyTo(copyTo *tree.CopyTo, inScope *scope) (outScope *scope) {
+	stmt := copyTo.Statement
+	switch t := stmt.(type) {
+	case nil:
+		// COPY t (a, b) TO STDOUT is equivalent to COPY (SELECT a, b FROM t) TO
+		// STDOUT.
+		exprs := tree.SelectExprs{tree.StarSelectExpr()}
+		if len(copyTo.Columns) > 0 {
+			exprs = make(tree.SelectExprs, len(copyTo.Columns))
+			for i, col := range copyTo.Columns {
+				exprs[i] = tree.SelectExpr{Expr: tree.NewUnresolvedName(string(col))}
+			}
+		}
+		table := copyTo.Table
+		stmt = &tree.Select{Select: &tree.SelectClause{
+			Exprs: exprs,
+			From:  tree.From{Tables: tree.TableExprs{&table}},
+		}}
+
+	case *tree.Insert:
+		checkCopyToReturning(t.Returning)
+	case *tree.Update:
+		checkCopyToReturning(t.Returning)
+	case *tree.Delete:
+		checkCopyToReturning(t.Returning)
+	}
+	return b.buildStmt(stmt, nil /* desiredTypes */, inScope)
+}
+
+// checkCopyToReturning ensures that a mutation copied out by COPY TO has rows
+// to copy out.
+func checkCopyToReturning(returning tree.ReturningClause) {
+	if !tree.HasReturningClause(returning) {
+		panic(pgerror.New(pgcode.FeatureNotSupported, "COPY query must have a RETURNING clause"))
+	}
+}
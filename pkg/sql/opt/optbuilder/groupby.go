@@ -45,6 +45,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/errors"
 )
 
@@ -100,6 +101,16 @@ type groupby struct {
 	// It is used to ensure that the builder does not throw a grouping error
 	// prematurely.
 	buildingGroupingCols bool
+
+	// groupingSets contains the grouping sets of the GROUP BY clause, once
+	// its ROLLUP, CUBE and GROUPING SETS elements have been expanded. Each set
+	// contains a subset of the grouping columns. It is nil if the GROUP BY
+	// clause has no such elements.
+	groupingSets []opt.ColSet
+
+	// groupingFuncs contains information about the GROUPING() function calls
+	// encountered.
+	groupingFuncs []*groupingFuncInfo
 }
 
 // groupByStrSet is a set of stringified GROUP BY expressions that map to the
@@ -279,14 +290,68 @@ func (a *aggregateInfo) ResolvedType() *types.T {
 var _ tree.Expr = &aggregateInfo{}
 var _ tree.TypedExpr = &aggregateInfo{}
 
+// groupingFuncInfo stores information about a GROUPING() function call.
+type groupingFuncInfo struct {
+	*tree.FuncExpr
+
+	// argCols contains the grouping column which corresponds to each argument
+	// of the function call. It is populated by buildGroupingFuncs.
+	argCols opt.ColList
+
+	// col is the output column of the function call.
+	col scopeColumn
+}
+
+// Walk is part of the tree.Expr interface.
+func (f *groupingFuncInfo) Walk(v tree.Visitor) tree.Expr {
+	return f
+}
+
+// TypeCheck is part of the tree.Expr interface.
+func (f *groupingFuncInfo) TypeCheck(
+	ctx context.Context, semaCtx *tree.SemaContext, desired *types.T,
+) (tree.TypedExpr, error) {
+	return f, nil
+}
+
+// Eval is part of the tree.TypedExpr interface.
+func (f *groupingFuncInfo) Eval(_ *tree.EvalContext) (tree.Datum, error) {
+	panic(errors.AssertionFailedf("groupingFuncInfo must be replaced before evaluation"))
+}
+
+// ResolvedType is part of the tree.TypedExpr interface.
+func (f *groupingFuncInfo) ResolvedType() *types.T {
+	return types.Int
+}
+
+// value returns the result of the function call for the given grouping set.
+// The result is a bit mask in which a bit is set if the corresponding
+// argument is not grouped in the set; the last argument corresponds to the
+// least significant bit.
+func (f *groupingFuncInfo) value(set opt.ColSet) tree.Datum {
+	var res tree.DInt
+	for _, col := range f.argCols {
+		res <<= 1
+		if !set.Contains(col) {
+			res |= 1
+		}
+	}
+	return tree.NewDInt(res)
+}
+
+var _ tree.Expr = &groupingFuncInfo{}
+var _ tree.TypedExpr = &groupingFuncInfo{}
+
 func (b *Builder) needsAggregation(sel *tree.SelectClause, scope *scope) bool {
 	// We have an aggregation if:
 	//  - we have a GROUP BY, or
 	//  - we have a HAVING clause, or
-	//  - we have aggregate functions in the SELECT, DISTINCT ON and/or ORDER BY expressions.
+	//  - we have aggregate functions in the SELECT, DISTINCT ON and/or ORDER BY expressions, or
+	//  - we have GROUPING() function calls.
 	return len(sel.GroupBy) > 0 ||
 		sel.Having != nil ||
-		(scope.groupby != nil && scope.groupby.hasAggregates())
+		(scope.groupby != nil &&
+			(scope.groupby.hasAggregates() || len(scope.groupby.groupingFuncs) > 0))
 }
 
 func (b *Builder) constructGroupBy(
@@ -336,6 +401,34 @@ func (b *Builder) buildGroupingColumns(sel *tree.SelectClause, projectionsScope,
 
 	// Copy the grouping columns to the aggOutScope.
 	g.aggOutScope.appendColumns(g.groupingCols())
+
+	b.buildGroupingFuncs(g)
+}
+
+// maxGroupingFuncArgs is the maximum number of arguments of a GROUPING()
+// function call, so that its result fits in an INT4 like in Postgres.
+const maxGroupingFuncArgs = 31
+
+// buildGroupingFuncs matches the arguments of the GROUPING() function calls
+// with the grouping columns, and adds the output columns of the function
+// calls to the aggOutScope.
+func (b *Builder) buildGroupingFuncs(g *groupby) {
+	for _, f := range g.groupingFuncs {
+		if len(f.Exprs) > maxGroupingFuncArgs {
+			panic(pgerror.Newf(pgcode.TooManyArguments,
+				"GROUPING must have fewer than %d arguments", maxGroupingFuncArgs+1))
+		}
+		f.argCols = make(opt.ColList, len(f.Exprs))
+		for i, e := range f.Exprs {
+			col, ok := g.groupStrs[symbolicExprStr(e.(tree.TypedExpr))]
+			if !ok {
+				panic(pgerror.New(pgcode.Grouping,
+					"arguments to GROUPING must be grouping expressions of the associated query level"))
+			}
+			f.argCols[i] = col.id
+		}
+		g.aggOutScope.appendColumn(&f.col)
+	}
 }
 
 // buildAggregation builds the aggregation operators and constructs the
@@ -354,6 +447,10 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 	// If there are any aggregates that are ordering sensitive, build the
 	// aggregations as window functions over each group.
 	if g.hasNonCommutativeAggregates() {
+		if g.groupingSets != nil || len(g.groupingFuncs) > 0 {
+			panic(unimplemented.NewWithIssue(46280,
+				"ordered aggregates are not supported with GROUPING SETS, ROLLUP, CUBE or GROUPING"))
+		}
 		return b.buildAggregationAsWindow(groupingColSet, having, fromScope)
 	}
 
@@ -422,20 +519,40 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 		}
 	}
 
-	if haveOrderingSensitiveAgg {
-		g.aggInScope.copyOrdering(fromScope)
-	}
-
-	// Construct the pre-projection, which renders the grouping columns and the
-	// aggregate arguments, as well as any additional order by columns.
-	b.constructProjectForScope(fromScope, g.aggInScope)
+	if len(g.groupingSets) > 1 {
+		// Each grouping set is aggregated separately (see constructGroupingSets).
+		g.aggOutScope.expr = b.constructGroupingSets(fromScope, aggCols)
+	} else {
+		if haveOrderingSensitiveAgg {
+			g.aggInScope.copyOrdering(fromScope)
+		}
 
-	g.aggOutScope.expr = b.constructGroupBy(
-		g.aggInScope.expr.(memo.RelExpr),
-		groupingColSet,
-		aggCols,
-		g.aggInScope.ordering,
-	)
+		// Construct the pre-projection, which renders the grouping columns and the
+		// aggregate arguments, as well as any additional order by columns.
+		b.constructProjectForScope(fromScope, g.aggInScope)
+
+		g.aggOutScope.expr = b.constructGroupBy(
+			g.aggInScope.expr.(memo.RelExpr),
+			groupingColSet,
+			aggCols,
+			g.aggInScope.ordering,
+		)
+
+		// All the grouping columns are grouped, so the GROUPING() function calls
+		// return 0.
+		if len(g.groupingFuncs) > 0 {
+			input := g.aggOutScope.expr
+			projections := make(memo.ProjectionsExpr, len(g.groupingFuncs))
+			for i, f := range g.groupingFuncs {
+				projections[i] = b.factory.ConstructProjectionsItem(
+					b.factory.ConstructConstVal(f.value(groupingColSet), types.Int), f.col.id,
+				)
+			}
+			g.aggOutScope.expr = b.factory.ConstructProject(
+				input, projections, input.Relational().OutputCols,
+			)
+		}
+	}
 
 	// Wrap with having filter if it exists.
 	if having != nil {
@@ -447,6 +564,196 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 	return g.aggOutScope
 }
 
+// constructGroupingSets constructs the aggregation of a GROUP BY clause which
+// has several grouping sets. Each grouping set is aggregated separately, over
+// a WITH binding of the input of the aggregation, and the results are combined
+// with a UNION ALL. The grouping columns which are not part of a grouping set
+// are NULL in its rows. For example:
+//
+//   SELECT a, b, sum(c) FROM t GROUP BY ROLLUP (a, b)
+//
+// is built like:
+//
+//   WITH w AS (SELECT a, b, c FROM t)
+//   SELECT a, b, sum(c) FROM w GROUP BY a, b
+//   UNION ALL SELECT a, NULL, sum(c) FROM w GROUP BY a
+//   UNION ALL SELECT NULL, NULL, sum(c) FROM w
+//
+// The output columns of the UNION ALL are the grouping columns, the aggregate
+// columns and the GROUPING() function columns of the aggOutScope. Each branch
+// builds its own copy of the pre-projection with new column IDs, and the
+// grouping columns are always synthesized by the pre-projection (see
+// buildGrouping), so that none of these columns is produced by any other
+// expression.
+func (b *Builder) constructGroupingSets(fromScope *scope, aggCols []scopeColumn) memo.RelExpr {
+	g := fromScope.groupby
+	md := b.factory.Metadata()
+
+	input := fromScope.expr
+	if !input.Relational().OuterCols.Empty() {
+		panic(unimplemented.NewWithIssue(46280,
+			"GROUPING SETS, ROLLUP and CUBE are not supported with correlated inputs"))
+	}
+	withID := b.factory.Memo().NextWithID()
+	md.AddWithBinding(withID, input)
+	b.addCTE(&cteSource{id: withID, expr: input})
+	inCols := input.Relational().OutputCols.ToList()
+
+	// Determine the output columns of the UNION ALL.
+	groupingCols := g.groupingCols()
+	outCols := make(opt.ColList, 0, len(groupingCols)+len(aggCols)+len(g.groupingFuncs))
+	for i := range groupingCols {
+		outCols = append(outCols, groupingCols[i].id)
+	}
+	var aggColSet opt.ColSet
+	for i := range aggCols {
+		if id := aggCols[i].id; !aggColSet.Contains(id) {
+			outCols = append(outCols, id)
+			aggColSet.Add(id)
+		}
+	}
+	for _, f := range g.groupingFuncs {
+		outCols = append(outCols, f.col.id)
+	}
+
+	var left memo.RelExpr
+	var leftCols opt.ColList
+	for i, set := range g.groupingSets {
+		right, rightCols := b.constructGroupingSet(withID, inCols, set, g, aggCols)
+		if i == 0 {
+			left, leftCols = right, rightCols
+			continue
+		}
+		cols := outCols
+		if i < len(g.groupingSets)-1 {
+			cols = make(opt.ColList, len(outCols))
+			for j, id := range outCols {
+				colMeta := md.ColumnMeta(id)
+				cols[j] = md.AddColumn(colMeta.Alias, colMeta.Type)
+			}
+		}
+		left = b.factory.ConstructUnionAll(left, right, &memo.SetPrivate{
+			LeftCols:  leftCols,
+			RightCols: rightCols,
+			OutCols:   cols,
+		})
+		leftCols = cols
+	}
+	return left
+}
+
+// constructGroupingSet constructs the aggregation of a single grouping set
+// for constructGroupingSets, over a new scan of the WITH binding with the
+// given ID. It returns the expression and its output columns, which
+// correspond to the output columns of the UNION ALL.
+func (b *Builder) constructGroupingSet(
+	withID opt.WithID, inCols opt.ColList, set opt.ColSet, g *groupby, aggCols []scopeColumn,
+) (memo.RelExpr, opt.ColList) {
+	md := b.factory.Metadata()
+	newCol := func(id opt.ColumnID) opt.ColumnID {
+		colMeta := md.ColumnMeta(id)
+		return md.AddColumn(colMeta.Alias, colMeta.Type)
+	}
+
+	// Scan the WITH binding with new column IDs. colMap maps the columns of the
+	// aggInScope and aggOutScope to the columns of this grouping set.
+	var colMap opt.ColMap
+	scanCols := make(opt.ColList, len(inCols))
+	for i, id := range inCols {
+		scanCols[i] = newCol(id)
+		colMap.Set(int(id), int(scanCols[i]))
+	}
+	input := b.factory.ConstructWithScan(&memo.WithScanPrivate{
+		With:    withID,
+		InCols:  inCols,
+		OutCols: scanCols,
+		ID:      md.NextUniqueID(),
+	})
+
+	// Construct the pre-projection.
+	var passthrough opt.ColSet
+	projections := make(memo.ProjectionsExpr, 0, len(g.aggInScope.cols))
+	for i := range g.aggInScope.cols {
+		col := &g.aggInScope.cols[i]
+		if col.scalar == nil {
+			id, ok := colMap.Get(int(col.id))
+			if !ok {
+				panic(errors.AssertionFailedf("column %d is not an input column", col.id))
+			}
+			passthrough.Add(opt.ColumnID(id))
+			continue
+		}
+		if _, ok := colMap.Get(int(col.id)); ok {
+			continue
+		}
+		id := newCol(col.id)
+		scalar := b.factory.CustomFuncs().RemapCols(col.scalar, colMap)
+		projections = append(projections, b.factory.ConstructProjectionsItem(scalar, id))
+		colMap.Set(int(col.id), int(id))
+	}
+	preProjection := b.factory.ConstructProject(input, projections, passthrough)
+	if rel := preProjection.Relational(); !rel.OuterCols.Empty() || rel.HasSubquery {
+		panic(unimplemented.NewWithIssue(46280,
+			"GROUPING SETS, ROLLUP and CUBE are not supported with correlated or subquery "+
+				"grouping expressions or aggregate arguments"))
+	}
+
+	// Construct the aggregation.
+	var groupingColSet opt.ColSet
+	set.ForEach(func(col opt.ColumnID) {
+		id, _ := colMap.Get(int(col))
+		groupingColSet.Add(opt.ColumnID(id))
+	})
+	aggs := make(memo.AggregationsExpr, 0, len(aggCols))
+	for i := range aggCols {
+		if _, ok := colMap.Get(int(aggCols[i].id)); ok {
+			continue
+		}
+		id := newCol(aggCols[i].id)
+		scalar := b.factory.CustomFuncs().RemapCols(aggCols[i].scalar, colMap)
+		aggs = append(aggs, b.factory.ConstructAggregationsItem(scalar, id))
+		colMap.Set(int(aggCols[i].id), int(id))
+	}
+	private := memo.GroupingPrivate{GroupingCols: groupingColSet}
+	var agg memo.RelExpr
+	if groupingColSet.Empty() {
+		agg = b.factory.ConstructScalarGroupBy(preProjection, aggs, &private)
+	} else {
+		agg = b.factory.ConstructGroupBy(preProjection, aggs, &private)
+	}
+
+	// Construct the post-projection, which renders NULL for the grouping
+	// columns which are not part of the grouping set, and the results of the
+	// GROUPING() function calls.
+	groupingCols := g.groupingCols()
+	outCols := make(opt.ColList, 0, len(groupingCols)+len(aggs)+len(g.groupingFuncs))
+	passthrough = opt.ColSet{}
+	projections = make(memo.ProjectionsExpr, 0, len(groupingCols)+len(g.groupingFuncs))
+	for i := range groupingCols {
+		if set.Contains(groupingCols[i].id) {
+			id, _ := colMap.Get(int(groupingCols[i].id))
+			passthrough.Add(opt.ColumnID(id))
+			outCols = append(outCols, opt.ColumnID(id))
+			continue
+		}
+		id := newCol(groupingCols[i].id)
+		null := b.factory.ConstructNull(md.ColumnMeta(id).Type)
+		projections = append(projections, b.factory.ConstructProjectionsItem(null, id))
+		outCols = append(outCols, id)
+	}
+	for i := range aggs {
+		passthrough.Add(aggs[i].Col)
+		outCols = append(outCols, aggs[i].Col)
+	}
+	for _, f := range g.groupingFuncs {
+		id := newCol(f.col.id)
+		value := b.factory.ConstructConstVal(f.value(set), types.Int)
+		projections = append(projections, b.factory.ConstructProjectionsItem(value, id))
+		outCols = append(outCols, id)
+	}
+	return b.factory.ConstructProject(agg, projections, passthrough), outCols
+}
+
 // analyzeHaving analyzes the having clause and returns it as a typed
 // expression. fromScope contains the name bindings that are visible for this
 // HAVING clause (e.g., passed in from an enclosing statement).
@@ -507,15 +814,126 @@ func (b *Builder) buildGroupingList(
 	// used in an aggregate function`. The builder cannot know whether there is
 	// a grouping error until the grouping columns are fully built.
 	g.buildingGroupingCols = true
-	for _, e := range groupBy {
-		b.buildGrouping(e, selects, projectionsScope, fromScope, g.aggInScope)
+	if hasGroupingSets(groupBy) {
+		// The grouping sets of the GROUP BY clause are the cross product of the
+		// grouping sets of its elements.
+		g.groupingSets = []opt.ColSet{{}}
+		for _, e := range groupBy {
+			sets := b.buildGroupingSets(e, selects, projectionsScope, fromScope)
+			g.groupingSets = crossGroupingSets(g.groupingSets, sets)
+		}
+	} else {
+		for _, e := range groupBy {
+			b.buildGrouping(e, selects, projectionsScope, fromScope, g.aggInScope)
+		}
 	}
 	g.buildingGroupingCols = false
 }
 
+// hasGroupingSets returns true if the given GROUP BY clause has ROLLUP, CUBE
+// or GROUPING SETS elements.
+func hasGroupingSets(groupBy tree.GroupBy) bool {
+	for _, e := range groupBy {
+		if _, ok := e.(*tree.GroupingSets); ok {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	// maxGroupingSets is the maximum number of grouping sets of a GROUP BY
+	// clause, as in Postgres.
+	maxGroupingSets = 4096
+
+	// maxCubeExprs is the maximum number of elements of a CUBE, as in Postgres.
+	maxCubeExprs = 12
+)
+
+// buildGroupingSets builds the grouping columns of an element of a GROUP BY
+// clause, like buildGrouping, and returns the grouping sets of the element.
+// An element which is not a ROLLUP, CUBE or GROUPING SETS has a single
+// grouping set. For example, the grouping sets of:
+//
+//   ROLLUP (a, (b, c))            are (a, b, c), (a) and ()
+//   CUBE (a, b)                   are (a, b), (a), (b) and ()
+//   GROUPING SETS (a, CUBE (b))   are (a), (b) and ()
+//
+func (b *Builder) buildGroupingSets(
+	groupBy tree.Expr, selects tree.SelectExprs, projectionsScope, fromScope *scope,
+) []opt.ColSet {
+	aggInScope := fromScope.groupby.aggInScope
+	gs, ok := groupBy.(*tree.GroupingSets)
+	if !ok {
+		return []opt.ColSet{b.buildGrouping(groupBy, selects, projectionsScope, fromScope, aggInScope)}
+	}
+
+	var sets []opt.ColSet
+	switch gs.Type {
+	case tree.RollupGroupingSets:
+		// The grouping sets are the prefixes of the list of elements, starting
+		// with the longest one.
+		sets = make([]opt.ColSet, len(gs.Exprs)+1)
+		for i, e := range gs.Exprs {
+			cols := b.buildGrouping(e, selects, projectionsScope, fromScope, aggInScope)
+			for j := 0; j <= len(gs.Exprs)-1-i; j++ {
+				sets[j].UnionWith(cols)
+			}
+		}
+
+	case tree.CubeGroupingSets:
+		// The grouping sets are all the subsets of the list of elements. The
+		// subsets are ordered like the binary numbers from 2^n-1 to 0, where
+		// the first element corresponds to the most significant bit.
+		if len(gs.Exprs) > maxCubeExprs {
+			panic(pgerror.Newf(pgcode.ProgramLimitExceeded,
+				"CUBE is limited to %d elements", maxCubeExprs))
+		}
+		n := len(gs.Exprs)
+		sets = make([]opt.ColSet, 1<<n)
+		for i, e := range gs.Exprs {
+			cols := b.buildGrouping(e, selects, projectionsScope, fromScope, aggInScope)
+			bit := 1 << (n - 1 - i)
+			for j := range sets {
+				if mask := len(sets) - 1 - j; mask&bit != 0 {
+					sets[j].UnionWith(cols)
+				}
+			}
+		}
+
+	case tree.ExplicitGroupingSets:
+		for _, e := range gs.Exprs {
+			sets = append(sets, b.buildGroupingSets(e, selects, projectionsScope, fromScope)...)
+			if len(sets) > maxGroupingSets {
+				panic(errTooManyGroupingSets)
+			}
+		}
+	}
+	return sets
+}
+
+var errTooManyGroupingSets = pgerror.Newf(pgcode.ProgramLimitExceeded,
+	"too many grouping sets present (maximum %d)", maxGroupingSets)
+
+// crossGroupingSets returns the cross product of two lists of grouping sets:
+// the unions of each set of the left list with each set of the right list.
+func crossGroupingSets(left, right []opt.ColSet) []opt.ColSet {
+	if len(left)*len(right) > maxGroupingSets {
+		panic(errTooManyGroupingSets)
+	}
+	res := make([]opt.ColSet, 0, len(left)*len(right))
+	for _, l := range left {
+		for _, r := range right {
+			res = append(res, l.Union(r))
+		}
+	}
+	return res
+}
+
 // buildGrouping builds a set of memo groups that represent a GROUP BY
 // expression. The expression (or expressions, if we have a star) is added to
-// groupStrs and to the aggInScope.
+// groupStrs and to the aggInScope. Returns the set of grouping columns of the
+// expression.
 //
 //
 // groupBy          The given GROUP BY expression.
@@ -529,7 +947,7 @@ func (b *Builder) buildGroupingList(
 //                  as the aggregate function arguments.
 func (b *Builder) buildGrouping(
 	groupBy tree.Expr, selects tree.SelectExprs, projectionsScope, fromScope, aggInScope *scope,
-) {
+) (cols opt.ColSet) {
 	// Unwrap parenthesized expressions like "((a))" to "a".
 	groupBy = tree.StripParens(groupBy)
 	alias := ""
@@ -627,7 +1045,8 @@ func (b *Builder) buildGrouping(
 		// If a grouping column has already been added, don't add it again.
 		// GROUP BY a, a is semantically equivalent to GROUP BY a.
 		exprStr := symbolicExprStr(e)
-		if _, ok := fromScope.groupby.groupStrs[exprStr]; ok {
+		if col, ok := fromScope.groupby.groupStrs[exprStr]; ok {
+			cols.Add(col.id)
 			continue
 		}
 
@@ -636,8 +1055,16 @@ func (b *Builder) buildGrouping(
 		//   SELECT x+y FROM t GROUP BY x+y
 		col := aggInScope.addColumn(scopeColName(tree.Name(alias)), e)
 		b.buildScalar(e, fromScope, aggInScope, col, nil)
+		if col.scalar == nil && fromScope.groupby.groupingSets != nil {
+			// With grouping sets, the grouping columns are NULL in the rows of the
+			// grouping sets which don't contain them, so they must not be pass-through
+			// columns of the input (see constructGroupingSets).
+			b.populateSynthesizedColumn(col, b.factory.ConstructVariable(col.id))
+		}
 		fromScope.groupby.groupStrs[exprStr] = col
+		cols.Add(col.id)
 	}
+	return cols
 }
 
 // buildAggArg builds a scalar expression which is used as an input in some form
@@ -891,6 +1318,10 @@ func isSQLFn(def *tree.FunctionDefinition) bool {
 	return def.Class == tree.SQLClass
 }
 
+func isGroupingFn(def *tree.FunctionDefinition) bool {
+	return def.Name == "grouping"
+}
+
 func newGroupingError(name tree.Name) error {
 	return pgerror.Newf(pgcode.Grouping,
 		"column \"%s\" must appear in the GROUP BY clause or be used in an aggregate function",
@@ -901,8 +1332,13 @@ func newGroupingError(name tree.Name) error {
 // allowImplicitGroupingColumn returns true if col is part of a table and the
 // the groupby metadata indicates that we are grouping on the entire PK of that
 // table. In that case, we can allow col as an "implicit" grouping column, even
-// if it is not specified in the query.
+// if it is not specified in the query. Implicit grouping columns are not
+// allowed with grouping sets, since col is NULL in the rows of the grouping
+// sets which don't contain the entire PK.
 func (b *Builder) allowImplicitGroupingColumn(colID opt.ColumnID, g *groupby) bool {
+	if g.groupingSets != nil {
+		return false
+	}
 	md := b.factory.Metadata()
 	colMeta := md.ColumnMeta(colID)
 	if colMeta.Table == 0 {
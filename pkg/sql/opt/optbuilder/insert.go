@@ -221,6 +221,8 @@ func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope
 	} else {
 		mb.init(b, "insert", tab, alias)
 	}
+	mb.initTriggerRows(inScope)
+	mb.rowLevelSecurity = b.rowLevelSecurityApplies(tab)
 
 	// Compute target columns in two cases:
 	//
@@ -353,6 +355,7 @@ func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope
 //      values specified for them.
 //   4. Each update value is the same as the corresponding insert value.
 //   5. There are no inbound foreign keys containing non-key columns.
+//   6. There are no triggers on the table.
 //
 // TODO(andyk): The fast path is currently only enabled when the UPSERT alias
 // is explicitly selected by the user. It's possible to fast path some queries
@@ -364,6 +367,18 @@ func (mb *mutationBuilder) needExistingRows() bool {
 		return true
 	}
 
+	// Triggers can refer to the old values of updated rows, and need to
+	// distinguish inserted rows from updated rows.
+	if mb.tab.TriggerCount() > 0 {
+		return true
+	}
+
+	// Row-level security policies are checked against the existing rows which
+	// are updated, and differ for inserted and updated rows.
+	if mb.rowLevelSecurity {
+		return true
+	}
+
 	// If there are any implicit partitioning columns in the primary index,
 	// these columns will need to be fetched.
 	primaryIndex := mb.tab.Index(cat.PrimaryIndex)
@@ -647,6 +662,10 @@ func (mb *mutationBuilder) addSynthesizedColsForInsert() {
 		false, /* applyOnUpdate */
 	)
 
+	// Apply the assignments of BEFORE INSERT triggers. Do this before adding
+	// computed columns, since those may depend on the assigned columns.
+	mb.buildBeforeTriggers(tree.TriggerEventInsert, mb.insertColIDs)
+
 	// Possibly round DECIMAL-related columns containing insertion values (whether
 	// synthesized or not).
 	mb.roundDecimalValues(mb.insertColIDs, false /* roundComputedCols */)
@@ -661,6 +680,8 @@ func (mb *mutationBuilder) addSynthesizedColsForInsert() {
 // buildInsert constructs an Insert operator, possibly wrapped by a Project
 // operator that corresponds to the given RETURNING clause.
 func (mb *mutationBuilder) buildInsert(returning tree.ReturningExprs) {
+	mb.joinTriggerRows()
+
 	// Disambiguate names so that references in any expressions, such as a
 	// check constraint, refer to the correct columns.
 	mb.disambiguateColumns()
@@ -668,6 +689,9 @@ func (mb *mutationBuilder) buildInsert(returning tree.ReturningExprs) {
 	// Add any check constraint boolean columns to the input.
 	mb.addCheckConstraintCols(false /* isUpdate */)
 
+	// Check the new rows against the row-level security policies.
+	mb.addRowLevelSecurityChecks(tree.PolicyCommandInsert)
+
 	// Project partial index PUT boolean columns.
 	mb.projectPartialIndexPutCols()
 
@@ -675,6 +699,8 @@ func (mb *mutationBuilder) buildInsert(returning tree.ReturningExprs) {
 
 	mb.buildFKChecksForInsert()
 
+	mb.buildAfterTriggers(tree.TriggerEventInsert)
+
 	private := mb.makeMutationPrivate(returning != nil)
 	mb.outScope.expr = mb.b.factory.ConstructInsert(
 		mb.outScope.expr, mb.uniqueChecks, mb.fkChecks, private,
@@ -870,6 +896,8 @@ func (mb *mutationBuilder) setUpsertCols(insertCols tree.NameList) {
 // buildUpsert constructs an Upsert operator, possibly wrapped by a Project
 // operator that corresponds to the given RETURNING clause.
 func (mb *mutationBuilder) buildUpsert(returning tree.ReturningExprs) {
+	mb.joinTriggerRows()
+
 	// Merge input insert and update columns using CASE expressions.
 	mb.projectUpsertColumns()
 
@@ -880,6 +908,9 @@ func (mb *mutationBuilder) buildUpsert(returning tree.ReturningExprs) {
 	// Add any check constraint boolean columns to the input.
 	mb.addCheckConstraintCols(false /* isUpdate */)
 
+	// Check the new and updated rows against the row-level security policies.
+	mb.addRowLevelSecurityChecks(tree.PolicyCommandInsert, tree.PolicyCommandUpdate)
+
 	// Add the partial index predicate expressions to the table metadata.
 	// These expressions are used to prune fetch columns during
 	// normalization.
@@ -902,6 +933,8 @@ func (mb *mutationBuilder) buildUpsert(returning tree.ReturningExprs) {
 
 	mb.buildFKChecksForUpsert()
 
+	mb.buildAfterTriggers(tree.TriggerEventInsert, tree.TriggerEventUpdate)
+
 	private := mb.makeMutationPrivate(returning != nil)
 	mb.outScope.expr = mb.b.factory.ConstructUpsert(
 		mb.outScope.expr, mb.uniqueChecks, mb.fkChecks, private,
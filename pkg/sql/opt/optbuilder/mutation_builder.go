@@ -187,6 +187,16 @@ type mutationBuilder struct {
 	// made accessible to the RETURNING clause.
 	extraAccessibleCols []scopeColumn
 
+	// triggerRows is set if the mutation is the body of an AFTER trigger; see
+	// joinTriggerRows.
+	triggerRows *scope
+
+	// rowLevelSecurity is true if the row-level security policies of the
+	// target table apply to the rows read and written by the mutation; see
+	// addRowLevelSecurityChecks. It is false for the mutations made by foreign
+	// key cascades, which bypass the policies.
+	rowLevelSecurity bool
+
 	// fkCheckHelper is used to prevent allocating the helper separately.
 	fkCheckHelper fkCheckHelper
 
@@ -296,6 +306,9 @@ func (mb *mutationBuilder) buildInputForUpdate(
 		noRowLocking,
 		inScope,
 	)
+	if mb.rowLevelSecurity {
+		mb.b.addRowLevelSecurityFilter(mb.tab, tree.PolicyCommandUpdate, mb.fetchScope)
+	}
 
 	// Set list of columns that will be fetched by the input expression.
 	mb.setFetchColIDs(mb.fetchScope.cols)
@@ -412,6 +425,9 @@ func (mb *mutationBuilder) buildInputForDelete(
 		noRowLocking,
 		inScope,
 	)
+	if mb.rowLevelSecurity {
+		mb.b.addRowLevelSecurityFilter(mb.tab, tree.PolicyCommandDelete, mb.fetchScope)
+	}
 	mb.outScope = mb.fetchScope
 
 	// WHERE
@@ -1501,3 +1517,22 @@ func (mb *mutationBuilder) buildCheckInputScan(
 	})
 	return withScanScope, notNullOutCols
 }
+
+// constraintCheckDeferred returns true if the validation of the given FK or
+// unique constraint is deferred until the end of the transaction, either
+// because the constraint is INITIALLY DEFERRED or because of SET CONSTRAINTS.
+// In that case, the check is still built, but it is marked as deferred: the
+// keys it finds are validated again when the transaction commits, instead of
+// generating an error.
+func (b *Builder) constraintCheckDeferred(
+	name string, deferrability tree.ConstraintDeferrability,
+) bool {
+	if !deferrability.IsDeferrable() {
+		return false
+	}
+	// Whether a deferrable constraint is deferred can be changed by SET
+	// CONSTRAINTS, so the memo cannot be reused.
+	b.DisableMemoReuse = true
+	deferred := b.evalCtx.DeferredConstraints
+	return deferred != nil && deferred.IsConstraintDeferred(name, deferrability)
+}
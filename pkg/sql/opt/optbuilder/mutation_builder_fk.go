@@ -172,7 +172,9 @@ func (mb *mutationBuilder) buildFKChecksAndCascadesForDelete() {
 
 		mb.ensureWithID()
 		withScanScope, _ := mb.buildCheckInputScan(checkInputScanFetchedVals, h.tabOrdinals)
-		mb.fkChecks = append(mb.fkChecks, h.buildDeletionCheck(withScanScope.expr, withScanScope.colList()))
+		mb.fkChecks = append(mb.fkChecks, h.buildDeletionCheck(
+			withScanScope.expr, withScanScope.colList(), h.fk.DeleteReferenceAction(),
+		))
 	}
 	telemetry.Inc(sqltelemetry.ForeignKeyChecksUseCounter)
 }
@@ -354,7 +356,9 @@ func (mb *mutationBuilder) buildFKChecksForUpdate() {
 			},
 		)
 
-		mb.fkChecks = append(mb.fkChecks, h.buildDeletionCheck(deletedRows, colsForOldRow))
+		mb.fkChecks = append(mb.fkChecks, h.buildDeletionCheck(
+			deletedRows, colsForOldRow, h.fk.UpdateReferenceAction(),
+		))
 	}
 	telemetry.Inc(sqltelemetry.ForeignKeyChecksUseCounter)
 }
@@ -458,7 +462,9 @@ func (mb *mutationBuilder) buildFKChecksForUpsert() {
 				OutCols:   colsForOldRow,
 			},
 		)
-		mb.fkChecks = append(mb.fkChecks, h.buildDeletionCheck(deletedRows, oldRowsScope.colList()))
+		mb.fkChecks = append(mb.fkChecks, h.buildDeletionCheck(
+			deletedRows, oldRowsScope.colList(), h.fk.UpdateReferenceAction(),
+		))
 	}
 	telemetry.Inc(sqltelemetry.ForeignKeyChecksUseCounter)
 }
@@ -605,6 +611,12 @@ func (h *fkCheckHelper) initWithInboundFK(mb *mutationBuilder, fkOrdinal int) (o
 	return true
 }
 
+// checkDeferred returns true if the check of the FK constraint is deferred
+// until the end of the transaction.
+func (h *fkCheckHelper) checkDeferred() bool {
+	return h.mb.b.constraintCheckDeferred(h.fk.Name(), h.fk.Deferrability())
+}
+
 // resolveTable resolves a table StableID. Returns nil if the table is in the
 // process of being added, in which case it is safe to ignore any FK
 // relation with the table.
@@ -744,15 +756,18 @@ func (h *fkCheckHelper) buildInsertionCheck() memo.FKChecksItem {
 		FKOrdinal:       h.fkOrdinal,
 		KeyCols:         withScanScope.colList(),
 		OpName:          h.mb.opName,
+		Deferred:        h.checkDeferred(),
 	})
 }
 
 // buildDeletionCheck creates a FK check for rows which are removed from a
 // table. deletedRows is used as the input to the deletion check, and deleteCols
 // is a list of the columns for the rows being deleted, containing values for
-// the referenced FK columns in the table we are mutating.
+// the referenced FK columns in the table we are mutating. action is the
+// reference action of the FK for the mutation; checks of constraints with the
+// RESTRICT action are never deferred.
 func (h *fkCheckHelper) buildDeletionCheck(
-	deletedRows memo.RelExpr, deleteCols opt.ColList,
+	deletedRows memo.RelExpr, deleteCols opt.ColList, action tree.ReferenceAction,
 ) memo.FKChecksItem {
 	// Build a semi join, with the referenced FK columns on the left and the
 	// origin columns on the right.
@@ -786,5 +801,6 @@ func (h *fkCheckHelper) buildDeletionCheck(
 		FKOrdinal:       h.fkOrdinal,
 		KeyCols:         deleteCols,
 		OpName:          h.mb.opName,
+		Deferred:        action == tree.NoAction && h.checkDeferred(),
 	})
 }
@@ -0,0 +1,483 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/props"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// Row-level triggers come in two flavors, which are planned very differently.
+//
+// BEFORE triggers modify the row being written using a list of assignments,
+// for example:
+//
+//   CREATE TRIGGER t_upd BEFORE UPDATE ON t FOR EACH ROW
+//     SET NEW.updated_at = now()
+//
+// The assignments are built as projections on top of the mutation input, the
+// same way that DEFAULT and computed column values are built, and the
+// projected columns replace the values that would otherwise be written.
+//
+// AFTER triggers execute a mutation statement once the triggering statement
+// has completed, for example:
+//
+//   CREATE TRIGGER t_audit AFTER DELETE ON t FOR EACH ROW
+//     INSERT INTO t_audit VALUES (OLD.k, OLD.v)
+//
+// They are planned as cascades (see memo.FKCascade), which read the old and
+// new values of the modified rows from the buffered mutation input. The body
+// of the trigger is built with the buffered rows as an outer scope, and its
+// input is joined with these rows (see joinTriggerRows). The cascades are
+// marked PerRow, so the body is planned and executed once for each modified
+// row, with a buffer containing only that row; each execution observes the
+// writes of the previous ones.
+//
+// In both cases, trigger expressions refer to the new and old versions of the
+// modified row as NEW.<colname> and OLD.<colname>. NEW is NULL when rows are
+// deleted and OLD is NULL when rows are inserted.
+
+// buildBeforeTriggers applies the assignments of the BEFORE triggers that fire
+// for the given event, in name order. colIDs contains the new values of the
+// modified rows (mb.insertColIDs or mb.updateColIDs); it is updated with the
+// IDs of the assigned columns.
+func (mb *mutationBuilder) buildBeforeTriggers(event tree.TriggerEvent, colIDs opt.OptionalColList) {
+	for i, n := 0, mb.tab.TriggerCount(); i < n; i++ {
+		trigger := mb.tab.Trigger(i)
+		if trigger.ActionTime() == tree.TriggerActionTimeBefore && trigger.HasEvent(event) {
+			mb.buildBeforeTrigger(trigger, event, colIDs)
+		}
+	}
+}
+
+// buildBeforeTrigger wraps the mutation input with a Project operator that
+// computes the assignments of the given BEFORE trigger.
+func (mb *mutationBuilder) buildBeforeTrigger(
+	trigger cat.Trigger, event tree.TriggerEvent, colIDs opt.OptionalColList,
+) {
+	ords := triggerColumnOrdinals(mb.tab)
+	newCols := make(opt.OptionalColList, len(ords))
+	var oldCols opt.OptionalColList
+	if event != tree.TriggerEventInsert {
+		oldCols = make(opt.OptionalColList, len(ords))
+	}
+	for i, ord := range ords {
+		newCols[i] = colIDs[ord]
+		if oldCols != nil {
+			oldCols[i] = mb.fetchColIDs[ord]
+			if newCols[i] == 0 {
+				// The column is not updated.
+				newCols[i] = oldCols[i]
+			}
+		}
+	}
+
+	// The assignments are resolved in a separate scope, so that the NEW and OLD
+	// rows are the only data sources that they can refer to.
+	rowScope := mb.b.buildTriggerRowScope(mb.outScope, mb.tab, ords, newCols, oldCols)
+	mb.outScope.expr = rowScope.expr
+
+	projectionsScope := mb.outScope.replace()
+	projectionsScope.appendColumnsFromScope(mb.outScope)
+	for i, n := 0, trigger.AssignmentCount(); i < n; i++ {
+		assignment := trigger.Assignment(i)
+		ord := assignment.ColumnOrdinal
+		tabCol := mb.tab.Column(ord)
+
+		// If the trigger has a WHEN condition, rows that do not satisfy it keep
+		// their current value:
+		//
+		//   CASE WHEN <when> THEN <expr> ELSE NEW.<col> END
+		//
+		// The NEW columns precede the OLD columns in rowScope, so
+		// getColumnForTableOrdinal returns the NEW column.
+		expr := mb.parseTriggerExpr(assignment.Expr)
+		if when := trigger.WhenExpr(); when != "" {
+			expr = &tree.CaseExpr{
+				Whens: []*tree.When{{Cond: mb.parseTriggerExpr(when), Val: expr}},
+				Else:  rowScope.getColumnForTableOrdinal(ord),
+			}
+		}
+		texpr := rowScope.resolveAndRequireType(expr, tabCol.DatumType())
+
+		// Ensure that only the new column has the table column name, so that it
+		// is the one referenced by computed column expressions.
+		for j := range projectionsScope.cols {
+			if projectionsScope.cols[j].name.MatchesReferenceName(tabCol.ColName()) {
+				projectionsScope.cols[j].clearName()
+			}
+		}
+		colName := scopeColName(tabCol.ColName()).WithMetadataName(
+			string(tabCol.ColName()) + "_" + trigger.Name(),
+		)
+		scopeCol := projectionsScope.addColumn(colName, texpr)
+		mb.b.buildScalar(texpr, rowScope, projectionsScope, scopeCol, nil)
+
+		// Remember the new value and add the corresponding target column, in
+		// case it was not already written by the statement.
+		colIDs[ord] = scopeCol.id
+		if tabColID := mb.tabID.ColumnID(ord); !mb.targetColSet.Contains(tabColID) {
+			mb.targetColList = append(mb.targetColList, tabColID)
+			mb.targetColSet.Add(tabColID)
+		}
+	}
+
+	mb.b.constructProjectForScope(mb.outScope, projectionsScope)
+	mb.outScope = projectionsScope
+}
+
+// buildAfterTriggers adds a cascade for each AFTER trigger that fires for the
+// given event. For UPSERT and INSERT .. ON CONFLICT statements, both INSERT
+// and UPDATE triggers fire; in that case, the canary column is used to
+// determine which of the rows were inserted.
+func (mb *mutationBuilder) buildAfterTriggers(events ...tree.TriggerEvent) {
+	isUpsert := len(events) > 1
+	for i, n := 0, mb.tab.TriggerCount(); i < n; i++ {
+		trigger := mb.tab.Trigger(i)
+		if trigger.ActionTime() != tree.TriggerActionTimeAfter {
+			continue
+		}
+		for _, event := range events {
+			if !trigger.HasEvent(event) {
+				continue
+			}
+			mb.ensureWithID()
+			builder := &triggerBuilder{
+				mutatedTable: mb.tab,
+				trigger:      trigger,
+				event:        event,
+				canaryIdx:    -1,
+			}
+
+			var oldCols, newCols opt.ColList
+			for _, ord := range triggerColumnOrdinals(mb.tab) {
+				var oldColID, newColID opt.ColumnID
+				switch {
+				case isUpsert:
+					oldColID, newColID = mb.fetchColIDs[ord], mb.mapToReturnColID(ord)
+				case event == tree.TriggerEventInsert:
+					newColID = mb.insertColIDs[ord]
+				case event == tree.TriggerEventUpdate:
+					oldColID, newColID = mb.fetchColIDs[ord], mb.mapToReturnColID(ord)
+				case event == tree.TriggerEventDelete:
+					oldColID = mb.fetchColIDs[ord]
+				}
+				if oldColID != 0 {
+					builder.oldOrds = append(builder.oldOrds, ord)
+					oldCols = append(oldCols, oldColID)
+				}
+				if newColID != 0 {
+					builder.newOrds = append(builder.newOrds, ord)
+					newCols = append(newCols, newColID)
+				}
+			}
+			if isUpsert {
+				idx, ok := oldCols.Find(mb.canaryColID)
+				if !ok {
+					panic(errors.AssertionFailedf("canary column is not a fetch column"))
+				}
+				builder.canaryIdx = idx
+			}
+
+			mb.cascades = append(mb.cascades, memo.FKCascade{
+				FKName:    trigger.Name(),
+				Builder:   builder,
+				WithID:    mb.withID,
+				OldValues: oldCols,
+				NewValues: newCols,
+				PerRow:    true,
+			})
+		}
+	}
+}
+
+// initTriggerRows must be called with the input scope of a mutation statement
+// right after the mutationBuilder is initialized. If the statement is the body
+// of an AFTER trigger, it remembers the rows that fired the trigger so that
+// they can be joined with the mutation input.
+func (mb *mutationBuilder) initTriggerRows(inScope *scope) {
+	if rows := mb.b.triggerRows; rows != nil && rows == inScope {
+		mb.triggerRows = rows
+		mb.b.triggerRows = nil
+	}
+}
+
+// joinTriggerRows wraps the mutation input with an apply-join with the rows
+// that fired the AFTER trigger whose body is being built. The body may refer
+// to the NEW and OLD values of these rows as outer columns:
+//
+//   INSERT INTO t_audit VALUES (OLD.k, OLD.v)
+//
+// so its input is built for the trigger row. The body is executed once per
+// modified row (see memo.FKCascade.PerRow), so there is a single trigger row.
+func (mb *mutationBuilder) joinTriggerRows() {
+	if mb.triggerRows == nil {
+		return
+	}
+	mb.outScope.expr = mb.b.factory.ConstructInnerJoinApply(
+		mb.triggerRows.expr,
+		mb.outScope.expr,
+		memo.TrueFilter,
+		memo.EmptyJoinPrivate,
+	)
+	mb.triggerRows = nil
+}
+
+// parseTriggerExpr parses the text of a trigger expression.
+func (mb *mutationBuilder) parseTriggerExpr(sql string) tree.Expr {
+	expr, err := parser.ParseExpr(sql)
+	if err != nil {
+		panic(err)
+	}
+	return expr
+}
+
+// buildTriggerRowScope returns a scope with the NEW and OLD columns that can be
+// referenced by trigger expressions. The columns correspond to the given table
+// ordinals and map 1-to-1 to the newCols and oldCols lists, which contain IDs
+// of columns produced by inScope. Columns with a zero ID (or a nil list) are
+// NULL; they are projected on top of inScope, and the resulting expression is
+// stored in the returned scope.
+func (b *Builder) buildTriggerRowScope(
+	inScope *scope, tab cat.Table, ords []int, newCols, oldCols opt.OptionalColList,
+) *scope {
+	md := b.factory.Metadata()
+	rowScope := b.allocScope()
+	projectionsScope := inScope.replace()
+	projectionsScope.appendColumnsFromScope(inScope)
+
+	addRow := func(rowName string, cols opt.OptionalColList) {
+		tableName := tree.MakeUnqualifiedTableName(tree.Name(rowName))
+		for i, ord := range ords {
+			tabCol := tab.Column(ord)
+			col := scopeColumn{
+				name:         scopeColName(tabCol.ColName()),
+				table:        tableName,
+				tableOrdinal: ord,
+			}
+			if cols != nil && cols[i] != 0 {
+				col.id = cols[i]
+				col.typ = md.ColumnMeta(cols[i]).Type
+			} else {
+				typ := tabCol.DatumType()
+				nullCol := b.synthesizeColumn(
+					projectionsScope,
+					scopeColName("").WithMetadataName(rowName+"_"+string(tabCol.ColName())),
+					typ,
+					nil, /* expr */
+					b.factory.ConstructNull(typ),
+				)
+				col.id = nullCol.id
+				col.typ = typ
+			}
+			rowScope.cols = append(rowScope.cols, col)
+		}
+	}
+	addRow(schemaexpr.TriggerNewRowName, newCols)
+	addRow(schemaexpr.TriggerOldRowName, oldCols)
+
+	b.constructProjectForScope(inScope, projectionsScope)
+	rowScope.expr = projectionsScope.expr
+	return rowScope
+}
+
+// triggerColumnOrdinals returns the ordinals of the table columns that can be
+// referenced by trigger expressions.
+func triggerColumnOrdinals(tab cat.Table) []int {
+	return tableOrdinals(tab, columnKinds{
+		includeMutations:       false,
+		includeSystem:          false,
+		includeInverted:        false,
+		includeVirtualComputed: true,
+	})
+}
+
+// triggerBuilder is a memo.CascadeBuilder implementation for AFTER triggers.
+//
+// It provides a method to build the body of the trigger, with its input joined
+// with the rows that fired the trigger. For example:
+//
+//   CREATE TRIGGER audit AFTER INSERT ON t FOR EACH ROW
+//     INSERT INTO t_audit VALUES (NEW.k, NEW.v)
+//
+//   insert t_audit
+//    ├── columns: <none>
+//    ├── insert-mapping:
+//    │    ├── column1:9 => t_audit.k:1
+//    │    └── column2:10 => t_audit.v:2
+//    └── inner-join-apply
+//         ├── columns: k:5 v:6 column1:9 column2:10
+//         ├── with-scan &1
+//         │    ├── columns: k:5 v:6
+//         │    └── mapping:
+//         │         ├──  t.k:3 => k:5
+//         │         └──  t.v:4 => v:6
+//         ├── values
+//         │    ├── columns: column1:9 column2:10
+//         │    └── (k:5, v:6)
+//         └── filters (true)
+//
+type triggerBuilder struct {
+	mutatedTable cat.Table
+	trigger      cat.Trigger
+	event        tree.TriggerEvent
+
+	// oldOrds and newOrds are the table ordinals of the columns in the
+	// oldValues and newValues lists passed to Build.
+	oldOrds []int
+	newOrds []int
+
+	// canaryIdx is the index in oldValues of the canary column of an UPSERT or
+	// INSERT .. ON CONFLICT statement, or -1 for other statements. The canary
+	// column is NULL for inserted rows and not NULL for updated rows.
+	canaryIdx int
+}
+
+var _ memo.CascadeBuilder = &triggerBuilder{}
+
+// Build is part of the memo.CascadeBuilder interface.
+func (tb *triggerBuilder) Build(
+	ctx context.Context,
+	semaCtx *tree.SemaContext,
+	evalCtx *tree.EvalContext,
+	catalog cat.Catalog,
+	factoryI interface{},
+	binding opt.WithID,
+	bindingProps *props.Relational,
+	oldValues, newValues opt.ColList,
+) (_ memo.RelExpr, err error) {
+	return buildCascadeHelper(ctx, semaCtx, evalCtx, catalog, factoryI, func(b *Builder) memo.RelExpr {
+		stmt, err := parser.ParseOne(tb.trigger.Body())
+		if err != nil {
+			panic(err)
+		}
+
+		b.triggerRows = tb.buildTriggerRows(b, binding, bindingProps, oldValues, newValues)
+
+		var outScope *scope
+		switch t := stmt.AST.(type) {
+		case *tree.Insert:
+			outScope = b.buildInsert(t, b.triggerRows)
+		case *tree.Update:
+			outScope = b.buildUpdate(t, b.triggerRows)
+		case *tree.Delete:
+			outScope = b.buildDelete(t, b.triggerRows)
+		default:
+			panic(errors.AssertionFailedf(
+				"unexpected body statement %s for trigger %s", stmt.AST.StatementTag(), tb.trigger.Name(),
+			))
+		}
+		return outScope.expr
+	})
+}
+
+// buildTriggerRows builds a scope with the rows that fire the trigger, which
+// are read from the mutation input using a WithScan. The scope has NEW and OLD
+// columns for the table columns; see buildTriggerRowScope.
+func (tb *triggerBuilder) buildTriggerRows(
+	b *Builder,
+	binding opt.WithID,
+	bindingProps *props.Relational,
+	oldValues, newValues opt.ColList,
+) *scope {
+	if len(oldValues) != len(tb.oldOrds) || len(newValues) != len(tb.newOrds) {
+		panic(errors.AssertionFailedf(
+			"expected %d oldValues/%d newValues columns, got %d/%d",
+			len(tb.oldOrds), len(tb.newOrds), len(oldValues), len(newValues),
+		))
+	}
+
+	f := b.factory
+	md := f.Metadata()
+	inCols := append(oldValues[:len(oldValues):len(oldValues)], newValues...)
+	outCols := make(opt.ColList, len(inCols))
+	for i := range outCols {
+		c := md.ColumnMeta(inCols[i])
+		outCols[i] = md.AddColumn(c.Alias, c.Type)
+	}
+	outColsOld := outCols[:len(oldValues)]
+	outColsNew := outCols[len(oldValues):]
+
+	md.AddWithBinding(binding, f.ConstructFakeRel(&memo.FakeRelPrivate{
+		Props: bindingProps,
+	}))
+	withScanScope := b.allocScope()
+	withScanScope.expr = f.ConstructWithScan(&memo.WithScanPrivate{
+		With:    binding,
+		InCols:  inCols,
+		OutCols: outCols,
+		ID:      md.NextUniqueID(),
+	})
+
+	// For UPSERT, only keep the rows for which the trigger fires.
+	if tb.canaryIdx >= 0 {
+		canary := f.ConstructVariable(outColsOld[tb.canaryIdx])
+		null := f.ConstructNull(md.ColumnMeta(outColsOld[tb.canaryIdx]).Type)
+		var cond opt.ScalarExpr
+		if tb.event == tree.TriggerEventInsert {
+			cond = f.ConstructIs(canary, null)
+		} else {
+			cond = f.ConstructIsNot(canary, null)
+		}
+		withScanScope.expr = f.ConstructSelect(
+			withScanScope.expr,
+			memo.FiltersExpr{f.ConstructFiltersItem(cond)},
+		)
+	}
+
+	// Map the columns to the NEW and OLD rows. For UPSERT, inserted rows have
+	// NULL OLD values since the fetched values are NULL.
+	ords := triggerColumnOrdinals(tb.mutatedTable)
+	var oldCols, newCols opt.OptionalColList
+	if tb.event != tree.TriggerEventInsert {
+		oldCols = tb.mapColumns(ords, tb.oldOrds, outColsOld)
+	}
+	if tb.event != tree.TriggerEventDelete {
+		newCols = tb.mapColumns(ords, tb.newOrds, outColsNew)
+	}
+	rows := b.buildTriggerRowScope(withScanScope, tb.mutatedTable, ords, newCols, oldCols)
+
+	// WHEN
+	if when := tb.trigger.WhenExpr(); when != "" {
+		expr, err := parser.ParseExpr(when)
+		if err != nil {
+			panic(err)
+		}
+		texpr := rows.resolveAndRequireType(expr, types.Bool)
+		filter := b.buildScalar(texpr, rows, nil, nil, nil)
+		rows.expr = f.ConstructSelect(rows.expr, memo.FiltersExpr{f.ConstructFiltersItem(filter)})
+	}
+	return rows
+}
+
+// mapColumns returns a list with one entry for each of the given table
+// ordinals, containing the corresponding column in cols (which maps 1-to-1 to
+// colOrds), or 0 if there is none.
+func (tb *triggerBuilder) mapColumns(ords, colOrds []int, cols opt.ColList) opt.OptionalColList {
+	res := make(opt.OptionalColList, len(ords))
+	j := 0
+	for i, ord := range ords {
+		if j < len(colOrds) && colOrds[j] == ord {
+			res[i] = cols[j]
+			j++
+		}
+	}
+	return res
+}
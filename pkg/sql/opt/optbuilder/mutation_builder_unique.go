@@ -177,6 +177,13 @@ func (mb *mutationBuilder) uniqueColsUpdated(uniqueOrdinal cat.UniqueOrdinal) bo
 	return false
 }
 
+// uniqueCheckDeferred returns true if the check of the given unique constraint
+// is deferred until the end of the transaction.
+func (mb *mutationBuilder) uniqueCheckDeferred(uniqueOrdinal cat.UniqueOrdinal) bool {
+	uc := mb.tab.Unique(uniqueOrdinal)
+	return mb.b.constraintCheckDeferred(uc.Name(), uc.Deferrability())
+}
+
 // uniqueConstraintIsArbiter returns true if the given unique constraint is used
 // as an arbiter to detect conflicts in an  INSERT ... ON CONFLICT statement.
 func (mb *mutationBuilder) uniqueConstraintIsArbiter(uniqueOrdinal int) bool {
@@ -390,6 +397,7 @@ func (h *uniqueCheckHelper) buildInsertionCheck() memo.UniqueChecksItem {
 		CheckOrdinal: h.uniqueOrdinal,
 		KeyCols:      keyCols,
 		OpName:       h.mb.opName,
+		Deferred:     h.mb.uniqueCheckDeferred(h.uniqueOrdinal),
 	})
 }
 
@@ -0,0 +1,222 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// rowLevelSecurityApplies returns true if the row-level security policies of
+// the given table restrict the rows that the current user can read and write.
+// The policies do not apply to admins, nor to the owner of the table unless
+// row-level security is forced for the table.
+func (b *Builder) rowLevelSecurityApplies(tab cat.Table) bool {
+	if !tab.IsRowLevelSecurityEnabled() {
+		return false
+	}
+
+	// Whether the policies apply, and which of them, depends on the current
+	// user, so the memo cannot be reused by other sessions.
+	b.DisableMemoReuse = true
+
+	isAdmin, err := b.catalog.HasAdminRole(b.ctx)
+	if err != nil {
+		panic(err)
+	}
+	if isAdmin {
+		return false
+	}
+	if !tab.IsRowLevelSecurityForced() {
+		isOwner, err := b.catalog.HasOwnership(b.ctx, tab)
+		if err != nil {
+			panic(err)
+		}
+		if isOwner {
+			return false
+		}
+	}
+	return true
+}
+
+// policyApplies returns true if the given policy applies to statements of the
+// given kind run by the current user.
+func (b *Builder) policyApplies(policy cat.Policy, cmd tree.PolicyCommand) bool {
+	if policy.Command() != tree.PolicyCommandAll && policy.Command() != cmd {
+		return false
+	}
+	if policy.RoleCount() == 0 {
+		return true
+	}
+	for i, n := 0, policy.RoleCount(); i < n; i++ {
+		isMember, err := b.catalog.IsMemberOfRole(b.ctx, policy.Role(i))
+		if err != nil {
+			panic(err)
+		}
+		if isMember {
+			return true
+		}
+	}
+	return false
+}
+
+// buildPolicyCondition returns the condition that rows must satisfy under the
+// policies of the table which apply to statements of the given kind run by the
+// current user. The USING expressions of the permissive policies are OR'ed
+// together, and the result is AND'ed with the expressions of the restrictive
+// policies. If withCheck is true, the WITH CHECK expressions of the policies
+// are used instead, falling back to their USING expressions.
+//
+// Policies without an expression are ignored, so rows are denied if none of
+// the applicable permissive policies has one, as in Postgres.
+func (b *Builder) buildPolicyCondition(
+	tab cat.Table, cmd tree.PolicyCommand, withCheck bool,
+) tree.Expr {
+	var permissive, restrictive tree.Expr
+	for i, n := 0, tab.PolicyCount(); i < n; i++ {
+		policy := tab.Policy(i)
+		if !b.policyApplies(policy, cmd) {
+			continue
+		}
+		sql := policy.UsingExpr()
+		if withCheck && policy.WithCheckExpr() != "" {
+			sql = policy.WithCheckExpr()
+		}
+		if sql == "" {
+			continue
+		}
+		expr, err := parser.ParseExpr(sql)
+		if err != nil {
+			panic(err)
+		}
+		expr = &tree.ParenExpr{Expr: expr}
+
+		if policy.IsRestrictive() {
+			if restrictive == nil {
+				restrictive = expr
+			} else {
+				restrictive = &tree.AndExpr{Left: restrictive, Right: expr}
+			}
+		} else {
+			if permissive == nil {
+				permissive = expr
+			} else {
+				permissive = &tree.OrExpr{Left: permissive, Right: expr}
+			}
+		}
+	}
+
+	if permissive == nil {
+		return tree.DBoolFalse
+	}
+	if restrictive == nil {
+		return permissive
+	}
+	return &tree.AndExpr{
+		Left:  &tree.ParenExpr{Expr: permissive},
+		Right: &tree.ParenExpr{Expr: restrictive},
+	}
+}
+
+// addRowLevelSecurityFilter wraps the expression of the given scope, which
+// scans the given table, in a Select that filters out the rows which are not
+// visible to the current user, and in a Barrier above it. The rows read by SELECT statements must satisfy
+// the USING expressions of the SELECT policies. The rows read by UPDATE and
+// DELETE statements must satisfy those of the policies for the statement as
+// well.
+//
+// The filter is only added to scans of tables referenced by the query, and not
+// to the scans built for foreign key and uniqueness checks, which must see all
+// the rows of the table.
+func (b *Builder) addRowLevelSecurityFilter(tab cat.Table, cmd tree.PolicyCommand, inScope *scope) {
+	cond := b.buildPolicyCondition(tab, tree.PolicyCommandSelect, false /* withCheck */)
+	if cmd != tree.PolicyCommandSelect {
+		cond = &tree.AndExpr{
+			Left:  b.buildPolicyCondition(tab, cmd, false /* withCheck */),
+			Right: cond,
+		}
+	}
+
+	filter := b.resolveAndBuildScalar(
+		cond, types.Bool, exprKindPolicy, tree.RejectSpecial, inScope,
+	)
+	// The Barrier prevents the filters of the query from being evaluated
+	// before the policy filter, since they could reveal the values of hidden
+	// rows by raising errors, e.g. WHERE 1/(v-42) = 0.
+	inScope.expr = b.factory.ConstructBarrier(
+		b.factory.ConstructSelect(
+			inScope.expr.(memo.RelExpr),
+			memo.FiltersExpr{b.factory.ConstructFiltersItem(filter)},
+		),
+	)
+}
+
+// addRowLevelSecurityChecks wraps the mutation input in a Select that raises
+// an error if a row written by the mutation does not satisfy the WITH CHECK
+// expressions of the policies for the given statement kinds. The rows are not
+// filtered out, so that writes are never silently dropped.
+//
+// For an UPSERT or INSERT ... ON CONFLICT DO UPDATE, both INSERT and UPDATE are
+// given. The new rows are checked against the INSERT policies if they are
+// inserted and against the UPDATE policies if they are updated, in which case
+// the existing rows must also satisfy the USING expressions of the UPDATE
+// policies.
+func (mb *mutationBuilder) addRowLevelSecurityChecks(cmds ...tree.PolicyCommand) {
+	if !mb.rowLevelSecurity {
+		return
+	}
+	f := mb.b.factory
+	buildCond := func(cmd tree.PolicyCommand, withCheck bool, inScope *scope) opt.ScalarExpr {
+		return mb.b.resolveAndBuildScalar(
+			mb.b.buildPolicyCondition(mb.tab, cmd, withCheck),
+			types.Bool, exprKindPolicy, tree.RejectSpecial, inScope,
+		)
+	}
+
+	var check opt.ScalarExpr
+	if len(cmds) == 1 {
+		check = buildCond(cmds[0], true /* withCheck */, mb.outScope)
+	} else {
+		insertCheck := buildCond(tree.PolicyCommandInsert, true /* withCheck */, mb.outScope)
+		updateCheck := buildCond(tree.PolicyCommandUpdate, true /* withCheck */, mb.outScope)
+		updateUsing := buildCond(tree.PolicyCommandUpdate, false /* withCheck */, mb.fetchScope)
+
+		// The canary column is null if the row is inserted.
+		isInsert := f.ConstructIs(f.ConstructVariable(mb.canaryColID), memo.NullSingleton)
+		newRowCheck := f.ConstructCase(
+			memo.TrueSingleton,
+			memo.ScalarListExpr{f.ConstructWhen(isInsert, insertCheck)},
+			updateCheck,
+		)
+		check = f.ConstructAnd(f.ConstructOr(isInsert, updateUsing), newRowCheck)
+	}
+
+	const fnName = "crdb_internal.check_row_level_security"
+	props, overloads := builtins.GetBuiltinProperties(fnName)
+	private := &memo.FunctionPrivate{
+		Name:       fnName,
+		Typ:        types.Bool,
+		Properties: props,
+		Overload:   &overloads[0],
+	}
+	tabName := f.ConstructConstVal(tree.NewDString(string(mb.tab.Name())), types.String)
+	fn := f.ConstructFunction(memo.ScalarListExpr{check, tabName}, private)
+
+	mb.outScope.expr = f.ConstructSelect(
+		mb.outScope.expr.(memo.RelExpr),
+		memo.FiltersExpr{f.ConstructFiltersItem(fn)},
+	)
+}
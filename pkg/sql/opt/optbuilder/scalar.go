@@ -116,6 +116,13 @@ func (b *Builder) buildScalar(
 	case *windowInfo:
 		return b.finishBuildScalarRef(t.col, inScope, outScope, outCol, colRefs)
 
+	case *groupingFuncInfo:
+		if t.argCols == nil {
+			panic(pgerror.New(pgcode.Grouping,
+				"arguments to GROUPING must be grouping expressions of the associated query level"))
+		}
+		return b.finishBuildScalarRef(&t.col, inScope.groupby.aggOutScope, outScope, outCol, colRefs)
+
 	case *tree.AndExpr:
 		left := b.buildScalar(reType(t.TypedLeft(), types.Bool), inScope, nil, nil, colRefs)
 		right := b.buildScalar(reType(t.TypedRight(), types.Bool), inScope, nil, nil, colRefs)
@@ -389,7 +396,11 @@ func (b *Builder) buildScalar(
 		return b.buildScalar(t.TypedInnerExpr(), inScope, outScope, outCol, colRefs)
 
 	case *tree.Placeholder:
-		if !b.KeepPlaceholders && b.evalCtx.HasPlaceholders() {
+		if b.udfParams != nil {
+			// Placeholders in the body of a user-defined function refer to the
+			// parameters of the function.
+			out = b.factory.ConstructVariable(b.udfParams[t.Idx])
+		} else if !b.KeepPlaceholders && b.evalCtx.HasPlaceholders() {
 			b.HadPlaceholders = true
 			// Replace placeholders with their value.
 			d, err := t.Eval(b.evalCtx)
@@ -519,11 +530,16 @@ func (b *Builder) buildFunction(
 		}
 	}
 
-	def, err := f.Func.Resolve(b.semaCtx.SearchPath)
+	def, err := f.Func.ResolveInSemaContext(b.ctx, b.semaCtx)
 	if err != nil {
 		panic(err)
 	}
 
+	if f.ResolvedOverload().UDF != nil {
+		out = b.buildUDF(f, def, inScope, colRefs)
+		return b.finishBuildScalar(f, out, inScope, outScope, outCol)
+	}
+
 	if isAggregate(def) {
 		panic(errors.AssertionFailedf("aggregate function should have been replaced"))
 	}
@@ -124,6 +124,7 @@ const (
 	exprKindOffset
 	exprKindOn
 	exprKindOrderBy
+	exprKindPolicy
 	exprKindReturning
 	exprKindSelect
 	exprKindValues
@@ -144,6 +145,7 @@ var exprKindName = [...]string{
 	exprKindOffset:            "OFFSET",
 	exprKindOn:                "ON",
 	exprKindOrderBy:           "ORDER BY",
+	exprKindPolicy:            "POLICY",
 	exprKindReturning:         "RETURNING",
 	exprKindSelect:            "SELECT",
 	exprKindValues:            "VALUES",
@@ -990,7 +992,7 @@ func (s *scope) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
 		return false, colI.(*scopeColumn)
 
 	case *tree.FuncExpr:
-		def, err := t.Func.Resolve(s.builder.semaCtx.SearchPath)
+		def, err := t.Func.ResolveInSemaContext(s.builder.ctx, s.builder.semaCtx)
 		if err != nil {
 			panic(err)
 		}
@@ -1010,6 +1012,11 @@ func (s *scope) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
 			break
 		}
 
+		if isGroupingFn(def) {
+			expr = s.replaceGroupingFn(t)
+			break
+		}
+
 		if isSQLFn(def) {
 			expr = s.replaceSQLFn(t, def)
 			break
@@ -1209,6 +1216,46 @@ func (s *scope) replaceAggregate(f *tree.FuncExpr, def *tree.FunctionDefinition)
 	return s.builder.buildAggregateFunction(f, &private, tempScope, s)
 }
 
+// replaceGroupingFn returns a groupingFuncInfo that replaces a GROUPING()
+// function call. The function call belongs to the grouping of this scope; its
+// arguments are matched with the grouping columns once they are built (see
+// buildGroupingFuncs).
+func (s *scope) replaceGroupingFn(f *tree.FuncExpr) tree.Expr {
+	switch {
+	case s.inAgg || s.builder.semaCtx.Properties.IsSet(tree.RejectNestedAggregates):
+		panic(pgerror.Newf(pgcode.Grouping,
+			"aggregate function calls cannot contain grouping operations"))
+
+	case s.builder.semaCtx.Properties.IsSet(tree.RejectAggregates),
+		s.context == exprKindWhere, s.context == exprKindOn, s.context == exprKindLateralJoin:
+		kind := s.context.String()
+		if kind == "" {
+			kind = "this context"
+		}
+		panic(pgerror.Newf(pgcode.Grouping, "grouping operations are not allowed in %s", kind))
+	}
+
+	expr := f.Walk(s)
+	typedFunc, err := tree.TypeCheck(s.builder.ctx, expr, s.builder.semaCtx, types.Int)
+	if err != nil {
+		panic(err)
+	}
+
+	if s.groupby == nil {
+		s.initGrouping()
+	}
+	info := &groupingFuncInfo{
+		FuncExpr: typedFunc.(*tree.FuncExpr),
+		col: scopeColumn{
+			name: scopeColName("grouping"),
+			typ:  types.Int,
+			id:   s.builder.factory.Metadata().AddColumn("grouping", types.Int),
+		},
+	}
+	s.groupby.groupingFuncs = append(s.groupby.groupingFuncs, info)
+	return info
+}
+
 func (s *scope) lookupWindowDef(name tree.Name) *tree.WindowDef {
 	for i := range s.windowDefs {
 		if s.windowDefs[i].Name == name {
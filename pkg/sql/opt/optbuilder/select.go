@@ -139,7 +139,7 @@ func (b *Builder) buildDataSource(
 		switch t := ds.(type) {
 		case cat.Table:
 			tabMeta := b.addTable(t, &resName)
-			return b.buildScan(
+			outScope = b.buildScan(
 				tabMeta,
 				tableOrdinals(t, columnKinds{
 					includeMutations:       false,
@@ -149,6 +149,10 @@ func (b *Builder) buildDataSource(
 				}),
 				indexFlags, locking, inScope,
 			)
+			if b.rowLevelSecurityApplies(t) {
+				b.addRowLevelSecurityFilter(t, tree.PolicyCommandSelect, outScope)
+			}
+			return outScope
 
 		case cat.Sequence:
 			return b.buildSequenceSelect(t, &resName, inScope)
@@ -156,6 +160,9 @@ func (b *Builder) buildDataSource(
 		case cat.View:
 			return b.buildView(t, &resName, locking, inScope)
 
+		case cat.ForeignTable:
+			return b.buildForeignScan(t, &resName, inScope)
+
 		default:
 			panic(errors.AssertionFailedf("unknown DataSource type %T", ds))
 		}
@@ -257,6 +264,13 @@ func (b *Builder) buildDataSource(
 			tn := tree.MakeUnqualifiedTableName(t.Name())
 			// Any explicitly listed columns are ignored.
 			outScope = b.buildSequenceSelect(t, &tn, inScope)
+		case cat.ForeignTable:
+			if source.Columns != nil {
+				panic(pgerror.Newf(pgcode.FeatureNotSupported,
+					"cannot specify an explicit column list when accessing a foreign table by reference"))
+			}
+			tn := tree.MakeUnqualifiedTableName(t.Name())
+			outScope = b.buildForeignScan(t, &tn, inScope)
 		default:
 			panic(errors.AssertionFailedf("unsupported catalog object"))
 		}
@@ -435,7 +449,11 @@ func (b *Builder) buildScanFromTableRef(
 
 	tn := tree.MakeUnqualifiedTableName(tab.Name())
 	tabMeta := b.addTable(tab, &tn)
-	return b.buildScan(tabMeta, ordinals, indexFlags, locking, inScope)
+	outScope = b.buildScan(tabMeta, ordinals, indexFlags, locking, inScope)
+	if b.rowLevelSecurityApplies(tab) {
+		b.addRowLevelSecurityFilter(tab, tree.PolicyCommandSelect, outScope)
+	}
+	return outScope
 }
 
 // addTable adds a table to the metadata and returns the TableMeta. The table
@@ -786,6 +804,39 @@ func (b *Builder) buildSequenceSelect(
 	return outScope
 }
 
+// buildForeignScan builds a ForeignScan which reads all the columns of the
+// given foreign table.
+func (b *Builder) buildForeignScan(
+	ft cat.ForeignTable, tabName *tree.TableName, inScope *scope,
+) (outScope *scope) {
+	md := b.factory.Metadata()
+	outScope = inScope.push()
+
+	cols := make(opt.ColList, ft.ColumnCount())
+	outScope.cols = make([]scopeColumn, ft.ColumnCount())
+	for i := range cols {
+		col := ft.Column(i)
+		cols[i] = md.AddColumn(string(col.ColName()), col.DatumType())
+		outScope.cols[i] = scopeColumn{
+			id:    cols[i],
+			name:  scopeColName(col.ColName()),
+			table: *tabName,
+			typ:   col.DatumType(),
+		}
+	}
+
+	private := memo.ForeignScanPrivate{
+		Table: md.AddForeignTable(ft),
+		Cols:  cols,
+	}
+	outScope.expr = b.factory.ConstructForeignScan(&private)
+
+	if b.trackViewDeps {
+		b.viewDeps = append(b.viewDeps, opt.ViewDep{DataSource: ft})
+	}
+	return outScope
+}
+
 // buildWithOrdinality builds a group which appends an increasing integer column
 // to the output.
 //
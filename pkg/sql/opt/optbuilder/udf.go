@@ -0,0 +1,294 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/props"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/errors"
+)
+
+// buildUDF builds a call to a SQL-language user-defined function. The body of
+// the function is parsed and built in a scope containing only the parameters
+// of the function.
+//
+// If the body is a single scalar expression, such as:
+//
+//   CREATE FUNCTION add_one(x INT) RETURNS INT LANGUAGE SQL AS 'SELECT x + 1'
+//
+// then the call is built as a UDF expression, which the InlineUDF rule may
+// replace by the body of the function. Otherwise, the call is built as a
+// scalar subquery which returns the first row of the body, correlated with a
+// single row containing the arguments of the call.
+//
+// See Builder.buildStmt for a description of the remaining input and
+// return values.
+func (b *Builder) buildUDF(
+	f *tree.FuncExpr, def *tree.FunctionDefinition, inScope *scope, colRefs *opt.ColSet,
+) opt.ScalarExpr {
+	o := f.ResolvedOverload()
+	info := o.UDF
+	if b.insideViewDef {
+		panic(unimplemented.NewWithIssuef(17511,
+			"user-defined function %s cannot be used in a view definition", def.Name))
+	}
+	for _, id := range b.udfStack {
+		if id == info.ID {
+			panic(pgerror.Newf(pgcode.InvalidRecursion,
+				"recursive call to user-defined function %s", def.Name))
+		}
+	}
+
+	// The definition of the function can be replaced or dropped, so the memo
+	// cannot be reused.
+	b.DisableMemoReuse = true
+
+	stmt, err := parser.ParseOne(info.Body)
+	if err != nil {
+		panic(err)
+	}
+	sel, ok := stmt.AST.(*tree.Select)
+	if !ok {
+		panic(errors.AssertionFailedf("unexpected body of function %s: %T", def.Name, stmt.AST))
+	}
+
+	args := make(memo.ScalarListExpr, len(f.Exprs))
+	for i, pexpr := range f.Exprs {
+		args[i] = b.buildScalar(pexpr.(tree.TypedExpr), inScope, nil, nil, colRefs)
+	}
+
+	// Create a column for each parameter of the function. The body can refer
+	// to the parameters by name, or by position with placeholders like $1.
+	paramTypes := o.Types.Types()
+	paramScope := b.allocScope()
+	params := make(opt.ColList, len(paramTypes))
+	for i, typ := range paramTypes {
+		col := b.synthesizeColumn(paramScope, scopeColName(tree.Name(info.ParamNames[i])), typ, nil, nil)
+		params[i] = col.id
+	}
+
+	// Build the body in a clean context, in which placeholders refer to the
+	// parameters, and which is not part of any subquery of the calling
+	// statement.
+	defer func(placeholders tree.PlaceholderInfo, udfParams opt.ColList, subquery *subquery) {
+		b.semaCtx.Placeholders = placeholders
+		b.udfParams = udfParams
+		b.subquery = subquery
+		b.udfStack = b.udfStack[:len(b.udfStack)-1]
+	}(b.semaCtx.Placeholders, b.udfParams, b.subquery)
+	b.semaCtx.Placeholders = tree.PlaceholderInfo{
+		PlaceholderTypesInfo: tree.PlaceholderTypesInfo{
+			TypeHints: paramTypes,
+			Types:     paramTypes,
+		},
+	}
+	b.udfParams = params
+	b.subquery = nil
+	b.udfStack = append(b.udfStack, info.ID)
+
+	returnType := f.ResolvedType()
+	private := &memo.UDFPrivate{
+		Name:       def.Name,
+		Params:     params,
+		Typ:        returnType,
+		Volatility: o.Volatility,
+	}
+	if expr, ok := scalarUDFBody(sel, b.semaCtx.SearchPath); ok {
+		texpr := paramScope.resolveType(expr, returnType)
+		checkUDFReturnType(texpr.ResolvedType(), returnType)
+		body := b.buildScalar(texpr, paramScope, nil, nil, nil)
+		if info.ReturnsNullOnNullInput {
+			body = b.buildStrictUDFBody(body, params, returnType)
+		}
+		if !b.factory.CustomFuncs().DuplicatesVolatileUDFArgs(args, body, params) {
+			return b.factory.ConstructUDF(args, body, private)
+		}
+	}
+	return b.buildUDFSubquery(sel, args, paramScope, private, info.ReturnsNullOnNullInput)
+}
+
+// buildUDFSubquery builds a call to a user-defined function as a scalar
+// subquery of the form:
+//
+//   (SELECT body.col FROM (SELECT args...) AS params, LATERAL (body LIMIT 1))
+//
+// Each argument is evaluated only once, and bound to the parameter columns
+// which the body references as outer columns. If the function returns NULL on
+// NULL input, then the row of arguments is filtered out if any argument is
+// NULL, so that the subquery returns NULL.
+//
+// Since the optimizer may prune the columns of the row of arguments which the
+// body doesn't reference, the arguments with volatile operators which it
+// doesn't reference are instead passed to a UDF expression wrapping the
+// subquery, which evaluates them along with the subquery.
+func (b *Builder) buildUDFSubquery(
+	sel *tree.Select,
+	args memo.ScalarListExpr,
+	paramScope *scope,
+	private *memo.UDFPrivate,
+	strict bool,
+) opt.ScalarExpr {
+	params, returnType := private.Params, private.Typ
+	bodyScope := b.buildSelect(sel, noRowLocking, []*types.T{returnType}, paramScope)
+	if len(bodyScope.cols) != 1 {
+		panic(udfReturnTypeMismatchError(returnType))
+	}
+	checkUDFReturnType(bodyScope.cols[0].typ, returnType)
+	body := b.factory.ConstructLimit(
+		bodyScope.expr,
+		b.factory.ConstructConstVal(tree.NewDInt(1), types.Int),
+		bodyScope.makeOrderingChoice(),
+	)
+
+	bodyCols := body.Relational().OuterCols
+	projections := make(memo.ProjectionsExpr, 0, len(args))
+	var unusedArgs memo.ScalarListExpr
+	var unusedParams opt.ColList
+	for i := range args {
+		// The filters of a strict function reference all the parameters.
+		if strict || bodyCols.Contains(params[i]) {
+			projections = append(projections, b.factory.ConstructProjectionsItem(args[i], params[i]))
+			continue
+		}
+		var argProps props.Shared
+		memo.BuildSharedProps(args[i], &argProps, b.evalCtx)
+		if argProps.VolatilitySet.HasVolatile() {
+			unusedArgs = append(unusedArgs, args[i])
+			unusedParams = append(unusedParams, params[i])
+		}
+	}
+	var argsRow memo.RelExpr = b.factory.ConstructProject(
+		b.factory.CustomFuncs().ConstructNoColsRow(), projections, opt.ColSet{},
+	)
+	if strict && len(params) > 0 {
+		filters := make(memo.FiltersExpr, len(params))
+		for i, col := range params {
+			filters[i] = b.factory.ConstructFiltersItem(b.factory.ConstructIsNot(
+				b.factory.ConstructVariable(col), memo.NullSingleton,
+			))
+		}
+		argsRow = b.factory.ConstructSelect(argsRow, filters)
+	}
+
+	out := b.factory.ConstructInnerJoinApply(argsRow, body, memo.TrueFilter, memo.EmptyJoinPrivate)
+	out = b.constructProject(out, bodyScope.cols[:1])
+	subquery := b.factory.ConstructSubquery(out, &memo.SubqueryPrivate{})
+	if len(unusedArgs) == 0 {
+		return subquery
+	}
+	wrapper := *private
+	wrapper.Params = unusedParams
+	return b.factory.ConstructUDF(unusedArgs, subquery, &wrapper)
+}
+
+// buildStrictUDFBody wraps the body of a function which returns NULL on NULL
+// input in a CASE expression which returns NULL if any parameter is NULL.
+func (b *Builder) buildStrictUDFBody(
+	body opt.ScalarExpr, params opt.ColList, returnType *types.T,
+) opt.ScalarExpr {
+	if len(params) == 0 {
+		return body
+	}
+	var anyNull opt.ScalarExpr
+	for _, col := range params {
+		isNull := b.factory.ConstructIs(b.factory.ConstructVariable(col), memo.NullSingleton)
+		if anyNull == nil {
+			anyNull = isNull
+		} else {
+			anyNull = b.factory.ConstructOr(anyNull, isNull)
+		}
+	}
+	return b.factory.ConstructCase(
+		memo.TrueSingleton,
+		memo.ScalarListExpr{
+			b.factory.ConstructWhen(anyNull, b.factory.ConstructNull(returnType)),
+		},
+		body,
+	)
+}
+
+// scalarUDFBody returns the expression of the body of a user-defined function
+// if the body is a single scalar expression without a FROM clause, subqueries,
+// aggregates, window functions or set-returning functions.
+func scalarUDFBody(sel *tree.Select, searchPath sessiondata.SearchPath) (tree.Expr, bool) {
+	if sel.With != nil || sel.OrderBy != nil || sel.Limit != nil || sel.Locking != nil {
+		return nil, false
+	}
+	clause, ok := sel.Select.(*tree.SelectClause)
+	if !ok || len(clause.Exprs) != 1 || len(clause.From.Tables) != 0 || clause.Distinct ||
+		clause.DistinctOn != nil || clause.Where != nil || clause.GroupBy != nil ||
+		clause.Having != nil || clause.Window != nil {
+		return nil, false
+	}
+	expr := clause.Exprs[0].Expr
+	if _, ok := expr.(tree.UnqualifiedStar); ok {
+		return nil, false
+	}
+	v := scalarUDFBodyVisitor{searchPath: searchPath}
+	tree.WalkExprConst(&v, expr)
+	return expr, !v.notScalar
+}
+
+// scalarUDFBodyVisitor searches an expression for the constructs which cannot
+// be built as part of a scalar UDF expression.
+type scalarUDFBodyVisitor struct {
+	searchPath sessiondata.SearchPath
+	notScalar  bool
+}
+
+var _ tree.Visitor = &scalarUDFBodyVisitor{}
+
+// VisitPre is part of the tree.Visitor interface.
+func (v *scalarUDFBodyVisitor) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
+	if v.notScalar {
+		return false, expr
+	}
+	switch t := expr.(type) {
+	case *tree.Subquery:
+		v.notScalar = true
+	case *tree.FuncExpr:
+		if t.WindowDef != nil || t.Filter != nil || len(t.OrderBy) > 0 {
+			v.notScalar = true
+			break
+		}
+		// User-defined functions are resolved when the expression is type
+		// checked; only built-in functions need to be checked here.
+		if def, err := t.Func.Resolve(v.searchPath); err == nil &&
+			def.Class != tree.NormalClass {
+			v.notScalar = true
+		}
+	}
+	return !v.notScalar, expr
+}
+
+// VisitPost is part of the tree.Visitor interface.
+func (v *scalarUDFBodyVisitor) VisitPost(expr tree.Expr) tree.Expr { return expr }
+
+// checkUDFReturnType panics if the type of the result of the body of a
+// function does not match the declared return type of the function.
+func checkUDFReturnType(typ, returnType *types.T) {
+	if typ.Family() != types.UnknownFamily && !typ.Equivalent(returnType) {
+		panic(udfReturnTypeMismatchError(returnType))
+	}
+}
+
+func udfReturnTypeMismatchError(returnType *types.T) error {
+	return pgerror.Newf(pgcode.InvalidFunctionDefinition,
+		"return type mismatch in function declared to return %s", returnType.SQLString())
+}
@@ -89,6 +89,8 @@ func (b *Builder) buildUpdate(upd *tree.Update, inScope *scope) (outScope *scope
 
 	var mb mutationBuilder
 	mb.init(b, "update", tab, alias)
+	mb.initTriggerRows(inScope)
+	mb.rowLevelSecurity = b.rowLevelSecurityApplies(tab)
 
 	// Build the input expression that selects the rows that will be updated:
 	//
@@ -324,6 +326,10 @@ func (mb *mutationBuilder) addSynthesizedColsForUpdate() {
 		true,  /* applyOnUpdate */
 	)
 
+	// Apply the assignments of BEFORE UPDATE triggers. Do this before adding
+	// computed columns, since those may depend on the assigned columns.
+	mb.buildBeforeTriggers(tree.TriggerEventUpdate, mb.updateColIDs)
+
 	// Possibly round DECIMAL-related columns containing update values. Do
 	// this before evaluating computed expressions, since those may depend on
 	// the inserted columns.
@@ -343,6 +349,8 @@ func (mb *mutationBuilder) addSynthesizedColsForUpdate() {
 // buildUpdate constructs an Update operator, possibly wrapped by a Project
 // operator that corresponds to the given RETURNING clause.
 func (mb *mutationBuilder) buildUpdate(returning tree.ReturningExprs) {
+	mb.joinTriggerRows()
+
 	// Disambiguate names so that references in any expressions, such as a
 	// check constraint, refer to the correct columns.
 	mb.disambiguateColumns()
@@ -350,6 +358,9 @@ func (mb *mutationBuilder) buildUpdate(returning tree.ReturningExprs) {
 	// Add any check constraint boolean columns to the input.
 	mb.addCheckConstraintCols(true /* isUpdate */)
 
+	// Check the updated rows against the row-level security policies.
+	mb.addRowLevelSecurityChecks(tree.PolicyCommandUpdate)
+
 	// Add the partial index predicate expressions to the table metadata.
 	// These expressions are used to prune fetch columns during
 	// normalization.
@@ -362,6 +373,8 @@ func (mb *mutationBuilder) buildUpdate(returning tree.ReturningExprs) {
 
 	mb.buildFKChecksForUpdate()
 
+	mb.buildAfterTriggers(tree.TriggerEventUpdate)
+
 	private := mb.makeMutationPrivate(returning != nil)
 	for _, col := range mb.extraAccessibleCols {
 		if col.id != 0 {
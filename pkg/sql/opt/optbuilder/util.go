@@ -583,6 +583,9 @@ func (b *Builder) resolveTable(
 	ds, _, resName := b.resolveDataSource(tn, priv)
 	tab, ok := ds.(cat.Table)
 	if !ok {
+		if _, ok := ds.(cat.ForeignTable); ok {
+			panic(readOnlyForeignTableError(tn))
+		}
 		panic(sqlerrors.NewWrongObjectTypeError(tn, "table"))
 	}
 	return tab, resName
@@ -595,11 +598,22 @@ func (b *Builder) resolveTableRef(ref *tree.TableRef, priv privilege.Kind) cat.T
 	ds, _ := b.resolveDataSourceRef(ref, priv)
 	tab, ok := ds.(cat.Table)
 	if !ok {
+		if _, ok := ds.(cat.ForeignTable); ok {
+			panic(readOnlyForeignTableError(ref))
+		}
 		panic(sqlerrors.NewWrongObjectTypeError(ref, "table"))
 	}
 	return tab
 }
 
+// readOnlyForeignTableError is raised when a foreign table is used where a
+// table with indexes and stored rows is required, such as the target of a
+// mutation.
+func readOnlyForeignTableError(name tree.NodeFormatter) error {
+	return pgerror.Newf(pgcode.WrongObjectType,
+		"%q is a foreign table, which is read-only and has no indexes", tree.ErrString(name))
+}
+
 // resolveDataSource returns the data source in the catalog with the given name,
 // along with the table's MDDepName and data source name. If the name does not
 // resolve to a table, or if the current user does not have the given privilege,
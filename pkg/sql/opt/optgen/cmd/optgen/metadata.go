@@ -196,6 +196,7 @@ func newMetadata(compiled *lang.CompiledExpr, pkg string) *metadata {
 		"TableID":             {fullName: "opt.TableID", passByVal: true},
 		"SchemaID":            {fullName: "opt.SchemaID", passByVal: true},
 		"SequenceID":          {fullName: "opt.SequenceID", passByVal: true},
+		"ForeignTableID":      {fullName: "opt.ForeignTableID", passByVal: true},
 		"UniqueID":            {fullName: "opt.UniqueID", passByVal: true},
 		"WithID":              {fullName: "opt.WithID", passByVal: true},
 		"Ordering":            {fullName: "opt.Ordering", passByVal: true},
@@ -244,6 +245,7 @@ func newMetadata(compiled *lang.CompiledExpr, pkg string) *metadata {
 		"SpanExpression":      {fullName: "inverted.SpanExpression", isPointer: true, usePointerIntern: true},
 		"InvertedSpans":       {fullName: "inverted.Spans", passByVal: true},
 		"Persistence":         {fullName: "tree.Persistence", passByVal: true},
+		"Volatility":          {fullName: "tree.Volatility", passByVal: true},
 		"PreFiltererState":    {fullName: "invertedexpr.PreFiltererStateForInvertedFilterer", isPointer: true, usePointerIntern: true},
 	}
 
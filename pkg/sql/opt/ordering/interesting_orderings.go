@@ -35,7 +35,7 @@ func DeriveInterestingOrderings(e memo.RelExpr) props.OrderingSet {
 	case opt.ScanOp:
 		res = interestingOrderingsForScan(e.(*memo.ScanExpr))
 
-	case opt.SelectOp, opt.IndexJoinOp, opt.LookupJoinOp:
+	case opt.SelectOp, opt.BarrierOp, opt.IndexJoinOp, opt.LookupJoinOp:
 		res = interestingOrderingsForExpr(e)
 
 	case opt.ProjectOp:
@@ -104,6 +104,11 @@ func init() {
 		buildChildReqOrdering: selectBuildChildReqOrdering,
 		buildProvidedOrdering: selectBuildProvided,
 	}
+	funcMap[opt.BarrierOp] = funcs{
+		canProvideOrdering:    barrierCanProvideOrdering,
+		buildChildReqOrdering: barrierBuildChildReqOrdering,
+		buildProvidedOrdering: barrierBuildProvided,
+	}
 	funcMap[opt.ProjectOp] = funcs{
 		canProvideOrdering:    projectCanProvideOrdering,
 		buildChildReqOrdering: projectBuildChildReqOrdering,
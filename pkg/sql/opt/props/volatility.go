@@ -77,6 +77,11 @@ import "github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 //   INNER JOIN (SELECT * FROM xz WHERE xz.x=random())
 //   ON xy.x=xz.x
 //
+// A call to a user-defined function contributes the volatility declared by
+// the definition of the function, rather than the volatility of its body. The
+// body of the function is only inlined into the calling expression when its
+// volatility is no greater than the declared volatility (see IsAtMost).
+//
 type VolatilitySet uint8
 
 // Add a volatility to the set.
@@ -120,6 +125,13 @@ func (vs VolatilitySet) HasVolatile() bool {
 	return (vs & volatilityBit(tree.VolatilityVolatile)) != 0
 }
 
+// IsAtMost returns true if every volatility in the set is no greater than the
+// given volatility. For example, a set containing only VolatilityImmutable is
+// at most VolatilityStable.
+func (vs VolatilitySet) IsAtMost(v tree.Volatility) bool {
+	return vs&^(volatilityBit(v)<<1-1) == 0
+}
+
 func (vs VolatilitySet) String() string {
 	// The only properties we care about are IsLeakProof(), HasStable() and
 	// HasVolatile(). We print one of the strings below:
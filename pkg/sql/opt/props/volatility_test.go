@@ -55,3 +55,22 @@ func TestVolatilitySet(t *testing.T) {
 	v.UnionWith(w)
 	check("stable+volatile", false, true, true)
 }
+
+func TestVolatilitySetIsAtMost(t *testing.T) {
+	var v VolatilitySet
+	require.True(t, v.IsAtMost(tree.VolatilityLeakProof))
+
+	v.AddImmutable()
+	require.False(t, v.IsAtMost(tree.VolatilityLeakProof))
+	require.True(t, v.IsAtMost(tree.VolatilityImmutable))
+	require.True(t, v.IsAtMost(tree.VolatilityStable))
+
+	v.AddStable()
+	require.False(t, v.IsAtMost(tree.VolatilityImmutable))
+	require.True(t, v.IsAtMost(tree.VolatilityStable))
+	require.True(t, v.IsAtMost(tree.VolatilityVolatile))
+
+	v.AddVolatile()
+	require.False(t, v.IsAtMost(tree.VolatilityStable))
+	require.True(t, v.IsAtMost(tree.VolatilityVolatile))
+}
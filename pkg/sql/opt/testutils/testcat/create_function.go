@@ -0,0 +1,90 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package testcat
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
+)
+
+var _ tree.FunctionResolver = (*Catalog)(nil)
+
+// CreateFunction handles the CREATE FUNCTION statement. The body of the
+// function is not validated.
+func (tc *Catalog) CreateFunction(c *tree.CreateFunction) {
+	// We don't handle qualified names.
+	name := c.Name.Object()
+	if _, ok := tree.FunDefs[name]; ok {
+		panic(errors.Newf("function %q conflicts with a built-in function", name))
+	}
+	id := uint32(len(tc.udfs) + 1)
+	if existing, ok := tc.udfs[name]; ok {
+		if !c.Replace {
+			panic(errors.Newf("function %q already exists", name))
+		}
+		id = existing.Definition[0].(*tree.Overload).UDF.ID
+	}
+
+	paramTypes := make(tree.ArgTypes, len(c.Params))
+	paramNames := make([]string, len(c.Params))
+	for i := range c.Params {
+		typ, err := tree.ResolveType(context.Background(), c.Params[i].Type, tc)
+		if err != nil {
+			panic(err)
+		}
+		paramTypes[i].Name = string(c.Params[i].Name)
+		paramTypes[i].Typ = typ
+		paramNames[i] = string(c.Params[i].Name)
+	}
+	returnType, err := tree.ResolveType(context.Background(), c.ReturnType, tc)
+	if err != nil {
+		panic(err)
+	}
+
+	volatility := tree.VolatilityVolatile
+	var leakproof, strict bool
+	var body string
+	for _, option := range c.Options {
+		switch t := option.(type) {
+		case tree.FunctionVolatility:
+			volatility = tree.Volatility(t)
+		case tree.FunctionLeakproof:
+			leakproof = bool(t)
+		case tree.FunctionNullInputBehavior:
+			strict = t != tree.FunctionCalledOnNullInput
+		case tree.FunctionBodyStr:
+			body = string(t)
+		}
+	}
+	if leakproof && volatility == tree.VolatilityImmutable {
+		volatility = tree.VolatilityLeakProof
+	}
+
+	if tc.udfs == nil {
+		tc.udfs = make(map[string]*tree.FunctionDefinition)
+	}
+	tc.udfs[name] = tree.NewUDFDefinition(name, paramTypes, returnType, volatility, &tree.UDFInfo{
+		ID:                     id,
+		ParamNames:             paramNames,
+		Body:                   body,
+		ReturnsNullOnNullInput: strict,
+	})
+}
+
+// ResolveFunction is part of the tree.FunctionResolver interface.
+func (tc *Catalog) ResolveFunction(
+	_ context.Context, name *tree.UnresolvedName,
+) (*tree.FunctionDefinition, error) {
+	// We don't handle qualified names.
+	return tc.udfs[name.Parts[0]], nil
+}
@@ -478,6 +478,9 @@ func (c *coster) ComputeCost(candidate memo.RelExpr, required *physical.Required
 	case opt.ValuesOp:
 		cost = c.computeValuesCost(candidate.(*memo.ValuesExpr))
 
+	case opt.ForeignScanOp:
+		cost = c.computeForeignScanCost(candidate.(*memo.ForeignScanExpr))
+
 	case opt.InnerJoinOp, opt.LeftJoinOp, opt.RightJoinOp, opt.FullJoinOp,
 		opt.SemiJoinOp, opt.AntiJoinOp, opt.InnerJoinApplyOp, opt.LeftJoinApplyOp,
 		opt.SemiJoinApplyOp, opt.AntiJoinApplyOp:
@@ -747,6 +750,14 @@ func (c *coster) computeValuesCost(values *memo.ValuesExpr) memo.Cost {
 	return memo.Cost(values.Relational().Stats.RowCount) * cpuCostFactor
 }
 
+func (c *coster) computeForeignScanCost(scan *memo.ForeignScanExpr) memo.Cost {
+	// The files of foreign tables are read sequentially in full, and every
+	// value is parsed from its text or Avro representation.
+	rowCount := scan.Relational().Stats.RowCount
+	numCols := scan.Cols.Len()
+	return memo.Cost(rowCount) * (seqIOCostFactor + memo.Cost(numCols)*cpuCostFactor)
+}
+
 func (c *coster) computeHashJoinCost(join memo.RelExpr) memo.Cost {
 	if join.Private().(*memo.JoinPrivate).Flags.Has(memo.DisallowHashJoinStoreRight) {
 		return hugeCost
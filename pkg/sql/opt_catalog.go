@@ -13,6 +13,7 @@ package sql
 import (
 	"context"
 	"math"
+	"sort"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/config"
@@ -21,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
@@ -270,6 +272,8 @@ func getDescFromCatalogObjectForPermissions(o cat.Object) (catalog.Descriptor, e
 		return t.desc, nil
 	case *optSequence:
 		return t.desc, nil
+	case *optForeignTable:
+		return t.desc, nil
 	default:
 		return nil, errors.AssertionFailedf("invalid object type: %T", o)
 	}
@@ -285,6 +289,8 @@ func getDescForDataSource(o cat.DataSource) (catalog.TableDescriptor, error) {
 		return t.desc, nil
 	case *optSequence:
 		return t.desc, nil
+	case *optForeignTable:
+		return t.desc, nil
 	default:
 		return nil, errors.AssertionFailedf("invalid object type: %T", o)
 	}
@@ -313,6 +319,23 @@ func (oc *optCatalog) HasAdminRole(ctx context.Context) (bool, error) {
 	return oc.planner.HasAdminRole(ctx)
 }
 
+// HasOwnership is part of the cat.Catalog interface.
+func (oc *optCatalog) HasOwnership(ctx context.Context, o cat.Object) (bool, error) {
+	desc, err := getDescFromCatalogObjectForPermissions(o)
+	if err != nil {
+		return false, err
+	}
+	return oc.planner.HasOwnership(ctx, desc)
+}
+
+// IsMemberOfRole is part of the cat.Catalog interface.
+func (oc *optCatalog) IsMemberOfRole(ctx context.Context, role string) (bool, error) {
+	target := security.MakeSQLUsernameFromPreNormalizedString(role)
+	return oc.planner.checkRolePredicate(ctx, oc.planner.User(), func(r security.SQLUsername) bool {
+		return r == target
+	})
+}
+
 // RequireAdminRole is part of the cat.Catalog interface.
 func (oc *optCatalog) RequireAdminRole(ctx context.Context, action string) error {
 	return oc.planner.RequireAdminRole(ctx, action)
@@ -375,6 +398,17 @@ func (oc *optCatalog) fullyQualifiedNameWithTxn(
 func (oc *optCatalog) dataSourceForDesc(
 	ctx context.Context, flags cat.Flags, desc catalog.TableDescriptor, name *cat.DataSourceName,
 ) (cat.DataSource, error) {
+	// Foreign tables are backed by files in external storage rather than by
+	// KV data, so they are not planned as tables.
+	if desc.IsForeignTable() {
+		if ds, ok := oc.dataSources[desc]; ok {
+			return ds, nil
+		}
+		ds := newOptForeignTable(desc)
+		oc.dataSources[desc] = ds
+		return ds, nil
+	}
+
 	// Because they are backed by physical data, we treat materialized views
 	// as tables for the purposes of planning.
 	if desc.IsTable() || desc.MaterializedView() {
@@ -583,6 +617,85 @@ func (os *optSequence) CollectTypes(ord int) (descpb.IDs, error) {
 	return collectTypes(col)
 }
 
+// optForeignTable is a wrapper around catalog.TableDescriptor that implements
+// the cat.Object, cat.DataSource and cat.ForeignTable interfaces. Only the
+// visible columns of the descriptor are presented to the catalog, since those
+// are the only columns that have values in the backing files.
+type optForeignTable struct {
+	desc catalog.TableDescriptor
+
+	columns []cat.Column
+}
+
+var _ cat.DataSource = &optForeignTable{}
+var _ cat.ForeignTable = &optForeignTable{}
+
+func newOptForeignTable(desc catalog.TableDescriptor) *optForeignTable {
+	ot := &optForeignTable{desc: desc}
+	visible := desc.VisibleColumns()
+	ot.columns = make([]cat.Column, len(visible))
+	for i, col := range visible {
+		// Values in the files are not validated against the column
+		// definitions, so every column is nullable.
+		ot.columns[i].Init(
+			i,
+			cat.StableID(col.GetID()),
+			col.ColName(),
+			cat.Ordinary,
+			col.GetType(),
+			true, /* nullable */
+			cat.Visible,
+			nil, /* defaultExpr */
+			nil, /* computedExpr */
+			nil, /* onUpdateExpr */
+			cat.NotGeneratedAsIdentity,
+			nil, /* generatedAsIdentitySequenceOption */
+		)
+	}
+	return ot
+}
+
+// ID is part of the cat.Object interface.
+func (ot *optForeignTable) ID() cat.StableID {
+	return cat.StableID(ot.desc.GetID())
+}
+
+// PostgresDescriptorID is part of the cat.Object interface.
+func (ot *optForeignTable) PostgresDescriptorID() cat.StableID {
+	return cat.StableID(ot.desc.GetID())
+}
+
+// Equals is part of the cat.Object interface.
+func (ot *optForeignTable) Equals(other cat.Object) bool {
+	otherTable, ok := other.(*optForeignTable)
+	if !ok {
+		return false
+	}
+	return ot.desc.GetID() == otherTable.desc.GetID() &&
+		ot.desc.GetVersion() == otherTable.desc.GetVersion()
+}
+
+// Name is part of the cat.DataSource interface.
+func (ot *optForeignTable) Name() tree.Name {
+	return tree.Name(ot.desc.GetName())
+}
+
+// ColumnCount is part of the cat.ForeignTable interface.
+func (ot *optForeignTable) ColumnCount() int {
+	return len(ot.columns)
+}
+
+// Column is part of the cat.ForeignTable interface.
+func (ot *optForeignTable) Column(i int) *cat.Column {
+	return &ot.columns[i]
+}
+
+// CollectTypes is part of the cat.DataSource interface.
+func (ot *optForeignTable) CollectTypes(ord int) (descpb.IDs, error) {
+	col := ot.desc.VisibleColumns()[ord]
+	return collectTypes(col)
+}
+
 // optTable is a wrapper around catalog.TableDescriptor that caches
 // index wrappers and maintains a ColumnID => Column mapping for fast lookup.
 type optTable struct {
@@ -632,6 +745,14 @@ type optTable struct {
 	// constraints for user defined types.
 	checkConstraints []cat.CheckConstraint
 
+	// triggers are the inlined wrappers for the table's triggers, ordered by
+	// name.
+	triggers []optTrigger
+
+	// policies are the inlined wrappers for the table's row-level security
+	// policies, ordered by name.
+	policies []optPolicy
+
 	// colMap is a mapping from unique ColumnID to column ordinal within the
 	// table. This is a common lookup that needs to be fast.
 	colMap catalog.TableColMap
@@ -755,12 +876,13 @@ func newOptTable(
 	for i := range ot.desc.GetUniqueWithoutIndexConstraints() {
 		u := &ot.desc.GetUniqueWithoutIndexConstraints()[i]
 		ot.uniqueConstraints = append(ot.uniqueConstraints, optUniqueConstraint{
-			name:         u.Name,
-			table:        ot.ID(),
-			columns:      u.ColumnIDs,
-			predicate:    u.Predicate,
-			withoutIndex: true,
-			validity:     u.Validity,
+			name:          u.Name,
+			table:         ot.ID(),
+			columns:       u.ColumnIDs,
+			predicate:     u.Predicate,
+			withoutIndex:  true,
+			validity:      u.Validity,
+			deferrability: u.Deferrability,
 		})
 	}
 
@@ -848,6 +970,7 @@ func newOptTable(
 			match:             fk.Match,
 			deleteAction:      fk.OnDelete,
 			updateAction:      fk.OnUpdate,
+			deferrability:     fk.Deferrability,
 		})
 	}
 	for i := range ot.desc.GetInboundFKs() {
@@ -862,6 +985,7 @@ func newOptTable(
 			match:             fk.Match,
 			deleteAction:      fk.OnDelete,
 			updateAction:      fk.OnUpdate,
+			deferrability:     fk.Deferrability,
 		})
 	}
 
@@ -871,6 +995,22 @@ func newOptTable(
 		ot.families[i].init(ot, &desc.GetFamilies()[i+1])
 	}
 
+	ot.triggers = make([]optTrigger, len(desc.GetTriggers()))
+	for i := range ot.triggers {
+		ot.triggers[i] = optTrigger{tab: ot, desc: &desc.GetTriggers()[i]}
+	}
+	sort.Slice(ot.triggers, func(i, j int) bool {
+		return ot.triggers[i].desc.Name < ot.triggers[j].desc.Name
+	})
+
+	ot.policies = make([]optPolicy, len(desc.GetPolicies()))
+	for i := range ot.policies {
+		ot.policies[i] = optPolicy{desc: &desc.GetPolicies()[i]}
+	}
+	sort.Slice(ot.policies, func(i, j int) bool {
+		return ot.policies[i].desc.Name < ot.policies[j].desc.Name
+	})
+
 	// Synthesize any check constraints for user defined types.
 	var synthesizedChecks []cat.CheckConstraint
 	for i := 0; i < ot.ColumnCount(); i++ {
@@ -1127,6 +1267,36 @@ func (ot *optTable) Unique(i cat.UniqueOrdinal) cat.UniqueConstraint {
 	return &ot.uniqueConstraints[i]
 }
 
+// TriggerCount is part of the cat.Table interface.
+func (ot *optTable) TriggerCount() int {
+	return len(ot.triggers)
+}
+
+// Trigger is part of the cat.Table interface.
+func (ot *optTable) Trigger(i int) cat.Trigger {
+	return &ot.triggers[i]
+}
+
+// IsRowLevelSecurityEnabled is part of the cat.Table interface.
+func (ot *optTable) IsRowLevelSecurityEnabled() bool {
+	return ot.desc.GetRowLevelSecurity()
+}
+
+// IsRowLevelSecurityForced is part of the cat.Table interface.
+func (ot *optTable) IsRowLevelSecurityForced() bool {
+	return ot.desc.GetForceRowLevelSecurity()
+}
+
+// PolicyCount is part of the cat.Table interface.
+func (ot *optTable) PolicyCount() int {
+	return len(ot.policies)
+}
+
+// Policy is part of the cat.Table interface.
+func (ot *optTable) Policy(i int) cat.Policy {
+	return &ot.policies[i]
+}
+
 // lookupColumnOrdinal returns the ordinal of the column with the given ID. A
 // cache makes the lookup O(1).
 func (ot *optTable) lookupColumnOrdinal(colID descpb.ColumnID) (int, error) {
@@ -1592,8 +1762,9 @@ type optUniqueConstraint struct {
 	columns   []descpb.ColumnID
 	predicate string
 
-	withoutIndex bool
-	validity     descpb.ConstraintValidity
+	withoutIndex  bool
+	validity      descpb.ConstraintValidity
+	deferrability descpb.ConstraintDeferrability
 }
 
 var _ cat.UniqueConstraint = &optUniqueConstraint{}
@@ -1641,6 +1812,99 @@ func (u *optUniqueConstraint) Validated() bool {
 	return u.validity == descpb.ConstraintValidity_Validated
 }
 
+// Deferrability is part of the cat.UniqueConstraint interface.
+func (u *optUniqueConstraint) Deferrability() tree.ConstraintDeferrability {
+	return descpb.ConstraintDeferrabilityType[u.deferrability]
+}
+
+// optTrigger is a wrapper around descpb.TriggerDescriptor that keeps a
+// reference to the table wrapper.
+type optTrigger struct {
+	tab  *optTable
+	desc *descpb.TriggerDescriptor
+}
+
+var _ cat.Trigger = &optTrigger{}
+
+// Name is part of the cat.Trigger interface.
+func (t *optTrigger) Name() string {
+	return t.desc.Name
+}
+
+// ActionTime is part of the cat.Trigger interface.
+func (t *optTrigger) ActionTime() tree.TriggerActionTime {
+	return descpb.TriggerDescriptorActionTime[t.desc.ActionTime]
+}
+
+// HasEvent is part of the cat.Trigger interface.
+func (t *optTrigger) HasEvent(event tree.TriggerEvent) bool {
+	return t.desc.HasEvent(descpb.TriggerEventValue[event])
+}
+
+// WhenExpr is part of the cat.Trigger interface.
+func (t *optTrigger) WhenExpr() string {
+	return t.desc.When
+}
+
+// Body is part of the cat.Trigger interface.
+func (t *optTrigger) Body() string {
+	return t.desc.Body
+}
+
+// AssignmentCount is part of the cat.Trigger interface.
+func (t *optTrigger) AssignmentCount() int {
+	return len(t.desc.Assignments)
+}
+
+// Assignment is part of the cat.Trigger interface.
+func (t *optTrigger) Assignment(i int) cat.TriggerAssignment {
+	a := &t.desc.Assignments[i]
+	ord, _ := t.tab.lookupColumnOrdinal(a.ColumnID)
+	return cat.TriggerAssignment{ColumnOrdinal: ord, Expr: a.Expr}
+}
+
+// optPolicy is a wrapper around descpb.PolicyDescriptor.
+type optPolicy struct {
+	desc *descpb.PolicyDescriptor
+}
+
+var _ cat.Policy = &optPolicy{}
+
+// Name is part of the cat.Policy interface.
+func (p *optPolicy) Name() string {
+	return p.desc.Name
+}
+
+// Command is part of the cat.Policy interface.
+func (p *optPolicy) Command() tree.PolicyCommand {
+	return descpb.PolicyDescriptorCommand[p.desc.Command]
+}
+
+// IsRestrictive is part of the cat.Policy interface.
+func (p *optPolicy) IsRestrictive() bool {
+	return p.desc.Restrictive
+}
+
+// RoleCount is part of the cat.Policy interface.
+func (p *optPolicy) RoleCount() int {
+	return len(p.desc.Roles)
+}
+
+// Role is part of the cat.Policy interface.
+func (p *optPolicy) Role(i int) string {
+	return p.desc.Roles[i]
+}
+
+// UsingExpr is part of the cat.Policy interface.
+func (p *optPolicy) UsingExpr() string {
+	return p.desc.UsingExpr
+}
+
+// WithCheckExpr is part of the cat.Policy interface.
+func (p *optPolicy) WithCheckExpr() string {
+	return p.desc.WithCheckExpr
+}
+
 // optForeignKeyConstraint implements cat.ForeignKeyConstraint and represents a
 // foreign key relationship. Both the origin and the referenced table store the
 // same optForeignKeyConstraint (as an outbound and inbound reference,
@@ -1654,10 +1918,11 @@ type optForeignKeyConstraint struct {
 	referencedTable   cat.StableID
 	referencedColumns []descpb.ColumnID
 
-	validity     descpb.ConstraintValidity
-	match        descpb.ForeignKeyReference_Match
-	deleteAction descpb.ForeignKeyReference_Action
-	updateAction descpb.ForeignKeyReference_Action
+	validity      descpb.ConstraintValidity
+	match         descpb.ForeignKeyReference_Match
+	deleteAction  descpb.ForeignKeyReference_Action
+	updateAction  descpb.ForeignKeyReference_Action
+	deferrability descpb.ConstraintDeferrability
 }
 
 var _ cat.ForeignKeyConstraint = &optForeignKeyConstraint{}
@@ -1729,6 +1994,11 @@ func (fk *optForeignKeyConstraint) UpdateReferenceAction() tree.ReferenceAction
 	return descpb.ForeignKeyReferenceActionType[fk.updateAction]
 }
 
+// Deferrability is part of the cat.ForeignKeyConstraint interface.
+func (fk *optForeignKeyConstraint) Deferrability() tree.ConstraintDeferrability {
+	return descpb.ConstraintDeferrabilityType[fk.deferrability]
+}
+
 // optVirtualTable is similar to optTable but is used with virtual tables.
 type optVirtualTable struct {
 	desc catalog.TableDescriptor
@@ -2021,6 +2291,36 @@ func (ot *optVirtualTable) Unique(i cat.UniqueOrdinal) cat.UniqueConstraint {
 	panic(errors.AssertionFailedf("no unique constraints"))
 }
 
+// TriggerCount is part of the cat.Table interface.
+func (ot *optVirtualTable) TriggerCount() int {
+	return 0
+}
+
+// Trigger is part of the cat.Table interface.
+func (ot *optVirtualTable) Trigger(i int) cat.Trigger {
+	panic(errors.AssertionFailedf("no triggers"))
+}
+
+// IsRowLevelSecurityEnabled is part of the cat.Table interface.
+func (ot *optVirtualTable) IsRowLevelSecurityEnabled() bool {
+	return false
+}
+
+// IsRowLevelSecurityForced is part of the cat.Table interface.
+func (ot *optVirtualTable) IsRowLevelSecurityForced() bool {
+	return false
+}
+
+// PolicyCount is part of the cat.Table interface.
+func (ot *optVirtualTable) PolicyCount() int {
+	return 0
+}
+
+// Policy is part of the cat.Table interface.
+func (ot *optVirtualTable) Policy(i int) cat.Policy {
+	panic(errors.AssertionFailedf("no policies"))
+}
+
 // CollectTypes is part of the cat.DataSource interface.
 func (ot *optVirtualTable) CollectTypes(ord int) (descpb.IDs, error) {
 	col := ot.desc.AllColumns()[ord]
@@ -1859,6 +1859,13 @@ func (ef *execFactory) ConstructSequenceSelect(sequence cat.Sequence) (exec.Node
 	return ef.planner.SequenceSelectNode(sequence.(*optSequence).desc)
 }
 
+// ConstructForeignScan is part of the exec.Factory interface.
+func (ef *execFactory) ConstructForeignScan(table cat.ForeignTable) (exec.Node, error) {
+	return ef.planner.newForeignScanNode(
+		ef.planner.extendedEvalCtx.Context, table.(*optForeignTable).desc,
+	)
+}
+
 // ConstructSaveTable is part of the exec.Factory interface.
 func (ef *execFactory) ConstructSaveTable(
 	input exec.Node, table *cat.DataSourceName, colNames []string,
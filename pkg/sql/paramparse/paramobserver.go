@@ -15,11 +15,15 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/geo/geoindex"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/errors"
+	"github.com/gorhill/cronexpr"
 )
 
 // ApplyStorageParameters applies given storage parameters with the
@@ -61,14 +65,30 @@ func ApplyStorageParameters(
 	return paramObserver.RunPostChecks()
 }
 
+// ResetStorageParameters resets the given storage parameters to their
+// defaults with the given observer.
+func ResetStorageParameters(
+	evalCtx *tree.EvalContext, params tree.NameList, paramObserver StorageParamObserver,
+) error {
+	for _, p := range params {
+		if err := paramObserver.Reset(evalCtx, string(p)); err != nil {
+			return err
+		}
+	}
+	return paramObserver.RunPostChecks()
+}
+
 // StorageParamObserver applies a storage parameter to an underlying item.
 type StorageParamObserver interface {
 	Apply(evalCtx *tree.EvalContext, key string, datum tree.Datum) error
+	Reset(evalCtx *tree.EvalContext, key string) error
 	RunPostChecks() error
 }
 
 // TableStorageParamObserver observes storage parameters for tables.
-type TableStorageParamObserver struct{}
+type TableStorageParamObserver struct {
+	TableDesc *descpb.TableDescriptor
+}
 
 var _ StorageParamObserver = (*TableStorageParamObserver)(nil)
 
@@ -89,11 +109,134 @@ func applyFillFactorStorageParam(evalCtx *tree.EvalContext, key string, datum tr
 	return nil
 }
 
+func datumAsBool(evalCtx *tree.EvalContext, key string, datum tree.Datum) (bool, error) {
+	if stringVal, err := DatumAsString(evalCtx, key, datum); err == nil {
+		return ParseBoolVar(key, stringVal)
+	}
+	b, err := GetSingleBool(key, datum)
+	if err != nil {
+		return false, err
+	}
+	return bool(*b), nil
+}
+
+// rowLevelTTL returns the row-level TTL of the table, which is added if
+// the table has none.
+func (a *TableStorageParamObserver) rowLevelTTL() *descpb.TableDescriptor_RowLevelTTL {
+	if a.TableDesc.RowLevelTTL == nil {
+		a.TableDesc.RowLevelTTL = &descpb.TableDescriptor_RowLevelTTL{}
+	}
+	return a.TableDesc.RowLevelTTL
+}
+
+func (a *TableStorageParamObserver) applyRowLevelTTLStorageParam(
+	evalCtx *tree.EvalContext, key string, datum tree.Datum,
+) error {
+	switch key {
+	case `ttl_expire_after`:
+		d, ok := datum.(*tree.DInterval)
+		if !ok {
+			s, err := DatumAsString(evalCtx, key, datum)
+			if err != nil {
+				return err
+			}
+			if d, err = tree.ParseDInterval(duration.IntervalStyle_POSTGRES, s); err != nil {
+				return pgerror.Wrapf(err, pgcode.InvalidParameterValue, "invalid value for %q", key)
+			}
+		}
+		if d.Duration.Compare(duration.Duration{}) <= 0 {
+			return pgerror.Newf(pgcode.InvalidParameterValue, "%q must be positive", key)
+		}
+		a.rowLevelTTL().ExpireAfter = d.Duration.String()
+	case `ttl_job_cron`:
+		s, err := DatumAsString(evalCtx, key, datum)
+		if err != nil {
+			return err
+		}
+		if _, err := cronexpr.Parse(s); err != nil {
+			return pgerror.Wrapf(err, pgcode.InvalidParameterValue, "invalid cron expression for %q", key)
+		}
+		a.rowLevelTTL().DeletionCron = s
+	case `ttl_select_batch_size`, `ttl_delete_batch_size`:
+		val, err := DatumAsInt(evalCtx, key, datum)
+		if err != nil {
+			return err
+		}
+		if val <= 0 {
+			return pgerror.Newf(pgcode.InvalidParameterValue, "%q must be positive", key)
+		}
+		if key == `ttl_select_batch_size` {
+			a.rowLevelTTL().SelectBatchSize = val
+		} else {
+			a.rowLevelTTL().DeleteBatchSize = val
+		}
+	case `ttl_delete_rate_limit`:
+		val, err := DatumAsInt(evalCtx, key, datum)
+		if err != nil {
+			return err
+		}
+		if val < 0 {
+			return pgerror.Newf(pgcode.InvalidParameterValue, "%q must be at least 0", key)
+		}
+		a.rowLevelTTL().DeleteRateLimit = val
+	case `ttl_pause`:
+		b, err := datumAsBool(evalCtx, key, datum)
+		if err != nil {
+			return err
+		}
+		a.rowLevelTTL().Pause = b
+	}
+	return nil
+}
+
 // RunPostChecks implements the StorageParamObserver interface.
 func (a *TableStorageParamObserver) RunPostChecks() error {
+	if ttl := a.TableDesc.RowLevelTTL; ttl != nil && ttl.ExpireAfter == "" {
+		return pgerror.New(pgcode.InvalidParameterValue,
+			`"ttl_expire_after" must be set to use row-level TTL`)
+	}
 	return nil
 }
 
+// Reset implements the StorageParamObserver interface.
+func (a *TableStorageParamObserver) Reset(evalCtx *tree.EvalContext, key string) error {
+	ttl := a.TableDesc.RowLevelTTL
+	switch key {
+	case `fillfactor`, `autovacuum_enabled`:
+		return nil
+	case `ttl_expire_after`:
+		// Resetting the expiration removes the row-level TTL altogether.
+		a.TableDesc.RowLevelTTL = nil
+		return nil
+	case `ttl_job_cron`:
+		if ttl != nil {
+			ttl.DeletionCron = ""
+		}
+		return nil
+	case `ttl_select_batch_size`:
+		if ttl != nil {
+			ttl.SelectBatchSize = 0
+		}
+		return nil
+	case `ttl_delete_batch_size`:
+		if ttl != nil {
+			ttl.DeleteBatchSize = 0
+		}
+		return nil
+	case `ttl_delete_rate_limit`:
+		if ttl != nil {
+			ttl.DeleteRateLimit = 0
+		}
+		return nil
+	case `ttl_pause`:
+		if ttl != nil {
+			ttl.Pause = false
+		}
+		return nil
+	}
+	return errors.Errorf("invalid storage parameter %q", key)
+}
+
 // Apply implements the StorageParamObserver interface.
 func (a *TableStorageParamObserver) Apply(
 	evalCtx *tree.EvalContext, key string, datum tree.Datum,
@@ -102,18 +245,9 @@ func (a *TableStorageParamObserver) Apply(
 	case `fillfactor`:
 		return applyFillFactorStorageParam(evalCtx, key, datum)
 	case `autovacuum_enabled`:
-		var boolVal bool
-		if stringVal, err := DatumAsString(evalCtx, key, datum); err == nil {
-			boolVal, err = ParseBoolVar(key, stringVal)
-			if err != nil {
-				return err
-			}
-		} else {
-			s, err := GetSingleBool(key, datum)
-			if err != nil {
-				return err
-			}
-			boolVal = bool(*s)
+		boolVal, err := datumAsBool(evalCtx, key, datum)
+		if err != nil {
+			return err
 		}
 		if !boolVal && evalCtx != nil {
 			evalCtx.ClientNoticeSender.BufferClientNotice(
@@ -122,6 +256,13 @@ func (a *TableStorageParamObserver) Apply(
 			)
 		}
 		return nil
+	case `ttl_expire_after`,
+		`ttl_job_cron`,
+		`ttl_select_batch_size`,
+		`ttl_delete_batch_size`,
+		`ttl_delete_rate_limit`,
+		`ttl_pause`:
+		return a.applyRowLevelTTLStorageParam(evalCtx, key, datum)
 	case `toast_tuple_target`,
 		`parallel_workers`,
 		`toast.autovacuum_enabled`,
@@ -250,6 +391,11 @@ func (a *IndexStorageParamObserver) Apply(
 	return errors.Errorf("invalid storage parameter %q", key)
 }
 
+// Reset implements the StorageParamObserver interface.
+func (a *IndexStorageParamObserver) Reset(evalCtx *tree.EvalContext, key string) error {
+	return errors.Errorf("resetting storage parameter %q is not supported for indexes", key)
+}
+
 // RunPostChecks implements the StorageParamObserver interface.
 func (a *IndexStorageParamObserver) RunPostChecks() error {
 	s2Config := getS2ConfigFromIndex(a.IndexDesc)
@@ -148,6 +148,34 @@ func TestContextualHelp(t *testing.T) {
 		{`CREATE TYPE blah AS ENUM ??`, `CREATE TYPE`},
 		{`DROP TYPE ??`, `DROP TYPE`},
 
+		{`CREATE TRIGGER ??`, `CREATE TRIGGER`},
+		{`CREATE TRIGGER foo AFTER ??`, `CREATE TRIGGER`},
+		{`DROP TRIGGER ??`, `DROP TRIGGER`},
+		{`DROP TRIGGER foo ON ??`, `DROP TRIGGER`},
+
+		{`CREATE POLICY ??`, `CREATE POLICY`},
+		{`CREATE POLICY foo ON t FOR ??`, `CREATE POLICY`},
+		{`DROP POLICY ??`, `DROP POLICY`},
+		{`DROP POLICY foo ON ??`, `DROP POLICY`},
+
+		{`CREATE FUNCTION ??`, `CREATE FUNCTION`},
+		{`CREATE OR REPLACE FUNCTION f(??`, `CREATE FUNCTION`},
+		{`DROP FUNCTION ??`, `DROP FUNCTION`},
+
+		{`CREATE PUBLICATION ??`, `CREATE PUBLICATION`},
+		{`CREATE PUBLICATION p FOR ??`, `CREATE PUBLICATION`},
+		{`DROP PUBLICATION ??`, `DROP PUBLICATION`},
+		{`CREATE SUBSCRIPTION ??`, `CREATE SUBSCRIPTION`},
+		{`CREATE SUBSCRIPTION s CONNECTION 'uri' ??`, `CREATE SUBSCRIPTION`},
+		{`DROP SUBSCRIPTION ??`, `DROP SUBSCRIPTION`},
+
+		{`CREATE SERVER ??`, `CREATE SERVER`},
+		{`CREATE SERVER s FOREIGN ??`, `CREATE SERVER`},
+		{`DROP SERVER ??`, `DROP SERVER`},
+		{`CREATE FOREIGN TABLE ??`, `CREATE FOREIGN TABLE`},
+		{`CREATE FOREIGN TABLE t (a INT) ??`, `CREATE FOREIGN TABLE`},
+		{`DROP FOREIGN TABLE ??`, `DROP FOREIGN TABLE`},
+
 		{`CREATE SCHEMA IF ??`, `CREATE SCHEMA`},
 		{`CREATE SCHEMA IF NOT ??`, `CREATE SCHEMA`},
 		{`CREATE SCHEMA bli ??`, `CREATE SCHEMA`},
@@ -160,6 +188,19 @@ func TestContextualHelp(t *testing.T) {
 		{`DISCARD ALL ??`, `DISCARD`},
 		{`DISCARD ??`, `DISCARD`},
 
+		{`CLOSE ??`, `CLOSE`},
+		{`DECLARE ??`, `DECLARE`},
+		{`DECLARE foo ??`, `DECLARE`},
+		{`FETCH ??`, `FETCH`},
+		{`FETCH 2 ??`, `FETCH`},
+		{`MOVE ??`, `MOVE`},
+		{`MOVE FORWARD ??`, `MOVE`},
+
+		{`LISTEN ??`, `LISTEN`},
+		{`NOTIFY ??`, `NOTIFY`},
+		{`NOTIFY foo, ??`, `NOTIFY`},
+		{`UNLISTEN ??`, `UNLISTEN`},
+
 		{`DROP ??`, `DROP`},
 
 		{`DROP DATABASE IF ??`, `DROP DATABASE`},
@@ -404,6 +445,8 @@ func TestContextualHelp(t *testing.T) {
 
 		{`SET TRANSACTION ??`, `SET TRANSACTION`},
 		{`SET TRANSACTION ISOLATION LEVEL SNAPSHOT ??`, `SET TRANSACTION`},
+		{`SET CONSTRAINTS ??`, `SET CONSTRAINTS`},
+		{`SET CONSTRAINTS ALL ??`, `SET CONSTRAINTS`},
 		{`SET TIME ??`, `SET SESSION`},
 		{`SET TIME ZONE 'UTC' ??`, `SET SESSION`},
 		{`SET blah TO ??`, `SET SESSION`},
@@ -564,6 +564,36 @@ func (u *sqlSymUnion) idxElems() tree.IndexElemList {
 func (u *sqlSymUnion) dropBehavior() tree.DropBehavior {
     return u.val.(tree.DropBehavior)
 }
+func (u *sqlSymUnion) triggerActionTime() tree.TriggerActionTime {
+    return u.val.(tree.TriggerActionTime)
+}
+func (u *sqlSymUnion) triggerEvent() tree.TriggerEvent {
+    return u.val.(tree.TriggerEvent)
+}
+func (u *sqlSymUnion) triggerEvents() []tree.TriggerEvent {
+    return u.val.([]tree.TriggerEvent)
+}
+func (u *sqlSymUnion) policyCommand() tree.PolicyCommand {
+    return u.val.(tree.PolicyCommand)
+}
+func (u *sqlSymUnion) funcParam() tree.FuncParam {
+    return u.val.(tree.FuncParam)
+}
+func (u *sqlSymUnion) funcParams() tree.FuncParams {
+    return u.val.(tree.FuncParams)
+}
+func (u *sqlSymUnion) functionOption() tree.FunctionOption {
+    return u.val.(tree.FunctionOption)
+}
+func (u *sqlSymUnion) functionOptions() tree.FunctionOptions {
+    return u.val.(tree.FunctionOptions)
+}
+func (u *sqlSymUnion) funcObj() tree.FuncObj {
+    return u.val.(tree.FuncObj)
+}
+func (u *sqlSymUnion) funcObjs() tree.FuncObjs {
+    return u.val.(tree.FuncObjs)
+}
 func (u *sqlSymUnion) validationBehavior() tree.ValidationBehavior {
     return u.val.(tree.ValidationBehavior)
 }
@@ -645,6 +675,9 @@ func (u *sqlSymUnion) transactionModes() tree.TransactionModes {
 func (u *sqlSymUnion) compositeKeyMatchMethod() tree.CompositeKeyMatchMethod {
   return u.val.(tree.CompositeKeyMatchMethod)
 }
+func (u *sqlSymUnion) constraintDeferrability() tree.ConstraintDeferrability {
+  return u.val.(tree.ConstraintDeferrability)
+}
 func (u *sqlSymUnion) referenceAction() tree.ReferenceAction {
     return u.val.(tree.ReferenceAction)
 }
@@ -731,6 +764,15 @@ func (u *sqlSymUnion) alterDefaultPrivilegesTargetObject() tree.AlterDefaultPriv
 func (u *sqlSymUnion) setVar() *tree.SetVar {
     return u.val.(*tree.SetVar)
 }
+func (u *sqlSymUnion) cursorSensitivity() tree.CursorSensitivity {
+    return u.val.(tree.CursorSensitivity)
+}
+func (u *sqlSymUnion) cursorScrollOption() tree.CursorScrollOption {
+    return u.val.(tree.CursorScrollOption)
+}
+func (u *sqlSymUnion) cursorStmt() tree.CursorStmt {
+    return u.val.(tree.CursorStmt)
+}
 %}
 
 // NB: the %token definitions must come before the %type definitions in this
@@ -750,15 +792,15 @@ func (u *sqlSymUnion) setVar() *tree.SetVar {
 // below; search this file for "Keyword category lists".
 
 // Ordinary key words in alphabetical order.
-%token <str> ABORT ACCESS ACTION ADD ADMIN AFTER AGGREGATE
+%token <str> ABORT ABSOLUTE ACCESS ACTION ADD ADMIN AFTER AGGREGATE
 %token <str> ALL ALTER ALWAYS ANALYSE ANALYZE AND AND_AND ANY ANNOTATE_TYPE ARRAY AS ASC
-%token <str> ASYMMETRIC AT ATTRIBUTE AUTHORIZATION AUTOMATIC AVAILABILITY
+%token <str> ASENSITIVE ASYMMETRIC AT ATTRIBUTE AUTHORIZATION AUTOMATIC AVAILABILITY
 
-%token <str> BACKUP BACKUPS BEFORE BEGIN BETWEEN BIGINT BIGSERIAL BINARY BIT
+%token <str> BACKUP BACKUPS BACKWARD BEFORE BEGIN BETWEEN BIGINT BIGSERIAL BINARY BIT
 %token <str> BUCKET_COUNT
 %token <str> BOOLEAN BOTH BOX2D BUNDLE BY
 
-%token <str> CACHE CANCEL CANCELQUERY CASCADE CASE CAST CBRT CHANGEFEED CHAR
+%token <str> CACHE CALLED CANCEL CANCELQUERY CASCADE CASE CAST CBRT CHANGEFEED CHAR
 %token <str> CHARACTER CHARACTERISTICS CHECK CLOSE
 %token <str> CLUSTER COALESCE COLLATE COLLATION COLUMN COLUMNS COMMENT COMMENTS COMMIT
 %token <str> COMMITTED COMPACT COMPLETE CONCAT CONCURRENTLY CONFIGURATION CONFIGURATIONS CONFIGURE
@@ -770,9 +812,9 @@ func (u *sqlSymUnion) setVar() *tree.SetVar {
 
 %token <str> DATA DATABASE DATABASES DATE DAY DEBUG_PAUSE_ON DEC DECIMAL DEFAULT DEFAULTS
 %token <str> DEALLOCATE DECLARE DEFERRABLE DEFERRED DELETE DELIMITER DESC DESTINATION DETACHED
-%token <str> DISCARD DISTINCT DO DOMAIN DOUBLE DROP
+%token <str> DISABLE DISCARD DISTINCT DO DOMAIN DOUBLE DROP
 
-%token <str> ELSE ENCODING ENCRYPTED ENCRYPTION_PASSPHRASE END ENUM ENUMS ESCAPE EXCEPT EXCLUDE EXCLUDING
+%token <str> EACH ELSE ENABLE ENCODING ENCRYPTED ENCRYPTION_PASSPHRASE END ENUM ENUMS ESCAPE EXCEPT EXCLUDE EXCLUDING
 %token <str> EXISTS EXECUTE EXECUTION EXPERIMENTAL
 %token <str> EXPERIMENTAL_FINGERPRINTS EXPERIMENTAL_REPLICA
 %token <str> EXPERIMENTAL_AUDIT
@@ -780,19 +822,19 @@ func (u *sqlSymUnion) setVar() *tree.SetVar {
 
 %token <str> FAILURE FALSE FAMILY FETCH FETCHVAL FETCHTEXT FETCHVAL_PATH FETCHTEXT_PATH
 %token <str> FILES FILTER
-%token <str> FIRST FLOAT FLOAT4 FLOAT8 FLOORDIV FOLLOWING FOR FORCE FORCE_INDEX FOREIGN FREEZE FROM FULL FUNCTION FUNCTIONS
+%token <str> FIRST FLOAT FLOAT4 FLOAT8 FLOORDIV FOLLOWING FOR FORCE FORCE_INDEX FOREIGN FORWARD FREEZE FROM FULL FUNCTION FUNCTIONS
 
 %token <str> GENERATED GEOGRAPHY GEOMETRY GEOMETRYM GEOMETRYZ GEOMETRYZM
 %token <str> GEOMETRYCOLLECTION GEOMETRYCOLLECTIONM GEOMETRYCOLLECTIONZ GEOMETRYCOLLECTIONZM
 %token <str> GLOBAL GOAL GRANT GRANTS GREATEST GROUP GROUPING GROUPS
 
-%token <str> HAVING HASH HEADER HIGH HISTOGRAM HOUR
+%token <str> HAVING HASH HEADER HIGH HISTOGRAM HOLD HOUR
 
 %token <str> IDENTITY
-%token <str> IF IFERROR IFNULL IGNORE_FOREIGN_KEYS ILIKE IMMEDIATE IMPORT IN INCLUDE INCLUDE_DEPRECATED_INTERLEAVES INCLUDING INCREMENT INCREMENTAL
+%token <str> IF IFERROR IFNULL IGNORE_FOREIGN_KEYS ILIKE IMMEDIATE IMMUTABLE IMPORT IN INCLUDE INCLUDE_DEPRECATED_INTERLEAVES INCLUDING INCREMENT INCREMENTAL
 %token <str> INET INET_CONTAINED_BY_OR_EQUALS
-%token <str> INET_CONTAINS_OR_EQUALS INDEX INDEXES INHERITS INJECT INTERLEAVE INITIALLY
-%token <str> INNER INSERT INT INTEGER
+%token <str> INET_CONTAINS_OR_EQUALS INDEX INDEXES INHERITS INJECT INPUT INTERLEAVE INITIALLY
+%token <str> INNER INSENSITIVE INSERT INT INTEGER
 %token <str> INTERSECT INTERVAL INTO INTO_DB INVERTED IS ISERROR ISNULL ISOLATION
 
 %token <str> JOB JOBS JOIN JSON JSONB JSON_SOME_EXISTS JSON_ALL_EXISTS
@@ -800,43 +842,44 @@ func (u *sqlSymUnion) setVar() *tree.SetVar {
 %token <str> KEY KEYS KMS KV
 
 %token <str> LANGUAGE LAST LATERAL LATEST LC_CTYPE LC_COLLATE
-%token <str> LEADING LEASE LEAST LEFT LESS LEVEL LIKE LIMIT
+%token <str> LEADING LEAKPROOF LEASE LEAST LEFT LESS LEVEL LIKE LIMIT
 %token <str> LINESTRING LINESTRINGM LINESTRINGZ LINESTRINGZM
-%token <str> LIST LOCAL LOCALITY LOCALTIME LOCALTIMESTAMP LOCKED LOGIN LOOKUP LOW LSHIFT
+%token <str> LIST LISTEN LOCAL LOCALITY LOCALTIME LOCALTIMESTAMP LOCKED LOGIN LOOKUP LOW LSHIFT
 
-%token <str> MATCH MATERIALIZED MERGE MINVALUE MAXVALUE METHOD MINUTE MODIFYCLUSTERSETTING MONTH
+%token <str> MATCH MATERIALIZED MERGE MINVALUE MAXVALUE METHOD MINUTE MODIFYCLUSTERSETTING MONTH MOVE
 %token <str> MULTILINESTRING MULTILINESTRINGM MULTILINESTRINGZ MULTILINESTRINGZM
 %token <str> MULTIPOINT MULTIPOINTM MULTIPOINTZ MULTIPOINTZM
 %token <str> MULTIPOLYGON MULTIPOLYGONM MULTIPOLYGONZ MULTIPOLYGONZM
 
 %token <str> NAN NAME NAMES NATURAL NEVER NEXT NO NOCANCELQUERY NOCONTROLCHANGEFEED NOCONTROLJOB
 %token <str> NOCREATEDB NOCREATELOGIN NOCREATEROLE NOLOGIN NOMODIFYCLUSTERSETTING NO_INDEX_JOIN NO_ZIGZAG_JOIN
-%token <str> NOSQLLOGIN NO_FULL_SCAN NONE NON_VOTERS NORMAL NOT NOTHING NOTNULL NOVIEWACTIVITY NOVIEWACTIVITYREDACTED NOWAIT NULL
+%token <str> NOSQLLOGIN NO_FULL_SCAN NONE NON_VOTERS NORMAL NOT NOTHING NOTIFY NOTNULL NOVIEWACTIVITY NOVIEWACTIVITYREDACTED NOWAIT NULL
 %token <str> NULLIF NULLS NUMERIC
 
 %token <str> OF OFF OFFSET OID OIDS OIDVECTOR ON ONLY OPT OPTION OPTIONS OR
 %token <str> ORDER ORDINALITY OTHERS OUT OUTER OVER OVERLAPS OVERLAY OWNED OWNER OPERATOR
 
-%token <str> PARENT PARTIAL PARTITION PARTITIONS PASSWORD PAUSE PAUSED PHYSICAL PLACEMENT PLACING
+%token <str> PARENT PARTIAL PARTITION PARTITIONS PASSWORD PAUSE PAUSED PERMISSIVE PHYSICAL PLACEMENT PLACING
 %token <str> PLAN PLANS POINT POINTM POINTZ POINTZM POLYGON POLYGONM POLYGONZ POLYGONZM
-%token <str> POSITION PRECEDING PRECISION PREPARE PRESERVE PRIMARY PRIORITY PRIVILEGES
+%token <str> POLICY POSITION PRECEDING PRECISION PREPARE PRESERVE PRIMARY PRIOR PRIORITY PRIVILEGES
 %token <str> PROCEDURAL PUBLIC PUBLICATION
 
 %token <str> QUERIES QUERY QUOTE
 
 %token <str> RANGE RANGES READ REAL REASON REASSIGN RECURSIVE RECURRING REF REFERENCES REFRESH
+%token <str> RELATIVE
 %token <str> REGCLASS REGION REGIONAL REGIONS REGNAMESPACE REGPROC REGPROCEDURE REGROLE REGTYPE REINDEX
 %token <str> REMOVE_PATH RENAME REPEATABLE REPLACE REPLICATION
-%token <str> RELEASE RESET RESTORE RESTRICT RESTRICTED RESUME RETURNING RETRY REVISION_HISTORY
+%token <str> RELEASE RESET RESTORE RESTRICT RESTRICTED RESTRICTIVE RESUME RETURNING RETURNS RETRY REVISION_HISTORY
 %token <str> REVOKE RIGHT ROLE ROLES ROLLBACK ROLLUP ROUTINES ROW ROWS RSHIFT RULE RUNNING
 
-%token <str> SAVEPOINT SCANS SCATTER SCHEDULE SCHEDULES SCHEMA SCHEMAS SCRUB SEARCH SECOND SELECT SEQUENCE SEQUENCES
+%token <str> SAVEPOINT SCANS SCATTER SCHEDULE SCHEDULES SCHEMA SCHEMAS SCROLL SCRUB SEARCH SECOND SECURITY SELECT SEQUENCE SEQUENCES
 %token <str> SERIALIZABLE SERVER SESSION SESSIONS SESSION_USER SET SETS SETTING SETTINGS
 %token <str> SHARE SHOW SIMILAR SIMPLE SKIP SKIP_LOCALITIES_CHECK SKIP_MISSING_FOREIGN_KEYS
 %token <str> SKIP_MISSING_SEQUENCES SKIP_MISSING_SEQUENCE_OWNERS SKIP_MISSING_VIEWS SMALLINT SMALLSERIAL SNAPSHOT SOME SPLIT SQL
 %token <str> SQLLOGIN
 
-%token <str> START STATISTICS STATUS STDIN STREAM STRICT STRING STORAGE STORE STORED STORING SUBSTRING
+%token <str> STABLE START STATISTICS STATUS STDIN STDOUT STREAM STRICT STRING STORAGE STORE STORED STORING SUBSTRING
 %token <str> SURVIVE SURVIVAL SYMMETRIC SYNTAX SYSTEM SQRT SUBSCRIPTION STATEMENTS
 
 %token <str> TABLE TABLES TABLESPACE TEMP TEMPLATE TEMPORARY TENANT TESTING_RELOCATE EXPERIMENTAL_RELOCATE TEXT THEN
@@ -845,12 +888,12 @@ func (u *sqlSymUnion) setVar() *tree.SetVar {
 %token <str> TRUNCATE TRUSTED TYPE TYPES
 %token <str> TRACING
 
-%token <str> UNBOUNDED UNCOMMITTED UNION UNIQUE UNKNOWN UNLOGGED UNSPLIT
+%token <str> UNBOUNDED UNCOMMITTED UNION UNIQUE UNKNOWN UNLISTEN UNLOGGED UNSPLIT
 %token <str> UPDATE UPSERT UNTIL USE USER USERS USING UUID
 
-%token <str> VALID VALIDATE VALUE VALUES VARBIT VARCHAR VARIADIC VIEW VARYING VIEWACTIVITY VIEWACTIVITYREDACTED VIRTUAL VISIBLE VOTERS
+%token <str> VALID VALIDATE VALUE VALUES VARBIT VARCHAR VARIADIC VIEW VARYING VIEWACTIVITY VIEWACTIVITYREDACTED VIRTUAL VISIBLE VOLATILE VOTERS
 
-%token <str> WHEN WHERE WINDOW WITH WITHIN WITHOUT WORK WRITE
+%token <str> WHEN WHERE WINDOW WITH WITHIN WITHOUT WORK WRAPPER WRITE
 
 %token <str> YEAR
 
@@ -972,6 +1015,7 @@ func (u *sqlSymUnion) setVar() *tree.SetVar {
 %type <tree.Statement> comment_stmt
 %type <tree.Statement> commit_stmt
 %type <tree.Statement> copy_from_stmt
+%type <tree.Statement> copy_to_stmt
 
 %type <tree.Statement> create_stmt
 %type <tree.Statement> create_changefeed_stmt create_replication_stream_stmt
@@ -993,6 +1037,20 @@ func (u *sqlSymUnion) setVar() *tree.SetVar {
 %type <*tree.CreateStatsOptions> create_stats_option
 
 %type <tree.Statement> create_type_stmt
+%type <tree.Statement> create_func_stmt
+%type <tree.FuncParams> opt_func_params func_params
+%type <tree.FuncParam> func_param
+%type <tree.FunctionOptions> opt_create_func_opt_list create_func_opt_list
+%type <tree.FunctionOption> create_func_opt_item
+%type <tree.FuncObjs> func_obj_list
+%type <tree.FuncObj> func_obj
+%type <tree.Statement> create_trigger_stmt
+%type <tree.Statement> create_policy_stmt
+%type <tree.Statement> create_publication_stmt
+%type <tree.Statement> create_server_stmt
+%type <tree.Statement> create_foreign_table_stmt
+%type <tree.Statement> create_subscription_stmt
+%type <tree.Statement> trigger_action_stmt
 %type <tree.Statement> delete_stmt
 %type <tree.Statement> discard_stmt
 
@@ -1004,6 +1062,13 @@ func (u *sqlSymUnion) setVar() *tree.SetVar {
 %type <tree.Statement> drop_schema_stmt
 %type <tree.Statement> drop_table_stmt
 %type <tree.Statement> drop_type_stmt
+%type <tree.Statement> drop_func_stmt
+%type <tree.Statement> drop_trigger_stmt
+%type <tree.Statement> drop_policy_stmt
+%type <tree.Statement> drop_publication_stmt
+%type <tree.Statement> drop_server_stmt
+%type <tree.Statement> drop_foreign_table_stmt
+%type <tree.Statement> drop_subscription_stmt
 %type <tree.Statement> drop_view_stmt
 %type <tree.Statement> drop_sequence_stmt
 
@@ -1042,6 +1107,8 @@ func (u *sqlSymUnion) setVar() *tree.SetVar {
 %type <tree.Statement> set_session_stmt
 %type <tree.Statement> set_csetting_stmt
 %type <tree.Statement> set_transaction_stmt
+%type <tree.Statement> set_constraints_stmt
+%type <bool> constraints_set_mode
 %type <tree.Statement> set_exprs_internal
 %type <tree.Statement> generic_set
 %type <tree.Statement> set_rest_more
@@ -1102,14 +1169,32 @@ func (u *sqlSymUnion) setVar() *tree.SetVar {
 
 %type <tree.Statement> close_cursor_stmt
 %type <tree.Statement> declare_cursor_stmt
+%type <tree.Statement> fetch_cursor_stmt
+%type <tree.Statement> move_cursor_stmt
+%type <tree.CursorStmt> cursor_movement_specifier
+%type <bool> opt_hold opt_binary
+%type <tree.CursorSensitivity> opt_sensitivity
+%type <tree.CursorScrollOption> opt_scroll
+%type <int64> opt_forward_backward forward_backward
+%type <int64> next_prior
 %type <tree.Statement> reindex_stmt
 
+%type <tree.Statement> listen_stmt
+%type <tree.Statement> notify_stmt
+%type <tree.Statement> unlisten_stmt
+
 %type <[]string> opt_incremental
 %type <tree.KVOption> kv_option
 %type <[]tree.KVOption> kv_option_list opt_with_options var_set_list opt_with_schedule_options
+%type <[]tree.KVOption> opt_partition_interval_options
+%type <[]tree.KVOption> opt_with_subscription_options
+%type <tree.KVOption> foreign_option
+%type <[]tree.KVOption> opt_foreign_options foreign_option_list
 %type <*tree.BackupOptions> opt_with_backup_options backup_options backup_options_list
 %type <*tree.RestoreOptions> opt_with_restore_options restore_options restore_options_list
 %type <*tree.CopyOptions> opt_with_copy_options copy_options copy_options_list
+%type <*tree.CopyOptions> copy_generic_option copy_generic_option_list
+%type <bool> copy_generic_boolean
 %type <str> import_format
 %type <tree.StorageParam> storage_parameter
 %type <[]tree.StorageParam> storage_parameter_list opt_table_with opt_with_storage_parameter_list
@@ -1134,6 +1219,14 @@ func (u *sqlSymUnion) setVar() *tree.SetVar {
 %type <tree.AlterIndexCmds> alter_index_cmds
 
 %type <tree.DropBehavior> opt_drop_behavior
+%type <tree.TriggerActionTime> trigger_action_time
+%type <tree.TriggerEvent> trigger_event
+%type <[]tree.TriggerEvent> trigger_event_list
+%type <tree.Expr> opt_trigger_when
+%type <bool> opt_policy_restrictive
+%type <tree.PolicyCommand> opt_policy_command
+%type <tree.NameList> opt_policy_roles
+%type <tree.Expr> opt_policy_using opt_policy_with_check
 %type <tree.DropBehavior> opt_interleave_drop_behavior
 
 %type <tree.ValidationBehavior> opt_validate_behavior
@@ -1170,6 +1263,7 @@ func (u *sqlSymUnion) setVar() *tree.SetVar {
 %type <*tree.UnresolvedName> table_pattern complex_table_pattern
 %type <*tree.UnresolvedName> column_path prefixed_column_path column_path_with_star
 %type <tree.TableExpr> insert_target create_stats_target analyze_target
+%type <tree.TableExpr> changefeed_target_expr
 
 %type <*tree.TableIndexName> table_index_name
 %type <tree.TableIndexNames> table_index_name_list
@@ -1192,7 +1286,7 @@ func (u *sqlSymUnion) setVar() *tree.SetVar {
 %type <tree.ListPartition> list_partition
 %type <[]tree.ListPartition> list_partitions
 %type <tree.RangePartition> range_partition
-%type <[]tree.RangePartition> range_partitions
+%type <[]tree.RangePartition> range_partitions opt_range_partitions
 %type <empty> opt_all_clause
 %type <empty> opt_privileges_clause
 %type <bool> distinct_clause
@@ -1348,6 +1442,7 @@ func (u *sqlSymUnion) setVar() *tree.SetVar {
 %type <tree.NamedColumnQualification> col_qualification create_as_col_qualification
 %type <tree.ColumnQualification> col_qualification_elem create_as_col_qualification_elem
 %type <tree.CompositeKeyMatchMethod> key_match
+%type <tree.ConstraintDeferrability> opt_deferrable
 %type <tree.ReferenceActions> reference_actions
 %type <tree.ReferenceAction> reference_action reference_on_delete reference_on_update
 
@@ -1483,6 +1578,7 @@ stmt:
 | preparable_stmt           // help texts in sub-rule
 | analyze_stmt              // EXTEND WITH HELP: ANALYZE
 | copy_from_stmt
+| copy_to_stmt
 | comment_stmt
 | execute_stmt              // EXTEND WITH HELP: EXECUTE
 | deallocate_stmt           // EXTEND WITH HELP: DEALLOCATE
@@ -1497,8 +1593,13 @@ stmt:
 | refresh_stmt              // EXTEND WITH HELP: REFRESH
 | nonpreparable_set_stmt    // help texts in sub-rule
 | transaction_stmt          // help texts in sub-rule
-| close_cursor_stmt
-| declare_cursor_stmt
+| close_cursor_stmt         // EXTEND WITH HELP: CLOSE
+| declare_cursor_stmt       // EXTEND WITH HELP: DECLARE
+| fetch_cursor_stmt         // EXTEND WITH HELP: FETCH
+| move_cursor_stmt          // EXTEND WITH HELP: MOVE
+| listen_stmt               // EXTEND WITH HELP: LISTEN
+| notify_stmt               // EXTEND WITH HELP: NOTIFY
+| unlisten_stmt             // EXTEND WITH HELP: UNLISTEN
 | reindex_stmt
 | /* EMPTY */
   {
@@ -1555,6 +1656,9 @@ alter_ddl_stmt:
 //   ALTER TABLE ... CONFIGURE ZONE <zoneconfig>
 //   ALTER TABLE ... SET SCHEMA <newschemaname>
 //   ALTER TABLE ... SET LOCALITY [REGIONAL BY [TABLE IN <region> | ROW] | GLOBAL]
+//   ALTER TABLE ... SET ( <storage_parameter> = <value> [, ...] )
+//   ALTER TABLE ... RESET ( <storage_parameter> [, ...] )
+//   ALTER TABLE ... { ENABLE | DISABLE | FORCE | NO FORCE } ROW LEVEL SECURITY
 //
 // Column qualifiers:
 //   [CONSTRAINT <constraintname>] {NULL | NOT NULL | UNIQUE | PRIMARY KEY | CHECK (<expr>) | DEFAULT <expr>}
@@ -2272,6 +2376,40 @@ alter_table_cmd:
   {
     $$.val = &tree.AlterTableSetAudit{Mode: $3.auditMode()}
   }
+  // ALTER TABLE <name> SET (<storage_parameter> = <value>, ...)
+| SET '(' storage_parameter_list ')'
+  {
+    $$.val = &tree.AlterTableSetStorageParams{
+      StorageParams: $3.storageParams(),
+    }
+  }
+  // ALTER TABLE <name> RESET (<storage_parameter>, ...)
+| RESET '(' name_list ')'
+  {
+    $$.val = &tree.AlterTableResetStorageParams{
+      Params: $3.nameList(),
+    }
+  }
+  // ALTER TABLE <name> ENABLE ROW LEVEL SECURITY
+| ENABLE ROW LEVEL SECURITY
+  {
+    $$.val = &tree.AlterTableRowLevelSecurity{Mode: tree.RowLevelSecurityEnable}
+  }
+  // ALTER TABLE <name> DISABLE ROW LEVEL SECURITY
+| DISABLE ROW LEVEL SECURITY
+  {
+    $$.val = &tree.AlterTableRowLevelSecurity{Mode: tree.RowLevelSecurityDisable}
+  }
+  // ALTER TABLE <name> FORCE ROW LEVEL SECURITY
+| FORCE ROW LEVEL SECURITY
+  {
+    $$.val = &tree.AlterTableRowLevelSecurity{Mode: tree.RowLevelSecurityForce}
+  }
+  // ALTER TABLE <name> NO FORCE ROW LEVEL SECURITY
+| NO FORCE ROW LEVEL SECURITY
+  {
+    $$.val = &tree.AlterTableRowLevelSecurity{Mode: tree.RowLevelSecurityNoForce}
+  }
   // ALTER TABLE <name> PARTITION BY ...
 | partition_by_table
   {
@@ -3065,6 +3203,8 @@ alter_unsupported_stmt:
 //    CSV
 //    DELIMITED
 //    MYSQLDUMP
+//    ORC
+//    PARQUET
 //    PGCOPY
 //    PGDUMP
 //
@@ -3127,9 +3267,14 @@ import_stmt:
 //
 // Formats:
 //    CSV
+//    PARQUET
 //
 // Options:
 //    delimiter = '...'   [CSV-specific]
+//    nullas = '...'      [CSV-specific]
+//    compression = '...' [gzip, or snappy for PARQUET]
+//    chunk_rows = '...'
+//    chunk_size = '...'
 //
 // %SeeAlso: SELECT
 export_stmt:
@@ -3217,7 +3362,7 @@ opt_with_options:
 // 1) The "really old" syntax from v7.2 and prior
 // 2) Pre 9.0 using hard-wired, space-separated options
 // 3) The current and preferred options using comma-separated generic identifiers instead of keywords.
-// We currently support only the #2 format.
+// We currently support the #2 and #3 formats.
 // See the comment for CopyStmt in https://github.com/postgres/postgres/blob/master/src/backend/parser/gram.y.
 copy_from_stmt:
   COPY table_name opt_column_list FROM STDIN opt_with_copy_options opt_where_clause
@@ -3239,11 +3384,55 @@ copy_from_stmt:
     return unimplemented(sqllex, "copy from unsupported format")
   }
 
+copy_to_stmt:
+  COPY table_name opt_column_list TO STDOUT opt_with_copy_options
+  {
+    /* FORCE DOC */
+    $$.val = &tree.CopyTo{
+       Table: $2.unresolvedObjectName().ToTableName(),
+       Columns: $3.nameList(),
+       Options: *$6.copyOptions(),
+    }
+  }
+| COPY '(' select_stmt ')' TO STDOUT opt_with_copy_options
+  {
+    /* FORCE DOC */
+    $$.val = &tree.CopyTo{Statement: $3.slct(), Options: *$7.copyOptions()}
+  }
+| COPY '(' insert_stmt ')' TO STDOUT opt_with_copy_options
+  {
+    /* FORCE DOC */
+    $$.val = &tree.CopyTo{Statement: $3.stmt(), Options: *$7.copyOptions()}
+  }
+| COPY '(' upsert_stmt ')' TO STDOUT opt_with_copy_options
+  {
+    /* FORCE DOC */
+    $$.val = &tree.CopyTo{Statement: $3.stmt(), Options: *$7.copyOptions()}
+  }
+| COPY '(' update_stmt ')' TO STDOUT opt_with_copy_options
+  {
+    /* FORCE DOC */
+    $$.val = &tree.CopyTo{Statement: $3.stmt(), Options: *$7.copyOptions()}
+  }
+| COPY '(' delete_stmt ')' TO STDOUT opt_with_copy_options
+  {
+    /* FORCE DOC */
+    $$.val = &tree.CopyTo{Statement: $3.stmt(), Options: *$7.copyOptions()}
+  }
+| COPY table_name opt_column_list TO error
+  {
+    return unimplemented(sqllex, "copy to unsupported destination")
+  }
+
 opt_with_copy_options:
   opt_with copy_options_list
   {
     $$.val = $2.copyOptions()
   }
+| opt_with '(' copy_generic_option_list ')'
+  {
+    $$.val = $3.copyOptions()
+  }
 | /* EMPTY */
   {
     $$.val = &tree.CopyOptions{}
@@ -3286,37 +3475,152 @@ copy_options:
   {
     return unimplementedWithIssueDetail(sqllex, 41608, "oids")
   }
-| FREEZE error
+| FREEZE
   {
-    return unimplementedWithIssueDetail(sqllex, 41608, "freeze")
+    $$.val = &tree.CopyOptions{Freeze: true}
   }
-| HEADER error
+| HEADER
   {
-    return unimplementedWithIssueDetail(sqllex, 41608, "header")
+    $$.val = &tree.CopyOptions{Header: true}
   }
 | QUOTE SCONST
   {
-    return unimplementedWithIssueDetail(sqllex, 41608, "quote")
+    $$.val = &tree.CopyOptions{Quote: tree.NewStrVal($2)}
   }
-| ESCAPE SCONST error
+| ESCAPE SCONST
   {
     $$.val = &tree.CopyOptions{Escape: tree.NewStrVal($2)}
   }
-| FORCE QUOTE error
+| FORCE QUOTE '*'
   {
-    return unimplementedWithIssueDetail(sqllex, 41608, "force quote")
+    $$.val = &tree.CopyOptions{ForceQuoteAll: true}
   }
-| FORCE NOT NULL error
+| FORCE QUOTE name_list
   {
-    return unimplementedWithIssueDetail(sqllex, 41608, "force not null")
+    $$.val = &tree.CopyOptions{ForceQuote: $3.nameList()}
   }
-| FORCE NULL error
+| FORCE NOT NULL name_list
   {
-    return unimplementedWithIssueDetail(sqllex, 41608, "force null")
+    $$.val = &tree.CopyOptions{ForceNotNull: $4.nameList()}
   }
-| ENCODING SCONST error
+| FORCE NULL name_list
   {
-    return unimplementedWithIssueDetail(sqllex, 41608, "encoding")
+    $$.val = &tree.CopyOptions{ForceNull: $3.nameList()}
+  }
+| ENCODING SCONST
+  {
+    $$.val = &tree.CopyOptions{Encoding: tree.NewStrVal($2)}
+  }
+
+copy_generic_option_list:
+  copy_generic_option
+  {
+    $$.val = $1.copyOptions()
+  }
+| copy_generic_option_list ',' copy_generic_option
+  {
+    if err := $1.copyOptions().CombineWith($3.copyOptions()); err != nil {
+      return setErr(sqllex, err)
+    }
+  }
+
+// copy_generic_option is an option of the parenthesized, comma-separated
+// syntax, e.g. COPY t TO STDOUT WITH (FORMAT csv, HEADER true). Options whose
+// name is not a keyword are matched as identifiers.
+copy_generic_option:
+  DELIMITER string_or_placeholder
+  {
+    $$.val = &tree.CopyOptions{Delimiter: $2.expr()}
+  }
+| NULL string_or_placeholder
+  {
+    $$.val = &tree.CopyOptions{Null: $2.expr()}
+  }
+| HEADER
+  {
+    $$.val = &tree.CopyOptions{Header: true}
+  }
+| HEADER copy_generic_boolean
+  {
+    $$.val = &tree.CopyOptions{Header: $2.bool()}
+  }
+| FREEZE
+  {
+    $$.val = &tree.CopyOptions{Freeze: true}
+  }
+| FREEZE copy_generic_boolean
+  {
+    $$.val = &tree.CopyOptions{Freeze: $2.bool()}
+  }
+| QUOTE SCONST
+  {
+    $$.val = &tree.CopyOptions{Quote: tree.NewStrVal($2)}
+  }
+| ESCAPE SCONST
+  {
+    $$.val = &tree.CopyOptions{Escape: tree.NewStrVal($2)}
+  }
+| ENCODING SCONST
+  {
+    $$.val = &tree.CopyOptions{Encoding: tree.NewStrVal($2)}
+  }
+| IDENT non_reserved_word_or_sconst
+  {
+    if $1 != "format" {
+      sqllex.Error(fmt.Sprintf("option %q not recognized", $1))
+      return 1
+    }
+    switch strings.ToLower($2) {
+    case "text":
+      $$.val = &tree.CopyOptions{}
+    case "binary":
+      $$.val = &tree.CopyOptions{CopyFormat: tree.CopyFormatBinary}
+    case "csv":
+      $$.val = &tree.CopyOptions{CopyFormat: tree.CopyFormatCSV}
+    default:
+      sqllex.Error(fmt.Sprintf("COPY format %q not recognized", $2))
+      return 1
+    }
+  }
+| IDENT '*'
+  {
+    if $1 != "force_quote" {
+      sqllex.Error(fmt.Sprintf("option %q not recognized", $1))
+      return 1
+    }
+    $$.val = &tree.CopyOptions{ForceQuoteAll: true}
+  }
+| IDENT '(' name_list ')'
+  {
+    switch $1 {
+    case "force_quote":
+      $$.val = &tree.CopyOptions{ForceQuote: $3.nameList()}
+    case "force_not_null":
+      $$.val = &tree.CopyOptions{ForceNotNull: $3.nameList()}
+    case "force_null":
+      $$.val = &tree.CopyOptions{ForceNull: $3.nameList()}
+    default:
+      sqllex.Error(fmt.Sprintf("option %q not recognized", $1))
+      return 1
+    }
+  }
+
+copy_generic_boolean:
+  TRUE
+  {
+    $$.val = true
+  }
+| FALSE
+  {
+    $$.val = false
+  }
+| ON
+  {
+    $$.val = true
+  }
+| OFF
+  {
+    $$.val = false
   }
 
 // %Help: CANCEL
@@ -3476,7 +3780,9 @@ comment_text:
 // %Text:
 // CREATE DATABASE, CREATE TABLE, CREATE INDEX, CREATE TABLE AS,
 // CREATE USER, CREATE VIEW, CREATE SEQUENCE, CREATE STATISTICS,
-// CREATE ROLE, CREATE TYPE, CREATE EXTENSION
+// CREATE ROLE, CREATE TYPE, CREATE EXTENSION, CREATE TRIGGER, CREATE POLICY,
+// CREATE PUBLICATION, CREATE SUBSCRIPTION, CREATE SERVER,
+// CREATE FOREIGN TABLE
 create_stmt:
   create_role_stmt     // EXTEND WITH HELP: CREATE ROLE
 | create_ddl_stmt      // help texts in sub-rule
@@ -3484,6 +3790,10 @@ create_stmt:
 | create_schedule_for_backup_stmt   // EXTEND WITH HELP: CREATE SCHEDULE FOR BACKUP
 | create_changefeed_stmt
 | create_replication_stream_stmt
+| create_publication_stmt   // EXTEND WITH HELP: CREATE PUBLICATION
+| create_subscription_stmt  // EXTEND WITH HELP: CREATE SUBSCRIPTION
+| create_server_stmt        // EXTEND WITH HELP: CREATE SERVER
+| create_foreign_table_stmt // EXTEND WITH HELP: CREATE FOREIGN TABLE
 | create_extension_stmt  // EXTEND WITH HELP: CREATE EXTENSION
 | create_unsupported   {}
 | CREATE error         // SHOW HELP: CREATE
@@ -3509,19 +3819,12 @@ create_unsupported:
 | CREATE CONSTRAINT TRIGGER error { return unimplementedWithIssueDetail(sqllex, 28296, "create constraint") }
 | CREATE CONVERSION error { return unimplemented(sqllex, "create conversion") }
 | CREATE DEFAULT CONVERSION error { return unimplemented(sqllex, "create def conv") }
-| CREATE FOREIGN TABLE error { return unimplemented(sqllex, "create foreign table") }
 | CREATE FOREIGN DATA error { return unimplemented(sqllex, "create fdw") }
-| CREATE FUNCTION error { return unimplementedWithIssueDetail(sqllex, 17511, "create function") }
-| CREATE OR REPLACE FUNCTION error { return unimplementedWithIssueDetail(sqllex, 17511, "create function") }
 | CREATE opt_or_replace opt_trusted opt_procedural LANGUAGE name error { return unimplementedWithIssueDetail(sqllex, 17511, "create language " + $6) }
 | CREATE OPERATOR error { return unimplementedWithIssue(sqllex, 65017) }
-| CREATE PUBLICATION error { return unimplemented(sqllex, "create publication") }
 | CREATE opt_or_replace RULE error { return unimplemented(sqllex, "create rule") }
-| CREATE SERVER error { return unimplemented(sqllex, "create server") }
-| CREATE SUBSCRIPTION error { return unimplemented(sqllex, "create subscription") }
 | CREATE TABLESPACE error { return unimplementedWithIssueDetail(sqllex, 54113, "create tablespace") }
 | CREATE TEXT error { return unimplementedWithIssueDetail(sqllex, 7821, "create text") }
-| CREATE TRIGGER error { return unimplementedWithIssueDetail(sqllex, 28296, "create trigger") }
 
 opt_or_replace:
   OR REPLACE {}
@@ -3544,17 +3847,11 @@ drop_unsupported:
 | DROP DOMAIN error { return unimplementedWithIssueDetail(sqllex, 27796, "drop") }
 | DROP EXTENSION IF EXISTS name error { return unimplemented(sqllex, "drop extension " + $5) }
 | DROP EXTENSION name error { return unimplemented(sqllex, "drop extension " + $3) }
-| DROP FOREIGN TABLE error { return unimplemented(sqllex, "drop foreign table") }
 | DROP FOREIGN DATA error { return unimplemented(sqllex, "drop fdw") }
-| DROP FUNCTION error { return unimplementedWithIssueDetail(sqllex, 17511, "drop function") }
 | DROP opt_procedural LANGUAGE name error { return unimplementedWithIssueDetail(sqllex, 17511, "drop language " + $4) }
 | DROP OPERATOR error { return unimplemented(sqllex, "drop operator") }
-| DROP PUBLICATION error { return unimplemented(sqllex, "drop publication") }
 | DROP RULE error { return unimplemented(sqllex, "drop rule") }
-| DROP SERVER error { return unimplemented(sqllex, "drop server") }
-| DROP SUBSCRIPTION error { return unimplemented(sqllex, "drop subscription") }
 | DROP TEXT error { return unimplementedWithIssueDetail(sqllex, 7821, "drop text") }
-| DROP TRIGGER error { return unimplementedWithIssueDetail(sqllex, 28296, "drop") }
 
 create_ddl_stmt:
   create_database_stmt // EXTEND WITH HELP: CREATE DATABASE
@@ -3567,6 +3864,342 @@ create_ddl_stmt:
 | create_type_stmt     // EXTEND WITH HELP: CREATE TYPE
 | create_view_stmt     // EXTEND WITH HELP: CREATE VIEW
 | create_sequence_stmt // EXTEND WITH HELP: CREATE SEQUENCE
+| create_trigger_stmt  // EXTEND WITH HELP: CREATE TRIGGER
+| create_func_stmt     // EXTEND WITH HELP: CREATE FUNCTION
+| create_policy_stmt   // EXTEND WITH HELP: CREATE POLICY
+
+// %Help: CREATE FUNCTION - define a new function
+// %Category: DDL
+// %Text:
+// CREATE [OR REPLACE] FUNCTION <name> ( [ [<argname>] <argtype> [, ...] ] )
+//   RETURNS <rettype>
+//   [ LANGUAGE SQL
+//   | { IMMUTABLE | STABLE | VOLATILE }
+//   | [ NOT ] LEAKPROOF
+//   | { CALLED ON NULL INPUT | RETURNS NULL ON NULL INPUT | STRICT }
+//   | AS '<definition>'
+//   ] ...
+//
+// The definition must be a single SELECT statement returning one column.
+// Parameters can be referenced by name or as $1, $2, ...
+// %SeeAlso: DROP FUNCTION, GRANT
+create_func_stmt:
+  CREATE FUNCTION db_object_name '(' opt_func_params ')' RETURNS typename opt_create_func_opt_list
+  {
+    $$.val = &tree.CreateFunction{
+      Name: $3.unresolvedObjectName(),
+      Params: $5.funcParams(),
+      ReturnType: $8.typeReference(),
+      Options: $9.functionOptions(),
+    }
+  }
+| CREATE OR REPLACE FUNCTION db_object_name '(' opt_func_params ')' RETURNS typename opt_create_func_opt_list
+  {
+    $$.val = &tree.CreateFunction{
+      Name: $5.unresolvedObjectName(),
+      Replace: true,
+      Params: $7.funcParams(),
+      ReturnType: $10.typeReference(),
+      Options: $11.functionOptions(),
+    }
+  }
+| CREATE FUNCTION error // SHOW HELP: CREATE FUNCTION
+| CREATE OR REPLACE FUNCTION error // SHOW HELP: CREATE FUNCTION
+
+opt_func_params:
+  func_params
+| /* EMPTY */
+  {
+    $$.val = tree.FuncParams{}
+  }
+
+func_params:
+  func_param
+  {
+    $$.val = tree.FuncParams{$1.funcParam()}
+  }
+| func_params ',' func_param
+  {
+    $$.val = append($1.funcParams(), $3.funcParam())
+  }
+
+func_param:
+  type_function_name typename
+  {
+    $$.val = tree.FuncParam{Name: tree.Name($1), Type: $2.typeReference()}
+  }
+| typename
+  {
+    $$.val = tree.FuncParam{Type: $1.typeReference()}
+  }
+
+opt_create_func_opt_list:
+  create_func_opt_list
+| /* EMPTY */
+  {
+    $$.val = tree.FunctionOptions(nil)
+  }
+
+create_func_opt_list:
+  create_func_opt_item
+  {
+    $$.val = tree.FunctionOptions{$1.functionOption()}
+  }
+| create_func_opt_list create_func_opt_item
+  {
+    $$.val = append($1.functionOptions(), $2.functionOption())
+  }
+
+create_func_opt_item:
+  AS SCONST
+  {
+    $$.val = tree.FunctionBodyStr($2)
+  }
+| LANGUAGE non_reserved_word_or_sconst
+  {
+    $$.val = tree.FunctionLanguage(strings.ToLower($2))
+  }
+| IMMUTABLE
+  {
+    $$.val = tree.FunctionVolatility(tree.VolatilityImmutable)
+  }
+| STABLE
+  {
+    $$.val = tree.FunctionVolatility(tree.VolatilityStable)
+  }
+| VOLATILE
+  {
+    $$.val = tree.FunctionVolatility(tree.VolatilityVolatile)
+  }
+| LEAKPROOF
+  {
+    $$.val = tree.FunctionLeakproof(true)
+  }
+| NOT LEAKPROOF
+  {
+    $$.val = tree.FunctionLeakproof(false)
+  }
+| CALLED ON NULL INPUT
+  {
+    $$.val = tree.FunctionCalledOnNullInput
+  }
+| RETURNS NULL ON NULL INPUT
+  {
+    $$.val = tree.FunctionReturnsNullOnNullInput
+  }
+| STRICT
+  {
+    $$.val = tree.FunctionStrict
+  }
+
+func_obj_list:
+  func_obj
+  {
+    $$.val = tree.FuncObjs{$1.funcObj()}
+  }
+| func_obj_list ',' func_obj
+  {
+    $$.val = append($1.funcObjs(), $3.funcObj())
+  }
+
+func_obj:
+  db_object_name
+  {
+    $$.val = tree.FuncObj{FuncName: $1.unresolvedObjectName()}
+  }
+| db_object_name '(' opt_func_params ')'
+  {
+    $$.val = tree.FuncObj{FuncName: $1.unresolvedObjectName(), Params: $3.funcParams()}
+  }
+
+// %Help: CREATE TRIGGER - define a new trigger
+// %Category: DDL
+// %Text:
+// CREATE TRIGGER <name> { BEFORE | AFTER } <event> [OR ...]
+//   ON <tablename> FOR EACH ROW [WHEN ( <condition> )] <action>
+//
+// Events:
+//   INSERT, UPDATE, DELETE
+//
+// Actions:
+//   SET <colname> = <expr> [, ...]       (BEFORE triggers)
+//   INSERT ... | UPSERT ... | UPDATE ... | DELETE ...  (AFTER triggers)
+//
+// The condition and the action can refer to the new and old versions
+// of the modified rows as NEW.<colname> and OLD.<colname>.
+// %SeeAlso: DROP TRIGGER
+create_trigger_stmt:
+  CREATE TRIGGER name trigger_action_time trigger_event_list ON table_name FOR EACH ROW opt_trigger_when SET set_clause_list
+  {
+    $$.val = &tree.CreateTrigger{
+      Name: tree.Name($3),
+      ActionTime: $4.triggerActionTime(),
+      Events: $5.triggerEvents(),
+      Table: $7.unresolvedObjectName(),
+      When: $11.expr(),
+      Set: $13.updateExprs(),
+    }
+  }
+| CREATE TRIGGER name trigger_action_time trigger_event_list ON table_name FOR EACH ROW opt_trigger_when trigger_action_stmt
+  {
+    $$.val = &tree.CreateTrigger{
+      Name: tree.Name($3),
+      ActionTime: $4.triggerActionTime(),
+      Events: $5.triggerEvents(),
+      Table: $7.unresolvedObjectName(),
+      When: $11.expr(),
+      Body: $12.stmt(),
+    }
+  }
+| CREATE TRIGGER error // SHOW HELP: CREATE TRIGGER
+
+trigger_action_time:
+  BEFORE
+  {
+    $$.val = tree.TriggerActionTimeBefore
+  }
+| AFTER
+  {
+    $$.val = tree.TriggerActionTimeAfter
+  }
+
+trigger_event_list:
+  trigger_event
+  {
+    $$.val = []tree.TriggerEvent{$1.triggerEvent()}
+  }
+| trigger_event_list OR trigger_event
+  {
+    $$.val = append($1.triggerEvents(), $3.triggerEvent())
+  }
+
+trigger_event:
+  INSERT
+  {
+    $$.val = tree.TriggerEventInsert
+  }
+| UPDATE
+  {
+    $$.val = tree.TriggerEventUpdate
+  }
+| DELETE
+  {
+    $$.val = tree.TriggerEventDelete
+  }
+
+opt_trigger_when:
+  WHEN '(' a_expr ')'
+  {
+    $$.val = $3.expr()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.Expr(nil)
+  }
+
+trigger_action_stmt:
+  insert_stmt
+| upsert_stmt
+| update_stmt
+| delete_stmt
+
+// %Help: CREATE POLICY - define a new row-level security policy for a table
+// %Category: DDL
+// %Text:
+// CREATE POLICY <name> ON <tablename>
+//   [AS { PERMISSIVE | RESTRICTIVE }]
+//   [FOR { ALL | SELECT | INSERT | UPDATE | DELETE }]
+//   [TO <role> [, ...]]
+//   [USING ( <condition> )]
+//   [WITH CHECK ( <condition> )]
+//
+// The policies of a table only apply once row-level security is enabled
+// with ALTER TABLE ... ENABLE ROW LEVEL SECURITY.
+// %SeeAlso: DROP POLICY, ALTER TABLE
+create_policy_stmt:
+  CREATE POLICY name ON table_name opt_policy_restrictive opt_policy_command opt_policy_roles opt_policy_using opt_policy_with_check
+  {
+    $$.val = &tree.CreatePolicy{
+      Name: tree.Name($3),
+      Table: $5.unresolvedObjectName(),
+      Restrictive: $6.bool(),
+      Command: $7.policyCommand(),
+      Roles: $8.nameList(),
+      Using: $9.expr(),
+      WithCheck: $10.expr(),
+    }
+  }
+| CREATE POLICY error // SHOW HELP: CREATE POLICY
+
+opt_policy_restrictive:
+  AS PERMISSIVE
+  {
+    $$.val = false
+  }
+| AS RESTRICTIVE
+  {
+    $$.val = true
+  }
+| /* EMPTY */
+  {
+    $$.val = false
+  }
+
+opt_policy_command:
+  FOR ALL
+  {
+    $$.val = tree.PolicyCommandAll
+  }
+| FOR SELECT
+  {
+    $$.val = tree.PolicyCommandSelect
+  }
+| FOR INSERT
+  {
+    $$.val = tree.PolicyCommandInsert
+  }
+| FOR UPDATE
+  {
+    $$.val = tree.PolicyCommandUpdate
+  }
+| FOR DELETE
+  {
+    $$.val = tree.PolicyCommandDelete
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.PolicyCommandAll
+  }
+
+opt_policy_roles:
+  TO name_list
+  {
+    $$.val = $2.nameList()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.NameList(nil)
+  }
+
+opt_policy_using:
+  USING '(' a_expr ')'
+  {
+    $$.val = $3.expr()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.Expr(nil)
+  }
+
+opt_policy_with_check:
+  WITH CHECK '(' a_expr ')'
+  {
+    $$.val = $4.expr()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.Expr(nil)
+  }
 
 // %Help: CREATE STATISTICS - create a new table statistic
 // %Category: Misc
@@ -3669,6 +4302,9 @@ create_stats_option:
 // CREATE CHANGEFEED
 // FOR <targets> [INTO sink] [WITH <options>]
 //
+// CREATE CHANGEFEED [INTO sink] [WITH <options>]
+// AS SELECT <targets> FROM <table> [WHERE <expr>]
+//
 // Sink: Data caputre stream stream destination.  Enterprise only.
 create_changefeed_stmt:
   CREATE CHANGEFEED FOR changefeed_targets opt_changefeed_sink opt_with_options
@@ -3679,6 +4315,18 @@ create_changefeed_stmt:
       Options: $6.kvOptions(),
     }
   }
+| CREATE CHANGEFEED opt_changefeed_sink opt_with_options AS SELECT target_list FROM changefeed_target_expr opt_where_clause
+  {
+    $$.val = &tree.CreateChangefeed{
+      SinkURI: $3.expr(),
+      Options: $4.kvOptions(),
+      Select: &tree.SelectClause{
+        Exprs: $7.selExprs(),
+        From:  tree.From{Tables: tree.TableExprs{$9.tblExpr()}},
+        Where: tree.NewWhere(tree.AstWhere, $10.expr()),
+      },
+    }
+  }
 | EXPERIMENTAL CHANGEFEED FOR changefeed_targets opt_with_options
   {
     /* SKIP DOC */
@@ -3698,6 +4346,16 @@ changefeed_targets:
     $$.val = tree.TargetList{Tables: $2.tablePatterns()}
   }
 
+changefeed_target_expr:
+  table_name opt_alias_clause
+  {
+    name := $1.unresolvedObjectName().ToTableName()
+    $$.val = &tree.AliasedTableExpr{
+      Expr: &name,
+      As:   $2.aliasClause(),
+    }
+  }
+
 single_table_pattern_list:
   table_name
   {
@@ -3779,6 +4437,151 @@ replication_options:
     $$.val = &tree.ReplicationOptions{Detached: true}
   }
 
+// %Help: CREATE PUBLICATION - define a new publication
+// %Category: DDL
+// %Text:
+// CREATE PUBLICATION <name> FOR TABLE <tablename> [, ...]
+// CREATE PUBLICATION <name> FOR ALL TABLES
+//
+// The tables of a publication must belong to the current database.
+// %SeeAlso: DROP PUBLICATION, CREATE SUBSCRIPTION
+create_publication_stmt:
+  CREATE PUBLICATION name FOR TABLE table_name_list
+  {
+    $$.val = &tree.CreatePublication{Name: tree.Name($3), Tables: $6.tableNames()}
+  }
+| CREATE PUBLICATION name FOR ALL TABLES
+  {
+    $$.val = &tree.CreatePublication{Name: tree.Name($3), AllTables: true}
+  }
+| CREATE PUBLICATION error // SHOW HELP: CREATE PUBLICATION
+
+// %Help: CREATE SUBSCRIPTION - replicate the tables of publications
+// %Category: CCL
+// %Text:
+// CREATE SUBSCRIPTION <name> CONNECTION '<uri>' PUBLICATION <publication> [, ...]
+//   [WITH ( <option> [= <value>] [, ...] )]
+//
+// The subscription applies the changes made to the tables of the
+// publications in the cluster at <uri> to the tables with the same
+// names in the current database.
+//
+// Options:
+//   copy_data = <bool>   copy the existing rows of the tables (default true)
+//
+// %SeeAlso: DROP SUBSCRIPTION, CREATE PUBLICATION
+create_subscription_stmt:
+  CREATE SUBSCRIPTION name CONNECTION string_or_placeholder PUBLICATION name_list opt_with_subscription_options
+  {
+    $$.val = &tree.CreateSubscription{
+      Name: tree.Name($3),
+      ConnectionURI: $5.expr(),
+      Publications: $7.nameList(),
+      Options: $8.kvOptions(),
+    }
+  }
+| CREATE SUBSCRIPTION error // SHOW HELP: CREATE SUBSCRIPTION
+
+opt_with_subscription_options:
+  WITH '(' kv_option_list ')'
+  {
+    $$.val = $3.kvOptions()
+  }
+| /* EMPTY */
+  {
+    $$.val = nil
+  }
+
+// %Help: CREATE SERVER - define a new foreign server
+// %Category: DDL
+// %Text:
+// CREATE SERVER [IF NOT EXISTS] <name> FOREIGN DATA WRAPPER <wrapper>
+//   [OPTIONS ( <option> '<value>' [, ...] )]
+//
+// The only supported wrapper is file_fdw, which reads the files of foreign
+// tables from external storage.
+//
+// Options:
+//   location   the URI of the external storage that the locations of the
+//              foreign tables of the server are relative to
+//
+// %SeeAlso: DROP SERVER, CREATE FOREIGN TABLE
+create_server_stmt:
+  CREATE SERVER name FOREIGN DATA WRAPPER name opt_foreign_options
+  {
+    $$.val = &tree.CreateServer{Name: tree.Name($3), Wrapper: tree.Name($7), Options: $8.kvOptions()}
+  }
+| CREATE SERVER IF NOT EXISTS name FOREIGN DATA WRAPPER name opt_foreign_options
+  {
+    $$.val = &tree.CreateServer{Name: tree.Name($6), IfNotExists: true, Wrapper: tree.Name($10), Options: $11.kvOptions()}
+  }
+| CREATE SERVER error // SHOW HELP: CREATE SERVER
+
+// %Help: CREATE FOREIGN TABLE - define a table reading files in external storage
+// %Category: DDL
+// %Text:
+// CREATE FOREIGN TABLE [IF NOT EXISTS] <tablename> ( <colname> <type> [NULL] [, ...] )
+//   SERVER <server> [OPTIONS ( <option> '<value>' [, ...] )]
+//
+// Options:
+//   location      the path of the files, relative to the location of the
+//                 server; may contain glob patterns
+//   format        'csv' (default) or 'avro'
+//   delimiter     the field delimiter of CSV files
+//   header        'true' if the first row of CSV files is a header
+//   null          the string representing NULL in CSV files
+//   compression   'auto' (default), 'none', 'gzip' or 'bzip'
+//
+// Foreign tables are read-only.
+// %SeeAlso: CREATE SERVER, DROP FOREIGN TABLE
+create_foreign_table_stmt:
+  CREATE FOREIGN TABLE table_name '(' opt_table_elem_list ')' SERVER name opt_foreign_options
+  {
+    name := $4.unresolvedObjectName().ToTableName()
+    $$.val = &tree.CreateTable{
+      Table: name,
+      Defs: $6.tblDefs(),
+      Foreign: &tree.ForeignTableDef{Server: tree.Name($9), Options: $10.kvOptions()},
+    }
+  }
+| CREATE FOREIGN TABLE IF NOT EXISTS table_name '(' opt_table_elem_list ')' SERVER name opt_foreign_options
+  {
+    name := $7.unresolvedObjectName().ToTableName()
+    $$.val = &tree.CreateTable{
+      Table: name,
+      IfNotExists: true,
+      Defs: $9.tblDefs(),
+      Foreign: &tree.ForeignTableDef{Server: tree.Name($12), Options: $13.kvOptions()},
+    }
+  }
+| CREATE FOREIGN TABLE error // SHOW HELP: CREATE FOREIGN TABLE
+
+opt_foreign_options:
+  OPTIONS '(' foreign_option_list ')'
+  {
+    $$.val = $3.kvOptions()
+  }
+| /* EMPTY */
+  {
+    $$.val = nil
+  }
+
+foreign_option_list:
+  foreign_option
+  {
+    $$.val = []tree.KVOption{$1.kvOption()}
+  }
+| foreign_option_list ',' foreign_option
+  {
+    $$.val = append($1.kvOptions(), $3.kvOption())
+  }
+
+foreign_option:
+  unrestricted_name SCONST
+  {
+    $$.val = tree.KVOption{Key: tree.Name($1), Value: tree.NewStrVal($2)}
+  }
+
 // %Help: DELETE - delete rows from a table
 // %Category: DML
 // %Text: DELETE FROM <tablename> [WHERE <expr>]
@@ -3823,11 +4626,16 @@ discard_stmt:
 // %Category: Group
 // %Text:
 // DROP DATABASE, DROP INDEX, DROP TABLE, DROP VIEW, DROP SEQUENCE,
-// DROP USER, DROP ROLE, DROP TYPE
+// DROP USER, DROP ROLE, DROP TYPE, DROP TRIGGER, DROP POLICY, DROP PUBLICATION,
+// DROP SUBSCRIPTION, DROP SERVER, DROP FOREIGN TABLE
 drop_stmt:
   drop_ddl_stmt      // help texts in sub-rule
 | drop_role_stmt     // EXTEND WITH HELP: DROP ROLE
 | drop_schedule_stmt // EXTEND WITH HELP: DROP SCHEDULES
+| drop_publication_stmt   // EXTEND WITH HELP: DROP PUBLICATION
+| drop_subscription_stmt  // EXTEND WITH HELP: DROP SUBSCRIPTION
+| drop_server_stmt        // EXTEND WITH HELP: DROP SERVER
+| drop_foreign_table_stmt // EXTEND WITH HELP: DROP FOREIGN TABLE
 | drop_unsupported   {}
 | DROP error         // SHOW HELP: DROP
 
@@ -3839,6 +4647,9 @@ drop_ddl_stmt:
 | drop_sequence_stmt // EXTEND WITH HELP: DROP SEQUENCE
 | drop_schema_stmt   // EXTEND WITH HELP: DROP SCHEMA
 | drop_type_stmt     // EXTEND WITH HELP: DROP TYPE
+| drop_trigger_stmt  // EXTEND WITH HELP: DROP TRIGGER
+| drop_func_stmt     // EXTEND WITH HELP: DROP FUNCTION
+| drop_policy_stmt   // EXTEND WITH HELP: DROP POLICY
 
 // %Help: DROP VIEW - remove a view
 // %Category: DDL
@@ -3849,29 +4660,102 @@ drop_view_stmt:
   {
     $$.val = &tree.DropView{Names: $3.tableNames(), IfExists: false, DropBehavior: $4.dropBehavior()}
   }
-| DROP VIEW IF EXISTS table_name_list opt_drop_behavior
+| DROP VIEW IF EXISTS table_name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropView{Names: $5.tableNames(), IfExists: true, DropBehavior: $6.dropBehavior()}
+  }
+| DROP MATERIALIZED VIEW table_name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropView{
+      Names: $4.tableNames(),
+      IfExists: false,
+      DropBehavior: $5.dropBehavior(),
+      IsMaterialized: true,
+    }
+  }
+| DROP MATERIALIZED VIEW IF EXISTS table_name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropView{
+      Names: $6.tableNames(),
+      IfExists: true,
+      DropBehavior: $7.dropBehavior(),
+      IsMaterialized: true,
+    }
+  }
+| DROP VIEW error // SHOW HELP: DROP VIEW
+
+// %Help: DROP FUNCTION - remove a function
+// %Category: DDL
+// %Text: DROP FUNCTION [IF EXISTS] <name> [ ( [<argtype> [, ...]] ) ] [, ...] [CASCADE | RESTRICT]
+// %SeeAlso: CREATE FUNCTION
+drop_func_stmt:
+  DROP FUNCTION func_obj_list opt_drop_behavior
+  {
+    $$.val = &tree.DropFunction{
+      Functions: $3.funcObjs(),
+      IfExists: false,
+      DropBehavior: $4.dropBehavior(),
+    }
+  }
+| DROP FUNCTION IF EXISTS func_obj_list opt_drop_behavior
+  {
+    $$.val = &tree.DropFunction{
+      Functions: $5.funcObjs(),
+      IfExists: true,
+      DropBehavior: $6.dropBehavior(),
+    }
+  }
+| DROP FUNCTION error // SHOW HELP: DROP FUNCTION
+
+// %Help: DROP TRIGGER - remove a trigger
+// %Category: DDL
+// %Text: DROP TRIGGER [IF EXISTS] <name> ON <tablename> [CASCADE | RESTRICT]
+// %SeeAlso: CREATE TRIGGER
+drop_trigger_stmt:
+  DROP TRIGGER name ON table_name opt_drop_behavior
+  {
+    $$.val = &tree.DropTrigger{
+      Name: tree.Name($3),
+      Table: $5.unresolvedObjectName(),
+      IfExists: false,
+      DropBehavior: $6.dropBehavior(),
+    }
+  }
+| DROP TRIGGER IF EXISTS name ON table_name opt_drop_behavior
   {
-    $$.val = &tree.DropView{Names: $5.tableNames(), IfExists: true, DropBehavior: $6.dropBehavior()}
+    $$.val = &tree.DropTrigger{
+      Name: tree.Name($5),
+      Table: $7.unresolvedObjectName(),
+      IfExists: true,
+      DropBehavior: $8.dropBehavior(),
+    }
   }
-| DROP MATERIALIZED VIEW table_name_list opt_drop_behavior
+| DROP TRIGGER error // SHOW HELP: DROP TRIGGER
+
+// %Help: DROP POLICY - remove a row-level security policy
+// %Category: DDL
+// %Text: DROP POLICY [IF EXISTS] <name> ON <tablename> [CASCADE | RESTRICT]
+// %SeeAlso: CREATE POLICY
+drop_policy_stmt:
+  DROP POLICY name ON table_name opt_drop_behavior
   {
-    $$.val = &tree.DropView{
-      Names: $4.tableNames(),
+    $$.val = &tree.DropPolicy{
+      Name: tree.Name($3),
+      Table: $5.unresolvedObjectName(),
       IfExists: false,
-      DropBehavior: $5.dropBehavior(),
-      IsMaterialized: true,
+      DropBehavior: $6.dropBehavior(),
     }
   }
-| DROP MATERIALIZED VIEW IF EXISTS table_name_list opt_drop_behavior
+| DROP POLICY IF EXISTS name ON table_name opt_drop_behavior
   {
-    $$.val = &tree.DropView{
-      Names: $6.tableNames(),
+    $$.val = &tree.DropPolicy{
+      Name: tree.Name($5),
+      Table: $7.unresolvedObjectName(),
       IfExists: true,
-      DropBehavior: $7.dropBehavior(),
-      IsMaterialized: true,
+      DropBehavior: $8.dropBehavior(),
     }
   }
-| DROP VIEW error // SHOW HELP: DROP VIEW
+| DROP POLICY error // SHOW HELP: DROP POLICY
 
 // %Help: DROP SEQUENCE - remove a sequence
 // %Category: DDL
@@ -4292,12 +5176,13 @@ deallocate_stmt:
 //   GRANT <roles...> TO <grantees...> [WITH ADMIN OPTION]
 //
 // Privileges:
-//   CREATE, DROP, GRANT, SELECT, INSERT, DELETE, UPDATE, USAGE
+//   CREATE, DROP, GRANT, SELECT, INSERT, DELETE, UPDATE, USAGE, EXECUTE
 //
 // Targets:
 //   DATABASE <databasename> [, ...]
 //   [TABLE] [<databasename> .] { <tablename> | * } [, ...]
 //   TYPE <typename> [, <typename>]...
+//   FUNCTION <funcname> [, <funcname>]...
 //   SCHEMA [<databasename> .]<schemaname> [, [<databasename> .]<schemaname>]...
 //   ALL TABLES IN SCHEMA schema_name [, ...]
 //
@@ -4319,6 +5204,16 @@ grant_stmt:
   {
     $$.val = &tree.Grant{Privileges: $2.privilegeList(), Targets: $5.targetList(), Grantees: $7.nameList()}
   }
+| GRANT privileges ON FUNCTION func_obj_list TO name_list
+  {
+    $$.val = &tree.Grant{
+      Privileges: $2.privilegeList(),
+      Targets: tree.TargetList{
+        Functions: $5.funcObjs(),
+      },
+      Grantees: $7.nameList(),
+    }
+  }
 | GRANT privileges ON SCHEMA schema_name_list TO name_list
   {
     $$.val = &tree.Grant{
@@ -4359,12 +5254,13 @@ grant_stmt:
 //   REVOKE [ADMIN OPTION FOR] <roles...> FROM <grantees...>
 //
 // Privileges:
-//   CREATE, DROP, GRANT, SELECT, INSERT, DELETE, UPDATE, USAGE
+//   CREATE, DROP, GRANT, SELECT, INSERT, DELETE, UPDATE, USAGE, EXECUTE
 //
 // Targets:
 //   DATABASE <databasename> [, <databasename>]...
 //   [TABLE] [<databasename> .] { <tablename> | * } [, ...]
 //   TYPE <typename> [, <typename>]...
+//   FUNCTION <funcname> [, <funcname>]...
 //   SCHEMA [<databasename> .]<schemaname> [, [<databasename> .]<schemaname]...
 //   ALL TABLES IN SCHEMA schema_name [, ...]
 //
@@ -4386,6 +5282,16 @@ revoke_stmt:
   {
     $$.val = &tree.Revoke{Privileges: $2.privilegeList(), Targets: $5.targetList(), Grantees: $7.nameList()}
   }
+| REVOKE privileges ON FUNCTION func_obj_list FROM name_list
+  {
+    $$.val = &tree.Revoke{
+      Privileges: $2.privilegeList(),
+      Targets: tree.TargetList{
+        Functions: $5.funcObjs(),
+      },
+      Grantees: $7.nameList(),
+    }
+  }
 | REVOKE privileges ON SCHEMA schema_name_list FROM name_list
   {
     $$.val = &tree.Revoke{
@@ -4494,8 +5400,8 @@ use_stmt:
 // SET remainder, e.g. SET TRANSACTION
 nonpreparable_set_stmt:
   set_transaction_stmt // EXTEND WITH HELP: SET TRANSACTION
+| set_constraints_stmt // EXTEND WITH HELP: SET CONSTRAINTS
 | set_exprs_internal   { /* SKIP DOC */ }
-| SET CONSTRAINTS error { return unimplemented(sqllex, "set constraints") }
 
 // SET SESSION / SET LOCAL / SET CLUSTER SETTING
 preparable_set_stmt:
@@ -4635,7 +5541,7 @@ set_exprs_internal:
 // %Text:
 // SET [SESSION] <var> { TO | = } <values...>
 // SET [SESSION] TIME ZONE <tz>
-// SET [SESSION] CHARACTERISTICS AS TRANSACTION ISOLATION LEVEL { SNAPSHOT | SERIALIZABLE }
+// SET [SESSION] CHARACTERISTICS AS TRANSACTION ISOLATION LEVEL { READ COMMITTED | SNAPSHOT | SERIALIZABLE }
 // SET [SESSION] TRACING { TO | = } { on | off | cluster | kv | results } [,...]
 //
 // %SeeAlso: SHOW SESSION, RESET, DISCARD, SHOW, SET CLUSTER SETTING, SET TRANSACTION, SET LOCAL
@@ -4692,7 +5598,7 @@ set_local_stmt:
 // SET [SESSION] TRANSACTION <txnparameters...>
 //
 // Transaction parameters:
-//    ISOLATION LEVEL { SNAPSHOT | SERIALIZABLE }
+//    ISOLATION LEVEL { READ COMMITTED | SNAPSHOT | SERIALIZABLE }
 //    PRIORITY { LOW | NORMAL | HIGH }
 //    AS OF SYSTEM TIME <expr>
 //    [NOT] DEFERRABLE
@@ -4711,6 +5617,35 @@ set_transaction_stmt:
   }
 | SET SESSION TRANSACTION error // SHOW HELP: SET TRANSACTION
 
+// %Help: SET CONSTRAINTS - set the checking mode of deferrable constraints
+// %Category: Txn
+// %Text:
+// SET CONSTRAINTS { ALL | <name> [, ...] } { DEFERRED | IMMEDIATE }
+//
+// DEFERRED constraints are checked when the transaction commits. Setting a
+// constraint to IMMEDIATE checks the pending changes right away.
+// %SeeAlso: CREATE TABLE, ALTER TABLE, SET TRANSACTION
+set_constraints_stmt:
+  SET CONSTRAINTS ALL constraints_set_mode
+  {
+    $$.val = &tree.SetConstraints{All: true, Deferred: $4.bool()}
+  }
+| SET CONSTRAINTS name_list constraints_set_mode
+  {
+    $$.val = &tree.SetConstraints{Names: $3.nameList(), Deferred: $4.bool()}
+  }
+| SET CONSTRAINTS error // SHOW HELP: SET CONSTRAINTS
+
+constraints_set_mode:
+  DEFERRED
+  {
+    $$.val = true
+  }
+| IMMEDIATE
+  {
+    $$.val = false
+  }
+
 generic_set:
   var_name to_or_eq var_list
   {
@@ -4829,11 +5764,11 @@ var_list:
 iso_level:
   READ UNCOMMITTED
   {
-    $$.val = tree.SerializableIsolation
+    $$.val = tree.ReadCommittedIsolation
   }
 | READ COMMITTED
   {
-    $$.val = tree.SerializableIsolation
+    $$.val = tree.ReadCommittedIsolation
   }
 | SNAPSHOT
   {
@@ -4942,14 +5877,280 @@ show_stmt:
 | show_full_scans_stmt
 | show_default_privileges_stmt // EXTEND WITH HELP: SHOW DEFAULT PRIVILEGES
 
-// Cursors are not yet supported by CockroachDB. CLOSE ALL is safe to no-op
-// since there will be no open cursors.
+// %Help: CLOSE - close a cursor
+// %Category: Misc
+// %Text: CLOSE { <name> | ALL }
+// %SeeAlso: DECLARE, FETCH, MOVE
 close_cursor_stmt:
-	CLOSE ALL { }
-| CLOSE cursor_name { return unimplementedWithIssue(sqllex, 41412) }
+  CLOSE ALL
+  {
+    $$.val = &tree.CloseCursor{
+      All: true,
+    }
+  }
+| CLOSE cursor_name
+  {
+    $$.val = &tree.CloseCursor{
+      Name: tree.Name($2),
+    }
+  }
+| CLOSE error // SHOW HELP: CLOSE
 
+// %Help: DECLARE - declare a cursor
+// %Category: Misc
+// %Text: DECLARE <name> [ BINARY ] [ ASENSITIVE | INSENSITIVE ] [ [ NO ] SCROLL ]
+//        CURSOR [ { WITH | WITHOUT } HOLD ] FOR <query>
+// %SeeAlso: CLOSE, FETCH, MOVE
 declare_cursor_stmt:
-	DECLARE { return unimplementedWithIssue(sqllex, 41412) }
+  DECLARE cursor_name opt_binary opt_sensitivity opt_scroll CURSOR opt_hold FOR select_stmt
+  {
+    $$.val = &tree.DeclareCursor{
+      Name: tree.Name($2),
+      Binary: $3.bool(),
+      Sensitivity: $4.cursorSensitivity(),
+      Scroll: $5.cursorScrollOption(),
+      Hold: $7.bool(),
+      Select: $9.slct(),
+    }
+  }
+| DECLARE error // SHOW HELP: DECLARE
+
+opt_binary:
+  BINARY
+  {
+    $$.val = true
+  }
+| /* EMPTY */
+  {
+    $$.val = false
+  }
+
+opt_sensitivity:
+  INSENSITIVE
+  {
+    $$.val = tree.Insensitive
+  }
+| ASENSITIVE
+  {
+    $$.val = tree.Asensitive
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.UnspecifiedSensitivity
+  }
+
+opt_scroll:
+  SCROLL
+  {
+    $$.val = tree.Scroll
+  }
+| NO SCROLL
+  {
+    $$.val = tree.NoScroll
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.UnspecifiedScroll
+  }
+
+opt_hold:
+  WITH HOLD
+  {
+    $$.val = true
+  }
+| WITHOUT HOLD
+  {
+    $$.val = false
+  }
+| /* EMPTY */
+  {
+    $$.val = false
+  }
+
+// %Help: FETCH - fetch rows from a cursor
+// %Category: Misc
+// %Text: FETCH [ <direction> [ FROM | IN ] ] <name>
+//
+// Direction:
+//   NEXT | FIRST | ABSOLUTE <count> | RELATIVE <count>
+//   <count> | ALL | FORWARD [ <count> | ALL ]
+// %SeeAlso: CLOSE, DECLARE, MOVE
+fetch_cursor_stmt:
+  FETCH cursor_movement_specifier
+  {
+    $$.val = &tree.FetchCursor{
+      CursorStmt: $2.cursorStmt(),
+    }
+  }
+| FETCH error // SHOW HELP: FETCH
+
+// %Help: MOVE - move a cursor without returning rows
+// %Category: Misc
+// %Text: MOVE [ <direction> [ FROM | IN ] ] <name>
+//
+// Direction:
+//   NEXT | FIRST | ABSOLUTE <count> | RELATIVE <count>
+//   <count> | ALL | FORWARD [ <count> | ALL ]
+// %SeeAlso: CLOSE, DECLARE, FETCH
+move_cursor_stmt:
+  MOVE cursor_movement_specifier
+  {
+    $$.val = &tree.MoveCursor{
+      CursorStmt: $2.cursorStmt(),
+    }
+  }
+| MOVE error // SHOW HELP: MOVE
+
+cursor_movement_specifier:
+  cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($1),
+      Count: 1,
+    }
+  }
+| from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($2),
+      Count: 1,
+    }
+  }
+| next_prior opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($3),
+      Count: $1.int64(),
+    }
+  }
+| forward_backward opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($3),
+      Count: $1.int64(),
+    }
+  }
+| opt_forward_backward signed_iconst64 opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($4),
+      Count: $2.int64() * $1.int64(),
+    }
+  }
+| opt_forward_backward ALL opt_from_or_in cursor_name
+  {
+    fetchType := tree.FetchAll
+    count := $1.int64()
+    if count < 0 {
+      fetchType = tree.FetchBackwardAll
+    }
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($4),
+      FetchType: fetchType,
+    }
+  }
+| ABSOLUTE signed_iconst64 opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($4),
+      FetchType: tree.FetchAbsolute,
+      Count: $2.int64(),
+    }
+  }
+| RELATIVE signed_iconst64 opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($4),
+      FetchType: tree.FetchRelative,
+      Count: $2.int64(),
+    }
+  }
+| FIRST opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($3),
+      FetchType: tree.FetchFirst,
+    }
+  }
+| LAST opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($3),
+      FetchType: tree.FetchLast,
+    }
+  }
+
+next_prior:
+  NEXT  { $$.val = int64(1) }
+| PRIOR { $$.val = int64(-1) }
+
+opt_forward_backward:
+  forward_backward { $$.val = $1.int64() }
+| /* EMPTY */ { $$.val = int64(1) }
+
+forward_backward:
+  FORWARD  { $$.val = int64(1) }
+| BACKWARD { $$.val = int64(-1) }
+
+opt_from_or_in:
+  from_or_in { }
+| /* EMPTY */ { }
+
+from_or_in:
+  FROM { }
+| IN { }
+
+// %Help: LISTEN - listen for notifications on a channel
+// %Category: Misc
+// %Text: LISTEN <channel>
+// %SeeAlso: NOTIFY, UNLISTEN
+listen_stmt:
+  LISTEN name
+  {
+    $$.val = &tree.Listen{
+      ChannelName: tree.Name($2),
+    }
+  }
+| LISTEN error // SHOW HELP: LISTEN
+
+// %Help: NOTIFY - send a notification on a channel
+// %Category: Misc
+// %Text: NOTIFY <channel> [, <payload>]
+// %SeeAlso: LISTEN, UNLISTEN
+notify_stmt:
+  NOTIFY name
+  {
+    $$.val = &tree.Notify{
+      ChannelName: tree.Name($2),
+    }
+  }
+| NOTIFY name ',' SCONST
+  {
+    $$.val = &tree.Notify{
+      ChannelName: tree.Name($2),
+      Payload: tree.NewStrVal($4),
+    }
+  }
+| NOTIFY error // SHOW HELP: NOTIFY
+
+// %Help: UNLISTEN - stop listening for notifications
+// %Category: Misc
+// %Text: UNLISTEN { <channel> | * }
+// %SeeAlso: LISTEN, NOTIFY
+unlisten_stmt:
+  UNLISTEN name
+  {
+    $$.val = &tree.Unlisten{
+      ChannelName: tree.Name($2),
+    }
+  }
+| UNLISTEN '*'
+  {
+    $$.val = &tree.Unlisten{
+      All: true,
+    }
+  }
+| UNLISTEN error // SHOW HELP: UNLISTEN
 
 reindex_stmt:
   REINDEX TABLE error
@@ -6702,6 +7903,17 @@ partition_by_inner:
       Range: $6.rangePartitions(),
     }
   }
+| RANGE '(' name_list ')' INTERVAL SCONST opt_partition_interval_options opt_range_partitions
+  {
+    $$.val = &tree.PartitionBy{
+      Fields: $3.nameList(),
+      Range: $8.rangePartitions(),
+      Interval: &tree.PartitionInterval{
+        Interval: tree.NewStrVal($6),
+        Options: $7.kvOptions(),
+      },
+    }
+  }
 | NOTHING
   {
     $$.val = (*tree.PartitionBy)(nil)
@@ -6727,6 +7939,26 @@ list_partition:
     }
   }
 
+opt_partition_interval_options:
+  OPTIONS '(' kv_option_list ')'
+  {
+    $$.val = $3.kvOptions()
+  }
+| /* EMPTY */
+  {
+    $$.val = nil
+  }
+
+opt_range_partitions:
+  '(' range_partitions ')'
+  {
+    $$.val = $2.rangePartitions()
+  }
+| /* EMPTY */
+  {
+    $$.val = []tree.RangePartition(nil)
+  }
+
 range_partitions:
   range_partition
   {
@@ -6856,7 +8088,7 @@ col_qualification_elem:
   {
     $$.val = &tree.ColumnOnUpdate{Expr: $3.expr()}
   }
-| REFERENCES table_name opt_name_parens key_match reference_actions
+| REFERENCES table_name opt_name_parens key_match reference_actions opt_deferrable
   {
     name := $2.unresolvedObjectName().ToTableName()
     $$.val = &tree.ColumnFKConstraint{
@@ -6864,6 +8096,7 @@ col_qualification_elem:
       Col: tree.Name($3),
       Actions: $5.referenceActions(),
       Match: $4.compositeKeyMatchMethod(),
+      Deferrable: $6.constraintDeferrability(),
     }
   }
 | generated_as '(' a_expr ')' STORED
@@ -6988,6 +8221,10 @@ table_constraint:
 constraint_elem:
   CHECK '(' a_expr ')' opt_deferrable
   {
+    if $5.constraintDeferrability() != tree.NotDeferrable {
+      sqllex.Error("CHECK constraints cannot be marked DEFERRABLE")
+      return 1
+    }
     $$.val = &tree.CheckConstraintTableDef{
       Expr: $3.expr(),
     }
@@ -7004,6 +8241,7 @@ constraint_elem:
         PartitionByIndex: $8.partitionByIndex(),
         Predicate: $10.expr(),
       },
+      Deferrable: $9.constraintDeferrability(),
     }
   }
 | PRIMARY KEY '(' index_params ')' opt_hash_sharded opt_interleave
@@ -7027,6 +8265,7 @@ constraint_elem:
       ToCols: $8.nameList(),
       Match: $9.compositeKeyMatchMethod(),
       Actions: $10.referenceActions(),
+      Deferrable: $11.constraintDeferrability(),
     }
   }
 | EXCLUDE USING error
@@ -7137,12 +8376,30 @@ create_as_col_qualification_elem:
   }
 
 opt_deferrable:
-  /* EMPTY */ { /* no error */ }
-| DEFERRABLE { return unimplementedWithIssueDetail(sqllex, 31632, "deferrable") }
-| DEFERRABLE INITIALLY DEFERRED { return unimplementedWithIssueDetail(sqllex, 31632, "def initially deferred") }
-| DEFERRABLE INITIALLY IMMEDIATE { return unimplementedWithIssueDetail(sqllex, 31632, "def initially immediate") }
-| INITIALLY DEFERRED { return unimplementedWithIssueDetail(sqllex, 31632, "initially deferred") }
-| INITIALLY IMMEDIATE { return unimplementedWithIssueDetail(sqllex, 31632, "initially immediate") }
+  /* EMPTY */
+  {
+    $$.val = tree.NotDeferrable
+  }
+| DEFERRABLE
+  {
+    $$.val = tree.DeferrableInitiallyImmediate
+  }
+| DEFERRABLE INITIALLY DEFERRED
+  {
+    $$.val = tree.DeferrableInitiallyDeferred
+  }
+| DEFERRABLE INITIALLY IMMEDIATE
+  {
+    $$.val = tree.DeferrableInitiallyImmediate
+  }
+| INITIALLY DEFERRED
+  {
+    $$.val = tree.DeferrableInitiallyDeferred
+  }
+| INITIALLY IMMEDIATE
+  {
+    $$.val = tree.NotDeferrable
+  }
 
 storing:
   COVERING
@@ -8453,6 +9710,66 @@ resume_schedules_stmt:
   }
 | RESUME SCHEDULES error // SHOW HELP: RESUME SCHEDULES
 
+// %Help: DROP PUBLICATION - remove a publication
+// %Category: DDL
+// %Text: DROP PUBLICATION [IF EXISTS] <name>
+// %SeeAlso: CREATE PUBLICATION
+drop_publication_stmt:
+  DROP PUBLICATION name
+  {
+    $$.val = &tree.DropPublication{Name: tree.Name($3)}
+  }
+| DROP PUBLICATION IF EXISTS name
+  {
+    $$.val = &tree.DropPublication{Name: tree.Name($5), IfExists: true}
+  }
+| DROP PUBLICATION error // SHOW HELP: DROP PUBLICATION
+
+// %Help: DROP SUBSCRIPTION - stop and remove a subscription
+// %Category: CCL
+// %Text: DROP SUBSCRIPTION [IF EXISTS] <name>
+// %SeeAlso: CREATE SUBSCRIPTION
+drop_subscription_stmt:
+  DROP SUBSCRIPTION name
+  {
+    $$.val = &tree.DropSubscription{Name: tree.Name($3)}
+  }
+| DROP SUBSCRIPTION IF EXISTS name
+  {
+    $$.val = &tree.DropSubscription{Name: tree.Name($5), IfExists: true}
+  }
+| DROP SUBSCRIPTION error // SHOW HELP: DROP SUBSCRIPTION
+
+// %Help: DROP SERVER - remove a foreign server
+// %Category: DDL
+// %Text: DROP SERVER [IF EXISTS] <name>
+// %SeeAlso: CREATE SERVER
+drop_server_stmt:
+  DROP SERVER name
+  {
+    $$.val = &tree.DropServer{Name: tree.Name($3)}
+  }
+| DROP SERVER IF EXISTS name
+  {
+    $$.val = &tree.DropServer{Name: tree.Name($5), IfExists: true}
+  }
+| DROP SERVER error // SHOW HELP: DROP SERVER
+
+// %Help: DROP FOREIGN TABLE - remove a foreign table
+// %Category: DDL
+// %Text: DROP FOREIGN TABLE [IF EXISTS] <tablename> [, ...] [CASCADE | RESTRICT]
+// %SeeAlso: CREATE FOREIGN TABLE
+drop_foreign_table_stmt:
+  DROP FOREIGN TABLE table_name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropTable{Names: $4.tableNames(), IfExists: false, DropBehavior: $5.dropBehavior(), Foreign: true}
+  }
+| DROP FOREIGN TABLE IF EXISTS table_name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropTable{Names: $6.tableNames(), IfExists: true, DropBehavior: $7.dropBehavior(), Foreign: true}
+  }
+| DROP FOREIGN TABLE error // SHOW HELP: DROP FOREIGN TABLE
+
 // %Help: DROP SCHEDULES - destroy specified schedules
 // %Category: Misc
 // %Text:
@@ -8507,7 +9824,7 @@ transaction_stmt:
 // START TRANSACTION [ <txnparameter> [[,] ...] ]
 //
 // Transaction parameters:
-//    ISOLATION LEVEL { SNAPSHOT | SERIALIZABLE }
+//    ISOLATION LEVEL { READ COMMITTED | SNAPSHOT | SERIALIZABLE }
 //    PRIORITY { LOW | NORMAL | HIGH }
 //
 // %SeeAlso: COMMIT, ROLLBACK, WEBDOCS/begin-transaction.html
@@ -9704,11 +11021,22 @@ group_by_list:
 // Note the '(' is required as CUBE and ROLLUP rely on setting precedence
 // of CUBE and ROLLUP below that of '(', so that they shift in these rules
 // rather than reducing the conflicting unreserved_keyword rule.
+//
+// The empty grouping set () is parsed as an empty tuple.
 group_by_item:
   a_expr { $$.val = $1.expr() }
-| ROLLUP '(' error { return unimplementedWithIssueDetail(sqllex, 46280, "rollup") }
-| CUBE '(' error { return unimplementedWithIssueDetail(sqllex, 46280, "cube") }
-| GROUPING SETS error { return unimplementedWithIssueDetail(sqllex, 46280, "grouping sets") }
+| ROLLUP '(' expr_list ')'
+  {
+    $$.val = &tree.GroupingSets{Type: tree.RollupGroupingSets, Exprs: $3.exprs()}
+  }
+| CUBE '(' expr_list ')'
+  {
+    $$.val = &tree.GroupingSets{Type: tree.CubeGroupingSets, Exprs: $3.exprs()}
+  }
+| GROUPING SETS '(' group_by_list ')'
+  {
+    $$.val = &tree.GroupingSets{Type: tree.ExplicitGroupingSets, Exprs: $4.exprs()}
+  }
 
 having_clause:
   HAVING a_expr
@@ -11602,7 +12930,10 @@ d_expr:
   {
     $$.val = $2.expr()
   }
-| GROUPING '(' expr_list ')' { return unimplemented(sqllex, "d_expr grouping") }
+| GROUPING '(' expr_list ')'
+  {
+    $$.val = &tree.FuncExpr{Func: tree.WrapFunction("grouping"), Exprs: $3.exprs()}
+  }
 
 func_application:
   func_name '(' ')'
@@ -13141,6 +14472,7 @@ unrestricted_name:
 // "Unreserved" keywords --- available for use as any kind of name.
 unreserved_keyword:
   ABORT
+| ABSOLUTE
 | ACTION
 | ACCESS
 | ADD
@@ -13149,12 +14481,14 @@ unreserved_keyword:
 | AGGREGATE
 | ALTER
 | ALWAYS
+| ASENSITIVE
 | AT
 | ATTRIBUTE
 | AUTOMATIC
 | AVAILABILITY
 | BACKUP
 | BACKUPS
+| BACKWARD
 | BEFORE
 | BEGIN
 | BINARY
@@ -13162,6 +14496,7 @@ unreserved_keyword:
 | BUNDLE
 | BY
 | CACHE
+| CALLED
 | CANCEL
 | CANCELQUERY
 | CASCADE
@@ -13208,10 +14543,13 @@ unreserved_keyword:
 | DELIMITER
 | DESTINATION
 | DETACHED
+| DISABLE
 | DISCARD
 | DOMAIN
 | DOUBLE
 | DROP
+| EACH
+| ENABLE
 | ENCODING
 | ENCRYPTED
 | ENCRYPTION_PASSPHRASE
@@ -13238,6 +14576,7 @@ unreserved_keyword:
 | FOLLOWING
 | FORCE
 | FORCE_INDEX
+| FORWARD
 | FREEZE
 | FUNCTION
 | FUNCTIONS
@@ -13257,9 +14596,11 @@ unreserved_keyword:
 | HEADER
 | HIGH
 | HISTOGRAM
+| HOLD
 | HOUR
 | IDENTITY
 | IMMEDIATE
+| IMMUTABLE
 | IMPORT
 | INCLUDE
 | INCLUDE_DEPRECATED_INTERLEAVES
@@ -13269,6 +14610,8 @@ unreserved_keyword:
 | INDEXES
 | INHERITS
 | INJECT
+| INPUT
+| INSENSITIVE
 | INSERT
 | INTERLEAVE
 | INTO_DB
@@ -13284,6 +14627,7 @@ unreserved_keyword:
 | LANGUAGE
 | LAST
 | LATEST
+| LEAKPROOF
 | LC_COLLATE
 | LC_CTYPE
 | LEASE
@@ -13294,6 +14638,7 @@ unreserved_keyword:
 | LINESTRINGZ
 | LINESTRINGZM
 | LIST
+| LISTEN
 | LOCAL
 | LOCKED
 | LOGIN
@@ -13321,6 +14666,7 @@ unreserved_keyword:
 | MULTIPOLYGONZ
 | MULTIPOLYGONZM
 | MONTH
+| MOVE
 | NAMES
 | NAN
 | NEVER
@@ -13340,6 +14686,7 @@ unreserved_keyword:
 | NOMODIFYCLUSTERSETTING
 | NON_VOTERS
 | NOSQLLOGIN
+| NOTIFY
 | NOVIEWACTIVITY
 | NOVIEWACTIVITYREDACTED
 | NOWAIT
@@ -13364,10 +14711,12 @@ unreserved_keyword:
 | PASSWORD
 | PAUSE
 | PAUSED
+| PERMISSIVE
 | PHYSICAL
 | PLACEMENT
 | PLAN
 | PLANS
+| POLICY
 | POINTM
 | POINTZ
 | POINTZM
@@ -13377,6 +14726,7 @@ unreserved_keyword:
 | PRECEDING
 | PREPARE
 | PRESERVE
+| PRIOR
 | PRIORITY
 | PRIVILEGES
 | PUBLIC
@@ -13397,6 +14747,7 @@ unreserved_keyword:
 | REGIONAL
 | REGIONS
 | REINDEX
+| RELATIVE
 | RELEASE
 | RENAME
 | REPEATABLE
@@ -13406,8 +14757,10 @@ unreserved_keyword:
 | RESTORE
 | RESTRICT
 | RESTRICTED
+| RESTRICTIVE
 | RESUME
 | RETRY
+| RETURNS
 | REVISION_HISTORY
 | REVOKE
 | ROLE
@@ -13428,9 +14781,11 @@ unreserved_keyword:
 | SCATTER
 | SCHEMA
 | SCHEMAS
+| SCROLL
 | SCRUB
 | SEARCH
 | SECOND
+| SECURITY
 | SERIALIZABLE
 | SEQUENCE
 | SEQUENCES
@@ -13452,10 +14807,12 @@ unreserved_keyword:
 | SPLIT
 | SQL
 | SQLLOGIN
+| STABLE
 | START
 | STATEMENTS
 | STATISTICS
 | STDIN
+| STDOUT
 | STORAGE
 | STORE
 | STORED
@@ -13489,6 +14846,7 @@ unreserved_keyword:
 | UNBOUNDED
 | UNCOMMITTED
 | UNKNOWN
+| UNLISTEN
 | UNLOGGED
 | UNSPLIT
 | UNTIL
@@ -13504,9 +14862,11 @@ unreserved_keyword:
 | VIEWACTIVITY
 | VIEWACTIVITYREDACTED
 | VISIBLE
+| VOLATILE
 | VOTERS
 | WITHIN
 | WITHOUT
+| WRAPPER
 | WRITE
 | YEAR
 | ZONE
@@ -28,11 +28,11 @@ func partitionByFromTableDesc(
 }
 
 // partitionByFromTableDescImpl contains the inner logic of partitionByFromTableDesc.
-// We derive the Fields, LIST and RANGE clauses from the table descriptor, recursing
+// We derive the Fields, LIST, RANGE and INTERVAL clauses from the table descriptor, recursing
 // into the subpartitions as required for LIST partitions.
 func partitionByFromTableDescImpl(
 	codec keys.SQLCodec,
-	tableDesc *tabledesc.Mutable,
+	tableDesc catalog.TableDescriptor,
 	idx catalog.Index,
 	part catalog.Partitioning,
 	colOffset int,
@@ -56,6 +56,9 @@ func partitionByFromTableDescImpl(
 	for i := 0; i < part.NumColumns(); i++ {
 		partitionBy.Fields[i] = tree.Name(idx.GetKeyColumnName(colOffset + i))
 	}
+	if interval := part.PartitioningDesc().Interval; interval != nil && colOffset == 0 {
+		partitionBy.Interval = intervalPartitioningToAST(interval)
+	}
 
 	// Copy the LIST of the PARTITION BY clause.
 	a := &rowenc.DatumAlloc{}
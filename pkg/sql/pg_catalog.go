@@ -21,6 +21,8 @@ import (
 	"time"
 	"unicode"
 
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
@@ -33,6 +35,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
@@ -568,6 +571,7 @@ var (
 	relKindView             = tree.NewDString("v")
 	relKindMaterializedView = tree.NewDString("m")
 	relKindSequence         = tree.NewDString("S")
+	relKindForeignTable     = tree.NewDString("f")
 
 	relPersistencePermanent = tree.NewDString("p")
 	relPersistenceTemporary = tree.NewDString("t")
@@ -592,6 +596,9 @@ https://www.postgresql.org/docs/9.5/catalog-pg-class.html`,
 		} else if table.IsSequence() {
 			relKind = relKindSequence
 			relAm = oidZero
+		} else if table.IsForeignTable() {
+			relKind = relKindForeignTable
+			relAm = oidZero
 		}
 		relPersistence := relPersistencePermanent
 		if table.IsTemporary() {
@@ -612,7 +619,7 @@ https://www.postgresql.org/docs/9.5/catalog-pg-class.html`,
 			tree.DNull,                     // reltuples
 			zeroVal,                        // relallvisible
 			oidZero,                        // reltoastrelid
-			tree.MakeDBool(tree.DBool(table.IsPhysicalTable())), // relhasindex
+			tree.MakeDBool(tree.DBool(table.IsPhysicalTable() && !table.IsForeignTable())), // relhasindex
 			tree.DBoolFalse, // relisshared
 			relPersistence,  // relPersistence
 			tree.DBoolFalse, // relistemp
@@ -620,7 +627,7 @@ https://www.postgresql.org/docs/9.5/catalog-pg-class.html`,
 			tree.NewDInt(tree.DInt(len(table.AccessibleColumns()))), // relnatts
 			tree.NewDInt(tree.DInt(len(table.GetChecks()))),         // relchecks
 			tree.DBoolFalse, // relhasoids
-			tree.MakeDBool(tree.DBool(table.IsPhysicalTable())), // relhaspkey
+			tree.MakeDBool(tree.DBool(table.IsPhysicalTable() && !table.IsForeignTable())), // relhaspkey
 			tree.DBoolFalse, // relhasrules
 			tree.DBoolFalse, // relhastriggers
 			tree.DBoolFalse, // relhassubclass
@@ -897,6 +904,7 @@ func populateTableConstraints(
 				}
 				f.WriteString(strings.Join(colNames, ", "))
 				f.WriteByte(')')
+				f.FormatNode(con.Deferrability())
 				if con.UniqueWithoutIndexConstraint.Validity != descpb.ConstraintValidity_Validated {
 					f.WriteString(" NOT VALID")
 				}
@@ -933,13 +941,16 @@ func populateTableConstraints(
 			condef = tree.NewDString(fmt.Sprintf("CHECK ((%s))%s", displayExpr, validity))
 		}
 
+		condeferrable := tree.MakeDBool(tree.DBool(con.Deferrability().IsDeferrable()))
+		condeferred := tree.MakeDBool(tree.DBool(con.Deferrability() == tree.DeferrableInitiallyDeferred))
+
 		if err := addRow(
 			oid,                  // oid
 			dNameOrNull(conName), // conname
 			namespaceOid,         // connamespace
 			contype,              // contype
-			tree.DBoolFalse,      // condeferrable
-			tree.DBoolFalse,      // condeferred
+			condeferrable,        // condeferrable
+			condeferred,          // condeferred
 			tree.MakeDBool(tree.DBool(!con.Unvalidated)), // convalidated
 			tblOid,         // conrelid
 			oidZero,        // contypid
@@ -1604,36 +1615,99 @@ https://www.postgresql.org/docs/9.5/catalog-pg-extension.html`,
 }
 
 var pgCatalogForeignDataWrapperTable = virtualSchemaTable{
-	comment: `foreign data wrappers (empty - feature does not exist)
+	comment: `foreign data wrappers
 https://www.postgresql.org/docs/9.5/catalog-pg-foreign-data-wrapper.html`,
 	schema: vtable.PGCatalogForeignDataWrapper,
 	populate: func(_ context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		// Foreign data wrappers are not supported.
-		return nil
+		// file_fdw is built in, so it is owned by the root user.
+		h := makeOidHasher()
+		return addRow(
+			foreignDataWrapperOid(fileForeignDataWrapper), // oid
+			tree.NewDName(fileForeignDataWrapper),         // fdwname
+			h.UserOid(security.RootUserName()),            // fdwowner
+			oidZero,                                       // fdwhandler
+			oidZero,                                       // fdwvalidator
+			tree.DNull,                                    // fdwacl
+			tree.DNull,                                    // fdwoptions
+		)
 	},
-	unimplemented: true,
 }
 
 var pgCatalogForeignServerTable = virtualSchemaTable{
-	comment: `foreign servers (empty - feature does not exist)
+	comment: `foreign servers
 https://www.postgresql.org/docs/9.5/catalog-pg-foreign-server.html`,
 	schema: vtable.PGCatalogForeignServer,
-	populate: func(_ context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		// Foreign servers are not supported.
-		return nil
+	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		h := makeOidHasher()
+		return forEachDatabaseDesc(ctx, p, dbContext, true, /* requiresPrivileges */
+			func(db catalog.DatabaseDescriptor) error {
+				// Foreign servers are owned by the owner of their database.
+				owner := h.UserOid(db.GetPrivileges().Owner())
+				for _, server := range db.GetForeignServers() {
+					options, err := foreignOptionsArray(server.Options, true /* isServer */)
+					if err != nil {
+						return err
+					}
+					if err := addRow(
+						h.ForeignServerOid(db.GetID(), server.Name), // oid
+						tree.NewDName(server.Name),                  // srvname
+						owner,                                       // srvowner
+						foreignDataWrapperOid(server.Wrapper),       // srvfdw
+						tree.DNull,                                  // srvtype
+						tree.DNull,                                  // srvversion
+						tree.DNull,                                  // srvacl
+						options,                                     // srvoptions
+					); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
 	},
-	unimplemented: true,
 }
 
 var pgCatalogForeignTableTable = virtualSchemaTable{
-	comment: `foreign tables (empty  - feature does not exist)
+	comment: `foreign tables
 https://www.postgresql.org/docs/9.5/catalog-pg-foreign-table.html`,
 	schema: vtable.PGCatalogForeignTable,
-	populate: func(_ context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		// Foreign tables are not supported.
-		return nil
+	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		h := makeOidHasher()
+		return forEachTableDesc(ctx, p, dbContext, hideVirtual,
+			func(db catalog.DatabaseDescriptor, _ string, table catalog.TableDescriptor) error {
+				if !table.IsForeignTable() {
+					return nil
+				}
+				options, err := foreignOptionsArray(table.GetForeign().Options, false /* isServer */)
+				if err != nil {
+					return err
+				}
+				return addRow(
+					tableOid(table.GetID()),                                   // ftrelid
+					h.ForeignServerOid(db.GetID(), table.GetForeign().Server), // ftserver
+					options, // ftoptions
+				)
+			})
 	},
-	unimplemented: true,
+}
+
+// foreignOptionsArray returns the options of a foreign server or table as an
+// array of key=value strings. The credentials in the location URIs of servers
+// are redacted; the locations of tables are paths relative to them.
+func foreignOptionsArray(options []descpb.ForeignOption, isServer bool) (tree.Datum, error) {
+	arr := tree.NewDArray(types.String)
+	for _, opt := range options {
+		value := opt.Value
+		if isServer && opt.Key == foreignOptionLocation {
+			var err error
+			if value, err = cloud.SanitizeExternalStorageURI(value, nil /* extraParams */); err != nil {
+				return nil, err
+			}
+		}
+		if err := arr.Append(tree.NewDString(opt.Key + "=" + value)); err != nil {
+			return nil, err
+		}
+	}
+	return arr, nil
 }
 
 func makeZeroedOidVector(size int) (tree.Datum, error) {
@@ -1915,14 +1989,48 @@ https://www.postgresql.org/docs/9.5/catalog-pg-language.html`,
 	unimplemented: true,
 }
 
+var (
+	locktypeAdvisory  = tree.NewDString("advisory")
+	lockModeExclusive = tree.NewDString("ExclusiveLock")
+)
+
 var pgCatalogLocksTable = virtualSchemaTable{
-	comment: `locks held by active processes (empty - feature does not exist)
+	comment: `locks held by active processes (only advisory locks)
 https://www.postgresql.org/docs/9.6/view-pg-locks.html`,
 	schema: vtable.PGCatalogLocks,
 	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		lm := p.ExecCfg().AdvisoryLockManager
+		if lm == nil || !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.AdvisoryLocksTable) {
+			return nil
+		}
+		locks, err := lm.List(ctx)
+		if err != nil {
+			return err
+		}
+		for _, l := range locks {
+			pid := BytesToClusterWideID(l.SessionID).BackendPID()
+			if err := addRow(
+				locktypeAdvisory,                        // locktype
+				tree.NewDOid(tree.DInt(l.DatabaseID)),   // database
+				tree.DNull,                              // relation
+				tree.DNull,                              // page
+				tree.DNull,                              // tuple
+				tree.DNull,                              // virtualxid
+				tree.DNull,                              // transactionid
+				tree.NewDOid(tree.DInt(l.Key.ClassID)),  // classid
+				tree.NewDOid(tree.DInt(l.Key.ObjID)),    // objid
+				tree.NewDInt(tree.DInt(l.Key.ObjSubID)), // objsubid
+				tree.NewDString(l.SessionIDString()),    // virtualtransaction
+				tree.NewDInt(tree.DInt(pid)),            // pid
+				lockModeExclusive,                       // mode
+				tree.MakeDBool(tree.DBool(l.Granted)),   // granted
+				tree.DBoolFalse,                         // fastpath
+			); err != nil {
+				return err
+			}
+		}
 		return nil
 	},
-	unimplemented: true,
 }
 
 var pgCatalogMatViewsTable = virtualSchemaTable{
@@ -3071,12 +3179,45 @@ https://www.postgresql.org/docs/9.5/catalog-pg-shseclabel.html`,
 }
 
 var pgCatalogPublicationRelTable = virtualSchemaTable{
-	comment: "pg_publication_rel was created for compatibility and is currently unimplemented",
-	schema:  vtable.PgCatalogPublicationRel,
-	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		return nil
+	comment: `tables explicitly added to publications
+https://www.postgresql.org/docs/13/catalog-pg-publication-rel.html`,
+	schema: vtable.PgCatalogPublicationRel,
+	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		h := makeOidHasher()
+		return forEachDatabaseDesc(ctx, p, dbContext, true, /* requiresPrivileges */
+			func(db catalog.DatabaseDescriptor) error {
+				pubs := db.GetPublications()
+				if len(pubs) == 0 {
+					return nil
+				}
+				// Skip the tables which were dropped since they were added to a
+				// publication.
+				var tableIDs catalog.DescriptorIDSet
+				if err := forEachTableDesc(ctx, p, db, hideVirtual,
+					func(_ catalog.DatabaseDescriptor, _ string, table catalog.TableDescriptor) error {
+						tableIDs.Add(table.GetID())
+						return nil
+					}); err != nil {
+					return err
+				}
+				for _, pub := range pubs {
+					pubOid := h.PublicationOid(db.GetID(), pub.Name)
+					for _, id := range pub.TableIDs {
+						if !tableIDs.Contains(id) {
+							continue
+						}
+						if err := addRow(
+							h.PublicationRelOid(db.GetID(), pub.Name, id), // oid
+							pubOid,       // prpubid
+							tableOid(id), // prrelid
+						); err != nil {
+							return err
+						}
+					}
+				}
+				return nil
+			})
 	},
-	unimplemented: true,
 }
 
 var pgCatalogConfigTable = virtualSchemaTable{
@@ -3161,12 +3302,75 @@ var pgCatalogTimezoneNamesTable = virtualSchemaTable{
 }
 
 var pgCatalogPublicationTablesTable = virtualSchemaTable{
-	comment: "pg_publication_tables was created for compatibility and is currently unimplemented",
-	schema:  vtable.PgCatalogPublicationTables,
-	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		return nil
+	comment: `tables published by publications
+https://www.postgresql.org/docs/13/view-pg-publication-tables.html`,
+	schema: vtable.PgCatalogPublicationTables,
+	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		return forEachDatabaseDesc(ctx, p, dbContext, true, /* requiresPrivileges */
+			func(db catalog.DatabaseDescriptor) error {
+				pubs := db.GetPublications()
+				if len(pubs) == 0 {
+					return nil
+				}
+				// Tables which were dropped since they were added to a publication
+				// are skipped, as they are not iterated over.
+				return forEachTableDesc(ctx, p, db, hideVirtual,
+					func(db catalog.DatabaseDescriptor, scName string, table catalog.TableDescriptor) error {
+						if !isPublishableTable(table) {
+							return nil
+						}
+						for i := range pubs {
+							if !publicationContainsTable(&pubs[i], table.GetID()) {
+								continue
+							}
+							if err := addRow(
+								tree.NewDName(pubs[i].Name),    // pubname
+								tree.NewDName(scName),          // schemaname
+								tree.NewDName(table.GetName()), // tablename
+							); err != nil {
+								return err
+							}
+						}
+						return nil
+					})
+			})
 	},
-	unimplemented: true,
+}
+
+// ReplicationOriginsTableName is the name of the table of the public schema in
+// which subscriptions record the commit timestamps, in the source cluster, of
+// the rows they apply to the tables of its database. It isn't published, as
+// its rows are specific to the database.
+const ReplicationOriginsTableName = "crdb_replication_origins"
+
+// isReplicationOriginsTable returns whether the table is the one in which
+// subscriptions record the origins of the rows they apply.
+func isReplicationOriginsTable(table catalog.TableDescriptor) bool {
+	return table.GetParentSchemaID() == keys.PublicSchemaID &&
+		table.GetName() == ReplicationOriginsTableName
+}
+
+// isPublishableTable returns whether the table can belong to a publication.
+func isPublishableTable(table catalog.TableDescriptor) bool {
+	return table.IsTable() && !table.IsVirtualTable() && !table.IsTemporary() &&
+		!isReplicationOriginsTable(table)
+}
+
+// publicationContainsTable returns whether the publication contains the table
+// with the given ID. Publications of all tables contain every publishable
+// table.
+func publicationContainsTable(
+	pub *descpb.DatabaseDescriptor_Publication, tableID descpb.ID,
+) bool {
+	if pub.AllTables {
+		return true
+	}
+	for _, id := range pub.TableIDs {
+		if id == tableID {
+			return true
+		}
+	}
+	return false
 }
 
 var pgCatalogUserMappingsTable = virtualSchemaTable{
@@ -3206,12 +3410,33 @@ var pgCatalogShadowTable = virtualSchemaTable{
 }
 
 var pgCatalogPublicationTable = virtualSchemaTable{
-	comment: "pg_publication was created for compatibility and is currently unimplemented",
-	schema:  vtable.PgCatalogPublication,
-	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		return nil
+	comment: `publications
+https://www.postgresql.org/docs/13/catalog-pg-publication.html`,
+	schema: vtable.PgCatalogPublication,
+	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		h := makeOidHasher()
+		return forEachDatabaseDesc(ctx, p, dbContext, true, /* requiresPrivileges */
+			func(db catalog.DatabaseDescriptor) error {
+				// Publications are owned by the owner of their database.
+				owner := h.UserOid(db.GetPrivileges().Owner())
+				for _, pub := range db.GetPublications() {
+					if err := addRow(
+						tree.DBoolTrue,                            // pubupdate
+						h.PublicationOid(db.GetID(), pub.Name),    // oid
+						tree.MakeDBool(tree.DBool(pub.AllTables)), // puballtables
+						tree.DBoolTrue,                            // pubdelete
+						tree.DBoolTrue,                            // pubinsert
+						tree.NewDName(pub.Name),                   // pubname
+						owner,                                     // pubowner
+						tree.DBoolFalse,                           // pubtruncate
+						tree.DBoolFalse,                           // pubviaroot
+					); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
 	},
-	unimplemented: true,
 }
 
 var pgCatalogGroupTable = virtualSchemaTable{
@@ -3224,12 +3449,28 @@ var pgCatalogGroupTable = virtualSchemaTable{
 }
 
 var pgCatalogCursorsTable = virtualSchemaTable{
-	comment: "pg_cursors was created for compatibility and is currently unimplemented",
-	schema:  vtable.PgCatalogCursors,
+	comment: `open cursors
+https://www.postgresql.org/docs/current/view-pg-cursors.html`,
+	schema: vtable.PgCatalogCursors,
 	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		for name, c := range p.sqlCursors.list() {
+			ts, err := tree.MakeDTimestampTZ(c.created, time.Microsecond)
+			if err != nil {
+				return err
+			}
+			if err := addRow(
+				tree.DBoolFalse,       // is_scrollable
+				tree.NewDString(name), // name
+				tree.NewDString(c.statement),
+				ts,              // creation_time
+				tree.DBoolFalse, // is_binary
+				tree.DBoolFalse, // is_holdable
+			); err != nil {
+				return err
+			}
+		}
 		return nil
 	},
-	unimplemented: true,
 }
 
 var pgCatalogTsParserTable = virtualSchemaTable{
@@ -3368,12 +3609,39 @@ var pgCatalogLargeobjectTable = virtualSchemaTable{
 }
 
 var pgCatalogReplicationSlotsTable = virtualSchemaTable{
-	comment: "pg_replication_slots was created for compatibility and is currently unimplemented",
-	schema:  vtable.PgCatalogReplicationSlots,
-	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		return nil
+	comment: `replication slots
+https://www.postgresql.org/docs/13/view-pg-replication-slots.html`,
+	schema: vtable.PgCatalogReplicationSlots,
+	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		return forEachDatabaseDesc(ctx, p, dbContext, true, /* requiresPrivileges */
+			func(db catalog.DatabaseDescriptor) error {
+				for _, slot := range db.GetReplicationSlots() {
+					// The changes are read from rangefeeds rather than retained
+					// logs, so the restart position of a slot is its confirmed
+					// position.
+					lsn := tree.NewDString(pgrepl.LSN(slot.ConfirmedFlushLSN).String())
+					if err := addRow(
+						tree.DNull,                  // safe_wal_size
+						tree.NewDString("reserved"), // wal_status
+						tree.NewDName(slot.Plugin),  // plugin
+						lsn,                         // restart_lsn
+						tree.DNull,                  // xmin
+						lsn,                         // confirmed_flush_lsn
+						tree.NewDName(db.GetName()), // database
+						dbOid(db.GetID()),           // datoid
+						tree.DBoolFalse,             // active
+						tree.DNull,                  // catalog_xmin
+						tree.NewDName(slot.Name),    // slot_name
+						tree.DNull,                  // active_pid
+						tree.NewDString("logical"),  // slot_type
+						tree.DBoolFalse,             // temporary
+					); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
 	},
-	unimplemented: true,
 }
 
 var pgCatalogInitPrivsTable = virtualSchemaTable{
@@ -4007,16 +4275,15 @@ func init() {
 // are 32 bits and that they are stable across accesses.
 //
 // The type has a few layers of methods:
-// - write<go_type> methods write concrete types to the underlying running hash.
-// - write<db_object> methods account for single database objects like TableDescriptors
-//   or IndexDescriptors in the running hash. These methods aim to write information
-//   that would uniquely fingerprint the object to the hash using the first layer of
-//   methods.
-// - <DB_Object>Oid methods use the second layer of methods to construct a unique
-//   object identifier for the provided database object. This object identifier will
-//   be returned as a *tree.DInt, and the running hash will be reset. These are the
-//   only methods that are part of the oidHasher's external facing interface.
-//
+//   - write<go_type> methods write concrete types to the underlying running hash.
+//   - write<db_object> methods account for single database objects like TableDescriptors
+//     or IndexDescriptors in the running hash. These methods aim to write information
+//     that would uniquely fingerprint the object to the hash using the first layer of
+//     methods.
+//   - <DB_Object>Oid methods use the second layer of methods to construct a unique
+//     object identifier for the provided database object. This object identifier will
+//     be returned as a *tree.DInt, and the running hash will be reset. These are the
+//     only methods that are part of the oidHasher's external facing interface.
 type oidHasher struct {
 	h hash.Hash32
 }
@@ -4077,6 +4344,9 @@ const (
 	enumEntryTypeTag
 	rewriteTypeTag
 	dbSchemaRoleTypeTag
+	publicationTypeTag
+	publicationRelTypeTag
+	foreignServerTypeTag
 )
 
 func (h oidHasher) writeTypeTag(tag oidTypeTag) {
@@ -4259,6 +4529,42 @@ func (h oidHasher) DBSchemaRoleOid(
 	return h.getOid()
 }
 
+// PublicationOid creates an OID for the publication of a database with the
+// given name.
+func (h oidHasher) PublicationOid(dbID descpb.ID, pubName string) *tree.DOid {
+	h.writeTypeTag(publicationTypeTag)
+	h.writeDB(dbID)
+	h.writeStr(pubName)
+	return h.getOid()
+}
+
+// PublicationRelOid creates an OID for the membership of a table in a
+// publication.
+func (h oidHasher) PublicationRelOid(
+	dbID descpb.ID, pubName string, tableID descpb.ID,
+) *tree.DOid {
+	h.writeTypeTag(publicationRelTypeTag)
+	h.writeDB(dbID)
+	h.writeStr(pubName)
+	h.writeTable(tableID)
+	return h.getOid()
+}
+
+// ForeignServerOid creates an OID for the foreign server of a database with
+// the given name.
+func (h oidHasher) ForeignServerOid(dbID descpb.ID, serverName string) *tree.DOid {
+	h.writeTypeTag(foreignServerTypeTag)
+	h.writeDB(dbID)
+	h.writeStr(serverName)
+	return h.getOid()
+}
+
+// foreignDataWrapperOid returns the OID of the foreign data wrapper with the
+// given name.
+func foreignDataWrapperOid(name string) *tree.DOid {
+	return stringOid(name)
+}
+
 func tableOid(id descpb.ID) *tree.DOid {
 	return tree.NewDOid(tree.DInt(id))
 }
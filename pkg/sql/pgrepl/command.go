@@ -0,0 +1,381 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgrepl
+
+import (
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+)
+
+// Parse parses a command of the streaming replication protocol, which
+// replication connections can run with simple queries besides SQL statements.
+// It returns nil if the query isn't a replication command, in which case it
+// should be parsed as SQL.
+//
+// See https://www.postgresql.org/docs/current/protocol-replication.html.
+func Parse(sql string) (tree.Statement, error) {
+	p := cmdParser{s: sql}
+	first, err := p.next()
+	if err != nil || first.kind != tokWord {
+		// Let the SQL parser report the errors of queries which don't start like
+		// a replication command.
+		return nil, nil //nolint:returnerrcheck
+	}
+	var stmt tree.Statement
+	switch strings.ToUpper(first.s) {
+	case "IDENTIFY_SYSTEM":
+		stmt = &tree.IdentifySystem{}
+	case "CREATE_REPLICATION_SLOT":
+		stmt, err = p.parseCreateReplicationSlot()
+	case "DROP_REPLICATION_SLOT":
+		stmt, err = p.parseDropReplicationSlot()
+	case "START_REPLICATION":
+		stmt, err = p.parseStartReplication()
+	case "TIMELINE_HISTORY", "BASE_BACKUP", "READ_REPLICATION_SLOT":
+		return nil, unimplemented.Newf("physical-replication",
+			"replication command %s is not supported", strings.ToUpper(first.s))
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectEnd(); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// parseCreateReplicationSlot parses:
+//
+//	CREATE_REPLICATION_SLOT name [TEMPORARY] LOGICAL plugin
+//	  [EXPORT_SNAPSHOT | NOEXPORT_SNAPSHOT | USE_SNAPSHOT | (SNAPSHOT 'mode')]
+func (p *cmdParser) parseCreateReplicationSlot() (tree.Statement, error) {
+	slot, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	n := &tree.CreateReplicationSlot{Slot: slot}
+	if p.acceptKeyword("TEMPORARY") {
+		n.Temporary = true
+	}
+	if p.acceptKeyword("PHYSICAL") {
+		return nil, unimplemented.New("physical-replication",
+			"physical replication slots are not supported")
+	}
+	if err := p.expectKeyword("LOGICAL"); err != nil {
+		return nil, err
+	}
+	if n.Plugin, err = p.expectName(); err != nil {
+		return nil, err
+	}
+	switch {
+	case p.acceptKeyword("EXPORT_SNAPSHOT"):
+		n.Snapshot = "export"
+	case p.acceptKeyword("NOEXPORT_SNAPSHOT"):
+		n.Snapshot = "nothing"
+	case p.acceptKeyword("USE_SNAPSHOT"):
+		n.Snapshot = "use"
+	case p.acceptKeyword("TWO_PHASE"):
+		return nil, unimplemented.New("two-phase-replication",
+			"two-phase decoding is not supported")
+	case p.accept(tokLParen):
+		opts, err := p.parseOptions()
+		if err != nil {
+			return nil, err
+		}
+		for _, opt := range opts {
+			switch opt.Key {
+			case "snapshot":
+				if opt.Value == nil {
+					return nil, p.syntaxErrorf("option %q requires a value", opt.Key)
+				}
+				mode := strings.ToLower(opt.Value.(*tree.StrVal).RawString())
+				if mode != "export" && mode != "nothing" && mode != "use" {
+					return nil, p.syntaxErrorf(
+						"unrecognized value for CREATE_REPLICATION_SLOT option \"snapshot\": %q", mode)
+				}
+				n.Snapshot = mode
+			case "two_phase":
+				return nil, unimplemented.New("two-phase-replication",
+					"two-phase decoding is not supported")
+			default:
+				return nil, p.syntaxErrorf("unrecognized option %q", opt.Key)
+			}
+		}
+	}
+	return n, nil
+}
+
+// parseDropReplicationSlot parses:
+//
+//	DROP_REPLICATION_SLOT name [WAIT]
+func (p *cmdParser) parseDropReplicationSlot() (tree.Statement, error) {
+	slot, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	return &tree.DropReplicationSlot{Slot: slot, Wait: p.acceptKeyword("WAIT")}, nil
+}
+
+// parseStartReplication parses:
+//
+//	START_REPLICATION SLOT name LOGICAL lsn [(option ['value'], ...)]
+func (p *cmdParser) parseStartReplication() (tree.Statement, error) {
+	if !p.acceptKeyword("SLOT") {
+		return nil, unimplemented.New("physical-replication",
+			"physical replication is not supported")
+	}
+	slot, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	if p.acceptKeyword("PHYSICAL") {
+		return nil, unimplemented.New("physical-replication",
+			"physical replication is not supported")
+	}
+	if err := p.expectKeyword("LOGICAL"); err != nil {
+		return nil, err
+	}
+	tok, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if tok.kind != tokWord {
+		return nil, p.syntaxErrorf("expected LSN")
+	}
+	if _, err := ParseLSN(tok.s); err != nil {
+		return nil, err
+	}
+	n := &tree.StartReplication{Slot: slot, StartLSN: tok.s}
+	if p.accept(tokLParen) {
+		if n.Options, err = p.parseOptions(); err != nil {
+			return nil, err
+		}
+	}
+	return n, nil
+}
+
+// parseOptions parses a list of options, after its opening parenthesis. The
+// option names are identifiers and their values are optional strings.
+func (p *cmdParser) parseOptions() (tree.KVOptions, error) {
+	var opts tree.KVOptions
+	for {
+		key, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		opt := tree.KVOption{Key: key}
+		tok, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		if tok.kind == tokString {
+			opt.Value = tree.NewStrVal(tok.s)
+			if tok, err = p.next(); err != nil {
+				return nil, err
+			}
+		}
+		opts = append(opts, opt)
+		switch tok.kind {
+		case tokComma:
+		case tokRParen:
+			return opts, nil
+		default:
+			return nil, p.syntaxErrorf("expected \",\" or \")\"")
+		}
+	}
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	// tokWord is an unquoted identifier or keyword, or an LSN.
+	tokWord
+	tokQuotedIdent
+	tokString
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type token struct {
+	kind tokenKind
+	s    string
+}
+
+// cmdParser parses replication commands. Their syntax is much simpler than the
+// one of SQL, and their keywords aren't keywords of SQL, so they are parsed by
+// hand rather than by the SQL grammar.
+type cmdParser struct {
+	s   string
+	pos int
+	// peeked is the next token, if it was peeked at.
+	peeked *token
+}
+
+func (p *cmdParser) syntaxErrorf(format string, args ...interface{}) error {
+	return pgerror.Newf(pgcode.Syntax, "syntax error in replication command: "+format, args...)
+}
+
+func (p *cmdParser) peek() (token, error) {
+	if p.peeked == nil {
+		tok, err := p.scan()
+		if err != nil {
+			return token{}, err
+		}
+		p.peeked = &tok
+	}
+	return *p.peeked, nil
+}
+
+func (p *cmdParser) next() (token, error) {
+	tok, err := p.peek()
+	p.peeked = nil
+	return tok, err
+}
+
+// accept consumes the next token if it is of the given kind.
+func (p *cmdParser) accept(kind tokenKind) bool {
+	if tok, err := p.peek(); err != nil || tok.kind != kind {
+		return false
+	}
+	p.peeked = nil
+	return true
+}
+
+// acceptKeyword consumes the next token if it is the given keyword.
+func (p *cmdParser) acceptKeyword(keyword string) bool {
+	if tok, err := p.peek(); err != nil || tok.kind != tokWord || !strings.EqualFold(tok.s, keyword) {
+		return false
+	}
+	p.peeked = nil
+	return true
+}
+
+func (p *cmdParser) expectKeyword(keyword string) error {
+	if !p.acceptKeyword(keyword) {
+		return p.syntaxErrorf("expected %s", keyword)
+	}
+	return nil
+}
+
+// expectName consumes an identifier. Unquoted identifiers are lowercased.
+func (p *cmdParser) expectName() (tree.Name, error) {
+	tok, err := p.next()
+	if err != nil {
+		return "", err
+	}
+	switch tok.kind {
+	case tokWord:
+		return tree.Name(strings.ToLower(tok.s)), nil
+	case tokQuotedIdent:
+		return tree.Name(tok.s), nil
+	default:
+		return "", p.syntaxErrorf("expected identifier")
+	}
+}
+
+func (p *cmdParser) expectEnd() error {
+	if tok, err := p.next(); err != nil {
+		return err
+	} else if tok.kind != tokEOF {
+		return p.syntaxErrorf("unexpected trailing input")
+	}
+	return nil
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || c == '/' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') ||
+		(c >= '0' && c <= '9')
+}
+
+// scan returns the next token of the input. A trailing semicolon is ignored.
+func (p *cmdParser) scan() (token, error) {
+	for p.pos < len(p.s) && isSpace(p.s[p.pos]) {
+		p.pos++
+	}
+	if p.pos == len(p.s) {
+		return token{kind: tokEOF}, nil
+	}
+	c := p.s[p.pos]
+	switch {
+	case c == '(':
+		p.pos++
+		return token{kind: tokLParen}, nil
+	case c == ')':
+		p.pos++
+		return token{kind: tokRParen}, nil
+	case c == ',':
+		p.pos++
+		return token{kind: tokComma}, nil
+	case c == ';':
+		p.pos++
+		for p.pos < len(p.s) && isSpace(p.s[p.pos]) {
+			p.pos++
+		}
+		if p.pos < len(p.s) {
+			return token{}, p.syntaxErrorf("unexpected input after \";\"")
+		}
+		return token{kind: tokEOF}, nil
+	case c == '\'' || c == '"':
+		s, err := p.scanQuoted(c)
+		if err != nil {
+			return token{}, err
+		}
+		if c == '"' {
+			return token{kind: tokQuotedIdent, s: s}, nil
+		}
+		return token{kind: tokString, s: s}, nil
+	case isWordChar(c):
+		start := p.pos
+		for p.pos < len(p.s) && isWordChar(p.s[p.pos]) {
+			p.pos++
+		}
+		return token{kind: tokWord, s: p.s[start:p.pos]}, nil
+	default:
+		return token{}, p.syntaxErrorf("unexpected character %q", c)
+	}
+}
+
+// scanQuoted scans a string or identifier quoted with the given character, in
+// which the quote is escaped by doubling it.
+func (p *cmdParser) scanQuoted(quote byte) (string, error) {
+	var b strings.Builder
+	p.pos++
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		p.pos++
+		if c != quote {
+			b.WriteByte(c)
+			continue
+		}
+		if p.pos < len(p.s) && p.s[p.pos] == quote {
+			b.WriteByte(quote)
+			p.pos++
+			continue
+		}
+		return b.String(), nil
+	}
+	if quote == '"' {
+		return "", p.syntaxErrorf("unterminated quoted identifier")
+	}
+	return "", p.syntaxErrorf("unterminated quoted string")
+}
@@ -0,0 +1,96 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgrepl
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testCases := []struct {
+		sql      string
+		expected tree.Statement
+		err      string
+	}{
+		{sql: `SELECT 1`},
+		{sql: `'IDENTIFY_SYSTEM'`},
+		{sql: `identify_system`, expected: &tree.IdentifySystem{}},
+		{sql: `IDENTIFY_SYSTEM;`, expected: &tree.IdentifySystem{}},
+		{sql: `IDENTIFY_SYSTEM; SELECT 1`, err: `unexpected input after ";"`},
+		{sql: `IDENTIFY_SYSTEM foo`, err: `unexpected trailing input`},
+
+		{
+			sql:      `CREATE_REPLICATION_SLOT Slot LOGICAL pgoutput`,
+			expected: &tree.CreateReplicationSlot{Slot: "slot", Plugin: "pgoutput"},
+		},
+		{
+			sql: `CREATE_REPLICATION_SLOT "Slot" TEMPORARY LOGICAL pgoutput NOEXPORT_SNAPSHOT`,
+			expected: &tree.CreateReplicationSlot{
+				Slot: "Slot", Temporary: true, Plugin: "pgoutput", Snapshot: "nothing",
+			},
+		},
+		{
+			sql: `CREATE_REPLICATION_SLOT s LOGICAL pgoutput (SNAPSHOT 'export')`,
+			expected: &tree.CreateReplicationSlot{
+				Slot: "s", Plugin: "pgoutput", Snapshot: "export",
+			},
+		},
+		{
+			sql: `CREATE_REPLICATION_SLOT s LOGICAL pgoutput (snapshot 'foo')`,
+			err: `unrecognized value for CREATE_REPLICATION_SLOT option "snapshot": "foo"`,
+		},
+		{sql: `CREATE_REPLICATION_SLOT s PHYSICAL`, err: `physical replication slots are not supported`},
+		{sql: `CREATE_REPLICATION_SLOT s pgoutput`, err: `expected LOGICAL`},
+
+		{sql: `DROP_REPLICATION_SLOT s`, expected: &tree.DropReplicationSlot{Slot: "s"}},
+		{sql: `DROP_REPLICATION_SLOT s WAIT`, expected: &tree.DropReplicationSlot{Slot: "s", Wait: true}},
+
+		{
+			sql:      `START_REPLICATION SLOT s LOGICAL 0/0`,
+			expected: &tree.StartReplication{Slot: "s", StartLSN: "0/0"},
+		},
+		{
+			sql: `START_REPLICATION SLOT s LOGICAL 16/B374D848 (proto_version '1', publication_names '"P", q')`,
+			expected: &tree.StartReplication{
+				Slot:     "s",
+				StartLSN: "16/B374D848",
+				Options: tree.KVOptions{
+					{Key: "proto_version", Value: tree.NewStrVal("1")},
+					{Key: "publication_names", Value: tree.NewStrVal(`"P", q`)},
+				},
+			},
+		},
+		{sql: `START_REPLICATION SLOT s LOGICAL 16`, err: `invalid input syntax for type pg_lsn: "16"`},
+		{sql: `START_REPLICATION 0/0`, err: `physical replication is not supported`},
+		{sql: `START_REPLICATION SLOT s LOGICAL 0/0 (a 'b'`, err: `expected "," or ")"`},
+		{sql: `START_REPLICATION SLOT s LOGICAL 0/0 (a 'b)`, err: `unterminated quoted string`},
+
+		{sql: `BASE_BACKUP`, err: `replication command BASE_BACKUP is not supported`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.sql, func(t *testing.T) {
+			stmt, err := Parse(tc.sql)
+			if tc.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, stmt)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgrepl
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+)
+
+// LSN is a PostgreSQL log sequence number, i.e. a position in the stream of
+// changes of a replication slot.
+//
+// The LSN of a change is the wall time of its commit timestamp, in
+// nanoseconds. The changes committed at the same wall time are streamed as a
+// single transaction, so that an LSN identifies a position between two
+// transactions and streaming can resume from any confirmed LSN without losing
+// or repeating changes.
+type LSN uint64
+
+// MakeLSN returns the LSN of the changes committed at the given timestamp.
+func MakeLSN(ts hlc.Timestamp) LSN {
+	return LSN(ts.WallTime)
+}
+
+// Timestamp returns the highest timestamp whose changes are at or before the
+// LSN. Streaming from an LSN emits the changes committed after it.
+func (l LSN) Timestamp() hlc.Timestamp {
+	return hlc.Timestamp{WallTime: int64(l), Logical: math.MaxInt32}
+}
+
+// String formats the LSN as PostgreSQL does, e.g. 16/B374D848.
+func (l LSN) String() string {
+	return fmt.Sprintf("%X/%X", uint64(l)>>32, uint32(l))
+}
+
+// ParseLSN parses an LSN formatted as PostgreSQL does.
+func ParseLSN(s string) (LSN, error) {
+	i := strings.IndexByte(s, '/')
+	if i < 0 {
+		return 0, pgerror.Newf(pgcode.InvalidTextRepresentation,
+			"invalid input syntax for type pg_lsn: %q", s)
+	}
+	hi, err := strconv.ParseUint(s[:i], 16, 32)
+	if err != nil {
+		return 0, pgerror.Newf(pgcode.InvalidTextRepresentation,
+			"invalid input syntax for type pg_lsn: %q", s)
+	}
+	lo, err := strconv.ParseUint(s[i+1:], 16, 32)
+	if err != nil {
+		return 0, pgerror.Newf(pgcode.InvalidTextRepresentation,
+			"invalid input syntax for type pg_lsn: %q", s)
+	}
+	return LSN(hi<<32 | lo), nil
+}
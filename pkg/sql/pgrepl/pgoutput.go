@@ -0,0 +1,350 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgrepl
+
+import (
+	"encoding/binary"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/lex"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq/oid"
+)
+
+// PgoutputPlugin is the name of the output plugin of PostgreSQL's logical
+// replication, which is the only one supported.
+const PgoutputPlugin = "pgoutput"
+
+// The types of the messages sent in CopyData messages over replication
+// connections.
+const (
+	// XLogDataMsg holds the changes of the stream.
+	XLogDataMsg byte = 'w'
+	// KeepaliveMsg is sent by the server.
+	KeepaliveMsg byte = 'k'
+	// StandbyStatusUpdateMsg is sent by the client to report its progress.
+	StandbyStatusUpdateMsg byte = 'r'
+	// HotStandbyFeedbackMsg is sent by physical replication clients.
+	HotStandbyFeedbackMsg byte = 'h'
+)
+
+// pgEpoch is the epoch of the timestamps of the replication protocol.
+var pgEpoch = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+func appendTime(b []byte, t time.Time) []byte {
+	return appendInt64(b, t.Sub(pgEpoch).Microseconds())
+}
+
+func appendInt16(b []byte, v int16) []byte {
+	return append(b, byte(v>>8), byte(v))
+}
+
+func appendInt32(b []byte, v int32) []byte {
+	return append(b, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+func appendInt64(b []byte, v int64) []byte {
+	return appendInt32(appendInt32(b, int32(v>>32)), int32(v))
+}
+
+func appendString(b []byte, s string) []byte {
+	return append(append(b, s...), 0)
+}
+
+// AppendXLogData appends the header of an XLogData message, which must be
+// followed by a pgoutput message.
+func AppendXLogData(b []byte, start, end LSN, sendTime time.Time) []byte {
+	b = append(b, XLogDataMsg)
+	b = appendInt64(b, int64(start))
+	b = appendInt64(b, int64(end))
+	return appendTime(b, sendTime)
+}
+
+// AppendKeepalive appends a primary keepalive message.
+func AppendKeepalive(b []byte, end LSN, sendTime time.Time, replyRequested bool) []byte {
+	b = append(b, KeepaliveMsg)
+	b = appendInt64(b, int64(end))
+	b = appendTime(b, sendTime)
+	if replyRequested {
+		return append(b, 1)
+	}
+	return append(b, 0)
+}
+
+// StandbyStatusUpdate is the progress reported by a client.
+type StandbyStatusUpdate struct {
+	WriteLSN, FlushLSN, ApplyLSN LSN
+	ReplyRequested               bool
+}
+
+// ParseStandbyStatusUpdate parses a standby status update message, including
+// its type byte.
+func ParseStandbyStatusUpdate(data []byte) (StandbyStatusUpdate, error) {
+	const size = 1 + 8 + 8 + 8 + 8 + 1
+	if len(data) != size || data[0] != StandbyStatusUpdateMsg {
+		return StandbyStatusUpdate{}, pgerror.Newf(pgcode.ProtocolViolation,
+			"invalid standby status update message")
+	}
+	return StandbyStatusUpdate{
+		WriteLSN:       LSN(binary.BigEndian.Uint64(data[1:])),
+		FlushLSN:       LSN(binary.BigEndian.Uint64(data[9:])),
+		ApplyLSN:       LSN(binary.BigEndian.Uint64(data[17:])),
+		ReplyRequested: data[33] != 0,
+	}, nil
+}
+
+// AppendBegin appends a pgoutput Begin message. There are no transaction IDs
+// in CockroachDB, so the xid of a transaction is derived from its LSN, and
+// should only be used by clients to tell transactions apart.
+func AppendBegin(b []byte, finalLSN LSN, commitTime time.Time) []byte {
+	b = append(b, 'B')
+	b = appendInt64(b, int64(finalLSN))
+	b = appendTime(b, commitTime)
+	return appendInt32(b, int32(finalLSN))
+}
+
+// AppendCommit appends a pgoutput Commit message.
+func AppendCommit(b []byte, commitLSN, endLSN LSN, commitTime time.Time) []byte {
+	b = append(b, 'C', 0 /* flags */)
+	b = appendInt64(b, int64(commitLSN))
+	b = appendInt64(b, int64(endLSN))
+	return appendTime(b, commitTime)
+}
+
+// Relation describes a published table. The changes of a table are preceded
+// by its Relation message, unless it was sent since the table last changed.
+type Relation struct {
+	ID        oid.Oid
+	Namespace string
+	Name      string
+	Columns   []RelationColumn
+}
+
+// RelationColumn describes a column of a Relation.
+type RelationColumn struct {
+	Name string
+	// Key is set for the columns of the primary key, which identify the rows in
+	// Update and Delete messages.
+	Key          bool
+	TypeOID      oid.Oid
+	TypeModifier int32
+}
+
+// AppendRelation appends a pgoutput Relation message. The replica identity of
+// the relations is always the default one, i.e. their primary key.
+func AppendRelation(b []byte, rel *Relation) []byte {
+	b = append(b, 'R')
+	b = appendInt32(b, int32(rel.ID))
+	b = appendString(b, rel.Namespace)
+	b = appendString(b, rel.Name)
+	b = append(b, 'd')
+	b = appendInt16(b, int16(len(rel.Columns)))
+	for i := range rel.Columns {
+		col := &rel.Columns[i]
+		if col.Key {
+			b = append(b, 1)
+		} else {
+			b = append(b, 0)
+		}
+		b = appendString(b, col.Name)
+		b = appendInt32(b, int32(col.TypeOID))
+		b = appendInt32(b, col.TypeModifier)
+	}
+	return b
+}
+
+// AppendInsert appends a pgoutput Insert message of a row of the relation.
+func AppendInsert(b []byte, rel *Relation, row tree.Datums) []byte {
+	b = append(b, 'I')
+	b = appendInt32(b, int32(rel.ID))
+	b = append(b, 'N')
+	return appendTuple(b, rel, row, false /* keyOnly */)
+}
+
+// AppendUpdate appends a pgoutput Update message of a row of the relation.
+// Since the primary key of a row can't change without deleting it, the old
+// key of the row is never sent.
+func AppendUpdate(b []byte, rel *Relation, row tree.Datums) []byte {
+	b = append(b, 'U')
+	b = appendInt32(b, int32(rel.ID))
+	b = append(b, 'N')
+	return appendTuple(b, rel, row, false /* keyOnly */)
+}
+
+// AppendDelete appends a pgoutput Delete message of a row of the relation,
+// identified by its primary key. Only the values of the key columns of the row
+// are used.
+func AppendDelete(b []byte, rel *Relation, row tree.Datums) []byte {
+	b = append(b, 'D')
+	b = appendInt32(b, int32(rel.ID))
+	b = append(b, 'K')
+	return appendTuple(b, rel, row, true /* keyOnly */)
+}
+
+// appendTuple appends the values of a row in text format. The values of the
+// columns which aren't part of the key are sent as nulls if keyOnly is set.
+func appendTuple(b []byte, rel *Relation, row tree.Datums, keyOnly bool) []byte {
+	b = appendInt16(b, int16(len(rel.Columns)))
+	for i := range rel.Columns {
+		if row[i] == tree.DNull || (keyOnly && !rel.Columns[i].Key) {
+			b = append(b, 'n')
+			continue
+		}
+		s := FormatDatum(row[i])
+		b = append(b, 't')
+		b = appendInt32(b, int32(len(s)))
+		b = append(b, s...)
+	}
+	return b
+}
+
+// FormatDatum formats a non-null datum in the text format of pgwire, in which
+// pgoutput sends the values of the columns.
+func FormatDatum(d tree.Datum) string {
+	switch v := tree.UnwrapDatum(nil, d).(type) {
+	case *tree.DBool:
+		return string(tree.PgwireFormatBool(bool(*v)))
+	case *tree.DFloat:
+		switch f := float64(*v); {
+		case math.IsInf(f, 1):
+			return "Infinity"
+		case math.IsInf(f, -1):
+			return "-Infinity"
+		default:
+			return strconv.FormatFloat(f, 'g', -1, 64)
+		}
+	case *tree.DBytes:
+		return lex.EncodeByteArrayToRawBytes(string(*v), sessiondatapb.BytesEncodeHex, false /* skipHexPrefix */)
+	case *tree.DString:
+		return string(*v)
+	case *tree.DCollatedString:
+		return v.Contents
+	case *tree.DGeography:
+		return v.Geography.EWKBHex()
+	case *tree.DGeometry:
+		return v.Geometry.EWKBHex()
+	case *tree.DJSON:
+		return v.JSON.String()
+	case *tree.DEnum:
+		return v.LogicalRep
+	default:
+		return tree.AsStringWithFlags(d, tree.FmtPgwireText)
+	}
+}
+
+// PgoutputOptions are the options of START_REPLICATION for the pgoutput
+// plugin.
+type PgoutputOptions struct {
+	ProtoVersion int
+	// Publications are the names of the publications whose tables' changes are
+	// streamed.
+	Publications []string
+}
+
+// ParsePgoutputOptions parses the options of START_REPLICATION for the
+// pgoutput plugin. Only version 1 of the protocol and the text format are
+// supported.
+func ParsePgoutputOptions(opts tree.KVOptions) (PgoutputOptions, error) {
+	var res PgoutputOptions
+	for _, opt := range opts {
+		var val string
+		if opt.Value != nil {
+			val = opt.Value.(*tree.StrVal).RawString()
+		}
+		switch opt.Key {
+		case "proto_version":
+			v, err := strconv.Atoi(val)
+			if err != nil {
+				return res, pgerror.Newf(pgcode.InvalidParameterValue,
+					"invalid proto_version: %q", val)
+			}
+			if v != 1 {
+				return res, pgerror.Newf(pgcode.FeatureNotSupported,
+					"proto_version %d is not supported, only version 1 is", v)
+			}
+			res.ProtoVersion = v
+		case "publication_names":
+			names, err := parseNameList(val)
+			if err != nil {
+				return res, err
+			}
+			res.Publications = names
+		case "binary":
+			if b, err := parseBoolOption(opt.Key, val); err != nil {
+				return res, err
+			} else if b {
+				return res, unimplemented.New("pgoutput-binary",
+					"binary transfer of pgoutput values is not supported")
+			}
+		case "messages", "streaming":
+			// Logical decoding messages and streamed transactions are never sent, so
+			// they can be requested.
+			if _, err := parseBoolOption(opt.Key, val); err != nil {
+				return res, err
+			}
+		default:
+			return res, pgerror.Newf(pgcode.InvalidParameterValue,
+				"unrecognized pgoutput option: %s", opt.Key)
+		}
+	}
+	if res.ProtoVersion == 0 {
+		return res, pgerror.New(pgcode.InvalidParameterValue, "proto_version option missing")
+	}
+	if len(res.Publications) == 0 {
+		return res, pgerror.New(pgcode.InvalidParameterValue, "publication_names parameter missing")
+	}
+	return res, nil
+}
+
+func parseBoolOption(key, val string) (bool, error) {
+	if val == "" {
+		return true, nil
+	}
+	b, err := tree.ParseBool(val)
+	if err != nil {
+		return false, pgerror.Newf(pgcode.InvalidParameterValue,
+			"invalid value for %s: %q", key, val)
+	}
+	return b, nil
+}
+
+// parseNameList parses a comma-separated list of identifiers, which are
+// lowercased unless they are quoted.
+func parseNameList(s string) ([]string, error) {
+	p := cmdParser{s: s}
+	var names []string
+	for {
+		name, err := p.expectName()
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid publication_names syntax")
+		}
+		names = append(names, string(name))
+		tok, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		switch tok.kind {
+		case tokComma:
+		case tokEOF:
+			return names, nil
+		default:
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"invalid publication_names syntax: %q", s)
+		}
+	}
+}
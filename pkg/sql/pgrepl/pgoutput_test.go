@@ -0,0 +1,183 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgrepl
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/lib/pq/oid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLSN(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	lsn := MakeLSN(hlc.Timestamp{WallTime: 0x16B374D848, Logical: 3})
+	require.Equal(t, "16/B374D848", lsn.String())
+	parsed, err := ParseLSN(lsn.String())
+	require.NoError(t, err)
+	require.Equal(t, lsn, parsed)
+	require.Equal(t, hlc.Timestamp{WallTime: 0x16B374D848, Logical: math.MaxInt32}, lsn.Timestamp())
+
+	for _, s := range []string{"", "16", "16/", "/1", "G/1", "1/100000000"} {
+		_, err := ParseLSN(s)
+		require.Error(t, err, s)
+	}
+}
+
+func TestPgoutputMessages(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	commitTime := pgEpoch.Add(time.Second)
+	rel := &Relation{
+		ID:        53,
+		Namespace: "public",
+		Name:      "t",
+		Columns: []RelationColumn{
+			{Name: "k", Key: true, TypeOID: oid.T_int8, TypeModifier: -1},
+			{Name: "v", TypeOID: oid.T_text, TypeModifier: -1},
+		},
+	}
+	row := tree.Datums{tree.NewDInt(1), tree.NewDString("a")}
+
+	testCases := []struct {
+		name     string
+		msg      []byte
+		expected []byte
+	}{
+		{
+			name: "xlogdata",
+			msg:  AppendXLogData(nil, 1, 2, commitTime),
+			expected: []byte{'w', 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 2,
+				0, 0, 0, 0, 0, 0x0f, 0x42, 0x40},
+		},
+		{
+			name: "keepalive",
+			msg:  AppendKeepalive(nil, 1, commitTime, true /* replyRequested */),
+			expected: []byte{'k', 0, 0, 0, 0, 0, 0, 0, 1,
+				0, 0, 0, 0, 0, 0x0f, 0x42, 0x40, 1},
+		},
+		{
+			name: "begin",
+			msg:  AppendBegin(nil, 0x100000002, commitTime),
+			expected: []byte{'B', 0, 0, 0, 1, 0, 0, 0, 2,
+				0, 0, 0, 0, 0, 0x0f, 0x42, 0x40, 0, 0, 0, 2},
+		},
+		{
+			name: "commit",
+			msg:  AppendCommit(nil, 2, 2, commitTime),
+			expected: []byte{'C', 0, 0, 0, 0, 0, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 2,
+				0, 0, 0, 0, 0, 0x0f, 0x42, 0x40},
+		},
+		{
+			name: "relation",
+			msg:  AppendRelation(nil, rel),
+			expected: []byte{'R', 0, 0, 0, 53, 'p', 'u', 'b', 'l', 'i', 'c', 0, 't', 0, 'd', 0, 2,
+				1, 'k', 0, 0, 0, 0, 20, 0xff, 0xff, 0xff, 0xff,
+				0, 'v', 0, 0, 0, 0, 25, 0xff, 0xff, 0xff, 0xff},
+		},
+		{
+			name: "insert",
+			msg:  AppendInsert(nil, rel, row),
+			expected: []byte{'I', 0, 0, 0, 53, 'N', 0, 2,
+				't', 0, 0, 0, 1, '1', 't', 0, 0, 0, 1, 'a'},
+		},
+		{
+			name: "update",
+			msg:  AppendUpdate(nil, rel, tree.Datums{tree.NewDInt(1), tree.DNull}),
+			expected: []byte{'U', 0, 0, 0, 53, 'N', 0, 2,
+				't', 0, 0, 0, 1, '1', 'n'},
+		},
+		{
+			name: "delete",
+			msg:  AppendDelete(nil, rel, row),
+			expected: []byte{'D', 0, 0, 0, 53, 'K', 0, 2,
+				't', 0, 0, 0, 1, '1', 'n'},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.msg)
+		})
+	}
+}
+
+func TestParseStandbyStatusUpdate(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	msg := []byte{'r', 0, 0, 0, 0, 0, 0, 0, 3, 0, 0, 0, 0, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 1,
+		0, 0, 0, 0, 0, 0, 0, 0, 1}
+	status, err := ParseStandbyStatusUpdate(msg)
+	require.NoError(t, err)
+	require.Equal(t, StandbyStatusUpdate{
+		WriteLSN: 3, FlushLSN: 2, ApplyLSN: 1, ReplyRequested: true,
+	}, status)
+
+	_, err = ParseStandbyStatusUpdate(msg[:len(msg)-1])
+	require.Error(t, err)
+}
+
+func TestFormatDatum(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testCases := []struct {
+		d        tree.Datum
+		expected string
+	}{
+		{tree.DBoolTrue, "t"},
+		{tree.NewDFloat(1.5), "1.5"},
+		{tree.NewDFloat(tree.DFloat(math.Inf(-1))), "-Infinity"},
+		{tree.NewDBytes("\x01a"), `\x0161`},
+		{tree.NewDString("it's"), "it's"},
+		{tree.NewDInt(-3), "-3"},
+	}
+	for _, tc := range testCases {
+		require.Equal(t, tc.expected, FormatDatum(tc.d))
+	}
+}
+
+func TestParsePgoutputOptions(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	opts := func(kvs ...string) tree.KVOptions {
+		var res tree.KVOptions
+		for i := 0; i < len(kvs); i += 2 {
+			res = append(res, tree.KVOption{Key: tree.Name(kvs[i]), Value: tree.NewStrVal(kvs[i+1])})
+		}
+		return res
+	}
+	res, err := ParsePgoutputOptions(opts(
+		"proto_version", "1", "publication_names", `a,"B" , c`, "messages", "false",
+	))
+	require.NoError(t, err)
+	require.Equal(t, PgoutputOptions{ProtoVersion: 1, Publications: []string{"a", "B", "c"}}, res)
+
+	for _, tc := range []struct {
+		opts tree.KVOptions
+		err  string
+	}{
+		{opts("publication_names", "a"), "proto_version option missing"},
+		{opts("proto_version", "1"), "publication_names parameter missing"},
+		{opts("proto_version", "2", "publication_names", "a"), "proto_version 2 is not supported"},
+		{opts("proto_version", "1", "publication_names", "a b"), "invalid publication_names syntax"},
+		{opts("proto_version", "1", "publication_names", "a", "binary", "true"), "binary transfer"},
+		{opts("proto_version", "1", "publication_names", "a", "foo", "x"), "unrecognized pgoutput option: foo"},
+	} {
+		_, err := ParsePgoutputOptions(tc.opts)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), tc.err)
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/notifications"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -59,6 +60,7 @@ type commandResult struct {
 	buffer struct {
 		notices            []pgnotice.Notice
 		paramStatusUpdates []paramStatusUpdate
+		notifications      []notifications.Notification
 	}
 
 	err error
@@ -87,6 +89,10 @@ type commandResult struct {
 	// (except types must always be set).
 	types []*types.T
 
+	// copyOut, if set, is the format in which rows are sent using the Copy-out
+	// subprotocol, for COPY ... TO STDOUT statements.
+	copyOut *sql.CopyOutFormat
+
 	// bufferingDisabled is conditionally set during planning of certain
 	// statements.
 	bufferingDisabled bool
@@ -107,6 +113,7 @@ type paramStatusUpdate struct {
 }
 
 var _ sql.CommandResult = &commandResult{}
+var _ sql.CopyOutResult = &commandResult{}
 
 // Close is part of the sql.RestrictedCommandResult interface.
 func (r *commandResult) Close(ctx context.Context, t sql.TransactionStatusIndicator) {
@@ -139,6 +146,16 @@ func (r *commandResult) Close(ctx context.Context, t sql.TransactionStatusIndica
 		}
 	}
 
+	for _, n := range r.buffer.notifications {
+		if err := r.conn.bufferNotification(n); err != nil {
+			panic(errors.AssertionFailedf("unexpected err when sending notification: %s", err))
+		}
+	}
+
+	if r.copyOut != nil {
+		r.conn.bufferCopyOutDone(r.copyOut)
+	}
+
 	// Send a completion message, specific to the type of result.
 	switch r.typ {
 	case commandComplete:
@@ -218,6 +235,10 @@ func (r *commandResult) addInternal(bufferData func() error) error {
 func (r *commandResult) AddRow(ctx context.Context, row tree.Datums) error {
 	return r.addInternal(func() error {
 		r.rowsAffected++
+		if r.copyOut != nil {
+			r.conn.bufferCopyOutRow(ctx, r.copyOut, row, r.conv, r.location, r.types)
+			return nil
+		}
 		r.conn.bufferRow(ctx, row, r.formatCodes, r.conv, r.location, r.types)
 		return nil
 	})
@@ -235,7 +256,8 @@ func (r *commandResult) AddBatch(ctx context.Context, batch coldata.Batch) error
 
 // SupportsAddBatch is part of the sql.RestrictedCommandResult interface.
 func (r *commandResult) SupportsAddBatch() bool {
-	return true
+	// Rows sent using the Copy-out subprotocol are only encoded by AddRow.
+	return r.copyOut == nil
 }
 
 // DisableBuffering is part of the sql.RestrictedCommandResult interface.
@@ -257,11 +279,21 @@ func (r *commandResult) BufferNotice(notice pgnotice.Notice) {
 	r.buffer.notices = append(r.buffer.notices, notice)
 }
 
+// BufferNotification is part of the sql.SyncResult and sql.FlushResult
+// interfaces.
+func (r *commandResult) BufferNotification(n notifications.Notification) {
+	r.buffer.notifications = append(r.buffer.notifications, n)
+}
+
 // SetColumns is part of the sql.RestrictedCommandResult interface.
 func (r *commandResult) SetColumns(ctx context.Context, cols colinfo.ResultColumns) {
 	r.assertNotReleased()
 	r.conn.writerState.fi.registerCmd(r.pos)
-	if r.descOpt == sql.NeedRowDesc {
+	if r.copyOut != nil {
+		// The CopyOutResponse takes the place of the RowDescription, and is sent
+		// whether or not the statement was described.
+		r.conn.bufferCopyOutResponse(r.copyOut, cols)
+	} else if r.descOpt == sql.NeedRowDesc {
 		_ /* err */ = r.conn.writeRowDescription(ctx, cols, r.formatCodes, &r.conn.writerState.buf)
 	}
 	r.types = make([]*types.T, len(cols))
@@ -270,6 +302,12 @@ func (r *commandResult) SetColumns(ctx context.Context, cols colinfo.ResultColum
 	}
 }
 
+// SetCopyOut is part of the sql.CopyOutResult interface.
+func (r *commandResult) SetCopyOut(format sql.CopyOutFormat) {
+	r.assertNotReleased()
+	r.copyOut = &format
+}
+
 // SetInferredTypes is part of the sql.DescribeResult interface.
 func (r *commandResult) SetInferredTypes(types []oid.Oid) {
 	r.assertNotReleased()
@@ -511,6 +549,10 @@ func (r *limitedCommandResult) moreResultsNeeded(ctx context.Context) error {
 			if err := r.conn.Flush(r.pos); err != nil {
 				return err
 			}
+		case sql.DeliverNotifications:
+			// We're in a transaction, so the notifications are delivered once
+			// it finishes. Skip the command.
+			r.conn.stmtBuf.AdvanceOne()
 		default:
 			// If the portal is immediately followed by a COMMIT, we can proceed and
 			// let the portal be destroyed at the end of the transaction.
@@ -27,7 +27,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/notifications"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
@@ -96,6 +98,9 @@ type conn struct {
 
 	readBuf    pgwirebase.ReadBuffer
 	msgBuilder writeBuffer
+	// copyOutValue is a scratch buffer used to format the values of rows sent
+	// using the Copy-out subprotocol.
+	copyOutValue writeBuffer
 
 	sv *settings.Values
 
@@ -188,6 +193,7 @@ func newConn(
 	c.writerState.fi.buf = &c.writerState.buf
 	c.writerState.fi.lastFlushed = -1
 	c.msgBuilder.init(metrics.BytesOutCount)
+	c.copyOutValue.init(metrics.BytesOutCount)
 
 	return c
 }
@@ -334,7 +340,7 @@ func (c *conn) serveImpl(
 		close(dummyCh)
 		procCh = dummyCh
 
-		if err := c.sendReadyForQuery(); err != nil {
+		if err := c.sendReadyForQuery(0 /* backendPID */); err != nil {
 			reserved.Close(ctx)
 			return
 		}
@@ -673,6 +679,17 @@ func (c *conn) bufferNotice(ctx context.Context, noticeErr pgnotice.Notice) erro
 	return writeErrFields(ctx, c.sv, noticeErr, &c.msgBuilder, &c.writerState.buf)
 }
 
+// bufferNotification buffers a NotificationResponse message. The process ID
+// of the notifying session matches the one it was sent in its BackendKeyData
+// message, which lets clients ignore their own notifications.
+func (c *conn) bufferNotification(n notifications.Notification) error {
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgNotificationResponse)
+	c.msgBuilder.putInt32(n.PID)
+	c.msgBuilder.writeTerminatedString(n.Channel)
+	c.msgBuilder.writeTerminatedString(n.Payload)
+	return c.msgBuilder.finishMsg(&c.writerState.buf)
+}
+
 func (c *conn) sendInitialConnData(
 	ctx context.Context, sqlServer *sql.Server, onDefaultIntSizeChange func(newSize int32),
 ) (sql.ConnectionHandler, error) {
@@ -708,23 +725,25 @@ func (c *conn) sendInitialConnData(
 		return sql.ConnectionHandler{}, err
 	}
 
-	if err := c.sendReadyForQuery(); err != nil {
+	if err := c.sendReadyForQuery(connHandler.BackendPID()); err != nil {
 		return sql.ConnectionHandler{}, err
 	}
 	return connHandler, nil
 }
 
 // sendReadyForQuery sends the final messages of the connection handshake.
-// This includes a placeholder BackendKeyData message and a ServerMsgReady
-// message indicating that there is no active transaction.
-func (c *conn) sendReadyForQuery() error {
-	// Send the client a dummy BackendKeyData message. This is necessary for
+// This includes a BackendKeyData message and a ServerMsgReady message
+// indicating that there is no active transaction.
+func (c *conn) sendReadyForQuery(backendPID int32) error {
+	// Send the client a BackendKeyData message. This is necessary for
 	// compatibility with tools that require this message. This information is
 	// normally used by clients to send a CancelRequest message:
 	// https://www.postgresql.org/docs/9.6/static/protocol-flow.html#AEN112861
-	// CockroachDB currently ignores all CancelRequests.
+	// CockroachDB currently ignores all CancelRequests, so the secret key is a
+	// placeholder. The process ID is also reported in the notifications sent
+	// by the session.
 	c.msgBuilder.initMsg(pgwirebase.ServerMsgBackendKeyData)
-	c.msgBuilder.putInt32(0)
+	c.msgBuilder.putInt32(backendPID)
 	c.msgBuilder.putInt32(0)
 	if err := c.msgBuilder.finishMsg(c.conn); err != nil {
 		return err
@@ -753,6 +772,15 @@ func (c *conn) handleSimpleQuery(
 	}
 
 	startParse := timeutil.Now()
+	if c.sessionArgs.Replication {
+		// Replication connections can also run the commands of the replication
+		// protocol, which aren't SQL statements.
+		if stmt, err := pgrepl.Parse(query); err != nil {
+			return c.stmtBuf.Push(ctx, sql.SendError{Err: err})
+		} else if stmt != nil {
+			return c.handleReplicationCommand(ctx, query, stmt, timeReceived, startParse)
+		}
+	}
 	stmts, err := c.parser.ParseWithInt(query, unqualifiedIntSize)
 	if err != nil {
 		log.SqlExec.Errorf(ctx, "failed to parse simple query: %s", query)
@@ -818,6 +846,40 @@ func (c *conn) handleSimpleQuery(
 	return nil
 }
 
+// handleReplicationCommand executes a command of the replication protocol.
+//
+// START_REPLICATION is special, like COPY FROM: it hands control of the
+// connection, through the stmtBuf, to the replication stream, and blocks this
+// network routine until the client ends streaming.
+func (c *conn) handleReplicationCommand(
+	ctx context.Context, query string, stmt tree.Statement, timeReceived, startParse time.Time,
+) error {
+	if sr, ok := stmt.(*tree.StartReplication); ok {
+		replicationDone := sync.WaitGroup{}
+		replicationDone.Add(1)
+		if err := c.stmtBuf.Push(
+			ctx,
+			sql.StartReplication{
+				Stmt:            sr,
+				Conn:            c,
+				ReplicationDone: &replicationDone,
+			},
+		); err != nil {
+			return err
+		}
+		replicationDone.Wait()
+		return nil
+	}
+	return c.stmtBuf.Push(
+		ctx,
+		sql.ExecStmt{
+			Statement:    parser.Statement{AST: stmt, SQL: query},
+			TimeReceived: timeReceived,
+			ParseStart:   startParse,
+			ParseEnd:     timeutil.Now(),
+		})
+}
+
 // An error is returned iff the statement buffer has been closed. In that case,
 // the connection should be considered toast.
 func (c *conn) handleParse(
@@ -1130,6 +1192,27 @@ func (c *conn) BeginCopyIn(
 	return c.msgBuilder.finishMsg(c.conn)
 }
 
+// BeginCopyBoth is part of the pgwirebase.Conn interface.
+func (c *conn) BeginCopyBoth(ctx context.Context) error {
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyBothResponse)
+	c.msgBuilder.writeByte(byte(pgwirebase.FormatBinary))
+	c.msgBuilder.putInt16(0)
+	return c.msgBuilder.finishMsg(c.conn)
+}
+
+// SendCopyData is part of the pgwirebase.Conn interface.
+func (c *conn) SendCopyData(data []byte) error {
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyData)
+	c.msgBuilder.write(data)
+	return c.msgBuilder.finishMsg(c.conn)
+}
+
+// SendCopyDone is part of the pgwirebase.Conn interface.
+func (c *conn) SendCopyDone() error {
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyDone)
+	return c.msgBuilder.finishMsg(c.conn)
+}
+
 // SendCommandComplete is part of the pgwirebase.Conn interface.
 func (c *conn) SendCommandComplete(tag []byte) error {
 	c.bufferCommandComplete(tag)
@@ -0,0 +1,199 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgwire
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// copyOutBinarySignature starts the data of the binary COPY format.
+const copyOutBinarySignature = "PGCOPY\n\377\r\n\000"
+
+// bufferCopyOutResponse buffers the CopyOutResponse message that starts the
+// Copy-out subprotocol, followed by the header of the data if the format has
+// one.
+func (c *conn) bufferCopyOutResponse(format *sql.CopyOutFormat, cols colinfo.ResultColumns) {
+	fmtCode := pgwirebase.FormatText
+	if format.Format == tree.CopyFormatBinary {
+		fmtCode = pgwirebase.FormatBinary
+	}
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyOutResponse)
+	c.msgBuilder.writeByte(byte(fmtCode))
+	c.msgBuilder.putInt16(int16(len(cols)))
+	for range cols {
+		c.msgBuilder.putInt16(int16(fmtCode))
+	}
+	if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
+		panic(errors.AssertionFailedf("unexpected err from buffer: %s", err))
+	}
+
+	switch {
+	case format.Format == tree.CopyFormatBinary:
+		c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyData)
+		c.msgBuilder.writeString(copyOutBinarySignature)
+		// Flags field and header extension area length.
+		c.msgBuilder.putInt32(0)
+		c.msgBuilder.putInt32(0)
+	case format.Format == tree.CopyFormatCSV && format.Header:
+		c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyData)
+		for i := range cols {
+			if i > 0 {
+				c.msgBuilder.writeByte(format.Delimiter)
+			}
+			writeCopyOutCSV(&c.msgBuilder, format, []byte(cols[i].Name), false /* forceQuote */, len(cols) == 1)
+		}
+		c.msgBuilder.writeByte('\n')
+	default:
+		return
+	}
+	if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
+		panic(errors.AssertionFailedf("unexpected err from buffer: %s", err))
+	}
+}
+
+// bufferCopyOutRow serializes a row in the given COPY format and adds it to
+// the buffer as a CopyData message.
+func (c *conn) bufferCopyOutRow(
+	ctx context.Context,
+	format *sql.CopyOutFormat,
+	row tree.Datums,
+	conv sessiondatapb.DataConversionConfig,
+	sessionLoc *time.Location,
+	types []*types.T,
+) {
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyData)
+	if format.Format == tree.CopyFormatBinary {
+		// The binary format of a row is the same as the body of a DataRow
+		// message using binary format codes.
+		c.msgBuilder.putInt16(int16(len(row)))
+		for i, col := range row {
+			c.msgBuilder.writeBinaryDatum(ctx, col, sessionLoc, types[i])
+		}
+	} else {
+		for i, col := range row {
+			if i > 0 {
+				c.msgBuilder.writeByte(format.Delimiter)
+			}
+			if col == tree.DNull {
+				c.msgBuilder.writeString(format.Null)
+				continue
+			}
+			// Format the value into the scratch buffer, and drop the length prefix.
+			c.copyOutValue.reset()
+			writeTextDatumNotNull(&c.copyOutValue, col, conv, sessionLoc, types[i])
+			if err := c.copyOutValue.err; err != nil {
+				c.msgBuilder.setError(err)
+				break
+			}
+			val := c.copyOutValue.wrapped.Bytes()[4:]
+			if format.Format == tree.CopyFormatCSV {
+				forceQuote := format.ForceQuote != nil && format.ForceQuote[i]
+				writeCopyOutCSV(&c.msgBuilder, format, val, forceQuote, len(row) == 1)
+			} else {
+				writeCopyOutText(&c.msgBuilder, format, val)
+			}
+		}
+		c.msgBuilder.writeByte('\n')
+	}
+	if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
+		panic(errors.AssertionFailedf("unexpected err from buffer: %s", err))
+	}
+}
+
+// bufferCopyOutDone buffers the trailer of the data, if the format has one,
+// and the CopyDone message that ends the Copy-out subprotocol.
+func (c *conn) bufferCopyOutDone(format *sql.CopyOutFormat) {
+	if format.Format == tree.CopyFormatBinary {
+		c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyData)
+		c.msgBuilder.putInt16(-1)
+		if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
+			panic(errors.AssertionFailedf("unexpected err from buffer: %s", err))
+		}
+	}
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyDone)
+	if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
+		panic(errors.AssertionFailedf("unexpected err from buffer: %s", err))
+	}
+}
+
+// writeCopyOutText writes a value in the text COPY format, in which
+// backslashes, the delimiter and control characters are escaped with a
+// backslash.
+func writeCopyOutText(b *writeBuffer, format *sql.CopyOutFormat, val []byte) {
+	start := 0
+	for i, ch := range val {
+		var esc byte
+		switch {
+		case ch == '\b':
+			esc = 'b'
+		case ch == '\f':
+			esc = 'f'
+		case ch == '\n':
+			esc = 'n'
+		case ch == '\r':
+			esc = 'r'
+		case ch == '\t':
+			esc = 't'
+		case ch == '\v':
+			esc = 'v'
+		case ch == '\\' || ch == format.Delimiter:
+			esc = ch
+		default:
+			continue
+		}
+		b.write(val[start:i])
+		b.writeByte('\\')
+		b.writeByte(esc)
+		start = i + 1
+	}
+	b.write(val[start:])
+}
+
+// writeCopyOutCSV writes a value in the CSV COPY format. The value is quoted if
+// forceQuote is set, if it matches the null string, if it contains the
+// delimiter, the quote character or a newline, or if it could be mistaken for
+// the end-of-data marker.
+func writeCopyOutCSV(
+	b *writeBuffer, format *sql.CopyOutFormat, val []byte, forceQuote bool, singleColumn bool,
+) {
+	useQuote := forceQuote || string(val) == format.Null ||
+		(singleColumn && string(val) == `\.`)
+	for i := 0; !useQuote && i < len(val); i++ {
+		switch val[i] {
+		case format.Delimiter, format.Quote, '\n', '\r':
+			useQuote = true
+		}
+	}
+	if !useQuote {
+		b.write(val)
+		return
+	}
+	b.writeByte(format.Quote)
+	start := 0
+	for i, ch := range val {
+		if ch == format.Quote || ch == format.Escape {
+			b.write(val[start:i])
+			b.writeByte(format.Escape)
+			start = i
+		}
+	}
+	b.write(val[start:])
+	b.writeByte(format.Quote)
+}
@@ -2014,3 +2014,76 @@ func TestFailPrepareFailsTxn(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+// TestListenNotify checks that notifications sent with NOTIFY and pg_notify()
+// are delivered as NotificationResponse messages to the sessions listening on
+// the corresponding channel, and only once the sending transaction commits.
+func TestListenNotify(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, _, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+
+	pgURL, cleanupFn := sqlutils.PGUrl(t, s.ServingSQLAddr(), t.Name(), url.User(security.RootUser))
+	defer cleanupFn()
+
+	listener, err := pgx.Connect(ctx, pgURL.String())
+	require.NoError(t, err)
+	defer func() { _ = listener.Close(ctx) }()
+	notifier, err := pgx.Connect(ctx, pgURL.String())
+	require.NoError(t, err)
+	defer func() { _ = notifier.Close(ctx) }()
+
+	waitForNotifications := func(channel string, expected ...string) {
+		t.Helper()
+		waitCtx, cancel := context.WithTimeout(ctx, testutils.DefaultSucceedsSoonDuration)
+		defer cancel()
+		var payloads []string
+		for range expected {
+			n, err := listener.WaitForNotification(waitCtx)
+			require.NoError(t, err)
+			require.Equal(t, channel, n.Channel)
+			// The notifications carry the process ID of the notifying session.
+			require.Equal(t, notifier.PgConn().PID(), n.PID)
+			payloads = append(payloads, n.Payload)
+		}
+		require.ElementsMatch(t, expected, payloads)
+	}
+
+	require.NotZero(t, notifier.PgConn().PID())
+	// pg_backend_pid() reports the same process ID, so that clients can
+	// recognize their own notifications.
+	var backendPID uint32
+	require.NoError(t, notifier.QueryRow(ctx, "SELECT pg_backend_pid()").Scan(&backendPID))
+	require.Equal(t, notifier.PgConn().PID(), backendPID)
+
+	_, err = listener.Exec(ctx, "LISTEN foo")
+	require.NoError(t, err)
+	// LISTEN only takes effect once the transaction commits.
+	_, err = listener.Exec(ctx, "BEGIN; LISTEN bar; ROLLBACK")
+	require.NoError(t, err)
+
+	for _, stmt := range []string{
+		"BEGIN; NOTIFY foo, 'rolled back'; ROLLBACK",
+		"NOTIFY bar, 'not listening'",
+		"NOTIFY foo, 'hello'",
+		"BEGIN; SELECT pg_notify('foo', 'world'); COMMIT",
+	} {
+		_, err = notifier.Exec(ctx, stmt)
+		require.NoError(t, err)
+	}
+	waitForNotifications("foo", "hello", "world")
+
+	_, err = listener.Exec(ctx, "UNLISTEN *; LISTEN baz")
+	require.NoError(t, err)
+	for _, stmt := range []string{
+		"NOTIFY foo, 'not listening'",
+		"NOTIFY baz",
+	} {
+		_, err = notifier.Exec(ctx, stmt)
+		require.NoError(t, err)
+	}
+	waitForNotifications("baz", "")
+}
@@ -34,4 +34,17 @@ type Conn interface {
 	// SendCommandComplete sends a serverMsgCommandComplete with the given
 	// payload.
 	SendCommandComplete(tag []byte) error
+
+	// BeginCopyBoth sends the server message initiating the Copy-both
+	// subprotocol, with which replication connections stream changes
+	// (START_REPLICATION).
+	BeginCopyBoth(ctx context.Context) error
+
+	// SendCopyData sends a CopyData message with the given payload and flushes
+	// it to the client.
+	SendCopyData(data []byte) error
+
+	// SendCopyDone sends a CopyDone message, which ends the data sent by the
+	// server in the Copy-both subprotocol.
+	SendCopyDone() error
 }
@@ -23,6 +23,8 @@ import (
 	"unicode/utf8"
 	"unsafe"
 
+	"github.com/cockroachdb/cockroach/pkg/geo"
+	"github.com/cockroachdb/cockroach/pkg/geo/geopb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql/lex"
 	"github.com/cockroachdb/cockroach/pkg/sql/oidext"
@@ -495,7 +497,7 @@ func DecodeDatum(
 	case FormatBinary:
 		switch id {
 		case oid.T_record:
-			return decodeBinaryTuple(evalCtx, b)
+			return decodeBinaryTuple(evalCtx, t, b)
 		case oid.T_bool:
 			if len(b) > 0 {
 				switch b[0] {
@@ -530,6 +532,23 @@ func DecodeDatum(
 			}
 			u := binary.BigEndian.Uint32(b)
 			return tree.NewDOid(tree.DInt(u)), nil
+		case oid.T_regoper,
+			oid.T_regproc,
+			oid.T_regrole,
+			oid.T_regclass,
+			oid.T_regtype,
+			oid.T_regconfig,
+			oid.T_regoperator,
+			oid.T_regnamespace,
+			oid.T_regprocedure,
+			oid.T_regdictionary:
+			if len(b) < 4 {
+				return nil, pgerror.Newf(pgcode.Syntax, "%s requires 4 bytes for binary format", t.SQLString())
+			}
+			// The reg* types are sent as their numeric OID; resolve the name the
+			// same way the text format does.
+			u := binary.BigEndian.Uint32(b)
+			return tree.ParseDOid(evalCtx, strconv.FormatUint(uint64(u), 10), t)
 		case oid.T_float4:
 			if len(b) < 4 {
 				return nil, pgerror.Newf(pgcode.Syntax, "float4 requires 4 bytes for binary format")
@@ -723,6 +742,29 @@ func DecodeDatum(
 				return nil, err
 			}
 			return tree.ParseDJSON(string(b))
+		case oidext.T_box2d:
+			if len(b) < 32 {
+				return nil, pgerror.Newf(pgcode.Syntax, "box2d requires 32 bytes for binary format")
+			}
+			var box geo.CartesianBoundingBox
+			box.LoX = math.Float64frombits(binary.BigEndian.Uint64(b))
+			box.HiX = math.Float64frombits(binary.BigEndian.Uint64(b[8:]))
+			box.LoY = math.Float64frombits(binary.BigEndian.Uint64(b[16:]))
+			box.HiY = math.Float64frombits(binary.BigEndian.Uint64(b[24:]))
+			return tree.NewDBox2D(box), nil
+		case oidext.T_geography:
+			// The binary format of spatial types is EWKB.
+			g, err := geo.ParseGeographyFromEWKB(geopb.EWKB(b))
+			if err != nil {
+				return nil, pgerror.Wrapf(err, pgcode.Syntax, "could not parse EWKB as geography")
+			}
+			return tree.NewDGeography(g), nil
+		case oidext.T_geometry:
+			g, err := geo.ParseGeometryFromEWKB(geopb.EWKB(b))
+			if err != nil {
+				return nil, pgerror.Wrapf(err, pgcode.Syntax, "could not parse EWKB as geometry")
+			}
+			return tree.NewDGeometry(g), nil
 		case oid.T_varbit, oid.T_bit:
 			if len(b) < 4 {
 				return nil, NewProtocolViolationErrorf("insufficient data: %d", len(b))
@@ -942,6 +984,9 @@ func decodeBinaryArray(
 			continue
 		}
 		buf := r.Next(int(vlen))
+		if len(buf) < int(vlen) {
+			return nil, NewInvalidBinaryRepresentationErrorf("insufficient data for array element")
+		}
 		elem, err := DecodeDatum(evalCtx, t, code, buf)
 		if err != nil {
 			return nil, err
@@ -955,7 +1000,11 @@ func decodeBinaryArray(
 
 const tupleHeaderSize, oidSize, elementSize = 4, 4, 4
 
-func decodeBinaryTuple(evalCtx *tree.EvalContext, b []byte) (tree.Datum, error) {
+// decodeBinaryTuple decodes a tuple in the binary format. If t describes the
+// types of the elements, as is the case for a tuple column, the elements are
+// decoded as these types and the result has type t; otherwise the element
+// types are looked up by the OIDs in the data.
+func decodeBinaryTuple(evalCtx *tree.EvalContext, t *types.T, b []byte) (tree.Datum, error) {
 
 	bufferLength := int32(len(b))
 	if bufferLength < tupleHeaderSize {
@@ -976,6 +1025,20 @@ func decodeBinaryTuple(evalCtx *tree.EvalContext, b []byte) (tree.Datum, error)
 	}
 	bufferStartIdx = bufferEndIdx
 
+	var expectedTyps []*types.T
+	if contents := t.TupleContents(); len(contents) == int(numberOfElements) {
+		expectedTyps = contents
+		for _, typ := range contents {
+			if typ.Family() == types.AnyFamily {
+				expectedTyps = nil
+				break
+			}
+		}
+	} else if !t.Identical(types.AnyTuple) {
+		return nil, pgerror.Newf(pgcode.DatatypeMismatch,
+			"wrong number of columns: %d, expected %d", numberOfElements, len(contents))
+	}
+
 	typs := make([]*types.T, numberOfElements)
 	datums := make(tree.Datums, numberOfElements)
 
@@ -1002,9 +1065,19 @@ func decodeBinaryTuple(evalCtx *tree.EvalContext, b []byte) (tree.Datum, error)
 		}
 
 		elementOID := int32(binary.BigEndian.Uint32(b[bufferStartIdx:bufferEndIdx]))
-		elementType, ok := types.OidToType[oid.Oid(elementOID)]
-		if !ok {
-			return nil, getSyntaxError("element type not found for OID %d. ", elementOID)
+		var elementType *types.T
+		if expectedTyps != nil {
+			elementType = expectedTyps[elementIdx]
+			if elementType.Oid() != oid.Oid(elementOID) {
+				return nil, pgerror.Newf(pgcode.DatatypeMismatch,
+					"wrong data type: %d, expected %d", elementOID, elementType.Oid())
+			}
+		} else {
+			var ok bool
+			elementType, ok = types.OidToType[oid.Oid(elementOID)]
+			if !ok {
+				return nil, getSyntaxError("element type not found for OID %d. ", elementOID)
+			}
 		}
 		typs[elementIdx] = elementType
 		bufferStartIdx = bufferEndIdx
@@ -1043,6 +1116,9 @@ func decodeBinaryTuple(evalCtx *tree.EvalContext, b []byte) (tree.Datum, error)
 		elementIdx++
 	}
 
+	if expectedTyps != nil {
+		return tree.NewDTuple(t, datums...), nil
+	}
 	tupleTyps := types.MakeTuple(typs)
 	return tree.NewDTuple(tupleTyps, datums...), nil
 
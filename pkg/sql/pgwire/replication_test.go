@@ -0,0 +1,242 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgwire_test
+
+import (
+	"context"
+	"encoding/binary"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/server"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgproto3/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLogicalReplication checks that the changes of published tables are
+// streamed as pgoutput messages to logical replication connections.
+func TestLogicalReplication(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `SET CLUSTER SETTING kv.rangefeed.enabled = true`)
+	sqlDB.Exec(t, `SET CLUSTER SETTING kv.closed_timestamp.target_duration = '100ms'`)
+	sqlDB.Exec(t, `CREATE TABLE t (k INT PRIMARY KEY, v STRING)`)
+	sqlDB.Exec(t, `CREATE TABLE unpublished (k INT PRIMARY KEY)`)
+	sqlDB.Exec(t, `CREATE PUBLICATION p FOR TABLE t`)
+
+	pgURL, cleanupFn := sqlutils.PGUrl(t, s.ServingSQLAddr(), t.Name(), url.User(security.RootUser))
+	defer cleanupFn()
+	q := pgURL.Query()
+	q.Set("replication", "database")
+	pgURL.RawQuery = q.Encode()
+	conn, err := pgconn.Connect(ctx, pgURL.String())
+	require.NoError(t, err)
+	defer func() { _ = conn.Close(ctx) }()
+
+	exec := func(sql string) [][][]byte {
+		results, err := conn.Exec(ctx, sql).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.NoError(t, results[0].Err)
+		return results[0].Rows
+	}
+	rows := exec(`IDENTIFY_SYSTEM`)
+	require.Len(t, rows, 1)
+	require.Equal(t, "defaultdb", string(rows[0][3]))
+	rows = exec(`CREATE_REPLICATION_SLOT s LOGICAL pgoutput NOEXPORT_SNAPSHOT`)
+	require.Len(t, rows, 1)
+	require.Equal(t, "s", string(rows[0][0]))
+	require.Nil(t, rows[0][2])
+	sqlDB.CheckQueryResults(t,
+		`SELECT slot_name, plugin, slot_type, database FROM pg_catalog.pg_replication_slots`,
+		[][]string{{"s", "pgoutput", "logical", "defaultdb"}})
+	// The slot protects its changes from garbage collection.
+	const slotRecords = `SELECT count(*) FROM system.protected_ts_records
+WHERE meta_type = 'replication_slots'`
+	sqlDB.CheckQueryResults(t, slotRecords, [][]string{{"1"}})
+
+	// Regular SQL statements can still run on replication connections.
+	exec(`SELECT 1`)
+
+	sqlDB.Exec(t, `INSERT INTO t VALUES (1, 'a'), (2, 'b')`)
+	sqlDB.Exec(t, `INSERT INTO unpublished VALUES (1)`)
+	sqlDB.Exec(t, `UPDATE t SET v = NULL WHERE k = 1`)
+	sqlDB.Exec(t, `DELETE FROM t WHERE k = 2`)
+
+	send := func(msg interface{ Encode([]byte) []byte }) {
+		require.NoError(t, conn.SendBytes(ctx, msg.Encode(nil)))
+	}
+	receive := func() pgproto3.BackendMessage {
+		ctx, cancel := context.WithTimeout(ctx, 45*time.Second)
+		defer cancel()
+		msg, err := conn.ReceiveMessage(ctx)
+		require.NoError(t, err)
+		return msg
+	}
+
+	send(&pgproto3.Query{
+		String: `START_REPLICATION SLOT s LOGICAL 0/0 (proto_version '1', publication_names 'p')`,
+	})
+	require.IsType(t, &pgproto3.CopyBothResponse{}, receive())
+
+	// Collect the pgoutput messages until the three transactions are received,
+	// and confirm their position.
+	var msgs []string
+	var lastLSN pgrepl.LSN
+	for commits := 0; commits < 3; {
+		data, ok := receive().(*pgproto3.CopyData)
+		require.True(t, ok)
+		switch data.Data[0] {
+		case pgrepl.KeepaliveMsg:
+			continue
+		case pgrepl.XLogDataMsg:
+		default:
+			t.Fatalf("unexpected message type %q", data.Data[0])
+		}
+		lastLSN = pgrepl.LSN(binary.BigEndian.Uint64(data.Data[1:]))
+		out := data.Data[25:]
+		switch out[0] {
+		case 'R':
+			msgs = append(msgs, "relation t")
+		case 'I', 'U', 'D':
+			// Skip the relation ID and the type of the tuple.
+			tuple := out[6:]
+			msg := string(out[0]) + " " + formatTuple(t, tuple)
+			msgs = append(msgs, msg)
+		case 'B':
+			msgs = append(msgs, "begin")
+		case 'C':
+			msgs = append(msgs, "commit")
+			commits++
+		default:
+			t.Fatalf("unexpected pgoutput message %q", out[0])
+		}
+	}
+	require.Equal(t, []string{
+		"begin", "relation t", "I 1 a", "I 2 b", "commit",
+		"begin", "U 1 NULL", "commit",
+		"begin", "D 2 NULL", "commit",
+	}, msgs)
+
+	status := make([]byte, 34)
+	status[0] = pgrepl.StandbyStatusUpdateMsg
+	binary.BigEndian.PutUint64(status[1:], uint64(lastLSN))
+	binary.BigEndian.PutUint64(status[9:], uint64(lastLSN))
+	binary.BigEndian.PutUint64(status[17:], uint64(lastLSN))
+	binary.BigEndian.PutUint64(status[25:], uint64(timeutil.Now().UnixNano()))
+	send(&pgproto3.CopyData{Data: status})
+	send(&pgproto3.CopyDone{})
+	for {
+		msg := receive()
+		if _, ok := msg.(*pgproto3.CopyData); ok {
+			continue
+		}
+		require.IsType(t, &pgproto3.CopyDone{}, msg)
+		break
+	}
+	require.Equal(t, &pgproto3.CommandComplete{CommandTag: []byte("START_STREAMING")}, receive())
+	require.IsType(t, &pgproto3.ReadyForQuery{}, receive())
+
+	// The confirmed position was persisted in the slot.
+	sqlDB.CheckQueryResults(t,
+		`SELECT confirmed_flush_lsn FROM pg_catalog.pg_replication_slots WHERE slot_name = 's'`,
+		[][]string{{lastLSN.String()}})
+	// The protected timestamp record of the slot was advanced to it.
+	var advanced bool
+	sqlDB.QueryRow(t,
+		`SELECT ts = $1::DECIMAL FROM system.protected_ts_records WHERE meta_type = 'replication_slots'`,
+		lastLSN.Timestamp().AsOfSystemTime()).Scan(&advanced)
+	require.True(t, advanced)
+
+	exec(`DROP_REPLICATION_SLOT s`)
+	sqlDB.CheckQueryResults(t, `SELECT count(*) FROM pg_catalog.pg_replication_slots`,
+		[][]string{{"0"}})
+	sqlDB.CheckQueryResults(t, slotRecords, [][]string{{"0"}})
+}
+
+// TestCreateReplicationSlotMixedVersion checks that replication slots can't be
+// created until the cluster version that adds them to database descriptors is
+// finalized.
+func TestCreateReplicationSlotMixedVersion(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, _, _ := serverutils.StartServer(t, base.TestServerArgs{
+		Knobs: base.TestingKnobs{
+			Server: &server.TestingKnobs{
+				DisableAutomaticVersionUpgrade: 1,
+				BinaryVersionOverride:          clusterversion.ByKey(clusterversion.ReplicationSlots - 1),
+			},
+		},
+	})
+	defer s.Stopper().Stop(ctx)
+
+	pgURL, cleanupFn := sqlutils.PGUrl(t, s.ServingSQLAddr(), t.Name(), url.User(security.RootUser))
+	defer cleanupFn()
+	q := pgURL.Query()
+	q.Set("replication", "database")
+	pgURL.RawQuery = q.Encode()
+	conn, err := pgconn.Connect(ctx, pgURL.String())
+	require.NoError(t, err)
+	defer func() { _ = conn.Close(ctx) }()
+
+	results, err := conn.Exec(ctx, `CREATE_REPLICATION_SLOT s LOGICAL pgoutput NOEXPORT_SNAPSHOT`).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	var pgErr *pgconn.PgError
+	require.True(t, errors.As(results[0].Err, &pgErr))
+	require.Equal(t, pgcode.FeatureNotSupported.String(), pgErr.Code)
+	require.Contains(t, pgErr.Message, "must be finalized to use replication slots")
+}
+
+// formatTuple formats the values of a pgoutput tuple, separated by spaces.
+func formatTuple(t *testing.T, tuple []byte) string {
+	n := int(binary.BigEndian.Uint16(tuple))
+	tuple = tuple[2:]
+	var s string
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			s += " "
+		}
+		switch tuple[0] {
+		case 'n':
+			s += "NULL"
+			tuple = tuple[1:]
+		case 't':
+			l := int(binary.BigEndian.Uint32(tuple[1:]))
+			s += string(tuple[5 : 5+l])
+			tuple = tuple[5+l:]
+		default:
+			t.Fatalf("unexpected tuple value kind %q", tuple[0])
+		}
+	}
+	return s
+}
@@ -36,9 +36,11 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
 	"github.com/cockroachdb/cockroach/pkg/util/contextutil"
 	"github.com/cockroachdb/cockroach/pkg/util/envutil"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
@@ -810,6 +812,26 @@ func parseClientProvidedSessionParameters(
 			}
 			args.RemoteAddr = &net.TCPAddr{IP: ip, Port: port}
 
+		case "replication":
+			// As in PostgreSQL, replication=database requests a logical
+			// replication connection, bound to the database of the connection,
+			// and a boolean value requests a physical replication connection or
+			// a regular connection.
+			if strings.ToLower(value) == "database" {
+				args.Replication = true
+				telemetry.Inc(sqltelemetry.ReplicationConnectionCounter)
+				break
+			}
+			physical, err := tree.ParseBool(value)
+			if err != nil {
+				return sql.SessionArgs{}, pgerror.Newf(pgcode.ProtocolViolation,
+					"invalid value for parameter \"replication\": %q", value)
+			}
+			if physical {
+				return sql.SessionArgs{}, unimplemented.New("physical-replication",
+					"physical replication connections are not supported")
+			}
+
 		case "options":
 			opts, err := parseOptions(value)
 			if err != nil {
@@ -25,6 +25,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -235,6 +236,74 @@ func TestByteArrayRoundTrip(t *testing.T) {
 	}
 }
 
+// TestBinaryRoundTrip checks that datums written in the binary format, as is
+// done by binary COPY clients, are decoded back to the same datums.
+func TestBinaryRoundTrip(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	rng := rand.New(rand.NewSource(timeutil.Now().Unix()))
+	evalCtx := tree.NewTestingEvalContext(cluster.MakeTestingClusterSettings())
+	defer evalCtx.Stop(context.Background())
+
+	tupleTyp := types.MakeLabeledTuple(
+		[]*types.T{types.Int, types.String, types.IntArray}, []string{"a", "b", "c"},
+	)
+	typs := []*types.T{
+		types.Box2D,
+		types.Geometry,
+		types.Geography,
+		types.IntArray,
+		types.StringArray,
+		types.MakeArray(types.Geometry),
+		tupleTyp,
+	}
+	for _, typ := range typs {
+		t.Run(typ.String(), func(t *testing.T) {
+			for i := 0; i < 10; i++ {
+				d := randgen.RandDatum(rng, typ, true /* nullOk */)
+				if d == tree.DNull {
+					continue
+				}
+				buf := newWriteBuffer(nil /* bytecount */)
+				buf.bytecount = metric.NewCounter(metric.Metadata{})
+				buf.writeBinaryDatum(context.Background(), d, time.UTC, typ)
+				if buf.err != nil {
+					t.Fatal(buf.err)
+				}
+				b := buf.wrapped.Bytes()
+				got, err := pgwirebase.DecodeDatum(evalCtx, typ, pgwirebase.FormatBinary, b[4:])
+				if err != nil {
+					t.Fatalf("decoding %s: %v", d, err)
+				}
+				if got.Compare(evalCtx, d) != 0 {
+					t.Fatalf("expected %s, got %s", d, got)
+				}
+			}
+		})
+	}
+
+	// A tuple whose element types differ from the expected ones is rejected.
+	buf := newWriteBuffer(nil /* bytecount */)
+	buf.bytecount = metric.NewCounter(metric.Metadata{})
+	pair := types.MakeTuple([]*types.T{types.String, types.Int})
+	buf.writeBinaryDatum(
+		context.Background(),
+		tree.NewDTuple(pair, tree.NewDString("a"), tree.NewDInt(1)),
+		time.UTC,
+		pair,
+	)
+	_, err := pgwirebase.DecodeDatum(
+		evalCtx,
+		types.MakeTuple([]*types.T{types.Int, types.String}),
+		pgwirebase.FormatBinary,
+		buf.wrapped.Bytes()[4:],
+	)
+	if !testutils.IsError(err, "wrong data type") {
+		t.Fatalf("expected wrong data type error, got %v", err)
+	}
+}
+
 func TestCanWriteAllDatums(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -96,6 +96,10 @@ func getPlanColumns(plan planNode, mut bool) colinfo.ResultColumns {
 		return n.resultColumns
 	case *invertedJoinNode:
 		return n.columns
+	case *fetchNode:
+		return n.columns()
+	case *foreignScanNode:
+		return n.columns
 
 	// Nodes with a fixed schema.
 	case *scrubNode:
@@ -124,6 +128,8 @@ func getPlanColumns(plan planNode, mut bool) colinfo.ResultColumns {
 		return n.getColumns(mut, colinfo.SequenceSelectColumns)
 	case *exportNode:
 		return n.getColumns(mut, colinfo.ExportColumns)
+	case *createReplicationSlotNode:
+		return n.getColumns(mut, colinfo.CreateReplicationSlotColumns)
 
 	// The columns in the hookFnNode are returned by the hook function; we don't
 	// know if they can be modified in place or not.
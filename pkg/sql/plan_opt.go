@@ -196,6 +196,12 @@ func (p *planner) makeOptimizerPlan(ctx context.Context) error {
 		return err
 	}
 
+	// Deferred constraint checks are validated before the transaction commits,
+	// so the statement cannot commit the transaction itself.
+	if p.deferredConstraints != nil && len(p.deferredConstraints.pending) > 0 {
+		p.autoCommit = false
+	}
+
 	// Build the plan tree.
 	if mode := p.SessionData().ExperimentalDistSQLPlanningMode; mode != sessiondatapb.ExperimentalDistSQLPlanningOff {
 		planningMode := distSQLDefaultPlanning
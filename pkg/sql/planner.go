@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
 	"github.com/cockroachdb/cockroach/pkg/spanconfig"
+	"github.com/cockroachdb/cockroach/pkg/sql/advisorylock"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
@@ -184,6 +185,23 @@ type planner struct {
 
 	createdSequences createdSequences
 
+	// sqlCursors contains the list of SQL CURSORs the session currently has
+	// access to.
+	sqlCursors sqlCursors
+
+	// listenOps accumulates the LISTEN and UNLISTEN statements executed in the
+	// current transaction. It is nil when the planner is not bound to a session.
+	listenOps *[]listenOp
+
+	// deferredConstraints contains the constraint modes and the deferred
+	// constraint checks of the current transaction. It is nil when the planner
+	// is not bound to a session.
+	deferredConstraints *deferredConstraints
+
+	// advisoryLocks returns the advisory locks held by the session. It is nil
+	// when the planner is not bound to a session.
+	advisoryLocks func() *advisorylock.Session
+
 	// avoidLeasedDescriptors, when true, instructs all code that
 	// accesses table/view descriptors to force reading the descriptors
 	// within the transaction. This is necessary to read descriptors
@@ -357,6 +375,7 @@ func newInternalPlanner(
 	p.semaCtx.IntervalStyleEnabled = sd.IntervalStyleEnabled
 	p.semaCtx.DateStyleEnabled = sd.DateStyleEnabled
 	p.semaCtx.TypeResolver = p
+	p.semaCtx.FunctionResolver = p
 	p.semaCtx.DateStyle = sd.GetDateStyle()
 	p.semaCtx.IntervalStyle = sd.GetIntervalStyle()
 
@@ -416,6 +435,7 @@ func newInternalPlanner(
 	p.queryCacheSession.Init()
 	p.optPlanningCtx.init(p)
 	p.createdSequences = emptyCreatedSequences{}
+	p.sqlCursors = emptySQLCursors{}
 
 	return p, func() {
 		// Note that we capture ctx here. This is only valid as long as we create
@@ -573,6 +593,11 @@ func (p *planner) TemporarySchemaName() string {
 	return temporarySchemaName(p.ExtendedEvalContext().SessionID)
 }
 
+// BackendPID implements the tree.EvalSessionAccessor interface.
+func (p *planner) BackendPID(_ context.Context) (int32, error) {
+	return p.ExtendedEvalContext().SessionID.BackendPID(), nil
+}
+
 // DistSQLPlanner returns the DistSQLPlanner
 func (p *planner) DistSQLPlanner() *DistSQLPlanner {
 	return p.extendedEvalCtx.DistSQLPlanner
@@ -150,6 +150,23 @@ func (p *planner) renameColumn(
 		}
 	}
 
+	// Rename the column in row-level security policies.
+	for i := range tableDesc.Policies {
+		policy := &tableDesc.Policies[i]
+		if policy.UsingExpr != "" {
+			policy.UsingExpr, err = schemaexpr.RenameColumn(policy.UsingExpr, *oldName, *newName)
+			if err != nil {
+				return false, err
+			}
+		}
+		if policy.WithCheckExpr != "" {
+			policy.WithCheckExpr, err = schemaexpr.RenameColumn(policy.WithCheckExpr, *oldName, *newName)
+			if err != nil {
+				return false, err
+			}
+		}
+	}
+
 	// Rename the column in computed columns.
 	for i := range tableDesc.Columns {
 		if otherCol := &tableDesc.Columns[i]; otherCol.IsComputed() {
@@ -0,0 +1,311 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts/ptpb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts/ptreconcile"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+)
+
+// IdentifySystem returns the identity of the cluster and the current position
+// of the stream of changes, for replication clients.
+// Privileges: None.
+func (p *planner) IdentifySystem(ctx context.Context, n *tree.IdentifySystem) (planNode, error) {
+	dbName := tree.DNull
+	if db := p.CurrentDatabase(); db != "" {
+		dbName = tree.NewDString(db)
+	}
+	v := p.newContainerValuesNode(colinfo.IdentifySystemColumns, 1)
+	if _, err := v.rows.AddRow(ctx, tree.Datums{
+		tree.NewDString(p.ExecCfg().ClusterID().String()), // systemid
+		tree.NewDInt(1), // timeline
+		tree.NewDString(pgrepl.MakeLSN(p.ExecCfg().Clock.Now()).String()), // xlogpos
+		dbName, // dbname
+	}); err != nil {
+		v.Close(ctx)
+		return nil, err
+	}
+	return v, nil
+}
+
+type createReplicationSlotNode struct {
+	optColumnsSlot
+
+	n      *tree.CreateReplicationSlot
+	dbDesc *dbdesc.Mutable
+
+	run struct {
+		row  tree.Datums
+		done bool
+	}
+}
+
+// CreateReplicationSlot creates a logical replication slot in the current
+// database, from which the changes of its published tables can be streamed.
+// Privileges: admin.
+func (p *planner) CreateReplicationSlot(
+	ctx context.Context, n *tree.CreateReplicationSlot,
+) (planNode, error) {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.ReplicationSlots) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to use replication slots",
+			clusterversion.ReplicationSlots)
+	}
+	if err := p.RequireAdminRole(ctx, "CREATE_REPLICATION_SLOT"); err != nil {
+		return nil, err
+	}
+	if n.Temporary {
+		return nil, unimplemented.New("temporary-replication-slot",
+			"temporary replication slots are not supported")
+	}
+	if n.Plugin != pgrepl.PgoutputPlugin {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"output plugin %q is not supported, only %q is", n.Plugin, pgrepl.PgoutputPlugin)
+	}
+	if n.Snapshot == "use" {
+		return nil, unimplemented.New("replication-slot-use-snapshot",
+			"USE_SNAPSHOT is not supported")
+	}
+
+	dbDesc, err := p.getCurrentMutableDatabase(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := dbDesc.GetReplicationSlot(string(n.Slot)); ok {
+		return nil, pgerror.Newf(pgcode.DuplicateObject,
+			"replication slot %q already exists", n.Slot)
+	}
+	return &createReplicationSlotNode{n: n, dbDesc: dbDesc}, nil
+}
+
+func (n *createReplicationSlotNode) startExec(params runParams) error {
+	telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("replication_slot"))
+
+	// The consistent point of the slot is just before the read timestamp of the
+	// transaction, so that the changes committed in the same nanosecond as it
+	// are streamed, since they share its LSN.
+	consistentPoint := pgrepl.MakeLSN(params.p.txn.ReadTimestamp()) - 1
+	slot := descpb.DatabaseDescriptor_ReplicationSlot{
+		Name:              string(n.n.Slot),
+		Plugin:            string(n.n.Plugin),
+		ConfirmedFlushLSN: uint64(consistentPoint),
+	}
+	if err := protectReplicationSlot(
+		params.ctx, params.ExecCfg(), params.p.txn, params.p.Descriptors(), n.dbDesc.GetID(), &slot,
+	); err != nil {
+		return err
+	}
+	n.dbDesc.SetReplicationSlot(slot)
+	if err := params.p.writeNonDropDatabaseChange(
+		params.ctx, n.dbDesc, tree.AsStringWithFQNames(n.n, params.Ann()),
+	); err != nil {
+		return err
+	}
+
+	// Snapshots can't be imported into transactions, so the snapshot of the
+	// slot is the timestamp of its consistent point, at which the tables can
+	// be read with AS OF SYSTEM TIME.
+	snapshot := tree.DNull
+	if n.n.Snapshot != "nothing" {
+		snapshot = tree.NewDString(consistentPoint.Timestamp().AsOfSystemTime())
+	}
+	n.run.row = tree.Datums{
+		tree.NewDString(string(n.n.Slot)),
+		tree.NewDString(consistentPoint.String()),
+		snapshot,
+		tree.NewDString(string(n.n.Plugin)),
+	}
+	return nil
+}
+
+func (n *createReplicationSlotNode) Next(runParams) (bool, error) {
+	if n.run.done {
+		return false, nil
+	}
+	n.run.done = true
+	return true, nil
+}
+func (n *createReplicationSlotNode) Values() tree.Datums   { return n.run.row }
+func (n *createReplicationSlotNode) Close(context.Context) {}
+
+type dropReplicationSlotNode struct {
+	n      *tree.DropReplicationSlot
+	dbDesc *dbdesc.Mutable
+}
+
+// DropReplicationSlot drops a replication slot of the current database. The
+// connections streaming its changes stop once they notice it's gone.
+// Privileges: admin.
+func (p *planner) DropReplicationSlot(
+	ctx context.Context, n *tree.DropReplicationSlot,
+) (planNode, error) {
+	if err := p.RequireAdminRole(ctx, "DROP_REPLICATION_SLOT"); err != nil {
+		return nil, err
+	}
+	dbDesc, err := p.getCurrentMutableDatabase(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := dbDesc.GetReplicationSlot(string(n.Slot)); !ok {
+		return nil, pgerror.Newf(pgcode.UndefinedObject,
+			"replication slot %q does not exist", n.Slot)
+	}
+	return &dropReplicationSlotNode{n: n, dbDesc: dbDesc}, nil
+}
+
+func (n *dropReplicationSlotNode) startExec(params runParams) error {
+	telemetry.Inc(sqltelemetry.SchemaChangeDropCounter("replication_slot"))
+
+	slot, _ := n.dbDesc.GetReplicationSlot(string(n.n.Slot))
+	if err := releaseReplicationSlot(params.ctx, params.ExecCfg(), params.p.txn, &slot); err != nil {
+		return err
+	}
+	n.dbDesc.RemoveReplicationSlot(string(n.n.Slot))
+	return params.p.writeNonDropDatabaseChange(
+		params.ctx, n.dbDesc, tree.AsStringWithFQNames(n.n, params.Ann()),
+	)
+}
+
+func (n *dropReplicationSlotNode) Next(runParams) (bool, error) { return false, nil }
+func (n *dropReplicationSlotNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *dropReplicationSlotNode) Close(context.Context)        {}
+
+// ReplicationSlotMetaType is the meta type of the protected timestamp records
+// of replication slots. Their meta is the ID of the database of the slot and
+// its name, separated by a slash.
+const ReplicationSlotMetaType = "replication_slots"
+
+func encodeReplicationSlotMeta(dbID descpb.ID, slot string) []byte {
+	return []byte(fmt.Sprintf("%d/%s", dbID, slot))
+}
+
+func decodeReplicationSlotMeta(meta []byte) (descpb.ID, string, error) {
+	s := string(meta)
+	i := strings.IndexByte(s, '/')
+	if i < 0 {
+		return 0, "", errors.Errorf("invalid replication slot meta %q", meta)
+	}
+	id, err := strconv.ParseUint(s[:i], 10, 32)
+	if err != nil {
+		return 0, "", errors.Wrapf(err, "invalid replication slot meta %q", meta)
+	}
+	return descpb.ID(id), s[i+1:], nil
+}
+
+// protectReplicationSlot protects the changes after the confirmed position of
+// a replication slot from garbage collection, replacing the previous protected
+// timestamp record of the slot, if any. The record covers the tables of the
+// database of the slot, including the ones created since the previous record,
+// and the descriptor table, whose history is needed to decode their changes.
+//
+// Tenants can't protect timestamps yet, so their slots have no record, and
+// streaming fails if the changes were garbage collected.
+func protectReplicationSlot(
+	ctx context.Context,
+	execCfg *ExecutorConfig,
+	txn *kv.Txn,
+	col *descs.Collection,
+	dbID descpb.ID,
+	slot *descpb.DatabaseDescriptor_ReplicationSlot,
+) error {
+	if !execCfg.Codec.ForSystemTenant() {
+		return nil
+	}
+	if err := releaseReplicationSlot(ctx, execCfg, txn, slot); err != nil {
+		return err
+	}
+	tables, err := col.GetAllTableDescriptorsInDatabase(ctx, txn, dbID)
+	if err != nil {
+		return err
+	}
+	spans := make([]roachpb.Span, 0, len(tables)+1)
+	addTableSpan := func(id descpb.ID) {
+		prefix := execCfg.Codec.TablePrefix(uint32(id))
+		spans = append(spans, roachpb.Span{Key: prefix, EndKey: prefix.PrefixEnd()})
+	}
+	addTableSpan(keys.DescriptorTableID)
+	for _, table := range tables {
+		addTableSpan(table.GetID())
+	}
+	slot.ProtectedTimestampRecord = uuid.MakeV4()
+	return execCfg.ProtectedTimestampProvider.Protect(ctx, txn, &ptpb.Record{
+		ID:        slot.ProtectedTimestampRecord,
+		Timestamp: pgrepl.LSN(slot.ConfirmedFlushLSN).Timestamp(),
+		Mode:      ptpb.PROTECT_AFTER,
+		MetaType:  ReplicationSlotMetaType,
+		Meta:      encodeReplicationSlotMeta(dbID, slot.Name),
+		Spans:     spans,
+	})
+}
+
+// releaseReplicationSlot releases the protected timestamp record of a
+// replication slot, if any.
+func releaseReplicationSlot(
+	ctx context.Context,
+	execCfg *ExecutorConfig,
+	txn *kv.Txn,
+	slot *descpb.DatabaseDescriptor_ReplicationSlot,
+) error {
+	if slot.ProtectedTimestampRecord == uuid.Nil {
+		return nil
+	}
+	if err := execCfg.ProtectedTimestampProvider.Release(
+		ctx, txn, slot.ProtectedTimestampRecord,
+	); err != nil {
+		return err
+	}
+	slot.ProtectedTimestampRecord = uuid.Nil
+	return nil
+}
+
+// ReplicationSlotStatusFunc returns the function with which the protected
+// timestamp reconciler removes the records of the replication slots which no
+// longer exist, such as those of dropped databases.
+func ReplicationSlotStatusFunc(codec keys.SQLCodec) ptreconcile.StatusFunc {
+	return func(ctx context.Context, txn *kv.Txn, meta []byte) (shouldRemove bool, _ error) {
+		dbID, name, err := decodeReplicationSlotMeta(meta)
+		if err != nil {
+			return false, err
+		}
+		dbDesc, err := catalogkv.GetDatabaseDescByID(ctx, txn, codec, dbID)
+		if err != nil {
+			return false, err
+		}
+		if dbDesc == nil || dbDesc.Dropped() {
+			return true, nil
+		}
+		_, ok := dbDesc.GetReplicationSlot(name)
+		return !ok, nil
+	}
+}
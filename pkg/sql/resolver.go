@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
@@ -562,6 +563,36 @@ func getDescriptorsFromTargetListForPrivilegeChange(
 		return descs, nil
 	}
 
+	if targets.Functions != nil {
+		if len(targets.Functions) == 0 {
+			return nil, errNoFunction
+		}
+		descs := make([]catalog.Descriptor, 0, len(targets.Functions))
+		for i := range targets.Functions {
+			fn := &targets.Functions[i]
+			_, descriptor, err := p.ResolveMutableFunctionDescriptor(ctx, fn.FuncName, required)
+			if err != nil {
+				return nil, err
+			}
+			if fn.Params != nil {
+				match, err := p.functionSignatureMatches(ctx, descriptor, fn.Params)
+				if err != nil {
+					return nil, err
+				}
+				if !match {
+					return nil, sqlerrors.NewUndefinedFunctionError(tree.AsString(fn))
+				}
+			}
+
+			descs = append(descs, descriptor)
+		}
+
+		if len(descs) == 0 {
+			return nil, errNoMatch
+		}
+		return descs, nil
+	}
+
 	if targets.Schemas != nil {
 		if len(targets.Schemas) == 0 {
 			return nil, errNoSchema
@@ -812,6 +843,34 @@ func (p *planner) getQualifiedTypeName(
 	return &typeName, nil
 }
 
+// getQualifiedFunctionName returns the database-qualified name of the
+// function represented by the provided descriptor.
+func (p *planner) getQualifiedFunctionName(
+	ctx context.Context, desc catalog.FunctionDescriptor,
+) (*tree.TableName, error) {
+	_, dbDesc, err := p.Descriptors().GetImmutableDatabaseByID(ctx, p.txn, desc.GetParentID(),
+		tree.DatabaseLookupFlags{
+			Required: true,
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	scDesc, err := p.Descriptors().GetImmutableSchemaByID(
+		ctx, p.txn, desc.GetParentSchemaID(), tree.SchemaLookupFlags{},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	fnName := tree.MakeTableNameWithSchema(
+		tree.Name(dbDesc.GetName()),
+		tree.Name(scDesc.GetName()),
+		tree.Name(desc.GetName()),
+	)
+	return &fnName, nil
+}
+
 // findTableContainingIndex returns the descriptor of a table
 // containing the index of the given name.
 // This is used by expandMutableIndexName().
@@ -1353,6 +1412,68 @@ func (p *planner) ResolveMutableTypeDescriptor(
 	return prefix, desc, nil
 }
 
+// ResolveMutableFunctionDescriptor resolves a function descriptor for mutable
+// access.
+func (p *planner) ResolveMutableFunctionDescriptor(
+	ctx context.Context, name *tree.UnresolvedObjectName, required bool,
+) (catalog.ResolvedObjectPrefix, *funcdesc.Mutable, error) {
+	prefix, desc, err := resolver.ResolveMutableFunction(ctx, p, name, required)
+	if err != nil {
+		return catalog.ResolvedObjectPrefix{}, nil, err
+	}
+
+	if desc != nil {
+		// Ensure that the user can access the target schema.
+		if err := p.canResolveDescUnderSchema(ctx, prefix.Schema, desc); err != nil {
+			return catalog.ResolvedObjectPrefix{}, nil, err
+		}
+	}
+
+	return prefix, desc, nil
+}
+
+// ResolveFunction implements the tree.FunctionResolver interface. It returns
+// nil if the name does not refer to a user-defined function.
+func (p *planner) ResolveFunction(
+	ctx context.Context, name *tree.UnresolvedName,
+) (*tree.FunctionDefinition, error) {
+	un, err := name.ToUnresolvedObjectName(tree.NoAnnotation)
+	if err != nil {
+		return nil, err
+	}
+	prefix, desc, err := resolver.ResolveFunction(ctx, p, un, false /* required */)
+	if err != nil || desc == nil {
+		return nil, err
+	}
+	if err := p.canResolveDescUnderSchema(ctx, prefix.Schema, desc); err != nil {
+		return nil, err
+	}
+	if err := p.CheckPrivilege(ctx, desc, privilege.EXECUTE); err != nil {
+		return nil, err
+	}
+
+	params := desc.GetParams()
+	paramTypes := make(tree.ArgTypes, len(params))
+	paramNames := make([]string, len(params))
+	for i := range params {
+		paramTypes[i].Name = params[i].Name
+		paramTypes[i].Typ = params[i].Type
+		paramNames[i] = params[i].Name
+	}
+	return tree.NewUDFDefinition(
+		desc.GetName(),
+		paramTypes,
+		desc.GetReturnType(),
+		desc.Volatility(),
+		&tree.UDFInfo{
+			ID:                     uint32(desc.GetID()),
+			ParamNames:             paramNames,
+			Body:                   desc.GetFunctionBody(),
+			ReturnsNullOnNullInput: desc.GetNullInputBehavior() == descpb.FunctionDescriptor_RETURNS_NULL_ON_NULL_INPUT,
+		},
+	), nil
+}
+
 // The versions below are part of the work for #34240.
 // TODO(radu): clean these up when everything is switched over.
 
@@ -0,0 +1,774 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvcoord"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/scheduledjobs"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/quotapool"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	pbtypes "github.com/gogo/protobuf/types"
+	io_prometheus_client "github.com/prometheus/client_model/go"
+)
+
+const (
+	// defaultRowLevelTTLSelectBatchSize is the default number of expired rows
+	// read at once by row-level TTL jobs.
+	defaultRowLevelTTLSelectBatchSize = 500
+	// defaultRowLevelTTLDeleteBatchSize is the default number of expired rows
+	// deleted per transaction by row-level TTL jobs.
+	defaultRowLevelTTLDeleteBatchSize = 100
+	// rowLevelTTLAdmissionPriority is the admission control priority of the
+	// reads and the deletes of row-level TTL jobs, which makes them yield to
+	// foreground traffic when the nodes are overloaded.
+	rowLevelTTLAdmissionPriority = admission.LowPri
+	// rowLevelTTLProgressInterval is the minimum interval between updates of
+	// the progress of row-level TTL jobs.
+	rowLevelTTLProgressInterval = 10 * time.Second
+)
+
+// copyRowLevelTTL returns a copy of the row-level TTL of a table, which is
+// nil if the table has none.
+func copyRowLevelTTL(
+	ttl *descpb.TableDescriptor_RowLevelTTL,
+) *descpb.TableDescriptor_RowLevelTTL {
+	if ttl == nil {
+		return nil
+	}
+	ret := *ttl
+	return &ret
+}
+
+// updateRowLevelTTLSchedule creates, updates or deletes the schedule of the
+// job deleting the expired rows of the table after its row-level TTL changed
+// from oldTTL. The ID of a created schedule is stored in the descriptor, which
+// must be written afterwards.
+func (p *planner) updateRowLevelTTLSchedule(
+	ctx context.Context, tableDesc *tabledesc.Mutable, oldTTL *descpb.TableDescriptor_RowLevelTTL,
+) error {
+	ttl := tableDesc.GetRowLevelTTL()
+	if ttl == nil {
+		if oldTTL == nil {
+			return nil
+		}
+		return p.deleteTableSchedule(ctx, oldTTL.ScheduleID)
+	}
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.RowLevelTTL) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to use row-level TTL", clusterversion.RowLevelTTL)
+	}
+	scheduleID, err := p.upsertTableSchedule(
+		ctx,
+		ttl.ScheduleID,
+		ttl.DeletionCronOrDefault(),
+		fmt.Sprintf("row-level-ttl-%d", tableDesc.GetID()),
+		tree.ScheduledRowLevelTTLExecutor,
+		&jobspb.RowLevelTTLDetails{TableID: tableDesc.GetID()},
+	)
+	if err != nil {
+		return err
+	}
+	ttl.ScheduleID = scheduleID
+	return nil
+}
+
+// RowLevelTTLMetrics are the metrics of the jobs deleting the expired rows of
+// tables with row-level TTL.
+type RowLevelTTLMetrics struct {
+	RowsSelected    *metric.Counter
+	RowsDeleted     *metric.Counter
+	RangesProcessed *metric.Counter
+	SelectDuration  *metric.Histogram
+	DeleteDuration  *metric.Histogram
+}
+
+var _ metric.Struct = &RowLevelTTLMetrics{}
+
+// MetricStruct implements the metric.Struct interface.
+func (m *RowLevelTTLMetrics) MetricStruct() {}
+
+var (
+	metaRowLevelTTLRowsSelected = metric.Metadata{
+		Name:        "jobs.row_level_ttl.rows_selected",
+		Help:        "Number of expired rows read by row-level TTL jobs",
+		Measurement: "Rows",
+		Unit:        metric.Unit_COUNT,
+		MetricType:  io_prometheus_client.MetricType_COUNTER,
+	}
+	metaRowLevelTTLRowsDeleted = metric.Metadata{
+		Name:        "jobs.row_level_ttl.rows_deleted",
+		Help:        "Number of expired rows deleted by row-level TTL jobs",
+		Measurement: "Rows",
+		Unit:        metric.Unit_COUNT,
+		MetricType:  io_prometheus_client.MetricType_COUNTER,
+	}
+	metaRowLevelTTLRangesProcessed = metric.Metadata{
+		Name:        "jobs.row_level_ttl.ranges_processed",
+		Help:        "Number of ranges whose expired rows were deleted by row-level TTL jobs",
+		Measurement: "Ranges",
+		Unit:        metric.Unit_COUNT,
+		MetricType:  io_prometheus_client.MetricType_COUNTER,
+	}
+	metaRowLevelTTLSelectDuration = metric.Metadata{
+		Name:        "jobs.row_level_ttl.select_duration",
+		Help:        "Latency of the reads of expired rows by row-level TTL jobs",
+		Measurement: "Latency",
+		Unit:        metric.Unit_NANOSECONDS,
+		MetricType:  io_prometheus_client.MetricType_HISTOGRAM,
+	}
+	metaRowLevelTTLDeleteDuration = metric.Metadata{
+		Name:        "jobs.row_level_ttl.delete_duration",
+		Help:        "Latency of the deletes of expired rows by row-level TTL jobs",
+		Measurement: "Latency",
+		Unit:        metric.Unit_NANOSECONDS,
+		MetricType:  io_prometheus_client.MetricType_HISTOGRAM,
+	}
+)
+
+func makeRowLevelTTLMetrics(histogramWindowInterval time.Duration) metric.Struct {
+	return &RowLevelTTLMetrics{
+		RowsSelected:    metric.NewCounter(metaRowLevelTTLRowsSelected),
+		RowsDeleted:     metric.NewCounter(metaRowLevelTTLRowsDeleted),
+		RangesProcessed: metric.NewCounter(metaRowLevelTTLRangesProcessed),
+		SelectDuration:  metric.NewLatency(metaRowLevelTTLSelectDuration, histogramWindowInterval),
+		DeleteDuration:  metric.NewLatency(metaRowLevelTTLDeleteDuration, histogramWindowInterval),
+	}
+}
+
+// rowLevelTTLResumer deletes the expired rows of a table, that is the rows
+// last written more than the ttl_expire_after of the table ago.
+//
+// The primary index of the table is processed one range at a time: the
+// expired rows of a range are read in batches at a fixed timestamp and deleted
+// in smaller batches, at the rate allowed by the ttl_delete_rate_limit of the
+// table. Both the reads and the deletes are subject to admission control with
+// a low priority, so that they yield to foreground traffic.
+//
+// The rows are deleted by regular transactional deletes rather than cleared,
+// so their previous versions stay readable until they are garbage collected,
+// which the protected timestamp records of backups prevent: deleting expired
+// rows never affects backups in progress or incremental backups.
+type rowLevelTTLResumer struct {
+	job *jobs.Job
+	st  *cluster.Settings
+	sj  *jobs.ScheduledJob
+}
+
+var _ jobs.Resumer = &rowLevelTTLResumer{}
+
+// rowLevelTTLTable is the state of a table with row-level TTL read by the
+// rowLevelTTLResumer.
+type rowLevelTTLTable struct {
+	desc      catalog.TableDescriptor
+	name      tree.TableName
+	ttl       descpb.TableDescriptor_RowLevelTTL
+	pkColumns []string
+	pkTypes   []*types.T
+	pkDirs    []descpb.IndexDescriptor_Direction
+}
+
+// Resume implements the jobs.Resumer interface.
+func (r *rowLevelTTLResumer) Resume(ctx context.Context, execCtx interface{}) error {
+	p := execCtx.(JobExecContext)
+	execCfg := p.ExecCfg()
+	details := r.job.Details().(jobspb.RowLevelTTLDetails)
+	log.Infof(ctx, "starting row-level TTL job for table %d", details.TableID)
+
+	var err error
+	if r.sj, err = markJobScheduleRunning(ctx, execCfg, r.job.ID()); err != nil {
+		return err
+	}
+
+	table, err := r.readTable(ctx, execCfg, details.TableID)
+	if err != nil {
+		return err
+	}
+	if table == nil {
+		// The table was dropped, or no longer has a row-level TTL.
+		return r.maybeNotifyJobTerminated(ctx, execCfg, jobs.StatusSucceeded)
+	}
+	if table.ttl.Pause {
+		log.Infof(ctx, "row-level TTL of table %d is paused", details.TableID)
+		return r.maybeNotifyJobTerminated(ctx, execCfg, jobs.StatusSucceeded)
+	}
+
+	var progress jobspb.RowLevelTTLProgress
+	if err := r.deleteExpiredRows(ctx, execCfg, table, &progress); err != nil {
+		return err
+	}
+	if err := r.job.SetProgress(ctx, nil /* txn */, progress); err != nil {
+		return err
+	}
+	return r.maybeNotifyJobTerminated(ctx, execCfg, jobs.StatusSucceeded)
+}
+
+// readTable reads the row-level TTL and the primary key of the table, or
+// returns nil if the table does not exist or no longer has a row-level TTL.
+func (r *rowLevelTTLResumer) readTable(
+	ctx context.Context, execCfg *ExecutorConfig, tableID descpb.ID,
+) (*rowLevelTTLTable, error) {
+	var table *rowLevelTTLTable
+	err := DescsTxn(ctx, execCfg, func(ctx context.Context, txn *kv.Txn, col *descs.Collection) error {
+		table = nil
+		flags := tree.ObjectLookupFlagsWithRequired()
+		flags.IncludeDropped = true
+		flags.IncludeOffline = true
+		desc, err := col.GetImmutableTableByID(ctx, txn, tableID, flags)
+		if err != nil {
+			if errors.Is(err, catalog.ErrDescriptorNotFound) {
+				return nil
+			}
+			return err
+		}
+		if !desc.Public() || !desc.HasRowLevelTTL() {
+			return nil
+		}
+		_, dbDesc, err := col.GetImmutableDatabaseByID(ctx, txn, desc.GetParentID(),
+			tree.DatabaseLookupFlags{Required: true})
+		if err != nil {
+			return err
+		}
+		scDesc, err := col.GetImmutableSchemaByID(ctx, txn, desc.GetParentSchemaID(),
+			tree.SchemaLookupFlags{Required: true})
+		if err != nil {
+			return err
+		}
+		table = &rowLevelTTLTable{
+			desc: desc,
+			name: tree.MakeTableNameWithSchema(
+				tree.Name(dbDesc.GetName()), tree.Name(scDesc.GetName()), tree.Name(desc.GetName()),
+			),
+			ttl: *desc.GetRowLevelTTL(),
+		}
+		pk := desc.GetPrimaryIndex()
+		for i := 0; i < pk.NumKeyColumns(); i++ {
+			column, err := desc.FindColumnWithID(pk.GetKeyColumnID(i))
+			if err != nil {
+				return err
+			}
+			table.pkColumns = append(table.pkColumns, tree.NameString(column.GetName()))
+			table.pkTypes = append(table.pkTypes, column.GetType())
+			table.pkDirs = append(table.pkDirs, pk.GetKeyColumnDirection(i))
+		}
+		return nil
+	})
+	return table, err
+}
+
+// deleteExpiredRows deletes the expired rows of each range of the primary
+// index of the table.
+func (r *rowLevelTTLResumer) deleteExpiredRows(
+	ctx context.Context,
+	execCfg *ExecutorConfig,
+	table *rowLevelTTLTable,
+	progress *jobspb.RowLevelTTLProgress,
+) error {
+	expireAfter, err := tree.ParseDInterval(duration.IntervalStyle_POSTGRES, table.ttl.ExpireAfter)
+	if err != nil {
+		return err
+	}
+	readTS := execCfg.Clock.Now()
+	cutoff := hlc.Timestamp{
+		WallTime: duration.Add(readTS.GoTime(), expireAfter.Duration.Mul(-1)).UnixNano(),
+	}
+	d := rowLevelTTLDeleter{
+		execCfg:         execCfg,
+		table:           table,
+		metrics:         execCfg.JobRegistry.MetricsStruct().RowLevelTTL.(*RowLevelTTLMetrics),
+		readTS:          readTS,
+		cutoff:          tree.TimestampToDecimalDatum(cutoff),
+		selectBatchSize: table.ttl.SelectBatchSize,
+		deleteBatchSize: table.ttl.DeleteBatchSize,
+	}
+	if d.selectBatchSize == 0 {
+		d.selectBatchSize = defaultRowLevelTTLSelectBatchSize
+	}
+	if d.deleteBatchSize == 0 {
+		d.deleteBatchSize = defaultRowLevelTTLDeleteBatchSize
+	}
+	if rate := table.ttl.DeleteRateLimit; rate > 0 {
+		burst := rate
+		if burst < d.deleteBatchSize {
+			burst = d.deleteBatchSize
+		}
+		d.limiter = quotapool.NewRateLimiter("row-level-ttl-delete", quotapool.Limit(rate), burst)
+	}
+
+	// The rows of interleaved tables are stored in the ranges of their
+	// ancestor, so the ranges of their own span do not bound them.
+	if table.desc.IsInterleaved() {
+		return d.processSpan(ctx, nil /* startKey */, nil /* endKey */, progress)
+	}
+
+	span := table.desc.PrimaryIndexSpan(execCfg.Codec)
+	rSpan, err := keys.SpanAddr(span)
+	if err != nil {
+		return err
+	}
+	every := util.Every(rowLevelTTLProgressInterval)
+	ri := kvcoord.NewRangeIterator(execCfg.DistSender)
+	for ri.Seek(ctx, rSpan.Key, kvcoord.Ascending); ; ri.Next(ctx) {
+		if !ri.Valid() {
+			return ri.Error()
+		}
+		startKey, endKey := rSpan.Key, rSpan.EndKey
+		if desc := ri.Desc(); startKey.Less(desc.StartKey) {
+			startKey = desc.StartKey
+		}
+		if desc := ri.Desc(); desc.EndKey.Less(endKey) {
+			endKey = desc.EndKey
+		}
+		if err := d.processSpan(ctx, startKey.AsRawKey(), endKey.AsRawKey(), progress); err != nil {
+			return errors.Wrapf(err, "deleting the expired rows of range r%d", ri.Desc().RangeID)
+		}
+		progress.RangesProcessed++
+		d.metrics.RangesProcessed.Inc(1)
+		if !ri.NeedAnother(rSpan) {
+			return nil
+		}
+		if every.ShouldProcess(timeutil.Now()) {
+			if err := r.job.SetProgress(ctx, nil /* txn */, *progress); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// OnFailOrCancel implements the jobs.Resumer interface.
+func (r *rowLevelTTLResumer) OnFailOrCancel(ctx context.Context, execCtx interface{}) error {
+	p := execCtx.(JobExecContext)
+	return r.maybeNotifyJobTerminated(ctx, p.ExecCfg(), jobs.StatusFailed)
+}
+
+// maybeNotifyJobTerminated notifies the schedule which created the job, if
+// any, of the job termination.
+func (r *rowLevelTTLResumer) maybeNotifyJobTerminated(
+	ctx context.Context, execCfg *ExecutorConfig, status jobs.Status,
+) error {
+	log.Infof(ctx, "row-level TTL job terminated with status = %s", status)
+	if r.sj == nil {
+		return nil
+	}
+	return jobs.NotifyJobTermination(
+		ctx, tableSchedulerEnv(execCfg), r.job.ID(), status, r.job.Details(),
+		r.sj.ScheduleID(), execCfg.InternalExecutor, nil, /* txn */
+	)
+}
+
+// rowLevelTTLDeleter deletes the expired rows of the spans of the primary index
+// of a table.
+type rowLevelTTLDeleter struct {
+	execCfg *ExecutorConfig
+	table   *rowLevelTTLTable
+	metrics *RowLevelTTLMetrics
+	// readTS is the timestamp at which the expired rows are read.
+	readTS hlc.Timestamp
+	// cutoff is the MVCC timestamp before which the rows are expired.
+	cutoff          *tree.DDecimal
+	selectBatchSize int64
+	deleteBatchSize int64
+	// limiter paces the deletes, if the table has a delete rate limit.
+	limiter *quotapool.RateLimiter
+}
+
+// processSpan deletes the expired rows of the primary index between two keys,
+// which are nil when unbounded.
+func (d *rowLevelTTLDeleter) processSpan(
+	ctx context.Context, startKey, endKey roachpb.Key, progress *jobspb.RowLevelTTLProgress,
+) error {
+	var startBound, endBound tree.Datums
+	var endInclusive bool
+	if startKey != nil {
+		startBound, _ = d.decodeBound(startKey)
+	}
+	if endKey != nil {
+		endBound, endInclusive = d.decodeBound(endKey)
+	}
+	orderBy := make([]string, len(d.table.pkColumns))
+	for i, col := range d.table.pkColumns {
+		orderBy[i] = col
+		if d.table.pkDirs[i] == descpb.IndexDescriptor_DESC {
+			orderBy[i] += " DESC"
+		}
+	}
+
+	ie := d.execCfg.InternalExecutor
+	override := sessiondata.InternalExecutorOverride{User: security.RootUserName()}
+	var lastPK tree.Datums
+	for {
+		conds := []string{"crdb_internal_mvcc_timestamp < $1"}
+		args := []interface{}{d.cutoff}
+		var cond string
+		if lastPK != nil {
+			cond, args = rowLevelTTLBoundCond(d.table.pkColumns, d.table.pkDirs, lastPK, true /* after */, false /* inclusive */, args)
+		} else {
+			cond, args = rowLevelTTLBoundCond(d.table.pkColumns, d.table.pkDirs, startBound, true /* after */, true /* inclusive */, args)
+		}
+		if cond != "" {
+			conds = append(conds, cond)
+		}
+		cond, args = rowLevelTTLBoundCond(d.table.pkColumns, d.table.pkDirs, endBound, false /* after */, endInclusive, args)
+		if cond != "" {
+			conds = append(conds, cond)
+		}
+		stmt := fmt.Sprintf("SELECT %s FROM %s WHERE %s ORDER BY %s LIMIT %d",
+			strings.Join(d.table.pkColumns, ", "), d.table.name.FQString(),
+			strings.Join(conds, " AND "), strings.Join(orderBy, ", "), d.selectBatchSize)
+
+		start := timeutil.Now()
+		var rows []tree.Datums
+		if err := d.execCfg.DB.TxnWithAdmissionControl(
+			ctx, roachpb.AdmissionHeader_FROM_SQL, rowLevelTTLAdmissionPriority,
+			func(ctx context.Context, txn *kv.Txn) error {
+				if err := txn.SetFixedTimestamp(ctx, d.readTS); err != nil {
+					return err
+				}
+				var err error
+				rows, err = ie.QueryBufferedEx(ctx, "row-level-ttl-select", txn, override, stmt, args...)
+				return err
+			},
+		); err != nil {
+			return errors.Wrap(err, "reading expired rows")
+		}
+		d.metrics.SelectDuration.RecordValue(timeutil.Since(start).Nanoseconds())
+		d.metrics.RowsSelected.Inc(int64(len(rows)))
+
+		for i := 0; i < len(rows); i += int(d.deleteBatchSize) {
+			batch := rows[i:]
+			if len(batch) > int(d.deleteBatchSize) {
+				batch = batch[:d.deleteBatchSize]
+			}
+			deleted, err := d.deleteRows(ctx, batch)
+			if err != nil {
+				return errors.Wrap(err, "deleting expired rows")
+			}
+			progress.RowsDeleted += int64(deleted)
+		}
+		if int64(len(rows)) < d.selectBatchSize {
+			return nil
+		}
+		lastPK = rows[len(rows)-1]
+	}
+}
+
+// deleteRows deletes the rows with the given primary keys which are still
+// expired, and returns the number of deleted rows.
+func (d *rowLevelTTLDeleter) deleteRows(ctx context.Context, pks []tree.Datums) (int, error) {
+	if d.limiter != nil {
+		if err := d.limiter.WaitN(ctx, int64(len(pks))); err != nil {
+			return 0, err
+		}
+	}
+	args := make([]interface{}, 0, 1+len(pks)*len(d.table.pkColumns))
+	args = append(args, d.cutoff)
+	tuples := make([]string, len(pks))
+	for i, pk := range pks {
+		placeholders := make([]string, len(pk))
+		for j, datum := range pk {
+			args = append(args, datum)
+			placeholders[j] = fmt.Sprintf("$%d", len(args))
+		}
+		tuples[i] = "(" + strings.Join(placeholders, ", ") + ")"
+	}
+	// The rows are checked to be expired again, as they may have been written
+	// since they were read.
+	stmt := fmt.Sprintf(
+		"DELETE FROM %s WHERE crdb_internal_mvcc_timestamp < $1 AND (%s) IN (%s)",
+		d.table.name.FQString(), strings.Join(d.table.pkColumns, ", "), strings.Join(tuples, ", "))
+
+	start := timeutil.Now()
+	var deleted int
+	if err := d.execCfg.DB.TxnWithAdmissionControl(
+		ctx, roachpb.AdmissionHeader_FROM_SQL, rowLevelTTLAdmissionPriority,
+		func(ctx context.Context, txn *kv.Txn) error {
+			var err error
+			deleted, err = d.execCfg.InternalExecutor.ExecEx(
+				ctx, "row-level-ttl-delete", txn,
+				sessiondata.InternalExecutorOverride{User: security.RootUserName()},
+				stmt, args...,
+			)
+			return err
+		},
+	); err != nil {
+		return 0, err
+	}
+	d.metrics.DeleteDuration.RecordValue(timeutil.Since(start).Nanoseconds())
+	d.metrics.RowsDeleted.Inc(int64(deleted))
+	return deleted, nil
+}
+
+// decodeBound decodes the values of the primary key columns encoded in a key
+// bounding a span of the primary index, such as the boundary of a range. A
+// range boundary may end in the middle of a primary key, so the values of the
+// longest prefix of the primary key columns fully encoded in the key are
+// returned, along with whether the key has bytes left after them. No values
+// are returned if the key is not in the primary index.
+func (d *rowLevelTTLDeleter) decodeBound(key roachpb.Key) (_ tree.Datums, partial bool) {
+	rest, err := d.execCfg.Codec.StripTenantPrefix(key)
+	if err != nil {
+		return nil, false
+	}
+	rest, tableID, indexID, err := rowenc.DecodePartialTableIDIndexID(rest)
+	if err != nil || tableID != d.table.desc.GetID() || indexID != d.table.desc.GetPrimaryIndexID() {
+		return nil, false
+	}
+	var alloc rowenc.DatumAlloc
+	var bound tree.Datums
+	for i, typ := range d.table.pkTypes {
+		if len(rest) == 0 {
+			break
+		}
+		enc := descpb.DatumEncoding_ASCENDING_KEY
+		if d.table.pkDirs[i] == descpb.IndexDescriptor_DESC {
+			enc = descpb.DatumEncoding_DESCENDING_KEY
+		}
+		ed, next, err := rowenc.EncDatumFromBuffer(typ, enc, rest)
+		if err != nil {
+			break
+		}
+		if err := ed.EnsureDecoded(typ, &alloc); err != nil || ed.Datum == tree.DNull {
+			break
+		}
+		bound = append(bound, ed.Datum)
+		rest = next
+	}
+	return bound, len(rest) > 0
+}
+
+// rowLevelTTLBoundCond returns a condition on the primary key columns which
+// selects the rows whose primary key sorts, in the order of the primary index,
+// after the given bound, if after is set, or before it otherwise. The bound
+// may hold values for a prefix of the primary key columns only; the rows
+// whose columns are equal to the bound also satisfy the condition if
+// inclusive is set. The values of the bound are appended to args, which are
+// referenced by placeholders in the condition. The condition is empty if the
+// bound is.
+func rowLevelTTLBoundCond(
+	cols []string,
+	dirs []descpb.IndexDescriptor_Direction,
+	bound tree.Datums,
+	after bool,
+	inclusive bool,
+	args []interface{},
+) (string, []interface{}) {
+	if len(bound) == 0 {
+		return "", args
+	}
+	placeholders := make([]string, len(bound))
+	for i, datum := range bound {
+		args = append(args, datum)
+		placeholders[i] = fmt.Sprintf("$%d", len(args))
+	}
+	var terms []string
+	var eqs []string
+	for i := range bound {
+		op := "<"
+		if after == (dirs[i] == descpb.IndexDescriptor_ASC) {
+			op = ">"
+		}
+		cmp := fmt.Sprintf("%s %s %s", cols[i], op, placeholders[i])
+		terms = append(terms, strings.Join(append(eqs[:len(eqs):len(eqs)], cmp), " AND "))
+		eqs = append(eqs, fmt.Sprintf("%s = %s", cols[i], placeholders[i]))
+	}
+	if inclusive {
+		terms = append(terms, strings.Join(eqs, " AND "))
+	}
+	for i := range terms {
+		terms[i] = "(" + terms[i] + ")"
+	}
+	return "(" + strings.Join(terms, " OR ") + ")", args
+}
+
+type rowLevelTTLScheduleMetrics struct {
+	*jobs.ExecutorMetrics
+}
+
+var _ metric.Struct = &rowLevelTTLScheduleMetrics{}
+
+// MetricStruct implements metric.Struct interface.
+func (m *rowLevelTTLScheduleMetrics) MetricStruct() {}
+
+// scheduledRowLevelTTLExecutor is executed by the scheduled job subsystem to
+// launch rowLevelTTLResumer through the job subsystem.
+type scheduledRowLevelTTLExecutor struct {
+	metrics rowLevelTTLScheduleMetrics
+}
+
+var _ jobs.ScheduledJobExecutor = &scheduledRowLevelTTLExecutor{}
+var _ jobs.ScheduledJobController = &scheduledRowLevelTTLExecutor{}
+
+func rowLevelTTLScheduleArgs(sj *jobs.ScheduledJob) (*jobspb.RowLevelTTLDetails, error) {
+	args := &jobspb.RowLevelTTLDetails{}
+	if err := pbtypes.UnmarshalAny(sj.ExecutionArgs().Args, args); err != nil {
+		return nil, errors.Wrap(err, "un-marshaling args")
+	}
+	return args, nil
+}
+
+// OnDrop implements the jobs.ScheduledJobController interface.
+func (e *scheduledRowLevelTTLExecutor) OnDrop(
+	ctx context.Context,
+	scheduleControllerEnv scheduledjobs.ScheduleControllerEnv,
+	env scheduledjobs.JobSchedulerEnv,
+	schedule *jobs.ScheduledJob,
+	txn *kv.Txn,
+) error {
+	args, err := rowLevelTTLScheduleArgs(schedule)
+	if err != nil {
+		return err
+	}
+	return errors.WithHint(
+		pgerror.Newf(pgcode.InvalidParameterValue,
+			"schedule %d deletes the expired rows of table %d and cannot be dropped",
+			schedule.ScheduleID(), args.TableID),
+		"use ALTER TABLE ... RESET (ttl_expire_after) on the table instead.",
+	)
+}
+
+// ExecuteJob implements the jobs.ScheduledJobExecutor interface.
+func (e *scheduledRowLevelTTLExecutor) ExecuteJob(
+	ctx context.Context,
+	cfg *scheduledjobs.JobExecutionConfig,
+	env scheduledjobs.JobSchedulerEnv,
+	sj *jobs.ScheduledJob,
+	txn *kv.Txn,
+) error {
+	if err := e.createRowLevelTTLJob(ctx, cfg, sj, txn); err != nil {
+		e.metrics.NumFailed.Inc(1)
+		return err
+	}
+	e.metrics.NumStarted.Inc(1)
+	return nil
+}
+
+func (e *scheduledRowLevelTTLExecutor) createRowLevelTTLJob(
+	ctx context.Context, cfg *scheduledjobs.JobExecutionConfig, sj *jobs.ScheduledJob, txn *kv.Txn,
+) error {
+	args, err := rowLevelTTLScheduleArgs(sj)
+	if err != nil {
+		return err
+	}
+	p, cleanup := cfg.PlanHookMaker("invoke-row-level-ttl", txn, security.NodeUserName())
+	defer cleanup()
+
+	registry := p.(*planner).ExecCfg().JobRegistry
+	record := jobs.Record{
+		Description:   fmt.Sprintf("row-level TTL of table %d", args.TableID),
+		Username:      security.NodeUserName(),
+		DescriptorIDs: descpb.IDs{args.TableID},
+		Details:       *args,
+		Progress:      jobspb.RowLevelTTLProgress{},
+		CreatedBy: &jobs.CreatedByInfo{
+			ID:   sj.ScheduleID(),
+			Name: jobs.CreatedByScheduledJobs,
+		},
+	}
+	_, err = registry.CreateAdoptableJobWithTxn(ctx, record, registry.MakeJobID(), txn)
+	return err
+}
+
+// NotifyJobTermination implements the jobs.ScheduledJobExecutor interface.
+func (e *scheduledRowLevelTTLExecutor) NotifyJobTermination(
+	ctx context.Context,
+	jobID jobspb.JobID,
+	jobStatus jobs.Status,
+	details jobspb.Details,
+	env scheduledjobs.JobSchedulerEnv,
+	sj *jobs.ScheduledJob,
+	ex sqlutil.InternalExecutor,
+	txn *kv.Txn,
+) error {
+	if jobStatus == jobs.StatusFailed {
+		jobs.DefaultHandleFailedRun(sj, "row-level TTL %d failed", jobID)
+		e.metrics.NumFailed.Inc(1)
+		return nil
+	}
+	if jobStatus == jobs.StatusSucceeded {
+		e.metrics.NumSucceeded.Inc(1)
+	}
+	sj.SetScheduleStatus(string(jobStatus))
+	return nil
+}
+
+// Metrics implements the jobs.ScheduledJobExecutor interface.
+func (e *scheduledRowLevelTTLExecutor) Metrics() metric.Struct {
+	return &e.metrics
+}
+
+// GetCreateScheduleStatement implements the jobs.ScheduledJobExecutor interface.
+func (e *scheduledRowLevelTTLExecutor) GetCreateScheduleStatement(
+	ctx context.Context,
+	env scheduledjobs.JobSchedulerEnv,
+	txn *kv.Txn,
+	sj *jobs.ScheduledJob,
+	ex sqlutil.InternalExecutor,
+) (string, error) {
+	args, err := rowLevelTTLScheduleArgs(sj)
+	if err != nil {
+		return "", err
+	}
+	return "", errors.Newf(
+		"schedule %d is created by the ttl_expire_after storage parameter of table %d",
+		sj.ScheduleID(), args.TableID)
+}
+
+func init() {
+	jobs.MakeRowLevelTTLMetricsHook = makeRowLevelTTLMetrics
+
+	jobs.RegisterConstructor(jobspb.TypeRowLevelTTL, func(job *jobs.Job, settings *cluster.Settings) jobs.Resumer {
+		return &rowLevelTTLResumer{
+			job: job,
+			st:  settings,
+		}
+	})
+
+	jobs.RegisterScheduledJobExecutorFactory(
+		tree.ScheduledRowLevelTTLExecutor.InternalName(),
+		func() (jobs.ScheduledJobExecutor, error) {
+			m := jobs.MakeExecutorMetrics(tree.ScheduledRowLevelTTLExecutor.InternalName())
+			return &scheduledRowLevelTTLExecutor{
+				metrics: rowLevelTTLScheduleMetrics{
+					ExecutorMetrics: &m,
+				},
+			}, nil
+		})
+}
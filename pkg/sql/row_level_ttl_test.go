@@ -0,0 +1,81 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRowLevelTTLBoundCond tests the conditions bounding the primary keys of
+// the rows read by row-level TTL jobs.
+func TestRowLevelTTLBoundCond(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	asc, desc := descpb.IndexDescriptor_ASC, descpb.IndexDescriptor_DESC
+	cols := []string{"a", "b", "c"}
+	for _, tc := range []struct {
+		name      string
+		dirs      []descpb.IndexDescriptor_Direction
+		bound     tree.Datums
+		after     bool
+		inclusive bool
+		expected  string
+	}{
+		{
+			name:     "unbounded",
+			dirs:     []descpb.IndexDescriptor_Direction{asc, asc, asc},
+			after:    true,
+			expected: "",
+		},
+		{
+			name:     "after",
+			dirs:     []descpb.IndexDescriptor_Direction{asc, asc, asc},
+			bound:    tree.Datums{tree.NewDInt(1), tree.NewDInt(2)},
+			after:    true,
+			expected: "((a > $2) OR (a = $2 AND b > $3))",
+		},
+		{
+			name:      "after inclusive",
+			dirs:      []descpb.IndexDescriptor_Direction{asc, asc, asc},
+			bound:     tree.Datums{tree.NewDInt(1), tree.NewDInt(2)},
+			after:     true,
+			inclusive: true,
+			expected:  "((a > $2) OR (a = $2 AND b > $3) OR (a = $2 AND b = $3))",
+		},
+		{
+			name:     "before",
+			dirs:     []descpb.IndexDescriptor_Direction{asc, asc, asc},
+			bound:    tree.Datums{tree.NewDInt(1), tree.NewDInt(2), tree.NewDInt(3)},
+			expected: "((a < $2) OR (a = $2 AND b < $3) OR (a = $2 AND b = $3 AND c < $4))",
+		},
+		{
+			name:     "descending",
+			dirs:     []descpb.IndexDescriptor_Direction{desc, asc, asc},
+			bound:    tree.Datums{tree.NewDInt(1), tree.NewDInt(2)},
+			after:    true,
+			expected: "((a < $2) OR (a = $2 AND b > $3))",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			args := []interface{}{tree.DNull}
+			cond, args := rowLevelTTLBoundCond(cols, tc.dirs, tc.bound, tc.after, tc.inclusive, args)
+			require.Equal(t, tc.expected, cond)
+			require.Len(t, args, 1+len(tc.bound))
+		})
+	}
+}
@@ -4864,6 +4864,29 @@ value if you rely on the HLC for accuracy.`,
 		},
 	),
 
+	"crdb_internal.check_row_level_security": makeBuiltin(
+		tree.FunctionProperties{
+			Category:     categorySystemInfo,
+			NullableArgs: true,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"ok", types.Bool}, {"table_name", types.String}},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				if args[0] == tree.DBoolTrue {
+					return tree.DBoolTrue, nil
+				}
+				tableName, _ := tree.AsDString(args[1])
+				return nil, pgerror.Newf(pgcode.InsufficientPrivilege,
+					"new row violates row-level security policy for table %q", string(tableName))
+			},
+			Info: "Returns true if `ok` is true, and raises an error otherwise. This " +
+				"function is used to check that the rows written to a table satisfy its " +
+				"row-level security policies.",
+			Volatility: tree.VolatilityVolatile,
+		},
+	),
+
 	"crdb_internal.notice": makeBuiltin(
 		tree.FunctionProperties{
 			Category: categorySystemInfo,
@@ -5983,6 +6006,28 @@ value if you rely on the HLC for accuracy.`,
 		},
 	),
 
+	// grouping is replaced by the optimizer with the bitmask of its arguments
+	// which are not grouped in the current grouping set; it is never evaluated.
+	"grouping": makeBuiltin(
+		tree.FunctionProperties{
+			NullableArgs: true,
+		},
+		tree.Overload{
+			Types: tree.VariadicType{
+				VarType: types.Any,
+			},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return nil, pgerror.New(pgcode.Grouping,
+					"grouping() can only be used in the SELECT list, HAVING or ORDER BY clause of a grouped query")
+			},
+			Info: "Returns a bit mask indicating which of its arguments, which must be " +
+				"GROUP BY expressions, are not included in the current grouping set. " +
+				"The last argument corresponds to the least significant bit.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+
 	GatewayRegionBuiltinName: makeBuiltin(
 		tree.FunctionProperties{
 			Category: categoryMultiRegion,
@@ -17,12 +17,14 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/advisorylock"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catconstants"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -58,6 +60,43 @@ func makeNotUsableFalseBuiltin() builtinDefinition {
 	}
 }
 
+func advisoryLockProps() tree.FunctionProperties {
+	return tree.FunctionProperties{
+		Category:         categorySystemInfo,
+		DistsqlBlocklist: true,
+	}
+}
+
+// makeAdvisoryLockBuiltin creates an advisory lock builtin with the two
+// overloads Postgres provides: one identifying the lock by an int8 value and
+// one by a pair of int4 values.
+func makeAdvisoryLockBuiltin(
+	info string, fn func(ctx *tree.EvalContext, key advisorylock.Key) (tree.Datum, error),
+) builtinDefinition {
+	return makeBuiltin(advisoryLockProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"key", types.Int}},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return fn(ctx, advisorylock.MakeKey(int64(tree.MustBeDInt(args[0]))))
+			},
+			Info:       info,
+			Volatility: tree.VolatilityVolatile,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"key1", types.Int4}, {"key2", types.Int4}},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return fn(ctx, advisorylock.MakePairKey(
+					int32(tree.MustBeDInt(args[0])), int32(tree.MustBeDInt(args[1])),
+				))
+			},
+			Info:       info,
+			Volatility: tree.VolatilityVolatile,
+		},
+	)
+}
+
 // typeBuiltinsHaveUnderscore is a map to keep track of which types have i/o
 // builtins with underscores in between their type name and the i/o builtin
 // name, like date_in vs int8in. There seems to be no other way to
@@ -614,10 +653,14 @@ var pgBuiltins = map[string]builtinDefinition{
 		tree.Overload{
 			Types:      tree.ArgTypes{},
 			ReturnType: tree.FixedReturnType(types.Int),
-			Fn: func(_ *tree.EvalContext, _ tree.Datums) (tree.Datum, error) {
-				return tree.NewDInt(-1), nil
+			Fn: func(ctx *tree.EvalContext, _ tree.Datums) (tree.Datum, error) {
+				pid, err := ctx.SessionAccessor.BackendPID(ctx.Context)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDInt(tree.DInt(pid)), nil
 			},
-			Info:       notUsableInfo,
+			Info:       "Returns the process ID reported to the client for the current session.",
 			Volatility: tree.VolatilityStable,
 		},
 	),
@@ -1048,26 +1091,114 @@ SELECT description
 		},
 	),
 
-	"pg_try_advisory_lock": makeBuiltin(defProps(),
+	// The advisory lock functions return true where Postgres returns void,
+	// like pg_sleep. The shared variants are not supported.
+	// https://www.postgresql.org/docs/current/functions-admin.html#FUNCTIONS-ADVISORY-LOCKS
+	"pg_advisory_lock": makeAdvisoryLockBuiltin(
+		"Obtains an exclusive session-level advisory lock, waiting if necessary.",
+		func(ctx *tree.EvalContext, key advisorylock.Key) (tree.Datum, error) {
+			if _, err := ctx.Planner.AcquireAdvisoryLock(ctx.Ctx(), key, false /* xact */, true /* wait */); err != nil {
+				return nil, err
+			}
+			return tree.DBoolTrue, nil
+		},
+	),
+
+	"pg_advisory_xact_lock": makeAdvisoryLockBuiltin(
+		"Obtains an exclusive transaction-level advisory lock, waiting if necessary.",
+		func(ctx *tree.EvalContext, key advisorylock.Key) (tree.Datum, error) {
+			if _, err := ctx.Planner.AcquireAdvisoryLock(ctx.Ctx(), key, true /* xact */, true /* wait */); err != nil {
+				return nil, err
+			}
+			return tree.DBoolTrue, nil
+		},
+	),
+
+	"pg_try_advisory_lock": makeAdvisoryLockBuiltin(
+		"Obtains an exclusive session-level advisory lock if available. "+
+			"Returns whether the lock was obtained.",
+		func(ctx *tree.EvalContext, key advisorylock.Key) (tree.Datum, error) {
+			acquired, err := ctx.Planner.AcquireAdvisoryLock(ctx.Ctx(), key, false /* xact */, false /* wait */)
+			if err != nil {
+				return nil, err
+			}
+			return tree.MakeDBool(tree.DBool(acquired)), nil
+		},
+	),
+
+	"pg_try_advisory_xact_lock": makeAdvisoryLockBuiltin(
+		"Obtains an exclusive transaction-level advisory lock if available. "+
+			"Returns whether the lock was obtained.",
+		func(ctx *tree.EvalContext, key advisorylock.Key) (tree.Datum, error) {
+			acquired, err := ctx.Planner.AcquireAdvisoryLock(ctx.Ctx(), key, true /* xact */, false /* wait */)
+			if err != nil {
+				return nil, err
+			}
+			return tree.MakeDBool(tree.DBool(acquired)), nil
+		},
+	),
+
+	"pg_advisory_unlock": makeAdvisoryLockBuiltin(
+		"Releases a previously-acquired exclusive session-level advisory lock. "+
+			"Returns whether the lock was held.",
+		func(ctx *tree.EvalContext, key advisorylock.Key) (tree.Datum, error) {
+			released, err := ctx.Planner.ReleaseAdvisoryLock(ctx.Ctx(), key)
+			if err != nil {
+				return nil, err
+			}
+			if !released && ctx.ClientNoticeSender != nil {
+				ctx.ClientNoticeSender.BufferClientNotice(
+					ctx.Context,
+					pgnotice.NewWithSeverityf("WARNING", "you don't own a lock of type ExclusiveLock"),
+				)
+			}
+			return tree.MakeDBool(tree.DBool(released)), nil
+		},
+	),
+
+	"pg_advisory_unlock_all": makeBuiltin(advisoryLockProps(),
 		tree.Overload{
-			Types:      tree.ArgTypes{{"int", types.Int}},
+			Types:      tree.ArgTypes{},
 			ReturnType: tree.FixedReturnType(types.Bool),
-			Fn: func(_ *tree.EvalContext, _ tree.Datums) (tree.Datum, error) {
+			Fn: func(ctx *tree.EvalContext, _ tree.Datums) (tree.Datum, error) {
+				if err := ctx.Planner.ReleaseAllAdvisoryLocks(ctx.Ctx()); err != nil {
+					return nil, err
+				}
 				return tree.DBoolTrue, nil
 			},
-			Info:       notUsableInfo,
+			Info:       "Releases all session-level advisory locks held by the current session.",
 			Volatility: tree.VolatilityVolatile,
 		},
 	),
 
-	"pg_advisory_unlock": makeBuiltin(defProps(),
+	// pg_notify sends a notification on the given channel, like NOTIFY does.
+	// Postgres returns void; we return true instead, like pg_sleep.
+	// https://www.postgresql.org/docs/current/sql-notify.html
+	"pg_notify": makeBuiltin(
+		tree.FunctionProperties{
+			Category:         categorySystemInfo,
+			NullableArgs:     true,
+			DistsqlBlocklist: true,
+		},
 		tree.Overload{
-			Types:      tree.ArgTypes{{"int", types.Int}},
+			Types:      tree.ArgTypes{{"channel", types.String}, {"payload", types.String}},
 			ReturnType: tree.FixedReturnType(types.Bool),
-			Fn: func(_ *tree.EvalContext, _ tree.Datums) (tree.Datum, error) {
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				var channel, payload string
+				if args[0] != tree.DNull {
+					channel = string(tree.MustBeDString(args[0]))
+				}
+				if args[1] != tree.DNull {
+					payload = string(tree.MustBeDString(args[1]))
+				}
+				if err := ctx.Planner.Notify(ctx.Ctx(), channel, payload); err != nil {
+					return nil, err
+				}
 				return tree.DBoolTrue, nil
 			},
-			Info:       notUsableInfo,
+			Info: "Sends a notification with the given payload on the given channel. " +
+				"The notification is delivered to the listening sessions when the " +
+				"current transaction commits.",
 			Volatility: tree.VolatilityVolatile,
 		},
 	),
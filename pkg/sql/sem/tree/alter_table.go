@@ -78,6 +78,9 @@ func (*AlterTableSetVisible) alterTableCmd()         {}
 func (*AlterTableValidateConstraint) alterTableCmd() {}
 func (*AlterTablePartitionByTable) alterTableCmd()   {}
 func (*AlterTableInjectStats) alterTableCmd()        {}
+func (*AlterTableSetStorageParams) alterTableCmd()   {}
+func (*AlterTableResetStorageParams) alterTableCmd() {}
+func (*AlterTableRowLevelSecurity) alterTableCmd()   {}
 
 var _ AlterTableCmd = &AlterTableAddColumn{}
 var _ AlterTableCmd = &AlterTableAddConstraint{}
@@ -96,6 +99,9 @@ var _ AlterTableCmd = &AlterTableSetVisible{}
 var _ AlterTableCmd = &AlterTableValidateConstraint{}
 var _ AlterTableCmd = &AlterTablePartitionByTable{}
 var _ AlterTableCmd = &AlterTableInjectStats{}
+var _ AlterTableCmd = &AlterTableSetStorageParams{}
+var _ AlterTableCmd = &AlterTableResetStorageParams{}
+var _ AlterTableCmd = &AlterTableRowLevelSecurity{}
 
 // ColumnMutationCmd is the subset of AlterTableCmds that modify an
 // existing column.
@@ -593,6 +599,58 @@ func (node *AlterTableSetAudit) Format(ctx *FmtCtx) {
 	ctx.WriteString(node.Mode.String())
 }
 
+// AlterTableSetStorageParams represents an ALTER TABLE SET (...) command.
+type AlterTableSetStorageParams struct {
+	StorageParams StorageParams
+}
+
+// TelemetryCounter implements the AlterTableCmd interface.
+func (node *AlterTableSetStorageParams) TelemetryCounter() telemetry.Counter {
+	return sqltelemetry.SchemaChangeAlterCounterWithExtra("table", "set_storage_param")
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTableSetStorageParams) Format(ctx *FmtCtx) {
+	ctx.WriteString(" SET (")
+	ctx.FormatNode(&node.StorageParams)
+	ctx.WriteString(")")
+}
+
+// AlterTableResetStorageParams represents an ALTER TABLE RESET (...) command.
+type AlterTableResetStorageParams struct {
+	Params NameList
+}
+
+// TelemetryCounter implements the AlterTableCmd interface.
+func (node *AlterTableResetStorageParams) TelemetryCounter() telemetry.Counter {
+	return sqltelemetry.SchemaChangeAlterCounterWithExtra("table", "reset_storage_param")
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTableResetStorageParams) Format(ctx *FmtCtx) {
+	ctx.WriteString(" RESET (")
+	ctx.FormatNode(&node.Params)
+	ctx.WriteString(")")
+}
+
+// AlterTableRowLevelSecurity represents an ALTER TABLE ... ROW LEVEL SECURITY
+// command.
+type AlterTableRowLevelSecurity struct {
+	Mode RowLevelSecurityMode
+}
+
+// TelemetryCounter implements the AlterTableCmd interface.
+func (node *AlterTableRowLevelSecurity) TelemetryCounter() telemetry.Counter {
+	return sqltelemetry.SchemaChangeAlterCounterWithExtra("table", "row_level_security")
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTableRowLevelSecurity) Format(ctx *FmtCtx) {
+	ctx.WriteByte(' ')
+	ctx.WriteString(node.Mode.String())
+	ctx.WriteString(" ROW LEVEL SECURITY")
+}
+
 // AlterTableInjectStats represents an ALTER TABLE INJECT STATISTICS statement.
 type AlterTableInjectStats struct {
 	Stats Expr
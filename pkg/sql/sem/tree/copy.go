@@ -23,6 +23,16 @@ type CopyFrom struct {
 	Options CopyOptions
 }
 
+// CopyTo represents a COPY TO STDOUT statement. Exactly one of Table and
+// Statement is set: COPY t TO STDOUT copies out the contents of a table, while
+// COPY (query) TO STDOUT copies out the results of a query.
+type CopyTo struct {
+	Table     TableName
+	Columns   NameList
+	Statement Statement
+	Options   CopyOptions
+}
+
 // CopyOptions describes options for COPY execution.
 type CopyOptions struct {
 	Destination Expr
@@ -30,6 +40,15 @@ type CopyOptions struct {
 	Delimiter   Expr
 	Null        Expr
 	Escape      *StrVal
+	Header      bool
+	Quote       *StrVal
+	// ForceQuoteAll is set by FORCE QUOTE *, in which case ForceQuote is empty.
+	ForceQuoteAll bool
+	ForceQuote    NameList
+	ForceNotNull  NameList
+	ForceNull     NameList
+	Encoding      *StrVal
+	Freeze        bool
 }
 
 var _ NodeFormatter = &CopyOptions{}
@@ -53,6 +72,28 @@ func (node *CopyFrom) Format(ctx *FmtCtx) {
 	}
 }
 
+// Format implements the NodeFormatter interface.
+func (node *CopyTo) Format(ctx *FmtCtx) {
+	ctx.WriteString("COPY ")
+	if node.Statement != nil {
+		ctx.WriteString("(")
+		ctx.FormatNode(node.Statement)
+		ctx.WriteString(")")
+	} else {
+		ctx.FormatNode(&node.Table)
+		if len(node.Columns) > 0 {
+			ctx.WriteString(" (")
+			ctx.FormatNode(&node.Columns)
+			ctx.WriteString(")")
+		}
+	}
+	ctx.WriteString(" TO STDOUT")
+	if !node.Options.IsDefault() {
+		ctx.WriteString(" WITH ")
+		ctx.FormatNode(&node.Options)
+	}
+}
+
 // Format implements the NodeFormatter interface
 func (o *CopyOptions) Format(ctx *FmtCtx) {
 	var addSep bool
@@ -97,11 +138,50 @@ func (o *CopyOptions) Format(ctx *FmtCtx) {
 		ctx.WriteString("ESCAPE ")
 		ctx.FormatNode(o.Escape)
 	}
+	if o.Header {
+		maybeAddSep()
+		ctx.WriteString("HEADER")
+	}
+	if o.Quote != nil {
+		maybeAddSep()
+		ctx.WriteString("QUOTE ")
+		ctx.FormatNode(o.Quote)
+	}
+	if o.ForceQuoteAll {
+		maybeAddSep()
+		ctx.WriteString("FORCE QUOTE *")
+	} else if len(o.ForceQuote) > 0 {
+		maybeAddSep()
+		ctx.WriteString("FORCE QUOTE ")
+		ctx.FormatNode(&o.ForceQuote)
+	}
+	if len(o.ForceNotNull) > 0 {
+		maybeAddSep()
+		ctx.WriteString("FORCE NOT NULL ")
+		ctx.FormatNode(&o.ForceNotNull)
+	}
+	if len(o.ForceNull) > 0 {
+		maybeAddSep()
+		ctx.WriteString("FORCE NULL ")
+		ctx.FormatNode(&o.ForceNull)
+	}
+	if o.Encoding != nil {
+		maybeAddSep()
+		ctx.WriteString("ENCODING ")
+		ctx.FormatNode(o.Encoding)
+	}
+	if o.Freeze {
+		maybeAddSep()
+		ctx.WriteString("FREEZE")
+	}
 }
 
 // IsDefault returns true if this struct has default value.
-func (o CopyOptions) IsDefault() bool {
-	return o == CopyOptions{}
+func (o *CopyOptions) IsDefault() bool {
+	return o.Destination == nil && o.CopyFormat == CopyFormatText && o.Delimiter == nil &&
+		o.Null == nil && o.Escape == nil && !o.Header && o.Quote == nil && !o.ForceQuoteAll &&
+		len(o.ForceQuote) == 0 && len(o.ForceNotNull) == 0 && len(o.ForceNull) == 0 &&
+		o.Encoding == nil && !o.Freeze
 }
 
 // CombineWith merges other options into this struct. An error is returned if
@@ -137,6 +217,49 @@ func (o *CopyOptions) CombineWith(other *CopyOptions) error {
 		}
 		o.Escape = other.Escape
 	}
+	if other.Header {
+		if o.Header {
+			return pgerror.Newf(pgcode.Syntax, "header option specified multiple times")
+		}
+		o.Header = true
+	}
+	if other.Quote != nil {
+		if o.Quote != nil {
+			return pgerror.Newf(pgcode.Syntax, "quote option specified multiple times")
+		}
+		o.Quote = other.Quote
+	}
+	if other.ForceQuoteAll || len(other.ForceQuote) > 0 {
+		if o.ForceQuoteAll || len(o.ForceQuote) > 0 {
+			return pgerror.Newf(pgcode.Syntax, "force quote option specified multiple times")
+		}
+		o.ForceQuoteAll = other.ForceQuoteAll
+		o.ForceQuote = other.ForceQuote
+	}
+	if len(other.ForceNotNull) > 0 {
+		if len(o.ForceNotNull) > 0 {
+			return pgerror.Newf(pgcode.Syntax, "force not null option specified multiple times")
+		}
+		o.ForceNotNull = other.ForceNotNull
+	}
+	if len(other.ForceNull) > 0 {
+		if len(o.ForceNull) > 0 {
+			return pgerror.Newf(pgcode.Syntax, "force null option specified multiple times")
+		}
+		o.ForceNull = other.ForceNull
+	}
+	if other.Encoding != nil {
+		if o.Encoding != nil {
+			return pgerror.Newf(pgcode.Syntax, "encoding option specified multiple times")
+		}
+		o.Encoding = other.Encoding
+	}
+	if other.Freeze {
+		if o.Freeze {
+			return pgerror.Newf(pgcode.Syntax, "freeze option specified multiple times")
+		}
+		o.Freeze = true
+	}
 	return nil
 }
 
@@ -454,6 +454,7 @@ type ColumnTableDef struct {
 		ConstraintName Name
 		Actions        ReferenceActions
 		Match          CompositeKeyMatchMethod
+		Deferrable     ConstraintDeferrability
 	}
 	Computed struct {
 		Computed bool
@@ -635,6 +636,7 @@ func NewColumnTableDef(
 			d.References.ConstraintName = c.Name
 			d.References.Actions = t.Actions
 			d.References.Match = t.Match
+			d.References.Deferrable = t.Deferrable
 		case *ColumnComputedDef:
 			if d.GeneratedIdentity.IsGeneratedAsIdentity {
 				return nil, pgerror.Newf(pgcode.Syntax,
@@ -788,6 +790,7 @@ func (node *ColumnTableDef) Format(ctx *FmtCtx) {
 			ctx.WriteString(node.References.Match.String())
 		}
 		ctx.FormatNode(&node.References.Actions)
+		ctx.FormatNode(node.References.Deferrable)
 	}
 	if node.IsComputed() {
 		ctx.WriteString(" AS (")
@@ -914,10 +917,11 @@ type ColumnCheckConstraint struct {
 
 // ColumnFKConstraint represents a FK-constaint on a column.
 type ColumnFKConstraint struct {
-	Table   TableName
-	Col     Name // empty-string means use PK
-	Actions ReferenceActions
-	Match   CompositeKeyMatchMethod
+	Table      TableName
+	Col        Name // empty-string means use PK
+	Actions    ReferenceActions
+	Match      CompositeKeyMatchMethod
+	Deferrable ConstraintDeferrability
 }
 
 // ColumnComputedDef represents the description of a computed column.
@@ -1007,6 +1011,7 @@ type UniqueConstraintTableDef struct {
 	IndexTableDef
 	PrimaryKey   bool
 	WithoutIndex bool
+	Deferrable   ConstraintDeferrability
 }
 
 // SetName implements the TableDef interface.
@@ -1046,6 +1051,7 @@ func (node *UniqueConstraintTableDef) Format(ctx *FmtCtx) {
 	if node.PartitionByIndex != nil {
 		ctx.FormatNode(node.PartitionByIndex)
 	}
+	ctx.FormatNode(node.Deferrable)
 	if node.Predicate != nil {
 		ctx.WriteString(" WHERE ")
 		ctx.FormatNode(node.Predicate)
@@ -1119,14 +1125,54 @@ func (c CompositeKeyMatchMethod) String() string {
 	return compositeKeyMatchMethodName[c]
 }
 
+// ConstraintDeferrability specifies whether the validation of a constraint
+// can be deferred until the end of the transaction, and whether it is deferred
+// by default. See SET CONSTRAINTS.
+type ConstraintDeferrability int
+
+// The values for ConstraintDeferrability.
+const (
+	NotDeferrable ConstraintDeferrability = iota
+	DeferrableInitiallyImmediate
+	DeferrableInitiallyDeferred
+)
+
+var constraintDeferrabilityName = [...]string{
+	NotDeferrable:                "NOT DEFERRABLE",
+	DeferrableInitiallyImmediate: "DEFERRABLE INITIALLY IMMEDIATE",
+	DeferrableInitiallyDeferred:  "DEFERRABLE INITIALLY DEFERRED",
+}
+
+func (d ConstraintDeferrability) String() string {
+	return constraintDeferrabilityName[d]
+}
+
+// IsDeferrable returns true if the validation of the constraint can be
+// deferred until the end of the transaction.
+func (d ConstraintDeferrability) IsDeferrable() bool {
+	return d != NotDeferrable
+}
+
+// Format implements the NodeFormatter interface. NOT DEFERRABLE is omitted
+// because it is the default.
+func (d ConstraintDeferrability) Format(ctx *FmtCtx) {
+	switch d {
+	case DeferrableInitiallyImmediate:
+		ctx.WriteString(" DEFERRABLE")
+	case DeferrableInitiallyDeferred:
+		ctx.WriteString(" DEFERRABLE INITIALLY DEFERRED")
+	}
+}
+
 // ForeignKeyConstraintTableDef represents a FOREIGN KEY constraint in the AST.
 type ForeignKeyConstraintTableDef struct {
-	Name     Name
-	Table    TableName
-	FromCols NameList
-	ToCols   NameList
-	Actions  ReferenceActions
-	Match    CompositeKeyMatchMethod
+	Name       Name
+	Table      TableName
+	FromCols   NameList
+	ToCols     NameList
+	Actions    ReferenceActions
+	Match      CompositeKeyMatchMethod
+	Deferrable ConstraintDeferrability
 }
 
 // Format implements the NodeFormatter interface.
@@ -1154,6 +1200,7 @@ func (node *ForeignKeyConstraintTableDef) Format(ctx *FmtCtx) {
 	}
 
 	ctx.FormatNode(&node.Actions)
+	ctx.FormatNode(node.Deferrable)
 }
 
 // SetName implements the ConstraintTableDef interface.
@@ -1312,9 +1359,13 @@ func (node *PartitionByTable) ContainsPartitioningClause() bool {
 // structs for table and index definitions respectively.
 type PartitionBy struct {
 	Fields NameList
-	// Exactly one of List or Range is required to be non-empty.
+	// Exactly one of List or Range is required to be non-empty, unless
+	// Interval is set.
 	List  []ListPartition
 	Range []RangePartition
+	// Interval is set for a PARTITION BY RANGE ... INTERVAL, whose partitions
+	// are managed automatically. Range holds the initial partitions, if any.
+	Interval *PartitionInterval
 }
 
 // Format implements the NodeFormatter interface.
@@ -1330,11 +1381,18 @@ func (node *PartitionBy) formatListOrRange(ctx *FmtCtx) {
 	}
 	if len(node.List) > 0 {
 		ctx.WriteString(`LIST (`)
-	} else if len(node.Range) > 0 {
+	} else if len(node.Range) > 0 || node.Interval != nil {
 		ctx.WriteString(`RANGE (`)
 	}
 	ctx.FormatNode(&node.Fields)
-	ctx.WriteString(`) (`)
+	ctx.WriteString(`)`)
+	if node.Interval != nil {
+		ctx.FormatNode(node.Interval)
+		if len(node.Range) == 0 {
+			return
+		}
+	}
+	ctx.WriteString(` (`)
 	for i := range node.List {
 		if i > 0 {
 			ctx.WriteString(", ")
@@ -1350,6 +1408,25 @@ func (node *PartitionBy) formatListOrRange(ctx *FmtCtx) {
 	ctx.WriteString(`)`)
 }
 
+// PartitionInterval represents the INTERVAL clause of a PARTITION BY RANGE on
+// a timestamp column, whose partitions each cover an interval of time and are
+// created and dropped by a scheduled job.
+type PartitionInterval struct {
+	Interval Expr
+	Options  KVOptions
+}
+
+// Format implements the NodeFormatter interface.
+func (node *PartitionInterval) Format(ctx *FmtCtx) {
+	ctx.WriteString(` INTERVAL `)
+	ctx.FormatNode(node.Interval)
+	if node.Options != nil {
+		ctx.WriteString(` OPTIONS (`)
+		ctx.FormatNode(&node.Options)
+		ctx.WriteByte(')')
+	}
+}
+
 // ListPartition represents a PARTITION definition within a PARTITION BY LIST.
 type ListPartition struct {
 	Name         UnrestrictedName
@@ -1443,6 +1520,8 @@ type CreateTable struct {
 	Defs     TableDefs
 	AsSource *Select
 	Locality *Locality
+	// Foreign is set for CREATE FOREIGN TABLE statements.
+	Foreign *ForeignTableDef
 }
 
 // As returns true if this table represents a CREATE TABLE ... AS statement,
@@ -1475,6 +1554,9 @@ func (node *CreateTable) Format(ctx *FmtCtx) {
 	case PersistenceUnlogged:
 		ctx.WriteString("UNLOGGED ")
 	}
+	if node.Foreign != nil {
+		ctx.WriteString("FOREIGN ")
+	}
 	ctx.WriteString("TABLE ")
 	if node.IfNotExists {
 		ctx.WriteString("IF NOT EXISTS ")
@@ -1483,6 +1565,14 @@ func (node *CreateTable) Format(ctx *FmtCtx) {
 	node.FormatBody(ctx)
 }
 
+func (node *CreateTable) formatStorageParams(ctx *FmtCtx) {
+	if node.StorageParams != nil {
+		ctx.WriteString(" WITH (")
+		ctx.FormatNode(&node.StorageParams)
+		ctx.WriteByte(')')
+	}
+}
+
 // FormatBody formats the "body" of the create table definition - everything
 // but the CREATE TABLE tableName part.
 func (node *CreateTable) FormatBody(ctx *FmtCtx) {
@@ -1492,6 +1582,7 @@ func (node *CreateTable) FormatBody(ctx *FmtCtx) {
 			ctx.FormatNode(&node.Defs)
 			ctx.WriteByte(')')
 		}
+		node.formatStorageParams(ctx)
 		ctx.WriteString(" AS ")
 		ctx.FormatNode(node.AsSource)
 	} else {
@@ -1504,12 +1595,15 @@ func (node *CreateTable) FormatBody(ctx *FmtCtx) {
 		if node.PartitionByTable != nil {
 			ctx.FormatNode(node.PartitionByTable)
 		}
-		// No storage parameters are implemented, so we never list the storage
-		// parameters in the output format.
+		node.formatStorageParams(ctx)
 		if node.Locality != nil {
 			ctx.WriteString(" ")
 			ctx.FormatNode(node.Locality)
 		}
+		if node.Foreign != nil {
+			ctx.WriteByte(' ')
+			ctx.FormatNode(node.Foreign)
+		}
 	}
 }
 
@@ -1559,12 +1653,13 @@ func (node *CreateTable) HoistConstraints() {
 					targetCol = append(targetCol, col.References.Col)
 				}
 				node.Defs = append(node.Defs, &ForeignKeyConstraintTableDef{
-					Table:    *col.References.Table,
-					FromCols: NameList{col.Name},
-					ToCols:   targetCol,
-					Name:     col.References.ConstraintName,
-					Actions:  col.References.Actions,
-					Match:    col.References.Match,
+					Table:      *col.References.Table,
+					FromCols:   NameList{col.Name},
+					ToCols:     targetCol,
+					Name:       col.References.ConstraintName,
+					Actions:    col.References.Actions,
+					Match:      col.References.Match,
+					Deferrable: col.References.Deferrable,
 				})
 				col.References.Table = nil
 			}
@@ -93,11 +93,18 @@ type DropTable struct {
 	Names        TableNames
 	IfExists     bool
 	DropBehavior DropBehavior
+	// Foreign is set for DROP FOREIGN TABLE statements, which only drop
+	// foreign tables.
+	Foreign bool
 }
 
 // Format implements the NodeFormatter interface.
 func (node *DropTable) Format(ctx *FmtCtx) {
-	ctx.WriteString("DROP TABLE ")
+	ctx.WriteString("DROP ")
+	if node.Foreign {
+		ctx.WriteString("FOREIGN ")
+	}
+	ctx.WriteString("TABLE ")
 	if node.IfExists {
 		ctx.WriteString("IF EXISTS ")
 	}
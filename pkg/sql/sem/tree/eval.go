@@ -30,6 +30,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/advisorylock"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
@@ -3237,6 +3238,26 @@ type EvalPlanner interface {
 	// error if validation fails or if constraintName is not actually a unique
 	// constraint on the table.
 	RevalidateUniqueConstraint(ctx context.Context, tableID int, constraintName string) error
+
+	// Notify sends a notification with the given payload on the given channel
+	// as part of the current transaction. It is used by pg_notify().
+	Notify(ctx context.Context, channel, payload string) error
+
+	// AcquireAdvisoryLock acquires the advisory lock with the given key in the
+	// current database, at the transaction level if xact is set and at the
+	// session level otherwise. If wait is false, it returns false instead of
+	// waiting when the lock is held by another session. It is used by
+	// pg_advisory_lock() and friends.
+	AcquireAdvisoryLock(ctx context.Context, key advisorylock.Key, xact, wait bool) (bool, error)
+
+	// ReleaseAdvisoryLock releases the session-level advisory lock with the
+	// given key in the current database. It returns false if the session
+	// doesn't hold the lock. It is used by pg_advisory_unlock().
+	ReleaseAdvisoryLock(ctx context.Context, key advisorylock.Key) (bool, error)
+
+	// ReleaseAllAdvisoryLocks releases all the session-level advisory locks
+	// held by the session. It is used by pg_advisory_unlock_all().
+	ReleaseAllAdvisoryLocks(ctx context.Context) error
 }
 
 // CompactEngineSpanFunc is used to compact an engine key span at the given
@@ -3265,6 +3286,10 @@ type EvalSessionAccessor interface {
 	// HasRoleOption returns nil iff the current session user has the specified
 	// role option.
 	HasRoleOption(ctx context.Context, roleOption roleoption.Option) (bool, error)
+
+	// BackendPID returns the process ID reported to the client for the
+	// current session, in the BackendKeyData message and in notifications.
+	BackendPID(ctx context.Context) (int32, error)
 }
 
 // PreparedStatementState is a limited interface that exposes metadata about
@@ -3273,6 +3298,22 @@ type PreparedStatementState interface {
 	HasPrepared() bool
 }
 
+// DeferredConstraintState tracks the validation of deferrable constraints in
+// the current transaction. See SET CONSTRAINTS.
+type DeferredConstraintState interface {
+	// IsConstraintDeferred returns true if the validation of the named
+	// constraint, which has the given deferrability, is currently deferred
+	// until the end of the transaction.
+	IsConstraintDeferred(name string, deferrability ConstraintDeferrability) bool
+
+	// DeferConstraintCheck registers a key which violated a deferred foreign
+	// key or unique without index constraint of the given table when it was
+	// written. The constraint must be validated again for that key before the
+	// transaction commits. For foreign keys, the key contains the values of
+	// the origin or referenced columns, which are the same.
+	DeferConstraintCheck(tableID ID, name string, isFK bool, key Datums)
+}
+
 // ClientNoticeSender is a limited interface to send notices to the
 // client.
 //
@@ -3595,6 +3636,11 @@ type EvalContext struct {
 
 	PreparedStatementState PreparedStatementState
 
+	// DeferredConstraints tracks the constraints whose validation is deferred
+	// until the end of the transaction. It is nil if the statement is not
+	// executed by a session.
+	DeferredConstraints DeferredConstraintState
+
 	// The transaction in which the statement is executing.
 	Txn *kv.Txn
 	// A handle to the database.
@@ -4241,6 +4287,12 @@ func (expr *FuncExpr) MaybeWrapError(err error) error {
 
 // Eval implements the TypedExpr interface.
 func (expr *FuncExpr) Eval(ctx *EvalContext) (Datum, error) {
+	if expr.fn.UDF != nil {
+		// The body of a user-defined function is planned by the optimizer, and
+		// cannot be evaluated here.
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"user-defined function %s() cannot be evaluated in this context", expr.Func.String())
+	}
 	nullResult, args, err := expr.evalArgs(ctx)
 	if err != nil {
 		return nil, err
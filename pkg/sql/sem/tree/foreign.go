@@ -0,0 +1,79 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+// CreateServer represents a CREATE SERVER statement.
+type CreateServer struct {
+	Name        Name
+	IfNotExists bool
+	// Wrapper is the name of the foreign data wrapper of the server.
+	Wrapper Name
+	Options KVOptions
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateServer) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE SERVER ")
+	if node.IfNotExists {
+		ctx.WriteString("IF NOT EXISTS ")
+	}
+	ctx.FormatNode(&node.Name)
+	ctx.WriteString(" FOREIGN DATA WRAPPER ")
+	ctx.FormatNode(&node.Wrapper)
+	formatForeignOptions(ctx, node.Options)
+}
+
+// DropServer represents a DROP SERVER statement.
+type DropServer struct {
+	Name     Name
+	IfExists bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *DropServer) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP SERVER ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(&node.Name)
+}
+
+// ForeignTableDef represents the SERVER and OPTIONS clauses of a
+// CREATE FOREIGN TABLE statement.
+type ForeignTableDef struct {
+	Server  Name
+	Options KVOptions
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ForeignTableDef) Format(ctx *FmtCtx) {
+	ctx.WriteString("SERVER ")
+	ctx.FormatNode(&node.Server)
+	formatForeignOptions(ctx, node.Options)
+}
+
+// formatForeignOptions formats the OPTIONS clause of foreign servers and
+// tables, which unlike other option lists has no equal signs.
+func formatForeignOptions(ctx *FmtCtx, options KVOptions) {
+	if len(options) == 0 {
+		return
+	}
+	ctx.WriteString(" OPTIONS (")
+	for i := range options {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(&options[i].Key)
+		ctx.WriteByte(' ')
+		ctx.FormatNode(options[i].Value)
+	}
+	ctx.WriteByte(')')
+}
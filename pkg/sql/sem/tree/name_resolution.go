@@ -257,6 +257,9 @@ const (
 	TableObject DesiredObjectKind = iota
 	// TypeObject is used when a type-like object is desired from resolution.
 	TypeObject
+	// FunctionObject is used when a user-defined function is desired from
+	// resolution.
+	FunctionObject
 )
 
 // NewQualifiedObjectName returns an ObjectName of the corresponding kind.
@@ -264,7 +267,7 @@ const (
 // on what kind of object was requested.
 func NewQualifiedObjectName(catalog, schema, object string, kind DesiredObjectKind) ObjectName {
 	switch kind {
-	case TableObject:
+	case TableObject, FunctionObject:
 		name := MakeTableNameWithSchema(Name(catalog), Name(schema), Name(object))
 		return &name
 	case TypeObject:
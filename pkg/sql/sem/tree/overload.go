@@ -74,6 +74,10 @@ type Overload struct {
 	// statement which will be executed as a common table expression in the query.
 	SQLFn func(*EvalContext, Datums) (string, error)
 
+	// UDF is set for the overload of a user-defined function. The body of a
+	// user-defined function is planned by the optimizer, so Fn is nil.
+	UDF *UDFInfo
+
 	// counter, if non-nil, should be incremented upon successful
 	// type check of expressions using this overload.
 	counter telemetry.Counter
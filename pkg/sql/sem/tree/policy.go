@@ -0,0 +1,136 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+// PolicyCommand is the kind of statement a row-level security policy applies
+// to.
+type PolicyCommand uint8
+
+const (
+	// PolicyCommandAll is used for policies which apply to all statements.
+	PolicyCommandAll PolicyCommand = iota
+	// PolicyCommandSelect is used for policies which apply to the rows read by
+	// queries.
+	PolicyCommandSelect
+	// PolicyCommandInsert is used for policies which apply to inserted rows.
+	PolicyCommandInsert
+	// PolicyCommandUpdate is used for policies which apply to updated rows.
+	PolicyCommandUpdate
+	// PolicyCommandDelete is used for policies which apply to deleted rows.
+	PolicyCommandDelete
+)
+
+var policyCommandName = [...]string{
+	PolicyCommandAll:    "ALL",
+	PolicyCommandSelect: "SELECT",
+	PolicyCommandInsert: "INSERT",
+	PolicyCommandUpdate: "UPDATE",
+	PolicyCommandDelete: "DELETE",
+}
+
+func (c PolicyCommand) String() string {
+	return policyCommandName[c]
+}
+
+// CreatePolicy represents a CREATE POLICY statement.
+type CreatePolicy struct {
+	Name  Name
+	Table *UnresolvedObjectName
+	// Restrictive is set for AS RESTRICTIVE policies, which must be satisfied
+	// in addition to one of the permissive policies.
+	Restrictive bool
+	Command     PolicyCommand
+	// Roles is empty if the policy applies to all roles.
+	Roles NameList
+	// Using is nil if the policy has no USING expression.
+	Using Expr
+	// WithCheck is nil if the policy has no WITH CHECK expression.
+	WithCheck Expr
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreatePolicy) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE POLICY ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteString(" ON ")
+	ctx.FormatNode(node.Table)
+	if node.Restrictive {
+		ctx.WriteString(" AS RESTRICTIVE")
+	}
+	if node.Command != PolicyCommandAll {
+		ctx.WriteString(" FOR ")
+		ctx.WriteString(node.Command.String())
+	}
+	if len(node.Roles) > 0 {
+		ctx.WriteString(" TO ")
+		ctx.FormatNode(&node.Roles)
+	}
+	if node.Using != nil {
+		ctx.WriteString(" USING (")
+		ctx.FormatNode(node.Using)
+		ctx.WriteByte(')')
+	}
+	if node.WithCheck != nil {
+		ctx.WriteString(" WITH CHECK (")
+		ctx.FormatNode(node.WithCheck)
+		ctx.WriteByte(')')
+	}
+}
+
+// DropPolicy represents a DROP POLICY statement.
+type DropPolicy struct {
+	Name         Name
+	Table        *UnresolvedObjectName
+	IfExists     bool
+	DropBehavior DropBehavior
+}
+
+// Format implements the NodeFormatter interface.
+func (node *DropPolicy) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP POLICY ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(&node.Name)
+	ctx.WriteString(" ON ")
+	ctx.FormatNode(node.Table)
+	if node.DropBehavior != DropDefault {
+		ctx.WriteByte(' ')
+		ctx.WriteString(node.DropBehavior.String())
+	}
+}
+
+// RowLevelSecurityMode is a change of the row-level security of a table made
+// by ALTER TABLE.
+type RowLevelSecurityMode uint8
+
+const (
+	// RowLevelSecurityEnable enables the policies of the table.
+	RowLevelSecurityEnable RowLevelSecurityMode = iota
+	// RowLevelSecurityDisable disables the policies of the table.
+	RowLevelSecurityDisable
+	// RowLevelSecurityForce applies the policies of the table to its owner.
+	RowLevelSecurityForce
+	// RowLevelSecurityNoForce exempts the owner of the table from its
+	// policies.
+	RowLevelSecurityNoForce
+)
+
+var rowLevelSecurityModeName = [...]string{
+	RowLevelSecurityEnable:  "ENABLE",
+	RowLevelSecurityDisable: "DISABLE",
+	RowLevelSecurityForce:   "FORCE",
+	RowLevelSecurityNoForce: "NO FORCE",
+}
+
+func (m RowLevelSecurityMode) String() string {
+	return rowLevelSecurityModeName[m]
+}
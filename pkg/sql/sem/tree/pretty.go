@@ -1227,6 +1227,7 @@ func (node *CreateTable) doc(p *PrettyCfg) pretty.Doc {
 	//     [SELECT ...] - for CREATE TABLE AS
 	//     [INTERLEAVE ...]
 	//     [PARTITION BY ...]
+	//     [WITH ( ... )]
 	//
 	title := pretty.Keyword("CREATE")
 	switch node.Persistence {
@@ -1246,6 +1247,13 @@ func (node *CreateTable) doc(p *PrettyCfg) pretty.Doc {
 			title = pretty.ConcatSpace(title,
 				p.bracket("(", p.Doc(&node.Defs), ")"))
 		}
+		if node.StorageParams != nil {
+			title = pretty.ConcatSpace(title, p.bracketKeyword(
+				"WITH", " (",
+				p.Doc(&node.StorageParams),
+				")", "",
+			))
+		}
 		title = pretty.ConcatSpace(title, pretty.Keyword("AS"))
 	} else {
 		title = pretty.ConcatSpace(title,
@@ -1263,6 +1271,13 @@ func (node *CreateTable) doc(p *PrettyCfg) pretty.Doc {
 	if node.PartitionByTable != nil {
 		clauses = append(clauses, p.Doc(node.PartitionByTable))
 	}
+	if node.StorageParams != nil && !node.As() {
+		clauses = append(clauses, p.bracketKeyword(
+			"WITH", " (",
+			p.Doc(&node.StorageParams),
+			")", "",
+		))
+	}
 	if node.Locality != nil {
 		clauses = append(clauses, p.Doc(node.Locality))
 	}
@@ -1454,6 +1469,9 @@ func (node *PartitionBy) doc(p *PrettyCfg) pretty.Doc {
 	//
 	// PARTITION BY RANGE (...)
 	//    ( ..values.. )
+	//
+	// PARTITION BY RANGE (...) INTERVAL '...' [OPTIONS (...)]
+	//    [( ..values.. )]
 	return node.docInner(p, `PARTITION BY `)
 }
 
@@ -1463,11 +1481,17 @@ func (node *PartitionBy) docInner(p *PrettyCfg, kw string) pretty.Doc {
 	}
 	if len(node.List) > 0 {
 		kw += `LIST`
-	} else if len(node.Range) > 0 {
+	} else if len(node.Range) > 0 || node.Interval != nil {
 		kw += `RANGE`
 	}
 	title := pretty.ConcatSpace(pretty.Keyword(kw),
 		p.bracket("(", p.Doc(&node.Fields), ")"))
+	if node.Interval != nil {
+		title = pretty.ConcatSpace(title, p.Doc(node.Interval))
+		if len(node.Range) == 0 {
+			return title
+		}
+	}
 
 	inner := make([]pretty.Doc, 0, len(node.List)+len(node.Range))
 	for _, v := range node.List {
@@ -1740,6 +1764,7 @@ func (node *UniqueConstraintTableDef) doc(p *PrettyCfg) pretty.Doc {
 	//    [STORING ( ... )]
 	//    [INTERLEAVE ...]
 	//    [PARTITION BY ...]
+	//    [DEFERRABLE ...]
 	//    [WHERE ...]
 	//
 	// or (no constraint name):
@@ -1748,6 +1773,7 @@ func (node *UniqueConstraintTableDef) doc(p *PrettyCfg) pretty.Doc {
 	//    [STORING ( ... )]
 	//    [INTERLEAVE ...]
 	//    [PARTITION BY ...]
+	//    [DEFERRABLE ...]
 	//    [WHERE ...]
 	//
 	clauses := make([]pretty.Doc, 0, 5)
@@ -1780,6 +1806,9 @@ func (node *UniqueConstraintTableDef) doc(p *PrettyCfg) pretty.Doc {
 	if node.PartitionByIndex != nil {
 		clauses = append(clauses, p.Doc(node.PartitionByIndex))
 	}
+	if node.Deferrable.IsDeferrable() {
+		clauses = append(clauses, p.Doc(node.Deferrable))
+	}
 	if node.Predicate != nil {
 		clauses = append(clauses, p.nestUnder(pretty.Keyword("WHERE"), p.Doc(node.Predicate)))
 	}
@@ -1797,6 +1826,7 @@ func (node *ForeignKeyConstraintTableDef) doc(p *PrettyCfg) pretty.Doc {
 	//    REFERENCES tbl (...)
 	//    [MATCH ...]
 	//    [ACTIONS ...]
+	//    [DEFERRABLE ...]
 	//
 	// or (no constraint name):
 	//
@@ -1804,8 +1834,9 @@ func (node *ForeignKeyConstraintTableDef) doc(p *PrettyCfg) pretty.Doc {
 	//    REFERENCES tbl [(...)]
 	//    [MATCH ...]
 	//    [ACTIONS ...]
+	//    [DEFERRABLE ...]
 	//
-	clauses := make([]pretty.Doc, 0, 4)
+	clauses := make([]pretty.Doc, 0, 5)
 	title := pretty.ConcatSpace(
 		pretty.Keyword("FOREIGN KEY"),
 		p.bracket("(", p.Doc(&node.FromCols), ")"))
@@ -1830,6 +1861,10 @@ func (node *ForeignKeyConstraintTableDef) doc(p *PrettyCfg) pretty.Doc {
 		clauses = append(clauses, actions)
 	}
 
+	if node.Deferrable.IsDeferrable() {
+		clauses = append(clauses, p.Doc(node.Deferrable))
+	}
+
 	return p.nestUnder(title, pretty.Group(pretty.Stack(clauses...)))
 }
 
@@ -1993,6 +2028,9 @@ func (node *ColumnTableDef) docRow(p *PrettyCfg) pretty.TableRow {
 		if ref := p.Doc(&node.References.Actions); ref != pretty.Nil {
 			fkDetails = append(fkDetails, ref)
 		}
+		if node.References.Deferrable.IsDeferrable() {
+			fkDetails = append(fkDetails, p.Doc(node.References.Deferrable))
+		}
 		fk := fkHead
 		if len(fkDetails) > 0 {
 			fk = p.nestUnder(fk, pretty.Group(pretty.Stack(fkDetails...)))
@@ -2064,6 +2102,16 @@ func (node *ReferenceActions) doc(p *PrettyCfg) pretty.Doc {
 	return pretty.Fold(pretty.ConcatSpace, docs...)
 }
 
+func (d ConstraintDeferrability) doc(p *PrettyCfg) pretty.Doc {
+	switch d {
+	case DeferrableInitiallyImmediate:
+		return pretty.Keyword("DEFERRABLE")
+	case DeferrableInitiallyDeferred:
+		return pretty.Keyword("DEFERRABLE INITIALLY DEFERRED")
+	}
+	return pretty.Nil
+}
+
 func (node *Backup) doc(p *PrettyCfg) pretty.Doc {
 	items := make([]pretty.TableRow, 0, 6)
 
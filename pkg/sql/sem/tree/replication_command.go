@@ -0,0 +1,99 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+// The commands of the streaming replication protocol, which replication
+// connections can run besides SQL statements. They aren't part of the SQL
+// grammar, and are parsed by pgrepl.Parse instead.
+
+// IdentifySystem represents an IDENTIFY_SYSTEM replication command.
+type IdentifySystem struct{}
+
+// Format implements the NodeFormatter interface.
+func (node *IdentifySystem) Format(ctx *FmtCtx) {
+	ctx.WriteString("IDENTIFY_SYSTEM")
+}
+
+// CreateReplicationSlot represents a CREATE_REPLICATION_SLOT replication
+// command. Only logical replication slots can be created.
+type CreateReplicationSlot struct {
+	Slot      Name
+	Temporary bool
+	Plugin    Name
+	// Snapshot is what to do with the snapshot of the slot, i.e. "export",
+	// "nothing" or "use", or empty if unspecified.
+	Snapshot string
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateReplicationSlot) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE_REPLICATION_SLOT ")
+	ctx.FormatNode(&node.Slot)
+	if node.Temporary {
+		ctx.WriteString(" TEMPORARY")
+	}
+	ctx.WriteString(" LOGICAL ")
+	ctx.FormatNode(&node.Plugin)
+	if node.Snapshot != "" {
+		ctx.WriteString(" (SNAPSHOT ")
+		ctx.FormatNode(NewStrVal(node.Snapshot))
+		ctx.WriteByte(')')
+	}
+}
+
+// DropReplicationSlot represents a DROP_REPLICATION_SLOT replication command.
+type DropReplicationSlot struct {
+	Slot Name
+	Wait bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *DropReplicationSlot) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP_REPLICATION_SLOT ")
+	ctx.FormatNode(&node.Slot)
+	if node.Wait {
+		ctx.WriteString(" WAIT")
+	}
+}
+
+// StartReplication represents a START_REPLICATION replication command, which
+// streams the changes of a logical replication slot.
+type StartReplication struct {
+	Slot Name
+	// StartLSN is the position from which changes are requested, formatted as
+	// a PostgreSQL log sequence number.
+	StartLSN string
+	// Options are the options of the output plugin. Their values are string
+	// constants, or nil if unspecified.
+	Options KVOptions
+}
+
+// Format implements the NodeFormatter interface.
+func (node *StartReplication) Format(ctx *FmtCtx) {
+	ctx.WriteString("START_REPLICATION SLOT ")
+	ctx.FormatNode(&node.Slot)
+	ctx.WriteString(" LOGICAL ")
+	ctx.WriteString(node.StartLSN)
+	if len(node.Options) > 0 {
+		ctx.WriteString(" (")
+		for i := range node.Options {
+			if i > 0 {
+				ctx.WriteString(", ")
+			}
+			ctx.FormatNode(&node.Options[i].Key)
+			if node.Options[i].Value != nil {
+				ctx.WriteByte(' ')
+				ctx.FormatNode(node.Options[i].Value)
+			}
+		}
+		ctx.WriteByte(')')
+	}
+}
@@ -618,6 +618,47 @@ func (node *GroupBy) Format(ctx *FmtCtx) {
 	}
 }
 
+// GroupingSetsType represents the type of a GroupingSets element of a GROUP
+// BY clause.
+type GroupingSetsType int
+
+// The values for GroupingSetsType.
+const (
+	// RollupGroupingSets represents ROLLUP (a, b, ...).
+	RollupGroupingSets GroupingSetsType = iota
+	// CubeGroupingSets represents CUBE (a, b, ...).
+	CubeGroupingSets
+	// ExplicitGroupingSets represents GROUPING SETS (a, (b, c), ...).
+	ExplicitGroupingSets
+)
+
+var groupingSetsTypeName = [...]string{
+	RollupGroupingSets:   "ROLLUP",
+	CubeGroupingSets:     "CUBE",
+	ExplicitGroupingSets: "GROUPING SETS",
+}
+
+func (t GroupingSetsType) String() string {
+	return groupingSetsTypeName[t]
+}
+
+// GroupingSets represents a ROLLUP, CUBE or GROUPING SETS element of a GROUP
+// BY clause. Each of its expressions is either a scalar expression, a tuple
+// of scalar expressions grouped together, or (only for GROUPING SETS) a
+// nested GroupingSets element.
+type GroupingSets struct {
+	Type  GroupingSetsType
+	Exprs Exprs
+}
+
+// Format implements the NodeFormatter interface.
+func (node *GroupingSets) Format(ctx *FmtCtx) {
+	ctx.WriteString(node.Type.String())
+	ctx.WriteString(" (")
+	ctx.FormatNode(&node.Exprs)
+	ctx.WriteByte(')')
+}
+
 // DistinctOn represents a DISTINCT ON clause.
 type DistinctOn []Expr
 
@@ -754,12 +754,22 @@ const (
 	// ScheduledSQLStatsCompactionExecutor is an executor responsible for the
 	// execution of the scheduled SQL Stats compaction.
 	ScheduledSQLStatsCompactionExecutor
+
+	// ScheduledIntervalPartitioningExecutor is an executor responsible for
+	// creating and expiring the partitions of an interval partitioned table.
+	ScheduledIntervalPartitioningExecutor
+
+	// ScheduledRowLevelTTLExecutor is an executor responsible for deleting the
+	// expired rows of a table with row-level TTL.
+	ScheduledRowLevelTTLExecutor
 )
 
 var scheduleExecutorInternalNames = map[ScheduledJobExecutorType]string{
-	InvalidExecutor:                     "unknown-executor",
-	ScheduledBackupExecutor:             "scheduled-backup-executor",
-	ScheduledSQLStatsCompactionExecutor: "scheduled-sql-stats-compaction-executor",
+	InvalidExecutor:                       "unknown-executor",
+	ScheduledBackupExecutor:               "scheduled-backup-executor",
+	ScheduledSQLStatsCompactionExecutor:   "scheduled-sql-stats-compaction-executor",
+	ScheduledIntervalPartitioningExecutor: "scheduled-interval-partitioning-executor",
+	ScheduledRowLevelTTLExecutor:          "scheduled-row-level-ttl-executor",
 }
 
 // InternalName returns an internal executor name.
@@ -775,6 +785,10 @@ func (t ScheduledJobExecutorType) UserName() string {
 		return "BACKUP"
 	case ScheduledSQLStatsCompactionExecutor:
 		return "SQL STATISTICS"
+	case ScheduledIntervalPartitioningExecutor:
+		return "INTERVAL PARTITIONING"
+	case ScheduledRowLevelTTLExecutor:
+		return "ROW LEVEL TTL"
 	}
 	return "unsupported-executor"
 }
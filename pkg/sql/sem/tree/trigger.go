@@ -0,0 +1,132 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+// TriggerActionTime indicates whether a trigger fires before or after the
+// rows of the triggering statement are modified.
+type TriggerActionTime uint8
+
+const (
+	// TriggerActionTimeBefore is used for triggers that fire before a row is
+	// written. Such triggers can modify the row being written.
+	TriggerActionTimeBefore TriggerActionTime = iota
+	// TriggerActionTimeAfter is used for triggers that fire after the rows of
+	// the triggering statement have been written.
+	TriggerActionTimeAfter
+)
+
+var triggerActionTimeName = [...]string{
+	TriggerActionTimeBefore: "BEFORE",
+	TriggerActionTimeAfter:  "AFTER",
+}
+
+func (t TriggerActionTime) String() string {
+	return triggerActionTimeName[t]
+}
+
+// TriggerEvent is a kind of row modification which causes a trigger to fire.
+type TriggerEvent uint8
+
+const (
+	// TriggerEventInsert fires the trigger for inserted rows.
+	TriggerEventInsert TriggerEvent = iota
+	// TriggerEventUpdate fires the trigger for updated rows.
+	TriggerEventUpdate
+	// TriggerEventDelete fires the trigger for deleted rows.
+	TriggerEventDelete
+)
+
+var triggerEventName = [...]string{
+	TriggerEventInsert: "INSERT",
+	TriggerEventUpdate: "UPDATE",
+	TriggerEventDelete: "DELETE",
+}
+
+func (e TriggerEvent) String() string {
+	return triggerEventName[e]
+}
+
+// CreateTrigger represents a CREATE TRIGGER statement.
+type CreateTrigger struct {
+	Name       Name
+	ActionTime TriggerActionTime
+	Events     []TriggerEvent
+	Table      *UnresolvedObjectName
+	// When is nil if the trigger has no WHEN condition.
+	When Expr
+	// Set contains the assignments to the new row made by a BEFORE trigger.
+	Set UpdateExprs
+	// Body is the statement executed by an AFTER trigger.
+	Body Statement
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateTrigger) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE TRIGGER ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteByte(' ')
+	ctx.WriteString(node.ActionTime.String())
+	for i, e := range node.Events {
+		if i > 0 {
+			ctx.WriteString(" OR")
+		}
+		ctx.WriteByte(' ')
+		ctx.WriteString(e.String())
+	}
+	ctx.WriteString(" ON ")
+	ctx.FormatNode(node.Table)
+	ctx.WriteString(" FOR EACH ROW")
+	if node.When != nil {
+		ctx.WriteString(" WHEN (")
+		ctx.FormatNode(node.When)
+		ctx.WriteByte(')')
+	}
+	if node.Body != nil {
+		ctx.WriteByte(' ')
+		ctx.FormatNode(node.Body)
+	} else {
+		ctx.WriteString(" SET ")
+		ctx.FormatNode(&node.Set)
+	}
+}
+
+// HasEvent returns true if the trigger fires for the given event.
+func (node *CreateTrigger) HasEvent(event TriggerEvent) bool {
+	for _, e := range node.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// DropTrigger represents a DROP TRIGGER statement.
+type DropTrigger struct {
+	Name         Name
+	Table        *UnresolvedObjectName
+	IfExists     bool
+	DropBehavior DropBehavior
+}
+
+// Format implements the NodeFormatter interface.
+func (node *DropTrigger) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP TRIGGER ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(&node.Name)
+	ctx.WriteString(" ON ")
+	ctx.FormatNode(node.Table)
+	if node.DropBehavior != DropDefault {
+		ctx.WriteByte(' ')
+		ctx.WriteString(node.DropBehavior.String())
+	}
+}
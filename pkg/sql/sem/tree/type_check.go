@@ -53,6 +53,10 @@ type SemaContext struct {
 	// name of a table given its ID.
 	TableNameResolver QualifiedNameResolver
 
+	// FunctionResolver is used to resolve the names of user-defined
+	// functions. If it is nil, only built-in functions can be resolved.
+	FunctionResolver FunctionResolver
+
 	// IntervalStyleEnabled determines whether IntervalStyle is enabled.
 	IntervalStyleEnabled bool
 	// DateStyleEnabled determines whether DateStyle is enabled.
@@ -149,6 +153,9 @@ const (
 	// RejectSubqueries rejects subqueries in scalar contexts.
 	RejectSubqueries
 
+	// RejectUserDefinedFunctions rejects any use of user-defined functions.
+	RejectUserDefinedFunctions
+
 	// RejectSpecial is used in common places like the LIMIT clause.
 	RejectSpecial = RejectAggregates | RejectGenerators | RejectWindowApplications
 )
@@ -809,6 +816,7 @@ var (
 	errInvalidDefaultUsage = pgerror.New(pgcode.Syntax, "DEFAULT can only appear in a VALUES list within INSERT or on the right side of a SET")
 	errInvalidMaxUsage     = pgerror.New(pgcode.Syntax, "MAXVALUE can only appear within a range partition expression")
 	errInvalidMinUsage     = pgerror.New(pgcode.Syntax, "MINVALUE can only appear within a range partition expression")
+	errGroupingSetsUsage   = pgerror.New(pgcode.Syntax, "ROLLUP, CUBE and GROUPING SETS can only appear within a GROUP BY clause")
 	errPrivateFunction     = pgerror.New(pgcode.ReservedName, "function reserved for internal use")
 )
 
@@ -863,6 +871,11 @@ func (sc *SemaContext) checkFunctionUsage(expr *FuncExpr, def *FunctionDefinitio
 		return nil
 	}
 
+	if def.IsUDF() && sc.Properties.required.rejectFlags&RejectUserDefinedFunctions != 0 {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"user-defined functions are not allowed in %s", sc.Properties.required.context)
+	}
+
 	if expr.IsWindowFunctionApplication() {
 		if sc.Properties.required.rejectFlags&RejectWindowApplications != 0 {
 			return NewInvalidFunctionUsageError(WindowClass, sc.Properties.required.context)
@@ -952,11 +965,7 @@ func CheckIsWindowOrAgg(def *FunctionDefinition) error {
 func (expr *FuncExpr) TypeCheck(
 	ctx context.Context, semaCtx *SemaContext, desired *types.T,
 ) (TypedExpr, error) {
-	var searchPath sessiondata.SearchPath
-	if semaCtx != nil {
-		searchPath = semaCtx.SearchPath
-	}
-	def, err := expr.Func.Resolve(searchPath)
+	def, err := expr.Func.ResolveInSemaContext(ctx, semaCtx)
 	if err != nil {
 		return nil, err
 	}
@@ -1424,6 +1433,13 @@ func (expr DefaultVal) TypeCheck(
 	return nil, errInvalidDefaultUsage
 }
 
+// TypeCheck implements the Expr interface.
+func (expr *GroupingSets) TypeCheck(
+	_ context.Context, _ *SemaContext, desired *types.T,
+) (TypedExpr, error) {
+	return nil, errGroupingSetsUsage
+}
+
 // TypeCheck implements the Expr interface.
 func (expr PartitionMinVal) TypeCheck(
 	_ context.Context, _ *SemaContext, desired *types.T,
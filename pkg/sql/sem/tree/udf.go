@@ -0,0 +1,307 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// CreateFunction represents a CREATE FUNCTION statement.
+type CreateFunction struct {
+	Name       *UnresolvedObjectName
+	Replace    bool
+	Params     FuncParams
+	ReturnType ResolvableTypeReference
+	Options    FunctionOptions
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateFunction) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE ")
+	if node.Replace {
+		ctx.WriteString("OR REPLACE ")
+	}
+	ctx.WriteString("FUNCTION ")
+	ctx.FormatNode(node.Name)
+	ctx.WriteByte('(')
+	ctx.FormatNode(&node.Params)
+	ctx.WriteString(") RETURNS ")
+	ctx.FormatTypeReference(node.ReturnType)
+	for _, option := range node.Options {
+		ctx.WriteByte(' ')
+		ctx.FormatNode(option)
+	}
+}
+
+// FuncParam represents a parameter in a function signature.
+type FuncParam struct {
+	// Name is empty if the parameter is unnamed.
+	Name Name
+	Type ResolvableTypeReference
+}
+
+// Format implements the NodeFormatter interface.
+func (node *FuncParam) Format(ctx *FmtCtx) {
+	if node.Name != "" {
+		ctx.FormatNode(&node.Name)
+		ctx.WriteByte(' ')
+	}
+	ctx.FormatTypeReference(node.Type)
+}
+
+// FuncParams represents the parameters in a function signature.
+type FuncParams []FuncParam
+
+// Format implements the NodeFormatter interface.
+func (node *FuncParams) Format(ctx *FmtCtx) {
+	for i := range *node {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(&(*node)[i])
+	}
+}
+
+// FunctionOption is an option in a CREATE FUNCTION statement.
+type FunctionOption interface {
+	NodeFormatter
+	functionOption()
+}
+
+func (FunctionVolatility) functionOption()        {}
+func (FunctionLeakproof) functionOption()         {}
+func (FunctionNullInputBehavior) functionOption() {}
+func (FunctionLanguage) functionOption()          {}
+func (FunctionBodyStr) functionOption()           {}
+
+// FunctionOptions is a list of function options.
+type FunctionOptions []FunctionOption
+
+// FunctionVolatility is the volatility declared by a function definition. It
+// is one of VolatilityImmutable, VolatilityStable or VolatilityVolatile.
+type FunctionVolatility Volatility
+
+// Format implements the NodeFormatter interface.
+func (node FunctionVolatility) Format(ctx *FmtCtx) {
+	switch Volatility(node) {
+	case VolatilityImmutable:
+		ctx.WriteString("IMMUTABLE")
+	case VolatilityStable:
+		ctx.WriteString("STABLE")
+	default:
+		ctx.WriteString("VOLATILE")
+	}
+}
+
+// FunctionLeakproof indicates whether a function is declared LEAKPROOF.
+type FunctionLeakproof bool
+
+// Format implements the NodeFormatter interface.
+func (node FunctionLeakproof) Format(ctx *FmtCtx) {
+	if !node {
+		ctx.WriteString("NOT ")
+	}
+	ctx.WriteString("LEAKPROOF")
+}
+
+// FunctionNullInputBehavior indicates how a function behaves when it is
+// called with a NULL argument.
+type FunctionNullInputBehavior int
+
+const (
+	// FunctionCalledOnNullInput indicates that the function is evaluated
+	// normally when some of its arguments are NULL.
+	FunctionCalledOnNullInput FunctionNullInputBehavior = iota
+	// FunctionReturnsNullOnNullInput indicates that the function returns NULL
+	// whenever any of its arguments is NULL.
+	FunctionReturnsNullOnNullInput
+	// FunctionStrict is a synonym of FunctionReturnsNullOnNullInput.
+	FunctionStrict
+)
+
+// Format implements the NodeFormatter interface.
+func (node FunctionNullInputBehavior) Format(ctx *FmtCtx) {
+	switch node {
+	case FunctionCalledOnNullInput:
+		ctx.WriteString("CALLED ON NULL INPUT")
+	case FunctionReturnsNullOnNullInput:
+		ctx.WriteString("RETURNS NULL ON NULL INPUT")
+	case FunctionStrict:
+		ctx.WriteString("STRICT")
+	}
+}
+
+// FunctionLanguage is the language in which the body of a function is
+// written.
+type FunctionLanguage string
+
+// FunctionLangSQL is the only language supported for user-defined functions.
+const FunctionLangSQL FunctionLanguage = "sql"
+
+// Format implements the NodeFormatter interface.
+func (node FunctionLanguage) Format(ctx *FmtCtx) {
+	ctx.WriteString("LANGUAGE ")
+	ctx.WriteString(string(node))
+}
+
+// FunctionBodyStr is the body of a function, given as a string constant.
+type FunctionBodyStr string
+
+// Format implements the NodeFormatter interface.
+func (node FunctionBodyStr) Format(ctx *FmtCtx) {
+	ctx.WriteString("AS ")
+	if ctx.flags.HasFlags(FmtHideConstants) {
+		ctx.WriteString("'_'")
+		return
+	}
+	lexbase.EncodeSQLStringWithFlags(&ctx.Buffer, string(node), ctx.flags.EncodeFlags())
+}
+
+// FuncObj refers to a function in a DROP FUNCTION or GRANT statement. The
+// parameter types are optional, and used to check the signature of the
+// function if they are specified.
+type FuncObj struct {
+	FuncName *UnresolvedObjectName
+	// Params is nil if the parameter list was omitted.
+	Params FuncParams
+}
+
+// Format implements the NodeFormatter interface.
+func (node *FuncObj) Format(ctx *FmtCtx) {
+	ctx.FormatNode(node.FuncName)
+	if node.Params != nil {
+		ctx.WriteByte('(')
+		ctx.FormatNode(&node.Params)
+		ctx.WriteByte(')')
+	}
+}
+
+// FuncObjs is a list of FuncObj.
+type FuncObjs []FuncObj
+
+// Format implements the NodeFormatter interface.
+func (node *FuncObjs) Format(ctx *FmtCtx) {
+	for i := range *node {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(&(*node)[i])
+	}
+}
+
+// DropFunction represents a DROP FUNCTION statement.
+type DropFunction struct {
+	Functions    FuncObjs
+	IfExists     bool
+	DropBehavior DropBehavior
+}
+
+// Format implements the NodeFormatter interface.
+func (node *DropFunction) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP FUNCTION ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(&node.Functions)
+	if node.DropBehavior != DropDefault {
+		ctx.WriteByte(' ')
+		ctx.WriteString(node.DropBehavior.String())
+	}
+}
+
+// FunctionResolver resolves the names of user-defined functions.
+type FunctionResolver interface {
+	// ResolveFunction returns the definition of the user-defined function with
+	// the given name, or nil if there is no such function.
+	ResolveFunction(ctx context.Context, name *UnresolvedName) (*FunctionDefinition, error)
+}
+
+// UDFInfo holds the definition of a user-defined function. It is set on the
+// single overload of the FunctionDefinition of a user-defined function.
+type UDFInfo struct {
+	// ID is the ID of the function descriptor.
+	ID uint32
+	// ParamNames contains the names of the parameters of the function, in
+	// order. The name of an unnamed parameter is empty.
+	ParamNames []string
+	// Body is the SQL text of the statement executed by the function.
+	Body string
+	// ReturnsNullOnNullInput is true if the function is declared STRICT or
+	// RETURNS NULL ON NULL INPUT.
+	ReturnsNullOnNullInput bool
+}
+
+// NewUDFDefinition returns the FunctionDefinition of a user-defined function
+// with the given name, parameter types, return type and declared volatility.
+func NewUDFDefinition(
+	name string, paramTypes ArgTypes, returnType *types.T, volatility Volatility, info *UDFInfo,
+) *FunctionDefinition {
+	overload := &Overload{
+		Types:      paramTypes,
+		ReturnType: FixedReturnType(returnType),
+		Volatility: volatility,
+		UDF:        info,
+	}
+	return &FunctionDefinition{
+		Name:       name,
+		Definition: []overloadImpl{overload},
+		FunctionProperties: FunctionProperties{
+			Class:        NormalClass,
+			NullableArgs: !info.ReturnsNullOnNullInput,
+			Undocumented: true,
+		},
+	}
+}
+
+// IsUDF returns true if fd is the definition of a user-defined function.
+func (fd *FunctionDefinition) IsUDF() bool {
+	if len(fd.Definition) != 1 {
+		return false
+	}
+	o, ok := fd.Definition[0].(*Overload)
+	return ok && o.UDF != nil
+}
+
+// ResolveInSemaContext is like Resolve, but also resolves the names of
+// user-defined functions using the FunctionResolver of the given SemaContext,
+// if any. Unlike built-in functions, the definition of a user-defined function
+// is not stored in the reference, so that a later resolution of the same
+// expression observes any redefinition of the function.
+func (fn *ResolvableFunctionReference) ResolveInSemaContext(
+	ctx context.Context, semaCtx *SemaContext,
+) (*FunctionDefinition, error) {
+	var searchPath sessiondata.SearchPath
+	if semaCtx != nil {
+		searchPath = semaCtx.SearchPath
+	}
+	def, err := fn.Resolve(searchPath)
+	if err == nil || semaCtx == nil || semaCtx.FunctionResolver == nil {
+		return def, err
+	}
+	un, ok := fn.FunctionReference.(*UnresolvedName)
+	if !ok || pgerror.GetPGCode(err) != pgcode.UndefinedFunction {
+		return nil, err
+	}
+	udf, udfErr := semaCtx.FunctionResolver.ResolveFunction(ctx, un)
+	if udfErr != nil {
+		return nil, udfErr
+	}
+	if udf == nil {
+		return nil, err
+	}
+	return udf, nil
+}
@@ -698,6 +698,16 @@ func (expr *ColumnItem) Walk(_ Visitor) Expr {
 // Walk implements the Expr interface.
 func (expr DefaultVal) Walk(_ Visitor) Expr { return expr }
 
+// Walk implements the Expr interface.
+func (expr *GroupingSets) Walk(v Visitor) Expr {
+	if exprs, changed := walkExprSlice(v, expr.Exprs); changed {
+		exprCopy := *expr
+		exprCopy.Exprs = exprs
+		return &exprCopy
+	}
+	return expr
+}
+
 // Walk implements the Expr interface.
 func (expr PartitionMaxVal) Walk(_ Visitor) Expr { return expr }
 
@@ -900,6 +910,22 @@ func (stmt *Backup) walkStmt(v Visitor) Statement {
 	return ret
 }
 
+// copyNode makes a copy of this Statement without recursing in any child Statements.
+func (stmt *CopyTo) copyNode() *CopyTo {
+	stmtCopy := *stmt
+	return &stmtCopy
+}
+
+// walkStmt is part of the walkableStmt interface.
+func (stmt *CopyTo) walkStmt(v Visitor) Statement {
+	s, changed := walkStmt(v, stmt.Statement)
+	if changed {
+		stmt = stmt.copyNode()
+		stmt.Statement = s
+	}
+	return stmt
+}
+
 // copyNode makes a copy of this Statement without recursing in any child Statements.
 func (stmt *Delete) copyNode() *Delete {
 	stmtCopy := *stmt
@@ -1565,6 +1591,7 @@ func (stmt *BeginTransaction) walkStmt(v Visitor) Statement {
 
 var _ walkableStmt = &CreateTable{}
 var _ walkableStmt = &Backup{}
+var _ walkableStmt = &CopyTo{}
 var _ walkableStmt = &Delete{}
 var _ walkableStmt = &Explain{}
 var _ walkableStmt = &Insert{}
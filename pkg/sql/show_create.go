@@ -72,6 +72,10 @@ func ShowCreateTable(
 	lCtx simpleSchemaResolver,
 	displayOptions ShowCreateDisplayOptions,
 ) (string, error) {
+	if desc.IsForeignTable() {
+		return showCreateForeignTable(ctx, p, tn, desc)
+	}
+
 	a := &rowenc.DatumAlloc{}
 
 	f := p.ExtendedEvalContext().FmtCtx(tree.FmtSimple)
@@ -206,11 +210,18 @@ func ShowCreateTable(
 	); err != nil {
 		return "", err
 	}
+	showCreateStorageParams(desc, f)
 
 	if err := showCreateLocality(desc, f); err != nil {
 		return "", err
 	}
 
+	if err := showCreatePolicies(
+		ctx, tn, desc, &p.RunParams(ctx).p.semaCtx, p.RunParams(ctx).p.SessionData(), &f.Buffer,
+	); err != nil {
+		return "", err
+	}
+
 	if !displayOptions.IgnoreComments {
 		if err := showComments(tn, desc, selectComment(ctx, p, desc.GetID()), &f.Buffer); err != nil {
 			return "", err
@@ -220,6 +231,41 @@ func ShowCreateTable(
 	return f.CloseAndGetString(), nil
 }
 
+// showCreateForeignTable returns a valid SQL representation of the CREATE
+// FOREIGN TABLE statement used to create the given foreign table. Foreign
+// tables have no constraints or indexes, so only their visible columns and
+// their SERVER and OPTIONS clauses are shown.
+func showCreateForeignTable(
+	ctx context.Context, p PlanHookState, tn *tree.TableName, desc catalog.TableDescriptor,
+) (string, error) {
+	f := p.ExtendedEvalContext().FmtCtx(tree.FmtSimple)
+	f.WriteString("CREATE FOREIGN TABLE ")
+	f.FormatNode(tn)
+	f.WriteString(" (")
+	for i, col := range desc.VisibleColumns() {
+		if i != 0 {
+			f.WriteString(",")
+		}
+		f.WriteString("\n\t")
+		colstr, err := schemaexpr.FormatColumnForDisplay(
+			ctx, desc, col, &p.RunParams(ctx).p.semaCtx, p.RunParams(ctx).p.SessionData(),
+		)
+		if err != nil {
+			return "", err
+		}
+		f.WriteString(colstr)
+	}
+	f.WriteString("\n) ")
+	def := tree.ForeignTableDef{Server: tree.Name(desc.GetForeign().Server)}
+	for _, opt := range desc.GetForeign().Options {
+		def.Options = append(def.Options, tree.KVOption{
+			Key: tree.Name(opt.Key), Value: tree.NewStrVal(opt.Value),
+		})
+	}
+	f.FormatNode(&def)
+	return f.CloseAndGetString(), nil
+}
+
 // formatQuoteNames quotes and adds commas between names.
 func formatQuoteNames(buf *bytes.Buffer, names ...string) {
 	f := tree.NewFmtCtx(tree.FmtSimple)
@@ -211,6 +211,69 @@ func formatViewQueryTypesForDisplay(
 	return newStmt.String(), nil
 }
 
+// showCreatePolicies appends the statements enabling the row-level security
+// of a table and creating its policies to buf.
+func showCreatePolicies(
+	ctx context.Context,
+	tn *tree.TableName,
+	desc catalog.TableDescriptor,
+	semaCtx *tree.SemaContext,
+	sessionData *sessiondata.SessionData,
+	buf *bytes.Buffer,
+) error {
+	f := tree.NewFmtCtx(tree.FmtSimple)
+	un := tn.ToUnresolvedObjectName()
+	for _, mode := range []struct {
+		enabled bool
+		mode    tree.RowLevelSecurityMode
+	}{
+		{desc.GetRowLevelSecurity(), tree.RowLevelSecurityEnable},
+		{desc.GetForceRowLevelSecurity(), tree.RowLevelSecurityForce},
+	} {
+		if mode.enabled {
+			f.WriteString(";\n")
+			f.FormatNode(&tree.AlterTable{
+				Table: un,
+				Cmds:  tree.AlterTableCmds{&tree.AlterTableRowLevelSecurity{Mode: mode.mode}},
+			})
+		}
+	}
+
+	formatExpr := func(expr string) (tree.Expr, error) {
+		if expr == "" {
+			return nil, nil
+		}
+		s, err := schemaexpr.FormatExprForDisplay(ctx, desc, expr, semaCtx, sessionData, tree.FmtParsable)
+		if err != nil {
+			return nil, err
+		}
+		return parser.ParseExpr(s)
+	}
+	for i := range desc.GetPolicies() {
+		policy := &desc.GetPolicies()[i]
+		n := tree.CreatePolicy{
+			Name:        tree.Name(policy.Name),
+			Table:       un,
+			Restrictive: policy.Restrictive,
+			Command:     descpb.PolicyDescriptorCommand[policy.Command],
+		}
+		for _, role := range policy.Roles {
+			n.Roles = append(n.Roles, tree.Name(role))
+		}
+		var err error
+		if n.Using, err = formatExpr(policy.UsingExpr); err != nil {
+			return err
+		}
+		if n.WithCheck, err = formatExpr(policy.WithCheckExpr); err != nil {
+			return err
+		}
+		f.WriteString(";\n")
+		f.FormatNode(&n)
+	}
+	buf.WriteString(f.CloseAndGetString())
+	return nil
+}
+
 // showComments prints out the COMMENT statements sufficient to populate a
 // table's comments, including its index and column comments.
 func showComments(
@@ -325,6 +388,7 @@ func showForeignKeyConstraint(
 		buf.WriteString(" ON UPDATE ")
 		buf.WriteString(fk.OnUpdate.String())
 	}
+	buf.WriteString(tree.AsString(descpb.ConstraintDeferrabilityType[fk.Deferrability]))
 	if fk.Validity != descpb.ConstraintValidity_Validated {
 		buf.WriteString(" NOT VALID")
 	}
@@ -379,6 +443,39 @@ func showFamilyClause(desc catalog.TableDescriptor, f *tree.FmtCtx) {
 	}
 }
 
+// showCreateStorageParams creates the WITH clause listing the storage
+// parameters of the table for a CREATE statement, writing it to tree.FmtCtx f.
+func showCreateStorageParams(desc catalog.TableDescriptor, f *tree.FmtCtx) {
+	ttl := desc.GetRowLevelTTL()
+	if ttl == nil {
+		return
+	}
+	params := tree.StorageParams{
+		{Key: "ttl_expire_after", Value: tree.NewStrVal(ttl.ExpireAfter)},
+	}
+	if ttl.DeletionCron != "" {
+		params = append(params, tree.StorageParam{Key: "ttl_job_cron", Value: tree.NewStrVal(ttl.DeletionCron)})
+	}
+	for _, p := range []struct {
+		key tree.Name
+		val int64
+	}{
+		{"ttl_select_batch_size", ttl.SelectBatchSize},
+		{"ttl_delete_batch_size", ttl.DeleteBatchSize},
+		{"ttl_delete_rate_limit", ttl.DeleteRateLimit},
+	} {
+		if p.val != 0 {
+			params = append(params, tree.StorageParam{Key: p.key, Value: tree.NewDInt(tree.DInt(p.val))})
+		}
+	}
+	if ttl.Pause {
+		params = append(params, tree.StorageParam{Key: "ttl_pause", Value: tree.DBoolTrue})
+	}
+	f.WriteString(" WITH (")
+	f.FormatNode(&params)
+	f.WriteString(")")
+}
+
 // showCreateLocality creates the LOCALITY clauses for a CREATE statement, writing them
 // to tree.FmtCtx f.
 func showCreateLocality(desc catalog.TableDescriptor, f *tree.FmtCtx) error {
@@ -490,8 +587,13 @@ func ShowCreatePartitioning(
 		}
 		buf.WriteString(idx.GetKeyColumnName(colOffset + i))
 	}
-	buf.WriteString(`) (`)
+	buf.WriteString(`)`)
 	fmtCtx := tree.NewFmtCtx(tree.FmtSimple)
+	if interval := part.PartitioningDesc().Interval; interval != nil && colOffset == 0 {
+		fmtCtx.FormatNode(intervalPartitioningToAST(interval))
+		_, _ = fmtCtx.Buffer.WriteTo(buf)
+	}
+	buf.WriteString(` (`)
 	isFirst := true
 	err := part.ForEachList(func(name string, values [][]byte, subPartitioning catalog.Partitioning) error {
 		if !isFirst {
@@ -599,6 +701,7 @@ func showConstraintClause(
 		}
 		f.WriteString(strings.Join(colNames, ", "))
 		f.WriteString(")")
+		f.FormatNode(descpb.ConstraintDeferrabilityType[c.Deferrability])
 		if c.IsPartial() {
 			f.WriteString(" WHERE ")
 			pred, err := schemaexpr.FormatExprForDisplay(ctx, desc, c.Predicate, semaCtx, sessionData, tree.FmtParsable)
@@ -0,0 +1,410 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// DeclareCursor implements the DECLARE statement.
+// See https://www.postgresql.org/docs/current/sql-declare.html for details.
+//
+// The cursor's query is handed to the internal executor, bound to the
+// current transaction, which plans it and runs it as a regular flow. Rows are
+// pulled from that flow one at a time by subsequent FETCH and MOVE
+// statements, so a cursor never buffers its result set.
+func (p *planner) DeclareCursor(ctx context.Context, s *tree.DeclareCursor) (planNode, error) {
+	if s.Hold {
+		return nil, unimplemented.NewWithIssue(41412, "DECLARE CURSOR WITH HOLD")
+	}
+	if s.Binary {
+		return nil, unimplemented.NewWithIssue(41412, "DECLARE BINARY CURSOR")
+	}
+	if s.Scroll == tree.Scroll {
+		return nil, unimplemented.NewWithIssue(41412, "DECLARE SCROLL CURSOR")
+	}
+
+	return &delayedNode{
+		name: s.String(),
+		constructor: func(ctx context.Context, p *planner) (planNode, error) {
+			if p.extendedEvalCtx.TxnImplicit {
+				return nil, pgerror.Newf(pgcode.NoActiveSQLTransaction,
+					"DECLARE CURSOR can only be used in transaction blocks")
+			}
+			// The cursor's query reads through the transaction as rows are
+			// fetched, but under READ COMMITTED every statement moves the
+			// transaction's read timestamp, and a FETCH retried after a
+			// statement-level retry can't replay the rows it already consumed.
+			if p.txn.IsoLevel() == enginepb.READ_COMMITTED {
+				return nil, unimplemented.NewWithIssue(41412,
+					"DECLARE CURSOR in a READ COMMITTED transaction")
+			}
+			name := string(s.Name)
+			if _, ok := p.sqlCursors.list()[name]; ok {
+				return nil, pgerror.Newf(pgcode.DuplicateCursor, "cursor %q already exists", name)
+			}
+
+			// The cursor outlives the DECLARE statement, so its query runs
+			// under the transaction's context rather than the statement's.
+			ie := p.ExtendedEvalContext().InternalExecutor.(*InternalExecutor)
+			rows, err := ie.QueryIteratorEx(
+				p.sqlCursors.txnCtx(), "sql-cursor", p.txn,
+				sessiondata.InternalExecutorOverride{}, s.Select.String(),
+			)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to DECLARE CURSOR")
+			}
+			cursor := &sqlCursor{
+				InternalRows: rows,
+				txn:          p.txn,
+				readSeqNum:   p.txn.GetLeafTxnInputState(ctx).ReadSeqNum,
+				statement:    s.String(),
+				created:      timeutil.Now(),
+			}
+			if err := p.sqlCursors.addCursor(name, cursor); err != nil {
+				// This case shouldn't happen because cursor names are scoped to a
+				// session, and sessions can't have more than one query running at
+				// once. But let's be diligent and clean up if it somehow does
+				// happen anyway.
+				_ = cursor.Close()
+				return nil, err
+			}
+			return newZeroNode(nil /* columns */), nil
+		},
+	}, nil
+}
+
+var errBackwardScan = pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+	"cursor can only scan forward")
+
+// FetchCursor implements the FETCH and MOVE statements.
+// See https://www.postgresql.org/docs/current/sql-fetch.html for details.
+func (p *planner) FetchCursor(
+	_ context.Context, s *tree.CursorStmt, isMove bool,
+) (planNode, error) {
+	cursor, err := p.sqlCursors.getCursor(string(s.Name))
+	if err != nil {
+		return nil, err
+	}
+	if s.Count < 0 || s.FetchType == tree.FetchBackwardAll {
+		return nil, errBackwardScan
+	}
+	node := &fetchNode{
+		n:         s.Count,
+		fetchType: s.FetchType,
+		cursor:    cursor,
+		isMove:    isMove,
+	}
+	if s.FetchType != tree.FetchNormal {
+		node.n = 0
+		node.offset = s.Count
+	}
+	return node, nil
+}
+
+// CloseCursor implements the CLOSE statement.
+// See https://www.postgresql.org/docs/current/sql-close.html for details.
+func (p *planner) CloseCursor(ctx context.Context, n *tree.CloseCursor) (planNode, error) {
+	return &delayedNode{
+		name: n.String(),
+		constructor: func(ctx context.Context, p *planner) (planNode, error) {
+			if n.All {
+				p.sqlCursors.closeAll()
+				return newZeroNode(nil /* columns */), nil
+			}
+			return newZeroNode(nil /* columns */), p.sqlCursors.closeCursor(string(n.Name))
+		},
+	}, nil
+}
+
+// fetchNode produces the rows of a FETCH statement, or skips over them for a
+// MOVE statement.
+type fetchNode struct {
+	cursor *sqlCursor
+	// n is the number of rows requested.
+	n int64
+	// offset is the number of rows to read first, when in relative or absolute
+	// mode.
+	offset    int64
+	fetchType tree.FetchType
+	// isMove is true if this node is executing a MOVE statement, in which case
+	// no rows are returned to the client.
+	isMove bool
+
+	seeked bool
+
+	// origTxnSeqNum is the transaction's read sequence number before the fetch
+	// began. It's restored once the fetch is done, if seqNumPinned is set.
+	origTxnSeqNum enginepb.TxnSeq
+	seqNumPinned  bool
+}
+
+func (f *fetchNode) startExec(params runParams) error {
+	// We need to make sure that we're reading at the same read sequence number
+	// that we had when we created the cursor, to preserve the "sensitivity"
+	// semantics of cursors, which demand that data written after the cursor
+	// was declared is not visible to the cursor.
+	f.origTxnSeqNum = f.cursor.txn.GetLeafTxnInputState(params.ctx).ReadSeqNum
+	if err := f.cursor.txn.SetReadSeqNum(f.cursor.readSeqNum); err != nil {
+		return err
+	}
+	f.seqNumPinned = true
+	return nil
+}
+
+func (f *fetchNode) Next(params runParams) (bool, error) {
+	if f.fetchType == tree.FetchAll {
+		return f.cursor.Next(params.ctx)
+	}
+
+	if !f.seeked {
+		// FIRST, LAST, ABSOLUTE, and RELATIVE require seeking before returning
+		// values. Do that first.
+		f.seeked = true
+		switch f.fetchType {
+		case tree.FetchFirst:
+			switch f.cursor.curRow {
+			case 0:
+				return f.cursor.Next(params.ctx)
+			case 1:
+				return true, nil
+			}
+			return false, errBackwardScan
+		case tree.FetchLast:
+			return false, errBackwardScan
+		case tree.FetchAbsolute:
+			if f.cursor.curRow > f.offset {
+				return false, errBackwardScan
+			}
+			for f.cursor.curRow < f.offset {
+				more, err := f.cursor.Next(params.ctx)
+				if !more || err != nil {
+					return more, err
+				}
+			}
+			return f.offset > 0, nil
+		case tree.FetchRelative:
+			if f.offset == 0 {
+				return f.cursor.curRow > 0 && !f.cursor.exhausted, nil
+			}
+			for i := int64(0); i < f.offset; i++ {
+				more, err := f.cursor.Next(params.ctx)
+				if !more || err != nil {
+					return more, err
+				}
+			}
+			return true, nil
+		}
+	}
+	if f.n <= 0 {
+		return false, nil
+	}
+	f.n--
+	return f.cursor.Next(params.ctx)
+}
+
+func (f *fetchNode) Values() tree.Datums {
+	if f.isMove {
+		return nil
+	}
+	return f.cursor.Cur()
+}
+
+func (f *fetchNode) Close(ctx context.Context) {
+	// We explicitly do not pass through the Close to our InternalRows, because
+	// running FETCH on a CURSOR does not close it.
+
+	// Reset the transaction's read sequence number to what it was before the
+	// fetch began, so that subsequent reads in the transaction can still see
+	// writes from that transaction.
+	if !f.seqNumPinned {
+		return
+	}
+	f.seqNumPinned = false
+	if err := f.cursor.txn.SetReadSeqNum(f.origTxnSeqNum); err != nil {
+		log.Warningf(ctx, "error resetting transaction read seq num after CURSOR operation: %v", err)
+	}
+}
+
+func (f *fetchNode) columns() colinfo.ResultColumns {
+	if f.isMove {
+		return nil
+	}
+	return f.cursor.Types()
+}
+
+// sqlCursor is a named cursor declared within a transaction.
+type sqlCursor struct {
+	sqlutil.InternalRows
+	// txn is the transaction object that the internal executor for this cursor
+	// is running with.
+	txn *kv.Txn
+	// readSeqNum is the sequence number of the transaction that the cursor was
+	// initialized with.
+	readSeqNum enginepb.TxnSeq
+	statement  string
+	created    time.Time
+	// curRow is the 1-based ordinal of the row the cursor is positioned on.
+	curRow int64
+	// exhausted is set once the cursor has been moved past its last row.
+	exhausted bool
+}
+
+// Next implements the InternalRows interface.
+func (s *sqlCursor) Next(ctx context.Context) (bool, error) {
+	more, err := s.InternalRows.Next(ctx)
+	if err == nil {
+		if more {
+			s.curRow++
+		} else {
+			s.exhausted = true
+		}
+	}
+	return more, err
+}
+
+// sqlCursors contains a set of active cursors for a session.
+type sqlCursors interface {
+	// closeAll closes all cursors in the set.
+	closeAll()
+	// closeCursor closes the named cursor, returning an error if that cursor
+	// didn't exist in the set.
+	closeCursor(string) error
+	// getCursor returns the named cursor, returning an error if that cursor
+	// didn't exist in the set.
+	getCursor(string) (*sqlCursor, error)
+	// addCursor adds a new cursor with the given name to the set, returning an
+	// error if the cursor already existed in the set.
+	addCursor(string, *sqlCursor) error
+	// list returns all open cursors in the set.
+	list() map[string]*sqlCursor
+	// txnCtx returns the context of the transaction the cursors belong to.
+	txnCtx() context.Context
+}
+
+// cursorMap is a sqlCursors that's backed by an actual map.
+type cursorMap struct {
+	cursors map[string]*sqlCursor
+}
+
+func (c *cursorMap) closeAll() {
+	for _, cursor := range c.cursors {
+		_ = cursor.Close()
+	}
+	c.cursors = nil
+}
+
+func (c *cursorMap) closeCursor(s string) error {
+	cursor, ok := c.cursors[s]
+	if !ok {
+		return pgerror.Newf(pgcode.InvalidCursorName, "cursor %q does not exist", s)
+	}
+	err := cursor.Close()
+	delete(c.cursors, s)
+	return err
+}
+
+func (c *cursorMap) getCursor(s string) (*sqlCursor, error) {
+	cursor, ok := c.cursors[s]
+	if !ok {
+		return nil, pgerror.Newf(pgcode.InvalidCursorName, "cursor %q does not exist", s)
+	}
+	return cursor, nil
+}
+
+func (c *cursorMap) addCursor(s string, cursor *sqlCursor) error {
+	if c.cursors == nil {
+		c.cursors = make(map[string]*sqlCursor)
+	}
+	if _, ok := c.cursors[s]; ok {
+		return pgerror.Newf(pgcode.DuplicateCursor, "cursor %q already exists", s)
+	}
+	c.cursors[s] = cursor
+	return nil
+}
+
+func (c *cursorMap) list() map[string]*sqlCursor {
+	return c.cursors
+}
+
+// connExCursorAccessor is a sqlCursors that delegates to a connExecutor's
+// extraTxnState.
+type connExCursorAccessor struct {
+	ex *connExecutor
+}
+
+var _ sqlCursors = connExCursorAccessor{}
+
+func (c connExCursorAccessor) closeAll() {
+	c.ex.extraTxnState.sqlCursors.closeAll()
+}
+
+func (c connExCursorAccessor) closeCursor(s string) error {
+	return c.ex.extraTxnState.sqlCursors.closeCursor(s)
+}
+
+func (c connExCursorAccessor) getCursor(s string) (*sqlCursor, error) {
+	return c.ex.extraTxnState.sqlCursors.getCursor(s)
+}
+
+func (c connExCursorAccessor) addCursor(s string, cursor *sqlCursor) error {
+	return c.ex.extraTxnState.sqlCursors.addCursor(s, cursor)
+}
+
+func (c connExCursorAccessor) list() map[string]*sqlCursor {
+	return c.ex.extraTxnState.sqlCursors.list()
+}
+
+func (c connExCursorAccessor) txnCtx() context.Context {
+	return c.ex.state.Ctx
+}
+
+// emptySQLCursors is the default impl used by the planner when the
+// connExecutor is not available.
+type emptySQLCursors struct{}
+
+var _ sqlCursors = emptySQLCursors{}
+
+func (e emptySQLCursors) closeAll() {}
+
+func (e emptySQLCursors) closeCursor(s string) error {
+	return errors.AssertionFailedf("closeCursor not supported in emptySQLCursors")
+}
+
+func (e emptySQLCursors) getCursor(s string) (*sqlCursor, error) {
+	return nil, errors.AssertionFailedf("getCursor not supported in emptySQLCursors")
+}
+
+func (e emptySQLCursors) addCursor(s string, cursor *sqlCursor) error {
+	return errors.AssertionFailedf("addCursor not supported in emptySQLCursors")
+}
+
+func (e emptySQLCursors) list() map[string]*sqlCursor {
+	return nil
+}
+
+func (e emptySQLCursors) txnCtx() context.Context {
+	return context.Background()
+}
@@ -68,3 +68,11 @@ var CloseRequestCounter = telemetry.GetCounterOnce("pgwire.command.close")
 // FlushRequestCounter is to be incremented every time a flush request
 // is made.
 var FlushRequestCounter = telemetry.GetCounterOnce("pgwire.command.flush")
+
+// ReplicationConnectionCounter is to be incremented every time a client opens
+// a logical replication connection.
+var ReplicationConnectionCounter = telemetry.GetCounterOnce("pgwire.replication_connection")
+
+// StartReplicationCounter is to be incremented every time a replication
+// connection starts streaming the changes of a replication slot.
+var StartReplicationCounter = telemetry.GetCounterOnce("pgwire.command.start_replication")
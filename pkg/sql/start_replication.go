@@ -0,0 +1,785 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/rangefeed"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/fsm"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq/oid"
+)
+
+const (
+	// replicationKeepaliveInterval is the interval at which keepalive messages
+	// are sent to replication clients, which report the position up to which
+	// all the changes were sent.
+	replicationKeepaliveInterval = 10 * time.Second
+	// replicationSlotUpdateInterval is the minimum interval between the updates
+	// of the confirmed position of a replication slot, which require writing
+	// the descriptor of its database.
+	replicationSlotUpdateInterval = 10 * time.Second
+	// replicationStopTimeout is how long the client is given to send a message
+	// once streaming fails, before its connection is closed. The client must be
+	// done sending its messages before the error can be reported to it.
+	replicationStopTimeout = 10 * time.Second
+)
+
+// errReplicationConnDone marks the errors after which the connection of a
+// replication client can't be used anymore.
+var errReplicationConnDone = errors.New("replication connection done")
+
+// We handle the START_REPLICATION command by handing the network connection to
+// a replicationStream until the client ends streaming. The contract is the same
+// as the one of execCopyIn: the pgwire.conn is not reading from the network
+// connection any more until this returns.
+func (ex *connExecutor) execStartReplication(
+	ctx context.Context, cmd StartReplication,
+) (fsm.Event, fsm.EventPayload, error) {
+	ex.incrementStartedStmtCounter(cmd.Stmt)
+	// When we're done, unblock the network connection.
+	defer cmd.ReplicationDone.Done()
+
+	var err error
+	if _, isNoTxn := ex.machine.CurState().(stateNoTxn); !isNoTxn {
+		err = pgerror.New(pgcode.ActiveSQLTransaction,
+			"START_REPLICATION cannot be executed inside a transaction block")
+	} else {
+		telemetry.Inc(sqltelemetry.StartReplicationCounter)
+		var s *replicationStream
+		s, err = newReplicationStream(ctx, ex.server.cfg, ex.sessionData(), ex.memMetrics, cmd)
+		if err == nil {
+			err = s.run(ctx, ex.onCancelSession)
+		}
+	}
+	if err != nil {
+		if errors.Is(err, errReplicationConnDone) {
+			return nil, nil, err
+		}
+		ev := eventNonRetriableErr{IsCommit: fsm.False}
+		payload := eventNonRetriableErrPayload{err: err}
+		return ev, payload, nil
+	}
+	return nil, nil, nil
+}
+
+// replicationStream streams the changes of the tables of the publications
+// requested by a replication client, as pgoutput messages.
+//
+// The changes are streamed from one rangefeed per table. The changes committed
+// at the same wall time, which share an LSN, are buffered and sent as a single
+// transaction once the frontiers of all the rangefeeds are past it.
+type replicationStream struct {
+	execCfg *ExecutorConfig
+	conn    pgwirebase.Conn
+	dbID    descpb.ID
+	slot    string
+	tables  map[descpb.ID]*replicatedTable
+	// start is the position from which the changes are streamed.
+	start pgrepl.LSN
+	// sent is the position up to which all the changes have been sent.
+	sent pgrepl.LSN
+	// confirmed is the position up to which the client confirmed receiving the
+	// changes, and updated is when it was last written to the slot.
+	confirmed pgrepl.LSN
+	updated   time.Time
+
+	fetchers   map[relationVersion]*replicatedRelation
+	collection *descs.Collection
+	alloc      rowenc.DatumAlloc
+	kvFetcher  row.SpanKVFetcher
+	buf        []byte
+
+	mu struct {
+		syncutil.Mutex
+		// pending are the changes which haven't been sent yet, by the wall time
+		// of their commit timestamp.
+		pending map[int64][]replicatedChange
+		// sent is the same as replicationStream.sent, and is used to drop the
+		// changes which are delivered again by the rangefeeds.
+		sent pgrepl.LSN
+	}
+	// frontierAdvanced is signaled when the frontier of a rangefeed advances.
+	frontierAdvanced chan struct{}
+}
+
+// replicatedTable is a table whose changes are streamed.
+type replicatedTable struct {
+	id         descpb.ID
+	schemaName string
+	span       roachpb.Span
+	// frontier is the frontier of the rangefeed of the table. It is guarded by
+	// the mutex of the replicationStream.
+	frontier hlc.Timestamp
+	// sentVersion is the version of the table whose Relation message was sent
+	// last, if any.
+	sentVersion descpb.DescriptorVersion
+}
+
+// relationVersion identifies a version of a published table.
+type relationVersion struct {
+	id      descpb.ID
+	version descpb.DescriptorVersion
+}
+
+// replicatedRelation is the Relation of a version of a published table, along
+// with the fetcher decoding its rows.
+type replicatedRelation struct {
+	rel pgrepl.Relation
+	// colIdx are the indexes in the decoded rows of the Relation columns.
+	colIdx  []int
+	fetcher *row.Fetcher
+}
+
+// replicatedChange is a change of a row of a published table.
+type replicatedChange struct {
+	tableID descpb.ID
+	kv      roachpb.KeyValue
+	// existed is set if the row existed before the change.
+	existed bool
+}
+
+// newReplicationStream checks the START_REPLICATION command, and resolves the
+// tables of its publications in the current database.
+func newReplicationStream(
+	ctx context.Context,
+	execCfg *ExecutorConfig,
+	sd *sessiondata.SessionData,
+	memMetrics MemoryMetrics,
+	cmd StartReplication,
+) (*replicationStream, error) {
+	if sd.Database == "" {
+		return nil, errNoDatabase
+	}
+	start, err := pgrepl.ParseLSN(cmd.Stmt.StartLSN)
+	if err != nil {
+		return nil, err
+	}
+	opts, err := pgrepl.ParsePgoutputOptions(cmd.Stmt.Options)
+	if err != nil {
+		return nil, err
+	}
+	// Changes are streamed from rangefeeds, which require the
+	// kv.rangefeed.enabled setting to be true.
+	if !kvserver.RangefeedEnabled.Get(&execCfg.Settings.SV) {
+		return nil, pgerror.New(pgcode.ObjectNotInPrerequisiteState,
+			"logical replication requires the kv.rangefeed.enabled setting")
+	}
+
+	s := &replicationStream{
+		execCfg:          execCfg,
+		conn:             cmd.Conn,
+		slot:             string(cmd.Stmt.Slot),
+		tables:           make(map[descpb.ID]*replicatedTable),
+		fetchers:         make(map[relationVersion]*replicatedRelation),
+		collection:       execCfg.CollectionFactory.NewCollection(nil /* TemporarySchemaProvider */),
+		frontierAdvanced: make(chan struct{}, 1),
+	}
+	if err := DescsTxn(ctx, execCfg, func(
+		ctx context.Context, txn *kv.Txn, col *descs.Collection,
+	) error {
+		p, cleanup := newInternalPlanner("start-replication", txn, sd.User(), &memMetrics,
+			execCfg, sd.SessionData, WithDescCollection(col))
+		defer cleanup()
+		if err := p.RequireAdminRole(ctx, "START_REPLICATION"); err != nil {
+			return err
+		}
+
+		dbDesc, err := col.GetImmutableDatabaseByName(ctx, txn, sd.Database,
+			tree.DatabaseLookupFlags{Required: true})
+		if err != nil {
+			return err
+		}
+		s.dbID = dbDesc.GetID()
+		slot, ok := dbDesc.GetReplicationSlot(s.slot)
+		if !ok {
+			return pgerror.Newf(pgcode.UndefinedObject,
+				"replication slot %q does not exist", s.slot)
+		}
+		if slot.Plugin != pgrepl.PgoutputPlugin {
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"output plugin %q is not supported", slot.Plugin)
+		}
+		s.start = start
+		if confirmed := pgrepl.LSN(slot.ConfirmedFlushLSN); confirmed > s.start {
+			s.start = confirmed
+		}
+		s.confirmed = pgrepl.LSN(slot.ConfirmedFlushLSN)
+
+		var pubs []descpb.DatabaseDescriptor_Publication
+		for _, name := range opts.Publications {
+			pub, ok := dbDesc.GetPublication(name)
+			if !ok {
+				return pgerror.Newf(pgcode.UndefinedObject,
+					"publication %q does not exist", name)
+			}
+			pubs = append(pubs, pub)
+		}
+		schemas, err := col.GetSchemasForDatabase(ctx, txn, s.dbID)
+		if err != nil {
+			return err
+		}
+		tables, err := col.GetAllTableDescriptorsInDatabase(ctx, txn, s.dbID)
+		if err != nil {
+			return err
+		}
+		for _, table := range tables {
+			if !table.Public() || !isPublishableTable(table) {
+				continue
+			}
+			for i := range pubs {
+				if !publicationContainsTable(&pubs[i], table.GetID()) {
+					continue
+				}
+				if table.NumFamilies() > 1 {
+					return pgerror.Newf(pgcode.FeatureNotSupported,
+						"cannot replicate table %q: tables with multiple column families are not supported",
+						table.GetName())
+				}
+				if table.IsInterleaved() {
+					return pgerror.Newf(pgcode.FeatureNotSupported,
+						"cannot replicate table %q: interleaved tables are not supported",
+						table.GetName())
+				}
+				schemaName, ok := schemas[table.GetParentSchemaID()]
+				if !ok {
+					schemaName = tree.PublicSchema
+				}
+				s.tables[table.GetID()] = &replicatedTable{
+					id:         table.GetID(),
+					schemaName: schemaName,
+					span:       table.PrimaryIndexSpan(execCfg.Codec),
+				}
+				break
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	s.sent = s.start
+	s.mu.sent = s.start
+	s.mu.pending = make(map[int64][]replicatedChange)
+	return s, nil
+}
+
+// replicationClientMsg is a message received from the client, or the error
+// which ended the reading of its messages.
+type replicationClientMsg struct {
+	status *pgrepl.StandbyStatusUpdate
+	// done is set when the client ended streaming.
+	done bool
+	err  error
+}
+
+// run streams the changes until the client ends streaming or an error occurs.
+func (s *replicationStream) run(ctx context.Context, cancelSession context.CancelFunc) error {
+	defer s.collection.ReleaseAll(ctx)
+	if err := s.conn.BeginCopyBoth(ctx); err != nil {
+		return errors.Mark(err, errReplicationConnDone)
+	}
+
+	msgs := make(chan replicationClientMsg)
+	stopReading := make(chan struct{})
+	readerDone := make(chan struct{})
+	go func() {
+		defer close(readerDone)
+		s.readClientMessages(msgs, stopReading)
+	}()
+
+	done, err := s.stream(ctx, msgs)
+	if done {
+		if err := s.updateSlot(ctx); err != nil {
+			log.Warningf(ctx, "updating replication slot %q: %v", s.slot, err)
+		}
+		if err := s.conn.SendCopyDone(); err != nil {
+			return errors.Mark(err, errReplicationConnDone)
+		}
+		// Streaming commands complete with this tag in PostgreSQL.
+		return s.conn.SendCommandComplete([]byte("START_STREAMING"))
+	}
+	if errors.Is(err, errReplicationConnDone) {
+		// The client messages can't be read anymore.
+		return err
+	}
+
+	// The error can only be reported once the client stops sending messages, as
+	// they would otherwise be mistaken for commands. A keepalive requesting a
+	// reply is sent so that the client sends a last message, after which the
+	// messages it sends are ignored until the error is received.
+	close(stopReading)
+	if ctx.Err() == nil {
+		if sendErr := s.sendKeepalive(true /* replyRequested */); sendErr != nil {
+			log.VEventf(ctx, 2, "sending keepalive after error: %v", sendErr)
+		}
+	}
+	select {
+	case <-readerDone:
+	case <-time.After(replicationStopTimeout):
+		if cancelSession != nil {
+			cancelSession()
+		}
+		<-readerDone
+		return errors.Mark(err, errReplicationConnDone)
+	}
+	return err
+}
+
+// readClientMessages reads the messages of the client until it ends streaming,
+// stopReading is closed or an error occurs.
+func (s *replicationStream) readClientMessages(
+	msgs chan<- replicationClientMsg, stopReading <-chan struct{},
+) {
+	readBuf := pgwirebase.MakeReadBuffer(
+		pgwirebase.ReadBufferOptionWithClusterSettings(&s.execCfg.Settings.SV),
+	)
+	for {
+		var msg replicationClientMsg
+		typ, _, err := readBuf.ReadTypedMsg(s.conn.Rd())
+		switch {
+		case err != nil:
+			msg.err = errors.Mark(err, errReplicationConnDone)
+		case typ == pgwirebase.ClientMsgCopyData:
+			if len(readBuf.Msg) == 0 {
+				msg.err = pgerror.New(pgcode.ProtocolViolation, "unexpected empty CopyData message")
+				break
+			}
+			switch readBuf.Msg[0] {
+			case pgrepl.StandbyStatusUpdateMsg:
+				status, err := pgrepl.ParseStandbyStatusUpdate(readBuf.Msg)
+				if err != nil {
+					msg.err = err
+				} else {
+					msg.status = &status
+				}
+			case pgrepl.HotStandbyFeedbackMsg:
+				// Only physical replication clients send hot standby feedback.
+				continue
+			default:
+				msg.err = pgerror.Newf(pgcode.ProtocolViolation,
+					"unexpected message type %q in CopyData", readBuf.Msg[0])
+			}
+		case typ == pgwirebase.ClientMsgCopyDone:
+			msg.done = true
+		case typ == pgwirebase.ClientMsgCopyFail:
+			msg.err = pgerror.Newf(pgcode.QueryCanceled,
+				"replication stopped by client: %s", string(readBuf.Msg))
+		case typ == pgwirebase.ClientMsgFlush, typ == pgwirebase.ClientMsgSync:
+			// As in copy-in mode, Flush and Sync messages are ignored.
+			continue
+		case typ == pgwirebase.ClientMsgTerminate:
+			msg.err = errors.Mark(
+				pgerror.New(pgcode.AdminShutdown, "replication client terminated the connection"),
+				errReplicationConnDone)
+		default:
+			msg.err = pgwirebase.NewUnrecognizedMsgTypeErr(typ)
+		}
+
+		select {
+		case msgs <- msg:
+		case <-stopReading:
+			return
+		}
+		if msg.done || msg.err != nil {
+			return
+		}
+	}
+}
+
+// stream starts the rangefeeds of the tables and sends their changes to the
+// client. It returns true once the client ends streaming.
+func (s *replicationStream) stream(
+	ctx context.Context, msgs <-chan replicationClientMsg,
+) (done bool, _ error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	initialTS := s.start.Timestamp()
+	for _, table := range s.tables {
+		table := table
+		table.frontier = initialTS
+		feed, err := s.execCfg.RangeFeedFactory.RangeFeed(ctx,
+			"replication-"+s.slot,
+			table.span,
+			initialTS,
+			func(ctx context.Context, value *roachpb.RangeFeedValue) {
+				s.onValue(table.id, value)
+			},
+			rangefeed.WithDiff(),
+			rangefeed.WithOnFrontierAdvance(func(ctx context.Context, frontier hlc.Timestamp) {
+				s.onFrontierAdvance(table, frontier)
+			}),
+		)
+		if err != nil {
+			return false, err
+		}
+		defer feed.Close()
+	}
+
+	keepalive := time.NewTicker(replicationKeepaliveInterval)
+	defer keepalive.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return false, errors.Mark(ctx.Err(), errReplicationConnDone)
+		case msg := <-msgs:
+			if msg.err != nil {
+				return false, msg.err
+			}
+			if msg.done {
+				return true, nil
+			}
+			if err := s.onStatusUpdate(ctx, msg.status); err != nil {
+				return false, err
+			}
+		case <-s.frontierAdvanced:
+			if err := s.sendResolvedChanges(ctx); err != nil {
+				return false, err
+			}
+		case <-keepalive.C:
+			if err := s.sendKeepalive(false /* replyRequested */); err != nil {
+				return false, errors.Mark(err, errReplicationConnDone)
+			}
+		}
+	}
+}
+
+// onValue buffers a change received from the rangefeed of a table.
+func (s *replicationStream) onValue(tableID descpb.ID, value *roachpb.RangeFeedValue) {
+	wallTime := value.Value.Timestamp.WallTime
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if pgrepl.LSN(wallTime) <= s.mu.sent {
+		return
+	}
+	changes := s.mu.pending[wallTime]
+	for i := range changes {
+		// Rangefeeds deliver the values again when they restart.
+		if changes[i].kv.Key.Equal(value.Key) && changes[i].kv.Value.Timestamp == value.Value.Timestamp {
+			return
+		}
+	}
+	s.mu.pending[wallTime] = append(changes, replicatedChange{
+		tableID: tableID,
+		kv:      roachpb.KeyValue{Key: value.Key, Value: value.Value},
+		existed: value.PrevValue.IsPresent(),
+	})
+}
+
+// onFrontierAdvance records the frontier of the rangefeed of a table.
+func (s *replicationStream) onFrontierAdvance(table *replicatedTable, frontier hlc.Timestamp) {
+	s.mu.Lock()
+	table.frontier = frontier
+	s.mu.Unlock()
+	select {
+	case s.frontierAdvanced <- struct{}{}:
+	default:
+	}
+}
+
+// sendResolvedChanges sends the transactions whose changes have all been
+// received, i.e. the ones committed before the frontiers of all the tables.
+func (s *replicationStream) sendResolvedChanges(ctx context.Context) error {
+	s.mu.Lock()
+	var resolved hlc.Timestamp
+	for _, table := range s.tables {
+		if resolved.IsEmpty() || table.frontier.Less(resolved) {
+			resolved = table.frontier
+		}
+	}
+	if resolved.IsEmpty() {
+		s.mu.Unlock()
+		return nil
+	}
+	// The changes committed at the wall time of the frontier may not all have
+	// been received yet.
+	sent := pgrepl.LSN(resolved.WallTime - 1)
+	var wallTimes []int64
+	for wallTime := range s.mu.pending {
+		if pgrepl.LSN(wallTime) <= sent {
+			wallTimes = append(wallTimes, wallTime)
+		}
+	}
+	sort.Slice(wallTimes, func(i, j int) bool { return wallTimes[i] < wallTimes[j] })
+	txns := make([][]replicatedChange, len(wallTimes))
+	for i, wallTime := range wallTimes {
+		txns[i] = s.mu.pending[wallTime]
+		delete(s.mu.pending, wallTime)
+	}
+	if sent > s.mu.sent {
+		s.mu.sent = sent
+	}
+	s.mu.Unlock()
+
+	for i, wallTime := range wallTimes {
+		if err := s.sendTransaction(ctx, wallTime, txns[i]); err != nil {
+			return err
+		}
+	}
+	if sent > s.sent {
+		s.sent = sent
+	}
+	return nil
+}
+
+// sendTransaction sends the changes committed at the given wall time as a
+// transaction.
+func (s *replicationStream) sendTransaction(
+	ctx context.Context, wallTime int64, changes []replicatedChange,
+) error {
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].kv.Value.Timestamp.Less(changes[j].kv.Value.Timestamp)
+	})
+	lsn := pgrepl.LSN(wallTime)
+	commitTime := timeutil.Unix(0, wallTime)
+	if err := s.send(lsn, func(b []byte) []byte {
+		return pgrepl.AppendBegin(b, lsn, commitTime)
+	}); err != nil {
+		return err
+	}
+	for i := range changes {
+		if err := s.sendChange(ctx, lsn, &changes[i]); err != nil {
+			return err
+		}
+	}
+	return s.send(lsn, func(b []byte) []byte {
+		return pgrepl.AppendCommit(b, lsn, lsn, commitTime)
+	})
+}
+
+// sendChange sends a change, preceded by the Relation message of its table if
+// it wasn't sent since the table last changed.
+func (s *replicationStream) sendChange(
+	ctx context.Context, lsn pgrepl.LSN, change *replicatedChange,
+) error {
+	table := s.tables[change.tableID]
+	r, err := s.relationAt(ctx, table, change.kv.Value.Timestamp)
+	if err != nil {
+		return err
+	}
+	s.kvFetcher.KVs = append(s.kvFetcher.KVs[:0], change.kv)
+	if err := r.fetcher.StartScanFrom(ctx, &s.kvFetcher); err != nil {
+		return err
+	}
+	datums, desc, _, err := r.fetcher.NextRowDecoded(ctx)
+	if err != nil {
+		return err
+	}
+	if datums == nil {
+		return errors.AssertionFailedf("no row decoded from key %s", change.kv.Key)
+	}
+	deleted := r.fetcher.RowIsDeleted()
+	if deleted && !change.existed {
+		// Deleting a row which doesn't exist doesn't change anything.
+		return nil
+	}
+	rowDatums := make(tree.Datums, len(r.colIdx))
+	for i, idx := range r.colIdx {
+		rowDatums[i] = datums[idx]
+	}
+
+	if table.sentVersion != desc.GetVersion() {
+		if err := s.send(lsn, func(b []byte) []byte {
+			return pgrepl.AppendRelation(b, &r.rel)
+		}); err != nil {
+			return err
+		}
+		table.sentVersion = desc.GetVersion()
+	}
+	return s.send(lsn, func(b []byte) []byte {
+		switch {
+		case deleted:
+			return pgrepl.AppendDelete(b, &r.rel, rowDatums)
+		case change.existed:
+			return pgrepl.AppendUpdate(b, &r.rel, rowDatums)
+		default:
+			return pgrepl.AppendInsert(b, &r.rel, rowDatums)
+		}
+	})
+}
+
+// relationAt returns the replicatedRelation of the version of the table at the
+// given timestamp.
+func (s *replicationStream) relationAt(
+	ctx context.Context, table *replicatedTable, ts hlc.Timestamp,
+) (*replicatedRelation, error) {
+	// Retrieve the descriptor from the lease manager, which does its own
+	// caching, and release the lease immediately, since it is only needed at
+	// the given timestamp.
+	leased, err := s.execCfg.LeaseManager.Acquire(ctx, ts, table.id)
+	if err != nil {
+		return nil, err
+	}
+	desc := leased.Underlying().(catalog.TableDescriptor)
+	leased.Release(ctx)
+	if desc.ContainsUserDefinedTypes() {
+		// The types of the columns of the leased descriptor aren't hydrated, so
+		// the descriptor is read again from a descs.Collection, which needs a
+		// transaction to read it at the given timestamp.
+		if err := s.execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+			if err := txn.SetFixedTimestamp(ctx, ts); err != nil {
+				return err
+			}
+			desc, err = s.collection.GetImmutableTableByID(ctx, txn, table.id, tree.ObjectLookupFlags{})
+			return err
+		}); err != nil {
+			return nil, err
+		}
+		s.collection.ReleaseAll(ctx)
+	}
+
+	idVer := relationVersion{id: desc.GetID(), version: desc.GetVersion()}
+	if r, ok := s.fetchers[idVer]; ok && catalog.UserDefinedTypeColsHaveSameVersion(
+		desc, r.fetcher.GetTables()[0].(catalog.TableDescriptor),
+	) {
+		return r, nil
+	}
+
+	r := &replicatedRelation{
+		rel: pgrepl.Relation{
+			ID:        oid.Oid(desc.GetID()),
+			Namespace: table.schemaName,
+			Name:      desc.GetName(),
+		},
+		fetcher: &row.Fetcher{},
+	}
+	keyCols := desc.GetPrimaryIndex().CollectKeyColumnIDs()
+	var colIdxMap catalog.TableColMap
+	var valNeededForCol util.FastIntSet
+	for _, col := range desc.PublicColumns() {
+		colIdxMap.Set(col.GetID(), col.Ordinal())
+		valNeededForCol.Add(col.Ordinal())
+		// Virtual columns aren't stored, so they aren't replicated.
+		if col.IsVirtual() {
+			continue
+		}
+		r.colIdx = append(r.colIdx, col.Ordinal())
+		r.rel.Columns = append(r.rel.Columns, pgrepl.RelationColumn{
+			Name:         col.GetName(),
+			Key:          keyCols.Contains(col.GetID()),
+			TypeOID:      col.GetType().Oid(),
+			TypeModifier: col.GetType().TypeModifier(),
+		})
+	}
+	if err := r.fetcher.Init(
+		ctx,
+		s.execCfg.Codec,
+		false, /* reverse */
+		descpb.ScanLockingStrength_FOR_NONE,
+		descpb.ScanLockingWaitPolicy_BLOCK,
+		0,     /* lockTimeout */
+		false, /* isCheck */
+		&s.alloc,
+		nil, /* memMonitor */
+		row.FetcherTableArgs{
+			Spans:           desc.AllIndexSpans(s.execCfg.Codec),
+			Desc:            desc,
+			Index:           desc.GetPrimaryIndex(),
+			ColIdxMap:       colIdxMap,
+			Cols:            desc.PublicColumns(),
+			ValNeededForCol: valNeededForCol,
+		},
+	); err != nil {
+		return nil, err
+	}
+	// Necessary because virtual columns are not populated.
+	r.fetcher.IgnoreUnexpectedNulls = true
+	s.fetchers[idVer] = r
+	return r, nil
+}
+
+// send sends a pgoutput message, appended by the given function, in an
+// XLogData message.
+func (s *replicationStream) send(lsn pgrepl.LSN, appendMsg func([]byte) []byte) error {
+	s.buf = pgrepl.AppendXLogData(s.buf[:0], lsn, lsn, timeutil.Now())
+	s.buf = appendMsg(s.buf)
+	return errors.Mark(s.conn.SendCopyData(s.buf), errReplicationConnDone)
+}
+
+func (s *replicationStream) sendKeepalive(replyRequested bool) error {
+	s.buf = pgrepl.AppendKeepalive(s.buf[:0], s.sent, timeutil.Now(), replyRequested)
+	return s.conn.SendCopyData(s.buf)
+}
+
+// onStatusUpdate records the position confirmed by the client, which is
+// written to the replication slot periodically.
+func (s *replicationStream) onStatusUpdate(
+	ctx context.Context, status *pgrepl.StandbyStatusUpdate,
+) error {
+	if status.ReplyRequested {
+		if err := s.sendKeepalive(false /* replyRequested */); err != nil {
+			return errors.Mark(err, errReplicationConnDone)
+		}
+	}
+	// Clients can't confirm positions which weren't sent.
+	if flushed := status.FlushLSN; flushed > s.confirmed && flushed <= s.sent {
+		s.confirmed = flushed
+	}
+	if timeutil.Since(s.updated) < replicationSlotUpdateInterval {
+		return nil
+	}
+	return s.updateSlot(ctx)
+}
+
+// updateSlot writes the confirmed position to the replication slot, and
+// advances its protected timestamp record to it. It fails if the slot was
+// dropped.
+func (s *replicationStream) updateSlot(ctx context.Context) error {
+	s.updated = timeutil.Now()
+	return DescsTxn(ctx, s.execCfg, func(
+		ctx context.Context, txn *kv.Txn, col *descs.Collection,
+	) error {
+		desc, err := col.GetMutableDescriptorByID(ctx, s.dbID, txn)
+		if err != nil {
+			return err
+		}
+		dbDesc := desc.(*dbdesc.Mutable)
+		slot, ok := dbDesc.GetReplicationSlot(s.slot)
+		if !ok {
+			return pgerror.Newf(pgcode.UndefinedObject,
+				"replication slot %q was dropped", s.slot)
+		}
+		if pgrepl.LSN(slot.ConfirmedFlushLSN) >= s.confirmed {
+			return nil
+		}
+		slot.ConfirmedFlushLSN = uint64(s.confirmed)
+		if err := protectReplicationSlot(ctx, s.execCfg, txn, col, s.dbID, &slot); err != nil {
+			return err
+		}
+		dbDesc.SetReplicationSlot(slot)
+		return col.WriteDesc(ctx, false /* kvTrace */, dbDesc, txn)
+	})
+}
@@ -337,6 +337,9 @@ WHERE
 	AND tbl.drop_time IS NULL
 	AND (
 			crdb_internal.pb_to_json('cockroach.sql.sqlbase.Descriptor', d.descriptor, false)->'table'->>'viewQuery'
+		) IS NULL
+	AND (
+			crdb_internal.pb_to_json('cockroach.sql.sqlbase.Descriptor', d.descriptor, false)->'table'->'foreign'
 		) IS NULL;`,
 		initialTableCollectionDelay,
 		systemschema.SystemDatabaseName,
@@ -354,7 +357,7 @@ WHERE
 			row := it.Cur()
 			tableID := descpb.ID(*row[0].(*tree.DInt))
 			// Don't create statistics for virtual tables.
-			// The query already excludes views and system tables.
+			// The query already excludes views, foreign tables and system tables.
 			if !descpb.IsVirtualTable(tableID) {
 				r.mutationCounts[tableID] += 0
 			}
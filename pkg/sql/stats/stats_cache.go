@@ -240,6 +240,11 @@ func hasStatistics(table catalog.TableDescriptor) bool {
 		// Don't try to get statistics for views.
 		return false
 	}
+	if table.IsForeignTable() {
+		// Don't try to get statistics for foreign tables, whose rows are not
+		// stored in the cluster.
+		return false
+	}
 	return true
 }
 
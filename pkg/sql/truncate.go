@@ -93,6 +93,9 @@ func (t *truncateNode) startExec(params runParams) error {
 		if err := p.CheckPrivilege(ctx, tableDesc, privilege.DROP); err != nil {
 			return err
 		}
+		if err := checkNotForeignTable(tableDesc, "truncate"); err != nil {
+			return err
+		}
 
 		toTruncate[tableDesc.ID] = tn.FQString()
 		toTraverse = append(toTraverse, *tableDesc)
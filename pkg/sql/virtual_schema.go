@@ -398,6 +398,9 @@ func (v *virtualSchemaEntry) GetObjectByName(
 			desc:    typedesc.MakeSimpleAlias(typ, catconstants.PgCatalogID),
 			mutable: flags.RequireMutable,
 		}, nil
+	case tree.FunctionObject:
+		// Virtual schemas do not contain user-defined functions.
+		return nil, nil
 	default:
 		return nil, errors.AssertionFailedf("unknown desired object kind %d", flags.DesiredObjectKind)
 	}
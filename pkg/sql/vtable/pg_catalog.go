@@ -959,8 +959,7 @@ CREATE TABLE pg_catalog.pg_available_extension_versions (
 	version STRING
 )`
 
-// PgCatalogPublicationRel is an empty table created by pg_catalog_test
-// and is currently unimplemented.
+// PgCatalogPublicationRel describes the schema of the pg_catalog.pg_publication_rel table.
 const PgCatalogPublicationRel = `
 CREATE TABLE pg_catalog.pg_publication_rel (
 	oid OID,
@@ -1009,8 +1008,7 @@ CREATE TABLE pg_catalog.pg_timezone_names (
 	utc_offset INTERVAL
 )`
 
-// PgCatalogPublicationTables is an empty table created by pg_catalog_test
-// and is currently unimplemented.
+// PgCatalogPublicationTables describes the schema of the pg_catalog.pg_publication_tables table.
 const PgCatalogPublicationTables = `
 CREATE TABLE pg_catalog.pg_publication_tables (
 	pubname NAME,
@@ -1066,8 +1064,7 @@ CREATE TABLE pg_catalog.pg_shadow (
 	usebypassrls BOOL
 )`
 
-// PgCatalogPublication is an empty table created by pg_catalog_test
-// and is currently unimplemented.
+// PgCatalogPublication describes the schema of the pg_catalog.pg_publication table.
 const PgCatalogPublication = `
 CREATE TABLE pg_catalog.pg_publication (
 	pubupdate BOOL,
@@ -1090,8 +1087,8 @@ CREATE TABLE pg_catalog.pg_group (
 	grosysid OID
 )`
 
-// PgCatalogCursors is an empty table created by pg_catalog_test
-// and is currently unimplemented.
+// PgCatalogCursors describes the schema of the pg_catalog.pg_cursors table.
+// https://www.postgresql.org/docs/current/view-pg-cursors.html
 const PgCatalogCursors = `
 CREATE TABLE pg_catalog.pg_cursors (
 	is_scrollable BOOL,
@@ -1305,7 +1302,7 @@ CREATE TABLE pg_catalog.pg_replication_origin_status (
 	local_id OID
 )`
 
-//PgCatalogReplicationSlots is an empty table in the pg_catalog that is not implemented yet
+// PgCatalogReplicationSlots describes the schema of the pg_catalog.pg_replication_slots table.
 const PgCatalogReplicationSlots = `
 CREATE TABLE pg_catalog.pg_replication_slots (
 	safe_wal_size INT,
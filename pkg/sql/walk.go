@@ -357,11 +357,17 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&controlSchedulesNode{}):           "control schedules",
 	reflect.TypeOf(&createDatabaseNode{}):             "create database",
 	reflect.TypeOf(&createExtensionNode{}):            "create extension",
+	reflect.TypeOf(&createFunctionNode{}):             "create function",
 	reflect.TypeOf(&createIndexNode{}):                "create index",
+	reflect.TypeOf(&createPolicyNode{}):               "create policy",
+	reflect.TypeOf(&createPublicationNode{}):          "create publication",
+	reflect.TypeOf(&createReplicationSlotNode{}):      "create replication slot",
 	reflect.TypeOf(&createSequenceNode{}):             "create sequence",
 	reflect.TypeOf(&createSchemaNode{}):               "create schema",
+	reflect.TypeOf(&createServerNode{}):               "create server",
 	reflect.TypeOf(&createStatsNode{}):                "create statistics",
 	reflect.TypeOf(&createTableNode{}):                "create table",
+	reflect.TypeOf(&createTriggerNode{}):              "create trigger",
 	reflect.TypeOf(&createTypeNode{}):                 "create type",
 	reflect.TypeOf(&CreateRoleNode{}):                 "create user/role",
 	reflect.TypeOf(&createViewNode{}):                 "create view",
@@ -370,10 +376,16 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&deleteRangeNode{}):                "delete range",
 	reflect.TypeOf(&distinctNode{}):                   "distinct",
 	reflect.TypeOf(&dropDatabaseNode{}):               "drop database",
+	reflect.TypeOf(&dropFunctionNode{}):               "drop function",
 	reflect.TypeOf(&dropIndexNode{}):                  "drop index",
+	reflect.TypeOf(&dropPolicyNode{}):                 "drop policy",
+	reflect.TypeOf(&dropPublicationNode{}):            "drop publication",
+	reflect.TypeOf(&dropReplicationSlotNode{}):        "drop replication slot",
 	reflect.TypeOf(&dropSequenceNode{}):               "drop sequence",
 	reflect.TypeOf(&dropSchemaNode{}):                 "drop schema",
+	reflect.TypeOf(&dropServerNode{}):                 "drop server",
 	reflect.TypeOf(&dropTableNode{}):                  "drop table",
+	reflect.TypeOf(&dropTriggerNode{}):                "drop trigger",
 	reflect.TypeOf(&dropTypeNode{}):                   "drop type",
 	reflect.TypeOf(&DropRoleNode{}):                   "drop user/role",
 	reflect.TypeOf(&dropViewNode{}):                   "drop view",
@@ -382,7 +394,9 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&explainVecNode{}):                 "explain vectorized",
 	reflect.TypeOf(&explainDDLNode{}):                 "explain ddl",
 	reflect.TypeOf(&exportNode{}):                     "export",
+	reflect.TypeOf(&fetchNode{}):                      "fetch",
 	reflect.TypeOf(&filterNode{}):                     "filter",
+	reflect.TypeOf(&foreignScanNode{}):                "foreign scan",
 	reflect.TypeOf(&GrantRoleNode{}):                  "grant role",
 	reflect.TypeOf(&groupNode{}):                      "group",
 	reflect.TypeOf(&hookFnNode{}):                     "plugin",
@@ -82,6 +82,22 @@ func TxnSeqIsIgnored(seq TxnSeq, ignored []IgnoredSeqNumRange) bool {
 	return false
 }
 
+// ToleratesWriteSkew returns whether transactions with the isolation level
+// can commit at a timestamp above their read timestamp without refreshing
+// their reads.
+func (l IsolationLevel) ToleratesWriteSkew() bool {
+	return l == READ_COMMITTED
+}
+
+// PerStatementReadSnapshot returns whether transactions with the isolation
+// level take a new read snapshot in each statement.
+func (l IsolationLevel) PerStatementReadSnapshot() bool {
+	return l == READ_COMMITTED
+}
+
+// SafeValue implements the redact.SafeValue interface.
+func (IsolationLevel) SafeValue() {}
+
 // Short returns a prefix of the transaction's ID.
 func (t TxnMeta) Short() redact.SafeString {
 	return redact.SafeString(t.ID.Short())
@@ -369,6 +385,9 @@ func (t TxnMeta) FormatW(buf io.Writer) {
 		t.WriteTimestamp,
 		t.MinTimestamp,
 		t.Sequence)
+	if t.IsoLevel != SERIALIZABLE {
+		fmt.Fprintf(buf, " iso=%s", t.IsoLevel)
+	}
 }
 
 // SafeMessage implements the SafeMessager interface.
@@ -389,6 +408,9 @@ func (t TxnMeta) SafeMessage() string {
 		t.WriteTimestamp,
 		t.MinTimestamp,
 		t.Sequence)
+	if t.IsoLevel != SERIALIZABLE {
+		fmt.Fprintf(&buf, " iso=%s", t.IsoLevel)
+	}
 	return buf.String()
 }
 
@@ -2600,6 +2600,64 @@ var charts = []sectionDescription{
 			},
 		},
 	},
+	{
+		Organization: [][]string{{Jobs, "Schedules", "Interval Partitioning"}},
+		Charts: []chartDescription{
+			{
+				Title: "Counts",
+				Metrics: []string{
+					"schedules.scheduled-interval-partitioning-executor.started",
+					"schedules.scheduled-interval-partitioning-executor.succeeded",
+					"schedules.scheduled-interval-partitioning-executor.failed",
+				},
+			},
+		},
+	},
+	{
+		Organization: [][]string{{Jobs, "Schedules", "Row Level TTL"}},
+		Charts: []chartDescription{
+			{
+				Title: "Counts",
+				Metrics: []string{
+					"schedules.scheduled-row-level-ttl-executor.started",
+					"schedules.scheduled-row-level-ttl-executor.succeeded",
+					"schedules.scheduled-row-level-ttl-executor.failed",
+				},
+			},
+		},
+	},
+	{
+		Organization: [][]string{{Jobs, "Row Level TTL"}},
+		Charts: []chartDescription{
+			{
+				Title: "Rows",
+				Metrics: []string{
+					"jobs.row_level_ttl.rows_selected",
+					"jobs.row_level_ttl.rows_deleted",
+				},
+				AxisLabel: "Rows",
+			},
+			{
+				Title: "Ranges Processed",
+				Metrics: []string{
+					"jobs.row_level_ttl.ranges_processed",
+				},
+				AxisLabel: "Ranges",
+			},
+			{
+				Title: "Select Latency",
+				Metrics: []string{
+					"jobs.row_level_ttl.select_duration",
+				},
+			},
+			{
+				Title: "Delete Latency",
+				Metrics: []string{
+					"jobs.row_level_ttl.delete_duration",
+				},
+			},
+		},
+	},
 	{
 		Organization: [][]string{{Jobs, "Execution"}},
 		Charts: []chartDescription{
@@ -2620,6 +2678,9 @@ var charts = []sectionDescription{
 					"jobs.migration.currently_running",
 					"jobs.auto_span_config_reconciliation.currently_running",
 					"jobs.auto_sql_stats_compaction.currently_running",
+					"jobs.subscription.currently_running",
+					"jobs.interval_partitioning.currently_running",
+					"jobs.row_level_ttl.currently_running",
 				},
 			},
 			{
@@ -2785,6 +2846,39 @@ var charts = []sectionDescription{
 					"jobs.auto_sql_stats_compaction.resume_retry_error",
 				},
 			},
+			{
+				Title: "Subscription",
+				Metrics: []string{
+					"jobs.subscription.fail_or_cancel_completed",
+					"jobs.subscription.fail_or_cancel_failed",
+					"jobs.subscription.fail_or_cancel_retry_error",
+					"jobs.subscription.resume_completed",
+					"jobs.subscription.resume_failed",
+					"jobs.subscription.resume_retry_error",
+				},
+			},
+			{
+				Title: "Interval Partitioning",
+				Metrics: []string{
+					"jobs.interval_partitioning.fail_or_cancel_completed",
+					"jobs.interval_partitioning.fail_or_cancel_failed",
+					"jobs.interval_partitioning.fail_or_cancel_retry_error",
+					"jobs.interval_partitioning.resume_completed",
+					"jobs.interval_partitioning.resume_failed",
+					"jobs.interval_partitioning.resume_retry_error",
+				},
+			},
+			{
+				Title: "Row Level TTL",
+				Metrics: []string{
+					"jobs.row_level_ttl.fail_or_cancel_completed",
+					"jobs.row_level_ttl.fail_or_cancel_failed",
+					"jobs.row_level_ttl.fail_or_cancel_retry_error",
+					"jobs.row_level_ttl.resume_completed",
+					"jobs.row_level_ttl.resume_failed",
+					"jobs.row_level_ttl.resume_retry_error",
+				},
+			},
 		},
 	},
 	{
@@ -2811,8 +2905,11 @@ var charts = []sectionDescription{
 					"admission.admitted.kv",
 					"admission.errored.kv",
 					"admission.requested.kv-stores",
+					"admission.requested.kv-bulk-stores",
 					"admission.admitted.kv-stores",
+					"admission.admitted.kv-bulk-stores",
 					"admission.errored.kv-stores",
+					"admission.errored.kv-bulk-stores",
 					"admission.requested.sql-kv-response",
 					"admission.admitted.sql-kv-response",
 					"admission.errored.sql-kv-response",
@@ -2832,6 +2929,7 @@ var charts = []sectionDescription{
 				Metrics: []string{
 					"admission.wait_queue_length.kv",
 					"admission.wait_queue_length.kv-stores",
+					"admission.wait_queue_length.kv-bulk-stores",
 					"admission.wait_queue_length.sql-kv-response",
 					"admission.wait_queue_length.sql-sql-response",
 					"admission.wait_queue_length.sql-leaf-start",
@@ -2843,6 +2941,7 @@ var charts = []sectionDescription{
 				Metrics: []string{
 					"admission.wait_sum.kv",
 					"admission.wait_sum.kv-stores",
+					"admission.wait_sum.kv-bulk-stores",
 					"admission.wait_sum.sql-kv-response",
 					"admission.wait_sum.sql-sql-response",
 					"admission.wait_sum.sql-leaf-start",
@@ -2854,6 +2953,7 @@ var charts = []sectionDescription{
 				Metrics: []string{
 					"admission.wait_durations.kv",
 					"admission.wait_durations.kv-stores",
+					"admission.wait_durations.kv-bulk-stores",
 					"admission.wait_durations.sql-kv-response",
 					"admission.wait_durations.sql-sql-response",
 					"admission.wait_durations.sql-leaf-start",
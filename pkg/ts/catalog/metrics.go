@@ -70,6 +70,7 @@ var histogramMetricsNames = map[string]struct{}{
 	"sql.txn.latency":                           {},
 	"sql.mem.root.max":                          {},
 	"admission.wait_durations.kv-stores":        {},
+	"admission.wait_durations.kv-bulk-stores":   {},
 	"sql.stats.mem.max":                         {},
 	"sql.distsql.service.latency.internal":      {},
 	"sql.stats.reported.mem.max.internal":       {},
@@ -50,6 +50,23 @@ var L0SubLevelCountOverloadThreshold = settings.RegisterIntSetting(
 	"when the L0 sub-level count exceeds this threshold, the store is considered overloaded",
 	l0SubLevelCountOverloadThreshold, settings.PositiveInt)
 
+// ProvisionedBandwidth sets the disk write bandwidth that is provisioned for
+// each store. It is used to throttle KVBulkWork when the store's writes are
+// close to saturating the disk.
+var ProvisionedBandwidth = settings.RegisterByteSizeSetting(
+	"admission.kv.stores.provisioned_bandwidth",
+	"if set to a non-zero value, this is used as the provisioned write bandwidth (in bytes/s) "+
+		"of each store, and bulk work is throttled when the bandwidth used approaches it",
+	0, settings.NonNegativeInt).WithPublic()
+
+// BulkBandwidthUtilizationThreshold sets the fraction of ProvisionedBandwidth
+// that writes to a store can use before KVBulkWork is throttled.
+var BulkBandwidthUtilizationThreshold = settings.RegisterFloatSetting(
+	"admission.kv.stores.bulk_bandwidth_utilization_threshold",
+	"the fraction of the provisioned bandwidth that writes to a store can use before bulk "+
+		"work is throttled",
+	0.8, settings.PositiveFloat).WithPublic()
+
 // grantChainID is the ID for a grant chain. See continueGrantChain for
 // details.
 type grantChainID uint64
@@ -201,6 +218,11 @@ type WorkKind int8
 //   provided by kvSlotAdjuster, provides instantaneous feedback (which is
 //   viable only because KVWork is the highest priority).
 //
+// KVBulkWork is the exception to the lower to higher level ordering: it is
+// only handled by the per-store GrantCoordinators, where it is ordered after
+// KVWork so that bulk writes (like AddSSTable requests issued by backfills
+// and imports) are throttled before foreground writes.
+//
 // Weaknesses of this strict prioritization across WorkKinds:
 // - Priority inversion: Lower importance KVWork, not derived from SQL, like
 //   GC of MVCC versions, will happen before user-facing SQLKVResponseWork.
@@ -249,6 +271,10 @@ const (
 	// SQLStatementRootStartWork represents the start of root-level processing
 	// for a SQL statement.
 	SQLStatementRootStartWork
+	// KVBulkWork represents AddSSTable requests submitted to the KV layer, that
+	// are subject to admission control by a store's disk bandwidth in addition
+	// to its IO load. It is not handled by the regular GrantCoordinator.
+	KVBulkWork
 	numWorkKinds
 )
 
@@ -264,6 +290,8 @@ func workKindString(workKind WorkKind) redact.RedactableString {
 		return "sql-leaf-start"
 	case SQLStatementRootStartWork:
 		return "sql-root-start"
+	case KVBulkWork:
+		return "kv-bulk"
 	default:
 		panic(errors.AssertionFailedf("unknown WorkKind"))
 	}
@@ -484,17 +512,12 @@ func (sg *kvGranter) tryGet() bool {
 
 func (sg *kvGranter) tryGetLocked() grantResult {
 	if sg.usedSlots < sg.totalSlots || sg.skipSlotEnforcement {
-		if !sg.ioTokensEnabled || sg.availableIOTokens > 0 {
+		if sg.hasIOTokensLocked() {
 			sg.usedSlots++
 			if sg.usedSlotsMetric != nil {
 				sg.usedSlotsMetric.Update(int64(sg.usedSlots))
 			}
-			if sg.ioTokensEnabled {
-				sg.availableIOTokens--
-				if sg.availableIOTokens == 0 {
-					sg.exhaustedStart = timeutil.Now()
-				}
-			}
+			sg.subtractIOTokenLocked()
 			return grantSuccess
 		}
 		return grantFailLocal
@@ -502,6 +525,23 @@ func (sg *kvGranter) tryGetLocked() grantResult {
 	return grantFailDueToSharedResource
 }
 
+// hasIOTokensLocked returns whether an IO token can be consumed.
+func (sg *kvGranter) hasIOTokensLocked() bool {
+	return !sg.ioTokensEnabled || sg.availableIOTokens > 0
+}
+
+// subtractIOTokenLocked consumes an IO token, if IO tokens are enabled. The
+// available tokens can become negative, if the token was taken without
+// permission.
+func (sg *kvGranter) subtractIOTokenLocked() {
+	if sg.ioTokensEnabled {
+		sg.availableIOTokens--
+		if sg.availableIOTokens == 0 {
+			sg.exhaustedStart = timeutil.Now()
+		}
+	}
+}
+
 func (sg *kvGranter) returnGrant() {
 	sg.coord.returnGrant(KVWork)
 }
@@ -525,12 +565,7 @@ func (sg *kvGranter) tookWithoutPermissionLocked() {
 	if sg.usedSlotsMetric != nil {
 		sg.usedSlotsMetric.Update(int64(sg.usedSlots))
 	}
-	if sg.ioTokensEnabled {
-		sg.availableIOTokens--
-		if sg.availableIOTokens == 0 {
-			sg.exhaustedStart = timeutil.Now()
-		}
-	}
+	sg.subtractIOTokenLocked()
 }
 
 func (sg *kvGranter) continueGrantChain(grantChainID grantChainID) {
@@ -560,6 +595,94 @@ func (sg *kvGranter) setAvailableIOTokensLocked(tokens int64) {
 	}
 }
 
+// kvBulkGranter implements granterWithLockedCalls. It is used for grants to
+// KVBulkWork in the per-store GrantCoordinators. Bulk work consumes the same
+// IO tokens as KVWork, and is additionally limited by disk bandwidth tokens.
+// Since the GrantCoordinator grants to KVWork before KVBulkWork, foreground
+// writes get the first claim on the IO tokens.
+type kvBulkGranter struct {
+	coord     *GrantCoordinator
+	requester requester
+	// kvGranter is the granter for KVWork in the same GrantCoordinator, whose
+	// IO tokens are shared with KVBulkWork.
+	kvGranter *kvGranter
+	usedSlots int
+
+	diskBandwidthTokensEnabled bool
+	// There is no rate limiting in granting these tokens. That is, they are all
+	// burst tokens.
+	availableDiskBandwidthTokens int64
+}
+
+var _ granterWithLockedCalls = &kvBulkGranter{}
+
+func (bg *kvBulkGranter) getPairedRequester() requester {
+	return bg.requester
+}
+
+func (bg *kvBulkGranter) grantKind() grantKind {
+	// Slot represents that there is a completion indicator, and it does not
+	// matter that kvBulkGranter only limits admission using tokens.
+	return slot
+}
+
+func (bg *kvBulkGranter) tryGet() bool {
+	return bg.coord.tryGet(KVBulkWork)
+}
+
+func (bg *kvBulkGranter) tryGetLocked() grantResult {
+	if bg.diskBandwidthTokensEnabled && bg.availableDiskBandwidthTokens <= 0 {
+		return grantFailLocal
+	}
+	if !bg.kvGranter.hasIOTokensLocked() {
+		return grantFailLocal
+	}
+	bg.usedSlots++
+	bg.subtractTokensLocked()
+	return grantSuccess
+}
+
+func (bg *kvBulkGranter) returnGrant() {
+	bg.coord.returnGrant(KVBulkWork)
+}
+
+func (bg *kvBulkGranter) returnGrantLocked() {
+	bg.usedSlots--
+	if bg.usedSlots < 0 {
+		panic(errors.AssertionFailedf("used slots is negative %d", bg.usedSlots))
+	}
+}
+
+func (bg *kvBulkGranter) tookWithoutPermission() {
+	bg.coord.tookWithoutPermission(KVBulkWork)
+}
+
+func (bg *kvBulkGranter) tookWithoutPermissionLocked() {
+	bg.usedSlots++
+	bg.subtractTokensLocked()
+}
+
+func (bg *kvBulkGranter) continueGrantChain(grantChainID grantChainID) {
+	bg.coord.continueGrantChain(KVBulkWork, grantChainID)
+}
+
+func (bg *kvBulkGranter) subtractTokensLocked() {
+	if bg.diskBandwidthTokensEnabled {
+		bg.availableDiskBandwidthTokens--
+	}
+	bg.kvGranter.subtractIOTokenLocked()
+}
+
+func (bg *kvBulkGranter) setAvailableDiskBandwidthTokensLocked(tokens int64) {
+	bg.diskBandwidthTokensEnabled = true
+	if bg.availableDiskBandwidthTokens < 0 {
+		// Negative because of tookWithoutPermission.
+		bg.availableDiskBandwidthTokens += tokens
+	} else {
+		bg.availableDiskBandwidthTokens = tokens
+	}
+}
+
 // GrantCoordinator is the top-level object that coordinates grants across
 // different WorkKinds (for more context see the comment in doc.go, and the
 // comment where WorkKind is declared). Typically there will one
@@ -724,12 +847,14 @@ func NewGrantCoordinators(opts Options) (GrantCoordinators, []metric.Struct) {
 	metricStructs = appendMetricStructsForQueues(metricStructs, coord)
 
 	storeWorkQueueMetrics := makeWorkQueueMetrics(string(workKindString(KVWork)) + "-stores")
-	metricStructs = append(metricStructs, storeWorkQueueMetrics)
+	storeBulkWorkQueueMetrics := makeWorkQueueMetrics(string(workKindString(KVBulkWork)) + "-stores")
+	metricStructs = append(metricStructs, storeWorkQueueMetrics, storeBulkWorkQueueMetrics)
 	storeCoordinators := &StoreGrantCoordinators{
 		settings:                    st,
 		makeRequesterFunc:           makeRequester,
 		kvIOTokensExhaustedDuration: metrics.KVIOTokensExhaustedDuration,
 		workQueueMetrics:            storeWorkQueueMetrics,
+		bulkWorkQueueMetrics:        storeBulkWorkQueueMetrics,
 	}
 
 	return GrantCoordinators{Stores: storeCoordinators, Regular: coord}, metricStructs
@@ -1112,6 +1237,9 @@ func (coord *GrantCoordinator) SafeFormat(s redact.SafePrinter, verb rune) {
 	newlineStr := redact.RedactableString("\n")
 	curSep := spaceStr
 	for i := range coord.granters {
+		if coord.granters[i] == nil {
+			continue
+		}
 		kind := WorkKind(i)
 		switch kind {
 		case KVWork:
@@ -1120,6 +1248,12 @@ func (coord *GrantCoordinator) SafeFormat(s redact.SafePrinter, verb rune) {
 			if g.ioTokensEnabled {
 				s.Printf(" io-avail: %d", g.availableIOTokens)
 			}
+		case KVBulkWork:
+			g := coord.granters[i].(*kvBulkGranter)
+			s.Printf("%s%s: used: %d", curSep, workKindString(kind), g.usedSlots)
+			if g.diskBandwidthTokensEnabled {
+				s.Printf(" disk-bandwidth-avail: %d", g.availableDiskBandwidthTokens)
+			}
 		case SQLStatementLeafStartWork, SQLStatementRootStartWork:
 			g := coord.granters[i].(*slotGranter)
 			s.Printf("%s%s: used: %d, total: %d", curSep, workKindString(kind), g.usedSlots, g.totalSlots)
@@ -1143,7 +1277,8 @@ type StoreGrantCoordinators struct {
 	makeRequesterFunc           makeRequesterFunc
 	kvIOTokensExhaustedDuration *metric.Counter
 	// These metrics are shared by WorkQueues across stores.
-	workQueueMetrics WorkQueueMetrics
+	workQueueMetrics     WorkQueueMetrics
+	bulkWorkQueueMetrics WorkQueueMetrics
 
 	gcMap syncutil.IntMap // map[int64(StoreID)]*GrantCoordinator
 	// numStores is used to track the number of stores which have been added
@@ -1252,13 +1387,26 @@ func (sgc *StoreGrantCoordinators) initGrantCoordinator(storeID int32) *GrantCoo
 	coord.queues[KVWork] = sgc.makeRequesterFunc(KVWork, kvg, sgc.settings, opts)
 	kvg.requester = coord.queues[KVWork]
 	coord.granters[KVWork] = kvg
+
+	bg := &kvBulkGranter{
+		coord:     coord,
+		kvGranter: kvg,
+	}
+	opts = makeWorkQueueOptions(KVBulkWork)
+	opts.metrics = &sgc.bulkWorkQueueMetrics
+	coord.queues[KVBulkWork] = sgc.makeRequesterFunc(KVBulkWork, bg, sgc.settings, opts)
+	bg.requester = coord.queues[KVBulkWork]
+	coord.granters[KVBulkWork] = bg
+
 	coord.ioLoadListener = &ioLoadListener{
-		storeID:     storeID,
-		settings:    sgc.settings,
-		kvRequester: coord.queues[KVWork],
+		storeID:         storeID,
+		settings:        sgc.settings,
+		kvRequester:     coord.queues[KVWork],
+		kvBulkRequester: coord.queues[KVBulkWork],
 	}
 	coord.ioLoadListener.mu.Mutex = &coord.mu
 	coord.ioLoadListener.mu.kvGranter = coord.granters[KVWork].(*kvGranter)
+	coord.ioLoadListener.mu.kvBulkGranter = coord.granters[KVBulkWork].(*kvBulkGranter)
 	return coord
 }
 
@@ -1272,6 +1420,27 @@ func (sgc *StoreGrantCoordinators) TryGetQueueForStore(storeID int32) *WorkQueue
 	return nil
 }
 
+// TryGetBulkQueueForStore returns the WorkQueue for KVBulkWork for the given
+// storeID, or nil if the storeID is not known.
+func (sgc *StoreGrantCoordinators) TryGetBulkQueueForStore(storeID int32) *WorkQueue {
+	if unsafeGranter, ok := sgc.gcMap.Load(int64(storeID)); ok {
+		granter := (*GrantCoordinator)(unsafeGranter)
+		return granter.GetWorkQueue(KVBulkWork)
+	}
+	return nil
+}
+
+// SetTenantWeights sets the tenant weights used by the WorkQueues of the
+// given storeID. It is a noop if the storeID is not known. See
+// WorkQueue.SetTenantWeights for details.
+func (sgc *StoreGrantCoordinators) SetTenantWeights(storeID int32, weights map[uint64]uint32) {
+	if unsafeGranter, ok := sgc.gcMap.Load(int64(storeID)); ok {
+		granter := (*GrantCoordinator)(unsafeGranter)
+		granter.GetWorkQueue(KVWork).SetTenantWeights(weights)
+		granter.GetWorkQueue(KVBulkWork).SetTenantWeights(weights)
+	}
+}
+
 func (sgc *StoreGrantCoordinators) close() {
 	// closeCh can be nil in tests that never called SetPebbleMetricsProvider.
 	if sgc.closeCh != nil {
@@ -1424,20 +1593,35 @@ type granterWithIOTokens interface {
 	setAvailableIOTokensLocked(tokens int64)
 }
 
+// granterWithDiskBandwidthTokens is used to abstract kvBulkGranter for
+// testing.
+type granterWithDiskBandwidthTokens interface {
+	// setAvailableDiskBandwidthTokensLocked is the equivalent of
+	// granterWithIOTokens.setAvailableIOTokensLocked for the disk bandwidth
+	// tokens consumed by KVBulkWork.
+	setAvailableDiskBandwidthTokensLocked(tokens int64)
+}
+
 // ioLoadListener adjusts tokens in kvGranter for IO, specifically due to
 // overload caused by writes. IO uses tokens and not slots since work
 // completion is not an indicator that the "resource usage" has ceased -- it
 // just means that the write has been applied to the WAL. Most of the work is
 // in flushing to sstables and the following compactions, which happens later.
+//
+// It also adjusts the disk bandwidth tokens in kvBulkGranter, which limit
+// KVBulkWork when the bytes written by the store approach its provisioned
+// bandwidth.
 type ioLoadListener struct {
-	storeID     int32
-	settings    *cluster.Settings
-	kvRequester requester
-	mu          struct {
-		// Used when changing state in kvGranter. This is a pointer since it is
-		// the same as GrantCoordinator.mu.
+	storeID         int32
+	settings        *cluster.Settings
+	kvRequester     requester
+	kvBulkRequester requester
+	mu              struct {
+		// Used when changing state in kvGranter and kvBulkGranter. This is a
+		// pointer since it is the same as GrantCoordinator.mu.
 		*syncutil.Mutex
-		kvGranter granterWithIOTokens
+		kvGranter     granterWithIOTokens
+		kvBulkGranter granterWithDiskBandwidthTokens
 	}
 
 	// Cumulative stats used to compute interval stats.
@@ -1455,6 +1639,18 @@ type ioLoadListener struct {
 	// represents what has been given out.
 	totalTokens     int64
 	tokensAllocated int64
+
+	// Cumulative stats used to compute the disk bandwidth used in an interval.
+	bulkAdmittedCount uint64
+	writeBytes        uint64
+	ingestedBytes     uint64
+	// Exponentially smoothed per interval value.
+	smoothedBytesPerBulkWork float64
+
+	// totalBulkTokens and bulkTokensAllocated are the equivalent of totalTokens
+	// and tokensAllocated for the disk bandwidth tokens given to KVBulkWork.
+	totalBulkTokens     int64
+	bulkTokensAllocated int64
 }
 
 const unlimitedTokens = math.MaxInt64
@@ -1507,40 +1703,59 @@ func (io *ioLoadListener) pebbleMetricsTick(m pebble.Metrics) {
 		io.admittedCount = io.kvRequester.getAdmittedCount()
 		io.l0Bytes = m.Levels[0].Size
 		io.l0AddedBytes = m.Levels[0].BytesFlushed + m.Levels[0].BytesIngested
+		io.bulkAdmittedCount = io.kvBulkRequester.getAdmittedCount()
+		io.writeBytes, io.ingestedBytes = diskWriteBytes(m)
 		// No initial limit, i.e, the first interval is unlimited.
 		io.totalTokens = unlimitedTokens
+		io.totalBulkTokens = unlimitedTokens
 		return
 	}
 	io.adjustTokens(m)
+	io.adjustBulkTokens(m)
 }
 
-// allocateTokensTick gives out 1/adjustmentInterval of the totalTokens every
-// 1s.
+// allocateTokensTick gives out 1/adjustmentInterval of the totalTokens and
+// totalBulkTokens every 1s.
 func (io *ioLoadListener) allocateTokensTick() {
+	toAllocate := tokensForTick(io.totalTokens, io.tokensAllocated)
+	toAllocateBulk := tokensForTick(io.totalBulkTokens, io.bulkTokensAllocated)
+	// INVARIANT: toAllocate >= 0 && toAllocateBulk >= 0.
+	io.mu.Lock()
+	defer io.mu.Unlock()
+	io.tokensAllocated += toAllocate
+	if io.tokensAllocated < 0 {
+		panic(errors.AssertionFailedf("tokens allocated is negative %d", io.tokensAllocated))
+	}
+	io.bulkTokensAllocated += toAllocateBulk
+	if io.bulkTokensAllocated < 0 {
+		panic(errors.AssertionFailedf(
+			"bulk tokens allocated is negative %d", io.bulkTokensAllocated))
+	}
+	io.mu.kvGranter.setAvailableIOTokensLocked(toAllocate)
+	io.mu.kvBulkGranter.setAvailableDiskBandwidthTokensLocked(toAllocateBulk)
+}
+
+// tokensForTick returns the tokens to give out in a 1s tick, given the
+// totalTokens for the adjustmentInterval, of which allocated have already
+// been given out.
+func tokensForTick(totalTokens int64, allocated int64) int64 {
 	var toAllocate int64
 	// unlimitedTokens==MaxInt64, so avoid overflow in the rounding up
 	// calculation.
-	if io.totalTokens >= unlimitedTokens-(adjustmentInterval-1) {
-		toAllocate = io.totalTokens / adjustmentInterval
+	if totalTokens >= unlimitedTokens-(adjustmentInterval-1) {
+		toAllocate = totalTokens / adjustmentInterval
 	} else {
 		// Round up so that we don't accumulate tokens to give in a burst on the
 		// last tick.
-		toAllocate = (io.totalTokens + adjustmentInterval - 1) / adjustmentInterval
+		toAllocate = (totalTokens + adjustmentInterval - 1) / adjustmentInterval
 		if toAllocate < 0 {
 			panic(errors.AssertionFailedf("toAllocate is negative %d", toAllocate))
 		}
-		if toAllocate+io.tokensAllocated > io.totalTokens {
-			toAllocate = io.totalTokens - io.tokensAllocated
+		if toAllocate+allocated > totalTokens {
+			toAllocate = totalTokens - allocated
 		}
 	}
-	// INVARIANT: toAllocate >= 0.
-	io.mu.Lock()
-	defer io.mu.Unlock()
-	io.tokensAllocated += toAllocate
-	if io.tokensAllocated < 0 {
-		panic(errors.AssertionFailedf("tokens allocated is negative %d", io.tokensAllocated))
-	}
-	io.mu.kvGranter.setAvailableIOTokensLocked(toAllocate)
+	return toAllocate
 }
 
 // adjustTokens computes a new value of totalTokens (and resets
@@ -1658,6 +1873,108 @@ func (io *ioLoadListener) adjustTokens(m pebble.Metrics) {
 	io.l0AddedBytes = l0AddedBytes
 }
 
+// diskWriteBytes returns the cumulative bytes written by the store, and the
+// subset of those bytes that were ingested. Ingested sstables are the main
+// way bulk work (like backfills and imports) writes to a store. The bytes
+// written include the WAL, flushes and compactions, and are used as a proxy
+// for the disk write bandwidth used by the store.
+func diskWriteBytes(m pebble.Metrics) (writeBytes uint64, ingestedBytes uint64) {
+	writeBytes = m.WAL.BytesWritten
+	for i := range m.Levels {
+		writeBytes += m.Levels[i].BytesFlushed + m.Levels[i].BytesIngested +
+			m.Levels[i].BytesCompacted
+		ingestedBytes += m.Levels[i].BytesIngested
+	}
+	return writeBytes, ingestedBytes
+}
+
+// adjustBulkTokens computes a new value of totalBulkTokens (and resets
+// bulkTokensAllocated). KVBulkWork is only constrained when the bandwidth
+// used by the store over the last interval exceeds the fraction of the
+// provisioned bandwidth given by BulkBandwidthUtilizationThreshold. In that
+// case bulk work is limited to the bandwidth, under that fraction, that is
+// left over by the remaining writes. Note that the bytes written by
+// compactions are all attributed to the remaining writes, even though some
+// of them are due to earlier bulk work, which errs on the side of throttling
+// bulk work.
+func (io *ioLoadListener) adjustBulkTokens(m pebble.Metrics) {
+	io.bulkTokensAllocated = 0
+	// Grab the cumulative stats.
+	bulkAdmittedCount := io.kvBulkRequester.getAdmittedCount()
+	writeBytes, ingestedBytes := diskWriteBytes(m)
+	// Compute the stats for the interval. These are simple delta computations
+	// over individually cumulative stats, so should not be negative.
+	bytesWritten := int64(writeBytes - io.writeBytes)
+	if bytesWritten < 0 {
+		log.Warningf(context.Background(), "bytesWritten %d is negative", bytesWritten)
+		bytesWritten = 0
+	}
+	bulkBytes := int64(ingestedBytes - io.ingestedBytes)
+	if bulkBytes < 0 {
+		log.Warningf(context.Background(), "bulkBytes %d is negative", bulkBytes)
+		bulkBytes = 0
+	}
+	if bulkBytes > bytesWritten {
+		bulkBytes = bytesWritten
+	}
+	var bulkAdmitted uint64
+	if bulkAdmittedCount < io.bulkAdmittedCount {
+		log.Warningf(context.Background(), "bulk admitted count decreased from %d to %d",
+			io.bulkAdmittedCount, bulkAdmittedCount)
+	} else {
+		bulkAdmitted = bulkAdmittedCount - io.bulkAdmittedCount
+	}
+	// Attribute the bulkBytes equally to all the admitted bulk work. See the
+	// comment in adjustTokens for why admitting a single work item is ignored.
+	if bulkAdmitted > 1 {
+		if perWork := float64(bulkBytes) / float64(bulkAdmitted); perWork > 0 {
+			const alpha = 0.5
+			if io.smoothedBytesPerBulkWork == 0 {
+				io.smoothedBytesPerBulkWork = perWork
+			} else {
+				io.smoothedBytesPerBulkWork = alpha*perWork + (1-alpha)*io.smoothedBytesPerBulkWork
+			}
+		}
+	}
+
+	provisionedBandwidth := ProvisionedBandwidth.Get(&io.settings.SV)
+	threshold := BulkBandwidthUtilizationThreshold.Get(&io.settings.SV)
+	// The bytes the store can write in an interval before bulk work is
+	// constrained.
+	allowedBytes := threshold * float64(provisionedBandwidth) * adjustmentInterval
+	if provisionedBandwidth <= 0 || float64(bytesWritten) < allowedBytes {
+		io.totalBulkTokens = unlimitedTokens
+	} else {
+		bytesPerBulkWork := io.smoothedBytesPerBulkWork
+		if bytesPerBulkWork < 1 {
+			// We've never seen any bulk work or somehow the estimate is less than
+			// 1. This is important to avoid overflow.
+			bytesPerBulkWork = 1
+		}
+		availableBytes := allowedBytes - float64(bytesWritten-bulkBytes)
+		if availableBytes < 0 {
+			availableBytes = 0
+		}
+		if numAdmit := availableBytes / bytesPerBulkWork; float64(math.MaxInt64) < numAdmit {
+			// Avoid overflow. This will be very rare.
+			io.totalBulkTokens = math.MaxInt64
+		} else {
+			io.totalBulkTokens = int64(numAdmit)
+		}
+		if bulkAdmitted > 0 {
+			log.Infof(context.Background(),
+				"disk bandwidth overload on store %d (written %d, provisioned %d/s): "+
+					"bulk admitted: %d, bulk bytes: %d, bulk admit: %d",
+				io.storeID, bytesWritten, provisionedBandwidth, bulkAdmitted, bulkBytes,
+				io.totalBulkTokens)
+		}
+	}
+	// Install the latest cumulative stats.
+	io.bulkAdmittedCount = bulkAdmittedCount
+	io.writeBytes = writeBytes
+	io.ingestedBytes = ingestedBytes
+}
+
 var _ cpuOverloadIndicator = &sqlNodeCPUOverloadIndicator{}
 var _ CPULoadListener = &sqlNodeCPUOverloadIndicator{}
 
@@ -205,6 +205,8 @@ func scanWorkKind(t *testing.T, d *datadriven.TestData) WorkKind {
 		return SQLStatementLeafStartWork
 	case "sql-root-start":
 		return SQLStatementRootStartWork
+	case "kv-bulk":
+		return KVBulkWork
 	}
 	panic("unknown WorkKind")
 }
@@ -238,6 +240,8 @@ func TestStoreCoordinators(t *testing.T) {
 	// All the KVWork requesters. The first one is for all KVWork and the
 	// remaining are the per-store ones.
 	var requesters []*testRequester
+	// All the KVBulkWork requesters, which are only created per-store.
+	var bulkRequesters []*testRequester
 	opts := Options{
 		Settings: settings,
 		makeRequesterFunc: func(
@@ -250,6 +254,8 @@ func TestStoreCoordinators(t *testing.T) {
 			}
 			if workKind == KVWork {
 				requesters = append(requesters, req)
+			} else if workKind == KVBulkWork {
+				bulkRequesters = append(bulkRequesters, req)
 			}
 			return req
 		},
@@ -265,8 +271,9 @@ func TestStoreCoordinators(t *testing.T) {
 	// Setting the metrics provider will cause the initialization of two
 	// GrantCoordinators for the two stores.
 	storeCoords.SetPebbleMetricsProvider(&mp)
-	// Now we have 1+2 = 3 KVWork requesters.
+	// Now we have 1+2 = 3 KVWork requesters, and 2 KVBulkWork requesters.
 	require.Equal(t, 3, len(requesters))
+	require.Equal(t, 2, len(bulkRequesters))
 	// Confirm that the store IDs are as expected.
 	var actualStores []int32
 
@@ -290,9 +297,86 @@ func TestStoreCoordinators(t *testing.T) {
 	require.Equal(t,
 		"kv: tryGet returned false\nkv: tryGet returned true\nkv: tryGet returned true\n",
 		buf.String())
+	// The KVBulkWork requesters also have unlimited tokens at this point in
+	// time.
+	buf.Reset()
+	for i := range bulkRequesters {
+		bulkRequesters[i].tryGet()
+	}
+	require.Equal(t, "kv-bulk: tryGet returned true\nkv-bulk: tryGet returned true\n", buf.String())
 	coords.Close()
 }
 
+// TestKVBulkGranter tests that KVBulkWork in a store's GrantCoordinator is
+// limited by both disk bandwidth tokens and the IO tokens it shares with
+// KVWork, and that KVWork is granted before KVBulkWork.
+func TestKVBulkGranter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	var buf strings.Builder
+	var requesters [numWorkKinds]*testRequester
+	sgc := &StoreGrantCoordinators{
+		settings: cluster.MakeTestingClusterSettings(),
+		makeRequesterFunc: func(
+			workKind WorkKind, granter granter, _ *cluster.Settings, opts workQueueOptions) requester {
+			req := &testRequester{
+				workKind:   workKind,
+				granter:    granter,
+				usesTokens: opts.usesTokens,
+				buf:        &buf,
+			}
+			requesters[workKind] = req
+			return req
+		},
+	}
+	coord := sgc.initGrantCoordinator(1)
+	setTokens := func(ioTokens, diskBandwidthTokens int64) {
+		coord.mu.Lock()
+		defer coord.mu.Unlock()
+		coord.granters[KVWork].(*kvGranter).setAvailableIOTokensLocked(ioTokens)
+		coord.granters[KVBulkWork].(*kvBulkGranter).setAvailableDiskBandwidthTokensLocked(
+			diskBandwidthTokens)
+	}
+	kv, bulk := requesters[KVWork], requesters[KVBulkWork]
+
+	setTokens(2, 1)
+	// The first bulk request consumes the only disk bandwidth token.
+	bulk.tryGet()
+	bulk.tryGet()
+	// KVWork is not limited by the disk bandwidth tokens.
+	kv.tryGet()
+	require.Equal(t,
+		"kv-bulk: tryGet returned true\nkv-bulk: tryGet returned false\nkv: tryGet returned true\n",
+		buf.String())
+	require.Equal(t, "(chain: id: 0 active: false index: 0) kv: used: 1, total: 2147483647 "+
+		"io-avail: 0 kv-bulk: used: 1 disk-bandwidth-avail: 0", coord.String())
+	buf.Reset()
+
+	// The IO tokens are exhausted, so bulk work is not admitted even though
+	// there are disk bandwidth tokens.
+	setTokens(0, 5)
+	bulk.tryGet()
+	require.Equal(t, "kv-bulk: tryGet returned false\n", buf.String())
+	buf.Reset()
+
+	// With both KVWork and KVBulkWork waiting, the only IO token is granted to
+	// KVWork.
+	kv.waitingRequests = true
+	bulk.waitingRequests = true
+	setTokens(1, 5)
+	coord.testingTryGrant()
+	require.Equal(t, "kv: granted in chain 0, and returning true\n", buf.String())
+	buf.Reset()
+
+	// Once KVWork is no longer waiting, KVBulkWork is granted.
+	kv.waitingRequests = false
+	setTokens(1, 5)
+	coord.testingTryGrant()
+	require.Equal(t, "kv-bulk: granted in chain 0, and returning true\n", buf.String())
+	coord.Close()
+}
+
 type testRequesterForIOLL struct {
 	admittedCount uint64
 }
@@ -317,6 +401,14 @@ func (g *testGranterWithIOTokens) setAvailableIOTokensLocked(tokens int64) {
 	fmt.Fprintf(&g.buf, "setAvailableIOTokens: %s", tokensFor1sToString(tokens))
 }
 
+type testGranterWithDiskBandwidthTokens struct {
+	tokens []int64
+}
+
+func (g *testGranterWithDiskBandwidthTokens) setAvailableDiskBandwidthTokensLocked(tokens int64) {
+	g.tokens = append(g.tokens, tokens)
+}
+
 func tokensForIntervalToString(tokens int64) string {
 	if tokens == unlimitedTokens {
 		return "unlimited"
@@ -364,8 +456,9 @@ func TestIOLoadListener(t *testing.T) {
 				metrics.Levels[0].Sublevels = int32(l0SubLevels)
 				if ioll == nil {
 					ioll = &ioLoadListener{
-						settings:    st,
-						kvRequester: req,
+						settings:        st,
+						kvRequester:     req,
+						kvBulkRequester: &testRequesterForIOLL{},
 					}
 					// The mutex is needed by ioLoadListener but is not useful in this
 					// test -- the channels provide synchronization and prevent this
@@ -373,6 +466,7 @@ func TestIOLoadListener(t *testing.T) {
 					// active.
 					ioll.mu.Mutex = &syncutil.Mutex{}
 					ioll.mu.kvGranter = kvGranter
+					ioll.mu.kvBulkGranter = &testGranterWithDiskBandwidthTokens{}
 				}
 				ioll.pebbleMetricsTick(metrics)
 				// Do the ticks until just before next adjustment.
@@ -400,11 +494,13 @@ func TestIOLoadListenerOverflow(t *testing.T) {
 	kvGranter := &testGranterWithIOTokens{}
 	st := cluster.MakeTestingClusterSettings()
 	ioll := ioLoadListener{
-		settings:    st,
-		kvRequester: req,
+		settings:        st,
+		kvRequester:     req,
+		kvBulkRequester: &testRequesterForIOLL{},
 	}
 	ioll.mu.Mutex = &syncutil.Mutex{}
 	ioll.mu.kvGranter = kvGranter
+	ioll.mu.kvBulkGranter = &testGranterWithDiskBandwidthTokens{}
 	// Bug 1: overflow when totalTokens is too large.
 	for i := int64(0); i < adjustmentInterval; i++ {
 		// Override the totalTokens manually to trigger the overflow bug.
@@ -433,11 +529,16 @@ func (g *testGranterNonNegativeTokens) setAvailableIOTokensLocked(tokens int64)
 	require.LessOrEqual(g.t, int64(0), tokens)
 }
 
+func (g *testGranterNonNegativeTokens) setAvailableDiskBandwidthTokensLocked(tokens int64) {
+	require.LessOrEqual(g.t, int64(0), tokens)
+}
+
 // TestBadIOLoadListenerStats tests that bad stats (non-monotonic cumulative
 // stats and negative values) don't cause panics or tokens to be negative.
 func TestBadIOLoadListenerStats(t *testing.T) {
 	var m pebble.Metrics
 	req := &testRequesterForIOLL{}
+	bulkReq := &testRequesterForIOLL{}
 
 	randomValues := func() {
 		// Use uints, and cast so that we get bad negative values.
@@ -446,16 +547,22 @@ func TestBadIOLoadListenerStats(t *testing.T) {
 		m.Levels[0].Size = int64(rand.Uint64())
 		m.Levels[0].BytesFlushed = rand.Uint64()
 		m.Levels[0].BytesIngested = rand.Uint64()
+		m.WAL.BytesWritten = rand.Uint64()
 		req.admittedCount = rand.Uint64()
+		bulkReq.admittedCount = rand.Uint64()
 	}
 	kvGranter := &testGranterNonNegativeTokens{t: t}
 	st := cluster.MakeTestingClusterSettings()
+	// Make sure the computation of disk bandwidth tokens is exercised.
+	ProvisionedBandwidth.Override(context.Background(), &st.SV, 1)
 	ioll := ioLoadListener{
-		settings:    st,
-		kvRequester: req,
+		settings:        st,
+		kvRequester:     req,
+		kvBulkRequester: bulkReq,
 	}
 	ioll.mu.Mutex = &syncutil.Mutex{}
 	ioll.mu.kvGranter = kvGranter
+	ioll.mu.kvBulkGranter = kvGranter
 	for i := 0; i < 100; i++ {
 		randomValues()
 		ioll.pebbleMetricsTick(m)
@@ -463,8 +570,78 @@ func TestBadIOLoadListenerStats(t *testing.T) {
 			ioll.allocateTokensTick()
 			require.LessOrEqual(t, int64(0), ioll.totalTokens)
 			require.LessOrEqual(t, int64(0), ioll.tokensAllocated)
+			require.LessOrEqual(t, int64(0), ioll.totalBulkTokens)
+			require.LessOrEqual(t, int64(0), ioll.bulkTokensAllocated)
+		}
+	}
+}
+
+// TestIOLoadListenerBulkTokens tests the disk bandwidth tokens that the
+// ioLoadListener gives to KVBulkWork.
+func TestIOLoadListenerBulkTokens(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const provisionedBandwidth = 1000
+	st := cluster.MakeTestingClusterSettings()
+	ProvisionedBandwidth.Override(context.Background(), &st.SV, provisionedBandwidth)
+	BulkBandwidthUtilizationThreshold.Override(context.Background(), &st.SV, 0.5)
+	bulkReq := &testRequesterForIOLL{}
+	bulkGranter := &testGranterWithDiskBandwidthTokens{}
+	ioll := ioLoadListener{
+		settings:        st,
+		kvRequester:     &testRequesterForIOLL{},
+		kvBulkRequester: bulkReq,
+	}
+	ioll.mu.Mutex = &syncutil.Mutex{}
+	ioll.mu.kvGranter = &testGranterWithIOTokens{}
+	ioll.mu.kvBulkGranter = bulkGranter
+
+	// 500 bytes/s, i.e., half the provisioned bandwidth, can be written in an
+	// interval before bulk work is limited.
+	const allowedBytes = provisionedBandwidth / 2 * adjustmentInterval
+	var m pebble.Metrics
+	tick := func(walBytes, ingestedBytes uint64, bulkAdmitted uint64) {
+		m.WAL.BytesWritten += walBytes
+		m.Levels[6].BytesIngested += ingestedBytes
+		bulkReq.admittedCount += bulkAdmitted
+		ioll.pebbleMetricsTick(m)
+		bulkGranter.tokens = bulkGranter.tokens[:0]
+		for i := 0; i < adjustmentInterval; i++ {
+			ioll.allocateTokensTick()
 		}
 	}
+
+	// The first interval is unlimited.
+	tick(0, 0, 0)
+	require.Equal(t, int64(unlimitedTokens), ioll.totalBulkTokens)
+
+	// Under the threshold, bulk work is not limited.
+	tick(allowedBytes/2, allowedBytes/4, 15)
+	require.Equal(t, int64(unlimitedTokens), ioll.totalBulkTokens)
+	require.Equal(t, float64(125), ioll.smoothedBytesPerBulkWork)
+
+	// Over the threshold, bulk work gets the bandwidth left over by the
+	// remaining writes, which is a fifth of allowedBytes, i.e., 1500 bytes or
+	// 12 bulk work items of 125 bytes.
+	tick(allowedBytes*4/5, allowedBytes/2, 30)
+	require.Equal(t, float64(125), ioll.smoothedBytesPerBulkWork)
+	require.Equal(t, int64(12), ioll.totalBulkTokens)
+	var allocated int64
+	for _, tokens := range bulkGranter.tokens {
+		allocated += tokens
+	}
+	require.Equal(t, ioll.totalBulkTokens, allocated)
+
+	// The remaining writes use all the allowed bandwidth, so bulk work is not
+	// admitted.
+	tick(allowedBytes, 0, 0)
+	require.Equal(t, int64(0), ioll.totalBulkTokens)
+
+	// Bulk work is not limited when the provisioned bandwidth is not set.
+	ProvisionedBandwidth.Override(context.Background(), &st.SV, 0)
+	tick(allowedBytes, 0, 0)
+	require.Equal(t, int64(unlimitedTokens), ioll.totalBulkTokens)
 }
 
 // TODO(sumeer):
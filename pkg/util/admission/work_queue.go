@@ -54,10 +54,19 @@ var SQLSQLResponseAdmissionControlEnabled = settings.RegisterBoolSetting(
 		"to admission control",
 	false).WithPublic()
 
+// KVTenantWeightsEnabled controls whether tenant weights are used by the
+// WorkQueues for KV work, when sharing admission across tenants.
+var KVTenantWeightsEnabled = settings.RegisterBoolSetting(
+	"admission.kv.tenant_weights.enabled",
+	"when true, tenant weights are used to share KV admission, including admission to "+
+		"overloaded stores, across tenants",
+	false).WithPublic()
+
 var admissionControlEnabledSettings = [numWorkKinds]*settings.BoolSetting{
 	KVWork:             KVAdmissionControlEnabled,
 	SQLKVResponseWork:  SQLKVResponseAdmissionControlEnabled,
 	SQLSQLResponseWork: SQLSQLResponseAdmissionControlEnabled,
+	KVBulkWork:         KVAdmissionControlEnabled,
 }
 
 // WorkPriority represents the priority of work. In an WorkQueue, it is only
@@ -120,17 +129,17 @@ type WorkInfo struct {
 
 // WorkQueue maintains a queue of work waiting to be admitted. Ordering of
 // work is achieved via 2 heaps: a tenant heap orders the tenants with waiting
-// work in increasing order of used slots or tokens. Within each tenant, the
-// waiting work is ordered based on priority and create time. Tenants with
-// non-zero values of used slots or tokens are tracked even if they have no
-// more waiting work. Token usage is reset to zero every second. The choice of
-// 1 second of memory for token distribution fairness is somewhat arbitrary.
-// The same 1 second interval is also used to garbage collect tenants who have
-// no waiting requests and no used slots or tokens.
+// work in increasing order of used slots or tokens, divided by the tenant's
+// weight. Within each tenant, the waiting work is ordered based on priority
+// and create time. Tenants with non-zero values of used slots or tokens are
+// tracked even if they have no more waiting work. Token usage is reset to
+// zero every second. The choice of 1 second of memory for token distribution
+// fairness is somewhat arbitrary. The same 1 second interval is also used to
+// garbage collect tenants who have no waiting requests and no used slots or
+// tokens.
 //
-// Note that currently there are no weights associated with tenants -- one
-// could imagine using weights and comparing used/weight values for
-// inter-tenant fairness.
+// Tenant weights default to 1, i.e., tenants share admission equally, and can
+// be changed using SetTenantWeights.
 //
 // Usage example:
 //  var grantCoord *GrantCoordinator
@@ -164,6 +173,9 @@ type WorkQueue struct {
 		tenantHeap tenantHeap
 		// All tenants, including those without waiting work. Periodically cleaned.
 		tenants map[uint64]*tenantInfo
+		// The weights of tenants, set by SetTenantWeights. Tenants that are not
+		// in the map have a weight of 1.
+		tenantWeights map[uint64]uint32
 	}
 	metrics       WorkQueueMetrics
 	admittedCount uint64
@@ -182,7 +194,7 @@ type workQueueOptions struct {
 
 func makeWorkQueueOptions(workKind WorkKind) workQueueOptions {
 	switch workKind {
-	case KVWork:
+	case KVWork, KVBulkWork:
 		return workQueueOptions{usesTokens: false, tiedToRange: true}
 	case SQLKVResponseWork, SQLSQLResponseWork:
 		return workQueueOptions{usesTokens: true, tiedToRange: false}
@@ -250,10 +262,11 @@ func (q *WorkQueue) Admit(ctx context.Context, info WorkInfo) (enabled bool, err
 	q.mu.Lock()
 	tenant, ok := q.mu.tenants[tenantID]
 	if !ok {
-		tenant = newTenantInfo(tenantID)
+		tenant = newTenantInfo(tenantID, q.getTenantWeightLocked(tenantID))
 		q.mu.tenants[tenantID] = tenant
 	}
-	if info.BypassAdmission && roachpb.IsSystemTenantID(tenantID) && q.workKind == KVWork {
+	if info.BypassAdmission && roachpb.IsSystemTenantID(tenantID) &&
+		(q.workKind == KVWork || q.workKind == KVBulkWork) {
 		tenant.used++
 		if len(tenant.waitingWorkHeap) > 0 {
 			q.mu.tenantHeap.fix(tenant)
@@ -308,7 +321,7 @@ func (q *WorkQueue) Admit(ctx context.Context, info WorkInfo) (enabled bool, err
 			tenant.used--
 		} else {
 			if !ok {
-				tenant = newTenantInfo(tenantID)
+				tenant = newTenantInfo(tenantID, q.getTenantWeightLocked(tenantID))
 				q.mu.tenants[tenantID] = tenant
 			}
 			// Don't want to overflow tenant.used if it is already 0 because of
@@ -431,6 +444,35 @@ func (q *WorkQueue) AdmittedWorkDone(tenantID roachpb.TenantID) {
 	q.granter.returnGrant()
 }
 
+// SetTenantWeights sets the weights of tenants, which are used to share
+// admission across tenants in proportion to their weight. Tenants that are
+// not in tenantWeights, or have a zero weight, get a weight of 1. A nil map
+// resets all tenants to a weight of 1. The WorkQueue takes ownership of
+// tenantWeights, so the caller must not modify it after this call.
+func (q *WorkQueue) SetTenantWeights(tenantWeights map[uint64]uint32) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.mu.tenantWeights = tenantWeights
+	for id, tenant := range q.mu.tenants {
+		weight := q.getTenantWeightLocked(id)
+		if tenant.weight == weight {
+			continue
+		}
+		tenant.weight = weight
+		if tenant.heapIndex >= 0 {
+			q.mu.tenantHeap.fix(tenant)
+		}
+	}
+}
+
+func (q *WorkQueue) getTenantWeightLocked(tenantID uint64) uint32 {
+	weight, ok := q.mu.tenantWeights[tenantID]
+	if !ok || weight == 0 {
+		return 1
+	}
+	return weight
+}
+
 func (q *WorkQueue) getAdmittedCount() uint64 {
 	return atomic.LoadUint64(&q.admittedCount)
 }
@@ -502,6 +544,9 @@ func (q *WorkQueue) SafeFormat(s redact.SafePrinter, verb rune) {
 	for _, id := range ids {
 		tenant := q.mu.tenants[id]
 		s.Printf("\n tenant-id: %d used: %d", tenant.id, tenant.used)
+		if tenant.weight != 1 {
+			s.Printf(" weight: %d", tenant.weight)
+		}
 		if len(tenant.waitingWorkHeap) > 0 {
 			s.Printf(" heap:")
 			for i := range tenant.waitingWorkHeap {
@@ -545,7 +590,10 @@ type tenantInfo struct {
 	// or (b) do not do used-- for the tokens case if the request was canceled.
 	// This does imply some inaccuracy in token counting -- it can be fixed if
 	// needed.
-	used            uint64
+	used uint64
+	// weight is the weight of the tenant, which is at least 1. The tenant's
+	// share of admission is proportional to its weight.
+	weight          uint32
 	waitingWorkHeap waitingWorkHeap
 
 	// The heapIndex is maintained by the heap.Interface methods, and represents
@@ -554,7 +602,8 @@ type tenantInfo struct {
 }
 
 // tenantHeap is a heap of tenants with waiting work, ordered in increasing
-// order of tenantInfo.used. That is, we prefer tenants that are using less.
+// order of tenantInfo.used/tenantInfo.weight. That is, we prefer tenants
+// that are using less, relative to their weight.
 type tenantHeap []*tenantInfo
 
 var _ heap.Interface = (*tenantHeap)(nil)
@@ -565,10 +614,11 @@ var tenantInfoPool = sync.Pool{
 	},
 }
 
-func newTenantInfo(id uint64) *tenantInfo {
+func newTenantInfo(id uint64, weight uint32) *tenantInfo {
 	ti := tenantInfoPool.Get().(*tenantInfo)
 	*ti = tenantInfo{
 		id:              id,
+		weight:          weight,
 		waitingWorkHeap: ti.waitingWorkHeap,
 		heapIndex:       -1,
 	}
@@ -604,7 +654,9 @@ func (th *tenantHeap) Len() int {
 }
 
 func (th *tenantHeap) Less(i, j int) bool {
-	return (*th)[i].used < (*th)[j].used
+	// Compare used/weight by cross-multiplying, to avoid division. The weights
+	// are uint32, so this does not overflow for any realistic value of used.
+	return (*th)[i].used*uint64((*th)[j].weight) < (*th)[j].used*uint64((*th)[i].weight)
 }
 
 func (th *tenantHeap) Swap(i, j int) {
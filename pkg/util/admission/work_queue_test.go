@@ -13,6 +13,7 @@ package admission
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -128,6 +129,7 @@ set-try-get-return-value v=<bool>
 granted chain-id=<int>
 cancel-work id=<int>
 work-done id=<int>
+set-tenant-weights [weights=<tenant>:<weight>,...]
 print
 */
 func TestWorkQueueBasic(t *testing.T) {
@@ -142,6 +144,9 @@ func TestWorkQueueBasic(t *testing.T) {
 		func(t *testing.T, d *datadriven.TestData) string {
 			switch d.Cmd {
 			case "init":
+				if q != nil {
+					q.close()
+				}
 				tg = &testGranter{buf: &buf}
 				q = makeWorkQueue(KVWork, tg, nil, makeWorkQueueOptions(KVWork)).(*WorkQueue)
 				tg.r = q
@@ -226,6 +231,25 @@ func TestWorkQueueBasic(t *testing.T) {
 				wrkMap.delete(id)
 				return buf.stringAndReset()
 
+			case "set-tenant-weights":
+				var weights map[uint64]uint32
+				if d.HasArg("weights") {
+					var weightsStr string
+					d.ScanArgs(t, "weights", &weightsStr)
+					weights = make(map[uint64]uint32)
+					for _, tw := range strings.Split(weightsStr, ",") {
+						parts := strings.Split(tw, ":")
+						require.Equal(t, 2, len(parts))
+						tenant, err := strconv.Atoi(parts[0])
+						require.NoError(t, err)
+						weight, err := strconv.Atoi(parts[1])
+						require.NoError(t, err)
+						weights[uint64(tenant)] = uint32(weight)
+					}
+				}
+				q.SetTenantWeights(weights)
+				return ""
+
 			case "print":
 				return q.String()
 
@@ -0,0 +1,127 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package orc
+
+import (
+	"bytes"
+	"compress/flate"
+	"io/ioutil"
+
+	"github.com/cockroachdb/errors"
+	"github.com/golang/snappy"
+)
+
+// checkCompression returns an error if the compression of a file is not
+// supported.
+func checkCompression(kind uint64) error {
+	switch kind {
+	case compressionNone, compressionZlib, compressionSnappy:
+		return nil
+	case compressionLZO:
+		return errors.New("LZO compressed orc files are not supported")
+	case compressionLZ4:
+		return errors.New("LZ4 compressed orc files are not supported")
+	case compressionZstd:
+		return errors.New("ZSTD compressed orc files are not supported")
+	default:
+		return errors.Newf("unknown orc compression kind %d", kind)
+	}
+}
+
+// decompress returns the content of a compressed stream, or of compressed
+// metadata. Compressed data is a sequence of chunks, each preceded by a
+// 3-byte little-endian header holding the length of the chunk and a flag
+// which is set if the chunk is stored uncompressed.
+func decompress(kind uint64, data []byte) ([]byte, error) {
+	if kind == compressionNone {
+		return data, nil
+	}
+	out := make([]byte, 0, len(data))
+	for len(data) > 0 {
+		if len(data) < 3 {
+			return nil, errors.New("truncated compression chunk header")
+		}
+		header := uint32(data[0]) | uint32(data[1])<<8 | uint32(data[2])<<16
+		data = data[3:]
+		n := int(header >> 1)
+		if n > len(data) {
+			return nil, errors.Newf("compression chunk of %d bytes is truncated", n)
+		}
+		chunk := data[:n]
+		data = data[n:]
+		if header&1 == 1 {
+			out = append(out, chunk...)
+			continue
+		}
+		switch kind {
+		case compressionZlib:
+			// ZLIB chunks are raw deflate streams, without a zlib header.
+			b, err := ioutil.ReadAll(flate.NewReader(bytes.NewReader(chunk)))
+			if err != nil {
+				return nil, errors.Wrap(err, "inflating compression chunk")
+			}
+			out = append(out, b...)
+		case compressionSnappy:
+			b, err := snappy.Decode(nil, chunk)
+			if err != nil {
+				return nil, errors.Wrap(err, "decoding snappy compression chunk")
+			}
+			out = append(out, b...)
+		default:
+			return nil, checkCompression(kind)
+		}
+	}
+	return out, nil
+}
+
+// compress returns data compressed in chunks of at most chunkSize bytes, as
+// read by decompress. Chunks which do not shrink are stored uncompressed.
+func compress(kind uint64, data []byte, chunkSize int) ([]byte, error) {
+	if kind == compressionNone {
+		return data, nil
+	}
+	var out []byte
+	for len(data) > 0 {
+		n := len(data)
+		if n > chunkSize {
+			n = chunkSize
+		}
+		chunk := data[:n]
+		data = data[n:]
+		var b []byte
+		switch kind {
+		case compressionZlib:
+			var buf bytes.Buffer
+			fw, err := flate.NewWriter(&buf, flate.DefaultCompression)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := fw.Write(chunk); err != nil {
+				return nil, err
+			}
+			if err := fw.Close(); err != nil {
+				return nil, err
+			}
+			b = buf.Bytes()
+		case compressionSnappy:
+			b = snappy.Encode(nil, chunk)
+		default:
+			return nil, checkCompression(kind)
+		}
+		header := uint32(len(b)) << 1
+		if len(b) >= len(chunk) {
+			b, header = chunk, uint32(len(chunk))<<1|1
+		}
+		out = append(out, byte(header), byte(header>>8), byte(header>>16))
+		out = append(out, b...)
+	}
+	return out, nil
+}
@@ -0,0 +1,124 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package orc
+
+import (
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/cockroachdb/errors"
+)
+
+// TypeForColumn returns the SQL type of the values of a column, as returned
+// by ValueToDatum.
+func TypeForColumn(col *Column) *types.T {
+	switch col.Kind {
+	case Boolean:
+		return types.Bool
+	case Byte, Short:
+		return types.Int2
+	case Int:
+		return types.Int4
+	case Long:
+		return types.Int
+	case Float:
+		return types.Float4
+	case Double:
+		return types.Float
+	case String:
+		return types.String
+	case Varchar:
+		return types.MakeVarChar(col.MaxLength)
+	case Char:
+		return types.MakeChar(col.MaxLength)
+	case Decimal:
+		return types.MakeDecimal(col.Precision, col.Scale)
+	case Date:
+		return types.Date
+	case Timestamp:
+		return types.Timestamp
+	case TimestampInstant:
+		return types.TimestampTZ
+	case List:
+		return types.MakeArray(TypeForColumn(col.Elem))
+	default:
+		return types.Bytes
+	}
+}
+
+// ValueToDatum converts a value read from a column to a datum of the type
+// returned by TypeForColumn.
+func ValueToDatum(v interface{}, col *Column) (tree.Datum, error) {
+	if v == nil {
+		return tree.DNull, nil
+	}
+	if col.Kind != List {
+		return leafDatum(v, col)
+	}
+	elems, ok := v.([]interface{})
+	if !ok {
+		return nil, errors.AssertionFailedf("value of type %T is invalid for list column %q", v, col.Name)
+	}
+	arr := tree.NewDArray(TypeForColumn(col.Elem))
+	for _, e := range elems {
+		d, err := leafDatum(e, col.Elem)
+		if err != nil {
+			return nil, err
+		}
+		if err := arr.Append(d); err != nil {
+			return nil, err
+		}
+	}
+	return arr, nil
+}
+
+func leafDatum(v interface{}, col *Column) (tree.Datum, error) {
+	if v == nil {
+		return tree.DNull, nil
+	}
+	switch x := v.(type) {
+	case bool:
+		return tree.MakeDBool(tree.DBool(x)), nil
+	case int64:
+		if col.Kind == Date {
+			date, err := pgdate.MakeDateFromUnixEpoch(x)
+			if err != nil {
+				return nil, err
+			}
+			return tree.NewDDate(date), nil
+		}
+		return tree.NewDInt(tree.DInt(x)), nil
+	case float32:
+		return tree.NewDFloat(tree.DFloat(x)), nil
+	case float64:
+		return tree.NewDFloat(tree.DFloat(x)), nil
+	case []byte:
+		if col.Kind == Binary {
+			return tree.NewDBytes(tree.DBytes(x)), nil
+		}
+		return tree.NewDString(string(x)), nil
+	case DecimalValue:
+		d := &tree.DDecimal{}
+		d.Negative = x.Unscaled.Sign() < 0
+		d.Coeff.Abs(x.Unscaled)
+		d.Exponent = -x.Scale
+		return d, nil
+	case time.Time:
+		if col.Kind == TimestampInstant {
+			return tree.MakeDTimestampTZ(x, time.Microsecond)
+		}
+		return tree.MakeDTimestamp(x, time.Microsecond)
+	}
+	return nil, errors.AssertionFailedf("value of type %T is invalid for column %q of type %s",
+		v, col.Name, col.Kind)
+}
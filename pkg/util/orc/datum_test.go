@@ -0,0 +1,96 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package orc
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValueToDatum(t *testing.T) {
+	evalCtx := tree.NewTestingEvalContext(cluster.MakeTestingClusterSettings())
+	defer evalCtx.Stop(context.Background())
+	mustDecimal := func(s string) tree.Datum {
+		d, err := tree.ParseDDecimal(s)
+		require.NoError(t, err)
+		return d
+	}
+	ts := time.Date(2021, 6, 1, 12, 30, 15, 123456789, time.UTC)
+	intArray := tree.NewDArray(types.Int)
+	require.NoError(t, intArray.Append(tree.NewDInt(1)))
+	require.NoError(t, intArray.Append(tree.DNull))
+
+	for _, tc := range []struct {
+		col      Column
+		v        interface{}
+		expected tree.Datum
+		typ      *types.T
+	}{
+		{Column{Kind: Boolean}, true, tree.DBoolTrue, types.Bool},
+		{Column{Kind: Byte}, int64(-3), tree.NewDInt(-3), types.Int2},
+		{Column{Kind: Int}, int64(7), tree.NewDInt(7), types.Int4},
+		{Column{Kind: Long}, int64(1 << 40), tree.NewDInt(1 << 40), types.Int},
+		{Column{Kind: Float}, float32(1.5), tree.NewDFloat(1.5), types.Float4},
+		{Column{Kind: Double}, float64(-2.5), tree.NewDFloat(-2.5), types.Float},
+		{Column{Kind: String}, []byte("a"), tree.NewDString("a"), types.String},
+		{Column{Kind: Varchar, MaxLength: 3}, []byte("ab"), tree.NewDString("ab"), types.MakeVarChar(3)},
+		{Column{Kind: Binary}, []byte{0, 1}, tree.NewDBytes("\x00\x01"), types.Bytes},
+		{
+			Column{Kind: Decimal, Precision: 5, Scale: 2},
+			DecimalValue{Unscaled: big.NewInt(-1234), Scale: 2},
+			mustDecimal("-12.34"),
+			types.MakeDecimal(5, 2),
+		},
+		{
+			Column{Kind: Date},
+			int64(-1),
+			tree.NewDDate(pgdate.MakeCompatibleDateFromDisk(-1)),
+			types.Date,
+		},
+		{
+			Column{Kind: Timestamp},
+			ts,
+			tree.MustMakeDTimestamp(ts, time.Microsecond),
+			types.Timestamp,
+		},
+		{
+			Column{Kind: TimestampInstant},
+			ts,
+			tree.MustMakeDTimestampTZ(ts, time.Microsecond),
+			types.TimestampTZ,
+		},
+		{
+			Column{Kind: List, Elem: &Column{Kind: Long}},
+			[]interface{}{int64(1), nil},
+			intArray,
+			types.IntArray,
+		},
+		{Column{Kind: Long}, nil, tree.DNull, types.Int},
+	} {
+		t.Run(tc.expected.String(), func(t *testing.T) {
+			require.True(t, tc.typ.Identical(TypeForColumn(&tc.col)), "%s", TypeForColumn(&tc.col))
+			res, err := ValueToDatum(tc.v, &tc.col)
+			require.NoError(t, err)
+			require.Equal(t, 0, tc.expected.Compare(evalCtx, res), "%s != %s", tc.expected, res)
+		})
+	}
+
+	_, err := ValueToDatum(int64(1), &Column{Name: "c", Kind: List, Elem: &Column{Kind: Long}})
+	require.Error(t, err)
+}
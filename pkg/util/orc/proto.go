@@ -0,0 +1,335 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package orc
+
+import (
+	"encoding/binary"
+
+	"github.com/cockroachdb/errors"
+)
+
+// The metadata of ORC files is encoded with protocol buffers. Only the few
+// messages needed to read the rows of a file are decoded, by hand, so that
+// this package does not depend on generated code for the ORC schema.
+
+// Protocol buffer wire types.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// protoDecoder decodes the fields of a protocol buffer message.
+type protoDecoder struct {
+	buf []byte
+	pos int
+}
+
+// readMessage calls fn with the number and wire type of each field of the
+// message, until the end of the buffer. fn must consume the value of the
+// field, or skip it.
+func (d *protoDecoder) readMessage(fn func(field int, wire int) error) error {
+	for d.pos < len(d.buf) {
+		key, err := d.varint()
+		if err != nil {
+			return err
+		}
+		if err := fn(int(key>>3), int(key&7)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *protoDecoder) varint() (uint64, error) {
+	v, n := binary.Uvarint(d.buf[d.pos:])
+	if n <= 0 {
+		return 0, errors.New("invalid varint")
+	}
+	d.pos += n
+	return v, nil
+}
+
+// bytes returns the value of a length-delimited field.
+func (d *protoDecoder) bytes() ([]byte, error) {
+	n, err := d.varint()
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(len(d.buf)-d.pos) {
+		return nil, errors.New("invalid length")
+	}
+	b := d.buf[d.pos : d.pos+int(n)]
+	d.pos += int(n)
+	return b, nil
+}
+
+// message returns a decoder of the embedded message of the field.
+func (d *protoDecoder) message() (protoDecoder, error) {
+	b, err := d.bytes()
+	return protoDecoder{buf: b}, err
+}
+
+// uint32s appends the value of a repeated uint32 field to values. The values
+// are either packed in a single length-delimited field, or repeated fields.
+func (d *protoDecoder) uint32s(wire int, values []uint32) ([]uint32, error) {
+	if wire != wireBytes {
+		v, err := d.varint()
+		return append(values, uint32(v)), err
+	}
+	packed, err := d.message()
+	if err != nil {
+		return nil, err
+	}
+	for packed.pos < len(packed.buf) {
+		v, err := packed.varint()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, uint32(v))
+	}
+	return values, nil
+}
+
+// skip skips the value of a field of the given wire type.
+func (d *protoDecoder) skip(wire int) error {
+	n := 0
+	switch wire {
+	case wireVarint:
+		_, err := d.varint()
+		return err
+	case wireBytes:
+		_, err := d.bytes()
+		return err
+	case wireFixed64:
+		n = 8
+	case wireFixed32:
+		n = 4
+	default:
+		return errors.Newf("unsupported wire type %d", wire)
+	}
+	if n > len(d.buf)-d.pos {
+		return errors.New("unexpected end of message")
+	}
+	d.pos += n
+	return nil
+}
+
+// postScript is the decoded PostScript of a file, which is stored
+// uncompressed at the end of the file.
+type postScript struct {
+	footerLength    uint64
+	compression     uint64
+	compressionSize uint64
+	magic           string
+}
+
+func decodePostScript(b []byte) (postScript, error) {
+	var ps postScript
+	d := protoDecoder{buf: b}
+	err := d.readMessage(func(field int, wire int) error {
+		var err error
+		switch {
+		case field == 1 && wire == wireVarint:
+			ps.footerLength, err = d.varint()
+		case field == 2 && wire == wireVarint:
+			ps.compression, err = d.varint()
+		case field == 3 && wire == wireVarint:
+			ps.compressionSize, err = d.varint()
+		case field == 8000 && wire == wireBytes:
+			var magic []byte
+			magic, err = d.bytes()
+			ps.magic = string(magic)
+		default:
+			err = d.skip(wire)
+		}
+		return err
+	})
+	return ps, err
+}
+
+// stripeInfo is the decoded StripeInformation of a stripe, which locates the
+// stripe in the file.
+type stripeInfo struct {
+	offset       uint64
+	indexLength  uint64
+	dataLength   uint64
+	footerLength uint64
+	numRows      uint64
+}
+
+// orcType is the decoded Type of a node of the schema of a file. Nodes are
+// identified by their index in the schema, which is a pre-order traversal of
+// the type tree.
+type orcType struct {
+	kind       Kind
+	subtypes   []uint32
+	fieldNames []string
+	maxLength  uint32
+	precision  uint32
+	scale      uint32
+}
+
+// footer is the decoded Footer of a file.
+type footer struct {
+	stripes []stripeInfo
+	types   []orcType
+	numRows uint64
+}
+
+func decodeFooter(b []byte) (footer, error) {
+	var f footer
+	d := protoDecoder{buf: b}
+	err := d.readMessage(func(field int, wire int) error {
+		var err error
+		switch {
+		case field == 3 && wire == wireBytes:
+			var si stripeInfo
+			si, err = decodeStripeInfo(&d)
+			f.stripes = append(f.stripes, si)
+		case field == 4 && wire == wireBytes:
+			var t orcType
+			t, err = decodeType(&d)
+			f.types = append(f.types, t)
+		case field == 6 && wire == wireVarint:
+			f.numRows, err = d.varint()
+		default:
+			err = d.skip(wire)
+		}
+		return err
+	})
+	return f, err
+}
+
+func decodeStripeInfo(parent *protoDecoder) (stripeInfo, error) {
+	var si stripeInfo
+	d, err := parent.message()
+	if err != nil {
+		return si, err
+	}
+	err = d.readMessage(func(field int, wire int) error {
+		if wire != wireVarint {
+			return d.skip(wire)
+		}
+		v, err := d.varint()
+		switch field {
+		case 1:
+			si.offset = v
+		case 2:
+			si.indexLength = v
+		case 3:
+			si.dataLength = v
+		case 4:
+			si.footerLength = v
+		case 5:
+			si.numRows = v
+		}
+		return err
+	})
+	return si, err
+}
+
+func decodeType(parent *protoDecoder) (orcType, error) {
+	var t orcType
+	d, err := parent.message()
+	if err != nil {
+		return t, err
+	}
+	err = d.readMessage(func(field int, wire int) error {
+		var err error
+		var v uint64
+		switch {
+		case field == 1 && wire == wireVarint:
+			v, err = d.varint()
+			t.kind = Kind(v)
+		case field == 2:
+			t.subtypes, err = d.uint32s(wire, t.subtypes)
+		case field == 3 && wire == wireBytes:
+			var name []byte
+			name, err = d.bytes()
+			t.fieldNames = append(t.fieldNames, string(name))
+		case field == 4 && wire == wireVarint:
+			v, err = d.varint()
+			t.maxLength = uint32(v)
+		case field == 5 && wire == wireVarint:
+			v, err = d.varint()
+			t.precision = uint32(v)
+		case field == 6 && wire == wireVarint:
+			v, err = d.varint()
+			t.scale = uint32(v)
+		default:
+			err = d.skip(wire)
+		}
+		return err
+	})
+	return t, err
+}
+
+// stream is the decoded Stream of a stripe footer, which locates the data of
+// a column in the stripe.
+type stream struct {
+	kind   uint64
+	column uint64
+	length uint64
+}
+
+// columnEncoding is the decoded ColumnEncoding of a column in a stripe.
+type columnEncoding struct {
+	kind           uint64
+	dictionarySize uint64
+}
+
+// stripeFooter is the decoded StripeFooter of a stripe.
+type stripeFooter struct {
+	streams   []stream
+	encodings []columnEncoding
+}
+
+func decodeStripeFooter(b []byte) (stripeFooter, error) {
+	var sf stripeFooter
+	d := protoDecoder{buf: b}
+	err := d.readMessage(func(field int, wire int) error {
+		if wire != wireBytes || (field != 1 && field != 2) {
+			return d.skip(wire)
+		}
+		m, err := d.message()
+		if err != nil {
+			return err
+		}
+		var s stream
+		var e columnEncoding
+		if err := m.readMessage(func(field int, wire int) error {
+			if wire != wireVarint {
+				return m.skip(wire)
+			}
+			v, err := m.varint()
+			switch field {
+			case 1:
+				s.kind, e.kind = v, v
+			case 2:
+				s.column, e.dictionarySize = v, v
+			case 3:
+				s.length = v
+			}
+			return err
+		}); err != nil {
+			return err
+		}
+		if field == 1 {
+			sf.streams = append(sf.streams, s)
+		} else {
+			sf.encodings = append(sf.encodings, e)
+		}
+		return nil
+	})
+	return sf, err
+}
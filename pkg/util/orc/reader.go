@@ -0,0 +1,577 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package orc
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+// magic starts ORC files, and is repeated in their PostScript.
+const magic = "ORC"
+
+// timestampEpoch is the Unix time of 2015-01-01 00:00:00 UTC, from which the
+// seconds of timestamps are stored.
+const timestampEpoch = 1420070400
+
+// maxPostScriptLength is the largest length of a PostScript, which is stored
+// in the last byte of a file.
+const maxPostScriptLength = 255
+
+// Reader reads the rows of an ORC file. Only the metadata of the file is read
+// when the Reader is created; the stripes are read on demand. The methods of
+// a Reader can be called concurrently, so that the stripes of a file can be
+// read in parallel.
+//
+// Files can have columns of primitive types and lists of primitive types.
+// Columns of other nested types, such as maps and structs, are not supported.
+// Files can be uncompressed, or compressed with ZLIB or Snappy.
+type Reader struct {
+	r           io.ReaderAt
+	size        int64
+	compression uint64
+	cols        []Column
+	numTypes    int
+	numRows     int64
+	stripes     []stripeInfo
+}
+
+// NewReader returns a Reader of the ORC file of the given size held by r.
+func NewReader(r io.ReaderAt, size int64) (*Reader, error) {
+	if size < int64(len(magic)+1) {
+		return nil, errors.New("file is too small to be an orc file")
+	}
+	var header [len(magic)]byte
+	if err := readFullAt(r, header[:], 0); err != nil {
+		return nil, err
+	}
+	if string(header[:]) != magic {
+		return nil, errors.New("not an orc file")
+	}
+
+	// The file ends with the PostScript, followed by its length on a byte.
+	tailLen := int64(maxPostScriptLength + 1)
+	if tailLen > size {
+		tailLen = size
+	}
+	tail := make([]byte, tailLen)
+	if err := readFullAt(r, tail, size-tailLen); err != nil {
+		return nil, err
+	}
+	psLen := int64(tail[len(tail)-1])
+	if psLen+1 > tailLen {
+		return nil, errors.Newf("invalid orc postscript length %d", psLen)
+	}
+	ps, err := decodePostScript(tail[tailLen-1-psLen : tailLen-1])
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid orc postscript")
+	}
+	if ps.magic != magic {
+		return nil, errors.New("not an orc file")
+	}
+	if err := checkCompression(ps.compression); err != nil {
+		return nil, err
+	}
+	footerOffset := size - 1 - psLen - int64(ps.footerLength)
+	if ps.footerLength > uint64(size) || footerOffset < int64(len(magic)) {
+		return nil, errors.Newf("invalid orc footer length %d", ps.footerLength)
+	}
+	buf := make([]byte, ps.footerLength)
+	if err := readFullAt(r, buf, footerOffset); err != nil {
+		return nil, err
+	}
+
+	rd := &Reader{r: r, size: size, compression: ps.compression}
+	if err := rd.decodeFooter(buf); err != nil {
+		return nil, errors.Wrap(err, "invalid orc footer")
+	}
+	return rd, nil
+}
+
+// Columns returns the columns of the file.
+func (r *Reader) Columns() []Column {
+	return r.cols
+}
+
+// NumRows returns the number of rows of the file.
+func (r *Reader) NumRows() int64 {
+	return r.numRows
+}
+
+// NumStripes returns the number of stripes of the file.
+func (r *Reader) NumStripes() int {
+	return len(r.stripes)
+}
+
+// StripeNumRows returns the number of rows of the i-th stripe.
+func (r *Reader) StripeNumRows(i int) int64 {
+	return int64(r.stripes[i].numRows)
+}
+
+// ReadStripe reads the rows of the i-th stripe. Rows hold one value per
+// column, using the same representation as the values passed to
+// Writer.AddRow: bool for Boolean columns, int64 for integer and Date
+// columns, which hold days since the Unix epoch, float32 and float64 for
+// Float and Double columns, []byte for string and Binary columns,
+// DecimalValue for Decimal columns, time.Time in UTC for timestamp columns,
+// and []interface{} for List columns. The values of NULLs are nil.
+//
+// Timestamp columns hold wall clock times, which are returned as if they were
+// in UTC, while TimestampInstant columns hold instants.
+func (r *Reader) ReadStripe(i int) ([][]interface{}, error) {
+	si := &r.stripes[i]
+	length := si.indexLength + si.dataLength + si.footerLength
+	if si.offset > uint64(r.size) || length > uint64(r.size)-si.offset {
+		return nil, errors.Newf("invalid range of stripe %d", i)
+	}
+	if si.numRows > math.MaxInt32 {
+		return nil, errors.Newf("stripe %d has too many rows", i)
+	}
+	buf := make([]byte, length)
+	if err := readFullAt(r.r, buf, int64(si.offset)); err != nil {
+		return nil, err
+	}
+	s, err := r.newStripeReader(buf, si)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading stripe %d", i)
+	}
+
+	n := int(si.numRows)
+	values := make([]interface{}, n*len(r.cols))
+	rows := make([][]interface{}, n)
+	for j := range rows {
+		rows[j] = values[j*len(r.cols) : (j+1)*len(r.cols) : (j+1)*len(r.cols)]
+	}
+	for c := range r.cols {
+		col, err := s.readColumn(&r.cols[c], n)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading column %q of stripe %d", r.cols[c].Name, i)
+		}
+		for j, v := range col {
+			rows[j][c] = v
+		}
+	}
+	return rows, nil
+}
+
+// readFullAt reads len(buf) bytes from r at the given offset.
+func readFullAt(r io.ReaderAt, buf []byte, off int64) error {
+	n, err := r.ReadAt(buf, off)
+	if n == len(buf) {
+		return nil
+	}
+	if err == nil || err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// decodeFooter decodes the Footer of the file, and the schema it holds.
+func (r *Reader) decodeFooter(buf []byte) error {
+	buf, err := decompress(r.compression, buf)
+	if err != nil {
+		return err
+	}
+	f, err := decodeFooter(buf)
+	if err != nil {
+		return err
+	}
+	if f.numRows > math.MaxInt64 {
+		return errors.Newf("invalid number of rows %d", f.numRows)
+	}
+	r.numRows = int64(f.numRows)
+	r.stripes = f.stripes
+	r.numTypes = len(f.types)
+
+	if len(f.types) == 0 || f.types[0].kind != Struct {
+		return errors.New("the root of the schema is not a struct")
+	}
+	root := &f.types[0]
+	if len(root.fieldNames) != len(root.subtypes) {
+		return errors.New("the root of the schema has fields without names")
+	}
+	// child returns the type of a child of a node, whose id is greater than
+	// the id of its parent in the pre-order traversal of the schema.
+	child := func(parent uint32, id uint32) (*orcType, error) {
+		if id <= parent || int(id) >= len(f.types) {
+			return nil, errors.Newf("invalid type id %d", id)
+		}
+		return &f.types[id], nil
+	}
+	r.cols = make([]Column, len(root.subtypes))
+	for i, id := range root.subtypes {
+		t, err := child(0, id)
+		if err != nil {
+			return err
+		}
+		col := &r.cols[i]
+		*col = newColumn(root.fieldNames[i], id, t)
+		if t.kind == List && len(t.subtypes) == 1 {
+			elemType, err := child(id, t.subtypes[0])
+			if err != nil {
+				return err
+			}
+			if elemType.kind.isPrimitive() {
+				elem := newColumn("", t.subtypes[0], elemType)
+				col.Elem = &elem
+				continue
+			}
+		}
+		if !t.kind.isPrimitive() {
+			return errors.Newf("column %q has unsupported type %s", col.Name, t.kind)
+		}
+	}
+	return nil
+}
+
+func newColumn(name string, id uint32, t *orcType) Column {
+	return Column{
+		Name:      name,
+		Kind:      t.kind,
+		Precision: int32(t.precision),
+		Scale:     int32(t.scale),
+		MaxLength: int32(t.maxLength),
+		id:        id,
+	}
+}
+
+type streamKey struct {
+	column uint64
+	kind   uint64
+}
+
+// stripeReader reads the columns of a stripe.
+type stripeReader struct {
+	compression uint64
+	// streams holds the compressed content of the streams of the stripe.
+	streams   map[streamKey][]byte
+	encodings []columnEncoding
+}
+
+func (r *Reader) newStripeReader(buf []byte, si *stripeInfo) (*stripeReader, error) {
+	dataEnd := si.indexLength + si.dataLength
+	sfBuf, err := decompress(r.compression, buf[dataEnd:])
+	if err != nil {
+		return nil, err
+	}
+	sf, err := decodeStripeFooter(sfBuf)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid stripe footer")
+	}
+	if len(sf.encodings) < r.numTypes {
+		return nil, errors.Newf("stripe footer has %d column encodings, expected %d",
+			len(sf.encodings), r.numTypes)
+	}
+	s := &stripeReader{
+		compression: r.compression,
+		streams:     make(map[streamKey][]byte, len(sf.streams)),
+		encodings:   sf.encodings,
+	}
+	// Streams are stored one after the other, starting with the streams of
+	// the index.
+	var offset uint64
+	for _, st := range sf.streams {
+		if st.length > dataEnd-offset {
+			return nil, errors.Newf("stream of column %d overflows the stripe", st.column)
+		}
+		s.streams[streamKey{column: st.column, kind: st.kind}] = buf[offset : offset+st.length]
+		offset += st.length
+	}
+	return s, nil
+}
+
+// stream returns the decompressed content of a stream of a column.
+func (s *stripeReader) stream(col *Column, kind uint64) ([]byte, error) {
+	buf, ok := s.streams[streamKey{column: uint64(col.id), kind: kind}]
+	if !ok {
+		return nil, errors.Newf("missing stream of kind %d", kind)
+	}
+	return decompress(s.compression, buf)
+}
+
+// readColumn reads the n values of a column, including NULLs.
+func (s *stripeReader) readColumn(col *Column, n int) ([]interface{}, error) {
+	present := n
+	var isPresent []bool
+	if _, ok := s.streams[streamKey{column: uint64(col.id), kind: streamPresent}]; ok && n > 0 {
+		buf, err := s.stream(col, streamPresent)
+		if err != nil {
+			return nil, err
+		}
+		if isPresent, err = decodeBools(buf, n); err != nil {
+			return nil, err
+		}
+		present = 0
+		for _, p := range isPresent {
+			if p {
+				present++
+			}
+		}
+	}
+	values, err := s.readValues(col, present)
+	if err != nil || isPresent == nil {
+		return values, err
+	}
+	// Only the values of the rows which are not NULL are stored.
+	all := make([]interface{}, n)
+	j := 0
+	for i, p := range isPresent {
+		if p {
+			all[i] = values[j]
+			j++
+		}
+	}
+	return all, nil
+}
+
+// readValues reads the n values of a column which are not NULL.
+func (s *stripeReader) readValues(col *Column, n int) ([]interface{}, error) {
+	values := make([]interface{}, n)
+	if n == 0 {
+		return values, nil
+	}
+	enc := s.encodings[col.id]
+	v2 := enc.kind == encodingDirectV2 || enc.kind == encodingDictionaryV2
+	data, err := s.stream(col, streamData)
+	if err != nil && col.Kind != List {
+		return nil, err
+	}
+
+	switch col.Kind {
+	case Boolean:
+		bools, err := decodeBools(data, n)
+		if err != nil {
+			return nil, err
+		}
+		for i, v := range bools {
+			values[i] = v
+		}
+
+	case Byte:
+		bytes, err := decodeBytes(data, n)
+		if err != nil {
+			return nil, err
+		}
+		for i, v := range bytes {
+			values[i] = int64(int8(v))
+		}
+
+	case Short, Int, Long, Date:
+		ints, err := decodeInts(data, n, true /* signed */, v2)
+		if err != nil {
+			return nil, err
+		}
+		for i, v := range ints {
+			values[i] = v
+		}
+
+	case Float:
+		if len(data) < 4*n {
+			return nil, errors.New("unexpected end of stream")
+		}
+		for i := range values {
+			values[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[4*i:]))
+		}
+
+	case Double:
+		if len(data) < 8*n {
+			return nil, errors.New("unexpected end of stream")
+		}
+		for i := range values {
+			values[i] = math.Float64frombits(binary.LittleEndian.Uint64(data[8*i:]))
+		}
+
+	case String, Varchar, Char, Binary:
+		if enc.kind == encodingDictionary || enc.kind == encodingDictionaryV2 {
+			return s.readDictionary(col, n, data, enc, v2)
+		}
+		lengths, err := s.readLengths(col, n, v2)
+		if err != nil {
+			return nil, err
+		}
+		for i, l := range lengths {
+			if l > int64(len(data)) {
+				return nil, errors.New("unexpected end of stream")
+			}
+			values[i] = data[:l:l]
+			data = data[l:]
+		}
+
+	case Decimal:
+		scales, err := s.readSecondary(col, n, true /* signed */, v2)
+		if err != nil {
+			return nil, err
+		}
+		d := rleDecoder{buf: data}
+		for i := range values {
+			unscaled, err := d.bigVarint()
+			if err != nil {
+				return nil, err
+			}
+			values[i] = DecimalValue{Unscaled: unscaled, Scale: int32(scales[i])}
+		}
+
+	case Timestamp, TimestampInstant:
+		secs, err := decodeInts(data, n, true /* signed */, v2)
+		if err != nil {
+			return nil, err
+		}
+		nanos, err := s.readSecondary(col, n, false /* signed */, v2)
+		if err != nil {
+			return nil, err
+		}
+		for i := range values {
+			values[i] = decodeTimestamp(secs[i], uint64(nanos[i]))
+		}
+
+	case List:
+		lengths, err := s.readLengths(col, n, v2)
+		if err != nil {
+			return nil, err
+		}
+		var total int64
+		for _, l := range lengths {
+			total += l
+		}
+		if total > math.MaxInt32 {
+			return nil, errors.Newf("list column %q has too many elements", col.Name)
+		}
+		elems, err := s.readColumn(col.Elem, int(total))
+		if err != nil {
+			return nil, err
+		}
+		for i, l := range lengths {
+			values[i] = elems[:l:l]
+			elems = elems[l:]
+		}
+
+	default:
+		return nil, errors.AssertionFailedf("unsupported column kind %s", col.Kind)
+	}
+	return values, nil
+}
+
+// readLengths reads the LENGTH stream of the n values of a column.
+func (s *stripeReader) readLengths(col *Column, n int, v2 bool) ([]int64, error) {
+	buf, err := s.stream(col, streamLength)
+	if err != nil {
+		return nil, err
+	}
+	lengths, err := decodeInts(buf, n, false /* signed */, v2)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range lengths {
+		if l < 0 {
+			return nil, errors.Newf("invalid length %d", l)
+		}
+	}
+	return lengths, nil
+}
+
+// readSecondary reads the SECONDARY stream of the n values of a column.
+func (s *stripeReader) readSecondary(col *Column, n int, signed bool, v2 bool) ([]int64, error) {
+	buf, err := s.stream(col, streamSecondary)
+	if err != nil {
+		return nil, err
+	}
+	return decodeInts(buf, n, signed, v2)
+}
+
+// readDictionary reads the n values of a dictionary encoded string column,
+// whose DATA stream holds the indexes of the values in the dictionary.
+func (s *stripeReader) readDictionary(
+	col *Column, n int, data []byte, enc columnEncoding, v2 bool,
+) ([]interface{}, error) {
+	if enc.dictionarySize > math.MaxInt32 {
+		return nil, errors.Newf("invalid dictionary size %d", enc.dictionarySize)
+	}
+	size := int(enc.dictionarySize)
+	lengths, err := s.readLengths(col, size, v2)
+	if err != nil {
+		return nil, err
+	}
+	dictData, err := s.stream(col, streamDictionaryData)
+	if err != nil && size > 0 {
+		return nil, err
+	}
+	dict := make([][]byte, size)
+	for i, l := range lengths {
+		if l > int64(len(dictData)) {
+			return nil, errors.New("unexpected end of stream")
+		}
+		dict[i] = dictData[:l:l]
+		dictData = dictData[l:]
+	}
+	ids, err := decodeInts(data, n, false /* signed */, v2)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]interface{}, n)
+	for i, id := range ids {
+		if id < 0 || id >= int64(size) {
+			return nil, errors.Newf("invalid dictionary index %d", id)
+		}
+		values[i] = dict[id]
+	}
+	return values, nil
+}
+
+// bigVarint reads a zigzag encoded varint of arbitrary size.
+func (d *rleDecoder) bigVarint() (*big.Int, error) {
+	v := new(big.Int)
+	var chunk big.Int
+	for shift := uint(0); ; shift += 7 {
+		b, err := d.byte()
+		if err != nil {
+			return nil, err
+		}
+		chunk.SetUint64(uint64(b & 0x7f))
+		v.Or(v, chunk.Lsh(&chunk, shift))
+		if b&0x80 == 0 {
+			break
+		}
+	}
+	// Undo the zigzag encoding.
+	negative := v.Bit(0) == 1
+	v.Rsh(v, 1)
+	if negative {
+		v.Neg(v).Sub(v, big.NewInt(1))
+	}
+	return v, nil
+}
+
+// decodeTimestamp returns the time of the seconds since timestampEpoch and
+// of the encoded nanoseconds of a timestamp. The nanoseconds are stored with
+// their trailing zeros removed: if the 3 lowest bits hold z > 0, the value
+// was divided by 10^(z+1).
+func decodeTimestamp(secs int64, nanos uint64) time.Time {
+	if z := nanos & 7; z != 0 {
+		nanos >>= 3
+		for i := uint64(0); i <= z; i++ {
+			nanos *= 10
+		}
+	} else {
+		nanos >>= 3
+	}
+	secs += timestampEpoch
+	// Writers store the seconds of negative times rounded towards zero
+	// rather than down.
+	if secs < 0 && nanos > 999999 {
+		secs--
+	}
+	return time.Unix(secs, int64(nanos)).UTC()
+}
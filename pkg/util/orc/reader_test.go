@@ -0,0 +1,313 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package orc
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// withCompression makes a Writer compress files in chunks of the given size.
+func withCompression(kind uint64, chunkSize int) WriterOption {
+	return func(w *Writer) {
+		w.compression = kind
+		w.compressionChunkSize = chunkSize
+	}
+}
+
+func TestReaderRoundTrip(t *testing.T) {
+	cols := []Column{
+		{Name: "b", Kind: Boolean},
+		{Name: "y", Kind: Byte},
+		{Name: "i", Kind: Int},
+		{Name: "f", Kind: Float},
+		{Name: "d", Kind: Double},
+		{Name: "s", Kind: String},
+		{Name: "v", Kind: Varchar, MaxLength: 10},
+		{Name: "bin", Kind: Binary},
+		{Name: "dec", Kind: Decimal, Precision: 30, Scale: 2},
+		{Name: "date", Kind: Date},
+		{Name: "ts", Kind: Timestamp},
+		{Name: "tsi", Kind: TimestampInstant},
+		{Name: "l", Kind: List, Elem: &Column{Kind: Long}},
+	}
+	times := []time.Time{
+		time.Date(2021, 6, 1, 12, 30, 15, 123456789, time.UTC),
+		time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(1960, 3, 4, 5, 6, 7, 500, time.UTC),
+		time.Date(1969, 12, 31, 23, 59, 58, 500000000, time.UTC),
+		time.Date(1900, 1, 1, 0, 0, 0, 120000000, time.UTC),
+	}
+	decimal := new(big.Int).Lsh(big.NewInt(-3), 80)
+	var rows [][]interface{}
+	for i := 0; i < 25; i++ {
+		row := []interface{}{
+			i%2 == 0,
+			int64(i - 12),
+			int64(i * 1000),
+			float32(i) / 2,
+			float64(i) * -1.5,
+			[]byte(fmt.Sprintf("s%d", i)),
+			[]byte(""),
+			[]byte{byte(i), 0},
+			DecimalValue{Unscaled: new(big.Int).Add(decimal, big.NewInt(int64(i))), Scale: 2},
+			int64(i*400 - 5000),
+			times[i%len(times)],
+			times[(i+1)%len(times)],
+			[]interface{}{int64(i), nil, int64(-i)},
+		}
+		if i%5 == 0 {
+			row[12] = []interface{}{}
+		}
+		// Each column is NULL in some rows.
+		row[i%len(row)] = nil
+		rows = append(rows, row)
+	}
+
+	for _, tc := range []struct {
+		name string
+		opts []WriterOption
+	}{
+		{name: "uncompressed", opts: []WriterOption{WithMaxStripeRows(10)}},
+		{name: "zlib", opts: []WriterOption{WithMaxStripeRows(10), withCompression(compressionZlib, 16)}},
+		{name: "snappy", opts: []WriterOption{withCompression(compressionSnappy, 64)}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewWriter(&buf, cols, tc.opts...)
+			require.NoError(t, err)
+			for _, row := range rows {
+				require.NoError(t, w.AddRow(row))
+			}
+			require.NoError(t, w.Close())
+
+			r, err := NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+			require.NoError(t, err)
+			require.Equal(t, int64(len(rows)), r.NumRows())
+			require.Len(t, r.Columns(), len(cols))
+			for i := range cols {
+				require.Equal(t, cols[i].Name, r.Columns()[i].Name)
+				require.Equal(t, cols[i].Kind, r.Columns()[i].Kind)
+			}
+			require.Equal(t, int32(10), r.Columns()[6].MaxLength)
+			require.Equal(t, int32(30), r.Columns()[8].Precision)
+			require.Equal(t, Long, r.Columns()[12].Elem.Kind)
+
+			var read [][]interface{}
+			for i := 0; i < r.NumStripes(); i++ {
+				stripe, err := r.ReadStripe(i)
+				require.NoError(t, err)
+				require.Len(t, stripe, int(r.StripeNumRows(i)))
+				read = append(read, stripe...)
+			}
+			require.Len(t, read, len(rows))
+			for i := range rows {
+				for j := range rows[i] {
+					if dec, ok := rows[i][j].(DecimalValue); ok {
+						readDec, ok := read[i][j].(DecimalValue)
+						require.True(t, ok)
+						require.Zero(t, dec.Unscaled.Cmp(readDec.Unscaled), "row %d", i)
+						require.Equal(t, dec.Scale, readDec.Scale)
+						continue
+					}
+					require.Equal(t, rows[i][j], read[i][j], "row %d column %s", i, cols[j].Name)
+				}
+			}
+		})
+	}
+}
+
+// rawStream is a stream of a file written by writeRawFile.
+type rawStream struct {
+	kind   uint64
+	column uint64
+	data   []byte
+}
+
+// writeRawFile returns an uncompressed file of one stripe with the given
+// types, encodings and streams.
+func writeRawFile(numRows uint64, types [][]byte, encodings []columnEncoding, streams []rawStream) []byte {
+	buf := []byte(magic)
+	var sf protoEncoder
+	for _, s := range streams {
+		var e protoEncoder
+		e.varintField(1, s.kind)
+		e.varintField(2, s.column)
+		e.varintField(3, uint64(len(s.data)))
+		sf.bytesField(1, e.buf)
+		buf = append(buf, s.data...)
+	}
+	for _, enc := range encodings {
+		var e protoEncoder
+		e.varintField(1, enc.kind)
+		e.varintField(2, enc.dictionarySize)
+		sf.bytesField(2, e.buf)
+	}
+	dataLength := len(buf) - len(magic)
+	buf = append(buf, sf.buf...)
+
+	var si, footer protoEncoder
+	si.varintField(1, uint64(len(magic)))
+	si.varintField(3, uint64(dataLength))
+	si.varintField(4, uint64(len(sf.buf)))
+	si.varintField(5, numRows)
+	footer.bytesField(3, si.buf)
+	for _, typ := range types {
+		footer.bytesField(4, typ)
+	}
+	footer.varintField(6, numRows)
+	buf = append(buf, footer.buf...)
+
+	var ps protoEncoder
+	ps.varintField(1, uint64(len(footer.buf)))
+	ps.bytesField(8000, []byte(magic))
+	buf = append(buf, ps.buf...)
+	return append(buf, byte(len(ps.buf)))
+}
+
+// rawType returns an encoded Type.
+func rawType(kind Kind, subtypes []uint64, names ...string) []byte {
+	var e protoEncoder
+	e.varintField(1, uint64(kind))
+	for _, s := range subtypes {
+		e.varintField(2, s)
+	}
+	for _, n := range names {
+		e.bytesField(3, []byte(n))
+	}
+	return e.buf
+}
+
+func TestReaderEncodings(t *testing.T) {
+	types := [][]byte{
+		rawType(Struct, []uint64{1, 2}, "s", "l"),
+		rawType(String, nil),
+		rawType(Long, nil),
+	}
+	encodings := []columnEncoding{
+		{kind: encodingDirect},
+		{kind: encodingDictionaryV2, dictionarySize: 2},
+		{kind: encodingDirectV2},
+	}
+	streams := []rawStream{
+		// An index stream, which is skipped.
+		{kind: 6, column: 1, data: []byte{1, 2, 3}},
+		// The 4th row is NULL.
+		{kind: streamPresent, column: 1, data: encodeBools([]bool{true, true, true, false, true})},
+		// Dictionary indexes 1, 0, 1, 1 in a direct run of 1-bit values.
+		{kind: streamData, column: 1, data: []byte{0x40, 0x03, 0xb0}},
+		{kind: streamDictionaryData, column: 1, data: []byte("abcd")},
+		// Dictionary lengths 1 and 3 in a direct run of 2-bit values.
+		{kind: streamLength, column: 1, data: []byte{0x42, 0x01, 0x70}},
+		// A delta run of the primes from 2 to 11, with 4-bit deltas.
+		{kind: streamData, column: 2, data: []byte{0xc6, 0x04, 0x04, 0x02, 0x22, 0x40}},
+	}
+	buf := writeRawFile(5, types, encodings, streams)
+	r, err := NewReader(bytes.NewReader(buf), int64(len(buf)))
+	require.NoError(t, err)
+	rows, err := r.ReadStripe(0)
+	require.NoError(t, err)
+	require.Equal(t, [][]interface{}{
+		{[]byte("bcd"), int64(2)},
+		{[]byte("a"), int64(3)},
+		{[]byte("bcd"), int64(5)},
+		{nil, int64(7)},
+		{[]byte("bcd"), int64(11)},
+	}, rows)
+}
+
+func TestReaderErrors(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, []Column{{Name: "i", Kind: Long}})
+	require.NoError(t, err)
+	require.NoError(t, w.AddRow([]interface{}{int64(1)}))
+	require.NoError(t, w.Close())
+	valid := buf.Bytes()
+
+	for _, tc := range []struct {
+		name string
+		file []byte
+		err  string
+	}{
+		{name: "empty", file: nil, err: "file is too small"},
+		{name: "not-orc", file: []byte("this is not an orc file"), err: "not an orc file"},
+		{name: "truncated", file: valid[:len(valid)-1], err: "orc"},
+		{
+			name: "map",
+			file: writeRawFile(0, [][]byte{
+				rawType(Struct, []uint64{1}, "m"),
+				rawType(Map, []uint64{2, 3}),
+				rawType(String, nil),
+				rawType(Long, nil),
+			}, nil, nil),
+			err: `column "m" has unsupported type MAP`,
+		},
+		{
+			name: "list-of-lists",
+			file: writeRawFile(0, [][]byte{
+				rawType(Struct, []uint64{1}, "l"),
+				rawType(List, []uint64{2}),
+				rawType(List, []uint64{3}),
+				rawType(Long, nil),
+			}, nil, nil),
+			err: `column "l" has unsupported type LIST`,
+		},
+		{
+			name: "invalid-type-id",
+			file: writeRawFile(0, [][]byte{rawType(Struct, []uint64{1}, "i")}, nil, nil),
+			err:  "invalid type id 1",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewReader(bytes.NewReader(tc.file), int64(len(tc.file)))
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.err)
+		})
+	}
+
+	// Files compressed with unsupported codecs are rejected.
+	lz4 := append([]byte(nil), valid...)
+	psLen := int(lz4[len(lz4)-1])
+	ps := lz4[len(lz4)-1-psLen : len(lz4)-1]
+	i := bytes.Index(ps, []byte{2<<3 | wireVarint, compressionNone})
+	require.True(t, i >= 0)
+	ps[i+1] = compressionLZ4
+	_, err = NewReader(bytes.NewReader(lz4), int64(len(lz4)))
+	require.EqualError(t, err, "LZ4 compressed orc files are not supported")
+
+	// Stripes with missing streams are rejected.
+	file := writeRawFile(1, [][]byte{
+		rawType(Struct, []uint64{1}, "i"),
+		rawType(Long, nil),
+	}, []columnEncoding{{}, {}}, nil)
+	r, err := NewReader(bytes.NewReader(file), int64(len(file)))
+	require.NoError(t, err)
+	_, err = r.ReadStripe(0)
+	require.EqualError(t, err, `reading column "i" of stripe 0: missing stream of kind 1`)
+}
+
+func TestWriterErrors(t *testing.T) {
+	_, err := NewWriter(&bytes.Buffer{}, []Column{{Name: "m", Kind: Map}})
+	require.EqualError(t, err, `column "m" has unsupported type MAP`)
+	_, err = NewWriter(&bytes.Buffer{}, []Column{{Name: "l", Kind: List}})
+	require.EqualError(t, err, `list column "l" must have primitive elements`)
+
+	w, err := NewWriter(&bytes.Buffer{}, []Column{{Name: "i", Kind: Long}})
+	require.NoError(t, err)
+	require.EqualError(t, w.AddRow([]interface{}{int32(1)}),
+		`value of type int32 is invalid for column "i" of type LONG`)
+	require.EqualError(t, w.AddRow(nil), "row has 0 values, expected 1")
+}
@@ -0,0 +1,391 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package orc
+
+import (
+	"encoding/binary"
+
+	"github.com/cockroachdb/errors"
+)
+
+// rleDecoder decodes the run length encodings of the streams of a column.
+// See https://orc.apache.org/specification/ORCv1/ for the details of the
+// encodings.
+type rleDecoder struct {
+	buf []byte
+	pos int
+}
+
+func (d *rleDecoder) byte() (byte, error) {
+	if d.pos >= len(d.buf) {
+		return 0, errors.New("unexpected end of stream")
+	}
+	b := d.buf[d.pos]
+	d.pos++
+	return b, nil
+}
+
+func (d *rleDecoder) uvarint() (uint64, error) {
+	v, n := binary.Uvarint(d.buf[d.pos:])
+	if n <= 0 {
+		return 0, errors.New("invalid varint")
+	}
+	d.pos += n
+	return v, nil
+}
+
+func (d *rleDecoder) varint() (int64, error) {
+	v, err := d.uvarint()
+	return unzigzag(v), err
+}
+
+func unzigzag(v uint64) int64 {
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+// bigEndian reads an unsigned big-endian integer of n bytes.
+func (d *rleDecoder) bigEndian(n int) (uint64, error) {
+	if n > len(d.buf)-d.pos {
+		return 0, errors.New("unexpected end of stream")
+	}
+	var v uint64
+	for _, b := range d.buf[d.pos : d.pos+n] {
+		v = v<<8 | uint64(b)
+	}
+	d.pos += n
+	return v, nil
+}
+
+// unpack appends n integers of the given bit width, packed from the most
+// significant bit of each byte. The bits left in the last byte are skipped.
+func (d *rleDecoder) unpack(values []uint64, n int, width int) ([]uint64, error) {
+	if width < 1 || width > 64 {
+		return nil, errors.Newf("invalid bit width %d", width)
+	}
+	if (n*width+7)/8 > len(d.buf)-d.pos {
+		return nil, errors.New("unexpected end of stream")
+	}
+	var cur byte
+	bitsLeft := 0
+	for i := 0; i < n; i++ {
+		var v uint64
+		for need := width; need > 0; {
+			if bitsLeft == 0 {
+				cur = d.buf[d.pos]
+				d.pos++
+				bitsLeft = 8
+			}
+			take := need
+			if take > bitsLeft {
+				take = bitsLeft
+			}
+			v = v<<uint(take) | uint64(cur>>uint(bitsLeft-take))&(1<<uint(take)-1)
+			bitsLeft -= take
+			need -= take
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// decodeBytes decodes n bytes encoded with the byte run length encoding.
+func decodeBytes(buf []byte, n int) ([]byte, error) {
+	d := rleDecoder{buf: buf}
+	values := make([]byte, 0, n)
+	for len(values) < n {
+		header, err := d.byte()
+		if err != nil {
+			return nil, err
+		}
+		if header < 0x80 {
+			// A run of header+3 repetitions of the next byte.
+			b, err := d.byte()
+			if err != nil {
+				return nil, err
+			}
+			for i := 0; i < int(header)+3; i++ {
+				values = append(values, b)
+			}
+			continue
+		}
+		// A literal list of 256-header bytes.
+		count := 0x100 - int(header)
+		if count > len(d.buf)-d.pos {
+			return nil, errors.New("unexpected end of stream")
+		}
+		values = append(values, d.buf[d.pos:d.pos+count]...)
+		d.pos += count
+	}
+	return values[:n], nil
+}
+
+// decodeBools decodes n booleans encoded as the bits of bytes, from the most
+// significant bit, with the byte run length encoding.
+func decodeBools(buf []byte, n int) ([]bool, error) {
+	bytes, err := decodeBytes(buf, (n+7)/8)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]bool, n)
+	for i := range values {
+		values[i] = bytes[i/8]&(0x80>>uint(i%8)) != 0
+	}
+	return values, nil
+}
+
+// decodeInts decodes n integers encoded with the version 1 or 2 of the
+// integer run length encoding. Unsigned integers are returned as int64.
+func decodeInts(buf []byte, n int, signed bool, v2 bool) ([]int64, error) {
+	d := rleDecoder{buf: buf}
+	values := make([]int64, 0, n)
+	var err error
+	for len(values) < n && err == nil {
+		if v2 {
+			values, err = d.intRunV2(values, signed)
+		} else {
+			values, err = d.intRunV1(values, signed)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return values[:n], nil
+}
+
+// intRunV1 appends the values of a run of the version 1 of the integer run
+// length encoding.
+func (d *rleDecoder) intRunV1(values []int64, signed bool) ([]int64, error) {
+	header, err := d.byte()
+	if err != nil {
+		return nil, err
+	}
+	readVarint := d.varint
+	if !signed {
+		readVarint = func() (int64, error) {
+			v, err := d.uvarint()
+			return int64(v), err
+		}
+	}
+	if header < 0x80 {
+		// A run of header+3 values, starting at a base value and separated by
+		// a signed delta which fits in a byte.
+		delta, err := d.byte()
+		if err != nil {
+			return nil, err
+		}
+		base, err := readVarint()
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < int(header)+3; i++ {
+			values = append(values, base+int64(i)*int64(int8(delta)))
+		}
+		return values, nil
+	}
+	// A literal list of 256-header varints.
+	for i := 0; i < 0x100-int(header); i++ {
+		v, err := readVarint()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// Sub-encodings of the version 2 of the integer run length encoding, stored
+// in the two most significant bits of the header of a run.
+const (
+	rleShortRepeat = 0
+	rleDirect      = 1
+	rlePatchedBase = 2
+	rleDelta       = 3
+)
+
+// decodeBitWidth returns the bit width encoded on 5 bits in the headers of
+// the runs of the version 2 of the integer run length encoding.
+func decodeBitWidth(code byte) int {
+	switch {
+	case code < 24:
+		return int(code) + 1
+	case code < 28:
+		// 26, 28, 30 and 32.
+		return 26 + 2*int(code-24)
+	default:
+		// 40, 48, 56 and 64.
+		return 40 + 8*int(code-28)
+	}
+}
+
+// closestFixedBits returns the smallest bit width which can be encoded in a
+// header and is greater or equal to n.
+func closestFixedBits(n int) int {
+	switch {
+	case n <= 1:
+		return 1
+	case n <= 24:
+		return n
+	case n <= 32:
+		return n + n%2
+	default:
+		return (n + 7) / 8 * 8
+	}
+}
+
+// intRunV2 appends the values of a run of the version 2 of the integer run
+// length encoding.
+func (d *rleDecoder) intRunV2(values []int64, signed bool) ([]int64, error) {
+	header, err := d.byte()
+	if err != nil {
+		return nil, err
+	}
+	sign := func(v uint64) int64 {
+		if signed {
+			return unzigzag(v)
+		}
+		return int64(v)
+	}
+	switch header >> 6 {
+	case rleShortRepeat:
+		// A run of 3 to 10 repetitions of a value stored in 1 to 8 bytes.
+		width := int(header>>3&7) + 1
+		count := int(header&7) + 3
+		v, err := d.bigEndian(width)
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < count; i++ {
+			values = append(values, sign(v))
+		}
+		return values, nil
+	}
+
+	// The other sub-encodings store the length of the run, minus 1, on 9
+	// bits.
+	next, err := d.byte()
+	if err != nil {
+		return nil, err
+	}
+	count := (int(header&1)<<8 | int(next)) + 1
+	widthCode := header >> 1 & 0x1f
+	var unpacked []uint64
+
+	switch header >> 6 {
+	case rleDirect:
+		// A list of bit packed values.
+		if unpacked, err = d.unpack(unpacked, count, decodeBitWidth(widthCode)); err != nil {
+			return nil, err
+		}
+		for _, v := range unpacked {
+			values = append(values, sign(v))
+		}
+		return values, nil
+
+	case rlePatchedBase:
+		// A list of bit packed offsets from a base value, some of which have
+		// their most significant bits stored separately, as patches.
+		width := decodeBitWidth(widthCode)
+		b2, err := d.byte()
+		if err != nil {
+			return nil, err
+		}
+		b3, err := d.byte()
+		if err != nil {
+			return nil, err
+		}
+		baseWidth := int(b2>>5) + 1
+		patchWidth := decodeBitWidth(b2 & 0x1f)
+		gapWidth := int(b3>>5) + 1
+		numPatches := int(b3 & 0x1f)
+		if width+patchWidth > 64 || gapWidth+patchWidth > 64 {
+			return nil, errors.Newf("invalid patch width %d", patchWidth)
+		}
+		// The base value is stored in sign-magnitude form.
+		v, err := d.bigEndian(baseWidth)
+		if err != nil {
+			return nil, err
+		}
+		base := int64(v)
+		if signBit := uint64(1) << uint(baseWidth*8-1); v&signBit != 0 {
+			base = -int64(v &^ signBit)
+		}
+		if unpacked, err = d.unpack(unpacked, count, width); err != nil {
+			return nil, err
+		}
+		patches, err := d.unpack(nil, numPatches, closestFixedBits(gapWidth+patchWidth))
+		if err != nil {
+			return nil, err
+		}
+		// Each patch holds the gap from the previous patched value, and the
+		// patch. Gaps larger than 255 are stored as a series of patches with a
+		// gap of 255 and an empty patch.
+		idx := 0
+		patchMask := uint64(1)<<uint(patchWidth) - 1
+		for _, p := range patches {
+			idx += int(p >> uint(patchWidth))
+			if patch := p & patchMask; patch != 0 {
+				if idx >= count {
+					return nil, errors.New("invalid patch position")
+				}
+				unpacked[idx] |= patch << uint(width)
+			}
+		}
+		for _, v := range unpacked {
+			values = append(values, base+int64(v))
+		}
+		return values, nil
+
+	default:
+		// A sequence starting at a base value, followed by either a fixed delta
+		// or by a first delta and bit packed deltas whose sign is the sign of
+		// the first delta.
+		var base int64
+		if signed {
+			base, err = d.varint()
+		} else {
+			var v uint64
+			v, err = d.uvarint()
+			base = int64(v)
+		}
+		if err != nil {
+			return nil, err
+		}
+		delta, err := d.varint()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, base)
+		if widthCode == 0 {
+			for i := 1; i < count; i++ {
+				base += delta
+				values = append(values, base)
+			}
+			return values, nil
+		}
+		if count == 1 {
+			return nil, errors.New("invalid delta run length")
+		}
+		base += delta
+		values = append(values, base)
+		if unpacked, err = d.unpack(unpacked, count-2, decodeBitWidth(widthCode)); err != nil {
+			return nil, err
+		}
+		for _, v := range unpacked {
+			if delta < 0 {
+				base -= int64(v)
+			} else {
+				base += int64(v)
+			}
+			values = append(values, base)
+		}
+		return values, nil
+	}
+}
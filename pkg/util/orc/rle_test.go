@@ -0,0 +1,175 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package orc
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeBytes(t *testing.T) {
+	// Examples from the specification of the format.
+	values, err := decodeBytes([]byte{0x61, 0x00}, 100)
+	require.NoError(t, err)
+	require.Equal(t, make([]byte, 100), values)
+
+	values, err = decodeBytes([]byte{0xfe, 0x44, 0x45}, 2)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x44, 0x45}, values)
+
+	bools, err := decodeBools([]byte{0xff, 0x80}, 1)
+	require.NoError(t, err)
+	require.Equal(t, []bool{true}, bools)
+
+	bools, err = decodeBools(encodeBools([]bool{true, false, false, true, true, false, true, false, true}), 9)
+	require.NoError(t, err)
+	require.Equal(t, []bool{true, false, false, true, true, false, true, false, true}, bools)
+
+	_, err = decodeBytes([]byte{0xfe, 0x44}, 2)
+	require.Error(t, err)
+	_, err = decodeBytes([]byte{0x61}, 100)
+	require.Error(t, err)
+}
+
+func TestDecodeIntsV1(t *testing.T) {
+	// Examples from the specification of the format.
+	for _, tc := range []struct {
+		buf      []byte
+		expected []int64
+	}{
+		{buf: []byte{0x61, 0x00, 0x07}, expected: repeat(7, 100)},
+		{buf: []byte{0x61, 0xff, 0x64}, expected: sequence(100, -1, 100)},
+		{buf: []byte{0xfb, 0x02, 0x03, 0x06, 0x07, 0x0b}, expected: []int64{2, 3, 6, 7, 11}},
+	} {
+		values, err := decodeInts(tc.buf, len(tc.expected), false /* signed */, false /* v2 */)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, values)
+	}
+
+	values, err := decodeInts([]byte{0xfd, 0x01, 0x02, 0x03}, 3, true /* signed */, false /* v2 */)
+	require.NoError(t, err)
+	require.Equal(t, []int64{-1, 1, -2}, values)
+
+	_, err = decodeInts([]byte{0xfb, 0x02, 0x03}, 5, false /* signed */, false /* v2 */)
+	require.Error(t, err)
+}
+
+func TestDecodeIntsV2(t *testing.T) {
+	// Examples from the specification of the format, and a run with a fixed
+	// delta.
+	for _, tc := range []struct {
+		name     string
+		buf      []byte
+		expected []int64
+	}{
+		{
+			name:     "short-repeat",
+			buf:      []byte{0x0a, 0x27, 0x10},
+			expected: repeat(10000, 5),
+		},
+		{
+			name:     "direct",
+			buf:      []byte{0x5e, 0x03, 0x5c, 0xa1, 0xab, 0x1e, 0xde, 0xad, 0xbe, 0xef},
+			expected: []int64{23713, 43806, 57005, 48879},
+		},
+		{
+			name: "patched-base",
+			buf: []byte{
+				0x8e, 0x13, 0x2b, 0x21, 0x07, 0xd0, 0x1e, 0x00, 0x14, 0x70, 0x28, 0x32, 0x3c, 0x46,
+				0x50, 0x5a, 0x64, 0x6e, 0x78, 0x82, 0x8c, 0x96, 0xa0, 0xaa, 0xb4, 0xbe, 0xfc, 0xe8,
+			},
+			expected: []int64{
+				2030, 2000, 2020, 1000000, 2040, 2050, 2060, 2070, 2080, 2090,
+				2100, 2110, 2120, 2130, 2140, 2150, 2160, 2170, 2180, 2190,
+			},
+		},
+		{
+			name:     "delta",
+			buf:      []byte{0xc6, 0x09, 0x02, 0x02, 0x22, 0x42, 0x42, 0x46},
+			expected: []int64{2, 3, 5, 7, 11, 13, 17, 19, 23, 29},
+		},
+		{
+			name:     "fixed-delta",
+			buf:      []byte{0xc0, 0x04, 0x0a, 0x05},
+			expected: sequence(10, -3, 5),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			values, err := decodeInts(tc.buf, len(tc.expected), false /* signed */, true /* v2 */)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, values)
+
+			for i := 1; i < len(tc.buf); i++ {
+				_, err := decodeInts(tc.buf[:i], len(tc.expected), false /* signed */, true /* v2 */)
+				require.Error(t, err)
+			}
+		})
+	}
+
+	// Signed values are zigzag encoded, except in patched base runs.
+	values, err := decodeInts([]byte{0x0a, 0x27, 0x11}, 5, true /* signed */, true /* v2 */)
+	require.NoError(t, err)
+	require.Equal(t, repeat(-5001, 5), values)
+
+	values, err = decodeInts([]byte{0x4e, 0x02, 0x03, 0x04, 0x05}, 3, true /* signed */, true /* v2 */)
+	require.NoError(t, err)
+	require.Equal(t, []int64{-2, 2, -3}, values)
+
+	values, err = decodeInts([]byte{0xc6, 0x02, 0x07, 0x03, 0x10}, 3, true /* signed */, true /* v2 */)
+	require.NoError(t, err)
+	require.Equal(t, []int64{-4, -6, -7}, values)
+}
+
+func TestEncodeInts(t *testing.T) {
+	values := []int64{0, 1, -1, math.MaxInt64, math.MinInt64, 1 << 40}
+	for i := 0; i < 300; i++ {
+		values = append(values, int64(i*i)-5000)
+	}
+	decoded, err := decodeInts(encodeInts(values, true /* signed */), len(values), true /* signed */, false /* v2 */)
+	require.NoError(t, err)
+	require.Equal(t, values, decoded)
+
+	lengths := []int64{0, 1, 127, 128, math.MaxInt64}
+	decoded, err = decodeInts(encodeInts(lengths, false /* signed */), len(lengths), false /* signed */, false /* v2 */)
+	require.NoError(t, err)
+	require.Equal(t, lengths, decoded)
+}
+
+func TestBitWidths(t *testing.T) {
+	var widths []int
+	for code := byte(0); code < 32; code++ {
+		w := decodeBitWidth(code)
+		require.Equal(t, w, closestFixedBits(w))
+		widths = append(widths, w)
+	}
+	require.Equal(t, []int{26, 28, 30, 32, 40, 48, 56, 64}, widths[24:])
+	require.Equal(t, 26, closestFixedBits(25))
+	require.Equal(t, 40, closestFixedBits(33))
+	require.Equal(t, 64, closestFixedBits(57))
+}
+
+func repeat(v int64, n int) []int64 {
+	values := make([]int64, n)
+	for i := range values {
+		values[i] = v
+	}
+	return values
+}
+
+func sequence(start, delta int64, n int) []int64 {
+	values := make([]int64, n)
+	for i := range values {
+		values[i] = start + int64(i)*delta
+	}
+	return values
+}
@@ -0,0 +1,132 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package orc
+
+import "math/big"
+
+// Kind is the type of the values of a column of an ORC file. The values match
+// the Type.Kind enum of the ORC format.
+type Kind int32
+
+// Kinds of the ORC format.
+const (
+	Boolean          Kind = 0
+	Byte             Kind = 1
+	Short            Kind = 2
+	Int              Kind = 3
+	Long             Kind = 4
+	Float            Kind = 5
+	Double           Kind = 6
+	String           Kind = 7
+	Binary           Kind = 8
+	Timestamp        Kind = 9
+	List             Kind = 10
+	Map              Kind = 11
+	Struct           Kind = 12
+	Union            Kind = 13
+	Decimal          Kind = 14
+	Date             Kind = 15
+	Varchar          Kind = 16
+	Char             Kind = 17
+	TimestampInstant Kind = 18
+)
+
+var kindNames = map[Kind]string{
+	Boolean:          "BOOLEAN",
+	Byte:             "BYTE",
+	Short:            "SHORT",
+	Int:              "INT",
+	Long:             "LONG",
+	Float:            "FLOAT",
+	Double:           "DOUBLE",
+	String:           "STRING",
+	Binary:           "BINARY",
+	Timestamp:        "TIMESTAMP",
+	List:             "LIST",
+	Map:              "MAP",
+	Struct:           "STRUCT",
+	Union:            "UNION",
+	Decimal:          "DECIMAL",
+	Date:             "DATE",
+	Varchar:          "VARCHAR",
+	Char:             "CHAR",
+	TimestampInstant: "TIMESTAMP_INSTANT",
+}
+
+func (k Kind) String() string {
+	if s, ok := kindNames[k]; ok {
+		return s
+	}
+	return "UNKNOWN"
+}
+
+// isPrimitive returns whether the kind is not a compound type.
+func (k Kind) isPrimitive() bool {
+	switch k {
+	case List, Map, Struct, Union:
+		return false
+	}
+	_, ok := kindNames[k]
+	return ok
+}
+
+// Column describes a column of an ORC file, which is a field of the struct
+// at the root of the schema of the file. All columns are nullable.
+type Column struct {
+	Name string
+	Kind Kind
+	// Precision and Scale are set for Decimal columns.
+	Precision int32
+	Scale     int32
+	// MaxLength is set for Varchar and Char columns.
+	MaxLength int32
+	// Elem describes the elements of List columns. Elements cannot be lists.
+	Elem *Column
+
+	// id is the index of the type of the column in the schema, which
+	// identifies its streams in the stripes.
+	id uint32
+}
+
+// DecimalValue is the value of a Decimal column: Unscaled * 10^-Scale.
+type DecimalValue struct {
+	Unscaled *big.Int
+	Scale    int32
+}
+
+// Values of the CompressionKind enum of the ORC format.
+const (
+	compressionNone   = 0
+	compressionZlib   = 1
+	compressionSnappy = 2
+	compressionLZO    = 3
+	compressionLZ4    = 4
+	compressionZstd   = 5
+)
+
+// Values of the Stream.Kind enum of the ORC format. The other kinds of
+// streams hold indexes, which are not used.
+const (
+	streamPresent        = 0
+	streamData           = 1
+	streamLength         = 2
+	streamDictionaryData = 3
+	streamSecondary      = 5
+)
+
+// Values of the ColumnEncoding.Kind enum of the ORC format. The V2 encodings
+// use the version 2 of the run length encoding of integers.
+const (
+	encodingDirect       = 0
+	encodingDictionary   = 1
+	encodingDirectV2     = 2
+	encodingDictionaryV2 = 3
+)
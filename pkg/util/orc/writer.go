@@ -0,0 +1,498 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package orc
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+// defaultMaxStripeRows is the default number of rows of the stripes written
+// by a Writer.
+const defaultMaxStripeRows = 10000
+
+// Writer writes rows to an uncompressed ORC file. Rows are buffered in memory
+// until a stripe is complete. All the columns use the direct encoding and the
+// version 1 of the run length encoding, which all readers support. The file
+// is complete once Close is called.
+type Writer struct {
+	w             io.Writer
+	cols          []Column
+	numTypes      int
+	maxStripeRows int
+	offset        uint64
+	rows          [][]interface{}
+	stripes       []stripeInfo
+	numRows       uint64
+	closed        bool
+	// compression and compressionChunkSize are only set by tests, which
+	// write compressed files to test their decompression.
+	compression          uint64
+	compressionChunkSize int
+}
+
+// WriterOption configures a Writer.
+type WriterOption func(*Writer)
+
+// WithMaxStripeRows sets the number of rows after which a stripe is written.
+func WithMaxStripeRows(n int) WriterOption {
+	return func(w *Writer) {
+		w.maxStripeRows = n
+	}
+}
+
+// NewWriter returns a Writer of an ORC file with the given columns to w.
+func NewWriter(w io.Writer, cols []Column, opts ...WriterOption) (*Writer, error) {
+	wr := &Writer{w: w, cols: make([]Column, len(cols)), maxStripeRows: defaultMaxStripeRows}
+	for _, opt := range opts {
+		opt(wr)
+	}
+	if wr.maxStripeRows <= 0 {
+		return nil, errors.Newf("invalid maximum number of stripe rows %d", wr.maxStripeRows)
+	}
+	// Columns are numbered in the pre-order traversal of the schema, whose
+	// root is a struct.
+	id := uint32(1)
+	for i := range cols {
+		col := cols[i]
+		if col.Kind == List {
+			if col.Elem == nil || !col.Elem.Kind.isPrimitive() {
+				return nil, errors.Newf("list column %q must have primitive elements", col.Name)
+			}
+			elem := *col.Elem
+			elem.id = id + 1
+			col.Elem = &elem
+		} else if !col.Kind.isPrimitive() {
+			return nil, errors.Newf("column %q has unsupported type %s", col.Name, col.Kind)
+		} else {
+			col.Elem = nil
+		}
+		col.id = id
+		id++
+		if col.Elem != nil {
+			id++
+		}
+		wr.cols[i] = col
+	}
+	wr.numTypes = int(id)
+	if _, err := io.WriteString(w, magic); err != nil {
+		return nil, err
+	}
+	wr.offset = uint64(len(magic))
+	return wr, nil
+}
+
+// AddRow adds a row to the file. The row holds one value per column, whose
+// type depends on the kind of the column, as described in Reader.ReadStripe.
+// NULLs are nil.
+func (w *Writer) AddRow(row []interface{}) error {
+	if w.closed {
+		return errors.New("writer is closed")
+	}
+	if len(row) != len(w.cols) {
+		return errors.Newf("row has %d values, expected %d", len(row), len(w.cols))
+	}
+	for i := range row {
+		if err := checkValue(row[i], &w.cols[i]); err != nil {
+			return err
+		}
+	}
+	w.rows = append(w.rows, append([]interface{}(nil), row...))
+	if len(w.rows) >= w.maxStripeRows {
+		return w.flushStripe()
+	}
+	return nil
+}
+
+// checkValue returns an error if a value cannot be stored in a column.
+func checkValue(v interface{}, col *Column) error {
+	if v == nil {
+		return nil
+	}
+	ok := false
+	switch col.Kind {
+	case Boolean:
+		_, ok = v.(bool)
+	case Byte, Short, Int, Long, Date:
+		_, ok = v.(int64)
+	case Float:
+		_, ok = v.(float32)
+	case Double:
+		_, ok = v.(float64)
+	case String, Varchar, Char, Binary:
+		_, ok = v.([]byte)
+	case Decimal:
+		var d DecimalValue
+		d, ok = v.(DecimalValue)
+		ok = ok && d.Unscaled != nil
+	case Timestamp, TimestampInstant:
+		_, ok = v.(time.Time)
+	case List:
+		var elems []interface{}
+		if elems, ok = v.([]interface{}); ok {
+			for _, e := range elems {
+				if err := checkValue(e, col.Elem); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if !ok {
+		return errors.Newf("value of type %T is invalid for column %q of type %s", v, col.Name, col.Kind)
+	}
+	return nil
+}
+
+// Close writes the buffered rows and the metadata of the file. It does not
+// close the underlying io.Writer.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	if len(w.rows) > 0 {
+		if err := w.flushStripe(); err != nil {
+			return err
+		}
+	}
+	w.closed = true
+
+	var footer protoEncoder
+	footer.varintField(1, uint64(len(magic)))
+	footer.varintField(2, w.offset)
+	for _, si := range w.stripes {
+		var e protoEncoder
+		e.varintField(1, si.offset)
+		e.varintField(2, si.indexLength)
+		e.varintField(3, si.dataLength)
+		e.varintField(4, si.footerLength)
+		e.varintField(5, si.numRows)
+		footer.bytesField(3, e.buf)
+	}
+	var root protoEncoder
+	root.varintField(1, uint64(Struct))
+	for i := range w.cols {
+		root.varintField(2, uint64(w.cols[i].id))
+	}
+	for i := range w.cols {
+		root.bytesField(3, []byte(w.cols[i].Name))
+	}
+	footer.bytesField(4, root.buf)
+	for i := range w.cols {
+		footer.bytesField(4, encodeType(&w.cols[i]))
+		if elem := w.cols[i].Elem; elem != nil {
+			footer.bytesField(4, encodeType(elem))
+		}
+	}
+	footer.varintField(6, w.numRows)
+	footerBuf, err := w.compress(footer.buf)
+	if err != nil {
+		return err
+	}
+
+	var ps protoEncoder
+	ps.varintField(1, uint64(len(footerBuf)))
+	ps.varintField(2, w.compression)
+	if w.compression != compressionNone {
+		ps.varintField(3, uint64(w.compressionChunkSize))
+	}
+	// The file follows the version 0.12 of the format.
+	ps.varintField(4, 0)
+	ps.varintField(4, 12)
+	ps.bytesField(8000, []byte(magic))
+
+	buf := append(footerBuf, ps.buf...)
+	buf = append(buf, byte(len(ps.buf)))
+	_, err = w.w.Write(buf)
+	return err
+}
+
+// compress returns the content of a stream or of metadata, compressed with
+// the compression of the file.
+func (w *Writer) compress(data []byte) ([]byte, error) {
+	return compress(w.compression, data, w.compressionChunkSize)
+}
+
+func encodeType(col *Column) []byte {
+	var e protoEncoder
+	e.varintField(1, uint64(col.Kind))
+	if col.Elem != nil {
+		e.varintField(2, uint64(col.Elem.id))
+	}
+	if col.MaxLength > 0 {
+		e.varintField(4, uint64(col.MaxLength))
+	}
+	if col.Kind == Decimal {
+		e.varintField(5, uint64(col.Precision))
+		e.varintField(6, uint64(col.Scale))
+	}
+	return e.buf
+}
+
+// columnStreams holds the streams of a column of a stripe.
+type columnStreams struct {
+	present   []bool
+	data      []byte
+	length    []int64
+	secondary []int64
+	// signed is set if the secondary stream holds signed integers.
+	signed bool
+}
+
+func (w *Writer) flushStripe() error {
+	streams := make([]columnStreams, w.numTypes)
+	for i := range w.cols {
+		col := &w.cols[i]
+		values := make([]interface{}, len(w.rows))
+		for j := range w.rows {
+			values[j] = w.rows[j][i]
+		}
+		addValues(streams, col, values)
+	}
+
+	var data []byte
+	var sf protoEncoder
+	addStream := func(kind uint64, id int, buf []byte) error {
+		buf, err := w.compress(buf)
+		if err != nil {
+			return err
+		}
+		var e protoEncoder
+		e.varintField(1, kind)
+		e.varintField(2, uint64(id))
+		e.varintField(3, uint64(len(buf)))
+		sf.bytesField(1, e.buf)
+		data = append(data, buf...)
+		return nil
+	}
+	for id := 1; id < len(streams); id++ {
+		s := &streams[id]
+		if err := addStream(streamPresent, id, encodeBools(s.present)); err != nil {
+			return err
+		}
+		if err := addStream(streamData, id, s.data); err != nil {
+			return err
+		}
+		if s.length != nil {
+			if err := addStream(streamLength, id, encodeInts(s.length, false /* signed */)); err != nil {
+				return err
+			}
+		}
+		if s.secondary != nil {
+			if err := addStream(streamSecondary, id, encodeInts(s.secondary, s.signed)); err != nil {
+				return err
+			}
+		}
+	}
+	for id := 0; id < len(streams); id++ {
+		var e protoEncoder
+		e.varintField(1, encodingDirect)
+		sf.bytesField(2, e.buf)
+	}
+	sfBuf, err := w.compress(sf.buf)
+	if err != nil {
+		return err
+	}
+
+	si := stripeInfo{
+		offset:       w.offset,
+		dataLength:   uint64(len(data)),
+		footerLength: uint64(len(sfBuf)),
+		numRows:      uint64(len(w.rows)),
+	}
+	if _, err := w.w.Write(append(data, sfBuf...)); err != nil {
+		return err
+	}
+	w.stripes = append(w.stripes, si)
+	w.offset += si.dataLength + si.footerLength
+	w.numRows += si.numRows
+	w.rows = w.rows[:0]
+	return nil
+}
+
+// addValues adds the values of a column to its streams.
+func addValues(streams []columnStreams, col *Column, values []interface{}) {
+	s := &streams[col.id]
+	var bools []bool
+	var ints []int64
+	var elems []interface{}
+	for _, v := range values {
+		s.present = append(s.present, v != nil)
+		switch x := v.(type) {
+		case bool:
+			bools = append(bools, x)
+		case int64:
+			if col.Kind == Byte {
+				s.data = append(s.data, byte(x))
+			} else {
+				ints = append(ints, x)
+			}
+		case float32:
+			s.data = appendUint32(s.data, math.Float32bits(x))
+		case float64:
+			s.data = appendUint64(s.data, math.Float64bits(x))
+		case []byte:
+			s.data = append(s.data, x...)
+			s.length = append(s.length, int64(len(x)))
+		case DecimalValue:
+			s.data = appendBigVarint(s.data, x.Unscaled)
+			s.secondary = append(s.secondary, int64(x.Scale))
+			s.signed = true
+		case time.Time:
+			secs, nanos := encodeTimestamp(x)
+			ints = append(ints, secs)
+			s.secondary = append(s.secondary, nanos)
+		case []interface{}:
+			s.length = append(s.length, int64(len(x)))
+			elems = append(elems, x...)
+		}
+	}
+	switch col.Kind {
+	case Boolean:
+		s.data = encodeBools(bools)
+	case Byte:
+		s.data = encodeBytes(s.data)
+	case Short, Int, Long, Date, Timestamp, TimestampInstant:
+		s.data = encodeInts(ints, true /* signed */)
+	case List:
+		addValues(streams, col.Elem, elems)
+	}
+}
+
+// encodeTimestamp returns the seconds since timestampEpoch and the encoded
+// nanoseconds of a time. It is the inverse of decodeTimestamp.
+func encodeTimestamp(t time.Time) (secs int64, nanos int64) {
+	// Writers round the milliseconds of times down, and then round their
+	// seconds towards zero.
+	ns := int64(t.Nanosecond())
+	millis := t.Unix()*1000 + ns/int64(time.Millisecond)
+	secs = millis/1000 - timestampEpoch
+	if ns == 0 {
+		return secs, 0
+	}
+	if ns%100 != 0 {
+		return secs, ns << 3
+	}
+	ns /= 100
+	z := int64(1)
+	for ns%10 == 0 && z < 7 {
+		ns /= 10
+		z++
+	}
+	return secs, ns<<3 | z
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+// appendBigVarint appends the zigzag encoded varint of an integer of
+// arbitrary size.
+func appendBigVarint(b []byte, x *big.Int) []byte {
+	v := new(big.Int).Lsh(x, 1)
+	if x.Sign() < 0 {
+		v.Neg(v).Sub(v, big.NewInt(1))
+	}
+	var chunk big.Int
+	mask := big.NewInt(0x7f)
+	for {
+		c := byte(chunk.And(v, mask).Uint64())
+		v.Rsh(v, 7)
+		if v.Sign() == 0 {
+			return append(b, c)
+		}
+		b = append(b, c|0x80)
+	}
+}
+
+// encodeBytes encodes bytes with the byte run length encoding, as literal
+// lists of up to 128 bytes.
+func encodeBytes(values []byte) []byte {
+	var b []byte
+	for len(values) > 0 {
+		n := len(values)
+		if n > 128 {
+			n = 128
+		}
+		b = append(b, byte(0x100-n))
+		b = append(b, values[:n]...)
+		values = values[n:]
+	}
+	return b
+}
+
+// encodeBools encodes booleans as the bits of bytes, from the most
+// significant bit, with the byte run length encoding.
+func encodeBools(values []bool) []byte {
+	bytes := make([]byte, (len(values)+7)/8)
+	for i, v := range values {
+		if v {
+			bytes[i/8] |= 0x80 >> uint(i%8)
+		}
+	}
+	return encodeBytes(bytes)
+}
+
+// encodeInts encodes integers with the version 1 of the integer run length
+// encoding, as literal lists of up to 128 varints.
+func encodeInts(values []int64, signed bool) []byte {
+	var b []byte
+	var buf [binary.MaxVarintLen64]byte
+	for len(values) > 0 {
+		n := len(values)
+		if n > 128 {
+			n = 128
+		}
+		b = append(b, byte(0x100-n))
+		for _, v := range values[:n] {
+			u := uint64(v)
+			if signed {
+				u = uint64(v<<1) ^ uint64(v>>63)
+			}
+			b = append(b, buf[:binary.PutUvarint(buf[:], u)]...)
+		}
+		values = values[n:]
+	}
+	return b
+}
+
+// protoEncoder encodes the fields of a protocol buffer message.
+type protoEncoder struct {
+	buf []byte
+}
+
+func (e *protoEncoder) varint(v uint64) {
+	var buf [binary.MaxVarintLen64]byte
+	e.buf = append(e.buf, buf[:binary.PutUvarint(buf[:], v)]...)
+}
+
+func (e *protoEncoder) varintField(field int, v uint64) {
+	e.varint(uint64(field)<<3 | wireVarint)
+	e.varint(v)
+}
+
+func (e *protoEncoder) bytesField(field int, b []byte) {
+	e.varint(uint64(field)<<3 | wireBytes)
+	e.varint(uint64(len(b)))
+	e.buf = append(e.buf, b...)
+}
@@ -0,0 +1,478 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package parquet
+
+import (
+	"encoding/binary"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/cockroachdb/apd/v2"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+)
+
+// NewColumn returns the column storing the values of a SQL type. Arrays are
+// stored as lists of their elements. Types without a corresponding Parquet
+// type are stored as strings, using the same representation as the CSV
+// format of EXPORT.
+func NewColumn(name string, typ *types.T) Column {
+	if typ.Family() == types.ArrayFamily {
+		col := NewColumn(name, typ.ArrayContents())
+		if !col.List {
+			col.List = true
+			return col
+		}
+		// Nested arrays are stored as strings.
+		return Column{Name: name, Type: ByteArray, Logical: LogicalType{Kind: LogicalString}}
+	}
+	col := Column{Name: name}
+	switch typ.Family() {
+	case types.BoolFamily:
+		col.Type = Boolean
+	case types.IntFamily:
+		col.Type = Int64
+		if typ.Width() == 16 || typ.Width() == 32 {
+			col.Type = Int32
+		}
+		col.Logical = LogicalType{Kind: LogicalInt, BitWidth: int8(typ.Width())}
+		if typ.Width() == 0 {
+			col.Logical.BitWidth = 64
+		}
+	case types.FloatFamily:
+		col.Type = Double
+		if typ.Width() == 32 {
+			col.Type = Float
+		}
+	case types.DecimalFamily:
+		col.Type = ByteArray
+		col.Logical = LogicalType{Kind: LogicalString}
+		// Decimals without a precision have an arbitrary scale, so they are
+		// stored as strings.
+		if typ.Precision() > 0 {
+			col.Logical = LogicalType{
+				Kind:      LogicalDecimal,
+				Precision: typ.Precision(),
+				Scale:     typ.Scale(),
+			}
+		}
+	case types.BytesFamily:
+		col.Type = ByteArray
+	case types.DateFamily:
+		col.Type = Int32
+		col.Logical = LogicalType{Kind: LogicalDate}
+	case types.TimeFamily:
+		col.Type = Int64
+		col.Logical = LogicalType{Kind: LogicalTime}
+	case types.TimestampFamily:
+		col.Type = Int64
+		col.Logical = LogicalType{Kind: LogicalTimestamp}
+	case types.TimestampTZFamily:
+		col.Type = Int64
+		col.Logical = LogicalType{Kind: LogicalTimestamp, AdjustedToUTC: true}
+	case types.UuidFamily:
+		col.Type = FixedLenByteArray
+		col.TypeLength = 16
+		col.Logical = LogicalType{Kind: LogicalUUID}
+	case types.JsonFamily:
+		col.Type = ByteArray
+		col.Logical = LogicalType{Kind: LogicalJSON}
+	case types.EnumFamily:
+		col.Type = ByteArray
+		col.Logical = LogicalType{Kind: LogicalEnum}
+	default:
+		col.Type = ByteArray
+		col.Logical = LogicalType{Kind: LogicalString}
+	}
+	return col
+}
+
+// NewColumns returns the columns storing the values of the given SQL types.
+func NewColumns(names []string, typs []*types.T) []Column {
+	cols := make([]Column, len(typs))
+	for i := range typs {
+		cols[i] = NewColumn(names[i], typs[i])
+	}
+	return cols
+}
+
+// DatumToValue converts a datum to the value stored in the column returned
+// by NewColumn for its type, suitable for Writer.AddRow.
+func DatumToValue(d tree.Datum, typ *types.T) (interface{}, error) {
+	if d == tree.DNull {
+		return nil, nil
+	}
+	d = tree.UnwrapDatum(nil /* evalCtx */, d)
+	col := NewColumn("", typ)
+	if col.List {
+		arr, ok := d.(*tree.DArray)
+		if !ok {
+			return nil, errors.AssertionFailedf("unexpected datum %T for type %s", d, typ)
+		}
+		elems := make([]interface{}, len(arr.Array))
+		for i, elem := range arr.Array {
+			v, err := leafValue(elem, typ.ArrayContents(), &col)
+			if err != nil {
+				return nil, err
+			}
+			elems[i] = v
+		}
+		return elems, nil
+	}
+	return leafValue(d, typ, &col)
+}
+
+func leafValue(d tree.Datum, typ *types.T, col *Column) (interface{}, error) {
+	if d == tree.DNull {
+		return nil, nil
+	}
+	d = tree.UnwrapDatum(nil /* evalCtx */, d)
+	switch col.Logical.Kind {
+	case LogicalString:
+		if s, ok := d.(*tree.DString); ok {
+			return []byte(*s), nil
+		}
+		if s, ok := d.(*tree.DCollatedString); ok {
+			return []byte(s.Contents), nil
+		}
+		return []byte(tree.AsStringWithFlags(d, tree.FmtExport)), nil
+	case LogicalDecimal:
+		dec, ok := d.(*tree.DDecimal)
+		if !ok {
+			break
+		}
+		return decimalToBytes(&dec.Decimal, col.Logical.Scale)
+	}
+
+	switch t := d.(type) {
+	case *tree.DBool:
+		return bool(*t), nil
+	case *tree.DInt:
+		if col.Type == Int32 {
+			return int32(*t), nil
+		}
+		return int64(*t), nil
+	case *tree.DFloat:
+		if col.Type == Float {
+			return float32(*t), nil
+		}
+		return float64(*t), nil
+	case *tree.DBytes:
+		return []byte(*t), nil
+	case *tree.DDate:
+		days := t.UnixEpochDays()
+		// Infinite dates are stored as the extreme values of the type.
+		if days < math.MinInt32 {
+			days = math.MinInt32
+		} else if days > math.MaxInt32 {
+			days = math.MaxInt32
+		}
+		return int32(days), nil
+	case *tree.DTime:
+		return int64(*t), nil
+	case *tree.DTimestamp:
+		return timeutil.ToUnixMicros(t.Time), nil
+	case *tree.DTimestampTZ:
+		return timeutil.ToUnixMicros(t.Time), nil
+	case *tree.DUuid:
+		return t.UUID.GetBytes(), nil
+	case *tree.DJSON:
+		return []byte(t.JSON.String()), nil
+	case *tree.DEnum:
+		return []byte(t.LogicalRep), nil
+	}
+	return nil, errors.AssertionFailedf("unexpected datum %T for type %s", d, typ)
+}
+
+// decimalToBytes returns the big-endian two's complement representation of
+// the unscaled value of a decimal with the given scale.
+func decimalToBytes(d *apd.Decimal, scale int32) ([]byte, error) {
+	if d.Form != apd.Finite {
+		return nil, errors.Newf("%s cannot be stored as a Parquet decimal", d)
+	}
+	var q apd.Decimal
+	if _, err := tree.ExactCtx.Quantize(&q, d, -scale); err != nil {
+		return nil, err
+	}
+	// The number of bytes is large enough to hold the sign bit.
+	n := q.Coeff.BitLen()/8 + 1
+	x := &q.Coeff
+	if q.Negative && q.Coeff.Sign() != 0 {
+		x = new(big.Int).Lsh(big.NewInt(1), uint(8*n))
+		x.Sub(x, &q.Coeff)
+	}
+	return x.FillBytes(make([]byte, n)), nil
+}
+
+// TypeForColumn returns the SQL type of the values of a column, as returned
+// by ValueToDatum. It is the inverse of NewColumn for the types that have a
+// corresponding Parquet type. Struct columns have labeled tuple types.
+func TypeForColumn(col *Column) *types.T {
+	if col.Fields != nil {
+		contents := make([]*types.T, len(col.Fields))
+		labels := make([]string, len(col.Fields))
+		for i := range col.Fields {
+			contents[i] = TypeForColumn(&col.Fields[i])
+			labels[i] = col.Fields[i].Name
+		}
+		return types.MakeLabeledTuple(contents, labels)
+	}
+	if col.List {
+		elem := *col
+		elem.List = false
+		return types.MakeArray(TypeForColumn(&elem))
+	}
+	l := &col.Logical
+	switch l.Kind {
+	case LogicalString, LogicalEnum:
+		return types.String
+	case LogicalDecimal:
+		return types.MakeDecimal(l.Precision, l.Scale)
+	case LogicalDate:
+		return types.Date
+	case LogicalTime:
+		return types.Time
+	case LogicalTimestamp:
+		if l.AdjustedToUTC {
+			return types.TimestampTZ
+		}
+		return types.Timestamp
+	case LogicalInt:
+		switch {
+		case l.BitWidth <= 16 && !(l.Unsigned && l.BitWidth == 16):
+			return types.Int2
+		case l.BitWidth <= 32 && !(l.Unsigned && l.BitWidth == 32):
+			return types.Int4
+		case l.BitWidth <= 64 && !(l.Unsigned && l.BitWidth == 64):
+			return types.Int
+		}
+		// Unsigned 64-bit integers do not fit in any integer type.
+		return types.MakeDecimal(20, 0)
+	case LogicalJSON:
+		return types.Jsonb
+	case LogicalUUID:
+		return types.Uuid
+	}
+	switch col.Type {
+	case Boolean:
+		return types.Bool
+	case Int32:
+		return types.Int4
+	case Int64:
+		return types.Int
+	case Int96:
+		return types.Timestamp
+	case Float:
+		return types.Float4
+	case Double:
+		return types.Float
+	default:
+		return types.Bytes
+	}
+}
+
+// ValueToDatum converts a value read from a column to a datum of the type
+// returned by TypeForColumn.
+func ValueToDatum(v interface{}, col *Column) (tree.Datum, error) {
+	if v == nil {
+		return tree.DNull, nil
+	}
+	if col.Fields != nil {
+		fields, ok := v.([]interface{})
+		if !ok || len(fields) != len(col.Fields) {
+			return nil, errors.AssertionFailedf("value of type %T is invalid for struct column %q", v, col.Name)
+		}
+		datums := make(tree.Datums, len(fields))
+		for i := range fields {
+			d, err := ValueToDatum(fields[i], &col.Fields[i])
+			if err != nil {
+				return nil, err
+			}
+			datums[i] = d
+		}
+		return tree.NewDTuple(TypeForColumn(col), datums...), nil
+	}
+	if !col.List {
+		return leafDatum(v, col)
+	}
+	elems, ok := v.([]interface{})
+	if !ok {
+		return nil, errors.AssertionFailedf("value of type %T is invalid for list column %q", v, col.Name)
+	}
+	elem := *col
+	elem.List = false
+	arr := tree.NewDArray(TypeForColumn(&elem))
+	for _, e := range elems {
+		d, err := leafDatum(e, &elem)
+		if err != nil {
+			return nil, err
+		}
+		if err := arr.Append(d); err != nil {
+			return nil, err
+		}
+	}
+	return arr, nil
+}
+
+// julianDayOfUnixEpoch is the Julian day number of the Unix epoch, used by
+// Int96 timestamps.
+const julianDayOfUnixEpoch = 2440588
+
+func leafDatum(v interface{}, col *Column) (tree.Datum, error) {
+	if v == nil {
+		return tree.DNull, nil
+	}
+	l := &col.Logical
+	switch l.Kind {
+	case LogicalString, LogicalEnum:
+		if b, ok := v.([]byte); ok {
+			return tree.NewDString(string(b)), nil
+		}
+	case LogicalJSON:
+		if b, ok := v.([]byte); ok {
+			return tree.ParseDJSON(string(b))
+		}
+	case LogicalUUID:
+		if b, ok := v.([]byte); ok {
+			u, err := uuid.FromBytes(b)
+			if err != nil {
+				return nil, err
+			}
+			return tree.NewDUuid(tree.DUuid{UUID: u}), nil
+		}
+	case LogicalDecimal:
+		var coeff big.Int
+		switch x := v.(type) {
+		case int32:
+			coeff.SetInt64(int64(x))
+		case int64:
+			coeff.SetInt64(x)
+		case []byte:
+			bytesToBigInt(&coeff, x)
+		default:
+			return nil, errors.AssertionFailedf("value of type %T is invalid for decimal column %q", v, col.Name)
+		}
+		d := &tree.DDecimal{}
+		d.Negative = coeff.Sign() < 0
+		d.Coeff.Abs(&coeff)
+		d.Exponent = -l.Scale
+		return d, nil
+	case LogicalDate:
+		if days, ok := v.(int32); ok {
+			switch days {
+			case math.MinInt32:
+				return tree.NewDDate(pgdate.NegInfDate), nil
+			case math.MaxInt32:
+				return tree.NewDDate(pgdate.PosInfDate), nil
+			}
+			date, err := pgdate.MakeDateFromUnixEpoch(int64(days))
+			if err != nil {
+				return nil, err
+			}
+			return tree.NewDDate(date), nil
+		}
+	case LogicalTime:
+		var t int64
+		switch x := v.(type) {
+		case int32:
+			t = int64(x)
+		case int64:
+			t = x
+		default:
+			return nil, errors.AssertionFailedf("value of type %T is invalid for time column %q", v, col.Name)
+		}
+		micros := toMicros(t, l.Unit)
+		// FromInt wraps 24:00 around to midnight.
+		if micros == int64(timeofday.Time2400) {
+			return tree.MakeDTime(timeofday.Time2400), nil
+		}
+		return tree.MakeDTime(timeofday.FromInt(micros)), nil
+	case LogicalTimestamp:
+		if x, ok := v.(int64); ok {
+			var t time.Time
+			if l.Unit == Nanoseconds {
+				t = timeutil.Unix(0, x)
+			} else {
+				t = timeutil.FromUnixMicros(toMicros(x, l.Unit))
+			}
+			if l.AdjustedToUTC {
+				return tree.MakeDTimestampTZ(t, time.Microsecond)
+			}
+			return tree.MakeDTimestamp(t, time.Microsecond)
+		}
+	case LogicalInt:
+		switch x := v.(type) {
+		case int32:
+			if l.Unsigned {
+				return tree.NewDInt(tree.DInt(uint32(x))), nil
+			}
+			return tree.NewDInt(tree.DInt(x)), nil
+		case int64:
+			if l.Unsigned && l.BitWidth == 64 {
+				d := &tree.DDecimal{}
+				d.Coeff.SetUint64(uint64(x))
+				return d, nil
+			}
+			return tree.NewDInt(tree.DInt(x)), nil
+		}
+	}
+
+	switch x := v.(type) {
+	case bool:
+		return tree.MakeDBool(tree.DBool(x)), nil
+	case int32:
+		return tree.NewDInt(tree.DInt(x)), nil
+	case int64:
+		return tree.NewDInt(tree.DInt(x)), nil
+	case float32:
+		return tree.NewDFloat(tree.DFloat(x)), nil
+	case float64:
+		return tree.NewDFloat(tree.DFloat(x)), nil
+	case []byte:
+		if col.Type == Int96 && len(x) == int96Length {
+			// Int96 timestamps hold the nanoseconds of the day followed by
+			// the Julian day number.
+			nanos := int64(binary.LittleEndian.Uint64(x))
+			days := int64(binary.LittleEndian.Uint32(x[8:])) - julianDayOfUnixEpoch
+			return tree.MakeDTimestamp(timeutil.Unix(days*24*60*60, nanos), time.Microsecond)
+		}
+		return tree.NewDBytes(tree.DBytes(x)), nil
+	}
+	return nil, errors.AssertionFailedf("value of type %T is invalid for column %q of type %s",
+		v, col.Name, col.Type)
+}
+
+// toMicros converts a time in the given unit to microseconds.
+func toMicros(t int64, unit TimeUnit) int64 {
+	switch unit {
+	case Milliseconds:
+		return t * 1000
+	case Nanoseconds:
+		return t / 1000
+	default:
+		return t
+	}
+}
+
+// bytesToBigInt sets x to the value of a big-endian two's complement integer.
+// It is the inverse of decimalToBytes.
+func bytesToBigInt(x *big.Int, b []byte) {
+	x.SetBytes(b)
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		x.Sub(x, new(big.Int).Lsh(big.NewInt(1), uint(8*len(b))))
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package parquet
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/apd/v2"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/randgen"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewColumn(t *testing.T) {
+	for _, tc := range []struct {
+		typ      *types.T
+		expected Column
+	}{
+		{types.Bool, Column{Type: Boolean}},
+		{types.Int2, Column{Type: Int32, Logical: LogicalType{Kind: LogicalInt, BitWidth: 16}}},
+		{types.Int, Column{Type: Int64, Logical: LogicalType{Kind: LogicalInt, BitWidth: 64}}},
+		{types.Float4, Column{Type: Float}},
+		{types.Float, Column{Type: Double}},
+		{types.Decimal, Column{Type: ByteArray, Logical: LogicalType{Kind: LogicalString}}},
+		{types.MakeDecimal(10, 2), Column{
+			Type: ByteArray, Logical: LogicalType{Kind: LogicalDecimal, Precision: 10, Scale: 2},
+		}},
+		{types.String, Column{Type: ByteArray, Logical: LogicalType{Kind: LogicalString}}},
+		{types.Bytes, Column{Type: ByteArray}},
+		{types.Date, Column{Type: Int32, Logical: LogicalType{Kind: LogicalDate}}},
+		{types.Time, Column{Type: Int64, Logical: LogicalType{Kind: LogicalTime}}},
+		{types.TimestampTZ, Column{
+			Type: Int64, Logical: LogicalType{Kind: LogicalTimestamp, AdjustedToUTC: true},
+		}},
+		{types.Uuid, Column{Type: FixedLenByteArray, TypeLength: 16, Logical: LogicalType{Kind: LogicalUUID}}},
+		{types.Jsonb, Column{Type: ByteArray, Logical: LogicalType{Kind: LogicalJSON}}},
+		{types.Interval, Column{Type: ByteArray, Logical: LogicalType{Kind: LogicalString}}},
+		{types.IntArray, Column{
+			Type: Int64, Logical: LogicalType{Kind: LogicalInt, BitWidth: 64}, List: true,
+		}},
+	} {
+		t.Run(tc.typ.String(), func(t *testing.T) {
+			tc.expected.Name = "c"
+			require.Equal(t, tc.expected, NewColumn("c", tc.typ))
+		})
+	}
+}
+
+func TestDecimalToBytes(t *testing.T) {
+	for _, tc := range []struct {
+		d        string
+		scale    int32
+		expected []byte
+	}{
+		{"0", 0, []byte{0x00}},
+		{"-0", 2, []byte{0x00}},
+		{"1.23", 2, []byte{0x7b}},
+		{"-1.23", 2, []byte{0x85}},
+		{"1.5", 0, []byte{0x02}},
+		{"1.2", 3, []byte{0x04, 0xb0}},
+		{"128", 0, []byte{0x00, 0x80}},
+		{"-128", 0, []byte{0xff, 0x80}},
+	} {
+		t.Run(tc.d, func(t *testing.T) {
+			d, _, err := apd.NewFromString(tc.d)
+			require.NoError(t, err)
+			b, err := decimalToBytes(d, tc.scale)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, b)
+		})
+	}
+
+	_, err := decimalToBytes(&apd.Decimal{Form: apd.NaN}, 0)
+	require.Error(t, err)
+}
+
+// TestDatumToValue checks that random datums of every type can be written to
+// a file.
+func TestDatumToValue(t *testing.T) {
+	rng, _ := randutil.NewPseudoRand()
+	typs := append([]*types.T{types.IntArray, types.StringArray, types.MakeDecimal(10, 2)}, types.Scalar...)
+	for _, typ := range typs {
+		t.Run(typ.String(), func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewWriter(&buf, NewColumns([]string{"c"}, []*types.T{typ}))
+			require.NoError(t, err)
+			for i := 0; i < 10; i++ {
+				d := randgen.RandDatum(rng, typ, true /* nullOk */)
+				if dec, ok := d.(*tree.DDecimal); ok && dec.Form != apd.Finite {
+					continue
+				}
+				v, err := DatumToValue(d, typ)
+				require.NoError(t, err)
+				require.NoError(t, w.AddRow([]interface{}{v}))
+			}
+			require.NoError(t, w.Close())
+		})
+	}
+}
+
+// TestValueToDatum checks that the datums written to a file are read back
+// with their type.
+func TestValueToDatum(t *testing.T) {
+	evalCtx := tree.NewTestingEvalContext(cluster.MakeTestingClusterSettings())
+	defer evalCtx.Stop(context.Background())
+	rng, _ := randutil.NewPseudoRand()
+	for _, typ := range []*types.T{
+		types.Bool, types.Int2, types.Int4, types.Int, types.Float, types.String, types.Bytes,
+		types.Date, types.Time, types.Timestamp, types.TimestampTZ, types.Uuid, types.Jsonb,
+		types.IntArray, types.StringArray,
+	} {
+		t.Run(typ.String(), func(t *testing.T) {
+			col := NewColumn("c", typ)
+			require.True(t, typ.Identical(TypeForColumn(&col)), "%s", TypeForColumn(&col))
+			for i := 0; i < 10; i++ {
+				d := randgen.RandDatum(rng, typ, true /* nullOk */)
+				v, err := DatumToValue(d, typ)
+				require.NoError(t, err)
+				res, err := ValueToDatum(v, &col)
+				require.NoError(t, err)
+				require.Equal(t, 0, d.Compare(evalCtx, res), "%s != %s", d, res)
+			}
+		})
+	}
+}
+
+func TestValueToDatumLogicalTypes(t *testing.T) {
+	evalCtx := tree.NewTestingEvalContext(cluster.MakeTestingClusterSettings())
+	defer evalCtx.Stop(context.Background())
+	mustDecimal := func(s string) tree.Datum {
+		d, err := tree.ParseDDecimal(s)
+		require.NoError(t, err)
+		return d
+	}
+	for _, tc := range []struct {
+		col      Column
+		v        interface{}
+		expected tree.Datum
+	}{
+		{
+			Column{Type: Int96},
+			// One hour after midnight on the day after the Unix epoch.
+			[]byte{0x00, 0xa0, 0xb8, 0x30, 0x46, 0x03, 0x00, 0x00, 0x8d, 0x3d, 0x25, 0x00},
+			tree.MustMakeDTimestamp(time.Date(1970, 1, 2, 1, 0, 0, 0, time.UTC), time.Microsecond),
+		},
+		{
+			Column{Type: Int64, Logical: LogicalType{Kind: LogicalInt, BitWidth: 64, Unsigned: true}},
+			int64(-1),
+			mustDecimal("18446744073709551615"),
+		},
+		{
+			Column{Type: Int32, Logical: LogicalType{Kind: LogicalInt, BitWidth: 32, Unsigned: true}},
+			int32(-1),
+			tree.NewDInt(4294967295),
+		},
+		{
+			Column{Type: Int32, Logical: LogicalType{Kind: LogicalDecimal, Precision: 4, Scale: 2}},
+			int32(-1234),
+			mustDecimal("-12.34"),
+		},
+		{
+			Column{Type: FixedLenByteArray, TypeLength: 2, Logical: LogicalType{Kind: LogicalDecimal, Precision: 4, Scale: 1}},
+			[]byte{0xff, 0x80},
+			mustDecimal("-12.8"),
+		},
+		{
+			Column{Type: Int32, Logical: LogicalType{Kind: LogicalTime, Unit: Milliseconds}},
+			int32(1000),
+			tree.MakeDTime(timeofday.New(0, 0, 1, 0)),
+		},
+		{
+			Column{Type: Int64, Logical: LogicalType{Kind: LogicalTimestamp, Unit: Nanoseconds, AdjustedToUTC: true}},
+			int64(1000),
+			tree.MustMakeDTimestampTZ(time.Unix(0, 1000).UTC(), time.Microsecond),
+		},
+		{
+			Column{Type: ByteArray, Logical: LogicalType{Kind: LogicalEnum}},
+			[]byte("happy"),
+			tree.NewDString("happy"),
+		},
+	} {
+		t.Run(tc.expected.String(), func(t *testing.T) {
+			require.Equal(t, tc.expected.ResolvedType().Family(), TypeForColumn(&tc.col).Family())
+			res, err := ValueToDatum(tc.v, &tc.col)
+			require.NoError(t, err)
+			require.Equal(t, 0, tc.expected.Compare(evalCtx, res), "%s != %s", tc.expected, res)
+		})
+	}
+}
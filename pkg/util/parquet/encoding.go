@@ -0,0 +1,406 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package parquet
+
+import (
+	"encoding/binary"
+	"math"
+	"math/bits"
+
+	"github.com/cockroachdb/errors"
+)
+
+// Values of the Encoding enum of the Parquet format, in addition to
+// encodingPlain and encodingRLE.
+const (
+	encodingPlainDictionary      = 2
+	encodingBitPacked            = 4
+	encodingDeltaBinaryPacked    = 5
+	encodingDeltaLengthByteArray = 6
+	encodingDeltaByteArray       = 7
+	encodingRLEDictionary        = 8
+	encodingByteStreamSplit      = 9
+)
+
+// int96Length is the length of the values of Int96 columns.
+const int96Length = 12
+
+var errTruncated = errors.New("truncated parquet page")
+
+// bitWidth returns the number of bits needed to encode values up to max.
+func bitWidth(max uint64) int {
+	return bits.Len64(max)
+}
+
+// decodeValues decodes n values of a column encoded with the given encoding.
+// dict holds the values of the dictionary page of the column chunk, if any.
+func decodeValues(
+	buf []byte, col *Column, encoding int32, n int, dict []interface{},
+) ([]interface{}, error) {
+	switch encoding {
+	case encodingPlain:
+		return decodePlain(buf, col, n)
+	case encodingPlainDictionary, encodingRLEDictionary:
+		if dict == nil {
+			return nil, errors.New("missing dictionary page")
+		}
+		if len(buf) < 1 {
+			return nil, errTruncated
+		}
+		indexes, err := decodeHybrid(buf[1:], int(buf[0]), n)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]interface{}, n)
+		for i, idx := range indexes {
+			if int(idx) >= len(dict) {
+				return nil, errors.Newf("invalid dictionary index %d", idx)
+			}
+			values[i] = dict[idx]
+		}
+		return values, nil
+	case encodingRLE:
+		if col.Type != Boolean {
+			break
+		}
+		if len(buf) < 4 {
+			return nil, errTruncated
+		}
+		size := binary.LittleEndian.Uint32(buf)
+		if uint64(size) > uint64(len(buf)-4) {
+			return nil, errTruncated
+		}
+		bools, err := decodeHybrid(buf[4:4+size], 1, n)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]interface{}, n)
+		for i, b := range bools {
+			values[i] = b != 0
+		}
+		return values, nil
+	case encodingDeltaBinaryPacked:
+		if col.Type != Int32 && col.Type != Int64 {
+			break
+		}
+		ints, _, err := decodeDeltaBinaryPacked(buf, n)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]interface{}, n)
+		for i, v := range ints {
+			if col.Type == Int32 {
+				values[i] = int32(v)
+			} else {
+				values[i] = v
+			}
+		}
+		return values, nil
+	case encodingDeltaLengthByteArray:
+		if col.Type != ByteArray {
+			break
+		}
+		return decodeDeltaLengthByteArray(buf, n)
+	case encodingDeltaByteArray:
+		if col.Type != ByteArray && col.Type != FixedLenByteArray {
+			break
+		}
+		return decodeDeltaByteArray(buf, n)
+	case encodingByteStreamSplit:
+		width := fixedWidth(col)
+		if width == 0 {
+			break
+		}
+		if len(buf) < n*width {
+			return nil, errTruncated
+		}
+		// The k-th bytes of the values are stored in the k-th stream.
+		plain := make([]byte, n*width)
+		for i := 0; i < n; i++ {
+			for k := 0; k < width; k++ {
+				plain[i*width+k] = buf[k*n+i]
+			}
+		}
+		return decodePlain(plain, col, n)
+	}
+	return nil, errors.Newf("unsupported encoding %d for column %q of type %s", encoding, col.Name, col.Type)
+}
+
+// fixedWidth returns the length of the PLAIN encoding of the values of a
+// column, or zero if it varies.
+func fixedWidth(col *Column) int {
+	switch col.Type {
+	case Int32, Float:
+		return 4
+	case Int64, Double:
+		return 8
+	case Int96:
+		return int96Length
+	case FixedLenByteArray:
+		return int(col.TypeLength)
+	}
+	return 0
+}
+
+// decodePlain decodes n values of a column encoded with the PLAIN encoding.
+// Byte array values reference buf.
+func decodePlain(buf []byte, col *Column, n int) ([]interface{}, error) {
+	values := make([]interface{}, n)
+	if col.Type == Boolean {
+		if len(buf) < (n+7)/8 {
+			return nil, errTruncated
+		}
+		for i := range values {
+			values[i] = buf[i/8]&(1<<(i%8)) != 0
+		}
+		return values, nil
+	}
+	if col.Type == ByteArray {
+		for i := range values {
+			if len(buf) < 4 {
+				return nil, errTruncated
+			}
+			size := binary.LittleEndian.Uint32(buf)
+			if uint64(size) > uint64(len(buf)-4) {
+				return nil, errTruncated
+			}
+			values[i] = buf[4 : 4+size : 4+size]
+			buf = buf[4+size:]
+		}
+		return values, nil
+	}
+
+	width := fixedWidth(col)
+	if width == 0 {
+		return nil, errors.Newf("unsupported physical type %s", col.Type)
+	}
+	if len(buf)/width < n {
+		return nil, errTruncated
+	}
+	for i := range values {
+		b := buf[i*width : (i+1)*width : (i+1)*width]
+		switch col.Type {
+		case Int32:
+			values[i] = int32(binary.LittleEndian.Uint32(b))
+		case Int64:
+			values[i] = int64(binary.LittleEndian.Uint64(b))
+		case Float:
+			values[i] = math.Float32frombits(binary.LittleEndian.Uint32(b))
+		case Double:
+			values[i] = math.Float64frombits(binary.LittleEndian.Uint64(b))
+		default:
+			values[i] = b
+		}
+	}
+	return values, nil
+}
+
+// decodeHybrid decodes n values of the given bit width encoded with the
+// RLE/bit-packing hybrid encoding, which is a sequence of runs of repeated
+// values and of bit-packed values.
+func decodeHybrid(buf []byte, bitWidth int, n int) ([]uint32, error) {
+	if bitWidth > 32 {
+		return nil, errors.Newf("invalid bit width %d", bitWidth)
+	}
+	byteWidth := (bitWidth + 7) / 8
+	values := make([]uint32, 0, n)
+	for len(values) < n {
+		h, k := binary.Uvarint(buf)
+		if k <= 0 {
+			return nil, errTruncated
+		}
+		buf = buf[k:]
+		if h&1 == 0 {
+			// The header of a run of repeated values holds its length, and is
+			// followed by the value in the fewest bytes holding the bit width.
+			if len(buf) < byteWidth {
+				return nil, errTruncated
+			}
+			var v uint32
+			for i := 0; i < byteWidth; i++ {
+				v |= uint32(buf[i]) << (8 * i)
+			}
+			buf = buf[byteWidth:]
+			count := h >> 1
+			if rem := uint64(n - len(values)); count > rem {
+				count = rem
+			}
+			for ; count > 0; count-- {
+				values = append(values, v)
+			}
+			continue
+		}
+		// The header of bit-packed values holds their number in groups of 8.
+		groups := h >> 1
+		if groups > uint64(len(buf)) || groups*uint64(bitWidth) > uint64(len(buf)) {
+			return nil, errTruncated
+		}
+		for i := 0; i < int(groups)*8 && len(values) < n; i++ {
+			values = append(values, uint32(unpackBits(buf, bitWidth, i)))
+		}
+		buf = buf[int(groups)*bitWidth:]
+	}
+	return values, nil
+}
+
+// unpackBits returns the i-th value of the given bit width of a sequence of
+// values packed starting with the least significant bit of each byte. The
+// bits of the value must be in buf.
+func unpackBits(buf []byte, bitWidth int, i int) uint64 {
+	var v uint64
+	bit := i * bitWidth
+	for b := 0; b < bitWidth; {
+		off := (bit + b) % 8
+		take := 8 - off
+		if take > bitWidth-b {
+			take = bitWidth - b
+		}
+		v |= uint64(buf[(bit+b)/8]>>off&(1<<take-1)) << b
+		b += take
+	}
+	return v
+}
+
+// decodeBitPackedLevels decodes n levels encoded with the deprecated
+// BIT_PACKED encoding, which packs them starting with the most significant
+// bit of each byte, and returns the rest of the buffer.
+func decodeBitPackedLevels(
+	buf []byte, maxLevel uint8, n int,
+) (levels []uint8, rest []byte, _ error) {
+	width := bitWidth(uint64(maxLevel))
+	size := (n*width + 7) / 8
+	if len(buf) < size {
+		return nil, nil, errTruncated
+	}
+	levels = make([]uint8, n)
+	for i := range levels {
+		var v uint8
+		for b := 0; b < width; b++ {
+			bit := i*width + b
+			v = v<<1 | buf[bit/8]>>(7-bit%8)&1
+		}
+		if v > maxLevel {
+			return nil, nil, errors.Newf("invalid level %d", v)
+		}
+		levels[i] = v
+	}
+	return levels, buf[size:], nil
+}
+
+// maxDeltaBlockSize bounds the size of the blocks of the
+// DELTA_BINARY_PACKED encoding accepted by the decoder.
+const maxDeltaBlockSize = 1 << 16
+
+// decodeDeltaBinaryPacked decodes n integers encoded with the
+// DELTA_BINARY_PACKED encoding, and returns the rest of the buffer. The
+// encoding starts with a header holding the first value, followed by blocks
+// of bit-packed deltas between consecutive values. The blocks are divided in
+// miniblocks, each with its own bit width.
+func decodeDeltaBinaryPacked(buf []byte, n int) (values []int64, rest []byte, _ error) {
+	var header [4]uint64
+	for i := range header {
+		v, k := binary.Uvarint(buf)
+		if k <= 0 {
+			return nil, nil, errTruncated
+		}
+		header[i], buf = v, buf[k:]
+	}
+	blockSize, miniblocks, total := header[0], header[1], header[2]
+	first := int64(header[3]>>1) ^ -int64(header[3]&1)
+	if blockSize == 0 || blockSize > maxDeltaBlockSize || miniblocks == 0 ||
+		blockSize%miniblocks != 0 || (blockSize/miniblocks)%8 != 0 {
+		return nil, nil, errors.Newf("invalid delta block size %d with %d miniblocks", blockSize, miniblocks)
+	}
+	if total != uint64(n) {
+		return nil, nil, errors.Newf("expected %d delta encoded values, found %d", n, total)
+	}
+	perMiniblock := int(blockSize / miniblocks)
+
+	values = make([]int64, 0, n)
+	if n > 0 {
+		values = append(values, first)
+	}
+	prev := first
+	for len(values) < n {
+		v, k := binary.Uvarint(buf)
+		if k <= 0 || uint64(len(buf)-k) < miniblocks {
+			return nil, nil, errTruncated
+		}
+		minDelta := int64(v>>1) ^ -int64(v&1)
+		widths := buf[k : k+int(miniblocks)]
+		buf = buf[k+int(miniblocks):]
+		for m := 0; m < len(widths) && len(values) < n; m++ {
+			w := int(widths[m])
+			if w > 64 {
+				return nil, nil, errors.Newf("invalid bit width %d", w)
+			}
+			// Miniblocks are padded to hold perMiniblock values.
+			size := perMiniblock * w / 8
+			if len(buf) < size {
+				return nil, nil, errTruncated
+			}
+			for i := 0; i < perMiniblock && len(values) < n; i++ {
+				// The deltas wrap around on overflow.
+				prev += minDelta + int64(unpackBits(buf, w, i))
+				values = append(values, prev)
+			}
+			buf = buf[size:]
+		}
+	}
+	return values, buf, nil
+}
+
+// decodeDeltaLengthByteArray decodes n byte arrays encoded with the
+// DELTA_LENGTH_BYTE_ARRAY encoding: their DELTA_BINARY_PACKED lengths
+// followed by their concatenation.
+func decodeDeltaLengthByteArray(buf []byte, n int) ([]interface{}, error) {
+	lengths, buf, err := decodeDeltaBinaryPacked(buf, n)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]interface{}, n)
+	for i, l := range lengths {
+		if l < 0 || l > int64(len(buf)) {
+			return nil, errTruncated
+		}
+		values[i] = buf[:l:l]
+		buf = buf[l:]
+	}
+	return values, nil
+}
+
+// decodeDeltaByteArray decodes n byte arrays encoded with the
+// DELTA_BYTE_ARRAY encoding: the DELTA_BINARY_PACKED lengths of their
+// prefixes shared with the previous value, followed by the
+// DELTA_LENGTH_BYTE_ARRAY encoding of their suffixes.
+func decodeDeltaByteArray(buf []byte, n int) ([]interface{}, error) {
+	prefixes, buf, err := decodeDeltaBinaryPacked(buf, n)
+	if err != nil {
+		return nil, err
+	}
+	suffixes, err := decodeDeltaLengthByteArray(buf, n)
+	if err != nil {
+		return nil, err
+	}
+	var prev []byte
+	values := make([]interface{}, n)
+	for i, p := range prefixes {
+		if p < 0 || p > int64(len(prev)) {
+			return nil, errors.Newf("invalid prefix length %d", p)
+		}
+		suffix := suffixes[i].([]byte)
+		v := make([]byte, int(p)+len(suffix))
+		copy(v, prev[:p])
+		copy(v[p:], suffix)
+		values[i], prev = v, v
+	}
+	return values, nil
+}
@@ -0,0 +1,325 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+
+	"github.com/cockroachdb/errors"
+	"github.com/golang/snappy"
+)
+
+// Values of the PageType enum of the Parquet format, in addition to
+// pageTypeData.
+const (
+	pageTypeDictionary = 2
+	pageTypeDataV2     = 3
+)
+
+// pageHeader is the decoded PageHeader of a page, along with the fields of
+// the header specific to its type.
+type pageHeader struct {
+	typ              int32
+	uncompressedSize int32
+	compressedSize   int32
+	numValues        int32
+	encoding         int32
+	defEncoding      int32
+	repEncoding      int32
+	// defLength, repLength and compressed are set for DATA_PAGE_V2 pages,
+	// whose levels are stored uncompressed before the values.
+	defLength  int32
+	repLength  int32
+	compressed bool
+}
+
+func decodePageHeader(d *thriftDecoder, h *pageHeader) error {
+	h.compressed = true
+	return d.readStruct(func(id int16, typ byte) error {
+		var err error
+		switch {
+		case id == 1 && typ == thriftI32:
+			h.typ, err = d.i32()
+		case id == 2 && typ == thriftI32:
+			h.uncompressedSize, err = d.i32()
+		case id == 3 && typ == thriftI32:
+			h.compressedSize, err = d.i32()
+		case (id == 5 || id == 7) && typ == thriftStruct:
+			// The DataPageHeader and the DictionaryPageHeader start with the
+			// same fields.
+			return d.readStruct(func(id int16, typ byte) error {
+				var err error
+				switch {
+				case id == 1 && typ == thriftI32:
+					h.numValues, err = d.i32()
+				case id == 2 && typ == thriftI32:
+					h.encoding, err = d.i32()
+				case id == 3 && typ == thriftI32:
+					h.defEncoding, err = d.i32()
+				case id == 4 && typ == thriftI32:
+					h.repEncoding, err = d.i32()
+				default:
+					err = d.skip(typ)
+				}
+				return err
+			})
+		case id == 8 && typ == thriftStruct:
+			return d.readStruct(func(id int16, typ byte) error {
+				var err error
+				switch {
+				case id == 1 && typ == thriftI32:
+					h.numValues, err = d.i32()
+				case id == 4 && typ == thriftI32:
+					h.encoding, err = d.i32()
+				case id == 5 && typ == thriftI32:
+					h.defLength, err = d.i32()
+				case id == 6 && typ == thriftI32:
+					h.repLength, err = d.i32()
+				case id == 7 && (typ == thriftTrue || typ == thriftFalse):
+					h.compressed = typ == thriftTrue
+				default:
+					err = d.skip(typ)
+				}
+				return err
+			})
+		default:
+			err = d.skip(typ)
+		}
+		return err
+	})
+}
+
+// chunkReader decodes the pages of a column chunk into the values of the
+// column.
+type chunkReader struct {
+	col    *Column
+	levels columnLevels
+	codec  CompressionCodec
+	// dict holds the values of the dictionary page of the chunk.
+	dict []interface{}
+	rows []interface{}
+}
+
+func (c *chunkReader) read(buf []byte) ([]interface{}, error) {
+	d := thriftDecoder{buf: buf}
+	for d.pos < len(buf) {
+		var h pageHeader
+		if err := decodePageHeader(&d, &h); err != nil {
+			return nil, err
+		}
+		if h.compressedSize < 0 || int(h.compressedSize) > len(buf)-d.pos ||
+			h.uncompressedSize < 0 || h.numValues < 0 {
+			return nil, errors.New("invalid page header")
+		}
+		page := buf[d.pos : d.pos+int(h.compressedSize)]
+		d.pos += int(h.compressedSize)
+
+		var err error
+		switch h.typ {
+		case pageTypeDictionary:
+			err = c.readDictionaryPage(&h, page)
+		case pageTypeData:
+			err = c.readDataPage(&h, page)
+		case pageTypeDataV2:
+			err = c.readDataPageV2(&h, page)
+		default:
+			// Index pages are not needed to read the values.
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return c.rows, nil
+}
+
+func (c *chunkReader) readDictionaryPage(h *pageHeader, page []byte) error {
+	if h.encoding != encodingPlain && h.encoding != encodingPlainDictionary {
+		return errors.Newf("unsupported dictionary encoding %d", h.encoding)
+	}
+	buf, err := c.decompress(page, h.uncompressedSize)
+	if err != nil {
+		return err
+	}
+	c.dict, err = decodePlain(buf, c.col, int(h.numValues))
+	return err
+}
+
+func (c *chunkReader) readDataPage(h *pageHeader, page []byte) error {
+	buf, err := c.decompress(page, h.uncompressedSize)
+	if err != nil {
+		return err
+	}
+	n := int(h.numValues)
+	var rep, def []uint8
+	if c.levels.maxRep > 0 {
+		if rep, buf, err = decodeLevels(buf, h.repEncoding, c.levels.maxRep, n); err != nil {
+			return err
+		}
+	}
+	if c.levels.maxDef > 0 {
+		if def, buf, err = decodeLevels(buf, h.defEncoding, c.levels.maxDef, n); err != nil {
+			return err
+		}
+	}
+	return c.readValues(h, buf, rep, def)
+}
+
+func (c *chunkReader) readDataPageV2(h *pageHeader, page []byte) error {
+	if h.repLength < 0 || h.defLength < 0 || int(h.repLength)+int(h.defLength) > len(page) {
+		return errors.New("invalid page header")
+	}
+	n := int(h.numValues)
+	var rep, def []uint8
+	var err error
+	if c.levels.maxRep > 0 {
+		if rep, err = decodeLevelsHybrid(page[:h.repLength], c.levels.maxRep, n); err != nil {
+			return err
+		}
+	}
+	page = page[h.repLength:]
+	if c.levels.maxDef > 0 {
+		if def, err = decodeLevelsHybrid(page[:h.defLength], c.levels.maxDef, n); err != nil {
+			return err
+		}
+	}
+	page = page[h.defLength:]
+	if h.compressed {
+		if page, err = c.decompress(page, h.uncompressedSize-h.repLength-h.defLength); err != nil {
+			return err
+		}
+	}
+	return c.readValues(h, page, rep, def)
+}
+
+// readValues decodes the values of a data page and assembles them into rows
+// according to their levels.
+func (c *chunkReader) readValues(h *pageHeader, buf []byte, rep, def []uint8) error {
+	n := int(h.numValues)
+	numValues := n
+	if def != nil {
+		numValues = 0
+		for _, l := range def {
+			if l == c.levels.maxDef {
+				numValues++
+			}
+		}
+	}
+	values, err := decodeValues(buf, c.col, h.encoding, numValues, c.dict)
+	if err != nil {
+		return err
+	}
+
+	lv := &c.levels
+	for i := 0; i < n; i++ {
+		l := lv.maxDef
+		if def != nil {
+			l = def[i]
+		}
+		var v interface{}
+		if l == lv.maxDef {
+			v, values = values[0], values[1:]
+		}
+		if lv.maxRep == 0 {
+			if l < lv.structDef {
+				v = nullStruct{}
+			}
+			c.rows = append(c.rows, v)
+			continue
+		}
+		if rep[i] == 0 {
+			switch {
+			case l < lv.structDef:
+				c.rows = append(c.rows, nullStruct{})
+			case l < lv.listDef:
+				c.rows = append(c.rows, nil)
+			case l < lv.elemDef:
+				c.rows = append(c.rows, []interface{}{})
+			default:
+				c.rows = append(c.rows, []interface{}{v})
+			}
+			continue
+		}
+		var list []interface{}
+		if len(c.rows) > 0 {
+			list, _ = c.rows[len(c.rows)-1].([]interface{})
+		}
+		if list == nil || l < lv.elemDef {
+			return errors.New("invalid repetition levels")
+		}
+		c.rows[len(c.rows)-1] = append(list, v)
+	}
+	return nil
+}
+
+func (c *chunkReader) decompress(page []byte, uncompressedSize int32) ([]byte, error) {
+	switch c.codec {
+	case CompressionNone:
+		return page, nil
+	case CompressionSnappy:
+		return snappy.Decode(nil, page)
+	case CompressionGZIP:
+		r, err := gzip.NewReader(bytes.NewReader(page))
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if uncompressedSize > 0 {
+			buf.Grow(int(uncompressedSize))
+		}
+		if _, err := io.Copy(&buf, r); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, errors.Newf("unsupported compression codec %s", c.codec)
+	}
+}
+
+// decodeLevels decodes the levels at the beginning of a DATA_PAGE page, and
+// returns the rest of the page.
+func decodeLevels(
+	buf []byte, encoding int32, maxLevel uint8, n int,
+) (levels []uint8, rest []byte, _ error) {
+	switch encoding {
+	case encodingRLE:
+		if len(buf) < 4 {
+			return nil, nil, errTruncated
+		}
+		size := binary.LittleEndian.Uint32(buf)
+		if uint64(size) > uint64(len(buf)-4) {
+			return nil, nil, errTruncated
+		}
+		levels, err := decodeLevelsHybrid(buf[4:4+size], maxLevel, n)
+		return levels, buf[4+size:], err
+	case encodingBitPacked:
+		return decodeBitPackedLevels(buf, maxLevel, n)
+	default:
+		return nil, nil, errors.Newf("unsupported level encoding %d", encoding)
+	}
+}
+
+func decodeLevelsHybrid(buf []byte, maxLevel uint8, n int) ([]uint8, error) {
+	values, err := decodeHybrid(buf, bitWidth(uint64(maxLevel)), n)
+	if err != nil {
+		return nil, err
+	}
+	levels := make([]uint8, n)
+	for i, v := range values {
+		if v > uint32(maxLevel) {
+			return nil, errors.Newf("invalid level %d", v)
+		}
+		levels[i] = uint8(v)
+	}
+	return levels, nil
+}
@@ -0,0 +1,638 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package parquet
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/cockroachdb/errors"
+)
+
+// Reader reads the rows of a Parquet file. Only the metadata of the file is
+// read when the Reader is created; the row groups are read on demand. The
+// methods of a Reader can be called concurrently, so that the row groups of a
+// file can be read in parallel.
+//
+// Files can have columns of primitive types, lists of primitive types, and
+// structs whose fields are of those types. Columns of other nested types,
+// such as maps and nested structs, are not supported.
+type Reader struct {
+	r      io.ReaderAt
+	size   int64
+	cols   []Column
+	leaves []leafColumn
+	// levels holds the levels of each leaf column.
+	levels    []columnLevels
+	numRows   int64
+	rowGroups []rowGroup
+}
+
+// columnLevels describes the definition and repetition levels of the values
+// of a column.
+type columnLevels struct {
+	maxDef uint8
+	maxRep uint8
+	// listDef and elemDef are set for list columns. Lists whose definition
+	// level is lower than listDef are NULL, and those whose level is lower
+	// than elemDef are empty. Other levels define an element, which is NULL
+	// unless its level is maxDef.
+	listDef uint8
+	elemDef uint8
+	// structDef is set for the fields of optional structs. The struct of
+	// values whose definition level is lower than structDef is NULL.
+	structDef uint8
+}
+
+// nullStruct is the value of the fields of NULL structs returned by
+// chunkReader, which distinguishes them from NULL fields.
+type nullStruct struct{}
+
+type rowGroup struct {
+	numRows int64
+	chunks  []columnChunk
+}
+
+type columnChunk struct {
+	typ       PhysicalType
+	codec     CompressionCodec
+	numValues int64
+	// offset and size delimit the pages of the chunk in the file.
+	offset int64
+	size   int64
+}
+
+// schemaElement is the decoded SchemaElement of a field of the schema.
+type schemaElement struct {
+	name        string
+	typ         PhysicalType
+	hasType     bool
+	typeLength  int32
+	repetition  int32
+	numChildren int32
+	logical     LogicalType
+	// list and nested are set for groups annotated as lists, and as maps
+	// or other unsupported types.
+	list   bool
+	nested bool
+}
+
+// NewReader returns a Reader of the Parquet file of the given size held by r.
+func NewReader(r io.ReaderAt, size int64) (*Reader, error) {
+	if size < int64(2*len(magic)+4) {
+		return nil, errors.New("file is too small to be a parquet file")
+	}
+	var tail [8]byte
+	if err := readFullAt(r, tail[:], size-8); err != nil {
+		return nil, err
+	}
+	if string(tail[4:]) != magic {
+		if string(tail[4:]) == "PARE" {
+			return nil, errors.New("encrypted parquet files are not supported")
+		}
+		return nil, errors.New("not a parquet file")
+	}
+	footerLen := int64(binary.LittleEndian.Uint32(tail[:4]))
+	if footerLen > size-int64(2*len(magic)+4) {
+		return nil, errors.Newf("invalid parquet footer length %d", footerLen)
+	}
+	footer := make([]byte, footerLen)
+	if err := readFullAt(r, footer, size-8-footerLen); err != nil {
+		return nil, err
+	}
+
+	rd := &Reader{r: r, size: size}
+	if err := rd.decodeFooter(footer); err != nil {
+		return nil, errors.Wrap(err, "invalid parquet footer")
+	}
+	return rd, nil
+}
+
+// Columns returns the columns of the file.
+func (r *Reader) Columns() []Column {
+	return r.cols
+}
+
+// NumRows returns the number of rows of the file.
+func (r *Reader) NumRows() int64 {
+	return r.numRows
+}
+
+// NumRowGroups returns the number of row groups of the file.
+func (r *Reader) NumRowGroups() int {
+	return len(r.rowGroups)
+}
+
+// RowGroupNumRows returns the number of rows of the i-th row group.
+func (r *Reader) RowGroupNumRows(i int) int64 {
+	return r.rowGroups[i].numRows
+}
+
+// ReadRowGroup reads the rows of the i-th row group. Rows hold one value per
+// column, using the same representation as the values passed to
+// Writer.AddRow. The values of Int96 columns are 12-byte []byte.
+func (r *Reader) ReadRowGroup(i int) ([][]interface{}, error) {
+	rg := &r.rowGroups[i]
+	values := make([]interface{}, rg.numRows*int64(len(r.cols)))
+	rows := make([][]interface{}, rg.numRows)
+	for j := range rows {
+		rows[j] = values[j*len(r.cols) : (j+1)*len(r.cols) : (j+1)*len(r.cols)]
+	}
+	for c := range rg.chunks {
+		leaf := &r.leaves[c]
+		col, err := r.readColumnChunk(c, &rg.chunks[c])
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading column %q of row group %d", leaf.name(r.cols), i)
+		}
+		if int64(len(col)) != rg.numRows {
+			return nil, errors.Newf("column %q of row group %d has %d values, expected %d",
+				leaf.name(r.cols), i, len(col), rg.numRows)
+		}
+		if leaf.field < 0 {
+			for j, v := range col {
+				rows[j][leaf.top] = v
+			}
+			continue
+		}
+		numFields := len(r.cols[leaf.top].Fields)
+		for j, v := range col {
+			if _, ok := v.(nullStruct); ok {
+				continue
+			}
+			fields, _ := rows[j][leaf.top].([]interface{})
+			if fields == nil {
+				fields = make([]interface{}, numFields)
+				rows[j][leaf.top] = fields
+			}
+			fields[leaf.field] = v
+		}
+	}
+	return rows, nil
+}
+
+func (r *Reader) readColumnChunk(c int, chunk *columnChunk) ([]interface{}, error) {
+	if chunk.offset < 0 || chunk.size < 0 || chunk.offset+chunk.size > r.size {
+		return nil, errors.Newf("invalid column chunk range [%d, %d)", chunk.offset, chunk.offset+chunk.size)
+	}
+	buf := make([]byte, chunk.size)
+	if err := readFullAt(r.r, buf, chunk.offset); err != nil {
+		return nil, err
+	}
+	cr := chunkReader{col: r.leaves[c].col, levels: r.levels[c], codec: chunk.codec}
+	return cr.read(buf)
+}
+
+// readFullAt reads len(buf) bytes from r at the given offset.
+func readFullAt(r io.ReaderAt, buf []byte, off int64) error {
+	n, err := r.ReadAt(buf, off)
+	if n == len(buf) {
+		return nil
+	}
+	if err == nil || err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// decodeFooter decodes the FileMetaData of the file.
+func (r *Reader) decodeFooter(footer []byte) error {
+	d := thriftDecoder{buf: footer}
+	var elems []schemaElement
+	err := d.readStruct(func(id int16, typ byte) error {
+		var err error
+		switch {
+		case id == 2 && typ == thriftList:
+			return d.list(thriftStruct, func() error {
+				var el schemaElement
+				err := decodeSchemaElement(&d, &el)
+				elems = append(elems, el)
+				return err
+			})
+		case id == 3 && typ == thriftI64:
+			r.numRows, err = d.zigzag()
+		case id == 4 && typ == thriftList:
+			return d.list(thriftStruct, func() error {
+				var rg rowGroup
+				err := decodeRowGroup(&d, &rg)
+				r.rowGroups = append(r.rowGroups, rg)
+				return err
+			})
+		default:
+			return d.skip(typ)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if r.cols, r.levels, err = parseSchema(elems); err != nil {
+		return err
+	}
+	r.leaves = leafColumns(r.cols)
+	for i := range r.rowGroups {
+		rg := &r.rowGroups[i]
+		if len(rg.chunks) != len(r.leaves) {
+			return errors.Newf("row group %d has %d column chunks, expected %d",
+				i, len(rg.chunks), len(r.leaves))
+		}
+		for j := range rg.chunks {
+			if rg.chunks[j].typ != r.leaves[j].col.Type {
+				return errors.Newf("column chunk %d of row group %d has type %s, expected %s",
+					j, i, rg.chunks[j].typ, r.leaves[j].col.Type)
+			}
+		}
+	}
+	return nil
+}
+
+func decodeSchemaElement(d *thriftDecoder, el *schemaElement) error {
+	converted := int32(-1)
+	var scale, precision int32
+	hasLogical := false
+	err := d.readStruct(func(id int16, typ byte) error {
+		var err error
+		switch {
+		case id == 1 && typ == thriftI32:
+			var t int32
+			t, err = d.i32()
+			el.typ, el.hasType = PhysicalType(t), true
+		case id == 2 && typ == thriftI32:
+			el.typeLength, err = d.i32()
+		case id == 3 && typ == thriftI32:
+			el.repetition, err = d.i32()
+		case id == 4 && typ == thriftBinary:
+			el.name, err = d.string()
+		case id == 5 && typ == thriftI32:
+			el.numChildren, err = d.i32()
+		case id == 6 && typ == thriftI32:
+			converted, err = d.i32()
+		case id == 7 && typ == thriftI32:
+			scale, err = d.i32()
+		case id == 8 && typ == thriftI32:
+			precision, err = d.i32()
+		case id == 10 && typ == thriftStruct:
+			hasLogical = true
+			err = decodeLogicalType(d, el)
+		default:
+			err = d.skip(typ)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if el.logical.Kind == LogicalDecimal && el.logical.Precision == 0 {
+		el.logical.Precision, el.logical.Scale = precision, scale
+	}
+	if !hasLogical {
+		applyConvertedType(el, converted, precision, scale)
+	}
+	return nil
+}
+
+// decodeLogicalType decodes the LogicalType union of a SchemaElement.
+func decodeLogicalType(d *thriftDecoder, el *schemaElement) error {
+	l := &el.logical
+	return d.readStruct(func(id int16, typ byte) error {
+		if typ != thriftStruct {
+			return d.skip(typ)
+		}
+		switch id {
+		case 1:
+			l.Kind = LogicalString
+		case 2:
+			el.nested = true
+		case 3:
+			el.list = true
+		case 4:
+			l.Kind = LogicalEnum
+		case 5:
+			l.Kind = LogicalDecimal
+			return d.readStruct(func(id int16, typ byte) error {
+				var err error
+				switch {
+				case id == 1 && typ == thriftI32:
+					l.Scale, err = d.i32()
+				case id == 2 && typ == thriftI32:
+					l.Precision, err = d.i32()
+				default:
+					err = d.skip(typ)
+				}
+				return err
+			})
+		case 6:
+			l.Kind = LogicalDate
+		case 7, 8:
+			l.Kind = LogicalTime
+			if id == 8 {
+				l.Kind = LogicalTimestamp
+			}
+			return d.readStruct(func(id int16, typ byte) error {
+				switch {
+				case id == 1 && (typ == thriftTrue || typ == thriftFalse):
+					l.AdjustedToUTC = typ == thriftTrue
+					return nil
+				case id == 2 && typ == thriftStruct:
+					return d.readStruct(func(id int16, typ byte) error {
+						for unit, unitID := range timeUnitIDs {
+							if id == unitID {
+								l.Unit = unit
+							}
+						}
+						return d.skip(typ)
+					})
+				default:
+					return d.skip(typ)
+				}
+			})
+		case 10:
+			l.Kind = LogicalInt
+			return d.readStruct(func(id int16, typ byte) error {
+				switch {
+				case id == 1 && typ == thriftByte:
+					b, err := d.byte()
+					l.BitWidth = int8(b)
+					return err
+				case id == 2 && (typ == thriftTrue || typ == thriftFalse):
+					l.Unsigned = typ == thriftFalse
+					return nil
+				default:
+					return d.skip(typ)
+				}
+			})
+		case 12:
+			l.Kind = LogicalJSON
+		case 14:
+			l.Kind = LogicalUUID
+		}
+		return d.skip(typ)
+	})
+}
+
+// applyConvertedType sets the logical type of a SchemaElement written by an
+// older writer, which only annotated it with a converted type.
+func applyConvertedType(el *schemaElement, converted, precision, scale int32) {
+	l := &el.logical
+	switch converted {
+	case convertedUTF8:
+		l.Kind = LogicalString
+	case convertedMap, convertedMapKeyValue:
+		el.nested = true
+	case convertedList:
+		el.list = true
+	case convertedEnum:
+		l.Kind = LogicalEnum
+	case convertedDecimal:
+		*l = LogicalType{Kind: LogicalDecimal, Precision: precision, Scale: scale}
+	case convertedDate:
+		l.Kind = LogicalDate
+	case convertedTimeMillis, convertedTimeMicros:
+		*l = LogicalType{Kind: LogicalTime, AdjustedToUTC: true}
+		if converted == convertedTimeMillis {
+			l.Unit = Milliseconds
+		}
+	case convertedTimestampMillis, convertedTimestampMicros:
+		*l = LogicalType{Kind: LogicalTimestamp, AdjustedToUTC: true}
+		if converted == convertedTimestampMillis {
+			l.Unit = Milliseconds
+		}
+	case convertedUint8, convertedUint16, convertedUint32, convertedUint64:
+		*l = LogicalType{Kind: LogicalInt, BitWidth: 8 << (converted - convertedUint8), Unsigned: true}
+	case convertedInt8, convertedInt16, convertedInt32, convertedInt64:
+		*l = LogicalType{Kind: LogicalInt, BitWidth: 8 << (converted - convertedInt8)}
+	case convertedJSON:
+		l.Kind = LogicalJSON
+	}
+}
+
+func decodeRowGroup(d *thriftDecoder, rg *rowGroup) error {
+	return d.readStruct(func(id int16, typ byte) error {
+		var err error
+		switch {
+		case id == 1 && typ == thriftList:
+			return d.list(thriftStruct, func() error {
+				var c columnChunk
+				err := decodeColumnChunk(d, &c)
+				rg.chunks = append(rg.chunks, c)
+				return err
+			})
+		case id == 3 && typ == thriftI64:
+			rg.numRows, err = d.zigzag()
+			if rg.numRows < 0 {
+				err = errors.Newf("invalid number of rows %d", rg.numRows)
+			}
+		default:
+			err = d.skip(typ)
+		}
+		return err
+	})
+}
+
+func decodeColumnChunk(d *thriftDecoder, c *columnChunk) error {
+	var dataOffset, dictOffset int64
+	err := d.readStruct(func(id int16, typ byte) error {
+		switch {
+		case id == 1 && typ == thriftBinary:
+			return errors.New("column chunks stored in other files are not supported")
+		case id == 3 && typ == thriftStruct:
+			return d.readStruct(func(id int16, typ byte) error {
+				var err error
+				var v int32
+				switch {
+				case id == 1 && typ == thriftI32:
+					v, err = d.i32()
+					c.typ = PhysicalType(v)
+				case id == 4 && typ == thriftI32:
+					v, err = d.i32()
+					c.codec = CompressionCodec(v)
+				case id == 5 && typ == thriftI64:
+					c.numValues, err = d.zigzag()
+				case id == 7 && typ == thriftI64:
+					c.size, err = d.zigzag()
+				case id == 9 && typ == thriftI64:
+					dataOffset, err = d.zigzag()
+				case id == 11 && typ == thriftI64:
+					dictOffset, err = d.zigzag()
+				default:
+					err = d.skip(typ)
+				}
+				return err
+			})
+		default:
+			return d.skip(typ)
+		}
+	})
+	// The pages of a chunk start with its dictionary page, if any. Some
+	// writers set the offset of the dictionary page to zero when there is
+	// none.
+	c.offset = dataOffset
+	if dictOffset > 0 && dictOffset < dataOffset {
+		c.offset = dictOffset
+	}
+	return err
+}
+
+// parseSchema returns the columns described by the flattened schema tree,
+// whose root is a group holding the fields of the columns, along with the
+// levels of their leaf columns.
+func parseSchema(elems []schemaElement) ([]Column, []columnLevels, error) {
+	if len(elems) == 0 {
+		return nil, nil, errors.New("empty schema")
+	}
+	var cols []Column
+	var levels []columnLevels
+	pos := 1
+	for i := int32(0); i < elems[0].numChildren; i++ {
+		if pos >= len(elems) {
+			return nil, nil, errors.New("truncated schema")
+		}
+		var col Column
+		var lv columnLevels
+		var next int
+		var err error
+		if elems[pos].isStruct() {
+			var structLevels []columnLevels
+			col, structLevels, next, err = parseStruct(elems, pos)
+			levels = append(levels, structLevels...)
+		} else {
+			col, lv, next, err = parseField(elems, pos)
+			levels = append(levels, lv)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		cols = append(cols, col)
+		pos = next
+	}
+	if pos != len(elems) {
+		return nil, nil, errors.New("invalid schema")
+	}
+	return cols, levels, nil
+}
+
+// isStruct returns whether the element is a group that is neither a list nor
+// another nested type, which holds the fields of a struct.
+func (el *schemaElement) isStruct() bool {
+	return el.numChildren > 0 && !el.list && !el.nested
+}
+
+// parseStruct parses the group of a struct column starting at elems[pos], and
+// returns the levels of its fields and the position of the next field.
+func parseStruct(elems []schemaElement, pos int) (Column, []columnLevels, int, error) {
+	el := &elems[pos]
+	unsupported := errors.Newf("column %q has an unsupported nested type", el.name)
+	if el.repetition == repetitionRepeated {
+		return Column{}, nil, 0, unsupported
+	}
+	col := Column{Name: el.name, Fields: make([]Column, 0, el.numChildren)}
+	levels := make([]columnLevels, 0, el.numChildren)
+	next := pos + 1
+	for i := int32(0); i < el.numChildren; i++ {
+		if next >= len(elems) {
+			return Column{}, nil, 0, errors.New("truncated schema")
+		}
+		if elems[next].isStruct() {
+			return Column{}, nil, 0, unsupported
+		}
+		f, lv, n, err := parseField(elems, next)
+		if err != nil {
+			return Column{}, nil, 0, err
+		}
+		if el.repetition == repetitionOptional {
+			lv.structDef = 1
+			lv.maxDef++
+			if f.List {
+				lv.listDef++
+				lv.elemDef++
+			}
+		}
+		col.Fields = append(col.Fields, f)
+		levels = append(levels, lv)
+		next = n
+	}
+	return col, levels, next, nil
+}
+
+// parseField parses the field of a column starting at elems[pos], and returns
+// the position of the next field.
+func parseField(elems []schemaElement, pos int) (Column, columnLevels, int, error) {
+	el := &elems[pos]
+	if el.numChildren == 0 {
+		col, err := primitiveColumn(el.name, el)
+		var lv columnLevels
+		switch el.repetition {
+		case repetitionOptional:
+			lv.maxDef = 1
+		case repetitionRepeated:
+			// A repeated field that is not in a group annotated as a list is a
+			// list of required elements that cannot be NULL.
+			col.List = true
+			lv = columnLevels{maxDef: 1, maxRep: 1, elemDef: 1}
+		}
+		return col, lv, pos + 1, err
+	}
+
+	unsupported := errors.Newf("column %q has an unsupported nested type", el.name)
+	if !el.list || el.nested || el.numChildren != 1 || el.repetition == repetitionRepeated ||
+		pos+1 >= len(elems) {
+		return Column{}, columnLevels{}, 0, unsupported
+	}
+	lv := columnLevels{maxRep: 1}
+	if el.repetition == repetitionOptional {
+		lv.listDef = 1
+	}
+	lv.elemDef = lv.listDef + 1
+	lv.maxDef = lv.elemDef
+
+	repeated := &elems[pos+1]
+	if repeated.repetition != repetitionRepeated {
+		return Column{}, columnLevels{}, 0, unsupported
+	}
+	// Lists written by older writers may use a two-level structure, in which
+	// the repeated field holds the elements.
+	if repeated.numChildren == 0 {
+		col, err := primitiveColumn(el.name, repeated)
+		col.List = true
+		return col, lv, pos + 2, err
+	}
+	if repeated.numChildren != 1 || pos+2 >= len(elems) {
+		return Column{}, columnLevels{}, 0, unsupported
+	}
+	elem := &elems[pos+2]
+	if elem.numChildren != 0 || elem.repetition == repetitionRepeated {
+		return Column{}, columnLevels{}, 0, unsupported
+	}
+	if elem.repetition == repetitionOptional {
+		lv.maxDef++
+	}
+	col, err := primitiveColumn(el.name, elem)
+	col.List = true
+	return col, lv, pos + 3, err
+}
+
+// primitiveColumn returns the column holding the values of a primitive field.
+func primitiveColumn(name string, el *schemaElement) (Column, error) {
+	col := Column{Name: name, Type: el.typ, TypeLength: el.typeLength, Logical: el.logical}
+	if _, ok := physicalTypeNames[el.typ]; !ok || !el.hasType {
+		return col, errors.Newf("column %q has an unsupported physical type %d", name, el.typ)
+	}
+	if el.nested || el.list {
+		return col, errors.Newf("column %q has an unsupported nested type", name)
+	}
+	if el.typ == FixedLenByteArray && el.typeLength <= 0 {
+		return col, errors.Newf("column %q has invalid type length %d", name, el.typeLength)
+	}
+	if el.typ != FixedLenByteArray {
+		col.TypeLength = 0
+	}
+	return col, nil
+}
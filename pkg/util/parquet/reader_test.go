@@ -0,0 +1,417 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestThriftDecoder(t *testing.T) {
+	var e thriftEncoder
+	e.structBegin()
+	e.i32Field(1, 3)
+	e.stringField(4, "ab")
+	e.structField(5)
+	e.boolField(1, true)
+	e.structEnd()
+	e.i64Field(30, -1)
+	e.listField(31, thriftI32, 2)
+	e.i32(1)
+	e.i32(-2)
+	e.structEnd()
+
+	d := thriftDecoder{buf: e.buf}
+	var ids []int16
+	require.NoError(t, d.readStruct(func(id int16, typ byte) error {
+		ids = append(ids, id)
+		switch id {
+		case 1:
+			v, err := d.i32()
+			require.Equal(t, int32(3), v)
+			return err
+		case 4:
+			v, err := d.string()
+			require.Equal(t, "ab", v)
+			return err
+		case 30:
+			v, err := d.zigzag()
+			require.Equal(t, int64(-1), v)
+			return err
+		case 31:
+			var vs []int32
+			err := d.list(thriftI32, func() error {
+				v, err := d.i32()
+				vs = append(vs, v)
+				return err
+			})
+			require.Equal(t, []int32{1, -2}, vs)
+			return err
+		default:
+			return d.skip(typ)
+		}
+	}))
+	require.Equal(t, []int16{1, 4, 5, 30, 31}, ids)
+	require.Equal(t, len(e.buf), d.pos)
+
+	for i := 0; i < len(e.buf); i++ {
+		d := thriftDecoder{buf: e.buf[:i]}
+		require.Error(t, d.skip(thriftStruct))
+	}
+}
+
+func TestReaderRoundTrip(t *testing.T) {
+	cols := []Column{
+		{Name: "b", Type: Boolean},
+		{Name: "i", Type: Int64, Logical: LogicalType{Kind: LogicalInt, BitWidth: 64}},
+		{Name: "s", Type: ByteArray, Logical: LogicalType{Kind: LogicalString}},
+		{Name: "u", Type: FixedLenByteArray, TypeLength: 2},
+		{Name: "l", Type: Int32, List: true},
+		{Name: "f", Type: Double},
+		{Name: "t", Type: Int64, Logical: LogicalType{
+			Kind: LogicalTimestamp, AdjustedToUTC: true, Unit: Milliseconds,
+		}},
+		{Name: "d", Type: ByteArray, Logical: LogicalType{Kind: LogicalDecimal, Precision: 10, Scale: 2}},
+		{Name: "n", Type: Int32, Logical: LogicalType{Kind: LogicalInt, BitWidth: 8, Unsigned: true}},
+		{Name: "st", Fields: []Column{
+			{Name: "x", Type: Int32},
+			{Name: "y", Type: ByteArray, List: true},
+		}},
+	}
+	rows := [][]interface{}{
+		{true, int64(1), []byte("a"), []byte{1, 2}, []interface{}{int32(1), nil, int32(3)},
+			1.5, int64(1000), []byte{0x7b}, int32(255), []interface{}{int32(7), []interface{}{[]byte("p"), nil}}},
+		{nil, nil, nil, nil, nil, nil, nil, nil, nil, nil},
+		{false, int64(-1), []byte(""), []byte{3, 4}, []interface{}{}, -2.5, int64(-1), []byte{0x85}, int32(0),
+			[]interface{}{nil, []interface{}{}}},
+		{true, int64(2), []byte("bc"), []byte{5, 6}, []interface{}{nil}, 0.0, int64(0), []byte{0x00}, nil,
+			[]interface{}{nil, nil}},
+	}
+
+	for _, codec := range []CompressionCodec{CompressionNone, CompressionSnappy, CompressionGZIP} {
+		t.Run(codec.String(), func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewWriter(&buf, cols, WithCompressionCodec(codec), WithMaxRowGroupLength(3))
+			require.NoError(t, err)
+			for _, row := range rows {
+				require.NoError(t, w.AddRow(row))
+			}
+			require.NoError(t, w.Close())
+
+			r, err := NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+			require.NoError(t, err)
+			require.Equal(t, cols, r.Columns())
+			require.Equal(t, int64(len(rows)), r.NumRows())
+			require.Equal(t, 2, r.NumRowGroups())
+			require.Equal(t, int64(3), r.RowGroupNumRows(0))
+			var read [][]interface{}
+			for i := 0; i < r.NumRowGroups(); i++ {
+				rg, err := r.ReadRowGroup(i)
+				require.NoError(t, err)
+				read = append(read, rg...)
+			}
+			require.Equal(t, rows, read)
+		})
+	}
+}
+
+func TestDecodeHybrid(t *testing.T) {
+	// The example of the specification of the encoding: the values 0 to 7
+	// bit-packed with a bit width of 3.
+	values, err := decodeHybrid([]byte{0x03, 0x88, 0xc6, 0xfa}, 3, 8)
+	require.NoError(t, err)
+	require.Equal(t, []uint32{0, 1, 2, 3, 4, 5, 6, 7}, values)
+
+	// A run of 4 times 300 followed by a group of bit-packed values starting
+	// with 1, 1.
+	values, err = decodeHybrid([]byte{0x08, 0x2c, 0x01, 0x03, 0x01, 0x02, 0, 0, 0, 0, 0, 0, 0}, 9, 6)
+	require.NoError(t, err)
+	require.Equal(t, []uint32{300, 300, 300, 300, 1, 1}, values)
+
+	values, err = decodeHybrid([]byte{0x06, 0x00}, 0, 3)
+	require.NoError(t, err)
+	require.Equal(t, []uint32{0, 0, 0}, values)
+
+	_, err = decodeHybrid([]byte{0x03, 0x88}, 3, 8)
+	require.Error(t, err)
+	_, err = decodeHybrid([]byte{0x08}, 1, 4)
+	require.Error(t, err)
+}
+
+func TestDecodeBitPackedLevels(t *testing.T) {
+	// The example of the specification of the encoding.
+	levels, rest, err := decodeBitPackedLevels([]byte{0x05, 0x39, 0x77, 0xff}, 7, 8)
+	require.NoError(t, err)
+	require.Equal(t, []uint8{0, 1, 2, 3, 4, 5, 6, 7}, levels)
+	require.Equal(t, []byte{0xff}, rest)
+}
+
+func TestDecodeDelta(t *testing.T) {
+	// The values 7, 5, 3, 1, 2 in a block of 4 miniblocks of 32 values: the
+	// deltas are -2, -2, -2, 1, stored relative to the min delta with a bit
+	// width of 2 in the first miniblock.
+	buf := []byte{
+		0x80, 0x01, 0x04, 0x05, 0x0e, // block size, miniblocks, count, first value
+		0x03,                   // min delta
+		0x02, 0x00, 0x00, 0x00, // bit widths
+		0xc0, 0, 0, 0, 0, 0, 0, 0, // first miniblock
+		'x',
+	}
+	values, rest, err := decodeDeltaBinaryPacked(buf, 5)
+	require.NoError(t, err)
+	require.Equal(t, []int64{7, 5, 3, 1, 2}, values)
+	require.Equal(t, []byte("x"), rest)
+
+	_, _, err = decodeDeltaBinaryPacked(buf, 4)
+	require.Error(t, err)
+	_, _, err = decodeDeltaBinaryPacked(buf[:12], 5)
+	require.Error(t, err)
+
+	// "axis" and "axle" share a prefix of 2 bytes.
+	byteArrays, err := decodeDeltaByteArray([]byte{
+		0x80, 0x01, 0x04, 0x02, 0x00, // prefix lengths 0, 2
+		0x04, 0x00, 0x00, 0x00, 0x00,
+		0x80, 0x01, 0x04, 0x02, 0x08, // suffix lengths 4, 2
+		0x03, 0x00, 0x00, 0x00, 0x00,
+		'a', 'x', 'i', 's', 'l', 'e',
+	}, 2)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{[]byte("axis"), []byte("axle")}, byteArrays)
+}
+
+// TestReaderEncodings reads a file written the way other writers do: with
+// required and repeated fields, dictionary pages, and DATA_PAGE_V2 pages.
+func TestReaderEncodings(t *testing.T) {
+	var file []byte
+	file = append(file, magic...)
+	appendPage := func(h func(e *thriftEncoder), data []byte) {
+		var e thriftEncoder
+		e.structBegin()
+		h(&e)
+		e.structEnd()
+		file = append(file, e.buf...)
+		file = append(file, data...)
+	}
+	plainInt32s := func(vs ...int32) []byte {
+		var b []byte
+		for _, v := range vs {
+			b = appendUint32(b, uint32(v))
+		}
+		return b
+	}
+	dataPageHeader := func(e *thriftEncoder, size, numValues, encoding int32) {
+		e.i32Field(1, pageTypeData)
+		e.i32Field(2, size)
+		e.i32Field(3, size)
+		e.structField(5)
+		e.i32Field(1, numValues)
+		e.i32Field(2, encoding)
+		e.i32Field(3, encodingRLE)
+		e.i32Field(4, encodingRLE)
+		e.structEnd()
+	}
+	var offsets []int64
+
+	// A required string column with the values "x", "y", "x" stored in a
+	// dictionary.
+	offsets = append(offsets, int64(len(file)))
+	dict := []byte{1, 0, 0, 0, 'x', 1, 0, 0, 0, 'y'}
+	appendPage(func(e *thriftEncoder) {
+		e.i32Field(1, pageTypeDictionary)
+		e.i32Field(2, int32(len(dict)))
+		e.i32Field(3, int32(len(dict)))
+		e.structField(7)
+		e.i32Field(1, 2)
+		e.i32Field(2, encodingPlainDictionary)
+		e.structEnd()
+	}, dict)
+	indexes := []byte{0x01, 0x03, 0x02}
+	appendPage(func(e *thriftEncoder) {
+		dataPageHeader(e, int32(len(indexes)), 3, encodingRLEDictionary)
+	}, indexes)
+
+	// An optional int column with the values 5, NULL, 7 in a DATA_PAGE_V2.
+	offsets = append(offsets, int64(len(file)))
+	v2 := append([]byte{0x03, 0x05}, plainInt32s(5, 7)...)
+	appendPage(func(e *thriftEncoder) {
+		e.i32Field(1, pageTypeDataV2)
+		e.i32Field(2, int32(len(v2)))
+		e.i32Field(3, int32(len(v2)))
+		e.structField(8)
+		e.i32Field(1, 3)
+		e.i32Field(2, 1)
+		e.i32Field(3, 3)
+		e.i32Field(4, encodingPlain)
+		e.i32Field(5, 2)
+		e.i32Field(6, 0)
+		e.boolField(7, false)
+		e.structEnd()
+	}, v2)
+
+	// A repeated int column with the values [1, 2], [], [3], split in two
+	// pages.
+	offsets = append(offsets, int64(len(file)))
+	page := appendLevels(nil, []uint8{0, 1, 0})
+	page = appendLevels(page, []uint8{1, 1, 0})
+	page = append(page, plainInt32s(1, 2)...)
+	appendPage(func(e *thriftEncoder) {
+		dataPageHeader(e, int32(len(page)), 3, encodingPlain)
+	}, page)
+	page = appendLevels(nil, []uint8{0})
+	page = appendLevels(page, []uint8{1})
+	page = append(page, plainInt32s(3)...)
+	appendPage(func(e *thriftEncoder) {
+		dataPageHeader(e, int32(len(page)), 1, encodingPlain)
+	}, page)
+	offsets = append(offsets, int64(len(file)))
+
+	var e thriftEncoder
+	e.structBegin()
+	e.i32Field(1, 1)
+	e.listField(2, thriftStruct, 4)
+	e.structBegin()
+	e.stringField(4, "schema")
+	e.i32Field(5, 3)
+	e.structEnd()
+	for _, f := range []struct {
+		name       string
+		typ        PhysicalType
+		repetition int32
+		converted  int32
+	}{
+		{"a", ByteArray, repetitionRequired, convertedUTF8},
+		{"b", Int32, repetitionOptional, convertedInt16},
+		{"c", Int32, repetitionRepeated, -1},
+	} {
+		e.structBegin()
+		e.i32Field(1, int32(f.typ))
+		e.i32Field(3, f.repetition)
+		e.stringField(4, f.name)
+		if f.converted >= 0 {
+			e.i32Field(6, f.converted)
+		}
+		e.structEnd()
+	}
+	e.i64Field(3, 3)
+	e.listField(4, thriftStruct, 1)
+	e.structBegin()
+	e.listField(1, thriftStruct, 3)
+	for i, typ := range []PhysicalType{ByteArray, Int32, Int32} {
+		e.structBegin()
+		e.i64Field(2, offsets[i])
+		e.structField(3)
+		e.i32Field(1, int32(typ))
+		e.i32Field(4, int32(CompressionNone))
+		e.i64Field(7, offsets[i+1]-offsets[i])
+		e.i64Field(9, offsets[i])
+		e.structEnd()
+		e.structEnd()
+	}
+	e.i64Field(3, 3)
+	e.structEnd()
+	e.structEnd()
+	file = append(file, e.buf...)
+	file = appendUint32(file, uint32(len(e.buf)))
+	file = append(file, magic...)
+
+	r, err := NewReader(bytes.NewReader(file), int64(len(file)))
+	require.NoError(t, err)
+	require.Equal(t, []Column{
+		{Name: "a", Type: ByteArray, Logical: LogicalType{Kind: LogicalString}},
+		{Name: "b", Type: Int32, Logical: LogicalType{Kind: LogicalInt, BitWidth: 16}},
+		{Name: "c", Type: Int32, List: true},
+	}, r.Columns())
+	rows, err := r.ReadRowGroup(0)
+	require.NoError(t, err)
+	require.Equal(t, [][]interface{}{
+		{[]byte("x"), int32(5), []interface{}{int32(1), int32(2)}},
+		{[]byte("y"), nil, []interface{}{}},
+		{[]byte("x"), int32(7), []interface{}{int32(3)}},
+	}, rows)
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	var scratch [4]byte
+	binary.LittleEndian.PutUint32(scratch[:], v)
+	return append(b, scratch[:]...)
+}
+
+func TestReaderErrors(t *testing.T) {
+	fileWithSchema := func(schema func(e *thriftEncoder)) []byte {
+		var e thriftEncoder
+		e.structBegin()
+		schema(&e)
+		e.structEnd()
+		file := append([]byte(magic), e.buf...)
+		file = appendUint32(file, uint32(len(e.buf)))
+		return append(file, magic...)
+	}
+	group := func(e *thriftEncoder, name string, numChildren, converted int32) {
+		e.structBegin()
+		e.i32Field(3, repetitionOptional)
+		e.stringField(4, name)
+		e.i32Field(5, numChildren)
+		if converted >= 0 {
+			e.i32Field(6, converted)
+		}
+		e.structEnd()
+	}
+	leaf := func(e *thriftEncoder, name string, repetition int32) {
+		e.structBegin()
+		e.i32Field(1, int32(Int32))
+		e.i32Field(3, repetition)
+		e.stringField(4, name)
+		e.structEnd()
+	}
+
+	for _, tc := range []struct {
+		name     string
+		file     []byte
+		expected string
+	}{
+		{"empty", nil, "too small"},
+		{"magic", []byte("PAR1\x00\x00\x00\x00PAR2"), "not a parquet file"},
+		{"encrypted", []byte("PAR1\x00\x00\x00\x00PARE"), "encrypted"},
+		{"footer length", []byte("PAR1\xff\x00\x00\x00PAR1"), "invalid parquet footer length"},
+		{"nested struct", fileWithSchema(func(e *thriftEncoder) {
+			e.listField(2, thriftStruct, 4)
+			group(e, "schema", 1, -1)
+			group(e, "s", 1, -1)
+			group(e, "n", 1, -1)
+			leaf(e, "a", repetitionOptional)
+		}), `column "s" has an unsupported nested type`},
+		{"map", fileWithSchema(func(e *thriftEncoder) {
+			e.listField(2, thriftStruct, 5)
+			group(e, "schema", 1, -1)
+			group(e, "m", 1, convertedMap)
+			e.structBegin()
+			e.i32Field(3, repetitionRepeated)
+			e.stringField(4, "key_value")
+			e.i32Field(5, 2)
+			e.structEnd()
+			leaf(e, "key", repetitionRequired)
+			leaf(e, "value", repetitionOptional)
+		}), `column "m" has an unsupported nested type`},
+		{"truncated schema", fileWithSchema(func(e *thriftEncoder) {
+			e.listField(2, thriftStruct, 1)
+			group(e, "schema", 1, -1)
+		}), "truncated schema"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewReader(bytes.NewReader(tc.file), int64(len(tc.file)))
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.expected)
+		})
+	}
+}
@@ -0,0 +1,445 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package parquet
+
+import "github.com/cockroachdb/errors"
+
+// PhysicalType is the type used to store the values of a column in a
+// Parquet file. The values match the Type enum of the Parquet format.
+type PhysicalType int32
+
+// Physical types supported by this package. Int96 is a deprecated type used
+// by some writers to store timestamps; it can be read but not written.
+const (
+	Boolean           PhysicalType = 0
+	Int32             PhysicalType = 1
+	Int64             PhysicalType = 2
+	Int96             PhysicalType = 3
+	Float             PhysicalType = 4
+	Double            PhysicalType = 5
+	ByteArray         PhysicalType = 6
+	FixedLenByteArray PhysicalType = 7
+)
+
+var physicalTypeNames = map[PhysicalType]string{
+	Boolean:           "BOOLEAN",
+	Int32:             "INT32",
+	Int64:             "INT64",
+	Int96:             "INT96",
+	Float:             "FLOAT",
+	Double:            "DOUBLE",
+	ByteArray:         "BYTE_ARRAY",
+	FixedLenByteArray: "FIXED_LEN_BYTE_ARRAY",
+}
+
+func (t PhysicalType) String() string {
+	if s, ok := physicalTypeNames[t]; ok {
+		return s
+	}
+	return "UNKNOWN"
+}
+
+// LogicalKind identifies the logical type annotation of a column, which
+// specifies how the values of its physical type are interpreted.
+type LogicalKind int
+
+// Logical types supported by this package.
+const (
+	// LogicalNone indicates that the values are not annotated.
+	LogicalNone LogicalKind = iota
+	// LogicalString annotates UTF-8 encoded byte arrays.
+	LogicalString
+	// LogicalEnum annotates byte arrays holding the label of an enum value.
+	LogicalEnum
+	// LogicalDecimal annotates byte arrays holding the big-endian two's
+	// complement unscaled value of a decimal.
+	LogicalDecimal
+	// LogicalDate annotates int32 values holding days since the Unix epoch.
+	LogicalDate
+	// LogicalTime annotates integers holding the time since midnight, in the
+	// unit of the type.
+	LogicalTime
+	// LogicalTimestamp annotates int64 values holding the time since the Unix
+	// epoch, in the unit of the type.
+	LogicalTimestamp
+	// LogicalInt annotates integers with their bit width.
+	LogicalInt
+	// LogicalJSON annotates UTF-8 encoded JSON documents.
+	LogicalJSON
+	// LogicalUUID annotates 16-byte fixed length byte arrays.
+	LogicalUUID
+)
+
+// TimeUnit is the unit of the values of LogicalTime and LogicalTimestamp
+// columns.
+type TimeUnit int
+
+// Time units supported by this package.
+const (
+	Microseconds TimeUnit = iota
+	Milliseconds
+	Nanoseconds
+)
+
+// LogicalType is the logical type annotation of a column.
+type LogicalType struct {
+	Kind LogicalKind
+	// Precision and Scale are set for LogicalDecimal.
+	Precision int32
+	Scale     int32
+	// BitWidth and Unsigned are set for LogicalInt.
+	BitWidth int8
+	Unsigned bool
+	// AdjustedToUTC is set for LogicalTime and LogicalTimestamp when the
+	// values are relative to UTC rather than to an unspecified time zone.
+	AdjustedToUTC bool
+	// Unit is set for LogicalTime and LogicalTimestamp.
+	Unit TimeUnit
+}
+
+// Values of the ConvertedType enum of the Parquet format. Converted types
+// are the predecessor of logical types; they are written alongside the
+// logical types for the benefit of older readers.
+const (
+	convertedUTF8            = 0
+	convertedMap             = 1
+	convertedMapKeyValue     = 2
+	convertedList            = 3
+	convertedEnum            = 4
+	convertedDecimal         = 5
+	convertedDate            = 6
+	convertedTimeMillis      = 7
+	convertedTimeMicros      = 8
+	convertedTimestampMillis = 9
+	convertedTimestampMicros = 10
+	convertedUint8           = 11
+	convertedUint16          = 12
+	convertedUint32          = 13
+	convertedUint64          = 14
+	convertedInt8            = 15
+	convertedInt16           = 16
+	convertedInt32           = 17
+	convertedInt64           = 18
+	convertedJSON            = 19
+)
+
+// Values of the FieldRepetitionType enum of the Parquet format.
+const (
+	repetitionRequired = 0
+	repetitionOptional = 1
+	repetitionRepeated = 2
+)
+
+// Column describes a column of a Parquet file. All columns are nullable.
+type Column struct {
+	Name string
+	// Type is the physical type of the values of the column, or of the
+	// elements of the column if List is set.
+	Type PhysicalType
+	// TypeLength is the length of the values of FixedLenByteArray columns.
+	TypeLength int32
+	Logical    LogicalType
+	// List indicates that the values of the column are lists of nullable
+	// elements. Lists are stored using the standard three-level structure:
+	//
+	//   optional group <name> (LIST) {
+	//     repeated group list {
+	//       optional <type> element;
+	//     }
+	//   }
+	List bool
+	// Fields is set for struct columns, which are stored as a group of the
+	// fields. Fields cannot be structs, and the type of a struct column is
+	// ignored.
+	Fields []Column
+}
+
+// leafColumn is a column holding values in a file: either a column that is
+// not a struct, or a field of a struct column.
+type leafColumn struct {
+	col *Column
+	// path is the path of the field of the column in the schema.
+	path []string
+	// top is the index of the column of the row holding the values, and field
+	// is the index of the field in the struct column, or -1.
+	top   int
+	field int
+}
+
+// name returns the name of the column, qualified by the name of its struct
+// column for fields.
+func (l *leafColumn) name(cols []Column) string {
+	if l.field < 0 {
+		return l.col.Name
+	}
+	return cols[l.top].Name + "." + l.col.Name
+}
+
+// leafColumns returns the leaf columns of the given columns, in the order of
+// their column chunks.
+func leafColumns(cols []Column) []leafColumn {
+	var leaves []leafColumn
+	for i := range cols {
+		c := &cols[i]
+		if c.Fields == nil {
+			leaves = append(leaves, leafColumn{col: c, path: c.path(), top: i, field: -1})
+			continue
+		}
+		for j := range c.Fields {
+			f := &c.Fields[j]
+			path := append([]string{c.Name}, f.path()...)
+			leaves = append(leaves, leafColumn{col: f, path: path, top: i, field: j})
+		}
+	}
+	return leaves
+}
+
+// maxDefinitionLevel returns the definition level of a non-null value of the
+// column.
+func (c *Column) maxDefinitionLevel() uint8 {
+	if c.List {
+		return 3
+	}
+	return 1
+}
+
+// maxRepetitionLevel returns the highest repetition level of the values of
+// the column.
+func (c *Column) maxRepetitionLevel() uint8 {
+	if c.List {
+		return 1
+	}
+	return 0
+}
+
+// path returns the path of the leaf field of the column in the schema.
+func (c *Column) path() []string {
+	if c.List {
+		return []string{c.Name, "list", "element"}
+	}
+	return []string{c.Name}
+}
+
+func (c *Column) validate() error {
+	if c.Name == "" {
+		return errors.New("parquet columns must have a name")
+	}
+	if c.Fields != nil {
+		if len(c.Fields) == 0 || c.List {
+			return errors.AssertionFailedf("struct column %q is invalid", c.Name)
+		}
+		for i := range c.Fields {
+			if c.Fields[i].Fields != nil {
+				return errors.AssertionFailedf("field %q of column %q cannot be a struct",
+					c.Fields[i].Name, c.Name)
+			}
+			if err := c.Fields[i].validate(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if _, ok := physicalTypeNames[c.Type]; !ok || c.Type == Int96 {
+		return errors.AssertionFailedf("unsupported physical type %d", c.Type)
+	}
+	if (c.Type == FixedLenByteArray) != (c.TypeLength > 0) {
+		return errors.AssertionFailedf(
+			"type length %d is invalid for column %q of type %s", c.TypeLength, c.Name, c.Type)
+	}
+	return nil
+}
+
+// numSchemaElements returns the number of SchemaElements describing a column.
+func (c *Column) numSchemaElements() int {
+	switch {
+	case c.Fields != nil:
+		n := 1
+		for i := range c.Fields {
+			n += c.Fields[i].numSchemaElements()
+		}
+		return n
+	case c.List:
+		return 3
+	default:
+		return 1
+	}
+}
+
+// encodeSchema appends the SchemaElements describing the columns to the list
+// of the schema field of the file metadata. The schema is a flattened tree
+// whose root is a group holding all the columns.
+func encodeSchema(e *thriftEncoder, cols []Column) {
+	n := 1
+	for i := range cols {
+		n += cols[i].numSchemaElements()
+	}
+	e.listField(2, thriftStruct, n)
+
+	e.structBegin()
+	e.stringField(4, "schema")
+	e.i32Field(5, int32(len(cols)))
+	e.structEnd()
+
+	for i := range cols {
+		encodeField(e, &cols[i])
+	}
+}
+
+// encodeField encodes the SchemaElements describing a column.
+func encodeField(e *thriftEncoder, c *Column) {
+	switch {
+	case c.Fields != nil:
+		e.structBegin()
+		e.i32Field(3, repetitionOptional)
+		e.stringField(4, c.Name)
+		e.i32Field(5, int32(len(c.Fields)))
+		e.structEnd()
+		for i := range c.Fields {
+			encodeField(e, &c.Fields[i])
+		}
+	case c.List:
+		e.structBegin()
+		e.i32Field(3, repetitionOptional)
+		e.stringField(4, c.Name)
+		e.i32Field(5, 1)
+		e.i32Field(6, convertedList)
+		e.structField(10)
+		e.structField(3)
+		e.structEnd()
+		e.structEnd()
+		e.structEnd()
+
+		e.structBegin()
+		e.i32Field(3, repetitionRepeated)
+		e.stringField(4, "list")
+		e.i32Field(5, 1)
+		e.structEnd()
+
+		encodeLeaf(e, c, "element")
+	default:
+		encodeLeaf(e, c, c.Name)
+	}
+}
+
+// encodeLeaf encodes the SchemaElement of the field holding the values of a
+// column.
+func encodeLeaf(e *thriftEncoder, c *Column, name string) {
+	e.structBegin()
+	e.i32Field(1, int32(c.Type))
+	if c.TypeLength > 0 {
+		e.i32Field(2, c.TypeLength)
+	}
+	e.i32Field(3, repetitionOptional)
+	e.stringField(4, name)
+
+	l := &c.Logical
+	converted := int32(-1)
+	switch l.Kind {
+	case LogicalString:
+		converted = convertedUTF8
+	case LogicalEnum:
+		converted = convertedEnum
+	case LogicalDecimal:
+		converted = convertedDecimal
+	case LogicalDate:
+		converted = convertedDate
+	case LogicalTime:
+		switch l.Unit {
+		case Microseconds:
+			converted = convertedTimeMicros
+		case Milliseconds:
+			converted = convertedTimeMillis
+		}
+	case LogicalTimestamp:
+		switch l.Unit {
+		case Microseconds:
+			converted = convertedTimestampMicros
+		case Milliseconds:
+			converted = convertedTimestampMillis
+		}
+	case LogicalInt:
+		switch l.BitWidth {
+		case 8:
+			converted = convertedInt8
+		case 16:
+			converted = convertedInt16
+		case 32:
+			converted = convertedInt32
+		case 64:
+			converted = convertedInt64
+		}
+		if l.Unsigned && converted >= 0 {
+			converted -= convertedInt8 - convertedUint8
+		}
+	case LogicalJSON:
+		converted = convertedJSON
+	}
+	if converted >= 0 {
+		e.i32Field(6, converted)
+	}
+	if l.Kind == LogicalDecimal {
+		e.i32Field(7, l.Scale)
+		e.i32Field(8, l.Precision)
+	}
+
+	if l.Kind != LogicalNone {
+		e.structField(10)
+		switch l.Kind {
+		case LogicalString:
+			e.structField(1)
+			e.structEnd()
+		case LogicalEnum:
+			e.structField(4)
+			e.structEnd()
+		case LogicalDecimal:
+			e.structField(5)
+			e.i32Field(1, l.Scale)
+			e.i32Field(2, l.Precision)
+			e.structEnd()
+		case LogicalDate:
+			e.structField(6)
+			e.structEnd()
+		case LogicalTime, LogicalTimestamp:
+			id := int16(7)
+			if l.Kind == LogicalTimestamp {
+				id = 8
+			}
+			e.structField(id)
+			e.boolField(1, l.AdjustedToUTC)
+			e.structField(2)
+			e.structField(timeUnitIDs[l.Unit])
+			e.structEnd()
+			e.structEnd()
+			e.structEnd()
+		case LogicalInt:
+			e.structField(10)
+			e.byteField(1, l.BitWidth)
+			e.boolField(2, !l.Unsigned /* isSigned */)
+			e.structEnd()
+		case LogicalJSON:
+			e.structField(12)
+			e.structEnd()
+		case LogicalUUID:
+			e.structField(14)
+			e.structEnd()
+		}
+		e.structEnd()
+	}
+	e.structEnd()
+}
+
+// timeUnitIDs maps time units to the ids of the fields of the TimeUnit union
+// of the Parquet format.
+var timeUnitIDs = map[TimeUnit]int16{
+	Milliseconds: 1,
+	Microseconds: 2,
+	Nanoseconds:  3,
+}
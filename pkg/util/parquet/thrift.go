@@ -0,0 +1,340 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package parquet
+
+import (
+	"encoding/binary"
+
+	"github.com/cockroachdb/errors"
+)
+
+// Type identifiers of the Thrift compact protocol, which is used to encode
+// the page headers and the footer of a Parquet file.
+const (
+	thriftStop      = 0x0
+	thriftTrue      = 0x1
+	thriftFalse     = 0x2
+	thriftByte      = 0x3
+	thriftI16       = 0x4
+	thriftI32       = 0x5
+	thriftI64       = 0x6
+	thriftDouble    = 0x7
+	thriftBinary    = 0x8
+	thriftList      = 0x9
+	thriftSet       = 0xa
+	thriftMap       = 0xb
+	thriftStruct    = 0xc
+	thriftTypeMask  = 0x0f
+	thriftDeltaMask = 0xf0
+)
+
+// thriftEncoder appends Thrift compact protocol structs to a buffer. Structs
+// are written field by field: the caller is responsible for writing fields
+// in increasing order of their ids, as required by the protocol's delta
+// encoding of field ids.
+type thriftEncoder struct {
+	buf []byte
+	// lastField is a stack of the ids of the last field written in each of
+	// the structs being encoded.
+	lastField []int16
+}
+
+func (e *thriftEncoder) varint(v uint64) {
+	var scratch [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(scratch[:], v)
+	e.buf = append(e.buf, scratch[:n]...)
+}
+
+func (e *thriftEncoder) zigzag(v int64) {
+	e.varint(uint64((v << 1) ^ (v >> 63)))
+}
+
+func (e *thriftEncoder) fieldHeader(id int16, typ byte) {
+	last := &e.lastField[len(e.lastField)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		e.buf = append(e.buf, byte(delta)<<4|typ)
+	} else {
+		e.buf = append(e.buf, typ)
+		e.zigzag(int64(id))
+	}
+	*last = id
+}
+
+// structBegin starts a struct that is either the top-level struct or an
+// element of a list.
+func (e *thriftEncoder) structBegin() {
+	e.lastField = append(e.lastField, 0)
+}
+
+// structEnd terminates the innermost struct.
+func (e *thriftEncoder) structEnd() {
+	e.buf = append(e.buf, thriftStop)
+	e.lastField = e.lastField[:len(e.lastField)-1]
+}
+
+// structField starts a field of struct type. It must be terminated with
+// structEnd.
+func (e *thriftEncoder) structField(id int16) {
+	e.fieldHeader(id, thriftStruct)
+	e.structBegin()
+}
+
+func (e *thriftEncoder) boolField(id int16, v bool) {
+	if v {
+		e.fieldHeader(id, thriftTrue)
+	} else {
+		e.fieldHeader(id, thriftFalse)
+	}
+}
+
+func (e *thriftEncoder) byteField(id int16, v int8) {
+	e.fieldHeader(id, thriftByte)
+	e.buf = append(e.buf, byte(v))
+}
+
+func (e *thriftEncoder) i32Field(id int16, v int32) {
+	e.fieldHeader(id, thriftI32)
+	e.zigzag(int64(v))
+}
+
+func (e *thriftEncoder) i64Field(id int16, v int64) {
+	e.fieldHeader(id, thriftI64)
+	e.zigzag(v)
+}
+
+func (e *thriftEncoder) binaryField(id int16, v []byte) {
+	e.fieldHeader(id, thriftBinary)
+	e.binary(v)
+}
+
+func (e *thriftEncoder) stringField(id int16, v string) {
+	e.binaryField(id, []byte(v))
+}
+
+func (e *thriftEncoder) binary(v []byte) {
+	e.varint(uint64(len(v)))
+	e.buf = append(e.buf, v...)
+}
+
+// listField starts a field of list type with n elements of the given type.
+// The elements are written right after it using i32, binary or structBegin
+// and structEnd.
+func (e *thriftEncoder) listField(id int16, elemType byte, n int) {
+	e.fieldHeader(id, thriftList)
+	if n < 15 {
+		e.buf = append(e.buf, byte(n)<<4|elemType)
+	} else {
+		e.buf = append(e.buf, thriftDeltaMask|elemType)
+		e.varint(uint64(n))
+	}
+}
+
+func (e *thriftEncoder) i32(v int32) {
+	e.zigzag(int64(v))
+}
+
+// errThriftTruncated is returned when decoding data that ends in the middle of
+// a struct.
+var errThriftTruncated = errors.New("truncated thrift data")
+
+// thriftDecoder decodes Thrift compact protocol structs from a buffer.
+type thriftDecoder struct {
+	buf []byte
+	pos int
+}
+
+func (d *thriftDecoder) varint() (uint64, error) {
+	v, n := binary.Uvarint(d.buf[d.pos:])
+	if n <= 0 {
+		return 0, errThriftTruncated
+	}
+	d.pos += n
+	return v, nil
+}
+
+func (d *thriftDecoder) zigzag() (int64, error) {
+	v, err := d.varint()
+	return int64(v>>1) ^ -int64(v&1), err
+}
+
+func (d *thriftDecoder) i32() (int32, error) {
+	v, err := d.zigzag()
+	return int32(v), err
+}
+
+func (d *thriftDecoder) byte() (byte, error) {
+	if d.pos >= len(d.buf) {
+		return 0, errThriftTruncated
+	}
+	d.pos++
+	return d.buf[d.pos-1], nil
+}
+
+func (d *thriftDecoder) binary() ([]byte, error) {
+	n, err := d.varint()
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(len(d.buf)-d.pos) {
+		return nil, errThriftTruncated
+	}
+	d.pos += int(n)
+	return d.buf[d.pos-int(n) : d.pos], nil
+}
+
+func (d *thriftDecoder) string() (string, error) {
+	b, err := d.binary()
+	return string(b), err
+}
+
+// listHeader decodes the header of a list or set, returning the type and the
+// number of its elements.
+func (d *thriftDecoder) listHeader() (byte, int, error) {
+	h, err := d.byte()
+	if err != nil {
+		return 0, 0, err
+	}
+	n := uint64(h >> 4)
+	if n == 15 {
+		if n, err = d.varint(); err != nil {
+			return 0, 0, err
+		}
+	}
+	// Every element takes at least one byte, except for empty structs which
+	// take one byte as well.
+	if n > uint64(len(d.buf)-d.pos) {
+		return 0, 0, errThriftTruncated
+	}
+	return h & thriftTypeMask, int(n), nil
+}
+
+// list decodes a list of elements of the expected type, calling fn for each
+// of them.
+func (d *thriftDecoder) list(elemType byte, fn func() error) error {
+	typ, n, err := d.listHeader()
+	if err != nil {
+		return err
+	}
+	if typ != elemType && n > 0 {
+		return errors.Newf("unexpected thrift list element type %d", typ)
+	}
+	for i := 0; i < n; i++ {
+		if err := fn(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readStruct decodes a struct, calling fn with the id and the type of each of
+// its fields. fn must either decode the value of the field or skip it. The
+// value of boolean fields is encoded in their type.
+func (d *thriftDecoder) readStruct(fn func(id int16, typ byte) error) error {
+	var last int16
+	for {
+		h, err := d.byte()
+		if err != nil {
+			return err
+		}
+		typ := h & thriftTypeMask
+		if typ == thriftStop {
+			return nil
+		}
+		id := last + int16(h>>4)
+		if h&thriftDeltaMask == 0 {
+			v, err := d.zigzag()
+			if err != nil {
+				return err
+			}
+			id = int16(v)
+		}
+		last = id
+		if err := fn(id, typ); err != nil {
+			return err
+		}
+	}
+}
+
+// skip skips a value of the given type.
+func (d *thriftDecoder) skip(typ byte) error {
+	switch typ {
+	case thriftTrue, thriftFalse:
+		return nil
+	case thriftByte:
+		_, err := d.byte()
+		return err
+	case thriftI16, thriftI32, thriftI64:
+		_, err := d.varint()
+		return err
+	case thriftDouble:
+		if len(d.buf)-d.pos < 8 {
+			return errThriftTruncated
+		}
+		d.pos += 8
+		return nil
+	case thriftBinary:
+		_, err := d.binary()
+		return err
+	case thriftList, thriftSet:
+		elemType, n, err := d.listHeader()
+		if err != nil {
+			return err
+		}
+		elemType = collectionElemType(elemType)
+		for i := 0; i < n; i++ {
+			if err := d.skip(elemType); err != nil {
+				return err
+			}
+		}
+		return nil
+	case thriftMap:
+		n, err := d.varint()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return nil
+		}
+		if n > uint64(len(d.buf)-d.pos) {
+			return errThriftTruncated
+		}
+		types, err := d.byte()
+		if err != nil {
+			return err
+		}
+		keyType, valueType := collectionElemType(types>>4), collectionElemType(types&thriftTypeMask)
+		for i := uint64(0); i < n; i++ {
+			if err := d.skip(keyType); err != nil {
+				return err
+			}
+			if err := d.skip(valueType); err != nil {
+				return err
+			}
+		}
+		return nil
+	case thriftStruct:
+		return d.readStruct(func(_ int16, typ byte) error {
+			return d.skip(typ)
+		})
+	default:
+		return errors.Newf("unknown thrift type %d", typ)
+	}
+}
+
+// collectionElemType returns the type of the encoding of the elements of
+// lists, sets and maps: booleans are encoded as one byte rather than in the
+// type.
+func collectionElemType(typ byte) byte {
+	if typ == thriftTrue || typ == thriftFalse {
+		return thriftByte
+	}
+	return typ
+}
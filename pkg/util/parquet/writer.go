@@ -0,0 +1,549 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package parquet implements the encoding and decoding of Apache Parquet
+// files.
+//
+// Files are written with a single data page per column chunk, using the
+// PLAIN encoding for values and the RLE encoding for repetition and
+// definition levels, which every Parquet reader supports. Files written by
+// other writers can be read as long as their pages use the standard
+// encodings and one of the compression codecs supported by the writer.
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"math"
+
+	"github.com/cockroachdb/errors"
+	"github.com/golang/snappy"
+)
+
+// magic delimits the beginning and the end of Parquet files.
+const magic = "PAR1"
+
+// createdBy is recorded in the metadata of the files.
+const createdBy = "cockroachdb"
+
+// CompressionCodec is the codec used to compress the pages of a file. The
+// values match the CompressionCodec enum of the Parquet format.
+type CompressionCodec int32
+
+// Compression codecs supported by this package.
+const (
+	CompressionNone   CompressionCodec = 0
+	CompressionSnappy CompressionCodec = 1
+	CompressionGZIP   CompressionCodec = 2
+)
+
+var compressionCodecNames = map[CompressionCodec]string{
+	CompressionNone:   "UNCOMPRESSED",
+	CompressionSnappy: "SNAPPY",
+	CompressionGZIP:   "GZIP",
+	3:                 "LZO",
+	4:                 "BROTLI",
+	5:                 "LZ4",
+	6:                 "ZSTD",
+	7:                 "LZ4_RAW",
+}
+
+func (c CompressionCodec) String() string {
+	if s, ok := compressionCodecNames[c]; ok {
+		return s
+	}
+	return "UNKNOWN"
+}
+
+// Values of the Encoding and PageType enums of the Parquet format.
+const (
+	encodingPlain = 0
+	encodingRLE   = 3
+	pageTypeData  = 0
+)
+
+// defaultMaxRowGroupLength is the default number of rows after which the
+// writer flushes a row group.
+const defaultMaxRowGroupLength = 64 << 10
+
+type writerConfig struct {
+	codec             CompressionCodec
+	maxRowGroupLength int
+}
+
+// Option configures a Writer.
+type Option func(*writerConfig)
+
+// WithCompressionCodec sets the codec used to compress the pages.
+func WithCompressionCodec(codec CompressionCodec) Option {
+	return func(c *writerConfig) {
+		c.codec = codec
+	}
+}
+
+// WithMaxRowGroupLength sets the number of rows after which a row group is
+// flushed.
+func WithMaxRowGroupLength(n int) Option {
+	return func(c *writerConfig) {
+		c.maxRowGroupLength = n
+	}
+}
+
+// Writer writes rows to a Parquet file. Rows are buffered in memory until a
+// row group is flushed, either explicitly with Flush, or because the row
+// group reached its maximum length. A Writer must not be used after it
+// returned an error.
+type Writer struct {
+	sink   io.Writer
+	cfg    writerConfig
+	cols   []Column
+	leaves []leafColumn
+	// bufs holds the values of each leaf column.
+	bufs   []columnBuffer
+	offset int64
+
+	// rows is the number of rows buffered in the current row group.
+	rows      int
+	numRows   int64
+	rowGroups []rowGroupMeta
+}
+
+type rowGroupMeta struct {
+	numRows int64
+	chunks  []columnChunkMeta
+}
+
+type columnChunkMeta struct {
+	offset            int64
+	numValues         int64
+	uncompressedBytes int64
+	compressedBytes   int64
+}
+
+// NewWriter returns a Writer of a file with the given columns to sink.
+func NewWriter(sink io.Writer, cols []Column, opts ...Option) (*Writer, error) {
+	w := &Writer{
+		sink: sink,
+		cfg:  writerConfig{maxRowGroupLength: defaultMaxRowGroupLength},
+		cols: cols,
+	}
+	for _, opt := range opts {
+		opt(&w.cfg)
+	}
+	switch w.cfg.codec {
+	case CompressionNone, CompressionSnappy, CompressionGZIP:
+	default:
+		return nil, errors.AssertionFailedf("unsupported compression codec %d", w.cfg.codec)
+	}
+	if len(cols) == 0 {
+		return nil, errors.New("parquet files must have at least one column")
+	}
+	for i := range cols {
+		if err := cols[i].validate(); err != nil {
+			return nil, err
+		}
+	}
+	w.leaves = leafColumns(w.cols)
+	w.bufs = make([]columnBuffer, len(w.leaves))
+	for i := range w.leaves {
+		w.bufs[i].col = w.leaves[i].col
+		if w.leaves[i].field >= 0 {
+			w.bufs[i].base = 1
+		}
+	}
+	if err := w.write([]byte(magic)); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// AddRow buffers a row. The values must hold one value per column: nil for
+// NULL, a bool, int32, int64, float32, float64, or []byte according to the
+// physical type of the column, a []interface{} of such values for list
+// columns, and a []interface{} holding one value per field for struct
+// columns.
+func (w *Writer) AddRow(row []interface{}) error {
+	if len(row) != len(w.cols) {
+		return errors.AssertionFailedf("expected %d values, found %d", len(w.cols), len(row))
+	}
+	for i := range w.leaves {
+		l := &w.leaves[i]
+		v := row[l.top]
+		if l.field < 0 {
+			if err := w.bufs[i].add(v); err != nil {
+				return err
+			}
+			continue
+		}
+		if v == nil {
+			w.bufs[i].addNullStruct()
+			continue
+		}
+		fields, ok := v.([]interface{})
+		if !ok || len(fields) != len(w.cols[l.top].Fields) {
+			return errors.AssertionFailedf("value of type %T is invalid for struct column %q",
+				v, w.cols[l.top].Name)
+		}
+		if err := w.bufs[i].add(fields[l.field]); err != nil {
+			return err
+		}
+	}
+	w.rows++
+	if w.rows >= w.cfg.maxRowGroupLength {
+		return w.Flush()
+	}
+	return nil
+}
+
+// BufferedRows returns the number of rows of the current row group.
+func (w *Writer) BufferedRows() int {
+	return w.rows
+}
+
+// Size returns an estimate of the size of the file: the bytes written to the
+// sink so far plus the encoded size of the buffered rows.
+func (w *Writer) Size() int64 {
+	size := w.offset
+	for i := range w.bufs {
+		size += w.bufs[i].size()
+	}
+	return size
+}
+
+// Flush writes the buffered rows as a row group.
+func (w *Writer) Flush() error {
+	if w.rows == 0 {
+		return nil
+	}
+	rg := rowGroupMeta{
+		numRows: int64(w.rows),
+		chunks:  make([]columnChunkMeta, len(w.bufs)),
+	}
+	for i := range w.bufs {
+		if err := w.writeColumnChunk(&w.bufs[i], &rg.chunks[i]); err != nil {
+			return err
+		}
+		w.bufs[i].reset()
+	}
+	w.rowGroups = append(w.rowGroups, rg)
+	w.numRows += int64(w.rows)
+	w.rows = 0
+	return nil
+}
+
+// Close flushes the buffered rows and writes the footer of the file. It does
+// not close the sink.
+func (w *Writer) Close() error {
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	footer := w.encodeFooter()
+	var length [4]byte
+	binary.LittleEndian.PutUint32(length[:], uint32(len(footer)))
+	footer = append(footer, length[:]...)
+	footer = append(footer, magic...)
+	return w.write(footer)
+}
+
+func (w *Writer) write(b []byte) error {
+	n, err := w.sink.Write(b)
+	w.offset += int64(n)
+	return err
+}
+
+// writeColumnChunk writes the buffered values of a column as a single data
+// page.
+func (w *Writer) writeColumnChunk(b *columnBuffer, meta *columnChunkMeta) error {
+	var page []byte
+	if b.col.maxRepetitionLevel() > 0 {
+		page = appendLevels(page, b.repLevels)
+	}
+	page = appendLevels(page, b.defLevels)
+	if b.col.Type == Boolean {
+		page = appendBitPacked(page, b.bools)
+	} else {
+		page = append(page, b.values...)
+	}
+	if len(page) > math.MaxInt32 {
+		return errors.Newf("page of column %q is too large", b.col.Name)
+	}
+
+	compressed, err := w.compress(page)
+	if err != nil {
+		return err
+	}
+
+	var e thriftEncoder
+	e.structBegin()
+	e.i32Field(1, pageTypeData)
+	e.i32Field(2, int32(len(page)))
+	e.i32Field(3, int32(len(compressed)))
+	e.structField(5)
+	e.i32Field(1, int32(len(b.defLevels)))
+	e.i32Field(2, encodingPlain)
+	e.i32Field(3, encodingRLE)
+	e.i32Field(4, encodingRLE)
+	e.structEnd()
+	e.structEnd()
+
+	*meta = columnChunkMeta{
+		offset:            w.offset,
+		numValues:         int64(len(b.defLevels)),
+		uncompressedBytes: int64(len(e.buf) + len(page)),
+		compressedBytes:   int64(len(e.buf) + len(compressed)),
+	}
+	if err := w.write(e.buf); err != nil {
+		return err
+	}
+	return w.write(compressed)
+}
+
+func (w *Writer) compress(page []byte) ([]byte, error) {
+	switch w.cfg.codec {
+	case CompressionSnappy:
+		return snappy.Encode(nil, page), nil
+	case CompressionGZIP:
+		var buf bytes.Buffer
+		gw := gzip.NewWriter(&buf)
+		if _, err := gw.Write(page); err != nil {
+			return nil, err
+		}
+		if err := gw.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return page, nil
+	}
+}
+
+// encodeFooter encodes the FileMetaData of the file.
+func (w *Writer) encodeFooter() []byte {
+	var e thriftEncoder
+	e.structBegin()
+	e.i32Field(1, 1 /* version */)
+	encodeSchema(&e, w.cols)
+	e.i64Field(3, w.numRows)
+	e.listField(4, thriftStruct, len(w.rowGroups))
+	for i := range w.rowGroups {
+		rg := &w.rowGroups[i]
+		var uncompressed, compressed int64
+		e.structBegin()
+		e.listField(1, thriftStruct, len(rg.chunks))
+		for j := range rg.chunks {
+			c := &rg.chunks[j]
+			col := w.leaves[j].col
+			uncompressed += c.uncompressedBytes
+			compressed += c.compressedBytes
+
+			e.structBegin()
+			e.i64Field(2, c.offset)
+			e.structField(3)
+			e.i32Field(1, int32(col.Type))
+			e.listField(2, thriftI32, 2)
+			e.i32(encodingPlain)
+			e.i32(encodingRLE)
+			path := w.leaves[j].path
+			e.listField(3, thriftBinary, len(path))
+			for _, p := range path {
+				e.binary([]byte(p))
+			}
+			e.i32Field(4, int32(w.cfg.codec))
+			e.i64Field(5, c.numValues)
+			e.i64Field(6, c.uncompressedBytes)
+			e.i64Field(7, c.compressedBytes)
+			e.i64Field(9, c.offset)
+			e.structEnd()
+			e.structEnd()
+		}
+		e.i64Field(2, uncompressed)
+		e.i64Field(3, rg.numRows)
+		e.i64Field(5, rg.chunks[0].offset)
+		e.i64Field(6, compressed)
+		e.structEnd()
+	}
+	e.stringField(6, createdBy)
+	e.structEnd()
+	return e.buf
+}
+
+// columnBuffer holds the values of a leaf column in the current row group.
+type columnBuffer struct {
+	col *Column
+	// base is the number of optional groups holding the column, which is 1
+	// for the fields of struct columns. The definition levels of the values
+	// of the column are offset by base.
+	base      uint8
+	defLevels []uint8
+	repLevels []uint8
+	// values holds the PLAIN encoding of the non-null values of the column,
+	// except for boolean columns whose values are held in bools.
+	values []byte
+	bools  []bool
+}
+
+func (b *columnBuffer) reset() {
+	b.defLevels = b.defLevels[:0]
+	b.repLevels = b.repLevels[:0]
+	b.values = b.values[:0]
+	b.bools = b.bools[:0]
+}
+
+func (b *columnBuffer) size() int64 {
+	return int64(len(b.defLevels)+len(b.repLevels)+len(b.values)) + int64(len(b.bools)/8)
+}
+
+// addNullStruct adds the value of a field of a NULL struct.
+func (b *columnBuffer) addNullStruct() {
+	b.defLevels = append(b.defLevels, 0)
+	if b.col.List {
+		b.repLevels = append(b.repLevels, 0)
+	}
+}
+
+func (b *columnBuffer) add(v interface{}) error {
+	if !b.col.List {
+		if v == nil {
+			b.defLevels = append(b.defLevels, b.base)
+			return nil
+		}
+		b.defLevels = append(b.defLevels, b.base+1)
+		return b.addValue(v)
+	}
+
+	if v == nil {
+		b.defLevels = append(b.defLevels, b.base)
+		b.repLevels = append(b.repLevels, 0)
+		return nil
+	}
+	elems, ok := v.([]interface{})
+	if !ok {
+		return errors.AssertionFailedf("value of type %T is invalid for list column %q", v, b.col.Name)
+	}
+	if len(elems) == 0 {
+		b.defLevels = append(b.defLevels, b.base+1)
+		b.repLevels = append(b.repLevels, 0)
+		return nil
+	}
+	for i, elem := range elems {
+		rep := uint8(1)
+		if i == 0 {
+			rep = 0
+		}
+		b.repLevels = append(b.repLevels, rep)
+		if elem == nil {
+			b.defLevels = append(b.defLevels, b.base+2)
+			continue
+		}
+		b.defLevels = append(b.defLevels, b.base+3)
+		if err := b.addValue(elem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *columnBuffer) addValue(v interface{}) error {
+	var scratch [8]byte
+	ok := false
+	switch b.col.Type {
+	case Boolean:
+		var x bool
+		if x, ok = v.(bool); ok {
+			b.bools = append(b.bools, x)
+		}
+	case Int32:
+		var x int32
+		if x, ok = v.(int32); ok {
+			binary.LittleEndian.PutUint32(scratch[:], uint32(x))
+			b.values = append(b.values, scratch[:4]...)
+		}
+	case Int64:
+		var x int64
+		if x, ok = v.(int64); ok {
+			binary.LittleEndian.PutUint64(scratch[:], uint64(x))
+			b.values = append(b.values, scratch[:8]...)
+		}
+	case Float:
+		var x float32
+		if x, ok = v.(float32); ok {
+			binary.LittleEndian.PutUint32(scratch[:], math.Float32bits(x))
+			b.values = append(b.values, scratch[:4]...)
+		}
+	case Double:
+		var x float64
+		if x, ok = v.(float64); ok {
+			binary.LittleEndian.PutUint64(scratch[:], math.Float64bits(x))
+			b.values = append(b.values, scratch[:8]...)
+		}
+	case ByteArray:
+		var x []byte
+		if x, ok = v.([]byte); ok {
+			binary.LittleEndian.PutUint32(scratch[:], uint32(len(x)))
+			b.values = append(b.values, scratch[:4]...)
+			b.values = append(b.values, x...)
+		}
+	case FixedLenByteArray:
+		var x []byte
+		if x, ok = v.([]byte); ok {
+			if int32(len(x)) != b.col.TypeLength {
+				return errors.AssertionFailedf("value of length %d is invalid for column %q of length %d",
+					len(x), b.col.Name, b.col.TypeLength)
+			}
+			b.values = append(b.values, x...)
+		}
+	}
+	if !ok {
+		return errors.AssertionFailedf("value of type %T is invalid for column %q of type %s",
+			v, b.col.Name, b.col.Type)
+	}
+	return nil
+}
+
+// appendLevels appends levels using the RLE encoding: the length of the
+// encoded data as a 4-byte little-endian integer, followed by a sequence of
+// runs of repeated values. Levels are at most 4 so the values of the runs
+// are encoded in a single byte.
+func appendLevels(buf []byte, levels []uint8) []byte {
+	start := len(buf)
+	buf = append(buf, 0, 0, 0, 0)
+	var scratch [binary.MaxVarintLen64]byte
+	for i := 0; i < len(levels); {
+		j := i + 1
+		for j < len(levels) && levels[j] == levels[i] {
+			j++
+		}
+		// The header of a run is its length shifted by one bit, whose unset
+		// low bit distinguishes it from a bit-packed run.
+		n := binary.PutUvarint(scratch[:], uint64(j-i)<<1)
+		buf = append(buf, scratch[:n]...)
+		buf = append(buf, levels[i])
+		i = j
+	}
+	binary.LittleEndian.PutUint32(buf[start:], uint32(len(buf)-start-4))
+	return buf
+}
+
+// appendBitPacked appends booleans using the PLAIN encoding, which packs them
+// one per bit starting with the least significant bit of each byte.
+func appendBitPacked(buf []byte, bools []bool) []byte {
+	for i := 0; i < len(bools); i += 8 {
+		var x byte
+		for j := 0; j < 8 && i+j < len(bools); j++ {
+			if bools[i+j] {
+				x |= 1 << j
+			}
+		}
+		buf = append(buf, x)
+	}
+	return buf
+}
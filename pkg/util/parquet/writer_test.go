@@ -0,0 +1,136 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestThriftEncoder(t *testing.T) {
+	var e thriftEncoder
+	e.structBegin()
+	e.i32Field(1, 3)
+	e.stringField(4, "ab")
+	e.structField(5)
+	e.boolField(1, true)
+	e.structEnd()
+	e.i64Field(30, -1)
+	e.listField(31, thriftI32, 2)
+	e.i32(1)
+	e.i32(-2)
+	e.structEnd()
+	require.Equal(t, []byte{
+		0x15, 0x06, // field 1, i32 3
+		0x38, 0x02, 'a', 'b', // field 4 (delta 3), binary "ab"
+		0x1c,       // field 5, struct
+		0x11,       // field 1, true
+		0x00,       // stop
+		0x06, 0x3c, // field 30 (delta 25) with a long header, i64
+		0x01,       // -1
+		0x19,       // field 31, list
+		0x25,       // 2 elements of type i32
+		0x02, 0x03, // 1, -2
+		0x00, // stop
+	}, e.buf)
+}
+
+func TestAppendLevels(t *testing.T) {
+	buf := appendLevels(nil, []uint8{1, 1, 1, 0, 3, 3})
+	require.Equal(t, []byte{
+		0x06, 0x00, 0x00, 0x00, // length
+		0x06, 0x01, // 3 times 1
+		0x02, 0x00, // once 0
+		0x04, 0x03, // twice 3
+	}, buf)
+	require.Equal(t, []byte{0, 0, 0, 0}, appendLevels(nil, nil))
+}
+
+func TestAppendBitPacked(t *testing.T) {
+	bools := []bool{true, false, true, true, false, false, false, false, false, true}
+	require.Equal(t, []byte{0x0d, 0x02}, appendBitPacked(nil, bools))
+}
+
+func TestWriter(t *testing.T) {
+	cols := []Column{
+		{Name: "b", Type: Boolean},
+		{Name: "i", Type: Int64, Logical: LogicalType{Kind: LogicalInt, BitWidth: 64}},
+		{Name: "s", Type: ByteArray, Logical: LogicalType{Kind: LogicalString}},
+		{Name: "u", Type: FixedLenByteArray, TypeLength: 2},
+		{Name: "l", Type: Int32, List: true},
+	}
+	rows := [][]interface{}{
+		{true, int64(1), []byte("a"), []byte{1, 2}, []interface{}{int32(1), nil, int32(3)}},
+		{nil, nil, nil, nil, nil},
+		{false, int64(-1), []byte(""), []byte{3, 4}, []interface{}{}},
+	}
+
+	for _, codec := range []CompressionCodec{CompressionNone, CompressionSnappy, CompressionGZIP} {
+		var buf bytes.Buffer
+		w, err := NewWriter(&buf, cols, WithCompressionCodec(codec), WithMaxRowGroupLength(2))
+		require.NoError(t, err)
+		for _, row := range rows {
+			require.NoError(t, w.AddRow(row))
+		}
+		require.Equal(t, 1, w.BufferedRows())
+		require.NoError(t, w.Close())
+		require.Equal(t, int64(buf.Len()), w.Size())
+		require.Len(t, w.rowGroups, 2)
+		require.Equal(t, int64(3), w.numRows)
+		// The list column has one level per element, and one level for each
+		// NULL or empty list.
+		require.Equal(t, int64(4), w.rowGroups[0].chunks[4].numValues)
+		require.Equal(t, int64(1), w.rowGroups[1].chunks[4].numValues)
+
+		b := buf.Bytes()
+		require.Equal(t, magic, string(b[:4]))
+		require.Equal(t, magic, string(b[len(b)-4:]))
+		footerLen := int(binary.LittleEndian.Uint32(b[len(b)-8:]))
+		require.Equal(t, w.encodeFooter(), b[len(b)-8-footerLen:len(b)-8])
+	}
+}
+
+func TestWriterErrors(t *testing.T) {
+	_, err := NewWriter(&bytes.Buffer{}, nil)
+	require.Error(t, err)
+	_, err = NewWriter(&bytes.Buffer{}, []Column{{Name: "u", Type: FixedLenByteArray}})
+	require.Error(t, err)
+	_, err = NewWriter(&bytes.Buffer{}, []Column{{Name: "s", Fields: []Column{}}})
+	require.Error(t, err)
+	_, err = NewWriter(&bytes.Buffer{}, []Column{
+		{Name: "s", Fields: []Column{{Name: "n", Fields: []Column{{Name: "i", Type: Int32}}}}},
+	})
+	require.Error(t, err)
+
+	cols := []Column{
+		{Name: "i", Type: Int32},
+		{Name: "u", Type: FixedLenByteArray, TypeLength: 2},
+		{Name: "l", Type: Int32, List: true},
+		{Name: "s", Fields: []Column{{Name: "i", Type: Int32}}},
+	}
+	for _, row := range [][]interface{}{
+		{int32(1)},
+		{int64(1), nil, nil, nil},
+		{nil, []byte{1}, nil, nil},
+		{nil, nil, int32(1), nil},
+		{nil, nil, []interface{}{int64(1)}, nil},
+		{nil, nil, nil, int32(1)},
+		{nil, nil, nil, []interface{}{}},
+		{nil, nil, nil, []interface{}{int64(1)}},
+	} {
+		w, err := NewWriter(&bytes.Buffer{}, cols)
+		require.NoError(t, err)
+		require.Error(t, w.AddRow(row))
+	}
+}